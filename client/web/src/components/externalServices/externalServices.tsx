@@ -6,6 +6,7 @@ import GithubIcon from 'mdi-react/GithubIcon'
 import GitIcon from 'mdi-react/GitIcon'
 import GitLabIcon from 'mdi-react/GitlabIcon'
 import LanguageJavaIcon from 'mdi-react/LanguageJavaIcon'
+import SourceRepositoryIcon from 'mdi-react/SourceRepositoryIcon'
 import React from 'react'
 
 import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
@@ -17,9 +18,11 @@ import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
 import jvmPackagesSchemaJSON from '../../../../../schema/jvm-packages.schema.json'
+import mercurialSchemaJSON from '../../../../../schema/mercurial.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../../schema/other_external_service.schema.json'
 import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
+import subversionSchemaJSON from '../../../../../schema/subversion.schema.json'
 import { ExternalServiceKind } from '../../graphql-operations'
 import { EditorAction } from '../../site-admin/configHelpers'
 import { PerforceIcon } from '../PerforceIcon'
@@ -1208,6 +1211,116 @@ const JVM_PACKAGES: AddExternalServiceOptions = {
     ),
     editorActions: [],
 }
+const MERCURIAL: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.MERCURIAL,
+    title: 'Mercurial',
+    icon: SourceRepositoryIcon,
+    jsonSchema: mercurialSchemaJSON,
+    defaultDisplayName: 'Mercurial',
+    defaultConfig: `{
+  "url": "https://hg.example.com/",
+  "repos": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to be the base URL of your Mercurial
+                    repositories.
+                </li>
+                <li>
+                    Add the paths of the repositories you wish to index relative to the base URL to the{' '}
+                    <Field>repos</Field> field. If <Field>repos</Field> is empty, all repositories listed by the hgweb
+                    index at the base URL are added.
+                </li>
+            </ol>
+            <p>
+                The repositories are converted to Git repositories when they are cloned and updated. See{' '}
+                <a rel="noopener noreferrer" target="_blank" href="https://docs.sourcegraph.com/admin/repo/mercurial">
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setURL',
+            label: 'Set Mercurial base URL',
+            run: (config: string) => {
+                const value = 'https://hg.example.com/'
+                const edits = setProperty(config, ['url'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: (config: string) => {
+                const value = 'path/to/repository'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
+const SUBVERSION: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.SUBVERSION,
+    title: 'Subversion',
+    icon: SourceRepositoryIcon,
+    jsonSchema: subversionSchemaJSON,
+    defaultDisplayName: 'Subversion',
+    defaultConfig: `{
+  "url": "https://svn.example.com/repos/",
+  "repos": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to be the base URL of your Subversion
+                    repositories.
+                </li>
+                <li>
+                    Add the paths of the repositories you wish to index relative to the base URL to the{' '}
+                    <Field>repos</Field> field. If <Field>repos</Field> is empty, all repositories in the parent path
+                    listing at the base URL are added.
+                </li>
+                <li>
+                    Set <Field>layout</Field> to <code>"none"</code> if your repositories don't have the standard{' '}
+                    <code>trunk</code>, <code>branches</code> and <code>tags</code> layout.
+                </li>
+            </ol>
+            <p>
+                The repositories are converted to Git repositories when they are cloned and updated. See{' '}
+                <a rel="noopener noreferrer" target="_blank" href="https://docs.sourcegraph.com/admin/repo/subversion">
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setURL',
+            label: 'Set Subversion base URL',
+            run: (config: string) => {
+                const value = 'https://svn.example.com/repos/'
+                const edits = setProperty(config, ['url'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addRepo',
+            label: 'Add a repository',
+            run: (config: string) => {
+                const value = 'path/to/repository'
+                const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
 
 export const codeHostExternalServices: Record<string, AddExternalServiceOptions> = {
     github: GITHUB_DOTCOM,
@@ -1222,6 +1335,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     git: GENERIC_GIT,
     ...(window.context?.experimentalFeatures?.perforce === 'enabled' ? { perforce: PERFORCE } : {}),
     ...(window.context?.experimentalFeatures?.jvmPackages === 'enabled' ? { jvmPackages: JVM_PACKAGES } : {}),
+    mercurial: MERCURIAL,
+    subversion: SUBVERSION,
 }
 
 export const nonCodeHostExternalServices: Record<string, AddExternalServiceOptions> = {
@@ -1244,4 +1359,6 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.JVMPACKAGES]: JVM_PACKAGES,
+    [ExternalServiceKind.MERCURIAL]: MERCURIAL,
+    [ExternalServiceKind.SUBVERSION]: SUBVERSION,
 }
@@ -37,6 +37,7 @@ const globalPolicies: CodeIntelligenceConfigurationPolicyFields[] = [
         retentionEnabled: true,
         retentionDurationHours: 168,
         retainIntermediateCommits: false,
+        retentionCount: null,
         indexingEnabled: false,
         indexCommitMaxAgeHours: 672,
         indexIntermediateCommits: false,
@@ -50,6 +51,7 @@ const globalPolicies: CodeIntelligenceConfigurationPolicyFields[] = [
         retentionEnabled: true,
         retentionDurationHours: 2016,
         retainIntermediateCommits: false,
+        retentionCount: null,
         indexingEnabled: false,
         indexCommitMaxAgeHours: 4032,
         indexIntermediateCommits: false,
@@ -66,6 +68,7 @@ const policies: CodeIntelligenceConfigurationPolicyFields[] = [
         retentionEnabled: true,
         retentionDurationHours: 8064,
         retainIntermediateCommits: true,
+        retentionCount: null,
         indexingEnabled: true,
         indexCommitMaxAgeHours: 40320,
         indexIntermediateCommits: true,
@@ -79,6 +82,7 @@ const policies: CodeIntelligenceConfigurationPolicyFields[] = [
         retentionEnabled: true,
         retentionDurationHours: 8064,
         retainIntermediateCommits: true,
+        retentionCount: null,
         indexingEnabled: true,
         indexCommitMaxAgeHours: 40320,
         indexIntermediateCommits: true,
@@ -22,6 +22,7 @@ const policy: CodeIntelligenceConfigurationPolicyFields = {
     retentionEnabled: true,
     retentionDurationHours: 168,
     retainIntermediateCommits: true,
+    retentionCount: null,
     indexingEnabled: true,
     indexCommitMaxAgeHours: 672,
     indexIntermediateCommits: true,
@@ -50,6 +50,7 @@ const emptyPolicy: CodeIntelligenceConfigurationPolicyFields = {
     retentionEnabled: false,
     retentionDurationHours: null,
     retainIntermediateCommits: false,
+    retentionCount: null,
     indexingEnabled: false,
     indexCommitMaxAgeHours: null,
     indexIntermediateCommits: false,
@@ -191,6 +192,7 @@ function comparePolicies(
         a.retentionEnabled === b.retentionEnabled &&
         a.retentionDurationHours === b.retentionDurationHours &&
         a.retainIntermediateCommits === b.retainIntermediateCommits &&
+        a.retentionCount === b.retentionCount &&
         a.indexingEnabled === b.indexingEnabled &&
         a.indexCommitMaxAgeHours === b.indexCommitMaxAgeHours &&
         a.indexIntermediateCommits === b.indexIntermediateCommits
@@ -17,6 +17,9 @@ export const RetentionPolicyDescription: FunctionComponent<{ policy: CodeIntelli
                 {policy.retentionDurationHours && (
                     <> for at least {formatDurationValue(policy.retentionDurationHours)} after upload</>
                 )}
+                {policy.retainIntermediateCommits && policy.retentionCount && (
+                    <>, up to the {policy.retentionCount} most recent commits of each branch</>
+                )}
                 .
             </span>
         </>
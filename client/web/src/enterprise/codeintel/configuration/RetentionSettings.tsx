@@ -53,5 +53,25 @@ export const RetentionSettings: FunctionComponent<RetentionSettingsProps> = ({ p
                 </label>
             </div>
         )}
+
+        {policy.type === GitObjectType.GIT_TREE && policy.retainIntermediateCommits && (
+            <div className="form-group">
+                <label htmlFor="retention-count">Max number of commits per branch</label>
+                <input
+                    id="retention-count"
+                    type="number"
+                    min={1}
+                    className="form-control"
+                    value={policy.retentionCount ?? ''}
+                    onChange={event =>
+                        setPolicy({
+                            ...policy,
+                            retentionCount: event.target.value === '' ? null : Math.max(1, event.target.valueAsNumber),
+                        })
+                    }
+                    disabled={!policy.retentionEnabled}
+                />
+            </div>
+        )}
     </Container>
 )
@@ -49,6 +49,7 @@ const codeIntelligenceConfigurationPolicyFieldsFragment = gql`
         retentionEnabled
         retentionDurationHours
         retainIntermediateCommits
+        retentionCount
         indexingEnabled
         indexCommitMaxAgeHours
         indexIntermediateCommits
@@ -114,6 +115,7 @@ export function updatePolicy(
                 $retentionEnabled: Boolean!
                 $retentionDurationHours: Int
                 $retainIntermediateCommits: Boolean!
+                $retentionCount: Int
                 $indexingEnabled: Boolean!
                 $indexCommitMaxAgeHours: Int
                 $indexIntermediateCommits: Boolean!
@@ -126,6 +128,7 @@ export function updatePolicy(
                     retentionEnabled: $retentionEnabled
                     retentionDurationHours: $retentionDurationHours
                     retainIntermediateCommits: $retainIntermediateCommits
+                    retentionCount: $retentionCount
                     indexingEnabled: $indexingEnabled
                     indexCommitMaxAgeHours: $indexCommitMaxAgeHours
                     indexIntermediateCommits: $indexIntermediateCommits
@@ -155,6 +158,7 @@ export function updatePolicy(
             $retentionEnabled: Boolean!
             $retentionDurationHours: Int
             $retainIntermediateCommits: Boolean!
+            $retentionCount: Int
             $indexingEnabled: Boolean!
             $indexCommitMaxAgeHours: Int
             $indexIntermediateCommits: Boolean!
@@ -167,6 +171,7 @@ export function updatePolicy(
                 retentionEnabled: $retentionEnabled
                 retentionDurationHours: $retentionDurationHours
                 retainIntermediateCommits: $retainIntermediateCommits
+                retentionCount: $retentionCount
                 indexingEnabled: $indexingEnabled
                 indexCommitMaxAgeHours: $indexCommitMaxAgeHours
                 indexIntermediateCommits: $indexIntermediateCommits
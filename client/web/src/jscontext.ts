@@ -94,7 +94,7 @@ export interface SourcegraphContext extends Pick<Required<SiteConfiguration>, 'e
 
     /** Authentication provider instances in site config. */
     authProviders: {
-        serviceType: 'github' | 'gitlab' | 'http-header' | 'openidconnect' | 'saml' | 'builtin'
+        serviceType: 'github' | 'gitlab' | 'bitbucketCloud' | 'http-header' | 'openidconnect' | 'saml' | 'ldap' | 'builtin'
         displayName: string
         isBuiltin: boolean
         authenticationURL?: string
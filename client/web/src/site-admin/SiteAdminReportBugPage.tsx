@@ -15,11 +15,13 @@ import githubSchemaJSON from '../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../schema/gitolite.schema.json'
 import jvmPackagesSchemaJSON from '../../../../schema/jvm-packages.schema.json'
+import mercurialSchemaJSON from '../../../../schema/mercurial.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../schema/other_external_service.schema.json'
 import perforceSchemaJSON from '../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../schema/phabricator.schema.json'
 import settingsSchemaJSON from '../../../../schema/settings.schema.json'
 import siteSchemaJSON from '../../../../schema/site.schema.json'
+import subversionSchemaJSON from '../../../../schema/subversion.schema.json'
 import { PageTitle } from '../components/PageTitle'
 import { DynamicallyImportedMonacoSettingsEditor } from '../settings/DynamicallyImportedMonacoSettingsEditor'
 
@@ -42,9 +44,11 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     GITLAB: gitlabSchemaJSON,
     GITOLITE: gitoliteSchemaJSON,
     JVMPACKAGES: jvmPackagesSchemaJSON,
+    MERCURIAL: mercurialSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
     PERFORCE: perforceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
+    SUBVERSION: subversionSchemaJSON,
 }
 
 const allConfigSchema = {
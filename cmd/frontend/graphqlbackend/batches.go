@@ -246,6 +246,16 @@ type CancelBatchSpecExecutionArgs struct {
 	BatchSpecExecution graphql.ID
 }
 
+type ResolveBatchSpecWorkspacesArgs struct {
+	BatchSpec        graphql.ID
+	AllowUnsupported bool
+	AllowIgnored     bool
+}
+
+type RetryBatchSpecWorkspaceExecutionArgs struct {
+	BatchSpecWorkspace graphql.ID
+}
+
 type CloseChangesetsArgs struct {
 	BulkOperationBaseArgs
 }
@@ -255,6 +265,46 @@ type PublishChangesetsArgs struct {
 	Draft bool
 }
 
+type BatchSpecTemplateInputDefinition struct {
+	Name        string
+	Description string
+	// Type is a value of type batcheslib.TemplateInputType.
+	Type         string
+	DefaultValue *JSONValue
+}
+
+type CreateBatchSpecTemplateArgs struct {
+	Namespace   *graphql.ID
+	Name        string
+	Description string
+	Template    string
+	Inputs      []BatchSpecTemplateInputDefinition
+}
+
+type UpdateBatchSpecTemplateArgs struct {
+	BatchSpecTemplate graphql.ID
+	Name              string
+	Description       string
+	Template          string
+	Inputs            []BatchSpecTemplateInputDefinition
+}
+
+type DeleteBatchSpecTemplateArgs struct {
+	BatchSpecTemplate graphql.ID
+}
+
+type InstantiateBatchSpecTemplateArgs struct {
+	BatchSpecTemplate graphql.ID
+	Namespace         graphql.ID
+	Inputs            *JSONValue
+}
+
+type ListBatchSpecTemplatesArgs struct {
+	First     int32
+	After     *string
+	Namespace *graphql.ID
+}
+
 type BatchChangesResolver interface {
 	//
 	// MUTATIONS
@@ -287,8 +337,14 @@ type BatchChangesResolver interface {
 	MergeChangesets(ctx context.Context, args *MergeChangesetsArgs) (BulkOperationResolver, error)
 	CreateBatchSpecExecution(ctx context.Context, args *CreateBatchSpecExecutionArgs) (BatchSpecExecutionResolver, error)
 	CancelBatchSpecExecution(ctx context.Context, args *CancelBatchSpecExecutionArgs) (BatchSpecExecutionResolver, error)
+	ResolveBatchSpecWorkspaces(ctx context.Context, args *ResolveBatchSpecWorkspacesArgs) (BatchSpecWorkspaceResolutionResolver, error)
+	RetryBatchSpecWorkspaceExecution(ctx context.Context, args *RetryBatchSpecWorkspaceExecutionArgs) (BatchSpecWorkspaceResolver, error)
 	CloseChangesets(ctx context.Context, args *CloseChangesetsArgs) (BulkOperationResolver, error)
 	PublishChangesets(ctx context.Context, args *PublishChangesetsArgs) (BulkOperationResolver, error)
+	CreateBatchSpecTemplate(ctx context.Context, args *CreateBatchSpecTemplateArgs) (BatchSpecTemplateResolver, error)
+	UpdateBatchSpecTemplate(ctx context.Context, args *UpdateBatchSpecTemplateArgs) (BatchSpecTemplateResolver, error)
+	DeleteBatchSpecTemplate(ctx context.Context, args *DeleteBatchSpecTemplateArgs) (*EmptyResponse, error)
+	InstantiateBatchSpecTemplate(ctx context.Context, args *InstantiateBatchSpecTemplateArgs) (BatchSpecResolver, error)
 
 	// Queries
 
@@ -303,6 +359,7 @@ type BatchChangesResolver interface {
 	BatchChangesCodeHosts(ctx context.Context, args *ListBatchChangesCodeHostsArgs) (BatchChangesCodeHostConnectionResolver, error)
 	RepoChangesetsStats(ctx context.Context, repo *graphql.ID) (RepoChangesetsStatsResolver, error)
 	RepoDiffStat(ctx context.Context, repo *graphql.ID) (*DiffStat, error)
+	BatchSpecTemplates(ctx context.Context, args *ListBatchSpecTemplatesArgs) (BatchSpecTemplateConnectionResolver, error)
 
 	NodeResolvers() map[string]NodeByIDFunc
 }
@@ -358,6 +415,8 @@ type BatchSpecResolver interface {
 
 	ViewerBatchChangesCodeHosts(ctx context.Context, args *ListViewerBatchChangesCodeHostsArgs) (BatchChangesCodeHostConnectionResolver, error)
 
+	WorkspaceResolution(ctx context.Context) (BatchSpecWorkspaceResolutionResolver, error)
+
 	// TODO(campaigns-deprecation)
 	// Defined so that BatchSpecResolver can act as a CampaignSpec:
 	AppliesToCampaign(ctx context.Context) (BatchChangeResolver, error)
@@ -594,6 +653,7 @@ type BatchChangeResolver interface {
 	CreatedAt() DateTime
 	UpdatedAt() DateTime
 	ChangesetsStats(ctx context.Context) (ChangesetsStatsResolver, error)
+	ChecksBreakdown(ctx context.Context) ([]ChangesetCheckBreakdownResolver, error)
 	Changesets(ctx context.Context, args *ListChangesetsArgs) (ChangesetsConnectionResolver, error)
 	ChangesetCountsOverTime(ctx context.Context, args *ChangesetCountsArgs) ([]ChangesetCountsResolver, error)
 	ClosedAt() *DateTime
@@ -642,6 +702,22 @@ type ChangesetsConnectionResolver interface {
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
 }
 
+type ChangesetCheckResolver interface {
+	Name() string
+	// State returns a value of type *btypes.ChangesetCheckState.
+	State() *string
+	URL() *string
+}
+
+type ChangesetCheckBreakdownResolver interface {
+	Name() string
+	Required() bool
+	Passed() int32
+	Failed() int32
+	Pending() int32
+	Missing() int32
+}
+
 type ChangesetLabelResolver interface {
 	Text() string
 	Color() string
@@ -697,6 +773,7 @@ type ExternalChangesetResolver interface {
 	ReviewState(context.Context) *string
 	// CheckState returns a value of type *btypes.ChangesetCheckState.
 	CheckState() *string
+	Checks() []ChangesetCheckResolver
 	Repository(ctx context.Context) *RepositoryResolver
 
 	Events(ctx context.Context, args *ChangesetEventsConnectionArgs) (ChangesetEventsConnectionResolver, error)
@@ -750,8 +827,55 @@ type BatchSpecExecutionResolver interface {
 	Namespace(ctx context.Context) (*NamespaceResolver, error)
 }
 
+type BatchSpecWorkspaceResolutionResolver interface {
+	State() string
+	FailureMessage() *string
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	Workspaces(ctx context.Context) ([]BatchSpecWorkspaceResolver, error)
+}
+
+type BatchSpecWorkspaceResolver interface {
+	ID() graphql.ID
+	Repository(ctx context.Context) (*RepositoryResolver, error)
+	Branch() string
+	Commit() string
+	Path() string
+	State(ctx context.Context) (string, error)
+	FailureMessage(ctx context.Context) (*string, error)
+	StartedAt(ctx context.Context) (*DateTime, error)
+	FinishedAt(ctx context.Context) (*DateTime, error)
+}
+
 type BatchSpecExecutionStepsResolver interface {
 	Setup() []ExecutionLogEntryResolver
 	SrcPreview() ExecutionLogEntryResolver
 	Teardown() []ExecutionLogEntryResolver
 }
+
+type BatchSpecTemplateResolver interface {
+	ID() graphql.ID
+	Name() string
+	Description() string
+	Template() string
+	Inputs() []BatchSpecTemplateInputResolver
+	Namespace(ctx context.Context) (*NamespaceResolver, error)
+	Creator(ctx context.Context) (*UserResolver, error)
+	ViewerCanAdminister(ctx context.Context) (bool, error)
+	CreatedAt() DateTime
+	UpdatedAt() DateTime
+}
+
+type BatchSpecTemplateInputResolver interface {
+	Name() string
+	Description() string
+	Type() string
+	DefaultValue() *JSONValue
+	Required() bool
+}
+
+type BatchSpecTemplateConnectionResolver interface {
+	Nodes(ctx context.Context) ([]BatchSpecTemplateResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -26,8 +28,10 @@ type CodeIntelResolver interface {
 	CreateCodeIntelligenceConfigurationPolicy(ctx context.Context, args *CreateCodeIntelligenceConfigurationPolicyArgs) (CodeIntelligenceConfigurationPolicyResolver, error)
 	UpdateCodeIntelligenceConfigurationPolicy(ctx context.Context, args *UpdateCodeIntelligenceConfigurationPolicyArgs) (*EmptyResponse, error)
 	DeleteCodeIntelligenceConfigurationPolicy(ctx context.Context, args *DeleteCodeIntelligenceConfigurationPolicyArgs) (*EmptyResponse, error)
+	PreviewCodeIntelligenceRetention(ctx context.Context, args *PreviewCodeIntelligenceRetentionArgs) (CodeIntelligenceRetentionPreviewResolver, error)
 	IndexConfiguration(ctx context.Context, id graphql.ID) (IndexConfigurationResolver, error) // TODO - rename ...ForRepo
 	UpdateRepositoryIndexConfiguration(ctx context.Context, args *UpdateRepositoryIndexConfigurationArgs) (*EmptyResponse, error)
+	SearchDocumentation(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error)
 	NodeResolvers() map[string]NodeByIDFunc
 }
 
@@ -60,12 +64,20 @@ type LSIFUploadResolver interface {
 	PlaceInQueue() *int32
 	AssociatedIndex(ctx context.Context) (LSIFIndexResolver, error)
 	ProjectRoot(ctx context.Context) (*GitTreeEntryResolver, error)
+	ValidationErrors(ctx context.Context) ([]LSIFUploadValidationErrorResolver, error)
+}
+
+type LSIFUploadValidationErrorResolver interface {
+	Severity() string
+	Message() string
+	RelevantLines() []int32
 }
 
 type LSIFUploadConnectionResolver interface {
 	Nodes(ctx context.Context) ([]LSIFUploadResolver, error)
 	TotalCount(ctx context.Context) (*int32, error)
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+	Precise() bool
 }
 
 type LSIFIndexesQueryArgs struct {
@@ -150,16 +162,21 @@ type GitBlobLSIFDataResolver interface {
 	Ranges(ctx context.Context, args *LSIFRangesArgs) (CodeIntelligenceRangeConnectionResolver, error)
 	Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
+	TypeDefinitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
+	Implementations(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
+	IncomingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) ([]CallHierarchyItemResolver, error)
+	OutgoingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) ([]CallHierarchyItemResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
 	Documentation(ctx context.Context, args *LSIFQueryPositionArgs) (DocumentationResolver, error)
 }
 
 type GitBlobLSIFDataArgs struct {
-	Repo      *types.Repo
-	Commit    api.CommitID
-	Path      string
-	ExactPath bool
-	ToolName  string
+	Repo        *types.Repo
+	Commit      api.CommitID
+	Path        string
+	ExactPath   bool
+	ToolName    string
+	SearchBased bool
 }
 
 type LSIFRangesArgs struct {
@@ -172,6 +189,11 @@ type LSIFQueryPositionArgs struct {
 	Character int32
 }
 
+type LSIFCallHierarchyArgs struct {
+	LSIFQueryPositionArgs
+	Depth int32
+}
+
 type LSIFPagedQueryPositionArgs struct {
 	LSIFQueryPositionArgs
 	graphqlutil.ConnectionArgs
@@ -214,6 +236,12 @@ type HoverResolver interface {
 	Range() RangeResolver
 }
 
+type CallHierarchyItemResolver interface {
+	Definition(ctx context.Context) (LocationResolver, error)
+	CallSites(ctx context.Context) ([]LocationResolver, error)
+	Calls() []CallHierarchyItemResolver
+}
+
 type DocumentationResolver interface {
 	PathID() string
 }
@@ -239,6 +267,7 @@ type CodeIntelConfigurationPolicy struct {
 	RetentionEnabled          bool
 	RetentionDurationHours    *int32
 	RetainIntermediateCommits bool
+	RetentionCount            *int32
 	IndexingEnabled           bool
 	IndexCommitMaxAgeHours    *int32
 	IndexIntermediateCommits  bool
@@ -262,6 +291,16 @@ type DeleteCodeIntelligenceConfigurationPolicyArgs struct {
 	Policy graphql.ID
 }
 
+type PreviewCodeIntelligenceRetentionArgs struct {
+	Repository graphql.ID
+	Policy     *graphql.ID
+}
+
+type CodeIntelligenceRetentionPreviewResolver interface {
+	Retained() []LSIFUploadResolver
+	Expired() []LSIFUploadResolver
+}
+
 type IndexConfigurationResolver interface {
 	Configuration(ctx context.Context) (*string, error)
 	InferredConfiguration(ctx context.Context) (*string, error)
@@ -280,6 +319,7 @@ type CodeIntelligenceConfigurationPolicyResolver interface {
 	RetentionEnabled() bool
 	RetentionDurationHours() *int32
 	RetainIntermediateCommits() bool
+	RetentionCount() *int32
 	IndexingEnabled() bool
 	IndexCommitMaxAgeHours() *int32
 	IndexIntermediateCommits() bool
@@ -258,3 +258,13 @@ func (r *NodeResolver) ToBatchSpecExecution() (BatchSpecExecutionResolver, bool)
 	n, ok := r.Node.(BatchSpecExecutionResolver)
 	return n, ok
 }
+
+func (r *NodeResolver) ToBatchSpecWorkspace() (BatchSpecWorkspaceResolver, bool) {
+	n, ok := r.Node.(BatchSpecWorkspaceResolver)
+	return n, ok
+}
+
+func (r *NodeResolver) ToBatchSpecTemplate() (BatchSpecTemplateResolver, bool) {
+	n, ok := r.Node.(BatchSpecTemplateResolver)
+	return n, ok
+}
@@ -44,13 +44,10 @@ func TestRepositoryComparison(t *testing.T) {
 	}
 	t.Cleanup(func() { git.Mocks.ResolveRevision = nil })
 
-	git.Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
-		if len(args) < 1 && args[0] != "diff" {
-			t.Fatalf("gitserver.ExecReader received wrong args: %v", args)
-		}
-		return io.NopCloser(strings.NewReader(testDiff + testCopyDiff)), nil
+	git.Mocks.Diff = func(repo api.RepoName, rangeSpec string) ([]byte, error) {
+		return []byte(testDiff + testCopyDiff), nil
 	}
-	t.Cleanup(func() { git.Mocks.ExecReader = nil })
+	t.Cleanup(func() { git.Mocks.Diff = nil })
 
 	git.Mocks.MergeBase = func(repo api.RepoName, a, b api.CommitID) (api.CommitID, error) {
 		if string(a) != wantBaseRevision || string(b) != wantHeadRevision {
@@ -33,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/docs"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
@@ -321,8 +322,8 @@ loop:
 	for _, r := range sr.Matches {
 		r := r // shadow so it doesn't change in the goroutine
 		switch m := r.(type) {
-		case *result.RepoMatch:
-			// We don't care about repo results here.
+		case *result.RepoMatch, *result.DocumentationMatch:
+			// We don't care about repo or documentation results here.
 			continue
 		case *result.CommitMatch:
 			// Diff searches are cheap, because we implicitly have author date info.
@@ -631,6 +632,19 @@ func (r *searchResolver) toSearchInputs(q query.Q) (*search.TextParameters, []ru
 				},
 			})
 		}
+
+		if args.ResultTypes.Has(result.TypeDocs) && p.Pattern != "" && EnterpriseResolvers.codeIntelResolver != nil {
+			limit := docs.DefaultLimit
+			if count := q.Count(); count != nil {
+				limit = *count
+			}
+
+			jobs = append(jobs, &docs.DocumentationSearch{
+				Searcher: EnterpriseResolvers.codeIntelResolver,
+				Args:     &args,
+				Limit:    limit,
+			})
+		}
 	}
 	return &args, jobs, nil
 }
@@ -1457,7 +1471,7 @@ func (r *searchResolver) doResults(ctx context.Context, args *search.TextParamet
 		defer cancelOnLimit()
 	}
 
-	agg := run.NewAggregator(r.db, stream)
+	agg := run.NewAggregator(ctx, r.db, stream)
 
 	// This ensures we properly cleanup in the case of an early return. In
 	// particular we want to cancel global searches before returning early.
@@ -1733,6 +1747,8 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 			return string(r.Name), "", nil
 		case *result.FileMatch:
 			return string(r.Repo.Name), r.Path, nil
+		case *result.DocumentationMatch:
+			return string(r.Repo.Name), r.PathID, nil
 		case *result.CommitMatch:
 			// Commits are relatively sorted by date, and after repo
 			// or path names. We use ~ as the key for repo and
@@ -1740,7 +1756,7 @@ func compareSearchResults(left, right result.Match, exactFilePatterns map[string
 			return "~", "~", &r.Commit.Author.Date
 		}
 		// Unreachable.
-		panic("unreachable: compareSearchResults expects RepositoryResolver, FileMatchResolver, or CommitSearchResultResolver")
+		panic("unreachable: compareSearchResults expects RepositoryResolver, FileMatchResolver, CommitSearchResultResolver, or DocumentationMatch")
 	}
 
 	arepo, afile, adate := sortKeys(left)
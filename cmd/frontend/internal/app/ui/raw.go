@@ -21,6 +21,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/vfsutil"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -145,6 +147,18 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 
 	switch contentType {
 	case applicationZip, applicationXTar:
+		// 🚨 SECURITY: Archives are created by gitserver and contain all the files
+		// of the requested path, so we don't serve them from repositories in which
+		// the actor has sub-repo permissions rules.
+		enabled, err := authz.ActorEnabledForRepo(r.Context(), authz.DefaultSubRepoPermsChecker, actor.FromContext(r.Context()), common.Repo.Name)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			w.WriteHeader(http.StatusNotFound)
+			return nil
+		}
+
 		// Set the proper filename field, so that downloading "/github.com/gorilla/mux/-/raw" gives us a
 		// "mux.zip" file (e.g. when downloading via a browser) or a .tar file depending on the contentType.
 		ext := ".zip"
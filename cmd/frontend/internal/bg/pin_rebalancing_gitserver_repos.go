@@ -0,0 +1,42 @@
+package bg
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// PinRebalancingGitserverRepos routes requests of the default gitserver client for
+// repositories which are being moved between gitservers to the gitserver which
+// served them before the move, until the move completes. The set of repositories
+// being moved is refreshed periodically in the background.
+func PinRebalancingGitserverRepos(ctx context.Context, db dbutil.DB) {
+	var pinned atomic.Value
+	pinned.Store(map[api.RepoName]string{})
+
+	gitserver.DefaultClient.PinnedAddrs = func() map[api.RepoName]string {
+		return pinned.Load().(map[api.RepoName]string)
+	}
+
+	goroutine.Go(func() {
+		for {
+			shards, err := database.GitserverRepos(db).ListRebalancing(ctx)
+			if err != nil {
+				log15.Error("listing rebalancing gitserver repos", "error", err)
+			} else {
+				pinned.Store(gitserver.PinnedAddrsForShards(shards, conf.Get().ServiceConnections.GitServers))
+			}
+
+			time.Sleep(10 * time.Second)
+		}
+	})
+}
@@ -253,6 +253,7 @@ func Main(enterpriseSetupHook func(db dbutil.DB, outOfBandMigrationRunner *oobmi
 	goroutine.Go(func() { bg.DeleteOldEventLogsInPostgres(context.Background(), db) })
 	goroutine.Go(func() { bg.DeleteOldSecurityEventLogsInPostgres(context.Background(), db) })
 	goroutine.Go(func() { updatecheck.Start(db) })
+	bg.PinRebalancingGitserverRepos(context.Background(), db)
 
 	// Parse GraphQL schema and set up resolvers that depend on dbconn.Global
 	// being initialized
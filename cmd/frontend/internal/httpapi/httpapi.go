@@ -60,15 +60,30 @@ func NewHandler(db dbutil.DB, m *mux.Router, schema *graphql.Schema, githubWebho
 		ExternalServices: database.ExternalServices(db),
 	}
 
-	webhookhandlers.Init(db, &gh)
+	// Events which aren't handled by the code host agnostic webhook handlers
+	// are passed on to the enterprise webhook handlers.
+	gl := webhooks.GitLabWebhook{
+		ExternalServices: database.ExternalServices(db),
+		Next:             gitlabWebhook,
+	}
+	bbs := webhooks.BitbucketServerWebhook{
+		ExternalServices: database.ExternalServices(db),
+		Next:             bitbucketServerWebhook,
+	}
+	bbc := webhooks.BitbucketCloudWebhook{
+		ExternalServices: database.ExternalServices(db),
+	}
+
+	webhookhandlers.Init(db, &gh, &gl, &bbs, &bbc)
 
 	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(&gh))
 
 	githubWebhook.Register(&gh)
 
 	m.Get(apirouter.GitHubWebhooks).Handler(trace.Route(&gh))
-	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(gitlabWebhook))
-	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(bitbucketServerWebhook))
+	m.Get(apirouter.GitLabWebhooks).Handler(trace.Route(&gl))
+	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.Route(&bbs))
+	m.Get(apirouter.BitbucketCloudWebhooks).Handler(trace.Route(&bbc))
 	m.Get(apirouter.LSIFUpload).Handler(trace.Route(newCodeIntelUploadHandler(false)))
 
 	if envvar.SourcegraphDotComMode() {
@@ -145,6 +160,7 @@ func NewInternalHandler(m *mux.Router, db dbutil.DB, schema *graphql.Schema, new
 	m.Get(apirouter.GraphQL).Handler(trace.Route(handler(serveGraphQL(schema, rateLimitWatcher, true))))
 	m.Get(apirouter.Configuration).Handler(trace.Route(handler(serveConfiguration)))
 	m.Get(apirouter.SearchConfiguration).Handler(trace.Route(handler(serveSearchConfiguration)))
+	m.Get(apirouter.StreamingSearch).Handler(trace.Route(frontendsearch.StreamHandler(db)))
 	m.Path("/ping").Methods("GET").Name("ping").HandlerFunc(handlePing)
 
 	m.Get(apirouter.LSIFUpload).Handler(trace.Route(newCodeIntelUploadHandler(true)))
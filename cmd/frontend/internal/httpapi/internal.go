@@ -214,13 +214,25 @@ func serveSearchConfiguration(w http.ResponseWriter, r *http.Request) error {
 
 		priority := float64(repo.Stars) + repoRankFromConfig(siteConfig, repoName)
 
+		// Files containing definitions referenced by many other repositories
+		// (as determined by precise code intelligence) are ranked first.
+		pathCounts, err := database.ReferenceCounts(dbconn.Global).GetPathCounts(ctx, repo.ID)
+		if err != nil {
+			return nil, err
+		}
+		documentRanks := make(map[string]float64, len(pathCounts))
+		for path, count := range pathCounts {
+			documentRanks[path] = float64(count)
+		}
+
 		return &searchbackend.RepoIndexOptions{
-			RepoID:     int32(repo.ID),
-			Public:     !repo.Private,
-			Priority:   priority,
-			Fork:       repo.Fork,
-			Archived:   repo.Archived,
-			GetVersion: getVersion,
+			RepoID:        int32(repo.ID),
+			Public:        !repo.Private,
+			Priority:      priority,
+			Fork:          repo.Fork,
+			Archived:      repo.Archived,
+			DocumentRanks: documentRanks,
+			GetVersion:    getVersion,
 		}, nil
 	}
 
@@ -0,0 +1,153 @@
+package webhookhandlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// handleGitLabPushEvent handles gitlab push and tag push events, and enqueues an
+// update of the pushed repo.
+func handleGitLabPushEvent(db dbutil.DB) func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+	return func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		log15.Debug("handleGitLabPushEvent: Got gitlab event", "type", fmt.Sprintf("%T", payload))
+
+		e, ok := payload.(*webhooks.PushEvent)
+		if !ok {
+			return errors.Errorf("incorrect event type sent to gitlab event handler: %T", payload)
+		}
+
+		c, err := extSvc.Configuration()
+		if err != nil {
+			return err
+		}
+		gc, ok := c.(*schema.GitLabConnection)
+		if !ok {
+			return errors.Errorf("invalid configuration for gitlab external service: %v", extSvc.ID)
+		}
+		serviceID, err := normalizeServiceID(gc.Url)
+		if err != nil {
+			return err
+		}
+
+		return enqueueRepoUpdate(ctx, db, api.ExternalRepoSpec{
+			ID:          strconv.Itoa(e.Project.ID),
+			ServiceType: extsvc.TypeGitLab,
+			ServiceID:   serviceID,
+		})
+	}
+}
+
+// handleBitbucketServerPushEvent handles bitbucket server refs changed events, and
+// enqueues an update of the pushed repo.
+func handleBitbucketServerPushEvent(db dbutil.DB) func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+	return func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		log15.Debug("handleBitbucketServerPushEvent: Got bitbucket server event", "type", fmt.Sprintf("%T", payload))
+
+		e, ok := payload.(*bitbucketserver.RepoRefsChangedEvent)
+		if !ok {
+			return errors.Errorf("incorrect event type sent to bitbucket server event handler: %T", payload)
+		}
+
+		c, err := extSvc.Configuration()
+		if err != nil {
+			return err
+		}
+		bc, ok := c.(*schema.BitbucketServerConnection)
+		if !ok {
+			return errors.Errorf("invalid configuration for bitbucket server external service: %v", extSvc.ID)
+		}
+		serviceID, err := normalizeServiceID(bc.Url)
+		if err != nil {
+			return err
+		}
+
+		return enqueueRepoUpdate(ctx, db, api.ExternalRepoSpec{
+			ID:          strconv.Itoa(e.Repository.ID),
+			ServiceType: extsvc.TypeBitbucketServer,
+			ServiceID:   serviceID,
+		})
+	}
+}
+
+// handleBitbucketCloudPushEvent handles bitbucket cloud push events, and enqueues an
+// update of the pushed repo.
+func handleBitbucketCloudPushEvent(db dbutil.DB) func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+	return func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		log15.Debug("handleBitbucketCloudPushEvent: Got bitbucket cloud event", "type", fmt.Sprintf("%T", payload))
+
+		e, ok := payload.(*bitbucketcloud.PushEvent)
+		if !ok {
+			return errors.Errorf("incorrect event type sent to bitbucket cloud event handler: %T", payload)
+		}
+
+		c, err := extSvc.Configuration()
+		if err != nil {
+			return err
+		}
+		bc, ok := c.(*schema.BitbucketCloudConnection)
+		if !ok {
+			return errors.Errorf("invalid configuration for bitbucket cloud external service: %v", extSvc.ID)
+		}
+		serviceID, err := normalizeServiceID(bc.Url)
+		if err != nil {
+			return err
+		}
+
+		return enqueueRepoUpdate(ctx, db, api.ExternalRepoSpec{
+			ID:          e.Repository.UUID,
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   serviceID,
+		})
+	}
+}
+
+// normalizeServiceID returns the ServiceID of the repos of a code host with the
+// given URL, as computed by the sources in internal/repos.
+func normalizeServiceID(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing code host URL")
+	}
+	return extsvc.NormalizeBaseURL(u).String(), nil
+}
+
+// enqueueRepoUpdate finds an internal repo from its external repo spec, and posts it to
+// repo-updater to schedule an update.
+// 🚨 SECURITY: we want to be able to find any private repo here, so the DB call uses internal actor
+func enqueueRepoUpdate(ctx context.Context, db dbutil.DB, spec api.ExternalRepoSpec) error {
+	// 🚨 SECURITY: we want to be able to find any private repo here, so set internal actor
+	ctx = actor.WithInternalActor(ctx)
+	rs, err := database.Repos(db).List(ctx, database.ReposListOptions{
+		ExternalRepos: []api.ExternalRepoSpec{spec},
+	})
+	if err != nil {
+		return err
+	}
+	if len(rs) == 0 {
+		// The repo is not mirrored by Sourcegraph.
+		log15.Debug("enqueueRepoUpdate: Ignoring push to unknown repo", "externalRepo", spec)
+		return nil
+	}
+
+	log15.Debug("enqueueRepoUpdate: Dispatching repo update", "repo", rs[0].Name)
+
+	_, err = repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, rs[0].Name)
+	return err
+}
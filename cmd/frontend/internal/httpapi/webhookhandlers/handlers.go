@@ -6,7 +6,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 )
 
-func Init(db dbutil.DB, w *webhooks.GitHubWebhook) {
+func Init(db dbutil.DB, w *webhooks.GitHubWebhook, gitlab *webhooks.GitLabWebhook, bitbucketServer *webhooks.BitbucketServerWebhook, bitbucketCloud *webhooks.BitbucketCloudWebhook) {
 	// Refer to https://docs.github.com/en/developers/webhooks-and-events/webhooks/webhook-events-and-payloads
 	// for event types
 
@@ -23,4 +23,11 @@ func Init(db dbutil.DB, w *webhooks.GitHubWebhook) {
 	w.Register(handleGitHubUserAuthzEvent(db, authz.FetchPermsOptions{InvalidateCaches: true}), "organisation")
 	w.Register(handleGitHubUserAuthzEvent(db, authz.FetchPermsOptions{InvalidateCaches: true}), "membership")
 
+	// Push events, which schedule an update of the pushed repository. Refer to
+	// https://docs.gitlab.com/ee/user/project/integrations/webhooks.html#push-events,
+	// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Push
+	// and https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Push
+	gitlab.Register(handleGitLabPushEvent(db), "push", "tag_push")
+	bitbucketServer.Register(handleBitbucketServerPushEvent(db), "repo:refs_changed")
+	bitbucketCloud.Register(handleBitbucketCloudPushEvent(db), "repo:push")
 }
@@ -388,6 +388,8 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo)
 		return fromRepository(v, repoCache)
 	case *result.CommitMatch:
 		return fromCommit(v, repoCache)
+	case *result.DocumentationMatch:
+		return fromDocumentation(v, repoCache)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -540,6 +542,27 @@ func fromCommit(commit *result.CommitMatch, repoCache map[api.RepoID]*types.Sear
 	return commitEvent
 }
 
+func fromDocumentation(dm *result.DocumentationMatch, repoCache map[api.RepoID]*types.SearchedRepo) *streamhttp.EventDocumentationMatch {
+	documentationEvent := &streamhttp.EventDocumentationMatch{
+		Type:       streamhttp.DocumentationMatchType,
+		Label:      dm.Label,
+		URL:        dm.URL().String(),
+		Detail:     dm.Detail,
+		SearchKey:  dm.SearchKey,
+		Tags:       dm.Tags,
+		Language:   dm.Language,
+		Repository: string(dm.Repo.Name),
+		Version:    string(dm.Commit),
+	}
+
+	if r, ok := repoCache[dm.Repo.ID]; ok {
+		documentationEvent.RepoStars = r.Stars
+		documentationEvent.RepoLastFetched = r.LastFetched
+	}
+
+	return documentationEvent
+}
+
 // eventStreamOTHook returns a StatHook which logs to log.
 func eventStreamOTHook(log func(...otlog.Field)) func(streamhttp.WriterStat) {
 	return func(stat streamhttp.WriterStat) {
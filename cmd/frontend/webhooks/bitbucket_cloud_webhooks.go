@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketCloudSecretParam is the URL parameter carrying the webhook secret of
+// Bitbucket Cloud webhooks, as Bitbucket Cloud does not sign webhook payloads.
+const BitbucketCloudSecretParam = "secret"
+
+// BitbucketCloudWebhook is responsible for handling incoming http requests for bitbucket
+// cloud webhooks and routing to any registered WebhookHandlers, events are routed by their
+// event type, passed in the X-Event-Key header.
+type BitbucketCloudWebhook struct {
+	ExternalServices *database.ExternalServiceStore
+
+	webhookRouter
+}
+
+func (h *BitbucketCloudWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log15.Error("Error parsing bitbucket cloud webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	eventType := bitbucketcloud.WebhookEventType(r)
+	if !h.handles(eventType) {
+		serveNext(nil, w, r, body)
+		return
+	}
+
+	// get external service and validate webhook secret
+	extSvc, err := h.getExternalService(r)
+	if err != nil {
+		log15.Error("Could not find valid external service for webhook", "error", err)
+		http.Error(w, "External service not found", http.StatusUnauthorized)
+		return
+	}
+
+	// parse event
+	e, err := bitbucketcloud.ParseWebhookEvent(eventType, body)
+	if err != nil {
+		log15.Error("Error parsing bitbucket cloud webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// match event handlers
+	err = h.Dispatch(r.Context(), eventType, extSvc, e)
+	if err != nil {
+		log15.Error("Error handling bitbucket cloud webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *BitbucketCloudWebhook) getExternalService(r *http.Request) (*types.ExternalService, error) {
+	q := r.URL.Query()
+	e, err := getExternalServiceByRawID(r.Context(), h.ExternalServices, extsvc.KindBitbucketCloud, q.Get(extsvc.IDParam))
+	if err != nil {
+		return nil, err
+	}
+	c, err := e.Configuration()
+	if err != nil {
+		return nil, err
+	}
+	bc, ok := c.(*schema.BitbucketCloudConnection)
+	if !ok {
+		return nil, errors.Errorf("invalid configuration, received bitbucket cloud webhook for non-bitbucket cloud external service: %v", e.ID)
+	}
+
+	// 🚨 SECURITY: Authenticate the request with the stored secret. If there is
+	// no secret or it doesn't match the one sent with the request, we return an
+	// error to the client.
+	secret := q.Get(BitbucketCloudSecretParam)
+	if bc.WebhookSecret == "" || secret == "" {
+		return nil, errors.New("missing webhook secret")
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(bc.WebhookSecret)) != 1 {
+		return nil, errors.New("webhook secret does not match the configured secret")
+	}
+	return e, nil
+}
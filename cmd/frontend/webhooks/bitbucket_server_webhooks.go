@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	gh "github.com/google/go-github/v28/github"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// BitbucketServerWebhook is responsible for handling incoming http requests for bitbucket
+// server webhooks and routing to any registered WebhookHandlers, events are routed by their
+// event type, passed in the X-Event-Key header.
+//
+// Events no handlers are registered for are passed on to Next.
+type BitbucketServerWebhook struct {
+	ExternalServices *database.ExternalServiceStore
+	Next             http.Handler
+
+	webhookRouter
+}
+
+func (h *BitbucketServerWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log15.Error("Error parsing bitbucket server webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	eventType := bitbucketserver.WebhookEventType(r)
+	if !h.handles(eventType) {
+		serveNext(h.Next, w, r, body)
+		return
+	}
+
+	// get external service and validate webhook payload signature
+	extSvc, err := h.getExternalService(r.Context(), r.Header.Get("X-Hub-Signature"), r.FormValue(extsvc.IDParam), body)
+	if err != nil {
+		log15.Error("Could not find valid external service for webhook", "error", err)
+		http.Error(w, "External service not found", http.StatusUnauthorized)
+		return
+	}
+
+	// parse event
+	e, err := bitbucketserver.ParseWebhookEvent(eventType, body)
+	if err != nil {
+		log15.Error("Error parsing bitbucket server webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// match event handlers
+	err = h.Dispatch(r.Context(), eventType, extSvc, e)
+	if err != nil {
+		log15.Error("Error handling bitbucket server webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getExternalService returns the Bitbucket Server external service whose
+// webhook secret the payload is signed with. The externalServiceID URL
+// parameter is missing on webhooks which were configured before it was added,
+// in which case all Bitbucket Server external services are searched.
+func (h *BitbucketServerWebhook) getExternalService(ctx context.Context, sig, rawID string, body []byte) (*types.ExternalService, error) {
+	args := database.ExternalServicesListOptions{Kinds: []string{extsvc.KindBitbucketServer}}
+	if rawID != "" {
+		externalServiceID, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing the raw external service ID")
+		}
+		args.IDs = []int64{externalServiceID}
+	}
+
+	es, err := h.ExternalServices.List(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Try to authenticate the request with any of the stored secrets.
+	// If there are no secrets or no secret managed to authenticate the request,
+	// we return an error to the client.
+	for _, e := range es {
+		c, err := e.Configuration()
+		if err != nil {
+			return nil, err
+		}
+		con, ok := c.(*schema.BitbucketServerConnection)
+		if !ok {
+			continue
+		}
+
+		if secret := con.WebhookSecret(); secret != "" {
+			if err := gh.ValidateSignature(sig, body, []byte(secret)); err == nil {
+				return e, nil
+			}
+		}
+	}
+	return nil, errExternalServiceNotFound
+}
@@ -0,0 +1,109 @@
+package webhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// GitLabWebhook is responsible for handling incoming http requests for gitlab webhooks
+// and routing to any registered WebhookHandlers, events are routed by their object kind,
+// passed in the object_kind field of the payload.
+//
+// Events no handlers are registered for are passed on to Next.
+type GitLabWebhook struct {
+	ExternalServices *database.ExternalServiceStore
+	Next             http.Handler
+
+	webhookRouter
+}
+
+func (h *GitLabWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log15.Error("Error parsing gitlab webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var kind struct {
+		ObjectKind string `json:"object_kind"`
+	}
+	if err := json.Unmarshal(body, &kind); err != nil {
+		log15.Error("Error parsing gitlab webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	eventType := kind.ObjectKind
+	if !h.handles(eventType) {
+		serveNext(h.Next, w, r, body)
+		return
+	}
+
+	// get external service and validate webhook secret
+	extSvc, err := h.getExternalService(r)
+	if err != nil {
+		log15.Error("Could not find valid external service for webhook", "error", err)
+		http.Error(w, "External service not found", http.StatusUnauthorized)
+		return
+	}
+
+	// parse event
+	e, err := webhooks.UnmarshalEvent(body)
+	if err != nil {
+		log15.Error("Error parsing gitlab webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// match event handlers
+	err = h.Dispatch(r.Context(), eventType, extSvc, e)
+	if err != nil {
+		log15.Error("Error handling gitlab webhook event", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *GitLabWebhook) getExternalService(r *http.Request) (*types.ExternalService, error) {
+	e, err := getExternalServiceByRawID(r.Context(), h.ExternalServices, extsvc.KindGitLab, r.FormValue(extsvc.IDParam))
+	if err != nil {
+		return nil, err
+	}
+	c, err := e.Configuration()
+	if err != nil {
+		return nil, err
+	}
+	gc, ok := c.(*schema.GitLabConnection)
+	if !ok {
+		return nil, errors.Errorf("invalid configuration, received gitlab webhook for non-gitlab external service: %v", e.ID)
+	}
+
+	// 🚨 SECURITY: Try to authenticate the request with any of the stored secrets.
+	// If there are no secrets or no secret matches the one sent with the request,
+	// we return an error to the client.
+	secret := r.Header.Get(webhooks.TokenHeaderName)
+	if secret == "" {
+		return nil, errors.New("missing webhook secret")
+	}
+	for _, hook := range gc.Webhooks {
+		if hook.Secret == "" {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(hook.Secret)) == 1 {
+			return e, nil
+		}
+	}
+	return nil, errors.New("webhook secret does not match any configured secret")
+}
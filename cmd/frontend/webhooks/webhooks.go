@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// webhookRouter routes webhook events to the WebhookHandlers registered for
+// their event type. It is embedded by the webhook receivers of code hosts other
+// than GitHub.
+type webhookRouter struct {
+	mu       sync.RWMutex
+	handlers map[string][]WebhookHandler
+}
+
+// Dispatch accepts an event for a particular event type and dispatches it
+// to the appropriate stack of handlers, if any are configured.
+func (h *webhookRouter) Dispatch(ctx context.Context, eventType string, extSvc *types.ExternalService, e interface{}) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	g := errgroup.Group{}
+	for _, handler := range h.handlers[eventType] {
+		// capture the handler variable within this loop
+		handler := handler
+		g.Go(func() error {
+			return handler(ctx, extSvc, e)
+		})
+	}
+	return g.Wait()
+}
+
+// Register associates a given event type(s) with the specified handler.
+// Handlers are organized into a stack and executed sequentially, so the order in
+// which they are provided is significant.
+func (h *webhookRouter) Register(handler WebhookHandler, eventTypes ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[string][]WebhookHandler)
+	}
+	for _, eventType := range eventTypes {
+		h.handlers[eventType] = append(h.handlers[eventType], handler)
+	}
+}
+
+// handles returns true if any handlers are registered for the event type.
+func (h *webhookRouter) handles(eventType string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.handlers[eventType]) > 0
+}
+
+// serveNext passes a request whose body has already been read to next, which
+// handles the events no WebhookHandlers are registered for. If next is nil, the
+// event is acknowledged and dropped.
+func serveNext(next http.Handler, w http.ResponseWriter, r *http.Request, body []byte) {
+	if next == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	next.ServeHTTP(w, r)
+}
+
+var errExternalServiceNotFound = errors.New("external service not found")
+
+// getExternalServiceByRawID returns the external service of the given kind with
+// the ID in the raw externalServiceID URL parameter.
+func getExternalServiceByRawID(ctx context.Context, store *database.ExternalServiceStore, kind, rawID string) (*types.ExternalService, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the raw external service ID")
+	}
+
+	es, err := store.List(ctx, database.ExternalServicesListOptions{
+		IDs:   []int64{id},
+		Kinds: []string{kind},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing external services")
+	}
+	if len(es) == 0 {
+		return nil, errExternalServiceNotFound
+	}
+	return es[0], nil
+}
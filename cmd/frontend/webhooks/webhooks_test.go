@@ -0,0 +1,203 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGitLabWebhookServeNext(t *testing.T) {
+	payload := []byte(`{"object_kind":"merge_request"}`)
+
+	var nextBody []byte
+	h := GitLabWebhook{
+		Next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nextBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	}
+	h.Register(func(ctx context.Context, svc *types.ExternalService, payload interface{}) error {
+		t.Errorf("Expected push handler not to be called")
+		return nil
+	}, "push")
+
+	req, err := http.NewRequest("POST", "https://example.com/.api/gitlab-webhooks", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if have, want := rec.Result().StatusCode, http.StatusAccepted; have != want {
+		t.Fatalf("Expected status %d, got %d", want, have)
+	}
+	if !bytes.Equal(nextBody, payload) {
+		t.Fatalf("Expected Next to receive payload %q, got %q", payload, nextBody)
+	}
+}
+
+func TestGitLabWebhookExternalServices(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	db := dbtest.NewDB(t, *dsn)
+
+	ctx := context.Background()
+
+	secret := "secret"
+	esStore := database.ExternalServices(db)
+	extSvc := &types.ExternalService{
+		Kind:        extsvc.KindGitLab,
+		DisplayName: "GitLab",
+		Config: marshalJSON(t, &schema.GitLabConnection{
+			Url:          "https://gitlab.com",
+			Token:        "abc",
+			ProjectQuery: []string{"none"},
+			Webhooks:     []*schema.GitLabWebhook{{Secret: secret}},
+		}),
+	}
+
+	err := esStore.Upsert(ctx, extSvc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := GitLabWebhook{
+		ExternalServices: esStore,
+	}
+
+	var called bool
+	hook.Register(func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		evt, ok := payload.(*webhooks.PushEvent)
+		if !ok {
+			t.Errorf("Expected *webhooks.PushEvent event, got %T", payload)
+		}
+		if evt.Project.ID != 42 {
+			t.Errorf("Expected project 42, got %d", evt.Project.ID)
+		}
+		called = true
+		return nil
+	}, "push")
+
+	for _, tc := range []struct {
+		secret string
+		status int
+		called bool
+	}{
+		{secret: secret, status: http.StatusOK, called: true},
+		{secret: "wrong", status: http.StatusUnauthorized},
+		{secret: "", status: http.StatusUnauthorized},
+	} {
+		called = false
+
+		payload := []byte(`{"object_kind":"push","ref":"refs/heads/main","project":{"id":42}}`)
+		req, err := http.NewRequest("POST", extsvc.WebhookURL(extsvc.TypeGitLab, extSvc.ID, "https://example.com/"), bytes.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(webhooks.TokenHeaderName, tc.secret)
+
+		rec := httptest.NewRecorder()
+		hook.ServeHTTP(rec, req)
+
+		if have := rec.Result().StatusCode; have != tc.status {
+			t.Fatalf("secret %q: expected status %d, got %d", tc.secret, tc.status, have)
+		}
+		if called != tc.called {
+			t.Fatalf("secret %q: expected called to be %v, got %v", tc.secret, tc.called, called)
+		}
+	}
+}
+
+func TestBitbucketCloudWebhookExternalServices(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	db := dbtest.NewDB(t, *dsn)
+
+	ctx := context.Background()
+
+	secret := "secret-secret"
+	esStore := database.ExternalServices(db)
+	extSvc := &types.ExternalService{
+		Kind:        extsvc.KindBitbucketCloud,
+		DisplayName: "Bitbucket Cloud",
+		Config: marshalJSON(t, &schema.BitbucketCloudConnection{
+			Url:           "https://bitbucket.org",
+			Username:      "user",
+			AppPassword:   "password",
+			WebhookSecret: secret,
+		}),
+	}
+
+	err := esStore.Upsert(ctx, extSvc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := BitbucketCloudWebhook{
+		ExternalServices: esStore,
+	}
+
+	var called bool
+	hook.Register(func(ctx context.Context, extSvc *types.ExternalService, payload interface{}) error {
+		evt, ok := payload.(*bitbucketcloud.PushEvent)
+		if !ok {
+			t.Errorf("Expected *bitbucketcloud.PushEvent event, got %T", payload)
+		}
+		if want := "{fceb73c7-cef6-4abe-956d-e471281126bd}"; evt.Repository.UUID != want {
+			t.Errorf("Expected repository %q, got %q", want, evt.Repository.UUID)
+		}
+		called = true
+		return nil
+	}, "repo:push")
+
+	for _, tc := range []struct {
+		secret string
+		status int
+		called bool
+	}{
+		{secret: secret, status: http.StatusOK, called: true},
+		{secret: "wrong", status: http.StatusUnauthorized},
+		{secret: "", status: http.StatusUnauthorized},
+	} {
+		called = false
+
+		u := extsvc.WebhookURL(extsvc.TypeBitbucketCloud, extSvc.ID, "https://example.com/") + "&" + BitbucketCloudSecretParam + "=" + url.QueryEscape(tc.secret)
+		payload := []byte(`{"repository":{"uuid":"{fceb73c7-cef6-4abe-956d-e471281126bd}","full_name":"sourcegraph/sourcegraph"},"push":{"changes":[]}}`)
+		req, err := http.NewRequest("POST", u, bytes.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Event-Key", "repo:push")
+
+		rec := httptest.NewRecorder()
+		hook.ServeHTTP(rec, req)
+
+		if have := rec.Result().StatusCode; have != tc.status {
+			t.Fatalf("secret %q: expected status %d, got %d", tc.secret, tc.status, have)
+		}
+		if called != tc.called {
+			t.Fatalf("secret %q: expected called to be %v, got %v", tc.secret, tc.called, called)
+		}
+	}
+}
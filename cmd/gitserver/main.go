@@ -8,6 +8,9 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -39,6 +42,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -49,6 +53,7 @@ var (
 	syncRepoStateInterval        = env.MustGetDuration("SRC_REPOS_SYNC_STATE_INTERVAL", 10*time.Minute, "Interval between state syncs")
 	syncRepoStateBatchSize       = env.MustGetInt("SRC_REPOS_SYNC_STATE_BATCH_SIZE", 500, "Number of upserts to perform per batch")
 	syncRepoStateUpsertPerSecond = env.MustGetInt("SRC_REPOS_SYNC_STATE_UPSERT_PER_SEC", 500, "The number of upserted rows allowed per second across all gitserver instances")
+	rebalance, _                 = strconv.ParseBool(env.Get("SRC_REPOS_REBALANCE", "false", "Move repositories from the gitserver previously serving them when gitservers are added or removed, rather than recloning them from their code host"))
 )
 
 func main() {
@@ -169,11 +174,35 @@ func main() {
 				}
 
 				return &server.JVMPackagesSyncer{Config: &c, DBStore: codeintelDB}, nil
+			case extsvc.TypeMercurial:
+				return &server.MercurialRepoSyncer{}, nil
+			case extsvc.TypeSubversion:
+				var c schema.SubversionConnection
+				for _, info := range r.Sources {
+					es, err := externalServiceStore.GetByID(ctx, info.ExternalServiceID())
+					if err != nil {
+						return nil, errors.Wrap(err, "get external service")
+					}
+
+					normalized, err := jsonc.Parse(es.Config)
+					if err != nil {
+						return nil, errors.Wrap(err, "normalize JSON")
+					}
+
+					if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+						return nil, errors.Wrap(err, "unmarshal JSON")
+					}
+					break
+				}
+
+				return &server.SubversionRepoSyncer{Layout: c.Layout}, nil
 			}
-			return &server.GitRepoSyncer{}, nil
+
+			return gitRepoSyncer(ctx, externalServiceStore, r)
 		},
-		Hostname: hostname.Get(),
-		DB:       db,
+		Hostname:  hostname.Get(),
+		DB:        db,
+		Rebalance: rebalance,
 	}
 	gitserver.RegisterMetrics()
 
@@ -236,6 +265,148 @@ func main() {
 	gitserver.Stop()
 }
 
+// defaultGitLFSMaxFileSize is the size of the largest Git LFS object fetched
+// if the maxFileSize of the gitLFS configuration of an external service is
+// unset.
+const defaultGitLFSMaxFileSize = 10 << 20
+
+// defaultPartialCloneBlobSizeLimit is the size of the largest blobs cloned with
+// a partial clone if the blobSizeLimit of its configuration is unset.
+const defaultPartialCloneBlobSizeLimit = 1 << 20
+
+// gitRepoSyncer returns the syncer of the Git repository repo. Git LFS objects
+// and partial clones are configured by the first external services of repo
+// which configure them.
+func gitRepoSyncer(ctx context.Context, externalServiceStore *database.ExternalServiceStore, repo *types.Repo) (*server.GitRepoSyncer, error) {
+	var syncer server.GitRepoSyncer
+	for _, info := range repo.Sources {
+		es, err := externalServiceStore.GetByID(ctx, info.ExternalServiceID())
+		if err != nil {
+			return nil, errors.Wrap(err, "get external service")
+		}
+
+		cfg, err := extsvc.ParseConfig(es.Kind, es.Config)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse external service config")
+		}
+
+		if syncer.LFS == nil {
+			syncer.LFS = gitLFSOptions(cfg)
+		}
+		if syncer.PartialClone == nil {
+			syncer.PartialClone, err = partialCloneOptions(cfg, repo.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &syncer, nil
+}
+
+// gitLFSOptions returns the Git LFS options of the external service config
+// cfg, or nil if it doesn't enable fetching Git LFS objects.
+func gitLFSOptions(cfg interface{}) *server.LFSOptions {
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		if c.GitLFS != nil && c.GitLFS.Enabled {
+			return newGitLFSOptions(c.GitLFS.MaxFileSize, c.GitLFS.Include, c.GitLFS.Exclude)
+		}
+	case *schema.GitLabConnection:
+		if c.GitLFS != nil && c.GitLFS.Enabled {
+			return newGitLFSOptions(c.GitLFS.MaxFileSize, c.GitLFS.Include, c.GitLFS.Exclude)
+		}
+	case *schema.BitbucketServerConnection:
+		if c.GitLFS != nil && c.GitLFS.Enabled {
+			return newGitLFSOptions(c.GitLFS.MaxFileSize, c.GitLFS.Include, c.GitLFS.Exclude)
+		}
+	case *schema.BitbucketCloudConnection:
+		if c.GitLFS != nil && c.GitLFS.Enabled {
+			return newGitLFSOptions(c.GitLFS.MaxFileSize, c.GitLFS.Include, c.GitLFS.Exclude)
+		}
+	case *schema.OtherExternalServiceConnection:
+		if c.GitLFS != nil && c.GitLFS.Enabled {
+			return newGitLFSOptions(c.GitLFS.MaxFileSize, c.GitLFS.Include, c.GitLFS.Exclude)
+		}
+	}
+	return nil
+}
+
+func newGitLFSOptions(maxFileSize int, include, exclude []string) *server.LFSOptions {
+	if maxFileSize <= 0 {
+		maxFileSize = defaultGitLFSMaxFileSize
+	}
+	return &server.LFSOptions{
+		MaxFileSize: int64(maxFileSize),
+		Include:     include,
+		Exclude:     exclude,
+	}
+}
+
+// partialCloneOptions returns the options of the first partial clone
+// configuration of the external service config cfg which matches repo, or nil
+// if none does.
+//
+// Repositories are never cloned partially while search indexing is enabled.
+// Zoekt fetches the repositories it indexes from gitserver with git
+// upload-pack, which fails on the blobs missing from a partial clone.
+func partialCloneOptions(cfg interface{}, repo api.RepoName) (*server.PartialCloneOptions, error) {
+	if conf.SearchIndexEnabled() {
+		return nil, nil
+	}
+
+	type partialClone struct {
+		repositoryPattern string
+		blobSizeLimit     *int
+		excludePaths      []string
+	}
+
+	var partialClones []partialClone
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		for _, pc := range c.PartialClones {
+			partialClones = append(partialClones, partialClone{pc.RepositoryPattern, pc.BlobSizeLimit, pc.ExcludePaths})
+		}
+	case *schema.GitLabConnection:
+		for _, pc := range c.PartialClones {
+			partialClones = append(partialClones, partialClone{pc.RepositoryPattern, pc.BlobSizeLimit, pc.ExcludePaths})
+		}
+	case *schema.BitbucketServerConnection:
+		for _, pc := range c.PartialClones {
+			partialClones = append(partialClones, partialClone{pc.RepositoryPattern, pc.BlobSizeLimit, pc.ExcludePaths})
+		}
+	case *schema.BitbucketCloudConnection:
+		for _, pc := range c.PartialClones {
+			partialClones = append(partialClones, partialClone{pc.RepositoryPattern, pc.BlobSizeLimit, pc.ExcludePaths})
+		}
+	case *schema.OtherExternalServiceConnection:
+		for _, pc := range c.PartialClones {
+			partialClones = append(partialClones, partialClone{pc.RepositoryPattern, pc.BlobSizeLimit, pc.ExcludePaths})
+		}
+	}
+
+	for _, pc := range partialClones {
+		re, err := regexp.Compile(pc.repositoryPattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid partial clone repositoryPattern %q", pc.repositoryPattern)
+		}
+		if !re.MatchString(string(repo)) {
+			continue
+		}
+
+		opts := &server.PartialCloneOptions{BlobSizeLimit: defaultPartialCloneBlobSizeLimit}
+		if pc.blobSizeLimit != nil && *pc.blobSizeLimit >= 0 {
+			opts.BlobSizeLimit = int64(*pc.blobSizeLimit)
+		}
+		for _, p := range pc.excludePaths {
+			if p = strings.Trim(p, "/"); p != "" {
+				opts.ExcludePaths = append(opts.ExcludePaths, p)
+			}
+		}
+		return opts, nil
+	}
+	return nil, nil
+}
+
 func getPercent(p int) (int, error) {
 	if p < 0 {
 		return 0, errors.Errorf("negative value given for percentage: %d", p)
@@ -1,7 +1,16 @@
 // gitserver is the gitserver server.
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
 
 func TestParsePercent(t *testing.T) {
 	tests := []struct {
@@ -29,3 +38,41 @@ func TestParsePercent(t *testing.T) {
 		})
 	}
 }
+
+func TestPartialCloneOptions(t *testing.T) {
+	blobSizeLimit := 1024
+	cfg := &schema.GitHubConnection{
+		PartialClones: []*schema.GitHubPartialClone{
+			{RepositoryPattern: "^github\\.com/foo/", BlobSizeLimit: &blobSizeLimit, ExcludePaths: []string{"/vendor/"}},
+		},
+	}
+
+	setSearchIndexEnabled := func(enabled bool) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchIndexEnabled: &enabled}})
+	}
+	defer conf.Mock(nil)
+
+	setSearchIndexEnabled(false)
+	for repo, want := range map[string]*server.PartialCloneOptions{
+		"github.com/foo/bar": {BlobSizeLimit: 1024, ExcludePaths: []string{"vendor"}},
+		"github.com/baz/bar": nil,
+	} {
+		got, err := partialCloneOptions(cfg, api.RepoName(repo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: unexpected options (-want +got):\n%s", repo, diff)
+		}
+	}
+
+	// Zoekt can't index partial clones.
+	setSearchIndexEnabled(true)
+	got, err := partialCloneOptions(cfg, "github.com/foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("expected no partial clone with search indexing enabled, got %+v", got)
+	}
+}
@@ -81,8 +81,9 @@ const reposStatsName = "repos-stats.json"
 // 4. Ensure correct git attributes
 // 5. Scrub remote URLs
 // 6. Perform garbage collection
-// 7. Re-clone repos after a while. (simulate git gc)
-// 8. Remove repos based on disk pressure.
+// 7. Remove stale replicas of hot repos.
+// 8. Re-clone repos after a while. (simulate git gc)
+// 9. Remove repos based on disk pressure.
 func (s *Server) cleanupRepos() {
 	janitorRunning.Set(1)
 	defer janitorRunning.Set(0)
@@ -155,10 +156,11 @@ func (s *Server) cleanupRepos() {
 			}
 		}
 
-		// We believe converting a Perforce depot to a Git repository is generally a
-		// very expensive operation, therefore we do not try to re-clone/redo the
-		// conversion only because it is old or slow to do "git gc".
-		if repoType == "perforce" && reason != maybeCorrupt {
+		// We believe converting a Perforce depot, Mercurial or Subversion
+		// repository to a Git repository is generally a very expensive operation,
+		// therefore we do not try to re-clone/redo the conversion only because it
+		// is old or slow to do "git gc".
+		if (repoType == "perforce" || repoType == "mercurial" || repoType == "subversion") && reason != maybeCorrupt {
 			reason = ""
 		}
 
@@ -227,6 +229,12 @@ func (s *Server) cleanupRepos() {
 		return false, gitGC(dir)
 	}
 
+	maybeRemoveStaleReplica := func(dir GitDir) (done bool, err error) {
+		// Replicas are fetched from their primary gitserver rather than re-cloned,
+		// so we stop here for them.
+		return s.removeStaleReplica(dir)
+	}
+
 	type cleanupFn struct {
 		Name string
 		Do   func(GitDir) (bool, error)
@@ -250,6 +258,9 @@ func (s *Server) cleanupRepos() {
 		// invocations of git add, packing refs, pruning reflog, rerere metadata or stale
 		// working trees. May also update ancillary indexes such as the commit-graph.
 		{"garbage collect", performGC},
+		// Replicas of repositories which are no longer hot are not fetched from their
+		// primary gitserver anymore, so we remove them once they become stale.
+		{"maybe remove stale replica", maybeRemoveStaleReplica},
 	}
 
 	if !conf.Get().DisableAutoGitUpdates {
@@ -488,6 +499,10 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 	ctx := context.Background()
 	dir := string(gitDir)
 
+	// Replicas are not recorded in the database, as the primary gitserver of the
+	// repository is responsible for it.
+	replica := isReplica(gitDir)
+
 	// Rename out of the location so we can atomically stop using the repo.
 	tmp, err := s.tempDir("delete-repo")
 	if err != nil {
@@ -502,7 +517,9 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 	// should not be returned, just logged.
 
 	// Set as not_cloned in the database
-	s.setCloneStatusNonFatal(ctx, s.name(gitDir), types.CloneStatusNotCloned)
+	if !replica {
+		s.setCloneStatusNonFatal(ctx, s.name(gitDir), types.CloneStatusNotCloned)
+	}
 
 	// Cleanup empty parent directories. We just attempt to remove and if we
 	// have a failure we assume it's due to the directory having other
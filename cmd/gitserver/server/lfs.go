@@ -0,0 +1,654 @@
+package server
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// LFSOptions configures which Git LFS objects are fetched for a repository.
+type LFSOptions struct {
+	// MaxFileSize is the size in bytes of the largest object which is fetched.
+	// Zero means there is no limit.
+	MaxFileSize int64
+	// Include are glob patterns of the paths whose objects are fetched. If
+	// empty, the objects of all paths are fetched.
+	Include []string
+	// Exclude are glob patterns of the paths whose objects are not fetched,
+	// even if they match Include.
+	Exclude []string
+}
+
+// lfsMaxPointerSize is the size in bytes of the largest blob which is
+// considered to be a Git LFS pointer. Pointers are much smaller in practice,
+// but the spec allows for extensions.
+const lfsMaxPointerSize = 1024
+
+// lfsBatchSize is the number of objects requested in a single call to the
+// batch API.
+const lfsBatchSize = 100
+
+// lfsBinaryCheckSize is the number of bytes at the start of an object which
+// are checked for NUL bytes to determine if it is binary, like git does.
+const lfsBinaryCheckSize = 8000
+
+var (
+	lfsObjectsFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_lfs_objects_fetched_total",
+		Help: "Number of Git LFS objects fetched from code hosts.",
+	}, []string{"status"})
+	lfsBytesFetched = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_lfs_fetched_bytes_total",
+		Help: "Size of the Git LFS objects fetched from code hosts in bytes.",
+	})
+)
+
+// lfsDoer is the HTTP client used to talk to Git LFS servers.
+var lfsDoer httpcli.Doer = httpcli.ExternalDoer
+
+// lfsPointer is a Git LFS pointer file, which is committed in place of the
+// content of a file stored in Git LFS.
+type lfsPointer struct {
+	// oid is the hex-encoded SHA-256 hash of the object.
+	oid  string
+	size int64
+}
+
+// parseLFSPointer parses b as a Git LFS pointer file, as described in
+// https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md. It returns false
+// if b is not a pointer.
+func parseLFSPointer(b []byte) (lfsPointer, bool) {
+	if len(b) > lfsMaxPointerSize || !bytes.HasPrefix(b, []byte("version https://git-lfs.github.com/spec/")) {
+		return lfsPointer{}, false
+	}
+
+	var p lfsPointer
+	sizeSet := false
+	for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		kv := strings.SplitN(line, " ", 2)
+		if len(kv) != 2 {
+			return lfsPointer{}, false
+		}
+		key, value := kv[0], kv[1]
+		switch key {
+		case "oid":
+			oid := strings.TrimPrefix(value, "sha256:")
+			if oid == value || len(oid) != 64 {
+				return lfsPointer{}, false
+			}
+			if _, err := hex.DecodeString(oid); err != nil {
+				return lfsPointer{}, false
+			}
+			p.oid = strings.ToLower(oid)
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return lfsPointer{}, false
+			}
+			p.size = size
+			sizeSet = true
+		}
+	}
+	if p.oid == "" || !sizeSet {
+		return lfsPointer{}, false
+	}
+	return p, true
+}
+
+// lfsObjectPath returns the path of the LFS object with the given oid in dir.
+// It uses the same layout as git-lfs, so that repositories cloned with git-lfs
+// are served the same way.
+func lfsObjectPath(dir GitDir, oid string) string {
+	return dir.Path("lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// hasLFSObjects returns true if LFS objects have been fetched for the
+// repository in dir.
+func hasLFSObjects(dir GitDir) bool {
+	_, err := os.Stat(dir.Path("lfs", "objects"))
+	return err == nil
+}
+
+// openLFSObject opens the LFS object pointed to by content if content is an
+// LFS pointer and its object has been fetched. Otherwise it returns nil.
+func openLFSObject(dir GitDir, content []byte) (*os.File, lfsPointer) {
+	p, ok := parseLFSPointer(content)
+	if !ok {
+		return nil, lfsPointer{}
+	}
+	f, err := os.Open(lfsObjectPath(dir, p.oid))
+	if err != nil {
+		return nil, p
+	}
+	if fi, err := f.Stat(); err != nil || fi.Size() != p.size {
+		f.Close()
+		return nil, p
+	}
+	return f, p
+}
+
+// lfsMatcher matches paths against the include and exclude patterns of
+// LFSOptions.
+type lfsMatcher struct {
+	include, exclude []glob.Glob
+}
+
+func compileLFSMatcher(opts *LFSOptions) (*lfsMatcher, error) {
+	compile := func(patterns []string) ([]glob.Glob, error) {
+		globs := make([]glob.Glob, 0, len(patterns))
+		for _, pattern := range patterns {
+			g, err := glob.Compile(pattern, '/')
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid Git LFS path pattern %q", pattern)
+			}
+			globs = append(globs, g)
+		}
+		return globs, nil
+	}
+
+	var m lfsMatcher
+	var err error
+	if m.include, err = compile(opts.Include); err != nil {
+		return nil, err
+	}
+	if m.exclude, err = compile(opts.Exclude); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *lfsMatcher) match(path string) bool {
+	for _, g := range m.exclude {
+		if g.Match(path) {
+			return false
+		}
+	}
+	if len(m.include) == 0 {
+		return true
+	}
+	for _, g := range m.include {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchLFSObjects fetches the LFS objects referenced by the tree of HEAD in
+// dir which match opts and haven't been fetched yet. Objects are fetched using
+// the batch API of the LFS server of remoteURL, which must be an HTTP(S) URL.
+func fetchLFSObjects(ctx context.Context, remoteURL *vcs.URL, dir GitDir, opts *LFSOptions) error {
+	endpoint, err := lfsEndpoint(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	matcher, err := compileLFSMatcher(opts)
+	if err != nil {
+		return err
+	}
+
+	pointers, err := listLFSPointers(ctx, dir, matcher)
+	if err != nil {
+		return errors.Wrap(err, "list Git LFS pointers")
+	}
+
+	var missing []lfsPointer
+	seen := make(map[string]bool)
+	for _, p := range pointers {
+		if seen[p.oid] || (opts.MaxFileSize > 0 && p.size > opts.MaxFileSize) {
+			continue
+		}
+		seen[p.oid] = true
+		if _, err := os.Stat(lfsObjectPath(dir, p.oid)); err == nil {
+			continue
+		}
+		missing = append(missing, p)
+	}
+
+	for len(missing) > 0 {
+		batch := missing
+		if len(batch) > lfsBatchSize {
+			batch = batch[:lfsBatchSize]
+		}
+		missing = missing[len(batch):]
+
+		objects, err := lfsBatch(ctx, endpoint, remoteURL, batch)
+		if err != nil {
+			return errors.Wrap(err, "Git LFS batch request")
+		}
+		for _, obj := range objects {
+			if err := downloadLFSObject(ctx, endpoint, remoteURL, dir, obj); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				lfsObjectsFetched.WithLabelValues("error").Inc()
+				log15.Warn("failed to fetch Git LFS object", "dir", dir, "oid", obj.OID, "error", newURLRedactor(remoteURL).redact(err.Error()))
+				continue
+			}
+			lfsObjectsFetched.WithLabelValues("ok").Inc()
+			lfsBytesFetched.Add(float64(obj.Size))
+		}
+	}
+	return nil
+}
+
+// lfsEndpoint returns the URL of the LFS server of the repository at
+// remoteURL, without credentials.
+func lfsEndpoint(remoteURL *vcs.URL) (string, error) {
+	if remoteURL.Scheme != "http" && remoteURL.Scheme != "https" {
+		return "", errors.Errorf("fetching Git LFS objects is not supported for %q remotes", remoteURL.Scheme)
+	}
+
+	u := remoteURL.URL
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs"
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// listLFSPointers returns the LFS pointers in the tree of HEAD in dir whose
+// paths are matched by matcher.
+func listLFSPointers(ctx context.Context, dir GitDir, matcher *lfsMatcher) ([]lfsPointer, error) {
+	if head, err := quickRevParseHead(dir); err != nil || !isAbsoluteRevision(head) {
+		// Empty repository.
+		return nil, nil
+	}
+
+	args := []string{"ls-tree", "-r", "--long", "--full-name", "-z", "HEAD"}
+	var entries []protocol.TreeEntry
+	out, err := runGit(ctx, dir, rpcListTree.maxOutput, args...)
+	switch {
+	case err != nil && out != nil && isMissingBlobError(out.stderr):
+		entries, err = lsTreePartialClone(ctx, dir, args, "HEAD", "")
+	case err != nil:
+	case out.truncated:
+		err = errors.Errorf("tree of HEAD is larger than %d bytes", rpcListTree.maxOutput)
+	default:
+		entries, err = protocol.ParseLsTree(out.stdout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates bytes.Buffer
+	for _, e := range entries {
+		// The size of blobs missing from partial clones is 0, and empty blobs
+		// aren't pointers either.
+		if e.Type == "blob" && e.Size > 0 && e.Size <= lfsMaxPointerSize && matcher.match(e.Path) {
+			candidates.WriteString(e.OID)
+			candidates.WriteByte('\n')
+		}
+	}
+	if candidates.Len() == 0 {
+		return nil, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	dir.Set(cmd)
+	cmd.Stdin = &candidates
+	cmd.Stdout = &stdout
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return nil, errors.Wrap(err, "git cat-file --batch")
+	}
+
+	// Each blob is written as "<oid> blob <size>\n<content>\n".
+	var pointers []lfsPointer
+	r := bufio.NewReader(&stdout)
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			return pointers, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid `git cat-file --batch` output: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Errorf("invalid `git cat-file --batch` size output: %q", header)
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return nil, err
+		}
+		if p, ok := parseLFSPointer(content[:size]); ok {
+			pointers = append(pointers, p)
+		}
+	}
+}
+
+type lfsBatchRequest struct {
+	Operation string           `json:"operation"`
+	Transfers []string         `json:"transfers"`
+	Objects   []lfsBatchObject `json:"objects"`
+}
+
+type lfsBatchResponse struct {
+	Objects []lfsBatchObject `json:"objects"`
+}
+
+type lfsBatchObject struct {
+	OID     string          `json:"oid"`
+	Size    int64           `json:"size"`
+	Actions *lfsActions     `json:"actions,omitempty"`
+	Error   *lfsObjectError `json:"error,omitempty"`
+}
+
+type lfsActions struct {
+	Download *lfsAction `json:"download"`
+}
+
+type lfsObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+// lfsBatch requests download actions for pointers from the LFS server at
+// endpoint. Objects the server can't provide are logged and omitted from the
+// result.
+func lfsBatch(ctx context.Context, endpoint string, remoteURL *vcs.URL, pointers []lfsPointer) ([]lfsBatchObject, error) {
+	batchReq := lfsBatchRequest{Operation: "download", Transfers: []string{"basic"}}
+	for _, p := range pointers {
+		batchReq.Objects = append(batchReq.Objects, lfsBatchObject{OID: p.oid, Size: p.size})
+	}
+	body, err := json.Marshal(&batchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.git-lfs+json")
+	req.Header.Set("Content-Type", "application/vnd.git-lfs+json")
+	setLFSAuth(req, remoteURL)
+
+	resp, err := lfsDoer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var batchResp lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, err
+	}
+
+	objects := make([]lfsBatchObject, 0, len(batchResp.Objects))
+	for _, obj := range batchResp.Objects {
+		if obj.Error != nil || obj.Actions == nil || obj.Actions.Download == nil {
+			var msg string
+			if obj.Error != nil {
+				msg = fmt.Sprintf("%d: %s", obj.Error.Code, obj.Error.Message)
+			}
+			lfsObjectsFetched.WithLabelValues("unavailable").Inc()
+			log15.Warn("Git LFS object is unavailable", "endpoint", endpoint, "oid", obj.OID, "error", msg)
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// setLFSAuth sets the credentials of remoteURL on req.
+func setLFSAuth(req *http.Request, remoteURL *vcs.URL) {
+	if remoteURL.User == nil {
+		return
+	}
+	password, _ := remoteURL.User.Password()
+	req.SetBasicAuth(remoteURL.User.Username(), password)
+}
+
+// downloadLFSObject downloads obj into dir. The content of the object is
+// verified against its oid before it is stored.
+func downloadLFSObject(ctx context.Context, endpoint string, remoteURL *vcs.URL, dir GitDir, obj lfsBatchObject) error {
+	if _, err := hex.DecodeString(obj.OID); err != nil || len(obj.OID) != 64 {
+		return errors.Errorf("invalid oid %q", obj.OID)
+	}
+	action := obj.Actions.Download
+
+	req, err := http.NewRequestWithContext(ctx, "GET", action.Href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range action.Header {
+		req.Header.Set(k, v)
+	}
+	// Like git-lfs, we only send the credentials of the remote to the LFS
+	// server itself. Other hosts, such as blob storage, authenticate requests
+	// using the headers of the action.
+	if req.Header.Get("Authorization") == "" && strings.HasPrefix(action.Href, endpoint) {
+		setLFSAuth(req, remoteURL)
+	}
+
+	resp, err := lfsDoer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	tmpDir := dir.Path("lfs", "tmp")
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(tmpDir, obj.OID+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, obj.Size+1))
+	if err != nil {
+		return err
+	}
+	if n != obj.Size {
+		return errors.Errorf("got %d bytes, expected %d", n, obj.Size)
+	}
+	if oid := hex.EncodeToString(h.Sum(nil)); oid != obj.OID {
+		return errors.Errorf("content has oid %s", oid)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	dst := lfsObjectPath(dir, obj.OID)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dst)
+}
+
+// lfsArchiveWriter is an http.ResponseWriter which rewrites a tar archive
+// written to it, replacing LFS pointers to fetched text objects with the
+// content of the objects. Responses with a status other than 200 are written
+// as is. Callers must call Close once the response has been written.
+type lfsArchiveWriter struct {
+	http.ResponseWriter
+	dir GitDir
+
+	passthrough bool
+	pw          *io.PipeWriter
+	done        chan struct{}
+}
+
+func newLFSArchiveWriter(w http.ResponseWriter, dir GitDir) *lfsArchiveWriter {
+	return &lfsArchiveWriter{ResponseWriter: w, dir: dir}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *lfsArchiveWriter) WriteHeader(code int) {
+	if code != http.StatusOK {
+		w.passthrough = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *lfsArchiveWriter) Write(p []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(p)
+	}
+	if w.pw == nil {
+		w.start()
+	}
+	return w.pw.Write(p)
+}
+
+// Flush implements http.Flusher. It is a no-op, since the rewritten archive is
+// flushed as it is written.
+func (w *lfsArchiveWriter) Flush() {}
+
+func (w *lfsArchiveWriter) start() {
+	pr, pw := io.Pipe()
+	w.pw = pw
+	w.done = make(chan struct{})
+
+	var out io.Writer = w.ResponseWriter
+	fw := newFlushingResponseWriter(w.ResponseWriter)
+	if fw != nil {
+		out = fw
+	}
+
+	go func() {
+		defer close(w.done)
+		if fw != nil {
+			defer fw.Close()
+		}
+
+		if err := rewriteLFSArchive(w.dir, pr, out); err != nil {
+			log15.Warn("failed to resolve Git LFS pointers in archive", "dir", w.dir, "error", err)
+			// Fail the writes of the archive, rather than blocking them.
+			_ = pr.CloseWithError(err)
+			return
+		}
+		// Consume the padding at the end of the archive.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+}
+
+// Close waits for the rewritten archive to be written.
+func (w *lfsArchiveWriter) Close() {
+	if w.pw == nil {
+		return
+	}
+	_ = w.pw.Close()
+	<-w.done
+}
+
+// rewriteLFSArchive copies the tar archive read from r to w, replacing LFS
+// pointers to fetched text objects with the content of the objects.
+func rewriteLFSArchive(dir GitDir, r io.Reader, w io.Writer) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > lfsMaxPointerSize {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if f, size := openLFSTextObject(dir, content); f != nil {
+			hdr.Size = size
+			err = tw.WriteHeader(hdr)
+			if err == nil {
+				_, err = io.Copy(tw, f)
+			}
+			f.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+}
+
+// openLFSTextObject is like openLFSObject, but only returns objects which
+// are text. It also returns the size of the object.
+func openLFSTextObject(dir GitDir, content []byte) (*os.File, int64) {
+	f, p := openLFSObject(dir, content)
+	if f == nil {
+		return nil, 0
+	}
+
+	buf := make([]byte, lfsBinaryCheckSize)
+	n, err := io.ReadFull(f, buf)
+	if (err != nil && err != io.ErrUnexpectedEOF && err != io.EOF) || bytes.IndexByte(buf[:n], 0) >= 0 {
+		f.Close()
+		return nil, 0
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, 0
+	}
+	return f, p.size
+}
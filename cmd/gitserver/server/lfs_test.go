@@ -0,0 +1,295 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestParseLFSPointer(t *testing.T) {
+	const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    lfsPointer
+		wantOK  bool
+	}{{
+		name:    "pointer",
+		content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n",
+		want:    lfsPointer{oid: oid, size: 12345},
+		wantOK:  true,
+	}, {
+		name:    "pointer with extension",
+		content: "version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + oid + "\noid sha256:" + oid + "\nsize 1\n",
+		want:    lfsPointer{oid: oid, size: 1},
+		wantOK:  true,
+	}, {
+		name:    "text",
+		content: "hello world\n",
+	}, {
+		name:    "missing size",
+		content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\n",
+	}, {
+		name:    "invalid oid",
+		content: "version https://git-lfs.github.com/spec/v1\noid sha256:../../../etc/passwd\nsize 1\n",
+	}, {
+		name:    "too large",
+		content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 1\n" + strings.Repeat("x", lfsMaxPointerSize),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseLFSPointer([]byte(tc.content))
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("got (%+v, %v), want (%+v, %v)", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestLFSMatcher(t *testing.T) {
+	m, err := compileLFSMatcher(&LFSOptions{
+		Include: []string{"**.json", "docs/**"},
+		Exclude: []string{"docs/large/**"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{
+		"data.json":              true,
+		"a/b/data.json":          true,
+		"docs/readme.md":         true,
+		"docs/large/video.json":  false,
+		"src/main.go":            false,
+		"documentation/guide.md": false,
+	} {
+		if got := m.match(path); got != want {
+			t.Errorf("match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+// lfsTestPointer returns the pointer file of content and the oid of its
+// object.
+func lfsTestPointer(content string) (pointer, oid string) {
+	sum := sha256.Sum256([]byte(content))
+	oid = hex.EncodeToString(sum[:])
+	return fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len(content)), oid
+}
+
+// newLFSTestServer returns a Git LFS server for the repository foo serving
+// objects. It records the oids of the objects which are downloaded.
+func newLFSTestServer(t *testing.T, objects map[string]string) (srv *httptest.Server, downloaded func() []string) {
+	var mu sync.Mutex
+	var oids []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/foo.git/info/lfs/objects/batch", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "user" || password != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req lfsBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp lfsBatchResponse
+		for _, obj := range req.Objects {
+			if _, ok := objects[obj.OID]; ok {
+				obj.Actions = &lfsActions{Download: &lfsAction{Href: srv.URL + "/objects/" + obj.OID}}
+			}
+			resp.Objects = append(resp.Objects, obj)
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	})
+	mux.HandleFunc("/objects/", func(w http.ResponseWriter, r *http.Request) {
+		oid := strings.TrimPrefix(r.URL.Path, "/objects/")
+		mu.Lock()
+		oids = append(oids, oid)
+		mu.Unlock()
+		_, _ = io.WriteString(w, objects[oid])
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), oids...)
+	}
+}
+
+func TestFetchLFSObjects(t *testing.T) {
+	const (
+		textContent  = "hello from git lfs\n"
+		largeContent = "this object is larger than the limit\n"
+	)
+	textPointer, textOID := lfsTestPointer(textContent)
+	largePointer, largeOID := lfsTestPointer(largeContent)
+	excludedPointer, excludedOID := lfsTestPointer("excluded\n")
+
+	srv, downloaded := newLFSTestServer(t, map[string]string{
+		textOID:     textContent,
+		largeOID:    largeContent,
+		excludedOID: "excluded\n",
+	})
+
+	oldDoer := lfsDoer
+	lfsDoer = http.DefaultClient
+	t.Cleanup(func() { lfsDoer = oldDoer })
+
+	reposDir := t.TempDir()
+	repoDir := filepath.Join(reposDir, "example.com", "foo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, repoDir, name, arg...)
+	}
+	cmd("git", "init", ".")
+	for path, content := range map[string]string{
+		"text.txt":         textPointer,
+		"large.txt":        largePointer,
+		"vendor/excl.txt":  excludedPointer,
+		"regular-file.txt": "not stored in LFS\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repoDir, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repoDir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd("git", "add", ".")
+	cmd("git", "commit", "-m", "lfs")
+	commit := api.CommitID(strings.TrimSpace(cmd("git", "rev-parse", "HEAD")))
+
+	remoteURL, err := vcs.ParseURL(strings.Replace(srv.URL, "http://", "http://user:secret@", 1) + "/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := GitDir(filepath.Join(repoDir, ".git"))
+	opts := &LFSOptions{
+		MaxFileSize: int64(len(largeContent) - 1),
+		Exclude:     []string{"vendor/**"},
+	}
+
+	if err := fetchLFSObjects(context.Background(), remoteURL, dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{textOID}, downloaded()); diff != "" {
+		t.Fatalf("unexpected downloaded objects (-want +got):\n%s", diff)
+	}
+	if got, err := os.ReadFile(lfsObjectPath(dir, textOID)); err != nil || string(got) != textContent {
+		t.Fatalf("got object %q (error %v), want %q", got, err, textContent)
+	}
+
+	// Objects which have been fetched are not fetched again.
+	if err := fetchLFSObjects(context.Background(), remoteURL, dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(downloaded()); got != 1 {
+		t.Fatalf("got %d downloads, want 1", got)
+	}
+
+	t.Run("read-blob", func(t *testing.T) {
+		s := &Server{ReposDir: reposDir, skipCloneForTests: true}
+		h := s.Handler()
+
+		for path, want := range map[string]string{
+			"text.txt":         textContent,
+			"large.txt":        largePointer,
+			"regular-file.txt": "not stored in LFS\n",
+		} {
+			body, err := json.Marshal(&protocol.ReadBlobRequest{Repo: "example.com/foo", Commit: commit, Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", "/read-blob", bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: unexpected status %d: %s", path, w.Code, w.Body)
+			}
+			var resp protocol.ReadBlobResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if string(resp.Content) != want {
+				t.Errorf("%s: got content %q, want %q", path, resp.Content, want)
+			}
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		var archive bytes.Buffer
+		tw := tar.NewWriter(&archive)
+		for _, f := range []struct{ name, content string }{
+			{"text.txt", textPointer},
+			{"large.txt", largePointer},
+			{"regular-file.txt", "not stored in LFS\n"},
+		} {
+			if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.WriteString(tw, f.content); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var rewritten bytes.Buffer
+		if err := rewriteLFSArchive(dir, &archive, &rewritten); err != nil {
+			t.Fatal(err)
+		}
+
+		got := map[string]string{}
+		tr := tar.NewReader(&rewritten)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[hdr.Name] = string(b)
+		}
+		want := map[string]string{
+			"text.txt":         textContent,
+			"large.txt":        largePointer,
+			"regular-file.txt": "not stored in LFS\n",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected archive (-want +got):\n%s", diff)
+		}
+	})
+}
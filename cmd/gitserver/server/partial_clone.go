@@ -0,0 +1,332 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// PartialCloneOptions configures a partial clone of a Git repository, which
+// only contains the blobs up to a size limit. The missing blobs are fetched
+// from the code host when they are read.
+type PartialCloneOptions struct {
+	// BlobSizeLimit is the size in bytes of the largest blob which is cloned.
+	// Zero means that no blobs are cloned.
+	BlobSizeLimit int64
+	// ExcludePaths are the paths of the files and directories whose blobs are
+	// never fetched. They are left out of archives.
+	ExcludePaths []string
+}
+
+// filter returns the git object filter of the partial clone.
+func (o *PartialCloneOptions) filter() string {
+	return "blob:limit=" + strconv.FormatInt(o.BlobSizeLimit, 10)
+}
+
+const (
+	// promisorRemote is the remote which partial clones fetch missing blobs
+	// from. We don't store remote URLs in repositories, so git can't fetch
+	// missing blobs on its own. Instead, we fetch them before running the git
+	// commands which read them, passing the URL in the config of git fetch.
+	promisorRemote = "sourcegraph-promisor"
+
+	// gitConfigPartialCloneExclude is a multi-valued key we add to git config
+	// which holds the ExcludePaths of a partial clone.
+	gitConfigPartialCloneExclude = "sourcegraph.partialCloneExclude"
+
+	// missingBlobsBatchSize is the number of missing blobs fetched by a single
+	// git fetch.
+	missingBlobsBatchSize = 1000
+)
+
+var partialCloneBlobsFetched = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_partial_clone_blobs_fetched_total",
+	Help: "Number of missing blobs of partial clones fetched from code hosts.",
+})
+
+var partialCloneConfigRe = lazyregexp.New(`(?im)^\s*partialclone\s*=\s*` + regexp.QuoteMeta(promisorRemote) + `\s*$`)
+
+// isPartialClone returns true if the repository at dir is a partial clone. It
+// reads the git config file rather than running git, as it is called for every
+// archive and file read.
+func isPartialClone(dir GitDir) bool {
+	b, err := os.ReadFile(dir.Path("config"))
+	return err == nil && partialCloneConfigRe.Match(b)
+}
+
+// configurePartialClone configures the repository at dir as a partial clone
+// with opts. It also updates the options of existing partial clones.
+func configurePartialClone(dir GitDir, opts *PartialCloneOptions) error {
+	for _, kv := range [][2]string{
+		// Extensions require version 1 of the repository format.
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", promisorRemote},
+		{"remote." + promisorRemote + ".promisor", "true"},
+		{"remote." + promisorRemote + ".partialclonefilter", opts.filter()},
+	} {
+		if err := gitConfigSet(dir, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := gitConfigUnset(dir, gitConfigPartialCloneExclude); err != nil {
+		return err
+	}
+	for _, path := range opts.ExcludePaths {
+		cmd := exec.Command("git", "config", "--add", gitConfigPartialCloneExclude, path)
+		dir.Set(cmd)
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(wrapCmdError(cmd, err), "failed to add git config %s", gitConfigPartialCloneExclude)
+		}
+	}
+	return nil
+}
+
+// partialCloneFilter returns the object filter of the partial clone at dir,
+// or "" if it isn't a partial clone.
+func partialCloneFilter(dir GitDir) string {
+	if !isPartialClone(dir) {
+		return ""
+	}
+	filter, _ := gitConfigGet(dir, "remote."+promisorRemote+".partialclonefilter")
+	return strings.TrimSpace(filter)
+}
+
+// partialCloneExcludePaths returns the paths whose blobs are never fetched
+// into the partial clone at dir.
+func partialCloneExcludePaths(dir GitDir) ([]string, error) {
+	cmd := exec.Command("git", "config", "--get-all", gitConfigPartialCloneExclude)
+	dir.Set(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means the key is not set.
+		var e *exec.ExitError
+		if errors.As(err, &e) && e.Sys().(syscall.WaitStatus).ExitStatus() == 1 {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(wrapCmdError(cmd, err), "failed to get git config %s", gitConfigPartialCloneExclude)
+	}
+
+	var paths []string
+	for _, path := range strings.Split(string(out), "\n") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// isMissingBlobError returns true if the stderr of a failed git command
+// reports that a blob missing from a partial clone could not be fetched.
+func isMissingBlobError(stderr string) bool {
+	return strings.Contains(stderr, "from promisor remote")
+}
+
+// missingBlobs returns the IDs of the blobs of the files matching pathspecs
+// in treeish which are missing from the partial clone at dir, except for the
+// blobs of the files at excludePaths.
+func missingBlobs(ctx context.Context, dir GitDir, treeish string, pathspecs, excludePaths []string) ([]string, error) {
+	args := []string{"rev-list", "--objects", "--missing=print", "--no-walk", treeish, "--"}
+	args = append(args, pathspecs...)
+	for _, path := range excludePaths {
+		args = append(args, ":(exclude,literal)"+path)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	cmd.Stderr = &limitWriter{W: &stderr, N: 1024}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// Missing objects are printed as "?<oid>".
+	var oids []string
+	sc := bufio.NewScanner(stdout)
+	for sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, "?") {
+			oids = append(oids, line[1:])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, errors.Wrapf(err, "git rev-list failed (stderr: %q)", stderr.String())
+	}
+	return oids, nil
+}
+
+// fetchBlobs fetches the blobs with the given IDs from remoteURL into the
+// partial clone at dir.
+func fetchBlobs(ctx context.Context, remoteURL *vcs.URL, dir GitDir, oids []string) error {
+	for len(oids) > 0 {
+		batch := oids
+		if len(batch) > missingBlobsBatchSize {
+			batch = batch[:missingBlobsBatchSize]
+		}
+		oids = oids[len(batch):]
+
+		args := []string{
+			"-c", "remote." + promisorRemote + ".url=" + remoteURL.String(),
+			"fetch", "--no-tags", "--recurse-submodules=no", "--filter=blob:none", promisorRemote,
+		}
+		cmd := exec.CommandContext(ctx, "git", append(args, batch...)...)
+		dir.Set(cmd)
+		if output, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+			return errors.Wrapf(err, "failed to fetch missing blobs with output %q", newURLRedactor(remoteURL).redact(string(output)))
+		}
+		partialCloneBlobsFetched.Add(float64(len(batch)))
+	}
+	return nil
+}
+
+// fetchMissingBlobs fetches the blobs of the files matching pathspecs in
+// treeish which are missing from the partial clone at dir, except for the
+// blobs of its excluded paths. It is a no-op if the repository isn't a partial
+// clone.
+func (s *Server) fetchMissingBlobs(ctx context.Context, repo api.RepoName, dir GitDir, treeish string, pathspecs []string) error {
+	if !isPartialClone(dir) {
+		return nil
+	}
+
+	excludePaths, err := partialCloneExcludePaths(dir)
+	if err != nil {
+		return err
+	}
+	oids, err := missingBlobs(ctx, dir, treeish, pathspecs, excludePaths)
+	if err != nil || len(oids) == 0 {
+		return err
+	}
+
+	remoteURL, err := s.getRemoteURL(ctx, repo)
+	if err != nil {
+		return errors.Wrap(err, "get remote URL")
+	}
+	return fetchBlobs(ctx, remoteURL, dir, oids)
+}
+
+// blobsReadByArgs returns the treeish and pathspecs whose blobs are read by
+// the git command with args, if it is a command which serves archives or file
+// contents.
+func blobsReadByArgs(args []string) (treeish string, pathspecs []string, ok bool) {
+	if len(args) == 0 {
+		return "", nil, false
+	}
+
+	switch args[0] {
+	case "archive":
+		// git archive [<options>] <tree-ish> -- [<path>...]
+		for i, arg := range args {
+			if arg == "--" && i > 1 {
+				return args[i-1], args[i+1:], true
+			}
+		}
+	case "show":
+		// git show <commit>:<path>
+		if len(args) == 2 {
+			if i := strings.Index(args[1], ":"); i > 0 {
+				return args[1][:i], []string{":(literal)" + args[1][i+1:]}, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// lsTreePartialClone lists tree entries like git ls-tree --long with args, in
+// partial clones where git ls-tree --long fails because the size of missing
+// blobs is unknown. The size of missing blobs is reported as 0. treeish and
+// path must be the treeish and path of args.
+func lsTreePartialClone(ctx context.Context, dir GitDir, args []string, treeish, path string) ([]protocol.TreeEntry, error) {
+	var shortArgs []string
+	for _, arg := range args {
+		if arg != "--long" {
+			shortArgs = append(shortArgs, arg)
+		}
+	}
+	out, err := runOpGit(ctx, dir, rpcListTree, shortArgs...)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := protocol.ParseLsTree(out.stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathspecs []string
+	if path != "" {
+		pathspecs = []string{":(literal)" + path}
+	}
+	missingOIDs, err := missingBlobs(ctx, dir, treeish, pathspecs, nil)
+	if err != nil {
+		return nil, err
+	}
+	missing := make(map[string]bool, len(missingOIDs))
+	for _, oid := range missingOIDs {
+		missing[oid] = true
+	}
+
+	var present bytes.Buffer
+	for i := range entries {
+		if entries[i].Type != "blob" {
+			continue
+		}
+		entries[i].Size = 0
+		if !missing[entries[i].OID] {
+			present.WriteString(entries[i].OID)
+			present.WriteByte('\n')
+		}
+	}
+	if present.Len() == 0 {
+		return entries, nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch-check=%(objectname) %(objectsize)")
+	dir.Set(cmd)
+	cmd.Stdin = &present
+	cmd.Stdout = &stdout
+	if _, err := runCommand(ctx, cmd); err != nil {
+		return nil, errors.Wrap(err, "git cat-file --batch-check")
+	}
+
+	sizes := map[string]int64{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid `git cat-file --batch-check` size output: %q", line)
+		}
+		sizes[fields[0]] = size
+	}
+	for i := range entries {
+		if entries[i].Type == "blob" {
+			entries[i].Size = sizes[entries[i].OID]
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+func TestBlobsReadByArgs(t *testing.T) {
+	for _, tc := range []struct {
+		args          []string
+		wantTreeish   string
+		wantPathspecs []string
+		wantOK        bool
+	}{{
+		args:        []string{"archive", "--worktree-attributes", "--format=tar", "HEAD", "--"},
+		wantTreeish: "HEAD",
+		wantOK:      true,
+	}, {
+		args:          []string{"archive", "--format=zip", "-0", "abc", "--", "a", "b/c"},
+		wantTreeish:   "abc",
+		wantPathspecs: []string{"a", "b/c"},
+		wantOK:        true,
+	}, {
+		args:          []string{"show", "abc:dir/file.txt"},
+		wantTreeish:   "abc",
+		wantPathspecs: []string{":(literal)dir/file.txt"},
+		wantOK:        true,
+	}, {
+		args: []string{"show", "abc"},
+	}, {
+		args: []string{"log", "--", "a"},
+	}} {
+		treeish, pathspecs, ok := blobsReadByArgs(tc.args)
+		if treeish != tc.wantTreeish || !cmp.Equal(pathspecs, tc.wantPathspecs) || ok != tc.wantOK {
+			t.Errorf("blobsReadByArgs(%q) = (%q, %q, %v), want (%q, %q, %v)", tc.args, treeish, pathspecs, ok, tc.wantTreeish, tc.wantPathspecs, tc.wantOK)
+		}
+	}
+}
+
+func TestPartialClone(t *testing.T) {
+	ctx := context.Background()
+
+	large := strings.Repeat("large file\n", 100)
+	remote := t.TempDir()
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remote, name, arg...)
+	}
+	cmd("git", "init", ".")
+	cmd("git", "config", "uploadpack.allowFilter", "true")
+	cmd("git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	for path, content := range map[string]string{
+		"small.txt":      "small file\n",
+		"large.txt":      large,
+		"vendor/big.bin": large + "binary\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(remote, path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(remote, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd("git", "add", ".")
+	cmd("git", "commit", "-m", "partial")
+	commit := api.CommitID(strings.TrimSpace(cmd("git", "rev-parse", "HEAD")))
+
+	remoteURL, err := vcs.ParseURL(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/foo")
+	dir := GitDir(filepath.Join(reposDir, string(repo), ".git"))
+
+	syncer := &GitRepoSyncer{PartialClone: &PartialCloneOptions{
+		BlobSizeLimit: 100,
+		ExcludePaths:  []string{"vendor"},
+	}}
+	cloneCmd, err := syncer.CloneCommand(ctx, remoteURL, string(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output, err := runWithRemoteOpts(ctx, cloneCmd, nil); err != nil {
+		t.Fatalf("clone failed: %s\n%s", err, output)
+	}
+	if err := setGitAttributes(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isPartialClone(dir) {
+		t.Fatal("repository is not a partial clone")
+	}
+	missing, err := missingBlobs(ctx, dir, string(commit), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 2 {
+		t.Fatalf("got %d missing blobs, want 2", len(missing))
+	}
+
+	s := &Server{
+		ReposDir:          reposDir,
+		skipCloneForTests: true,
+		GetRemoteURLFunc:  staticGetRemoteURL(remote),
+	}
+	h := s.Handler()
+
+	t.Run("read-blob", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"small.txt": "small file\n",
+			"large.txt": large,
+		} {
+			body, err := json.Marshal(&protocol.ReadBlobRequest{Repo: repo, Commit: commit, Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", "/read-blob", bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: unexpected status %d: %s", path, w.Code, w.Body)
+			}
+			var resp protocol.ReadBlobResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if string(resp.Content) != want {
+				t.Errorf("%s: got content %q, want %q", path, resp.Content, want)
+			}
+		}
+
+		// The blobs of excluded paths are never fetched.
+		body, err := json.Marshal(&protocol.ReadBlobRequest{Repo: repo, Commit: commit, Path: "vendor/big.bin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/read-blob", bytes.NewReader(body)))
+		var rpcErr protocol.RPCError
+		if err := json.NewDecoder(w.Body).Decode(&rpcErr); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusUnprocessableEntity || rpcErr.Kind != protocol.RPCErrorBlobUnavailable {
+			t.Errorf("got status %d and error %v, want %s", w.Code, &rpcErr, protocol.RPCErrorBlobUnavailable)
+		}
+	})
+
+	t.Run("list-tree", func(t *testing.T) {
+		body, err := json.Marshal(&protocol.ListTreeRequest{Repo: repo, Commit: commit, Recursive: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/list-tree", bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+		}
+		var resp protocol.ListTreeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]int64{}
+		for _, e := range resp.Entries {
+			got[e.Path] = e.Size
+		}
+		want := map[string]int64{
+			"large.txt":      int64(len(large)),
+			"small.txt":      int64(len("small file\n")),
+			"vendor":         -1,
+			"vendor/big.bin": 0, // missing
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected entries (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/archive?repo=example.com/foo&format=tar&treeish="+string(commit), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+		}
+		if status := w.Header().Get("X-Exec-Exit-Status"); status != "0" {
+			t.Fatalf("git archive failed with status %s: %s", status, w.Header().Get("X-Exec-Stderr"))
+		}
+
+		var got []string
+		tr := tar.NewReader(w.Body)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hdr.Typeflag == tar.TypeXGlobalHeader {
+				continue
+			}
+			got = append(got, hdr.Name)
+		}
+		sort.Strings(got)
+		if diff := cmp.Diff([]string{"large.txt", "small.txt"}, got); diff != "" {
+			t.Errorf("unexpected archive entries (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(remote, "large2.txt"), []byte(large+"2\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd("git", "add", ".")
+		cmd("git", "commit", "-m", "large2")
+		head := strings.TrimSpace(cmd("git", "rev-parse", "HEAD"))
+
+		if err := syncer.Fetch(ctx, remoteURL, dir); err != nil {
+			t.Fatal(err)
+		}
+		missing, err := missingBlobs(ctx, dir, head, []string{"large2.txt"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(missing) != 1 {
+			t.Errorf("got %d missing blobs, want the blob of large2.txt to be missing", len(missing))
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+var rebalanceCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_rebalance_total",
+	Help: "Incremented each time a repository is moved to or removed from this gitserver by rebalancing.",
+}, []string{"type"})
+
+// rebalanceSource returns the shard which served the given repository before it
+// was assigned to this gitserver, along with the URL of the repository on that
+// shard, if the repository should be fetched from that shard rather than cloned
+// from its code host.
+func (s *Server) rebalanceSource(ctx context.Context, repo api.RepoName) (string, *vcs.URL, bool) {
+	if !s.Rebalance || s.DB == nil || repoCloned(s.dir(repo)) {
+		return "", nil, false
+	}
+
+	gr, err := database.GitserverRepos(s.DB).GetByName(ctx, repo)
+	if err != nil {
+		// No shard has recorded the repository yet
+		return "", nil, false
+	}
+
+	// Resume an interrupted move, or start a new one if the repository is cloned
+	// on another shard.
+	shardID := gr.RebalancingFrom
+	if shardID == "" && gr.CloneStatus == types.CloneStatusCloned {
+		shardID = gr.ShardID
+	}
+	if shardID == "" || shardID == s.Hostname {
+		return "", nil, false
+	}
+
+	addr, ok := gitserver.AddrForShard(shardID, conf.Get().ServiceConnections.GitServers)
+	if !ok {
+		// The previous shard has been removed, so we can only clone from the code host
+		return "", nil, false
+	}
+
+	remoteURL, err := vcs.ParseURL("http://" + addr + "/git/" + string(protocol.NormalizeRepo(repo)))
+	if err != nil {
+		log15.Warn("Failed to build rebalancing URL", "repo", repo, "shard", shardID, "error", err)
+		return "", nil, false
+	}
+
+	return shardID, remoteURL, true
+}
+
+// rebalanceRepo starts moving the given repository, which is owned by this
+// gitserver but cloned on another shard, to this gitserver. It reports whether a
+// move was started.
+func (s *Server) rebalanceRepo(ctx context.Context, repo types.RepoGitserverStatus) bool {
+	if repo.GitserverRepo == nil {
+		return false
+	}
+	if repo.RebalancingFrom == "" && (repo.ShardID == s.Hostname || repo.CloneStatus != types.CloneStatusCloned) {
+		return false
+	}
+
+	dir := s.dir(repo.Name)
+	if repoCloned(dir) {
+		if repo.RebalancingFrom == "" {
+			return false
+		}
+
+		// The move completed but we failed to record it
+		if err := database.GitserverRepos(s.DB).FinishRebalancing(ctx, repo.Name, s.Hostname); err != nil {
+			log15.Warn("Failed to finish rebalancing repo", "repo", repo.Name, "error", err)
+		}
+		return true
+	}
+	if _, cloning := s.locker.Status(dir); cloning {
+		return true
+	}
+
+	if _, err := s.cloneRepo(ctx, repo.Name, nil); err != nil {
+		log15.Warn("Failed to start rebalancing repo", "repo", repo.Name, "error", err)
+		return false
+	}
+
+	return true
+}
+
+// removeRebalancedRepo removes the local copy of the given repository, which is
+// not owned by this gitserver, once another shard has finished moving it away from
+// this gitserver.
+func (s *Server) removeRebalancedRepo(repo types.RepoGitserverStatus) {
+	if repo.GitserverRepo == nil || repo.RebalancingFrom != "" || repo.ShardID == "" || repo.ShardID == s.Hostname || repo.CloneStatus != types.CloneStatusCloned {
+		return
+	}
+
+	dir := s.dir(repo.Name)
+	if !repoCloned(dir) || isReplica(dir) {
+		return
+	}
+
+	// We do not use removeRepoDirectory as it records the repository as not cloned,
+	// which would overwrite the state recorded by the shard now serving it.
+	tmp, err := s.tempDir("rebalanced-repo")
+	if err != nil {
+		log15.Warn("Failed to remove rebalanced repo", "repo", repo.Name, "error", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := renameAndSync(string(dir), filepath.Join(tmp, "repo")); err != nil {
+		log15.Warn("Failed to remove rebalanced repo", "repo", repo.Name, "error", err)
+		return
+	}
+
+	log15.Info("removed rebalanced repo", "repo", repo.Name, "shard", repo.ShardID)
+	rebalanceCounter.WithLabelValues("removed").Inc()
+}
+
+// rebalanceSyncer fetches a Git copy of a repository from the gitserver which
+// served it before it was moved to this gitserver. The repository keeps the type of
+// the syncer of its code host, which is used for all subsequent updates.
+type rebalanceSyncer struct {
+	VCSSyncer
+	git GitRepoSyncer
+}
+
+// newRebalanceSyncer returns the rebalanceSyncer of a repository synced by
+// syncer. Partial clones are fetched from the other gitserver without blobs, as
+// git can't serve fetches filtered by blob size from partial clones. Their
+// blobs are fetched from the code host when they are read.
+func newRebalanceSyncer(syncer VCSSyncer) *rebalanceSyncer {
+	s := &rebalanceSyncer{VCSSyncer: syncer}
+	if gs, ok := syncer.(*GitRepoSyncer); ok && gs.PartialClone != nil {
+		s.git.PartialClone = &PartialCloneOptions{ExcludePaths: gs.PartialClone.ExcludePaths}
+	}
+	return s
+}
+
+func (s *rebalanceSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	return s.git.IsCloneable(ctx, remoteURL)
+}
+
+func (s *rebalanceSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	return s.git.CloneCommand(ctx, remoteURL, tmpPath)
+}
+
+func (s *rebalanceSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	return s.git.RemoteShowCommand(ctx, remoteURL)
+}
@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+const (
+	// gitConfigReplicatedAt is a key we add to git config to mark a repository as
+	// a replica of a hot repository owned by another gitserver. Its value is the
+	// time the replica was last fetched from the primary gitserver.
+	gitConfigReplicatedAt = "sourcegraph.replicatedAt"
+
+	// replicaTTL is how long a replica is kept after it was last fetched from
+	// the primary gitserver.
+	replicaTTL = 24 * time.Hour
+)
+
+var replicaCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_replicas_total",
+	Help: "Incremented each time a replica of a hot repository is created, updated or removed on this gitserver.",
+}, []string{"type"})
+
+// observeRead records a read request for the given repository, which this
+// gitserver is expected to own, and reports whether the repository is hot. The
+// replicas of a hot repository are asked to fetch it if they have not done so
+// recently. Partial clones are never reported as hot, as replicas are full
+// clones, which can't be fetched from partial clones.
+func (s *Server) observeRead(repo api.RepoName) bool {
+	now := time.Now()
+	if !s.hotRepos.Observe(repo, now) || isPartialClone(s.dir(repo)) {
+		return false
+	}
+
+	if s.hotRepos.ShouldReplicate(repo, now) {
+		go s.replicateRepo(repo)
+	}
+	return true
+}
+
+// replicaExecResponseWriter is the http.ResponseWriter of exec requests sent
+// to a replica. It tracks whether the response has been started, so that the
+// replica can still respond with a 404 if the command fails before writing any
+// output.
+type replicaExecResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *replicaExecResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *replicaExecResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// serveNotFound responds with the 404 of a replica which can't serve a request.
+// The response must not have been started.
+func (w *replicaExecResponseWriter) serveNotFound() {
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+}
+
+// isRevisionNotFoundStderr returns true if the stderr of a failed git command
+// reports that a revision or object it was given doesn't exist.
+func isRevisionNotFoundStderr(stderr string) bool {
+	for _, msg := range []string{
+		"unknown revision",
+		"bad revision",
+		"bad object",
+		"invalid object name",
+		"not a valid object name",
+		"Not a valid object name",
+		"not a tree object",
+	} {
+		if strings.Contains(stderr, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicateRepo asks the replicas of the given hot repository to fetch it from
+// this gitserver, if this gitserver is the primary gitserver of the repository.
+func (s *Server) replicateRepo(repo api.RepoName) {
+	addrs := gitserver.ReplicaAddrsForRepo(repo, conf.Get().ServiceConnections.GitServers, conf.GitServerReplicas().Replicas)
+	if len(addrs) < 2 || !s.hostnameMatch(addrs[0]) || isPartialClone(s.dir(repo)) {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	for _, addr := range addrs[1:] {
+		if err := gitserver.DefaultClient.ReplicateRepo(ctx, repo, addr, addrs[0]); err != nil {
+			log15.Warn("Failed to replicate hot repo", "repo", repo, "replica", addr, "error", err)
+		}
+	}
+}
+
+func (s *Server) handleReplicate(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ReplicateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 🚨 SECURITY: Only fetch from gitservers, never from arbitrary hosts.
+	var found bool
+	for _, addr := range conf.Get().ServiceConnections.GitServers {
+		if addr == req.Primary {
+			found = true
+			break
+		}
+	}
+	if !found || s.hostnameMatch(req.Primary) {
+		http.Error(w, "primary is not another gitserver", http.StatusBadRequest)
+		return
+	}
+
+	repo := protocol.NormalizeRepo(req.Repo)
+	go func() {
+		ctx, cancel := s.serverContext()
+		defer cancel()
+
+		if err := s.syncReplica(ctx, repo, req.Primary); err != nil {
+			log15.Warn("Failed to sync replica of hot repo", "repo", repo, "primary", req.Primary, "error", err)
+		}
+	}()
+}
+
+// syncReplica creates or updates the replica of the given hot repository by
+// fetching it from its primary gitserver. Replicas are never recorded in the
+// database, as the primary gitserver is responsible for the repository.
+func (s *Server) syncReplica(ctx context.Context, repo api.RepoName, primary string) error {
+	dir := s.dir(repo)
+	if repoCloned(dir) && !isReplica(dir) {
+		// This gitserver owned the repository before, so the janitor will remove it
+		// once it is no longer needed.
+		return nil
+	}
+
+	lock, ok := s.locker.TryAcquire(dir, "replicating")
+	if !ok {
+		// The replica is already being updated
+		return nil
+	}
+	defer lock.Release()
+
+	ctx, cancel := context.WithTimeout(ctx, longGitCommandTimeout)
+	defer cancel()
+
+	remoteURL, err := vcs.ParseURL("http://" + primary + "/git/" + string(repo))
+	if err != nil {
+		return err
+	}
+	syncer := &GitRepoSyncer{}
+
+	if repoCloned(dir) {
+		if err := syncer.Fetch(ctx, remoteURL, dir); err != nil {
+			return err
+		}
+		removeBadRefs(ctx, dir)
+		replicaCounter.WithLabelValues("updated").Inc()
+		return setReplicatedAt(dir, time.Now())
+	}
+
+	tmpPath, err := s.tempDir("replica-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpPath)
+	tmpPath = filepath.Join(tmpPath, ".git")
+	tmp := GitDir(tmpPath)
+
+	cmd, err := syncer.CloneCommand(ctx, remoteURL, tmpPath)
+	if err != nil {
+		return errors.Wrap(err, "get clone command")
+	}
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go readCloneProgress(newURLRedactor(remoteURL), lock, pr)
+
+	if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
+		return errors.Wrapf(err, "replica clone failed. Output: %s", string(output))
+	}
+
+	removeBadRefs(ctx, tmp)
+
+	if err := setHEAD(ctx, tmp, syncer, repo, remoteURL); err != nil {
+		return errors.Wrap(err, "failed to ensure HEAD exists")
+	}
+	if err := setGitAttributes(tmp); err != nil {
+		return err
+	}
+	if err := setReplicatedAt(tmp, time.Now()); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		return err
+	}
+	if err := renameAndSync(tmpPath, string(dir)); err != nil {
+		return err
+	}
+
+	log15.Info("repo replicated", "repo", repo, "primary", primary)
+	replicaCounter.WithLabelValues("created").Inc()
+	return nil
+}
+
+// setReplicatedAt records the time a replica was last fetched from the primary
+// gitserver.
+func setReplicatedAt(dir GitDir, now time.Time) error {
+	return gitConfigSet(dir, gitConfigReplicatedAt, strconv.FormatInt(now.Unix(), 10))
+}
+
+// getReplicatedAt returns the time a replica was last fetched from the primary
+// gitserver, and false if the repository is not a replica.
+func getReplicatedAt(dir GitDir) (time.Time, bool) {
+	value, err := gitConfigGet(dir, gitConfigReplicatedAt)
+	if err != nil || value == "" {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 0)
+	if err != nil {
+		return time.Time{}, true
+	}
+	return time.Unix(sec, 0), true
+}
+
+// isReplica returns true if the repository at dir is a replica of a hot
+// repository owned by another gitserver.
+func isReplica(dir GitDir) bool {
+	_, ok := getReplicatedAt(dir)
+	return ok
+}
+
+// removeStaleReplica removes the repository at dir if it is a replica which has
+// not been fetched from its primary gitserver for a while, as the repository is
+// then no longer hot. It reports whether dir is a replica.
+func (s *Server) removeStaleReplica(dir GitDir) (bool, error) {
+	replicatedAt, ok := getReplicatedAt(dir)
+	if !ok {
+		return false, nil
+	}
+	if time.Since(replicatedAt) < replicaTTL {
+		return true, nil
+	}
+
+	log15.Info("removing stale replica", "repo", s.name(dir), "replicatedAt", replicatedAt)
+	if err := s.removeRepoDirectory(dir); err != nil {
+		return true, err
+	}
+	replicaCounter.WithLabelValues("removed").Inc()
+	return true, nil
+}
@@ -0,0 +1,568 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// rpcCacheSize is the number of responses to typed requests which are cached.
+const rpcCacheSize = 1000
+
+// rpcMaxCachedResponseSize is the size in bytes of the largest encoded response
+// to a typed request which is cached.
+const rpcMaxCachedResponseSize = 256 * 1024
+
+// rpcOp is an operation served by a typed gitserver endpoint.
+type rpcOp struct {
+	// name identifies the operation in metrics and traces.
+	name string
+	// gitCmd is the git subcommand run by the operation. It determines the
+	// timeout of the operation.
+	gitCmd string
+	// maxOutput is the maximum number of bytes the git command may write to
+	// stdout. Operations which produce more output fail with an
+	// RPCErrorLimitExceeded error instead of being parsed.
+	maxOutput int
+}
+
+var (
+	rpcResolveRevision = rpcOp{name: "resolve-revision", gitCmd: "rev-parse", maxOutput: 4096}
+	rpcListRefs        = rpcOp{name: "list-refs", gitCmd: "show-ref", maxOutput: 64 << 20}
+	rpcReadBlob        = rpcOp{name: "read-blob", gitCmd: "show", maxOutput: 100 << 20}
+	rpcListTree        = rpcOp{name: "list-tree", gitCmd: "ls-tree", maxOutput: 256 << 20}
+	rpcLog             = rpcOp{name: "log", gitCmd: "log", maxOutput: 256 << 20}
+	rpcDiff            = rpcOp{name: "diff", gitCmd: "diff", maxOutput: 256 << 20}
+	rpcBlame           = rpcOp{name: "blame", gitCmd: "blame", maxOutput: 64 << 20}
+)
+
+var (
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_rpc_duration_seconds",
+		Help:    "Typed gitserver request latencies in seconds.",
+		Buckets: trace.UserLatencyBuckets,
+	}, []string{"op", "status"})
+	rpcCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_rpc_cache_hits_total",
+		Help: "Number of typed gitserver requests served from the cache.",
+	}, []string{"op"})
+)
+
+// rpcCall is a typed request served by serveRPC.
+type rpcCall struct {
+	op   rpcOp
+	repo api.RepoName
+
+	// req is the decoded request. The requests of cacheable calls are used as
+	// their cache keys.
+	req interface{}
+
+	// revision is the revision read by the request. Replicas which don't have
+	// it respond with a 404, so that the client retries the request on the
+	// primary gitserver of the repository.
+	revision string
+	// ensureRevision is whether revision should be fetched from the remote if
+	// it doesn't exist locally.
+	ensureRevision bool
+
+	// cacheable is whether the response only depends on the request, which is
+	// the case if the request only refers to absolute commits.
+	cacheable bool
+	// skipCache, if set, is called after run to decide whether the response of
+	// a cacheable call must not be cached after all.
+	skipCache func() bool
+
+	// run runs the operation in the repository and returns the response.
+	run func(ctx context.Context, dir GitDir) (interface{}, error)
+}
+
+// serveRPC serves a typed request. Requests for repositories which are not
+// cloned are responded to like exec requests, errors returned by call.run are
+// responded to with a protocol.RPCError.
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request, call rpcCall) {
+	ctx, cancel := context.WithTimeout(r.Context(), shortGitCommandTimeout([]string{call.op.gitCmd}))
+	defer cancel()
+
+	start := time.Now()
+	status := "unknown"
+	var rpcErr error
+
+	repo := protocol.NormalizeRepo(call.repo)
+
+	var tr *trace.Trace
+	tr, ctx = trace.New(ctx, "rpc."+call.op.name, string(repo))
+	defer func() {
+		tr.LogFields(otlog.String("status", status))
+		tr.SetError(rpcErr)
+		tr.Finish()
+
+		rpcDuration.WithLabelValues(call.op.name, status).Observe(time.Since(start).Seconds())
+	}()
+
+	// Requests for hot repositories may be sent to a replica of the repository
+	// rather than to this gitserver, so we only observe requests sent here.
+	replicaRead := r.Header.Get(protocol.ReplicaReadHeader) != ""
+	if !replicaRead && s.observeRead(repo) {
+		w.Header().Set(protocol.HotRepoHeader, "true")
+	}
+
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		status = s.serveRepoNotCloned(ctx, w, repo, dir, replicaRead)
+		return
+	}
+
+	// Cached responses outlive the repository, so we only serve them once we
+	// know that the repository is still cloned.
+	var cacheKey string
+	if call.cacheable && s.rpcCache != nil {
+		if key, err := json.Marshal(call.req); err == nil {
+			cacheKey = call.op.name + ":" + string(key)
+		}
+	}
+	if cacheKey != "" {
+		if body, ok := s.rpcCache.Get(cacheKey); ok {
+			status = "cached"
+			rpcCacheHits.WithLabelValues(call.op.name).Inc()
+			writeRPCResponse(w, body.([]byte))
+			return
+		}
+	}
+
+	if replicaRead {
+		// Replicas are only fetched from the primary gitserver, which fetches the
+		// revision if it is missing.
+		if !revisionExists(dir, call.revision) {
+			status = "replica-revision-not-found"
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+			return
+		}
+	} else if call.ensureRevision && !conf.Get().DisableAutoGitUpdates {
+		// ensureRevision may kick off a git fetch operation which we don't want if we've
+		// configured DisableAutoGitUpdates.
+		s.ensureRevision(ctx, repo, call.revision, dir)
+	}
+
+	resp, err := call.run(ctx, dir)
+	if err != nil {
+		rpcErr = err
+
+		var e *protocol.RPCError
+		if !errors.As(err, &e) {
+			e = &protocol.RPCError{Message: err.Error()}
+		}
+		status = "error"
+		if e.Kind != "" {
+			status = e.Kind
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(e)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		rpcErr = err
+		status = "error"
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status = "ok"
+	if cacheKey != "" && len(body) <= rpcMaxCachedResponseSize && (call.skipCache == nil || !call.skipCache()) {
+		s.rpcCache.Add(cacheKey, body)
+	}
+	writeRPCResponse(w, body)
+}
+
+func writeRPCResponse(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// decodeRPCRequest decodes the typed request in the body of r into req. It
+// responds with a 400 and returns false if the request is invalid.
+func decodeRPCRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func invalidRPCRequest(err error) error {
+	return &protocol.RPCError{Kind: protocol.RPCErrorInvalidRequest, Message: err.Error()}
+}
+
+// gitOutput is the output of a git command run for a typed request.
+type gitOutput struct {
+	stdout []byte
+	// stderr is the first 1024 bytes written to stderr.
+	stderr     string
+	exitStatus int
+	// truncated is whether stdout was truncated to the output limit.
+	truncated bool
+}
+
+// runGit runs git with the given arguments in dir. Only the first limit bytes
+// written to stdout are kept. If git fails, the returned output is non-nil so
+// that its stderr can be inspected.
+func runGit(ctx context.Context, dir GitDir, limit int, args ...string) (*gitOutput, error) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	stdoutW := &writeCounter{w: &limitWriter{W: &stdoutBuf, N: limit}}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	cmd.Stdout = stdoutW
+	cmd.Stderr = &limitWriter{W: &stderrBuf, N: 1024}
+
+	exitStatus, err := runCommand(ctx, cmd)
+	out := &gitOutput{
+		stdout:     stdoutBuf.Bytes(),
+		stderr:     strings.TrimSpace(stderrBuf.String()),
+		exitStatus: exitStatus,
+		truncated:  stdoutW.n > int64(limit),
+	}
+	if err != nil {
+		return out, errors.WithMessage(err, fmt.Sprintf("git command %v failed (stderr: %q)", args, out.stderr))
+	}
+	return out, nil
+}
+
+// runOpGit is like runGit, but returns an RPCErrorLimitExceeded error if the
+// output exceeds the limit of op.
+func runOpGit(ctx context.Context, dir GitDir, op rpcOp, args ...string) (*gitOutput, error) {
+	out, err := runGit(ctx, dir, op.maxOutput, args...)
+	if err != nil {
+		return out, err
+	}
+	if out.truncated {
+		return out, &protocol.RPCError{
+			Kind:    protocol.RPCErrorLimitExceeded,
+			Message: fmt.Sprintf("output of %s exceeds %d bytes", op.name, op.maxOutput),
+		}
+	}
+	return out, nil
+}
+
+// isAbsoluteRange returns true if spec is an absolute commit, or a range of
+// absolute commits.
+func isAbsoluteRange(spec string) bool {
+	for _, rev := range strings.Split(strings.Replace(spec, "...", "..", 1), "..") {
+		if !isAbsoluteRevision(rev) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) handleResolveRevision(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ResolveRevisionRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+	if req.Spec == "" {
+		req.Spec = "HEAD"
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:             rpcResolveRevision,
+		repo:           req.Repo,
+		req:            &req,
+		revision:       req.Spec,
+		ensureRevision: req.EnsureRevision,
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if err := checkSpecArgSafety(req.Spec); err != nil {
+				return nil, invalidRPCRequest(err)
+			}
+
+			// Like exec, resolve HEAD without running git if possible, as HEAD is
+			// resolved for every repository searched.
+			if req.Spec == "HEAD" {
+				if resolved, err := quickRevParseHead(dir); err == nil && isAbsoluteRevision(resolved) {
+					return &protocol.ResolveRevisionResponse{CommitID: api.CommitID(resolved)}, nil
+				}
+			}
+
+			out, err := runOpGit(ctx, dir, rpcResolveRevision, req.GitArgs()...)
+			if err != nil {
+				if out != nil && strings.Contains(out.stderr, "unknown revision") {
+					return nil, &protocol.RPCError{Kind: protocol.RPCErrorRevisionNotFound, Message: out.stderr}
+				}
+				return nil, err
+			}
+			return &protocol.ResolveRevisionResponse{CommitID: api.CommitID(bytes.TrimSpace(out.stdout))}, nil
+		},
+	})
+}
+
+func (s *Server) handleListRefs(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ListRefsRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:   rpcListRefs,
+		repo: req.Repo,
+		req:  &req,
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			out, err := runOpGit(ctx, dir, rpcListRefs, req.GitArgs()...)
+			if err != nil {
+				// Exit status of 1 and no output means there were no
+				// results. This is not a fatal error.
+				if out != nil && out.exitStatus == 1 && len(out.stdout) == 0 {
+					return &protocol.ListRefsResponse{}, nil
+				}
+				return nil, err
+			}
+
+			refs, err := protocol.ParseShowRef(out.stdout)
+			if err != nil {
+				return nil, err
+			}
+			return &protocol.ListRefsResponse{Refs: refs}, nil
+		},
+	})
+}
+
+func (s *Server) handleReadBlob(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ReadBlobRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	// unresolvedLFSPointer is set if the blob is a pointer to a Git LFS object
+	// which hasn't been fetched (yet).
+	var unresolvedLFSPointer bool
+
+	s.serveRPC(w, r, rpcCall{
+		op:        rpcReadBlob,
+		repo:      req.Repo,
+		req:       &req,
+		revision:  string(req.Commit),
+		cacheable: true,
+		skipCache: func() bool { return unresolvedLFSPointer },
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if !isAbsoluteRevision(string(req.Commit)) {
+				return nil, invalidRPCRequest(errors.Errorf("non-absolute commit ID: %q", req.Commit))
+			}
+
+			limit := rpcReadBlob.maxOutput
+			truncate := req.MaxBytes > 0 && req.MaxBytes <= int64(limit)
+			if truncate {
+				limit = int(req.MaxBytes)
+			}
+
+			// Read enough of the blob to tell whether it is a Git LFS pointer.
+			readLimit := limit
+			if readLimit < lfsMaxPointerSize {
+				readLimit = lfsMaxPointerSize
+			}
+
+			// Partial clones may not have the blob yet.
+			if err := s.fetchMissingBlobs(ctx, protocol.NormalizeRepo(req.Repo), dir, string(req.Commit), []string{":(literal)" + req.Path}); err != nil {
+				log15.Warn("Failed to fetch missing blobs of partial clone", "repo", req.Repo, "error", err)
+			}
+
+			out, err := runGit(ctx, dir, readLimit, req.GitArgs()...)
+			if err != nil {
+				if out != nil && (strings.Contains(out.stderr, "exists on disk, but not in") || strings.Contains(out.stderr, "does not exist")) {
+					return nil, &protocol.RPCError{Kind: protocol.RPCErrorPathNotFound, Message: out.stderr}
+				}
+				if out != nil && isMissingBlobError(out.stderr) {
+					return nil, &protocol.RPCError{Kind: protocol.RPCErrorBlobUnavailable, Message: out.stderr}
+				}
+				return nil, err
+			}
+
+			content, truncated := out.stdout, out.truncated
+			if !truncated {
+				// Serve the content of files stored in Git LFS rather than their
+				// pointers, if their objects have been fetched.
+				f, p := openLFSObject(dir, content)
+				if f != nil {
+					content, err = io.ReadAll(io.LimitReader(f, int64(limit)+1))
+					f.Close()
+					if err != nil {
+						return nil, err
+					}
+				} else if p.oid != "" {
+					unresolvedLFSPointer = true
+				}
+			}
+			if len(content) > limit {
+				content, truncated = content[:limit], true
+			}
+
+			if truncated && !truncate {
+				return nil, &protocol.RPCError{
+					Kind:    protocol.RPCErrorLimitExceeded,
+					Message: fmt.Sprintf("file %q is larger than %d bytes", req.Path, limit),
+				}
+			}
+			return &protocol.ReadBlobResponse{Content: content, Truncated: truncated}, nil
+		},
+	})
+}
+
+func (s *Server) handleListTree(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ListTreeRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:        rpcListTree,
+		repo:      req.Repo,
+		req:       &req,
+		revision:  string(req.Commit),
+		cacheable: true,
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if !isAbsoluteRevision(string(req.Commit)) {
+				return nil, invalidRPCRequest(errors.Errorf("non-absolute commit ID: %q", req.Commit))
+			}
+			if err := checkSpecArgSafety(req.Path); err != nil {
+				return nil, invalidRPCRequest(err)
+			}
+
+			args := req.GitArgs()
+			out, err := runOpGit(ctx, dir, rpcListTree, args...)
+			if err != nil {
+				if out != nil && strings.Contains(out.stderr, "exists on disk, but not in") {
+					return nil, &protocol.RPCError{Kind: protocol.RPCErrorPathNotFound, Message: out.stderr}
+				}
+				if out != nil && isMissingBlobError(out.stderr) {
+					entries, err := lsTreePartialClone(ctx, dir, args, string(req.Commit), req.Path)
+					if err != nil {
+						return nil, err
+					}
+					return &protocol.ListTreeResponse{Entries: entries}, nil
+				}
+				return nil, err
+			}
+
+			entries, err := protocol.ParseLsTree(out.stdout)
+			if err != nil {
+				return nil, err
+			}
+			return &protocol.ListTreeResponse{Entries: entries}, nil
+		},
+	})
+}
+
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	var req protocol.LogRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:             rpcLog,
+		repo:           req.Repo,
+		req:            &req,
+		revision:       req.Range,
+		ensureRevision: req.EnsureRevision,
+		// Dates may be relative to now, such as "1 week ago".
+		cacheable: isAbsoluteRevision(req.Range) && req.After == "" && req.Before == "",
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if err := checkSpecArgSafety(req.Range); err != nil {
+				return nil, invalidRPCRequest(err)
+			}
+
+			out, err := runOpGit(ctx, dir, rpcLog, req.GitArgs()...)
+			if err != nil {
+				if out != nil && out.stderr == "fatal: bad object "+req.Range {
+					return nil, &protocol.RPCError{Kind: protocol.RPCErrorRevisionNotFound, Message: out.stderr}
+				}
+				return nil, err
+			}
+
+			commits, err := protocol.ParseLog(out.stdout)
+			if err != nil {
+				return nil, err
+			}
+			return &protocol.LogResponse{Commits: commits}, nil
+		},
+	})
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	var req protocol.DiffRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:        rpcDiff,
+		repo:      req.Repo,
+		req:       &req,
+		revision:  req.Range,
+		cacheable: isAbsoluteRange(req.Range),
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if strings.HasPrefix(req.Range, "-") || strings.HasPrefix(req.Range, ".") {
+				// We don't want to allow user input to add `git diff` command line
+				// flags or refer to a file.
+				return nil, invalidRPCRequest(errors.Errorf("invalid diff range argument: %q", req.Range))
+			}
+
+			out, err := runOpGit(ctx, dir, rpcDiff, req.GitArgs()...)
+			if err != nil {
+				return nil, err
+			}
+			return &protocol.DiffResponse{Patch: out.stdout}, nil
+		},
+	})
+}
+
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	var req protocol.BlameRequest
+	if !decodeRPCRequest(w, r, &req) {
+		return
+	}
+
+	s.serveRPC(w, r, rpcCall{
+		op:        rpcBlame,
+		repo:      req.Repo,
+		req:       &req,
+		revision:  string(req.Commit),
+		cacheable: isAbsoluteRevision(string(req.Commit)),
+		run: func(ctx context.Context, dir GitDir) (interface{}, error) {
+			if err := checkSpecArgSafety(string(req.Commit)); err != nil {
+				return nil, invalidRPCRequest(err)
+			}
+
+			out, err := runOpGit(ctx, dir, rpcBlame, req.GitArgs()...)
+			if err != nil {
+				return nil, err
+			}
+
+			hunks, err := protocol.ParseBlamePorcelain(out.stdout)
+			if err != nil {
+				return nil, err
+			}
+			return &protocol.BlameResponse{Hunks: hunks}, nil
+		},
+	})
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestRPC(t *testing.T) {
+	reposDir := t.TempDir()
+	repoDir := filepath.Join(reposDir, "example.com", "foo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, repoDir, name, arg...)
+	}
+	commit := api.CommitID(strings.TrimSpace(makeSingleCommitRepo(cmd)))
+	repo := api.RepoName("example.com/foo")
+
+	s := &Server{
+		ReposDir:          reposDir,
+		skipCloneForTests: true,
+	}
+	h := s.Handler()
+
+	// do sends req to the typed endpoint op, and decodes the response into
+	// resp if the request succeeded.
+	do := func(t *testing.T, op string, req, resp interface{}) (int, *protocol.RPCError) {
+		t.Helper()
+
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/"+op, bytes.NewReader(body)))
+
+		switch w.Code {
+		case http.StatusOK:
+			if err := json.NewDecoder(w.Body).Decode(resp); err != nil {
+				t.Fatal(err)
+			}
+		case http.StatusUnprocessableEntity:
+			var rpcErr protocol.RPCError
+			if err := json.NewDecoder(w.Body).Decode(&rpcErr); err != nil {
+				t.Fatal(err)
+			}
+			return w.Code, &rpcErr
+		}
+		return w.Code, nil
+	}
+
+	t.Run("resolve-revision", func(t *testing.T) {
+		var resp protocol.ResolveRevisionResponse
+		if code, _ := do(t, "resolve-revision", &protocol.ResolveRevisionRequest{Repo: repo, Spec: "HEAD"}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if resp.CommitID != commit {
+			t.Errorf("got commit %q, want %q", resp.CommitID, commit)
+		}
+
+		_, rpcErr := do(t, "resolve-revision", &protocol.ResolveRevisionRequest{Repo: repo, Spec: "doesnotexist^0"}, &resp)
+		if rpcErr == nil || rpcErr.Kind != protocol.RPCErrorRevisionNotFound {
+			t.Errorf("got error %v, want %s", rpcErr, protocol.RPCErrorRevisionNotFound)
+		}
+	})
+
+	t.Run("list-refs", func(t *testing.T) {
+		var resp protocol.ListRefsResponse
+		if code, _ := do(t, "list-refs", &protocol.ListRefsRequest{Repo: repo, HeadsOnly: true}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if len(resp.Refs) != 1 || !strings.HasPrefix(resp.Refs[0].Name, "refs/heads/") || resp.Refs[0].CommitID != commit {
+			t.Errorf("unexpected refs %+v", resp.Refs)
+		}
+
+		if code, _ := do(t, "list-refs", &protocol.ListRefsRequest{Repo: repo, TagsOnly: true}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if len(resp.Refs) != 0 {
+			t.Errorf("unexpected tags %+v", resp.Refs)
+		}
+	})
+
+	t.Run("read-blob", func(t *testing.T) {
+		for _, tc := range []struct {
+			maxBytes      int64
+			wantContent   string
+			wantTruncated bool
+		}{
+			{maxBytes: 0, wantContent: "hello world\n"},
+			{maxBytes: 5, wantContent: "hello", wantTruncated: true},
+			// Reading the cached response must not be affected by the
+			// request with a limit.
+			{maxBytes: 0, wantContent: "hello world\n"},
+		} {
+			var resp protocol.ReadBlobResponse
+			req := &protocol.ReadBlobRequest{Repo: repo, Commit: commit, Path: "hello.txt", MaxBytes: tc.maxBytes}
+			if code, _ := do(t, "read-blob", req, &resp); code != http.StatusOK {
+				t.Fatalf("unexpected status %d", code)
+			}
+			if string(resp.Content) != tc.wantContent || resp.Truncated != tc.wantTruncated {
+				t.Errorf("maxBytes %d: got %q (truncated %v), want %q (truncated %v)", tc.maxBytes, resp.Content, resp.Truncated, tc.wantContent, tc.wantTruncated)
+			}
+		}
+
+		var resp protocol.ReadBlobResponse
+		_, rpcErr := do(t, "read-blob", &protocol.ReadBlobRequest{Repo: repo, Commit: commit, Path: "missing.txt"}, &resp)
+		if rpcErr == nil || rpcErr.Kind != protocol.RPCErrorPathNotFound {
+			t.Errorf("got error %v, want %s", rpcErr, protocol.RPCErrorPathNotFound)
+		}
+
+		_, rpcErr = do(t, "read-blob", &protocol.ReadBlobRequest{Repo: repo, Commit: "HEAD", Path: "hello.txt"}, &resp)
+		if rpcErr == nil || rpcErr.Kind != protocol.RPCErrorInvalidRequest {
+			t.Errorf("got error %v, want %s", rpcErr, protocol.RPCErrorInvalidRequest)
+		}
+	})
+
+	t.Run("list-tree", func(t *testing.T) {
+		var resp protocol.ListTreeResponse
+		if code, _ := do(t, "list-tree", &protocol.ListTreeRequest{Repo: repo, Commit: commit, Recursive: true}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		want := protocol.TreeEntry{Path: "hello.txt", Mode: 0100644, Type: "blob", Size: 12}
+		if len(resp.Entries) != 1 {
+			t.Fatalf("unexpected entries %+v", resp.Entries)
+		}
+		if got := resp.Entries[0]; got.Path != want.Path || got.Mode != want.Mode || got.Type != want.Type || got.Size != want.Size {
+			t.Errorf("got entry %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("log", func(t *testing.T) {
+		var resp protocol.LogResponse
+		if code, _ := do(t, "log", &protocol.LogRequest{Repo: repo, Range: string(commit)}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if len(resp.Commits) != 1 {
+			t.Fatalf("unexpected commits %+v", resp.Commits)
+		}
+		if got := resp.Commits[0]; got.ID != commit || got.Message != "hello" || got.Author.Email != "a@a.com" || len(got.Parents) != 0 {
+			t.Errorf("unexpected commit %+v", got)
+		}
+
+		const missing = "e86b31b62399cfc86199e8b6e21a35e76d0e8b5e"
+		_, rpcErr := do(t, "log", &protocol.LogRequest{Repo: repo, Range: missing}, &resp)
+		if rpcErr == nil || rpcErr.Kind != protocol.RPCErrorRevisionNotFound {
+			t.Errorf("got error %v, want %s", rpcErr, protocol.RPCErrorRevisionNotFound)
+		}
+	})
+
+	t.Run("blame", func(t *testing.T) {
+		var resp protocol.BlameResponse
+		if code, _ := do(t, "blame", &protocol.BlameRequest{Repo: repo, Commit: commit, Path: "hello.txt"}, &resp); code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if len(resp.Hunks) != 1 {
+			t.Fatalf("unexpected hunks %+v", resp.Hunks)
+		}
+		if got := resp.Hunks[0]; got.CommitID != commit || got.StartLine != 1 || got.EndLine != 2 || got.EndByte != 12 || got.Message != "hello" {
+			t.Errorf("unexpected hunk %+v", got)
+		}
+	})
+
+	t.Run("not cloned", func(t *testing.T) {
+		var resp protocol.LogResponse
+		code, _ := do(t, "log", &protocol.LogRequest{Repo: "example.com/notcloned"}, &resp)
+		if code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		// The response to this request was cached above, but must not be served
+		// once the repository is removed.
+		if err := os.Rename(repoDir, repoDir+".removed"); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = os.Rename(repoDir+".removed", repoDir) })
+
+		var resp protocol.ReadBlobResponse
+		code, _ := do(t, "read-blob", &protocol.ReadBlobRequest{Repo: repo, Commit: commit, Path: "hello.txt"}, &resp)
+		if code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", code, http.StatusNotFound)
+		}
+	})
+}
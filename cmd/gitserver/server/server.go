@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
@@ -141,9 +142,25 @@ type Server struct {
 	// shared db handle
 	DB dbutil.DB
 
+	// Rebalance enables moving repositories between gitservers when gitservers
+	// are added or removed. A repository owned by this gitserver but cloned on
+	// another one is fetched from that gitserver rather than cloned from its code
+	// host, and the copies of repositories which have been moved away from this
+	// gitserver are removed.
+	Rebalance bool
+
+	// pinnedAddrs holds the map[api.RepoName]string of the addresses of the
+	// gitservers serving the repositories which are being moved between
+	// gitservers, as loaded by SyncRepoState while rebalancing.
+	pinnedAddrs atomic.Value
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
+	// hotRepos tracks the repositories owned by this gitserver which are hot, and
+	// which are therefore replicated to other gitservers.
+	hotRepos hotRepoTracker
+
 	// ctx is the context we use for all background jobs. It is done when the
 	// server is stopped. Do not directly call this, rather call
 	// Server.context()
@@ -167,6 +184,10 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// rpcCache caches the encoded responses to typed requests which only refer
+	// to absolute commits. It is nil if caching is disabled.
+	rpcCache *lru.Cache
 }
 
 type locks struct {
@@ -228,6 +249,7 @@ func (s *Server) Handler() http.Handler {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.locker = &RepositoryLocker{}
 	s.repoUpdateLocks = make(map[api.RepoName]*locks)
+	s.rpcCache, _ = lru.New(rpcCacheSize)
 
 	// GitMaxConcurrentClones controls the maximum number of clones that
 	// can happen at once on a single gitserver.
@@ -273,10 +295,26 @@ func (s *Server) Handler() http.Handler {
 		setRPSLimiter()
 	})
 
+	// Route the requests this gitserver sends for repositories being moved
+	// between gitservers like the frontend routes them.
+	if s.Rebalance {
+		s.pinnedAddrs.Store(map[api.RepoName]string{})
+		gitserver.DefaultClient.PinnedAddrs = func() map[api.RepoName]string {
+			return s.pinnedAddrs.Load().(map[api.RepoName]string)
+		}
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/archive", s.handleArchive)
 	mux.HandleFunc("/exec", s.handleExec)
 	mux.HandleFunc("/p4-exec", s.handleP4Exec)
+	mux.HandleFunc("/resolve-revision", s.handleResolveRevision)
+	mux.HandleFunc("/list-refs", s.handleListRefs)
+	mux.HandleFunc("/read-blob", s.handleReadBlob)
+	mux.HandleFunc("/list-tree", s.handleListTree)
+	mux.HandleFunc("/log", s.handleLog)
+	mux.HandleFunc("/diff", s.handleDiff)
+	mux.HandleFunc("/blame", s.handleBlame)
 	mux.HandleFunc("/list", s.handleList)
 	mux.HandleFunc("/list-gitolite", s.handleListGitolite)
 	mux.HandleFunc("/is-repo-cloneable", s.handleIsRepoCloneable)
@@ -288,6 +326,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc("/replicate", s.handleReplicate)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -308,17 +347,27 @@ func (s *Server) Janitor(interval time.Duration) {
 
 // SyncRepoState syncs state on disk to the database for all repos and is
 // expected to run in a background goroutine. We perform a full sync if the known
-// gitserver addresses has changed since the last run. Otherwise, we only sync
-// repos that have not yet been assigned a shard.
+// gitserver addresses or the repositories being moved between gitservers have
+// changed since the last run. Otherwise, we only sync repos that have not yet
+// been assigned a shard.
 func (s *Server) SyncRepoState(interval time.Duration, batchSize, perSecond int) {
-	var previousAddrs string
+	var previousAddrs, previousPins string
 	for {
 		addrs := conf.Get().ServiceConnections.GitServers
 		// We turn addrs into a string here for easy comparison and storage of previous
 		// addresses since we'd need to take a copy of the slice anyway.
 		currentAddrs := strings.Join(addrs, ",")
-		fullSync := currentAddrs != previousAddrs
+		// While rebalancing, moves which start or complete change the pinned
+		// repositories, and we must revisit every repository to notice them.
+		currentPins := previousPins
+		if pins, err := s.loadPinnedAddrs(addrs); err != nil {
+			log15.Error("Loading rebalancing repos", "error", err)
+		} else {
+			currentPins = pins
+		}
+		fullSync := currentAddrs != previousAddrs || currentPins != previousPins
 		previousAddrs = currentAddrs
+		previousPins = currentPins
 
 		if err := s.syncRepoState(addrs, batchSize, perSecond, fullSync); err != nil {
 			log15.Error("Syncing repo state", "error ", err)
@@ -328,19 +377,33 @@ func (s *Server) SyncRepoState(interval time.Duration, batchSize, perSecond int)
 	}
 }
 
+// loadPinnedAddrs loads the addresses of the gitservers serving the repositories
+// which are being moved between gitservers into s.pinnedAddrs. It returns them
+// as a string for easy comparison with the previously loaded addresses.
+func (s *Server) loadPinnedAddrs(addrs []string) (string, error) {
+	if !s.Rebalance || s.DB == nil {
+		return "", nil
+	}
+
+	shards, err := database.GitserverRepos(s.DB).ListRebalancing(s.ctx)
+	if err != nil {
+		return "", err
+	}
+	pinned := gitserver.PinnedAddrsForShards(shards, addrs)
+	s.pinnedAddrs.Store(pinned)
+
+	pins := make([]string, 0, len(pinned))
+	for name, addr := range pinned {
+		pins = append(pins, string(name)+"="+addr)
+	}
+	sort.Strings(pins)
+	return strings.Join(pins, ","), nil
+}
+
 // hostnameMatch checks whether the hostname matches the given address.
 // If we don't find an exact match, we look at the initial prefix.
 func (s *Server) hostnameMatch(addr string) bool {
-	if !strings.HasPrefix(addr, s.Hostname) {
-		return false
-	}
-	if addr == s.Hostname {
-		return true
-	}
-	// We know that s.Hostname is shorter than addr so we can safely check the next
-	// char
-	next := addr[len(s.Hostname)]
-	return next == '.' || next == ':'
+	return gitserver.HostnameMatch(s.Hostname, addr)
 }
 
 var (
@@ -438,10 +501,20 @@ func (s *Server) syncRepoState(addrs []string, batchSize, perSecond int, fullSyn
 		// Ensure we're only dealing with repos we are responsible for
 		if addr := gitserver.AddrForRepo(repo.Name, addrs); !s.hostnameMatch(addr) {
 			repoSyncStateCounter.WithLabelValues("other_shard").Inc()
+			if s.Rebalance {
+				s.removeRebalancedRepo(repo)
+			}
 			return nil
 		}
 		repoSyncStateCounter.WithLabelValues("this_shard").Inc()
 
+		// The state of repositories being moved to this shard is recorded by the
+		// move itself, so we must not overwrite it here.
+		if s.Rebalance && s.rebalanceRepo(ctx, repo) {
+			repoSyncStateCounter.WithLabelValues("rebalancing").Inc()
+			return nil
+		}
+
 		dir := s.dir(repo.Name)
 		cloned := repoCloned(dir)
 		_, cloning := s.locker.Status(dir)
@@ -754,6 +827,16 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, paths...)
 
+	// Archives are used to search repositories, so we serve the content of
+	// text files stored in Git LFS rather than their pointers.
+	if format == "tar" {
+		if dir := s.dir(protocol.NormalizeRepo(req.Repo)); hasLFSObjects(dir) {
+			lw := newLFSArchiveWriter(w, dir)
+			defer lw.Close()
+			w = lw
+		}
+	}
+
 	s.exec(w, r, req)
 }
 
@@ -787,6 +870,13 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 
 	req.Repo = protocol.NormalizeRepo(req.Repo)
 
+	// Requests for hot repositories may be sent to a replica of the repository
+	// rather than to this gitserver, so we only observe requests sent here.
+	replicaRead := r.Header.Get(protocol.ReplicaReadHeader) != ""
+	if !replicaRead && s.observeRead(req.Repo) {
+		w.Header().Set(protocol.HotRepoHeader, "true")
+	}
+
 	// Instrumentation
 	{
 		repo := repotrackutil.GetTrackedRepo(req.Repo)
@@ -874,43 +964,20 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 
 	dir := s.dir(req.Repo)
 	if !repoCloned(dir) {
-		if conf.Get().DisableAutoGitUpdates {
-			log15.Debug("not cloning on demand as DisableAutoGitUpdates is set")
-			status = "repo-not-found"
-			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
-			return
-		}
-
-		cloneProgress, cloneInProgress := s.locker.Status(dir)
-		if cloneInProgress {
-			status = "clone-in-progress"
-			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
-				CloneInProgress: true,
-				CloneProgress:   cloneProgress,
-			})
-			return
-		}
+		status = s.serveRepoNotCloned(ctx, w, req.Repo, dir, replicaRead)
+		return
+	}
 
-		cloneProgress, err := s.cloneRepo(ctx, req.Repo, nil)
-		if err != nil {
-			log15.Debug("error starting repo clone", "repo", req.Repo, "err", err)
-			status = "repo-not-found"
+	if replicaRead {
+		// Replicas are only fetched from the primary gitserver, which fetches the
+		// revision if it is missing.
+		if !revisionExists(dir, req.EnsureRevision) {
+			status = "replica-revision-not-found"
 			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
+			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
 			return
 		}
-		status = "clone-in-progress"
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
-			CloneInProgress: true,
-			CloneProgress:   cloneProgress,
-		})
-		return
-	}
-
-	if !conf.Get().DisableAutoGitUpdates {
+	} else if !conf.Get().DisableAutoGitUpdates {
 		// ensureRevision may kick off a git fetch operation which we don't want if we've
 		// configured DisableAutoGitUpdates.
 		if s.ensureRevision(ctx, req.Repo, req.EnsureRevision, dir) {
@@ -918,13 +985,33 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		}
 	}
 
+	// Partial clones don't have all blobs, and git can't fetch the missing ones
+	// on its own, so we fetch the blobs read by archives and file reads first.
+	if treeish, pathspecs, ok := blobsReadByArgs(req.Args); ok {
+		if err := s.fetchMissingBlobs(ctx, req.Repo, dir, treeish, pathspecs); err != nil {
+			log15.Warn("Failed to fetch missing blobs of partial clone", "repo", req.Repo, "error", err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 
 	w.Header().Set("Trailer", "X-Exec-Error")
 	w.Header().Add("Trailer", "X-Exec-Exit-Status")
 	w.Header().Add("Trailer", "X-Exec-Stderr")
-	w.WriteHeader(http.StatusOK)
+
+	// Replicas may lack commits the primary gitserver has, which the client can
+	// only find out from the trailers once it read the response. So replicas only
+	// respond once the command writes output, and respond with a 404 if the
+	// command fails on a missing revision before that, so that the client falls
+	// back to the primary gitserver.
+	var rw *replicaExecResponseWriter
+	if replicaRead {
+		rw = &replicaExecResponseWriter{ResponseWriter: w}
+		w = rw
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
 
 	// Special-case `git rev-parse HEAD` requests. These are invoked by search queries for every repo in scope.
 	// For searches over large repo sets (> 1k), this leads to too many child process execs, which can lead
@@ -970,12 +1057,62 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stderr := stderrBuf.String()
 	checkMaybeCorruptRepo(req.Repo, dir, stderr)
 
+	if rw != nil && !rw.wroteHeader && exitStatus != 0 && isRevisionNotFoundStderr(stderr) {
+		status = "replica-revision-not-found"
+		rw.serveNotFound()
+		return
+	}
+
 	// write trailer
 	w.Header().Set("X-Exec-Error", errorString(execErr))
 	w.Header().Set("X-Exec-Exit-Status", status)
 	w.Header().Set("X-Exec-Stderr", stderr)
 }
 
+// serveRepoNotCloned responds to a request for a repository which is not cloned
+// with a protocol.NotFoundPayload, and starts cloning the repository unless the
+// request was sent to a replica. It returns the status of the request for
+// instrumentation.
+func (s *Server) serveRepoNotCloned(ctx context.Context, w http.ResponseWriter, repo api.RepoName, dir GitDir, replicaRead bool) (status string) {
+	if replicaRead {
+		// The client falls back to the primary gitserver of the repository
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+		return "replica-not-found"
+	}
+
+	if conf.Get().DisableAutoGitUpdates {
+		log15.Debug("not cloning on demand as DisableAutoGitUpdates is set")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{})
+		return "repo-not-found"
+	}
+
+	cloneProgress, cloneInProgress := s.locker.Status(dir)
+	if cloneInProgress {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
+			CloneInProgress: true,
+			CloneProgress:   cloneProgress,
+		})
+		return "clone-in-progress"
+	}
+
+	cloneProgress, err := s.cloneRepo(ctx, repo, nil)
+	if err != nil {
+		log15.Debug("error starting repo clone", "repo", repo, "err", err)
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
+		return "repo-not-found"
+	}
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{
+		CloneInProgress: true,
+		CloneProgress:   cloneProgress,
+	})
+	return "clone-in-progress"
+}
+
 func (s *Server) handleP4Exec(w http.ResponseWriter, r *http.Request) {
 	var req protocol.P4ExecRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1184,13 +1321,23 @@ func setGitAttributes(dir GitDir) error {
 		return errors.Wrap(err, "failed to set git attributes")
 	}
 
-	_, err := updateFileIfDifferent(
-		filepath.Join(infoDir, "attributes"),
-		[]byte(`# Managed by Sourcegraph gitserver.
+	attributes := []byte(`# Managed by Sourcegraph gitserver.
 
 # We want every file to be present in git archive.
 * -export-ignore
-`))
+`)
+
+	// Except for the files excluded from partial clones, whose blobs are never
+	// fetched.
+	excludePaths, err := partialCloneExcludePaths(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to set git attributes")
+	}
+	for _, path := range excludePaths {
+		attributes = append(attributes, fmt.Sprintf("/%s export-ignore\n/%s/** export-ignore\n", path, path)...)
+	}
+
+	_, err = updateFileIfDifferent(filepath.Join(infoDir, "attributes"), attributes)
 	if err != nil {
 		return errors.Wrap(err, "failed to set git attributes")
 	}
@@ -1238,6 +1385,14 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		return "", err
 	}
 
+	// When this gitserver has taken over the repository from another shard, we
+	// fetch it from that shard rather than from the code host.
+	rebalanceFrom, rebalanceURL, rebalancing := s.rebalanceSource(ctx, repo)
+	if rebalancing {
+		syncer = newRebalanceSyncer(syncer)
+		remoteURL = rebalanceURL
+	}
+
 	redactor := newURLRedactor(remoteURL)
 
 	// isCloneable causes a network request, so we limit the number that can
@@ -1315,8 +1470,13 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		// It may already be cloned
-		if !repoCloned(dir) {
+		if rebalancing {
+			// Keep routing requests to the previous shard until we're done
+			if err := database.GitserverRepos(s.DB).StartRebalancing(ctx, repo, rebalanceFrom, s.Hostname); err != nil {
+				return err
+			}
+		} else if !repoCloned(dir) {
+			// It may already be cloned
 			s.setCloneStatusNonFatal(ctx, repo, types.CloneStatusCloning)
 		}
 		defer func() {
@@ -1374,6 +1534,12 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 			return err
 		}
 
+		// Fetch Git LFS objects before the clone is visible, so that files
+		// stored in LFS are never served as pointers after the clone.
+		if gitSyncer, ok := syncer.(*GitRepoSyncer); ok {
+			gitSyncer.fetchLFSObjects(ctx, remoteURL, tmp)
+		}
+
 		if overwrite {
 			// remove the current repo by putting it into our temporary directory
 			err := renameAndSync(dstPath, filepath.Join(filepath.Dir(tmpPath), "old"))
@@ -1389,6 +1555,15 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, opts *cloneOp
 			return err
 		}
 
+		if rebalancing {
+			if err := database.GitserverRepos(s.DB).FinishRebalancing(ctx, repo, s.Hostname); err != nil {
+				return err
+			}
+
+			log15.Info("repo rebalanced", "repo", repo, "from", rebalanceFrom)
+			rebalanceCounter.WithLabelValues("moved").Inc()
+		}
+
 		log15.Info("repo cloned", "repo", repo)
 		repoClonedCounter.Inc()
 
@@ -1683,6 +1858,12 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName) error {
 		return errors.Wrap(err, `git config set "sourcegraph.type"`)
 	}
 
+	// A replica we now own is kept up to date by fetching from the code host, so
+	// it must no longer be removed as a stale replica.
+	if err := gitConfigUnset(dir, gitConfigReplicatedAt); err != nil {
+		log15.Warn("Failed to unmark replica", "repo", repo, "error", err)
+	}
+
 	// Update the last-changed stamp.
 	if err := setLastChanged(dir); err != nil {
 		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
@@ -1693,6 +1874,12 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName) error {
 		return errors.Wrap(err, "update last fetched time")
 	}
 
+	// Replicas of hot repositories only ever fetch from us, so we tell them to
+	// fetch the update.
+	if s.hotRepos.IsHot(repo, time.Now()) {
+		go s.replicateRepo(repo)
+	}
+
 	return nil
 }
 
@@ -1927,9 +2114,20 @@ func computeRefHash(dir GitDir) ([]byte, error) {
 }
 
 func (s *Server) ensureRevision(ctx context.Context, repo api.RepoName, rev string, repoDir GitDir) (didUpdate bool) {
-	if rev == "" || rev == "HEAD" {
+	if revisionExists(repoDir, rev) {
 		return false
 	}
+	// Revision not found, update before returning.
+	_ = s.doRepoUpdate(ctx, repo)
+	return true
+}
+
+// revisionExists returns true if the given revision exists in the repository at
+// repoDir. The empty revision and HEAD are assumed to exist.
+func revisionExists(repoDir GitDir, rev string) bool {
+	if rev == "" || rev == "HEAD" {
+		return true
+	}
 	// rev-parse on an OID does not check if the commit actually exists, so it always
 	// works. So we append ^0 to force the check
 	if isAbsoluteRevision(rev) {
@@ -1937,12 +2135,7 @@ func (s *Server) ensureRevision(ctx context.Context, repo api.RepoName, rev stri
 	}
 	cmd := exec.Command("git", "rev-parse", rev, "--")
 	cmd.Dir = string(repoDir)
-	if err := cmd.Run(); err == nil {
-		return false
-	}
-	// Revision not found, update before returning.
-	_ = s.doRepoUpdate(ctx, repo)
-	return true
+	return cmd.Run() == nil
 }
 
 const headFileRefPrefix = "ref: "
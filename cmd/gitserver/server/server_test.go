@@ -24,9 +24,12 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
@@ -89,6 +92,16 @@ func TestRequest(t *testing.T) {
 			ExpectedCode: http.StatusNotFound,
 			ExpectedBody: `{"cloneInProgress":true}`,
 		},
+		{
+			Name: "UnclonedRepoReplicaRead",
+			Request: func() *http.Request {
+				r := httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "github.com/nicksnyder/go-i18n", "args": ["testcommand"]}`))
+				r.Header.Set(gitserverprotocol.ReplicaReadHeader, "true")
+				return r
+			}(),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"cloneInProgress":false}`, // replicas never clone on demand
+		},
 		{
 			Name:         "Error",
 			Request:      httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "github.com/gorilla/mux", "args": ["testerror"]}`)),
@@ -99,6 +112,41 @@ func TestRequest(t *testing.T) {
 				"X-Exec-Stderr":      {""},
 			},
 		},
+		{
+			Name:         "RevisionNotFound",
+			Request:      httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "github.com/gorilla/mux", "args": ["testrevisionnotfound"]}`)),
+			ExpectedCode: http.StatusOK,
+			ExpectedTrailers: http.Header{
+				"X-Exec-Error":       {""},
+				"X-Exec-Exit-Status": {"128"},
+				"X-Exec-Stderr":      {"fatal: bad object deadbeef"},
+			},
+		},
+		{
+			Name: "RevisionNotFoundReplicaRead",
+			Request: func() *http.Request {
+				r := httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "github.com/gorilla/mux", "args": ["testrevisionnotfound"]}`))
+				r.Header.Set(gitserverprotocol.ReplicaReadHeader, "true")
+				return r
+			}(),
+			ExpectedCode: http.StatusNotFound,
+			ExpectedBody: `{"cloneInProgress":false}`, // the client falls back to the primary gitserver
+		},
+		{
+			Name: "CommandReplicaRead",
+			Request: func() *http.Request {
+				r := httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "github.com/gorilla/mux", "args": ["testcommand"]}`))
+				r.Header.Set(gitserverprotocol.ReplicaReadHeader, "true")
+				return r
+			}(),
+			ExpectedCode: http.StatusOK,
+			ExpectedBody: "teststdout",
+			ExpectedTrailers: http.Header{
+				"X-Exec-Error":       {""},
+				"X-Exec-Exit-Status": {"42"},
+				"X-Exec-Stderr":      {"teststderr"},
+			},
+		},
 		{
 			Name:         "EmptyBody",
 			Request:      httptest.NewRequest("POST", "/exec", nil),
@@ -147,6 +195,9 @@ func TestRequest(t *testing.T) {
 			return 42, nil
 		case "testerror":
 			return 0, errors.New("testerror")
+		case "testrevisionnotfound":
+			_, _ = cmd.Stderr.Write([]byte("fatal: bad object deadbeef"))
+			return 128, nil
 		}
 		return 0, nil
 	}
@@ -948,6 +999,77 @@ func TestSyncRepoState(t *testing.T) {
 	}
 }
 
+func TestCloneRepoRebalancing(t *testing.T) {
+	ctx := context.Background()
+	db := dbtesting.GetDB(t)
+	remoteDir := t.TempDir()
+
+	cmd := func(name string, arg ...string) string {
+		t.Helper()
+		return runCmd(t, remoteDir, name, arg...)
+	}
+
+	// Setup a repo with a commit so we can see if we can clone it.
+	cmd("git", "init", ".")
+	cmd("sh", "-c", "echo hello world > hello.txt")
+	cmd("git", "add", "hello.txt")
+	cmd("git", "commit", "-m", "hello")
+	wantCommit := cmd("git", "rev-parse", "HEAD")
+
+	repoName := api.RepoName("example.com/foo/bar")
+	if err := database.Repos(db).Create(ctx, &types.Repo{Name: repoName, URI: string(repoName)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The previous owner of the repository clones it from the code host and serves
+	// it over the git protocol.
+	oldServer := makeTestServer(ctx, t.TempDir(), remoteDir, db)
+	oldServer.Hostname = "127.0.0.1"
+	oldServer.Rebalance = true
+	if _, err := oldServer.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/git", oldServer.gitServiceHandler()))
+	t.Cleanup(srv.Close)
+
+	conf.Mock(&conf.Unified{ServiceConnections: conftypes.ServiceConnections{
+		GitServers: []string{srv.Listener.Addr().String(), "gitserver-2"},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	// The new owner of the repository must fetch it from the previous owner, as its
+	// code host is unreachable.
+	newServer := makeTestServer(ctx, t.TempDir(), "https://invalid.example.com/foo/bar", db)
+	newServer.Hostname = "gitserver-2"
+	newServer.Rebalance = true
+	if _, err := newServer.cloneRepo(ctx, repoName, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newServer.dir(repoName)
+	if haveCommit := runCmd(t, string(dst), "git", "rev-parse", "HEAD"); haveCommit != wantCommit {
+		t.Fatalf("unexpected HEAD. want=%q have=%q", wantCommit, haveCommit)
+	}
+
+	gr, err := database.GitserverRepos(db).GetByName(ctx, repoName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gr.ShardID != "gitserver-2" || gr.RebalancingFrom != "" || gr.CloneStatus != types.CloneStatusCloned {
+		t.Fatalf("unexpected gitserver repo after rebalancing: %+v", gr)
+	}
+
+	// The previous owner removes its copy now that the move has completed
+	oldServer.removeRebalancedRepo(types.RepoGitserverStatus{Name: repoName, GitserverRepo: gr})
+	if repoCloned(oldServer.dir(repoName)) {
+		t.Fatal("expected previous copy of the repo to be removed")
+	}
+	if !repoCloned(dst) {
+		t.Fatal("expected new copy of the repo to remain")
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	if !testing.Verbose() {
@@ -2,12 +2,16 @@ package server
 
 import (
 	"os/exec"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 )
 
@@ -19,6 +23,14 @@ func (s *Server) RegisterMetrics() {
 		Help: "Duration of executing the echo command.",
 	})
 	prometheus.MustRegister(echoDuration)
+
+	hotRepos := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "src_gitserver_hot_repos",
+		Help: "Number of repositories this gitserver currently considers hot.",
+	}, func() float64 {
+		return float64(s.hotRepos.Len(time.Now()))
+	})
+	prometheus.MustRegister(hotRepos)
 	go func() {
 		for {
 			time.Sleep(10 * time.Second)
@@ -62,3 +74,117 @@ func (s *Server) RegisterMetrics() {
 	})
 	prometheus.MustRegister(c)
 }
+
+// replicaRefreshInterval is the minimum interval at which the replicas of a hot
+// repository are asked to fetch it when it is not updated.
+const replicaRefreshInterval = 10 * time.Minute
+
+// hotRepoTracker observes the rate of read requests for each repository to find
+// the hot repositories, which are served by several gitservers. The zero value is
+// ready to use.
+type hotRepoTracker struct {
+	mu sync.Mutex
+
+	// minute is the number of minutes since the Unix epoch of the current window.
+	minute int64
+	// current and previous count the requests for each repository in the
+	// current and previous window.
+	current  map[api.RepoName]int
+	previous map[api.RepoName]int
+
+	// hotUntil is the time until which each repository is hot.
+	hotUntil map[api.RepoName]time.Time
+	// replicatedAt is the time the replicas of each repository were last asked
+	// to fetch it.
+	replicatedAt map[api.RepoName]time.Time
+}
+
+// Observe records a read request for the given repo and returns true if the repo
+// is hot, either because it is listed in the site configuration or because its
+// request rate exceeds the configured threshold.
+func (t *hotRepoTracker) Observe(repo api.RepoName, now time.Time) bool {
+	config := conf.GitServerReplicas()
+	if config.Replicas < 2 {
+		return false
+	}
+	if gitserver.IsHotRepo(repo) {
+		return true
+	}
+	if config.RequestsPerMinute <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if minute := now.Unix() / 60; minute != t.minute {
+		if minute == t.minute+1 {
+			t.previous = t.current
+		} else {
+			t.previous = nil
+		}
+		t.current = map[api.RepoName]int{}
+		t.minute = minute
+	}
+	t.current[repo]++
+
+	// Estimate the number of requests in the last minute by assuming the requests
+	// of the previous window were evenly spread out.
+	elapsed := float64(now.Unix()%60) / 60
+	rate := float64(t.previous[repo])*(1-elapsed) + float64(t.current[repo])
+	if rate > float64(config.RequestsPerMinute) {
+		if t.hotUntil == nil {
+			t.hotUntil = map[api.RepoName]time.Time{}
+		}
+		t.hotUntil[repo] = now.Add(gitserver.HotRepoTTL)
+	}
+
+	return now.Before(t.hotUntil[repo])
+}
+
+// IsHot returns true if the given repo is listed as hot in the site
+// configuration, or if it was recently observed to be hot.
+func (t *hotRepoTracker) IsHot(repo api.RepoName, now time.Time) bool {
+	if conf.GitServerReplicas().Replicas < 2 {
+		return false
+	}
+	if gitserver.IsHotRepo(repo) {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return now.Before(t.hotUntil[repo])
+}
+
+// ShouldReplicate returns true if the replicas of the given repo were not asked
+// to fetch it within the replica refresh interval, and records that they are
+// asked now.
+func (t *hotRepoTracker) ShouldReplicate(repo api.RepoName, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.replicatedAt[repo]) < replicaRefreshInterval {
+		return false
+	}
+	if t.replicatedAt == nil {
+		t.replicatedAt = map[api.RepoName]time.Time{}
+	}
+	t.replicatedAt[repo] = now
+	return true
+}
+
+// Len returns the number of repositories which were recently observed to be
+// hot, and forgets the repositories which are no longer hot.
+func (t *hotRepoTracker) Len(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for repo, until := range t.hotUntil {
+		if !now.Before(until) {
+			delete(t.hotUntil, repo)
+			delete(t.replicatedAt, repo)
+		}
+	}
+	return len(t.hotUntil)
+}
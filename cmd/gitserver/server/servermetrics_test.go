@@ -0,0 +1,61 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestHotRepoTracker(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				GitServerReplicas: &schema.GitServerReplicas{
+					Repos:             []string{"github.com/sourcegraph/monorepo"},
+					RequestsPerMinute: 10,
+				},
+			},
+		},
+	})
+	defer conf.Mock(nil)
+
+	var tracker hotRepoTracker
+	now := time.Unix(1600000000, 0)
+
+	if !tracker.Observe("github.com/sourcegraph/monorepo", now) {
+		t.Error("expected configured repo to be hot")
+	}
+
+	for i := 0; i < 10; i++ {
+		if tracker.Observe("github.com/sourcegraph/busy", now.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("expected repo to not be hot after %d requests", i+1)
+		}
+	}
+	if !tracker.Observe("github.com/sourcegraph/busy", now.Add(10*time.Second)) {
+		t.Error("expected repo to be hot once its request rate exceeds the threshold")
+	}
+	if tracker.Observe("github.com/sourcegraph/quiet", now.Add(10*time.Second)) {
+		t.Error("expected quiet repo to not be hot")
+	}
+	if have := tracker.Len(now.Add(10 * time.Second)); have != 1 {
+		t.Errorf("unexpected number of hot repos. want=%d have=%d", 1, have)
+	}
+
+	if !tracker.ShouldReplicate("github.com/sourcegraph/busy", now) {
+		t.Error("expected hot repo to be replicated")
+	}
+	if tracker.ShouldReplicate("github.com/sourcegraph/busy", now.Add(time.Minute)) {
+		t.Error("expected hot repo to not be replicated again within the refresh interval")
+	}
+
+	later := now.Add(10*time.Second + 2*gitserver.HotRepoTTL)
+	if tracker.IsHot("github.com/sourcegraph/busy", later) {
+		t.Error("expected repo to no longer be hot")
+	}
+	if have := tracker.Len(later); have != 0 {
+		t.Errorf("unexpected number of hot repos. want=%d have=%d", 0, have)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 
 	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
@@ -33,7 +34,15 @@ type VCSSyncer interface {
 }
 
 // GitRepoSyncer is a syncer for Git repositories.
-type GitRepoSyncer struct{}
+type GitRepoSyncer struct {
+	// LFS configures which Git LFS objects are fetched with the repository. If
+	// nil, no LFS objects are fetched.
+	LFS *LFSOptions
+	// PartialClone configures the repository to be cloned as a partial clone.
+	// If nil, the repository is cloned in full. Repositories which have been
+	// cloned before are not converted.
+	PartialClone *PartialCloneOptions
+}
 
 func (s *GitRepoSyncer) Type() string {
 	return "git"
@@ -78,12 +87,24 @@ func (s *GitRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tm
 		return nil, errors.Wrapf(err, "clone setup failed")
 	}
 
-	cmd, _ = s.fetchCommand(ctx, remoteURL)
+	// Partial clones are only supported with the default fetch command.
+	var filter string
+	if s.PartialClone != nil && customFetchCmd(ctx, remoteURL) == nil && !useRefspecOverrides() {
+		if err := configurePartialClone(GitDir(tmpPath), s.PartialClone); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+		filter = s.PartialClone.filter()
+	}
+
+	cmd, _ = s.fetchCommand(ctx, remoteURL, filter)
 	cmd.Dir = tmpPath
 	return cmd, nil
 }
 
-func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, configRemoteOpts bool) {
+// fetchCommand returns the command to fetch from remoteURL. If filter is set,
+// the default fetch command fetches the objects of a partial clone with the
+// object filter.
+func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL, filter string) (cmd *exec.Cmd, configRemoteOpts bool) {
 	configRemoteOpts = true
 	if customCmd := customFetchCmd(ctx, remoteURL); customCmd != nil {
 		cmd = customCmd
@@ -91,8 +112,15 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, remoteURL)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch",
-			"--progress", "--prune", remoteURL.String(),
+		args := []string{"fetch", "--progress", "--prune", remoteURL.String()}
+		if filter != "" {
+			// The URL of the promisor remote isn't stored in the repository.
+			args = []string{
+				"-c", "remote." + promisorRemote + ".url=" + remoteURL.String(),
+				"fetch", "--progress", "--prune", "--filter=" + filter, promisorRemote,
+			}
+		}
+		cmd = exec.CommandContext(ctx, "git", append(args,
 			// Normal git refs
 			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
 			// GitHub pull requests
@@ -104,21 +132,46 @@ func (s *GitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 			// Gerrit changesets
 			"+refs/changes/*:refs/changes/*",
 			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
+			"+refs/sourcegraph/*:refs/sourcegraph/*")...)
 	}
 	return cmd, configRemoteOpts
 }
 
 // Fetch tries to fetch updates of a Git repository.
 func (s *GitRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
-	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL)
+	filter := partialCloneFilter(dir)
+	if filter != "" && s.PartialClone != nil {
+		// Apply changes to the options of the partial clone.
+		if err := configurePartialClone(dir, s.PartialClone); err != nil {
+			return err
+		}
+		if err := setGitAttributes(dir); err != nil {
+			return err
+		}
+		filter = s.PartialClone.filter()
+	}
+
+	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL, filter)
 	dir.Set(cmd)
 	if output, err := runWith(ctx, cmd, configRemoteOpts, nil); err != nil {
 		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
 	}
+	s.fetchLFSObjects(ctx, remoteURL, dir)
 	return nil
 }
 
+// fetchLFSObjects fetches the Git LFS objects of HEAD if configured. Failing
+// to fetch LFS objects doesn't fail the fetch of the repository, since their
+// pointers can still be served.
+func (s *GitRepoSyncer) fetchLFSObjects(ctx context.Context, remoteURL *vcs.URL, dir GitDir) {
+	if s.LFS == nil {
+		return
+	}
+	if err := fetchLFSObjects(ctx, remoteURL, dir, s.LFS); err != nil {
+		log15.Warn("failed to fetch Git LFS objects", "dir", dir, "error", newURLRedactor(remoteURL).redact(err.Error()))
+	}
+}
+
 // RemoteShowCommand returns the command to be executed for showing remote of a Git repository.
 func (s *GitRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
 	return exec.CommandContext(ctx, "git", "remote", "show", remoteURL.String()), nil
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// mercurialRemote is the name of the remote which Mercurial repositories are
+// fetched from. git-remote-hg keeps the state of the conversion under the name
+// of the remote, so we always fetch with the same name to convert only the
+// new changesets. The URL of the remote isn't stored in the repository.
+const mercurialRemote = "hg"
+
+// MercurialRepoSyncer is a syncer for Mercurial repositories. They are
+// converted to Git repositories with git-remote-hg.
+type MercurialRepoSyncer struct{}
+
+var _ VCSSyncer = &MercurialRepoSyncer{}
+
+func (s *MercurialRepoSyncer) Type() string {
+	return "mercurial"
+}
+
+// mercurialEnv returns the environment of Mercurial commands.
+func mercurialEnv() []string {
+	// Disable localization and user configuration which changes output.
+	return append(os.Environ(), "HGPLAIN=1")
+}
+
+// IsCloneable checks to see if the Mercurial remote URL is cloneable.
+func (s *MercurialRepoSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "hg", "identify", "--noninteractive", remoteURL.String())
+	cmd.Env = mercurialEnv()
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, newURLRedactor(remoteURL).redact(string(out)))
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns the command to be executed for cloning a Mercurial
+// repository as a Git repository.
+func (s *MercurialRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "clone setup failed")
+	}
+
+	cmd = s.fetchCommand(ctx, remoteURL)
+	cmd.Dir = tmpPath
+	return cmd, nil
+}
+
+// fetchCommand returns the command to fetch the branches, bookmarks and tags
+// of the Mercurial repository at remoteURL with git-remote-hg.
+func (s *MercurialRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) *exec.Cmd {
+	// Example: git -c remote.hg.url=hg::https://hg.example.com/repo fetch --progress --prune hg +refs/heads/*:refs/heads/* +refs/tags/*:refs/tags/*
+	cmd := exec.CommandContext(ctx, "git",
+		"-c", "remote."+mercurialRemote+".url=hg::"+remoteURL.String(),
+		"fetch", "--progress", "--prune", mercurialRemote,
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+	cmd.Env = mercurialEnv()
+	return cmd
+}
+
+// Fetch tries to fetch updates of a Mercurial repository as a Git repository.
+func (s *MercurialRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
+	cmd := s.fetchCommand(ctx, remoteURL)
+	dir.Set(cmd)
+	if output, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+	return nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Mercurial repository.
+func (s *MercurialRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	// Showing the remote would convert the repository once more under another
+	// name, so we show the current repository instead.
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// SubversionRepoSyncer is a syncer for Subversion repositories. They are
+// converted to Git repositories with git-svn.
+type SubversionRepoSyncer struct {
+	// Layout is the layout of the repository. With the "standard" layout,
+	// trunk is converted to the branch master, the directories in branches to
+	// branches and the directories in tags to tags. With the "none" layout, the
+	// root of the repository is converted to the branch master.
+	Layout string
+}
+
+var _ VCSSyncer = &SubversionRepoSyncer{}
+
+func (s *SubversionRepoSyncer) Type() string {
+	return "subversion"
+}
+
+// decomposeSubversionRemoteURL decomposes the credentials from the clone URL
+// of a Subversion repository.
+func decomposeSubversionRemoteURL(remoteURL *vcs.URL) (repoURL, username, password string) {
+	u := *remoteURL
+	u.User = nil
+	if remoteURL.User != nil {
+		username = remoteURL.User.Username()
+		password, _ = remoteURL.User.Password()
+	}
+	return u.String(), username, password
+}
+
+// IsCloneable checks to see if the Subversion remote URL is cloneable.
+func (s *SubversionRepoSyncer) IsCloneable(ctx context.Context, remoteURL *vcs.URL) error {
+	repoURL, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	args := []string{"info", "--non-interactive", "--no-auth-cache"}
+	if username != "" {
+		args = append(args, "--username", username, "--password-from-stdin")
+	}
+	cmd := exec.CommandContext(ctx, "svn", append(args, repoURL)...)
+	cmd.Stdin = strings.NewReader(password)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = errors.Errorf("%s (output follows)\n\n%s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// CloneCommand returns the command to be executed for cloning a Subversion
+// repository as a Git repository.
+func (s *SubversionRepoSyncer) CloneCommand(ctx context.Context, remoteURL *vcs.URL, tmpPath string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "init", "--bare", ".")
+	cmd.Dir = tmpPath
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "clone setup failed")
+	}
+
+	// The same as git svn init, but converting to branches and tags rather
+	// than remote-tracking branches, which we don't serve.
+	repoURL, _, _ := decomposeSubversionRemoteURL(remoteURL)
+	config := [][2]string{{"svn-remote.svn.url", repoURL}}
+	if s.Layout == "none" {
+		config = append(config, [2]string{"svn-remote.svn.fetch", ":refs/heads/master"})
+	} else {
+		config = append(config,
+			[2]string{"svn-remote.svn.fetch", "trunk:refs/heads/master"},
+			[2]string{"svn-remote.svn.branches", "branches/*:refs/heads/*"},
+			[2]string{"svn-remote.svn.tags", "tags/*:refs/tags/*"},
+		)
+	}
+	for _, kv := range config {
+		if err := gitConfigSet(GitDir(tmpPath), kv[0], kv[1]); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+
+	cmd, err := s.fetchCommand(ctx, remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = tmpPath
+	return cmd, nil
+}
+
+// fetchCommand returns the command to fetch the new revisions of the
+// Subversion repository at remoteURL with git-svn.
+func (s *SubversionRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (*exec.Cmd, error) {
+	_, username, password := decomposeSubversionRemoteURL(remoteURL)
+
+	// Example: git svn fetch --quiet --no-auth-cache --username admin
+	args := []string{"svn", "fetch", "--quiet", "--no-auth-cache"}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = os.Environ()
+	if username != "" {
+		askpass, err := svnAskpass()
+		if err != nil {
+			return nil, err
+		}
+		cmd.Args = append(cmd.Args, "--username", username)
+		// git-svn reads the password from GIT_ASKPASS, and from SSH_ASKPASS if
+		// GIT_ASKPASS prints nothing. We set both, since the remote options of
+		// git commands disable GIT_ASKPASS.
+		cmd.Env = append(cmd.Env,
+			"GIT_ASKPASS="+askpass,
+			"SSH_ASKPASS="+askpass,
+			svnPasswordEnv+"="+password,
+		)
+	}
+	return cmd, nil
+}
+
+// Fetch tries to fetch updates of a Subversion repository as a Git repository.
+func (s *SubversionRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, dir GitDir) error {
+	cmd, err := s.fetchCommand(ctx, remoteURL)
+	if err != nil {
+		return err
+	}
+	dir.Set(cmd)
+	if output, err := runWith(ctx, cmd, false, nil); err != nil {
+		return errors.Wrapf(err, "failed to update with output %q", newURLRedactor(remoteURL).redact(string(output)))
+	}
+	return nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing Git remote
+// of a Subversion repository.
+func (s *SubversionRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, err error) {
+	// Remote info is encoded as in the current repository
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+// svnPasswordEnv is the environment variable which passes the password of a
+// Subversion repository to the program returned by svnAskpass.
+const svnPasswordEnv = "SRC_SVN_PASSWORD"
+
+var (
+	svnAskpassMu   sync.Mutex
+	svnAskpassPath string
+)
+
+// svnAskpass returns the path of a program which prints the password in the
+// svnPasswordEnv environment variable, so that we can pass passwords to
+// git-svn without storing them on disk. The program is created in the temp
+// dir, and created again if it has been removed since, e.g. by a tmp cleaner.
+func svnAskpass() (string, error) {
+	svnAskpassMu.Lock()
+	defer svnAskpassMu.Unlock()
+
+	if svnAskpassPath != "" {
+		if _, err := os.Stat(svnAskpassPath); err == nil {
+			return svnAskpassPath, nil
+		}
+	}
+
+	f, err := os.CreateTemp("", "svn-askpass-")
+	if err != nil {
+		return "", errors.Wrap(err, "create svn askpass")
+	}
+	defer f.Close()
+	if _, err := f.WriteString("#!/bin/sh\nprintf '%s\\n' \"$" + svnPasswordEnv + "\"\n"); err != nil {
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "write svn askpass")
+	}
+	if err := f.Chmod(0700); err != nil {
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "chmod svn askpass")
+	}
+	svnAskpassPath = f.Name()
+	return svnAskpassPath, nil
+}
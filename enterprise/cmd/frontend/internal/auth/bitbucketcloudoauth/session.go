@@ -0,0 +1,120 @@
+package bitbucketcloudoauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/oauth"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+)
+
+type sessionIssuerHelper struct {
+	*extsvc.CodeHost
+	apiURL   *url.URL
+	clientID string
+	db       dbutil.DB
+}
+
+func (s *sessionIssuerHelper) GetOrCreateUser(ctx context.Context, token *oauth2.Token, anonymousUserID, firstSourceURL string) (actr *actor.Actor, safeErrMsg string, err error) {
+	bUser, err := UserFromContext(ctx)
+	if err != nil {
+		return nil, "Could not read Bitbucket Cloud user from callback request.", errors.Wrap(err, "could not read user from context")
+	}
+
+	username := bUser.Username
+	if username == "" {
+		username = bUser.Nickname
+	}
+	login, err := auth.NormalizeUsername(username)
+	if err != nil {
+		return nil, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", login), err
+	}
+
+	email, err := s.primaryEmail(ctx, token)
+	if err != nil {
+		return nil, "Could not get the email addresses of the Bitbucket Cloud user.", err
+	}
+
+	var data extsvc.AccountData
+	bitbucketcloud.SetExternalAccountData(&data, bUser, token)
+
+	// Like with GitLab, we only use the primary email to resolve the user's identity, and
+	// only if Bitbucket Cloud reports it as confirmed.
+	userID, safeErrMsg, err := auth.GetAndSaveUser(ctx, s.db, auth.GetAndSaveUserOp{
+		UserProps: database.NewUser{
+			Username:        login,
+			Email:           email,
+			EmailIsVerified: email != "",
+			DisplayName:     bUser.DisplayName,
+			AvatarURL:       bUser.Links.Avatar.Href,
+		},
+		ExternalAccount: extsvc.AccountSpec{
+			ServiceType: s.ServiceType,
+			ServiceID:   s.ServiceID,
+			ClientID:    s.clientID,
+			AccountID:   bUser.UUID,
+		},
+		ExternalAccountData: data,
+		CreateIfNotExist:    true,
+	})
+	if err != nil {
+		return nil, safeErrMsg, err
+	}
+	return actor.FromUser(userID), "", nil
+}
+
+// primaryEmail returns the confirmed primary email address of the user of the
+// token, or an empty string if it has none.
+func (s *sessionIssuerHelper) primaryEmail(ctx context.Context, token *oauth2.Token) (string, error) {
+	client := newClient(s.apiURL, token.AccessToken)
+	for page := (&bitbucketcloud.PageToken{Pagelen: 100}); page != nil; {
+		var emails []*bitbucketcloud.UserEmail
+		var err error
+		if emails, page, err = client.CurrentUserEmails(ctx, page); err != nil {
+			return "", errors.Wrap(err, "list emails of Bitbucket Cloud user")
+		}
+		if !page.HasMore() {
+			page = nil
+		}
+		for _, e := range emails {
+			if e.IsPrimary && e.IsConfirmed {
+				return e.Email, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *sessionIssuerHelper) CreateCodeHostConnection(ctx context.Context, token *oauth2.Token, providerID string) (safeErrMsg string, err error) {
+	// Bitbucket Cloud connections authenticate with app passwords, which can't be
+	// created with an OAuth token.
+	return "Creating Bitbucket Cloud code host connections from OAuth flow is not supported.", errors.New("unsupported code host connection from OAuth flow")
+}
+
+func (s *sessionIssuerHelper) DeleteStateCookie(w http.ResponseWriter) {
+	stateConfig := getStateConfig()
+	stateConfig.MaxAge = -1
+	http.SetCookie(w, oauth.NewCookie(stateConfig, ""))
+}
+
+func (s *sessionIssuerHelper) SessionData(token *oauth2.Token) oauth.SessionData {
+	return oauth.SessionData{
+		ID: providers.ConfigID{
+			ID:   s.ServiceID,
+			Type: s.ServiceType,
+		},
+		AccessToken: token.AccessToken,
+		TokenType:   token.Type(),
+	}
+}
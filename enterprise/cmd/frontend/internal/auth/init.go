@@ -8,9 +8,11 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/app"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/bitbucketcloudoauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/githuboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/gitlaboauth"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/httpheader"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/ldap"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/openidconnect"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/auth/saml"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -21,6 +23,8 @@ import (
 func Init(db dbutil.DB) {
 	githuboauth.Init(db)
 	gitlaboauth.Init(db)
+	bitbucketcloudoauth.Init(db)
+	ldap.Init(db)
 
 	// Register enterprise auth middleware
 	auth.RegisterMiddlewares(
@@ -29,6 +33,8 @@ func Init(db dbutil.DB) {
 		httpheader.Middleware(db),
 		githuboauth.Middleware(db),
 		gitlaboauth.Middleware(db),
+		bitbucketcloudoauth.Middleware(db),
+		ldap.Middleware(db),
 	)
 	// Register app-level sign-out handler
 	app.RegisterSSOSignOutHandler(ssoSignOutHandler)
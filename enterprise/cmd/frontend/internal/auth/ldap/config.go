@@ -0,0 +1,98 @@
+package ldap
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Init must be called by the frontend to register the LDAP auth providers of the site
+// configuration and to start syncing the members of their groups to organizations.
+func Init(db dbutil.DB) {
+	const pkgName = "ldap"
+	conf.ContributeValidator(validateConfig)
+	go func() {
+		conf.Watch(func() {
+			providers.Update(pkgName, getProviders())
+		})
+	}()
+
+	startGroupSync(db)
+}
+
+func getProviders() []providers.Provider {
+	var ps []providers.Provider
+	for _, p := range conf.Get().AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+		ps = append(ps, &provider{config: *p.Ldap})
+	}
+	return ps
+}
+
+// getProvider looks up the registered LDAP auth provider with the given ID.
+func getProvider(id string) *provider {
+	p, _ := providers.GetProviderByConfigID(providers.ConfigID{Type: providerType, ID: id}).(*provider)
+	return p
+}
+
+func validateConfig(c conf.Unified) (problems conf.Problems) {
+	for _, p := range c.AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+		for _, msg := range validateProvider(p.Ldap) {
+			problems = append(problems, conf.NewSiteProblem(msg))
+		}
+	}
+	return problems
+}
+
+func validateProvider(p *schema.LDAPAuthProvider) (problems []string) {
+	u, err := url.Parse(p.Url)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("Could not parse `url` of ldap auth provider: %s", err))
+	} else if p.StartTLS && u.Scheme == "ldaps" {
+		problems = append(problems, "`startTLS` of ldap auth provider only applies to ldap URLs, as ldaps URLs already use TLS")
+	}
+
+	if p.Certificate != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(p.Certificate)) {
+		problems = append(problems, "Invalid `certificate` of ldap auth provider")
+	}
+
+	if p.UserFilter != "" {
+		if _, err := eldap.CompileFilter(p.UserFilter); err != nil {
+			problems = append(problems, fmt.Sprintf("Invalid `userFilter` of ldap auth provider: %s", err))
+		}
+	}
+
+	if p.GroupSync != nil {
+		orgs := make(map[string]bool, len(p.GroupSync.Orgs))
+		for _, o := range p.GroupSync.Orgs {
+			if orgs[o.Org] {
+				problems = append(problems, fmt.Sprintf("Organization %q is synced from more than one group in `groupSync.orgs` of ldap auth provider", o.Org))
+			}
+			orgs[o.Org] = true
+		}
+	}
+	return problems
+}
+
+func providerConfigID(pc *schema.LDAPAuthProvider) string {
+	data, err := json.Marshal(pc)
+	if err != nil {
+		panic(err)
+	}
+	b := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(b[:16])
+}
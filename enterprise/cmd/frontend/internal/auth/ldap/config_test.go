@@ -0,0 +1,53 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       schema.LDAPAuthProvider
+		wantProblems []string
+	}{
+		{
+			name:   "valid",
+			config: schema.LDAPAuthProvider{Url: "ldap://ldap.example.com", StartTLS: true, UserFilter: "(objectClass=inetOrgPerson)"},
+		},
+		{
+			name:         "startTLS with ldaps",
+			config:       schema.LDAPAuthProvider{Url: "ldaps://ldap.example.com", StartTLS: true},
+			wantProblems: []string{"`startTLS` of ldap auth provider only applies to ldap URLs, as ldaps URLs already use TLS"},
+		},
+		{
+			name:         "invalid certificate",
+			config:       schema.LDAPAuthProvider{Url: "ldaps://ldap.example.com", Certificate: "not a certificate"},
+			wantProblems: []string{"Invalid `certificate` of ldap auth provider"},
+		},
+		{
+			name:         "invalid user filter",
+			config:       schema.LDAPAuthProvider{Url: "ldap://ldap.example.com", UserFilter: "objectClass=person"},
+			wantProblems: []string{"Invalid `userFilter` of ldap auth provider: invalid filter \"objectClass=person\": expected '(' at offset 0"},
+		},
+		{
+			name: "org synced from two groups",
+			config: schema.LDAPAuthProvider{Url: "ldap://ldap.example.com", GroupSync: &schema.LDAPGroupSync{Orgs: []*schema.LDAPGroupOrg{
+				{GroupDN: "cn=a,dc=example,dc=com", Org: "eng"},
+				{GroupDN: "cn=b,dc=example,dc=com", Org: "eng"},
+			}}},
+			wantProblems: []string{"Organization \"eng\" is synced from more than one group in `groupSync.orgs` of ldap auth provider"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems := validateProvider(&test.config)
+			if diff := cmp.Diff(test.wantProblems, problems); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
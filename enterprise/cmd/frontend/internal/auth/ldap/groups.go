@@ -0,0 +1,188 @@
+package ldap
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// startGroupSync starts the background routine that periodically syncs the members of the
+// organizations that are mapped from LDAP groups.
+func startGroupSync(db dbutil.DB) {
+	ctx := context.Background()
+	s := &groupSyncer{db: db, lastSync: map[string]time.Time{}}
+	go goroutine.MonitorBackgroundRoutines(ctx,
+		goroutine.NewPeriodicGoroutine(ctx, time.Minute, goroutine.NewHandlerWithErrorMessage("ldap group sync", s.Handle)),
+	)
+}
+
+// groupSyncer syncs the organization memberships of the users of each LDAP auth provider with
+// group sync enabled, once every interval configured for that provider.
+type groupSyncer struct {
+	db dbutil.DB
+
+	mu       sync.Mutex
+	lastSync map[string]time.Time // service ID -> time of the last sync
+}
+
+func (s *groupSyncer) Handle(ctx context.Context) error {
+	var errs *multierror.Error
+	for _, pp := range providers.Providers() {
+		p, ok := pp.(*provider)
+		if !ok || p.config.GroupSync == nil {
+			continue
+		}
+
+		interval := time.Duration(p.config.GroupSync.Interval) * time.Minute
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		s.mu.Lock()
+		due := time.Since(s.lastSync[p.serviceID()]) >= interval
+		if due {
+			s.lastSync[p.serviceID()] = time.Now()
+		}
+		s.mu.Unlock()
+		if !due {
+			continue
+		}
+
+		accounts, err := database.ExternalAccounts(s.db).List(ctx, database.ExternalAccountsListOptions{
+			ServiceType: providerType,
+			ServiceID:   p.serviceID(),
+		})
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, "listing LDAP external accounts"))
+			continue
+		}
+		userDNs := make(map[int32]string, len(accounts))
+		for _, acct := range accounts {
+			userDNs[acct.UserID] = acct.AccountID
+		}
+		if err := syncOrgs(ctx, s.db, p, userDNs); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "syncing organizations of LDAP server %q", p.serviceID()))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// syncOrgs adds the given users to, and removes them from, the organizations mapped from the
+// LDAP groups of the provider so that they are members exactly of the organizations mapped
+// from the groups they belong to. userDNs maps user IDs to the normalized DNs of their LDAP
+// entries. Members of the organizations who are not in userDNs are left untouched.
+func syncOrgs(ctx context.Context, db dbutil.DB, p *provider, userDNs map[int32]string) error {
+	if p.config.GroupSync == nil || len(p.config.GroupSync.Orgs) == 0 || len(userDNs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	c, err := p.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	memberAttr := p.config.GroupSync.MemberAttribute
+	if memberAttr == "" {
+		memberAttr = "member"
+	}
+
+	var errs *multierror.Error
+	for _, o := range p.config.GroupSync.Orgs {
+		org, err := database.Orgs(db).GetByName(ctx, o.Org)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				log15.Warn("Skipping sync of LDAP group to organization that does not exist.", "group", o.GroupDN, "org", o.Org)
+				continue
+			}
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		members, err := groupMembers(ctx, c, o.GroupDN, memberAttr)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "getting members of LDAP group %q", o.GroupDN))
+			continue
+		}
+		orgMembers, err := database.OrgMembers(db).GetByOrgID(ctx, org.ID)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		add, remove := diffOrgMembers(userDNs, members, orgMembers)
+		for _, userID := range add {
+			if _, err := database.OrgMembers(db).Create(ctx, org.ID, userID); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "adding user %d to organization %q", userID, o.Org))
+			}
+		}
+		for _, userID := range remove {
+			if err := database.OrgMembers(db).Remove(ctx, org.ID, userID); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "removing user %d from organization %q", userID, o.Org))
+			}
+		}
+		if len(add) > 0 || len(remove) > 0 {
+			log15.Debug("Synced LDAP group to organization.", "group", o.GroupDN, "org", o.Org, "added", len(add), "removed", len(remove))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// groupMembers returns the set of normalized DNs of the members of an LDAP group.
+func groupMembers(ctx context.Context, c *eldap.Conn, groupDN, memberAttr string) (map[string]bool, error) {
+	entries, err := c.Search(ctx, &eldap.SearchRequest{
+		BaseDN:     groupDN,
+		Scope:      eldap.ScopeBaseObject,
+		Attributes: []string{memberAttr},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("group not found")
+	}
+
+	members := map[string]bool{}
+	for _, dn := range entries[0].Values(memberAttr) {
+		members[eldap.NormalizeDN(dn)] = true
+	}
+	return members, nil
+}
+
+// diffOrgMembers returns the IDs of the users in userDNs to add to and remove from an
+// organization with the given members, so that exactly those whose DNs are in groupMembers are
+// members of it.
+func diffOrgMembers(userDNs map[int32]string, groupMembers map[string]bool, orgMembers []*types.OrgMembership) (add, remove []int32) {
+	isOrgMember := make(map[int32]bool, len(orgMembers))
+	for _, m := range orgMembers {
+		isOrgMember[m.UserID] = true
+	}
+
+	for userID, dn := range userDNs {
+		inGroup := groupMembers[eldap.NormalizeDN(dn)]
+		switch {
+		case inGroup && !isOrgMember[userID]:
+			add = append(add, userID)
+		case !inGroup && isOrgMember[userID]:
+			remove = append(remove, userID)
+		}
+	}
+	sort.Slice(add, func(i, j int) bool { return add[i] < add[j] })
+	sort.Slice(remove, func(i, j int) bool { return remove[i] < remove[j] })
+	return add, remove
+}
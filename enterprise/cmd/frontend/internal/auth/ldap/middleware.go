@@ -0,0 +1,202 @@
+// Package ldap implements auth via LDAP.
+package ldap
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// All LDAP endpoints are under this path prefix.
+const authPrefix = auth.AuthURLPrefix + "/ldap"
+
+// csrfCookieName is the name of the cookie holding the token that the login form must echo
+// back. The auth middlewares run before the app's CSRF protection, so the login form needs its
+// own.
+const csrfCookieName = "sg-ldap-csrf"
+
+// Middleware is middleware for LDAP authentication, adding endpoints under the auth path prefix
+// ("/.auth") that show a login form and check the submitted credentials against the LDAP
+// server.
+//
+// 🚨 SECURITY
+func Middleware(db dbutil.DB) *auth.Middleware {
+	return &auth.Middleware{
+		API: func(next http.Handler) http.Handler {
+			return next
+		},
+		App: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.URL.Path, authPrefix+"/") {
+					next.ServeHTTP(w, r)
+					return
+				}
+				authHandler(db)(w, r)
+			})
+		},
+	}
+}
+
+// authHandler handles the LDAP login form.
+//
+// 🚨 SECURITY
+func authHandler(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, authPrefix) != "/login" {
+			http.Error(w, "", http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			p := getProvider(r.URL.Query().Get("pc"))
+			if p == nil {
+				http.Error(w, "Misconfigured LDAP auth provider.", http.StatusNotFound)
+				return
+			}
+			renderLoginForm(w, r, p, r.URL.Query().Get("redirect"), "", "", http.StatusOK)
+
+		case http.MethodPost:
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, "Invalid login form.", http.StatusBadRequest)
+				return
+			}
+			p := getProvider(r.PostForm.Get("pc"))
+			if p == nil {
+				http.Error(w, "Misconfigured LDAP auth provider.", http.StatusNotFound)
+				return
+			}
+			redirect := r.PostForm.Get("redirect")
+
+			// Validate the CSRF token to prevent login CSRF attacks.
+			cookie, err := r.Cookie(csrfCookieName)
+			if err != nil || cookie.Value == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.PostForm.Get("csrf"))) != 1 {
+				log15.Error("LDAP auth failed: CSRF token mismatch (possible request forgery).")
+				renderLoginForm(w, r, p, redirect, "", "Your sign-in session expired. Try signing in again.", http.StatusBadRequest)
+				return
+			}
+
+			username := r.PostForm.Get("username")
+			ctx := r.Context()
+			entry, err := p.authenticate(ctx, username, r.PostForm.Get("password"))
+			if err != nil {
+				if eldap.IsInvalidCredentials(err) {
+					renderLoginForm(w, r, p, redirect, username, "Invalid username or password.", http.StatusUnauthorized)
+					return
+				}
+				log15.Error("LDAP auth failed: error authenticating user.", "error", err)
+				renderLoginForm(w, r, p, redirect, username, "Authentication failed. The LDAP server could not be reached or returned an error.", http.StatusInternalServerError)
+				return
+			}
+
+			actr, safeErrMsg, err := getOrCreateUser(ctx, db, p, entry)
+			if err != nil {
+				log15.Error("LDAP auth failed: error looking up LDAP-authenticated user.", "error", err, "userErr", safeErrMsg)
+				http.Error(w, safeErrMsg, http.StatusInternalServerError)
+				return
+			}
+
+			// Sync the user's organizations right away, instead of waiting for the next
+			// periodic sync. It's not fatal if this fails.
+			if err := syncOrgs(ctx, db, p, map[int32]string{actr.UID: eldap.NormalizeDN(entry.DN)}); err != nil {
+				log15.Warn("Failed to sync organizations of LDAP-authenticated user.", "error", err)
+			}
+
+			user, err := database.GlobalUsers.GetByID(ctx, actr.UID)
+			if err != nil {
+				log15.Error("LDAP auth failed: error retrieving user from database.", "error", err)
+				http.Error(w, "Failed to retrieve user: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err := session.SetActor(w, r, actr, 0, user.CreatedAt); err != nil {
+				log15.Error("LDAP auth failed: could not initiate session.", "error", err)
+				http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: csrfCookieName, Path: authPrefix, MaxAge: -1})
+
+			// 🚨 SECURITY: Call auth.SafeRedirectURL to avoid an open-redirect vuln.
+			http.Redirect(w, r, auth.SafeRedirectURL(redirect), http.StatusFound)
+
+		default:
+			http.Error(w, "", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+var loginFormTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sign in with {{.DisplayName}} - Sourcegraph</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; display: flex; justify-content: center; margin-top: 4rem; }
+form { width: 20rem; }
+label, input, button { display: block; width: 100%; box-sizing: border-box; }
+input { margin: 0.25rem 0 1rem; padding: 0.375rem; }
+button { padding: 0.5rem; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<form method="post" action="{{.Action}}">
+<h1>Sign in with {{.DisplayName}}</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<input type="hidden" name="pc" value="{{.ProviderID}}">
+<input type="hidden" name="redirect" value="{{.Redirect}}">
+<input type="hidden" name="csrf" value="{{.CSRFToken}}">
+<label for="username">Username</label>
+<input id="username" name="username" value="{{.Username}}" autocomplete="username" autofocus required>
+<label for="password">Password</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required>
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
+
+// renderLoginForm renders the login form of the provider with a new CSRF token.
+func renderLoginForm(w http.ResponseWriter, r *http.Request, p *provider, redirect, username, errMsg string, status int) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log15.Error("LDAP auth failed: could not generate CSRF token.", "error", err)
+		http.Error(w, "Could not generate CSRF token.", http.StatusInternalServerError)
+		return
+	}
+	csrfToken := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    csrfToken,
+		Path:     authPrefix,
+		HttpOnly: true,
+		Secure:   r.URL.Scheme == "https" || r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := loginFormTemplate.Execute(w, map[string]string{
+		"Action":      authPrefix + "/login",
+		"DisplayName": p.CachedInfo().DisplayName,
+		"ProviderID":  p.ConfigID().ID,
+		"Redirect":    redirect,
+		"Username":    username,
+		"CSRFToken":   csrfToken,
+		"Error":       errMsg,
+	}); err != nil {
+		log15.Error("Failed to render LDAP login form.", "error", err)
+	}
+}
@@ -0,0 +1,167 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const providerType = "ldap"
+
+// dialTimeout bounds the time spent on each connection to the LDAP server, including binding and
+// searching.
+const dialTimeout = 30 * time.Second
+
+type provider struct {
+	config schema.LDAPAuthProvider
+}
+
+// ConfigID implements providers.Provider.
+func (p *provider) ConfigID() providers.ConfigID {
+	return providers.ConfigID{
+		Type: providerType,
+		ID:   providerConfigID(&p.config),
+	}
+}
+
+// Config implements providers.Provider.
+func (p *provider) Config() schema.AuthProviders {
+	return schema.AuthProviders{Ldap: &p.config}
+}
+
+// Refresh implements providers.Provider.
+func (p *provider) Refresh(context.Context) error {
+	return nil
+}
+
+// CachedInfo implements providers.Provider.
+func (p *provider) CachedInfo() *providers.Info {
+	info := providers.Info{
+		ServiceID:   p.serviceID(),
+		DisplayName: p.config.DisplayName,
+		AuthenticationURL: (&url.URL{
+			Path:     path.Join(authPrefix, "login"),
+			RawQuery: (url.Values{"pc": []string{providerConfigID(&p.config)}}).Encode(),
+		}).String(),
+	}
+	if info.DisplayName == "" {
+		info.DisplayName = "LDAP"
+	}
+	return &info
+}
+
+// serviceID is the stable identifier of the LDAP server, used as the service ID of the
+// external accounts of its users.
+func (p *provider) serviceID() string {
+	return strings.TrimSuffix(p.config.Url, "/")
+}
+
+// attributes returns the names of the LDAP attributes of the username, email and display name
+// of users.
+func (p *provider) attributes() (username, email, displayName string) {
+	username, email, displayName = "uid", "mail", "cn"
+	if a := p.config.Attributes; a != nil {
+		if a.Username != "" {
+			username = a.Username
+		}
+		if a.Email != "" {
+			email = a.Email
+		}
+		if a.DisplayName != "" {
+			displayName = a.DisplayName
+		}
+	}
+	return username, email, displayName
+}
+
+func (p *provider) allowSignup() bool {
+	return p.config.AllowSignup == nil || *p.config.AllowSignup
+}
+
+// dial connects to the LDAP server and binds as the configured service account. The caller
+// must close the returned connection.
+func (p *provider) dial(ctx context.Context) (*eldap.Conn, error) {
+	var tlsConfig *tls.Config
+	if p.config.Certificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(p.config.Certificate)) {
+			return nil, errors.New("invalid certificate of LDAP auth provider")
+		}
+		tlsConfig = &tls.Config{RootCAs: pool}
+	}
+
+	c, err := eldap.Dial(ctx, p.config.Url, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if p.config.StartTLS && strings.HasPrefix(p.config.Url, "ldap://") {
+		if err := c.StartTLS(ctx, tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if err := c.Bind(ctx, p.config.BindDN, p.config.BindPassword); err != nil {
+		c.Close()
+		// Don't wrap err, so that a misconfigured bindDN is not mistaken for invalid user
+		// credentials.
+		return nil, errors.Newf("binding as bindDN: %s", err.Error())
+	}
+	return c, nil
+}
+
+// authenticate looks up the entry of the user with the given username and verifies their
+// password by binding as that entry. It returns an error for which eldap.IsInvalidCredentials
+// is true if there is no such user or the password is wrong.
+func (p *provider) authenticate(ctx context.Context, username, password string) (*eldap.Entry, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	c, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	usernameAttr, emailAttr, displayNameAttr := p.attributes()
+	userFilter := p.config.UserFilter
+	if userFilter == "" {
+		userFilter = "(objectClass=person)"
+	}
+	entries, err := c.Search(ctx, &eldap.SearchRequest{
+		BaseDN:     p.config.UserBaseDN,
+		Scope:      eldap.ScopeWholeSubtree,
+		Filter:     fmt.Sprintf("(&%s(%s=%s))", userFilter, usernameAttr, eldap.EscapeFilter(username)),
+		Attributes: []string{usernameAttr, emailAttr, displayNameAttr},
+		SizeLimit:  2,
+	})
+	if err != nil {
+		var e *eldap.Error
+		if errors.As(err, &e) && e.ResultCode == eldap.ResultSizeLimitExceeded {
+			return nil, errors.Errorf("more than one LDAP entry matches username %q", username)
+		}
+		return nil, errors.Wrap(err, "searching for user")
+	}
+	switch len(entries) {
+	case 0:
+		return nil, &eldap.Error{ResultCode: eldap.ResultInvalidCredentials, Message: "no such user"}
+	case 1:
+	default:
+		return nil, errors.Errorf("more than one LDAP entry matches username %q", username)
+	}
+
+	if err := c.Bind(ctx, entries[0].DN, password); err != nil {
+		return nil, err
+	}
+	return entries[0], nil
+}
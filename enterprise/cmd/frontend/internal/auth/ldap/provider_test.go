@@ -0,0 +1,175 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ldaptest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var testEntries = []ldaptest.Entry{
+	{
+		DN: "cn=admin,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"person"},
+			"userPassword": {"admin-secret"},
+		},
+	},
+	{
+		DN: "uid=alice,ou=People,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"person"},
+			"uid":          {"alice"},
+			"mail":         {"alice@example.com"},
+			"cn":           {"Alice Liddell"},
+			"userPassword": {"alice-secret"},
+		},
+	},
+	{
+		DN: "uid=bob,ou=People,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"person"},
+			"uid":          {"bob"},
+			"userPassword": {"bob-secret"},
+		},
+	},
+	{
+		DN: "uid=bob,ou=Contractors,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"person"},
+			"uid":          {"bob"},
+			"userPassword": {"bob-secret"},
+		},
+	},
+	{
+		DN: "cn=engineering,ou=Groups,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"member":      {"UID=Alice, OU=People, DC=example, DC=com"},
+		},
+	},
+}
+
+func newTestProvider(url string) *provider {
+	return &provider{config: schema.LDAPAuthProvider{
+		Type:         providerType,
+		Url:          url,
+		BindDN:       "cn=admin,dc=example,dc=com",
+		BindPassword: "admin-secret",
+		UserBaseDN:   "ou=People,dc=example,dc=com",
+	}}
+}
+
+func TestProvider_authenticate(t *testing.T) {
+	s := ldaptest.NewServer(testEntries...)
+	defer s.Close()
+
+	tests := []struct {
+		name                   string
+		userBaseDN             string
+		username, password     string
+		wantDN                 string
+		wantInvalidCredentials bool
+		wantErr                bool
+	}{
+		{name: "valid credentials", username: "alice", password: "alice-secret", wantDN: "uid=alice,ou=People,dc=example,dc=com"},
+		{name: "wrong password", username: "alice", password: "bob-secret", wantInvalidCredentials: true},
+		{name: "empty password", username: "alice", password: "", wantInvalidCredentials: true},
+		{name: "unknown user", username: "carol", password: "alice-secret", wantInvalidCredentials: true},
+		{name: "filter injection", username: "*", password: "alice-secret", wantInvalidCredentials: true},
+		{name: "ambiguous user", userBaseDN: "dc=example,dc=com", username: "bob", password: "bob-secret", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newTestProvider(s.URL)
+			if test.userBaseDN != "" {
+				p.config.UserBaseDN = test.userBaseDN
+			}
+
+			entry, err := p.authenticate(context.Background(), test.username, test.password)
+			switch {
+			case test.wantInvalidCredentials:
+				if !eldap.IsInvalidCredentials(err) {
+					t.Fatalf("want invalid credentials error but got %v", err)
+				}
+				return
+			case test.wantErr:
+				if err == nil || eldap.IsInvalidCredentials(err) {
+					t.Fatalf("want error other than invalid credentials but got %v", err)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+			if entry.DN != test.wantDN {
+				t.Fatalf("want DN %q but got %q", test.wantDN, entry.DN)
+			}
+			if got, want := entry.Value("mail"), "alice@example.com"; got != want {
+				t.Fatalf("want email %q but got %q", want, got)
+			}
+		})
+	}
+
+	t.Run("wrong bind password", func(t *testing.T) {
+		p := newTestProvider(s.URL)
+		p.config.BindPassword = "wrong"
+		if _, err := p.authenticate(context.Background(), "alice", "alice-secret"); err == nil || eldap.IsInvalidCredentials(err) {
+			t.Fatalf("want bind error but got %v", err)
+		}
+	})
+}
+
+func TestGroupMembers(t *testing.T) {
+	s := ldaptest.NewServer(testEntries...)
+	defer s.Close()
+
+	ctx := context.Background()
+	c, err := newTestProvider(s.URL).dial(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	members, err := groupMembers(ctx, c, "cn=engineering,ou=Groups,dc=example,dc=com", "member")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]bool{"uid=alice,ou=people,dc=example,dc=com": true}, members); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := groupMembers(ctx, c, "cn=missing,ou=Groups,dc=example,dc=com", "member"); err == nil {
+		t.Fatal("want error for missing group but got none")
+	}
+}
+
+func TestDiffOrgMembers(t *testing.T) {
+	userDNs := map[int32]string{
+		1: "uid=alice,ou=people,dc=example,dc=com",
+		2: "uid=bob,ou=people,dc=example,dc=com",
+		3: "uid=carol,ou=people,dc=example,dc=com",
+		4: "uid=dave,ou=people,dc=example,dc=com",
+	}
+	groupMembers := map[string]bool{
+		"uid=alice,ou=people,dc=example,dc=com": true,
+		"uid=bob,ou=people,dc=example,dc=com":   true,
+	}
+	orgMembers := []*types.OrgMembership{
+		{UserID: 2}, // in the group and the org
+		{UserID: 3}, // no longer in the group
+		{UserID: 5}, // not an LDAP user
+	}
+
+	add, remove := diffOrgMembers(userDNs, groupMembers, orgMembers)
+	if diff := cmp.Diff([]int32{1}, add); diff != "" {
+		t.Fatalf("add mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int32{3}, remove); diff != "" {
+		t.Fatalf("remove mismatch (-want +got):\n%s", diff)
+	}
+}
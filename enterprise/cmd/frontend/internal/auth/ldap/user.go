@@ -0,0 +1,59 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// getOrCreateUser gets or creates a user account based on the LDAP entry of an authenticated
+// user. It returns the authenticated actor if successful; otherwise it returns a friendly error
+// message (safeErrMsg) that is safe to display to users, and a non-nil err with lower-level
+// error details.
+//
+// 🚨 SECURITY: Existing users are only looked up by their LDAP external account or by the
+// verified email of the entry, never by username. Otherwise, an entry without an email would
+// be linked to any existing user with the same username, such as a builtin site admin.
+func getOrCreateUser(ctx context.Context, db dbutil.DB, p *provider, entry *eldap.Entry) (_ *actor.Actor, safeErrMsg string, err error) {
+	usernameAttr, emailAttr, displayNameAttr := p.attributes()
+
+	login := entry.Value(usernameAttr)
+	email := entry.Value(emailAttr)
+	displayName := entry.Value(displayNameAttr)
+	if displayName == "" {
+		displayName = login
+	}
+	login, err = auth.NormalizeUsername(login)
+	if err != nil {
+		return nil, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", login), err
+	}
+
+	var data extsvc.AccountData
+	data.SetAccountData(entry)
+
+	userID, safeErrMsg, err := auth.GetAndSaveUser(ctx, db, auth.GetAndSaveUserOp{
+		UserProps: database.NewUser{
+			Username:        login,
+			Email:           email,
+			EmailIsVerified: email != "", // the directory is trusted to hold verified email addresses
+			DisplayName:     displayName,
+		},
+		ExternalAccount: extsvc.AccountSpec{
+			ServiceType: providerType,
+			ServiceID:   p.serviceID(),
+			AccountID:   eldap.NormalizeDN(entry.DN),
+		},
+		ExternalAccountData: data,
+		CreateIfNotExist:    p.allowSignup(),
+	})
+	if err != nil {
+		return nil, safeErrMsg, err
+	}
+	return actor.FromUser(userID), "", nil
+}
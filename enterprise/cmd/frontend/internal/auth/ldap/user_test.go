@@ -0,0 +1,93 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	eldap "github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func init() {
+	dbtesting.DBNameSuffix = "ldapauth"
+}
+
+func TestGetOrCreateUser(t *testing.T) {
+	db := dbtesting.GetDB(t)
+	ctx := context.Background()
+
+	// A builtin site admin, whose username collides with the LDAP entry without an email.
+	admin, err := database.GlobalUsers.Create(ctx, database.NewUser{Username: "admin", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.GlobalUsers.SetIsSiteAdmin(ctx, admin.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	alice, err := database.GlobalUsers.Create(ctx, database.NewUser{Username: "alice2", Email: "alice@example.com", EmailIsVerified: true, Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := newTestProvider("ldap://ldap.example.com")
+
+	t.Run("colliding username without email", func(t *testing.T) {
+		entry := &eldap.Entry{
+			DN:         "uid=admin,ou=People,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"admin"}},
+		}
+		actr, _, err := getOrCreateUser(ctx, db, p, entry)
+		if err == nil {
+			t.Fatalf("want error but got user %d", actr.UID)
+		}
+		if !database.IsUsernameExists(err) {
+			t.Fatalf("want username exists error but got %v", err)
+		}
+
+		accounts, err := database.ExternalAccounts(db).List(ctx, database.ExternalAccountsListOptions{UserID: admin.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts) != 0 {
+			t.Fatalf("want no external accounts of site admin but got %d", len(accounts))
+		}
+	})
+
+	t.Run("verified email", func(t *testing.T) {
+		entry := &eldap.Entry{
+			DN:         "uid=alice,ou=People,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"alice"}, "mail": {"alice@example.com"}},
+		}
+		actr, _, err := getOrCreateUser(ctx, db, p, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actr.UID != alice.ID {
+			t.Fatalf("want user %d but got %d", alice.ID, actr.UID)
+		}
+	})
+
+	t.Run("new user without email", func(t *testing.T) {
+		entry := &eldap.Entry{
+			DN:         "uid=bob,ou=People,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"bob"}},
+		}
+		actr, _, err := getOrCreateUser(ctx, db, p, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actr.UID == admin.ID || actr.UID == alice.ID {
+			t.Fatalf("want new user but got existing user %d", actr.UID)
+		}
+
+		// Signing in again finds the user by their external account.
+		again, _, err := getOrCreateUser(ctx, db, p, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.UID != actr.UID {
+			t.Fatalf("want user %d but got %d", actr.UID, again.UID)
+		}
+	})
+}
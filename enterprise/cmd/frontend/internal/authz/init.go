@@ -33,6 +33,7 @@ var clock = timeutil.Now
 func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigration.Runner, enterpriseServices *enterprise.Services) error {
 	database.ExternalServices = edb.NewExternalServicesStore
 	database.GlobalAuthz = edb.NewAuthzStore(db, clock)
+	authz.DefaultSubRepoPermsChecker = authz.NewSubRepoPermsClient(edb.SubRepoPerms(db))
 
 	extsvcStore := database.ExternalServices(db)
 
@@ -77,6 +78,10 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 				authzNames = append(authzNames, "GitLab")
 			case extsvc.TypeBitbucketServer:
 				authzNames = append(authzNames, "Bitbucket Server")
+			case extsvc.TypeBitbucketCloud:
+				authzNames = append(authzNames, "Bitbucket Cloud")
+			case extsvc.TypeGitolite:
+				authzNames = append(authzNames, "Gitolite")
 			default:
 				authzNames = append(authzNames, t)
 			}
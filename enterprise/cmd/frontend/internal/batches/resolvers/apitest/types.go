@@ -216,6 +216,8 @@ type BatchSpec struct {
 	// NEW
 	SupersedingBatchSpec *BatchSpec
 	AppliesToBatchChange BatchChange
+
+	WorkspaceResolution *BatchSpecWorkspaceResolution
 }
 
 // ChangesetSpecDelta is the delta between two ChangesetSpecs describing the same Changeset.
@@ -381,3 +383,23 @@ type BatchSpecExecution struct {
 	Initiator    User
 	Namespace    UserOrg
 }
+
+type BatchSpecWorkspaceResolution struct {
+	State          string
+	FailureMessage string
+	StartedAt      graphqlbackend.DateTime
+	FinishedAt     graphqlbackend.DateTime
+	Workspaces     []BatchSpecWorkspace
+}
+
+type BatchSpecWorkspace struct {
+	ID             string
+	Repository     Repository
+	Branch         string
+	Commit         string
+	Path           string
+	State          string
+	FailureMessage string
+	StartedAt      graphqlbackend.DateTime
+	FinishedAt     graphqlbackend.DateTime
+}
@@ -161,6 +161,38 @@ func (r *batchChangeResolver) ChangesetsStats(ctx context.Context) (graphqlbacke
 	return &changesetsStatsResolver{stats: stats}, nil
 }
 
+func (r *batchChangeResolver) ChecksBreakdown(ctx context.Context) ([]graphqlbackend.ChangesetCheckBreakdownResolver, error) {
+	batchSpec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: r.batchChange.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+	var required []string
+	if batchSpec.Spec.ChangesetTemplate != nil {
+		required = batchSpec.Spec.ChangesetTemplate.RequiredChecks
+	}
+
+	publishedState := btypes.ChangesetPublicationStatePublished
+	cs, _, err := r.store.ListChangesets(ctx, store.ListChangesetsOpts{
+		BatchChangeID:    r.batchChange.ID,
+		PublicationState: &publishedState,
+		ExternalStates: []btypes.ChangesetExternalState{
+			btypes.ChangesetExternalStateOpen,
+			btypes.ChangesetExternalStateDraft,
+		},
+		EnforceAuthz: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := computeChecksBreakdown(cs, required)
+	resolvers := make([]graphqlbackend.ChangesetCheckBreakdownResolver, 0, len(breakdown))
+	for _, b := range breakdown {
+		resolvers = append(resolvers, &changesetCheckBreakdownResolver{breakdown: b})
+	}
+	return resolvers, nil
+}
+
 func (r *batchChangeResolver) Changesets(
 	ctx context.Context,
 	args *graphqlbackend.ListChangesetsArgs,
@@ -0,0 +1,168 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+const batchSpecTemplateIDKind = "BatchSpecTemplate"
+
+func marshalBatchSpecTemplateID(id int64) graphql.ID {
+	return relay.MarshalID(batchSpecTemplateIDKind, id)
+}
+
+func unmarshalBatchSpecTemplateID(id graphql.ID) (batchSpecTemplateID int64, err error) {
+	err = relay.UnmarshalSpec(id, &batchSpecTemplateID)
+	return
+}
+
+var _ graphqlbackend.BatchSpecTemplateResolver = &batchSpecTemplateResolver{}
+
+type batchSpecTemplateResolver struct {
+	store    *store.Store
+	template *btypes.BatchSpecTemplate
+}
+
+func (r *batchSpecTemplateResolver) ID() graphql.ID {
+	return marshalBatchSpecTemplateID(r.template.ID)
+}
+
+func (r *batchSpecTemplateResolver) Name() string {
+	return r.template.Name
+}
+
+func (r *batchSpecTemplateResolver) Description() string {
+	return r.template.Description
+}
+
+func (r *batchSpecTemplateResolver) Template() string {
+	return r.template.RawTemplate
+}
+
+func (r *batchSpecTemplateResolver) Inputs() []graphqlbackend.BatchSpecTemplateInputResolver {
+	resolvers := make([]graphqlbackend.BatchSpecTemplateInputResolver, 0, len(r.template.Inputs))
+	for _, in := range r.template.Inputs {
+		resolvers = append(resolvers, &batchSpecTemplateInputResolver{input: in})
+	}
+	return resolvers
+}
+
+func (r *batchSpecTemplateResolver) Namespace(ctx context.Context) (*graphqlbackend.NamespaceResolver, error) {
+	if r.template.SiteWide() {
+		return nil, nil
+	}
+
+	var (
+		namespace graphqlbackend.NamespaceResolver
+		err       error
+	)
+	if r.template.NamespaceUserID != 0 {
+		namespace.Namespace, err = graphqlbackend.UserByIDInt32(
+			ctx,
+			r.store.DB(),
+			r.template.NamespaceUserID,
+		)
+		return &namespace, err
+	}
+	namespace.Namespace, err = graphqlbackend.OrgByIDInt32(
+		ctx,
+		r.store.DB(),
+		r.template.NamespaceOrgID,
+	)
+	return &namespace, err
+}
+
+func (r *batchSpecTemplateResolver) Creator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.template.CreatorID == 0 {
+		return nil, nil
+	}
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.store.DB(), r.template.CreatorID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *batchSpecTemplateResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
+	svc := service.New(r.store)
+	return svc.CanAdministerBatchSpecTemplate(ctx, r.template)
+}
+
+func (r *batchSpecTemplateResolver) CreatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.template.CreatedAt}
+}
+
+func (r *batchSpecTemplateResolver) UpdatedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.template.UpdatedAt}
+}
+
+var _ graphqlbackend.BatchSpecTemplateInputResolver = &batchSpecTemplateInputResolver{}
+
+type batchSpecTemplateInputResolver struct {
+	input batcheslib.TemplateInput
+}
+
+func (r *batchSpecTemplateInputResolver) Name() string {
+	return r.input.Name
+}
+
+func (r *batchSpecTemplateInputResolver) Description() string {
+	return r.input.Description
+}
+
+func (r *batchSpecTemplateInputResolver) Type() string {
+	return strings.ToUpper(string(r.input.Type))
+}
+
+func (r *batchSpecTemplateInputResolver) DefaultValue() *graphqlbackend.JSONValue {
+	if r.input.Default == nil {
+		return nil
+	}
+	return &graphqlbackend.JSONValue{Value: r.input.Default}
+}
+
+func (r *batchSpecTemplateInputResolver) Required() bool {
+	return r.input.Required()
+}
+
+// unmarshalBatchSpecTemplateInputs converts the GraphQL input definitions into
+// the inputs stored with a batch spec template.
+func unmarshalBatchSpecTemplateInputs(defs []graphqlbackend.BatchSpecTemplateInputDefinition) []batcheslib.TemplateInput {
+	inputs := make([]batcheslib.TemplateInput, 0, len(defs))
+	for _, def := range defs {
+		in := batcheslib.TemplateInput{
+			Name:        def.Name,
+			Description: def.Description,
+			Type:        batcheslib.TemplateInputType(strings.ToLower(def.Type)),
+		}
+		if def.DefaultValue != nil {
+			in.Default = def.DefaultValue.Value
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// unmarshalBatchSpecTemplateValues converts the JSON object of input values
+// given when instantiating a batch spec template.
+func unmarshalBatchSpecTemplateValues(v *graphqlbackend.JSONValue) (map[string]interface{}, error) {
+	if v == nil || v.Value == nil {
+		return nil, nil
+	}
+	values, ok := v.Value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("inputs must be an object mapping input names to values")
+	}
+	return values, nil
+}
@@ -0,0 +1,172 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const batchSpecWorkspaceIDKind = "BatchSpecWorkspace"
+
+func marshalBatchSpecWorkspaceID(id int64) graphql.ID {
+	return relay.MarshalID(batchSpecWorkspaceIDKind, id)
+}
+
+func unmarshalBatchSpecWorkspaceID(id graphql.ID) (batchSpecWorkspaceID int64, err error) {
+	err = relay.UnmarshalSpec(id, &batchSpecWorkspaceID)
+	return
+}
+
+type batchSpecWorkspaceResolutionResolver struct {
+	store *store.Store
+	job   *btypes.BatchSpecResolutionJob
+}
+
+// Type guard.
+var _ graphqlbackend.BatchSpecWorkspaceResolutionResolver = &batchSpecWorkspaceResolutionResolver{}
+
+func (r *batchSpecWorkspaceResolutionResolver) State() string {
+	return r.job.State.ToGraphQL()
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) FailureMessage() *string {
+	return r.job.FailureMessage
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) StartedAt() *graphqlbackend.DateTime {
+	if r.job.StartedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.StartedAt}
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.job.FinishedAt.IsZero() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.job.FinishedAt}
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) Workspaces(ctx context.Context) ([]graphqlbackend.BatchSpecWorkspaceResolver, error) {
+	if r.job.State != btypes.BatchSpecResolutionJobStateCompleted {
+		return []graphqlbackend.BatchSpecWorkspaceResolver{}, nil
+	}
+
+	workspaces, _, err := r.store.ListBatchSpecWorkspaces(ctx, store.ListBatchSpecWorkspacesOpts{BatchSpecID: r.job.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(workspaces))
+	for _, w := range workspaces {
+		repoIDs = append(repoIDs, w.RepoID)
+	}
+
+	// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under
+	// the hood and filters out repositories that the user doesn't have access
+	// to, so workspaces in such repositories are omitted.
+	reposByID, err := r.store.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.BatchSpecWorkspaceResolver, 0, len(workspaces))
+	for _, w := range workspaces {
+		repo, ok := reposByID[w.RepoID]
+		if !ok {
+			continue
+		}
+		resolvers = append(resolvers, &batchSpecWorkspaceResolver{store: r.store, workspace: w, repo: repo})
+	}
+	return resolvers, nil
+}
+
+type batchSpecWorkspaceResolver struct {
+	store     *store.Store
+	workspace *btypes.BatchSpecWorkspace
+	repo      *types.Repo
+
+	// We cache the newest execution job on the resolver, since it's accessed
+	// more than once.
+	jobOnce sync.Once
+	job     *btypes.BatchSpecWorkspaceExecutionJob
+	jobErr  error
+}
+
+// Type guard.
+var _ graphqlbackend.BatchSpecWorkspaceResolver = &batchSpecWorkspaceResolver{}
+
+func (r *batchSpecWorkspaceResolver) ID() graphql.ID {
+	return marshalBatchSpecWorkspaceID(r.workspace.ID)
+}
+
+func (r *batchSpecWorkspaceResolver) Repository(ctx context.Context) (*graphqlbackend.RepositoryResolver, error) {
+	return graphqlbackend.NewRepositoryResolver(r.store.DB(), r.repo), nil
+}
+
+func (r *batchSpecWorkspaceResolver) Branch() string {
+	return r.workspace.Branch
+}
+
+func (r *batchSpecWorkspaceResolver) Commit() string {
+	return r.workspace.Commit
+}
+
+func (r *batchSpecWorkspaceResolver) Path() string {
+	return r.workspace.Path
+}
+
+func (r *batchSpecWorkspaceResolver) computeJob(ctx context.Context) (*btypes.BatchSpecWorkspaceExecutionJob, error) {
+	r.jobOnce.Do(func() {
+		r.job, r.jobErr = r.store.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{
+			BatchSpecWorkspaceID: r.workspace.ID,
+		})
+	})
+	return r.job, r.jobErr
+}
+
+func (r *batchSpecWorkspaceResolver) State(ctx context.Context) (string, error) {
+	job, err := r.computeJob(ctx)
+	if err != nil {
+		return "", err
+	}
+	return job.State.ToGraphQL(), nil
+}
+
+func (r *batchSpecWorkspaceResolver) FailureMessage(ctx context.Context) (*string, error) {
+	job, err := r.computeJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return job.FailureMessage, nil
+}
+
+func (r *batchSpecWorkspaceResolver) StartedAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
+	job, err := r.computeJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if job.StartedAt.IsZero() {
+		return nil, nil
+	}
+	return &graphqlbackend.DateTime{Time: job.StartedAt}, nil
+}
+
+func (r *batchSpecWorkspaceResolver) FinishedAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
+	job, err := r.computeJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if job.FinishedAt.IsZero() {
+		return nil, nil
+	}
+	return &graphqlbackend.DateTime{Time: job.FinishedAt}, nil
+}
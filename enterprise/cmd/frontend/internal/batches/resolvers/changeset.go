@@ -384,6 +384,18 @@ func (r *changesetResolver) CheckState() *string {
 	return &state
 }
 
+func (r *changesetResolver) Checks() []graphqlbackend.ChangesetCheckResolver {
+	resolvers := make([]graphqlbackend.ChangesetCheckResolver, 0, len(r.changeset.ExternalChecks))
+	if !r.changeset.Published() {
+		return resolvers
+	}
+
+	for _, check := range r.changeset.ExternalChecks {
+		resolvers = append(resolvers, &changesetCheckResolver{check: check})
+	}
+	return resolvers
+}
+
 func (r *changesetResolver) Error() *string { return r.changeset.FailureMessage }
 
 func (r *changesetResolver) SyncerError() *string { return r.changeset.SyncErrorMessage }
@@ -0,0 +1,107 @@
+package resolvers
+
+import (
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+var _ graphqlbackend.ChangesetCheckResolver = &changesetCheckResolver{}
+
+type changesetCheckResolver struct {
+	check btypes.ChangesetCheck
+}
+
+func (r *changesetCheckResolver) Name() string {
+	return r.check.Name
+}
+
+func (r *changesetCheckResolver) State() *string {
+	if r.check.State == btypes.ChangesetCheckStateUnknown {
+		return nil
+	}
+	state := string(r.check.State)
+	return &state
+}
+
+func (r *changesetCheckResolver) URL() *string {
+	if r.check.URL == "" {
+		return nil
+	}
+	return &r.check.URL
+}
+
+var _ graphqlbackend.ChangesetCheckBreakdownResolver = &changesetCheckBreakdownResolver{}
+
+type changesetCheckBreakdownResolver struct {
+	breakdown changesetCheckBreakdown
+}
+
+func (r *changesetCheckBreakdownResolver) Name() string   { return r.breakdown.Name }
+func (r *changesetCheckBreakdownResolver) Required() bool { return r.breakdown.Required }
+func (r *changesetCheckBreakdownResolver) Passed() int32  { return r.breakdown.Passed }
+func (r *changesetCheckBreakdownResolver) Failed() int32  { return r.breakdown.Failed }
+func (r *changesetCheckBreakdownResolver) Pending() int32 { return r.breakdown.Pending }
+func (r *changesetCheckBreakdownResolver) Missing() int32 { return r.breakdown.Missing }
+
+type changesetCheckBreakdown struct {
+	Name     string
+	Required bool
+
+	Passed  int32
+	Failed  int32
+	Pending int32
+	Missing int32
+}
+
+// computeChecksBreakdown counts the states of every check reported on the
+// given changesets, plus the required checks, which are included even if no
+// changeset reported them. The result is sorted by name.
+func computeChecksBreakdown(cs btypes.Changesets, required []string) []changesetCheckBreakdown {
+	byName := make(map[string]*changesetCheckBreakdown)
+	get := func(name string) *changesetCheckBreakdown {
+		b, ok := byName[name]
+		if !ok {
+			b = &changesetCheckBreakdown{Name: name}
+			byName[name] = b
+		}
+		return b
+	}
+
+	for _, name := range required {
+		get(name).Required = true
+	}
+	for _, c := range cs {
+		for _, check := range c.ExternalChecks {
+			get(check.Name)
+		}
+	}
+
+	for _, c := range cs {
+		states := make(map[string]btypes.ChangesetCheckState, len(c.ExternalChecks))
+		for _, check := range c.ExternalChecks {
+			states[check.Name] = check.State
+		}
+
+		for name, b := range byName {
+			switch states[name] {
+			case btypes.ChangesetCheckStatePassed:
+				b.Passed++
+			case btypes.ChangesetCheckStateFailed:
+				b.Failed++
+			case btypes.ChangesetCheckStatePending:
+				b.Pending++
+			default:
+				b.Missing++
+			}
+		}
+	}
+
+	breakdown := make([]changesetCheckBreakdown, 0, len(byName))
+	for _, b := range byName {
+		breakdown = append(breakdown, *b)
+	}
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Name < breakdown[j].Name })
+	return breakdown
+}
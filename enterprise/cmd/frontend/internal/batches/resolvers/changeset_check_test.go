@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func TestComputeChecksBreakdown(t *testing.T) {
+	cs := btypes.Changesets{
+		{ExternalChecks: []btypes.ChangesetCheck{
+			{Name: "build", State: btypes.ChangesetCheckStatePassed},
+			{Name: "lint", State: btypes.ChangesetCheckStateFailed},
+		}},
+		{ExternalChecks: []btypes.ChangesetCheck{
+			{Name: "build", State: btypes.ChangesetCheckStatePending},
+		}},
+		{},
+	}
+
+	have := computeChecksBreakdown(cs, []string{"build", "security"})
+	want := []changesetCheckBreakdown{
+		{Name: "build", Required: true, Passed: 1, Pending: 1, Missing: 1},
+		{Name: "lint", Failed: 1, Missing: 2},
+		{Name: "security", Required: true, Missing: 3},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("wrong breakdown (-want +have):\n%s", diff)
+	}
+}
@@ -135,6 +135,12 @@ func (r *Resolver) NodeResolvers() map[string]graphqlbackend.NodeByIDFunc {
 		batchSpecExecutionIDKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
 			return r.batchSpecExecutionByID(ctx, id)
 		},
+		batchSpecTemplateIDKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
+			return r.batchSpecTemplateByID(ctx, id)
+		},
+		batchSpecWorkspaceIDKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
+			return r.batchSpecWorkspaceByID(ctx, id)
+		},
 	}
 }
 
@@ -376,6 +382,79 @@ func (r *Resolver) batchSpecExecutionByID(ctx context.Context, id graphql.ID) (g
 	return &batchSpecExecutionResolver{store: r.store, exec: spec}, nil
 }
 
+func (r *Resolver) batchSpecWorkspaceByID(ctx context.Context, id graphql.ID) (graphqlbackend.BatchSpecWorkspaceResolver, error) {
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := unmarshalBatchSpecWorkspaceID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceID == 0 {
+		return nil, nil
+	}
+
+	workspace, err := r.store.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: workspaceID})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	spec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Unlike batch specs, workspaces have sequential IDs, so only
+	// the creator of the batch spec and site-admins can look them up.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.store.DB(), spec.UserID); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: database.Repos.Get uses the authzFilter under the hood and
+	// returns an error if the user doesn't have access to the repository.
+	repo, err := r.store.Repos().Get(ctx, workspace.RepoID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &batchSpecWorkspaceResolver{store: r.store, workspace: workspace, repo: repo}, nil
+}
+
+func (r *Resolver) batchSpecTemplateByID(ctx context.Context, id graphql.ID) (graphqlbackend.BatchSpecTemplateResolver, error) {
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalBatchSpecTemplateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateID == 0 {
+		return nil, nil
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: GetBatchSpecTemplate checks whether the current user can see
+	// the template.
+	template, err := svc.GetBatchSpecTemplate(ctx, templateID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &batchSpecTemplateResolver{store: r.store, template: template}, nil
+}
+
 func (r *Resolver) CreateBatchChange(ctx context.Context, args *graphqlbackend.CreateBatchChangeArgs) (graphqlbackend.BatchChangeResolver, error) {
 	var err error
 	tr, _ := trace.New(ctx, "Resolver.CreateBatchChange", fmt.Sprintf("BatchSpec %s", args.BatchSpec))
@@ -1500,6 +1579,278 @@ func (r *Resolver) CancelBatchSpecExecution(ctx context.Context, args *graphqlba
 	return r.batchSpecExecutionByID(ctx, marshalBatchSpecExecutionRandID(exec.RandID))
 }
 
+func (r *Resolver) ResolveBatchSpecWorkspaces(ctx context.Context, args *graphqlbackend.ResolveBatchSpecWorkspacesArgs) (_ graphqlbackend.BatchSpecWorkspaceResolutionResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ResolveBatchSpecWorkspaces", fmt.Sprintf("BatchSpec: %q", args.BatchSpec))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Check that the requesting user is admin.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	randID, err := unmarshalBatchSpecID(args.BatchSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if randID == "" {
+		return nil, ErrIDIsZero{}
+	}
+
+	spec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{RandID: randID})
+	if err != nil {
+		return nil, err
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: EnqueueBatchSpecResolution checks that the requesting user
+	// created the batch spec or is a site-admin.
+	job, err := svc.EnqueueBatchSpecResolution(ctx, spec.ID, service.ResolveWorkspacesForBatchSpecOpts{
+		AllowUnsupported: args.AllowUnsupported,
+		AllowIgnored:     args.AllowIgnored,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSpecWorkspaceResolutionResolver{store: r.store, job: job}, nil
+}
+
+func (r *Resolver) RetryBatchSpecWorkspaceExecution(ctx context.Context, args *graphqlbackend.RetryBatchSpecWorkspaceExecutionArgs) (_ graphqlbackend.BatchSpecWorkspaceResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.RetryBatchSpecWorkspaceExecution", fmt.Sprintf("BatchSpecWorkspace: %q", args.BatchSpecWorkspace))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Check that the requesting user is admin.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := unmarshalBatchSpecWorkspaceID(args.BatchSpecWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: RetryBatchSpecWorkspaceExecution checks that the requesting
+	// user created the batch spec or is a site-admin.
+	if _, err := svc.RetryBatchSpecWorkspaceExecution(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return r.batchSpecWorkspaceByID(ctx, marshalBatchSpecWorkspaceID(workspaceID))
+}
+
+func (r *Resolver) CreateBatchSpecTemplate(ctx context.Context, args *graphqlbackend.CreateBatchSpecTemplateArgs) (_ graphqlbackend.BatchSpecTemplateResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.CreateBatchSpecTemplate", fmt.Sprintf("Name: %q", args.Name))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesCreateAccess(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	opts := service.CreateBatchSpecTemplateOpts{
+		Name:        args.Name,
+		Description: args.Description,
+		RawTemplate: args.Template,
+		Inputs:      unmarshalBatchSpecTemplateInputs(args.Inputs),
+	}
+
+	if args.Namespace != nil {
+		err = graphqlbackend.UnmarshalNamespaceID(*args.Namespace, &opts.NamespaceUserID, &opts.NamespaceOrgID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: CreateBatchSpecTemplate checks whether the current user can
+	// create templates in the namespace.
+	template, err := svc.CreateBatchSpecTemplate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSpecTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *Resolver) UpdateBatchSpecTemplate(ctx context.Context, args *graphqlbackend.UpdateBatchSpecTemplateArgs) (_ graphqlbackend.BatchSpecTemplateResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.UpdateBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate: %q", args.BatchSpecTemplate))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesCreateAccess(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalBatchSpecTemplateID(args.BatchSpecTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: UpdateBatchSpecTemplate checks whether the current user is
+	// authorized.
+	template, err := svc.UpdateBatchSpecTemplate(ctx, service.UpdateBatchSpecTemplateOpts{
+		ID:          templateID,
+		Name:        args.Name,
+		Description: args.Description,
+		RawTemplate: args.Template,
+		Inputs:      unmarshalBatchSpecTemplateInputs(args.Inputs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSpecTemplateResolver{store: r.store, template: template}, nil
+}
+
+func (r *Resolver) DeleteBatchSpecTemplate(ctx context.Context, args *graphqlbackend.DeleteBatchSpecTemplateArgs) (_ *graphqlbackend.EmptyResponse, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.DeleteBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate: %q", args.BatchSpecTemplate))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalBatchSpecTemplateID(args.BatchSpecTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: DeleteBatchSpecTemplate checks whether the current user is
+	// authorized.
+	if err := svc.DeleteBatchSpecTemplate(ctx, templateID); err != nil {
+		return nil, err
+	}
+
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) InstantiateBatchSpecTemplate(ctx context.Context, args *graphqlbackend.InstantiateBatchSpecTemplateArgs) (_ graphqlbackend.BatchSpecResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.InstantiateBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate: %q, Namespace: %q", args.BatchSpecTemplate, args.Namespace))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesCreateAccess(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	templateID, err := unmarshalBatchSpecTemplateID(args.BatchSpecTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	opts := service.InstantiateBatchSpecTemplateOpts{TemplateID: templateID}
+
+	err = graphqlbackend.UnmarshalNamespaceID(args.Namespace, &opts.NamespaceUserID, &opts.NamespaceOrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Values, err = unmarshalBatchSpecTemplateValues(args.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: InstantiateBatchSpecTemplate checks whether the current user
+	// can see the template and has access to the namespace.
+	batchSpec, err := svc.InstantiateBatchSpecTemplate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	eventArg := &batchSpecCreatedArg{}
+	if err := logBackendEvent(ctx, r.store.DB(), "BatchSpecCreated", eventArg, eventArg); err != nil {
+		return nil, err
+	}
+
+	return &batchSpecResolver{store: r.store, batchSpec: batchSpec}, nil
+}
+
+func (r *Resolver) BatchSpecTemplates(ctx context.Context, args *graphqlbackend.ListBatchSpecTemplatesArgs) (graphqlbackend.BatchSpecTemplateConnectionResolver, error) {
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	if err := validateFirstParamDefaults(args.First); err != nil {
+		return nil, err
+	}
+	opts := store.ListBatchSpecTemplatesOpts{
+		LimitOpts: store.LimitOpts{Limit: int(args.First)},
+	}
+	if args.After != nil {
+		cursor, err := strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cursor = cursor
+	}
+
+	if args.Namespace != nil {
+		err := graphqlbackend.UnmarshalNamespaceID(*args.Namespace, &opts.NamespaceUserID, &opts.NamespaceOrgID)
+		if err != nil {
+			return nil, err
+		}
+		svc := service.New(r.store)
+		// 🚨 SECURITY: Check that the requesting user has access to the namespace.
+		if err := svc.CheckNamespaceAccess(ctx, opts.NamespaceUserID, opts.NamespaceOrgID); err != nil {
+			return nil, err
+		}
+	} else if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		// 🚨 SECURITY: Only return the templates the current user can see.
+		opts.AccessibleToUserID = a.UID
+	} else {
+		opts.OnlySiteWide = true
+	}
+
+	return &batchSpecTemplateConnectionResolver{
+		store: r.store,
+		opts:  opts,
+	}, nil
+}
+
 func parseBatchChangeState(s *string) (btypes.BatchChangeState, error) {
 	if s == nil {
 		return btypes.BatchChangeStateAny, nil
@@ -11,9 +11,11 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/batch-change-utils/overridable"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/batches/resolvers/apitest"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/search"
@@ -55,6 +57,7 @@ func TestNullIDResilience(t *testing.T) {
 		marshalBatchChangesCredentialID(0, false),
 		marshalBatchChangesCredentialID(0, true),
 		marshalBulkOperationID(""),
+		marshalBatchSpecWorkspaceID(0),
 	}
 
 	for _, id := range ids {
@@ -92,6 +95,8 @@ func TestNullIDResilience(t *testing.T) {
 		fmt.Sprintf(`mutation { publishChangesets(batchChange: %q, changesets: []) { id } }`, marshalBatchChangeID(0)),
 		fmt.Sprintf(`mutation { publishChangesets(batchChange: %q, changesets: [%q]) { id } }`, marshalBatchChangeID(1), marshalChangesetID(0)),
 		fmt.Sprintf(`mutation { cancelBatchSpecExecution(batchSpecExecution: %q) { id } }`, marshalBatchSpecExecutionRandID("")),
+		fmt.Sprintf(`mutation { resolveBatchSpecWorkspaces(batchSpec: %q) { state } }`, marshalBatchSpecRandID("")),
+		fmt.Sprintf(`mutation { retryBatchSpecWorkspaceExecution(batchSpecWorkspace: %q) { id } }`, marshalBatchSpecWorkspaceID(0)),
 	}
 
 	for _, m := range mutations {
@@ -1668,6 +1673,215 @@ mutation($spec: String!, $namespace: ID) {
 }
 `
 
+func TestResolver_ResolveBatchSpecWorkspaces(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	cstore := store.New(db, &observation.TestContext, nil)
+
+	adminID := ct.CreateTestUser(t, db, true).ID
+	adminCtx := actor.WithActor(ctx, actor.FromUser(adminID))
+	userID := ct.CreateTestUser(t, db, false).ID
+	userCtx := actor.WithActor(ctx, actor.FromUser(userID))
+
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 1)
+	spec := ct.CreateBatchSpec(t, ctx, cstore, "resolve-me", adminID)
+
+	r := &Resolver{store: cstore}
+	s, err := graphqlbackend.NewSchema(db, r, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := map[string]interface{}{"batchSpec": marshalBatchSpecRandID(spec.RandID)}
+
+	t.Run("non-admin", func(t *testing.T) {
+		var response struct{}
+		errs := apitest.Exec(userCtx, t, s, input, &response, mutationResolveBatchSpecWorkspaces)
+		if len(errs) != 1 || errs[0].Message != backend.ErrMustBeSiteAdmin.Error() {
+			t.Fatalf("expected site admin error, got %+v", errs)
+		}
+	})
+
+	t.Run("enqueue", func(t *testing.T) {
+		var response struct {
+			ResolveBatchSpecWorkspaces apitest.BatchSpecWorkspaceResolution
+		}
+		apitest.MustExec(adminCtx, t, s, input, &response, mutationResolveBatchSpecWorkspaces)
+
+		want := apitest.BatchSpecWorkspaceResolution{
+			State:      "QUEUED",
+			Workspaces: []apitest.BatchSpecWorkspace{},
+		}
+		if diff := cmp.Diff(want, response.ResolveBatchSpecWorkspaces); diff != "" {
+			t.Fatalf("unexpected response (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		var response struct{}
+		errs := apitest.Exec(adminCtx, t, s, input, &response, mutationResolveBatchSpecWorkspaces)
+		if len(errs) != 1 || errs[0].Message != service.ErrBatchSpecAlreadyResolved.Error() {
+			t.Fatalf("expected already resolved error, got %+v", errs)
+		}
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		ws := &btypes.BatchSpecWorkspace{
+			BatchSpecID: spec.ID,
+			RepoID:      repos[0].ID,
+			Branch:      "refs/heads/main",
+			Commit:      "d34db33f",
+			Path:        "a/b",
+		}
+		if err := cstore.CreateBatchSpecWorkspace(ctx, ws); err != nil {
+			t.Fatal(err)
+		}
+		if err := cstore.CreateBatchSpecWorkspaceExecutionJobs(ctx, ws.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := cstore.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_resolution_jobs SET state = 'completed' WHERE batch_spec_id = %s", spec.ID)); err != nil {
+			t.Fatal(err)
+		}
+
+		var response struct{ Node apitest.BatchSpec }
+		apitest.MustExec(adminCtx, t, s, input, &response, queryBatchSpecWorkspaceResolution)
+
+		want := &apitest.BatchSpecWorkspaceResolution{
+			State: "COMPLETED",
+			Workspaces: []apitest.BatchSpecWorkspace{{
+				ID: string(marshalBatchSpecWorkspaceID(ws.ID)),
+				Repository: apitest.Repository{
+					ID:   string(graphqlbackend.MarshalRepositoryID(repos[0].ID)),
+					Name: string(repos[0].Name),
+				},
+				Branch: "refs/heads/main",
+				Commit: "d34db33f",
+				Path:   "a/b",
+				State:  "QUEUED",
+			}},
+		}
+		if diff := cmp.Diff(want, response.Node.WorkspaceResolution); diff != "" {
+			t.Fatalf("unexpected response (-want +got):\n%s", diff)
+		}
+	})
+}
+
+const mutationResolveBatchSpecWorkspaces = `
+mutation($batchSpec: ID!) {
+	resolveBatchSpecWorkspaces(batchSpec: $batchSpec, allowIgnored: true) {
+		state
+		failureMessage
+		startedAt
+		finishedAt
+		workspaces { id }
+	}
+}
+`
+
+const queryBatchSpecWorkspaceResolution = `
+query($batchSpec: ID!) {
+	node(id: $batchSpec) {
+		... on BatchSpec {
+			workspaceResolution {
+				state
+				failureMessage
+				startedAt
+				finishedAt
+				workspaces {
+					id
+					repository { id name }
+					branch
+					commit
+					path
+					state
+					failureMessage
+					startedAt
+					finishedAt
+				}
+			}
+		}
+	}
+}
+`
+
+func TestResolver_RetryBatchSpecWorkspaceExecution(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	cstore := store.New(db, &observation.TestContext, nil)
+
+	adminID := ct.CreateTestUser(t, db, true).ID
+	adminCtx := actor.WithActor(ctx, actor.FromUser(adminID))
+
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 1)
+	spec := ct.CreateBatchSpec(t, ctx, cstore, "retry-me", adminID)
+
+	ws := &btypes.BatchSpecWorkspace{
+		BatchSpecID: spec.ID,
+		RepoID:      repos[0].ID,
+		Branch:      "refs/heads/main",
+		Commit:      "d34db33f",
+	}
+	if err := cstore.CreateBatchSpecWorkspace(ctx, ws); err != nil {
+		t.Fatal(err)
+	}
+	if err := cstore.CreateBatchSpecWorkspaceExecutionJobs(ctx, ws.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Resolver{store: cstore}
+	s, err := graphqlbackend.NewSchema(db, r, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := map[string]interface{}{"batchSpecWorkspace": marshalBatchSpecWorkspaceID(ws.ID)}
+
+	t.Run("not failed", func(t *testing.T) {
+		var response struct{}
+		errs := apitest.Exec(adminCtx, t, s, input, &response, mutationRetryBatchSpecWorkspaceExecution)
+		if len(errs) != 1 || errs[0].Message != service.ErrBatchSpecWorkspaceNotRetryable.Error() {
+			t.Fatalf("expected not retryable error, got %+v", errs)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		if err := cstore.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_workspace_execution_jobs SET state = 'failed', failure_message = 'boom' WHERE batch_spec_workspace_id = %s", ws.ID)); err != nil {
+			t.Fatal(err)
+		}
+
+		var response struct {
+			RetryBatchSpecWorkspaceExecution apitest.BatchSpecWorkspace
+		}
+		apitest.MustExec(adminCtx, t, s, input, &response, mutationRetryBatchSpecWorkspaceExecution)
+
+		want := apitest.BatchSpecWorkspace{
+			ID:    string(marshalBatchSpecWorkspaceID(ws.ID)),
+			State: "QUEUED",
+		}
+		if diff := cmp.Diff(want, response.RetryBatchSpecWorkspaceExecution); diff != "" {
+			t.Fatalf("unexpected response (-want +got):\n%s", diff)
+		}
+	})
+}
+
+const mutationRetryBatchSpecWorkspaceExecution = `
+mutation($batchSpecWorkspace: ID!) {
+	retryBatchSpecWorkspaceExecution(batchSpecWorkspace: $batchSpecWorkspace) {
+		id
+		state
+		failureMessage
+	}
+}
+`
+
 func TestCloseChangesets(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -241,6 +241,9 @@ func (h *GitLabWebhook) handlePipelineEvent(ctx context.Context, esID string, ev
 		return errPipelineMissingMergeRequest
 	}
 
+	// The jobs of the pipeline are sent alongside it as builds.
+	event.Pipeline.Jobs = event.Builds
+
 	pr := gitlabToPR(&event.Project, event.MergeRequest)
 	if err := h.upsertChangesetEvent(ctx, esID, pr, &event.Pipeline); err != nil {
 		return errors.Wrap(err, "upserting changeset event")
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+	"github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
@@ -59,6 +60,7 @@ func newResolver(ctx context.Context, db dbutil.DB, observationContext *observat
 		services.dbStore,
 		services.lsifStore,
 		services.gitserverClient,
+		symbols.DefaultClient,
 		services.indexEnqueuer,
 		hunkCache,
 		observationContext,
@@ -0,0 +1,104 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const slowDocumentationSearchRequestThreshold = 2 * time.Second
+
+// documentationSearchOverfetchFactor is the factor by which we over-request search results
+// from the LSIF store. Results belonging to uploads that are no longer visible from the tip
+// of the default branch of their repository are discarded after the search.
+const documentationSearchOverfetchFactor = 3
+
+// DocumentationSearch returns the documentation of symbols matching the given query in the
+// given repositories. Only documentation of uploads visible from the tip of the default branch
+// of their repository is returned, so that results describe the current API of a repository
+// rather than all APIs it has ever had.
+func (r *resolver) DocumentationSearch(ctx context.Context, args search.DocumentationParameters) (_ []*result.DocumentationMatch, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "DocumentationSearch", r.operations.documentationSearch, slowDocumentationSearchRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.String("query", args.Query),
+			log.Int("numRepoIDs", len(args.RepoIDs)),
+			log.String("languages", strings.Join(args.Languages, ", ")),
+			log.String("kinds", strings.Join(args.Kinds, ", ")),
+			log.Int("limit", args.Limit),
+		},
+	})
+	defer endObservation()
+
+	if len(args.RepoIDs) == 0 || args.Limit <= 0 {
+		return nil, nil
+	}
+
+	repositoryIDs := make([]int, 0, len(args.RepoIDs))
+	for _, repoID := range args.RepoIDs {
+		repositoryIDs = append(repositoryIDs, int(repoID))
+	}
+
+	searchResults, err := r.lsifStore.DocumentationSearch(ctx, lsifstore.DocumentationSearchOptions{
+		Query:         args.Query,
+		RepositoryIDs: repositoryIDs,
+		Languages:     args.Languages,
+		Tags:          args.Kinds,
+		Limit:         args.Limit * documentationSearchOverfetchFactor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numSearchResults", len(searchResults)))
+
+	ids := make([]int, 0, len(searchResults))
+	for _, searchResult := range searchResults {
+		ids = append(ids, searchResult.DumpID)
+	}
+
+	dumps, err := r.dbStore.GetDumpsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpsByID := map[int]int{}
+	for i, dump := range dumps {
+		dumpsByID[dump.ID] = i
+	}
+
+	matches := make([]*result.DocumentationMatch, 0, len(searchResults))
+	for _, searchResult := range searchResults {
+		i, ok := dumpsByID[searchResult.DumpID]
+		if !ok || !dumps[i].VisibleAtTip {
+			continue
+		}
+
+		matches = append(matches, &result.DocumentationMatch{
+			Repo: types.RepoName{
+				ID:   api.RepoID(dumps[i].RepositoryID),
+				Name: api.RepoName(dumps[i].RepositoryName),
+			},
+			Commit:    api.CommitID(dumps[i].Commit),
+			PathID:    searchResult.PathID,
+			SearchKey: searchResult.SearchKey,
+			Label:     searchResult.Label,
+			Detail:    searchResult.Detail,
+			Tags:      searchResult.Tags,
+			Language:  searchResult.Language,
+		})
+		if len(matches) >= args.Limit {
+			break
+		}
+	}
+	traceLog(log.Int("numMatches", len(matches)))
+
+	return matches, nil
+}
@@ -0,0 +1,101 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestDocumentationSearch(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+
+	mockLSIFStore.DocumentationSearchFunc.SetDefaultReturn([]lsifstore.DocumentationSearchResult{
+		{DumpID: 50, RepositoryID: 42, Language: "go", PathID: "/#Router", SearchKey: "mux.Router", Label: "type Router", Tags: []string{"struct"}},
+		{DumpID: 51, RepositoryID: 42, Language: "go", PathID: "/#Route", SearchKey: "mux.Route", Label: "type Route", Tags: []string{"struct"}},
+		{DumpID: 52, RepositoryID: 43, Language: "go", PathID: "/#Router", SearchKey: "chi.Router", Label: "type Router", Tags: []string{"interface"}},
+		{DumpID: 53, RepositoryID: 44, Language: "go", PathID: "/#Router", SearchKey: "gin.Router", Label: "type Router", Tags: []string{"struct"}},
+	}, nil)
+	mockDBStore.GetDumpsByIDsFunc.SetDefaultReturn([]dbstore.Dump{
+		{ID: 50, RepositoryID: 42, RepositoryName: "github.com/gorilla/mux", Commit: "deadbeef", VisibleAtTip: true},
+		{ID: 51, RepositoryID: 42, RepositoryName: "github.com/gorilla/mux", Commit: "cafebabe", VisibleAtTip: false},
+		{ID: 52, RepositoryID: 43, RepositoryName: "github.com/go-chi/chi", Commit: "f00dcafe", VisibleAtTip: true},
+		{ID: 53, RepositoryID: 44, RepositoryName: "github.com/gin-gonic/gin", Commit: "beefcafe", VisibleAtTip: true},
+	}, nil)
+
+	resolver := newResolver(mockDBStore, mockLSIFStore, nil, nil, nil, nil, &observation.TestContext)
+	matches, err := resolver.DocumentationSearch(context.Background(), search.DocumentationParameters{
+		Query:     "router",
+		RepoIDs:   []api.RepoID{42, 43, 44},
+		Languages: []string{"go"},
+		Kinds:     []string{"struct", "interface"},
+		Limit:     2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error searching documentation: %s", err)
+	}
+
+	if history := mockLSIFStore.DocumentationSearchFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of documentation searches. want=%d have=%d", 1, len(history))
+	} else {
+		expectedOpts := lsifstore.DocumentationSearchOptions{
+			Query:         "router",
+			RepositoryIDs: []int{42, 43, 44},
+			Languages:     []string{"go"},
+			Tags:          []string{"struct", "interface"},
+			Limit:         2 * documentationSearchOverfetchFactor,
+		}
+		if diff := cmp.Diff(expectedOpts, history[0].Arg1); diff != "" {
+			t.Errorf("unexpected search options (-want +got):\n%s", diff)
+		}
+	}
+
+	expectedMatches := []*result.DocumentationMatch{
+		{
+			Repo:      types.RepoName{ID: 42, Name: "github.com/gorilla/mux"},
+			Commit:    "deadbeef",
+			PathID:    "/#Router",
+			SearchKey: "mux.Router",
+			Label:     "type Router",
+			Tags:      []string{"struct"},
+			Language:  "go",
+		},
+		{
+			Repo:      types.RepoName{ID: 43, Name: "github.com/go-chi/chi"},
+			Commit:    "f00dcafe",
+			PathID:    "/#Router",
+			SearchKey: "chi.Router",
+			Label:     "type Router",
+			Tags:      []string{"interface"},
+			Language:  "go",
+		},
+	}
+	if diff := cmp.Diff(expectedMatches, matches); diff != "" {
+		t.Errorf("unexpected matches (-want +got):\n%s", diff)
+	}
+}
+
+func TestDocumentationSearchNoRepositories(t *testing.T) {
+	mockLSIFStore := NewMockLSIFStore()
+
+	resolver := newResolver(NewMockDBStore(), mockLSIFStore, nil, nil, nil, nil, &observation.TestContext)
+	matches, err := resolver.DocumentationSearch(context.Background(), search.DocumentationParameters{Query: "router", Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error searching documentation: %s", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("unexpected matches. want=%d have=%d", 0, len(matches))
+	}
+	if history := mockLSIFStore.DocumentationSearchFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of documentation searches. want=%d have=%d", 0, len(history))
+	}
+}
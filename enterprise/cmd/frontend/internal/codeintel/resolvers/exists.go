@@ -7,6 +7,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/opentracing/opentracing-go/log"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/commitgraph"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -133,6 +134,17 @@ func (r *resolver) inferClosestUploads(ctx context.Context, repositoryID int, co
 		return nil, errors.Wrap(err, "gitserverClient.CommitGraph")
 	}
 
+	// The commit may not be reachable from any tracked branch (e.g. the head of a pull request
+	// opened from a fork), in which case the fragment above may not reach any commit for which
+	// we have data. Link the fragment to the commit's merge base with the default branch so that
+	// the uploads visible from the base are also visible here. Results from these uploads are
+	// mapped onto this commit by the position adjuster.
+	if mergeBase, ok, err := r.gitserverClient.MergeBase(ctx, repositoryID, commit, "HEAD"); err != nil {
+		return nil, errors.Wrap(err, "gitserverClient.MergeBase")
+	} else if ok {
+		graph = commitgraph.AttachMergeBase(graph, mergeBase)
+	}
+
 	dumps, err := r.dbStore.FindClosestDumpsFromGraphFragment(ctx, repositoryID, commit, path, exactPath, indexer, graph)
 	if err != nil {
 		return nil, errors.Wrap(err, "dbstore.FindClosestDumpsFromGraphFragment")
@@ -37,7 +37,7 @@ func TestFindClosestDumps(t *testing.T) {
 		return commit != "c4", nil
 	})
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
@@ -92,7 +92,7 @@ func TestFindClosestDumpsInfersClosestUploads(t *testing.T) {
 		return false, nil
 	})
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
@@ -125,13 +125,65 @@ func TestFindClosestDumpsInfersClosestUploads(t *testing.T) {
 	}
 }
 
+func TestFindClosestDumpsInfersClosestUploadsFromMergeBase(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	commitChecker := newCachedCommitChecker(mockGitserverClient)
+
+	graph := gitserver.ParseCommitGraph([]string{
+		"a b",
+		"b c",
+	})
+
+	// has repository, commit unknown but does exist
+	mockDBStore.HasRepositoryFunc.SetDefaultReturn(true, nil)
+	mockGitserverClient.CommitExistsFunc.SetDefaultReturn(true, nil)
+
+	mockGitserverClient.CommitGraphFunc.SetDefaultReturn(graph, nil)
+	mockGitserverClient.MergeBaseFunc.SetDefaultReturn("m", true, nil)
+	mockDBStore.FindClosestDumpsFromGraphFragmentFunc.SetDefaultReturn([]store.Dump{{ID: 50, Root: "s1/"}}, nil)
+	mockLSIFStore.ExistsFunc.SetDefaultReturn(true, nil)
+
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
+	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "a", "s1/main.go", true, "idx")
+	if err != nil {
+		t.Fatalf("unexpected error finding closest dumps: %s", err)
+	}
+
+	expected := []store.Dump{{ID: 50, Root: "s1/"}}
+	if diff := cmp.Diff(expected, dumps); diff != "" {
+		t.Errorf("unexpected dumps (-want +got):\n%s", diff)
+	}
+
+	if calls := mockGitserverClient.MergeBaseFunc.History(); len(calls) != 1 {
+		t.Errorf("expected number of calls to gitserverClient.MergeBase. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg2 != "a" || calls[0].Arg3 != "HEAD" {
+		t.Errorf("unexpected merge base arguments. want=%v have=%v", []string{"a", "HEAD"}, []string{calls[0].Arg2, calls[0].Arg3})
+	}
+
+	if calls := mockDBStore.FindClosestDumpsFromGraphFragmentFunc.History(); len(calls) != 1 {
+		t.Errorf("expected number of calls to store.FindClosestDumpsFromGraphFragmentFunc. want=%d have=%d", 1, len(calls))
+	} else {
+		expectedGraph := map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"m"},
+			"m": {},
+		}
+		if diff := cmp.Diff(expectedGraph, calls[0].Arg6.Graph()); diff != "" {
+			t.Errorf("unexpected graph (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestFindClosestDumpsDoesNotInferClosestUploadForUnknownRepository(t *testing.T) {
 	mockDBStore := NewMockDBStore()
 	mockLSIFStore := NewMockLSIFStore()
 	mockGitserverClient := NewMockGitserverClient()
 	commitChecker := newCachedCommitChecker(mockGitserverClient)
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
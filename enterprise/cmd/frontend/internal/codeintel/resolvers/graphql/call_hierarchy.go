@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"context"
+
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+)
+
+type CallHierarchyItemResolver struct {
+	item             resolvers.CallHierarchyItem
+	locationResolver *CachedLocationResolver
+}
+
+func NewCallHierarchyItemResolvers(items []resolvers.CallHierarchyItem, locationResolver *CachedLocationResolver) []gql.CallHierarchyItemResolver {
+	itemResolvers := make([]gql.CallHierarchyItemResolver, 0, len(items))
+	for _, item := range items {
+		itemResolvers = append(itemResolvers, &CallHierarchyItemResolver{
+			item:             item,
+			locationResolver: locationResolver,
+		})
+	}
+
+	return itemResolvers
+}
+
+func (r *CallHierarchyItemResolver) Definition(ctx context.Context) (gql.LocationResolver, error) {
+	return resolveLocation(ctx, r.locationResolver, r.item.Definition)
+}
+
+func (r *CallHierarchyItemResolver) CallSites(ctx context.Context) ([]gql.LocationResolver, error) {
+	return resolveLocations(ctx, r.locationResolver, r.item.CallSites)
+}
+
+func (r *CallHierarchyItemResolver) Calls() []gql.CallHierarchyItemResolver {
+	return NewCallHierarchyItemResolvers(r.item.Calls, r.locationResolver)
+}
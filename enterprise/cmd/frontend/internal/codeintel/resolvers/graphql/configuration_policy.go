@@ -53,6 +53,15 @@ func (r *configurationPolicyResolver) RetentionDurationHours() *int32 {
 	return toHours(r.configurationPolicy.RetentionDuration)
 }
 
+func (r *configurationPolicyResolver) RetentionCount() *int32 {
+	if r.configurationPolicy.RetentionCount == nil {
+		return nil
+	}
+
+	v := int32(*r.configurationPolicy.RetentionCount)
+	return &v
+}
+
 func (r *configurationPolicyResolver) RetainIntermediateCommits() bool {
 	return r.configurationPolicy.RetainIntermediateCommits
 }
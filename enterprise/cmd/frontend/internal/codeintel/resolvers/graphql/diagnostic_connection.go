@@ -6,6 +6,7 @@ import (
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 type DiagnosticConnectionResolver struct {
@@ -25,7 +26,19 @@ func NewDiagnosticConnectionResolver(diagnostics []resolvers.AdjustedDiagnostic,
 func (r *DiagnosticConnectionResolver) Nodes(ctx context.Context) ([]gql.DiagnosticResolver, error) {
 	resolvers := make([]gql.DiagnosticResolver, 0, len(r.diagnostics))
 	for i := range r.diagnostics {
-		resolvers = append(resolvers, NewDiagnosticResolver(r.diagnostics[i], r.locationResolver))
+		// Skip the diagnostics whose location can't be resolved, such as those in files
+		// which the actor can't read because of sub-repo permissions, as their location
+		// is non-nullable.
+		d := r.diagnostics[i]
+		treeResolver, err := r.locationResolver.Path(ctx, api.RepoID(d.Dump.RepositoryID), d.AdjustedCommit, d.Path)
+		if err != nil {
+			return nil, err
+		}
+		if treeResolver == nil {
+			continue
+		}
+
+		resolvers = append(resolvers, NewDiagnosticResolver(d, r.locationResolver))
 	}
 	return resolvers, nil
 }
@@ -12,13 +12,15 @@ type LocationConnectionResolver struct {
 	locations        []resolvers.AdjustedLocation
 	cursor           *string
 	locationResolver *CachedLocationResolver
+	precise          bool
 }
 
-func NewLocationConnectionResolver(locations []resolvers.AdjustedLocation, cursor *string, locationResolver *CachedLocationResolver) gql.LocationConnectionResolver {
+func NewLocationConnectionResolver(locations []resolvers.AdjustedLocation, cursor *string, locationResolver *CachedLocationResolver, precise bool) gql.LocationConnectionResolver {
 	return &LocationConnectionResolver{
 		locations:        locations,
 		cursor:           cursor,
 		locationResolver: locationResolver,
+		precise:          precise,
 	}
 }
 
@@ -29,3 +31,7 @@ func (r *LocationConnectionResolver) Nodes(ctx context.Context) ([]gql.LocationR
 func (r *LocationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
 	return encodeCursor(r.cursor), nil
 }
+
+func (r *LocationConnectionResolver) Precise() bool {
+	return r.precise
+}
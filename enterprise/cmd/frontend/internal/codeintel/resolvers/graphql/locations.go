@@ -9,7 +9,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -73,7 +75,8 @@ func (r *CachedLocationResolver) Commit(ctx context.Context, id api.RepoID, comm
 }
 
 // Path resolves the git tree entry with the given repository identifier, commit hash, and relative path.
-// This method may return a nil resolver if the commit is not known by gitserver.
+// This method may return a nil resolver if the commit is not known by gitserver, or if the path can't be
+// read by the actor because of sub-repo permissions.
 func (r *CachedLocationResolver) Path(ctx context.Context, id api.RepoID, commit, path string) (*gql.GitTreeEntryResolver, error) {
 	pathResolver, err := r.cachedPath(ctx, id, commit, path)
 	if err != nil {
@@ -236,9 +239,21 @@ func (r *CachedLocationResolver) resolveCommit(ctx context.Context, repositoryRe
 	return repositoryResolver.CommitFromID(ctx, &gql.RepositoryCommitArgs{Rev: commit}, commitID)
 }
 
-// Path resolves the git tree entry with the given commit resolver and relative path. This method must be
-// called only when constructing a resolver to populate the cache.
+// Path resolves the git tree entry with the given commit resolver and relative path. This method may
+// return a nil resolver if the path can't be read by the actor because of sub-repo permissions. This
+// method must be called only when constructing a resolver to populate the cache.
 func (r *CachedLocationResolver) resolvePath(ctx context.Context, commitResolver *gql.GitCommitResolver, path string) (*gql.GitTreeEntryResolver, error) {
+	perms, err := authz.ActorPermissions(ctx, authz.DefaultSubRepoPermsChecker, actor.FromContext(ctx), authz.RepoContent{
+		Repo: commitResolver.Repository().RepoName(),
+		Path: path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !perms.Include(authz.Read) {
+		return nil, nil
+	}
+
 	return gql.NewGitTreeEntryResolver(commitResolver, r.db, gql.CreateFileInfo(path, true)), nil
 }
 
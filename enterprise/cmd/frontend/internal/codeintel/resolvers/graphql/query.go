@@ -65,7 +65,7 @@ func (r *QueryResolver) Definitions(ctx context.Context, args *gql.LSIFQueryPosi
 		return nil, err
 	}
 
-	return NewLocationConnectionResolver(locations, nil, r.locationResolver), nil
+	return NewLocationConnectionResolver(locations, nil, r.locationResolver, r.resolver.Precise()), nil
 }
 
 func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQueryPositionArgs) (gql.LocationConnectionResolver, error) {
@@ -83,7 +83,43 @@ func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQuery
 		return nil, err
 	}
 
-	return NewLocationConnectionResolver(locations, strPtr(cursor), r.locationResolver), nil
+	return NewLocationConnectionResolver(locations, strPtr(cursor), r.locationResolver, r.resolver.Precise()), nil
+}
+
+func (r *QueryResolver) TypeDefinitions(ctx context.Context, args *gql.LSIFQueryPositionArgs) (gql.LocationConnectionResolver, error) {
+	locations, err := r.resolver.TypeDefinitions(ctx, int(args.Line), int(args.Character))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocationConnectionResolver(locations, nil, r.locationResolver, r.resolver.Precise()), nil
+}
+
+func (r *QueryResolver) Implementations(ctx context.Context, args *gql.LSIFQueryPositionArgs) (gql.LocationConnectionResolver, error) {
+	locations, err := r.resolver.Implementations(ctx, int(args.Line), int(args.Character))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocationConnectionResolver(locations, nil, r.locationResolver, r.resolver.Precise()), nil
+}
+
+func (r *QueryResolver) IncomingCalls(ctx context.Context, args *gql.LSIFCallHierarchyArgs) ([]gql.CallHierarchyItemResolver, error) {
+	items, err := r.resolver.IncomingCalls(ctx, int(args.Line), int(args.Character), int(args.Depth))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallHierarchyItemResolvers(items, r.locationResolver, r.resolver.Precise()), nil
+}
+
+func (r *QueryResolver) OutgoingCalls(ctx context.Context, args *gql.LSIFCallHierarchyArgs) ([]gql.CallHierarchyItemResolver, error) {
+	items, err := r.resolver.OutgoingCalls(ctx, int(args.Line), int(args.Character), int(args.Depth))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallHierarchyItemResolvers(items, r.locationResolver, r.resolver.Precise()), nil
 }
 
 func (r *QueryResolver) Hover(ctx context.Context, args *gql.LSIFQueryPositionArgs) (gql.HoverResolver, error) {
@@ -106,7 +142,7 @@ func (r *QueryResolver) Diagnostics(ctx context.Context, args *gql.LSIFDiagnosti
 		return nil, err
 	}
 
-	return NewDiagnosticConnectionResolver(diagnostics, totalCount, r.locationResolver), nil
+	return NewDiagnosticConnectionResolver(diagnostics, totalCount, r.locationResolver, r.resolver.Precise()), nil
 }
 
 func (r *QueryResolver) Documentation(ctx context.Context, args *gql.LSIFQueryPositionArgs) (gql.DocumentationResolver, error) {
@@ -17,11 +17,11 @@ func (r *CodeIntelligenceRangeResolver) Range(ctx context.Context) (gql.RangeRes
 }
 
 func (r *CodeIntelligenceRangeResolver) Definitions(ctx context.Context) (gql.LocationConnectionResolver, error) {
-	return NewLocationConnectionResolver(r.r.Definitions, nil, r.locationResolver), nil
+	return NewLocationConnectionResolver(r.r.Definitions, nil, r.locationResolver, true), nil
 }
 
 func (r *CodeIntelligenceRangeResolver) References(ctx context.Context) (gql.LocationConnectionResolver, error) {
-	return NewLocationConnectionResolver(r.r.References, nil, r.locationResolver), nil
+	return NewLocationConnectionResolver(r.r.References, nil, r.locationResolver, true), nil
 }
 
 func (r *CodeIntelligenceRangeResolver) Hover(ctx context.Context) (gql.HoverResolver, error) {
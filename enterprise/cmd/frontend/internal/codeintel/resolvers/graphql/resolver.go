@@ -15,6 +15,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 )
 
 const (
@@ -260,6 +262,14 @@ func (r *Resolver) GitBlobLSIFData(ctx context.Context, args *gql.GitBlobLSIFDat
 	return NewQueryResolver(resolver, r.locationResolver), nil
 }
 
+// SearchDocumentation returns the documentation matching a type:docs search query.
+//
+// 🚨 SECURITY: The repositories in args must already have been filtered to those visible
+// to the current user (as is the case for repositories resolved by the search backend).
+func (r *Resolver) SearchDocumentation(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+	return r.resolver.DocumentationSearch(ctx, args)
+}
+
 func (r *Resolver) ConfigurationPolicyByID(ctx context.Context, id graphql.ID) (gql.CodeIntelligenceConfigurationPolicyResolver, error) {
 	// 🚨 SECURITY: Only site admins may configure code intelligence
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, dbconn.Global); err != nil {
@@ -317,6 +327,9 @@ func (r *Resolver) CreateCodeIntelligenceConfigurationPolicy(ctx context.Context
 	if args.Type != gql.GitObjectTypeCommit && args.Type != gql.GitObjectTypeTag && args.Type != gql.GitObjectTypeTree {
 		return nil, errors.Errorf("illegal git object type '%s', expected 'GIT_COMMIT', 'GIT_TAG', or 'GIT_TREE'", args.Type)
 	}
+	if args.RetentionCount != nil && *args.RetentionCount <= 0 {
+		return nil, errors.Errorf("illegal retention count %d, expected a positive number", *args.RetentionCount)
+	}
 
 	var repositoryID *int
 	if args.Repository != nil {
@@ -340,6 +353,7 @@ func (r *Resolver) CreateCodeIntelligenceConfigurationPolicy(ctx context.Context
 		IndexingEnabled:           args.IndexingEnabled,
 		IndexCommitMaxAge:         toDuration(args.IndexCommitMaxAgeHours),
 		IndexIntermediateCommits:  args.IndexIntermediateCommits,
+		RetentionCount:            toCount(args.RetentionCount),
 	})
 	if err != nil {
 		return nil, err
@@ -357,6 +371,15 @@ func toDuration(hours *int32) *time.Duration {
 	return &v
 }
 
+func toCount(count *int32) *int {
+	if count == nil {
+		return nil
+	}
+
+	v := int(*count)
+	return &v
+}
+
 func (r *Resolver) UpdateCodeIntelligenceConfigurationPolicy(ctx context.Context, args *gql.UpdateCodeIntelligenceConfigurationPolicyArgs) (*gql.EmptyResponse, error) {
 	// 🚨 SECURITY: Only site admins may configure code intelligence
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, dbconn.Global); err != nil {
@@ -366,6 +389,9 @@ func (r *Resolver) UpdateCodeIntelligenceConfigurationPolicy(ctx context.Context
 	if args.Type != gql.GitObjectTypeCommit && args.Type != gql.GitObjectTypeTag && args.Type != gql.GitObjectTypeTree {
 		return nil, errors.Errorf("illegal git object type '%s', expected 'GIT_COMMIT', 'GIT_TAG', or 'GIT_TREE'", args.Type)
 	}
+	if args.RetentionCount != nil && *args.RetentionCount <= 0 {
+		return nil, errors.Errorf("illegal retention count %d, expected a positive number", *args.RetentionCount)
+	}
 
 	id, err := unmarshalConfigurationPolicyGQLID(args.ID)
 	if err != nil {
@@ -383,6 +409,7 @@ func (r *Resolver) UpdateCodeIntelligenceConfigurationPolicy(ctx context.Context
 		IndexingEnabled:           args.IndexingEnabled,
 		IndexCommitMaxAge:         toDuration(args.IndexCommitMaxAgeHours),
 		IndexIntermediateCommits:  args.IndexIntermediateCommits,
+		RetentionCount:            toCount(args.RetentionCount),
 	}); err != nil {
 		return nil, err
 	}
@@ -408,6 +435,40 @@ func (r *Resolver) DeleteCodeIntelligenceConfigurationPolicy(ctx context.Context
 	return &gql.EmptyResponse{}, nil
 }
 
+func (r *Resolver) PreviewCodeIntelligenceRetention(ctx context.Context, args *gql.PreviewCodeIntelligenceRetentionArgs) (gql.CodeIntelligenceRetentionPreviewResolver, error) {
+	// 🚨 SECURITY: Only site admins may configure code intelligence
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, dbconn.Global); err != nil {
+		return nil, err
+	}
+
+	repositoryID, err := unmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var policyID *int
+	if args.Policy != nil {
+		id64, err := unmarshalConfigurationPolicyGQLID(*args.Policy)
+		if err != nil {
+			return nil, err
+		}
+
+		id := int(id64)
+		policyID = &id
+	}
+
+	retained, expired, err := r.resolver.PreviewRetention(ctx, int(repositoryID), policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new prefetcher here as we only want to cache upload and index records in
+	// the same graphQL request, not across different request.
+	prefetcher := NewPrefetcher(r.resolver)
+
+	return NewRetentionPreviewResolver(r.resolver, retained, expired, prefetcher, r.locationResolver), nil
+}
+
 func (r *Resolver) IndexConfiguration(ctx context.Context, id graphql.ID) (gql.IndexConfigurationResolver, error) {
 	if !autoIndexingEnabled() {
 		return nil, errAutoIndexingNotEnabled
@@ -0,0 +1,42 @@
+package graphql
+
+import (
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+type retentionPreviewResolver struct {
+	resolver         resolvers.Resolver
+	retained         []store.Upload
+	expired          []store.Upload
+	prefetcher       *Prefetcher
+	locationResolver *CachedLocationResolver
+}
+
+func NewRetentionPreviewResolver(resolver resolvers.Resolver, retained, expired []store.Upload, prefetcher *Prefetcher, locationResolver *CachedLocationResolver) gql.CodeIntelligenceRetentionPreviewResolver {
+	return &retentionPreviewResolver{
+		resolver:         resolver,
+		retained:         retained,
+		expired:          expired,
+		prefetcher:       prefetcher,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *retentionPreviewResolver) Retained() []gql.LSIFUploadResolver {
+	return r.uploadResolvers(r.retained)
+}
+
+func (r *retentionPreviewResolver) Expired() []gql.LSIFUploadResolver {
+	return r.uploadResolvers(r.expired)
+}
+
+func (r *retentionPreviewResolver) uploadResolvers(uploads []store.Upload) []gql.LSIFUploadResolver {
+	resolvers := make([]gql.LSIFUploadResolver, 0, len(uploads))
+	for _, upload := range uploads {
+		resolvers = append(resolvers, NewUploadResolver(r.resolver, upload, r.prefetcher, r.locationResolver))
+	}
+
+	return resolvers
+}
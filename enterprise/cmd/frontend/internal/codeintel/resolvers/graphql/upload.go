@@ -73,3 +73,33 @@ func (r *UploadResolver) AssociatedIndex(ctx context.Context) (gql.LSIFIndexReso
 func (r *UploadResolver) ProjectRoot(ctx context.Context) (*gql.GitTreeEntryResolver, error) {
 	return r.locationResolver.Path(ctx, api.RepoID(r.upload.RepositoryID), r.upload.Commit, r.upload.Root)
 }
+
+func (r *UploadResolver) ValidationErrors(ctx context.Context) ([]gql.LSIFUploadValidationErrorResolver, error) {
+	validationErrors, err := r.resolver.GetUploadValidationErrors(ctx, r.upload.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	validationErrorResolvers := make([]gql.LSIFUploadValidationErrorResolver, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		validationErrorResolvers = append(validationErrorResolvers, &uploadValidationErrorResolver{validationError: validationError})
+	}
+
+	return validationErrorResolvers, nil
+}
+
+type uploadValidationErrorResolver struct {
+	validationError store.UploadValidationError
+}
+
+func (r *uploadValidationErrorResolver) Severity() string { return r.validationError.Severity }
+func (r *uploadValidationErrorResolver) Message() string  { return r.validationError.Message }
+
+func (r *uploadValidationErrorResolver) RelevantLines() []int32 {
+	relevantLines := make([]int32, 0, len(r.validationError.RelevantLines))
+	for _, line := range r.validationError.RelevantLines {
+		relevantLines = append(relevantLines, int32(line))
+	}
+
+	return relevantLines
+}
@@ -9,13 +9,21 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
 type GitserverClient interface {
+	CommitDate(ctx context.Context, repositoryID int, commit string) (time.Time, error)
 	CommitExists(ctx context.Context, repositoryID int, commit string) (bool, error)
 	CommitGraph(ctx context.Context, repositoryID int, options gitserver.CommitGraphOptions) (*gitserver.CommitGraph, error)
+	CommitsOnBranch(ctx context.Context, repositoryID int, branchName string, since *time.Time, limit int) ([]string, error)
+	MergeBase(ctx context.Context, repositoryID int, commit, revision string) (string, bool, error)
+	RefDescriptions(ctx context.Context, repositoryID int) (map[string]gitserver.RefDescription, error)
+	RawContents(ctx context.Context, repositoryID int, commit, file string) ([]byte, error)
+	SearchWord(ctx context.Context, repositoryID int, commit, word string, pathspecs []string, limit int) ([]gitserver.WordMatch, error)
 }
 
 type DBStore interface {
@@ -24,6 +32,7 @@ type DBStore interface {
 	GetUploadByID(ctx context.Context, id int) (dbstore.Upload, bool, error)
 	GetUploadsByIDs(ctx context.Context, ids ...int) ([]dbstore.Upload, error)
 	GetUploads(ctx context.Context, opts dbstore.GetUploadsOptions) ([]dbstore.Upload, int, error)
+	GetUploadValidationErrors(ctx context.Context, uploadID int) ([]dbstore.UploadValidationError, error)
 	DeleteUploadByID(ctx context.Context, id int) (bool, error)
 	GetDumpsByIDs(ctx context.Context, ids []int) ([]dbstore.Dump, error)
 	FindClosestDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) ([]dbstore.Dump, error)
@@ -52,6 +61,10 @@ type LSIFStore interface {
 	Ranges(ctx context.Context, bundleID int, path string, startLine, endLine int) ([]lsifstore.CodeIntelligenceRange, error)
 	Definitions(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
 	References(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
+	TypeDefinitions(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
+	Implementations(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
+	EnclosingDefinitions(ctx context.Context, bundleID int, path string, ranges []lsifstore.Range) (map[int]lsifstore.Range, error)
+	EnclosedRanges(ctx context.Context, bundleID int, path string, line, character int) ([]lsifstore.Range, error)
 	Hover(ctx context.Context, bundleID int, path string, line, character int) (string, lsifstore.Range, bool, error)
 	Diagnostics(ctx context.Context, bundleID int, prefix string, limit, offset int) ([]lsifstore.Diagnostic, int, error)
 	MonikersByPosition(ctx context.Context, bundleID int, path string, line, character int) ([][]precise.MonikerData, error)
@@ -61,6 +74,11 @@ type LSIFStore interface {
 	DocumentationPathInfo(ctx context.Context, bundleID int, pathID string) (*precise.DocumentationPathInfoData, error)
 	DocumentationDefinitions(ctx context.Context, bundleID int, pathID string, limit, offset int) ([]lsifstore.Location, int, error)
 	DocumentationAtPosition(ctx context.Context, bundleID int, path string, line, character int) ([]string, error)
+	DocumentationSearch(ctx context.Context, opts lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error)
+}
+
+type SymbolsClient interface {
+	Search(ctx context.Context, args search.SymbolsParameters) (*result.Symbols, error)
 }
 
 type IndexEnqueuer interface {
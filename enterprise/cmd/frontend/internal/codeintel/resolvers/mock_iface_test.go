@@ -15,6 +15,8 @@ import (
 	api "github.com/sourcegraph/sourcegraph/internal/api"
 	basestore "github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	protocol "github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	search "github.com/sourcegraph/sourcegraph/internal/search"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
 	config "github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	precise "github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
@@ -77,6 +79,10 @@ type MockDBStore struct {
 	// GetUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadByID.
 	GetUploadByIDFunc *DBStoreGetUploadByIDFunc
+	// GetUploadValidationErrorsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetUploadValidationErrors.
+	GetUploadValidationErrorsFunc *DBStoreGetUploadValidationErrorsFunc
 	// GetUploadsFunc is an instance of a mock function object controlling
 	// the behavior of the method GetUploads.
 	GetUploadsFunc *DBStoreGetUploadsFunc
@@ -192,6 +198,11 @@ func NewMockDBStore() *MockDBStore {
 				return dbstore.Upload{}, false, nil
 			},
 		},
+		GetUploadValidationErrorsFunc: &DBStoreGetUploadValidationErrorsFunc{
+			defaultHook: func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+				return nil, nil
+			},
+		},
 		GetUploadsFunc: &DBStoreGetUploadsFunc{
 			defaultHook: func(context.Context, dbstore.GetUploadsOptions) ([]dbstore.Upload, int, error) {
 				return nil, 0, nil
@@ -292,6 +303,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		GetUploadByIDFunc: &DBStoreGetUploadByIDFunc{
 			defaultHook: i.GetUploadByID,
 		},
+		GetUploadValidationErrorsFunc: &DBStoreGetUploadValidationErrorsFunc{
+			defaultHook: i.GetUploadValidationErrors,
+		},
 		GetUploadsFunc: &DBStoreGetUploadsFunc{
 			defaultHook: i.GetUploads,
 		},
@@ -2136,6 +2150,118 @@ func (c DBStoreGetUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DBStoreGetUploadValidationErrorsFunc describes the behavior when the
+// GetUploadValidationErrors method of the parent MockDBStore instance is
+// invoked.
+type DBStoreGetUploadValidationErrorsFunc struct {
+	defaultHook func(context.Context, int) ([]dbstore.UploadValidationError, error)
+	hooks       []func(context.Context, int) ([]dbstore.UploadValidationError, error)
+	history     []DBStoreGetUploadValidationErrorsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetUploadValidationErrors delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) GetUploadValidationErrors(v0 context.Context, v1 int) ([]dbstore.UploadValidationError, error) {
+	r0, r1 := m.GetUploadValidationErrorsFunc.nextHook()(v0, v1)
+	m.GetUploadValidationErrorsFunc.appendCall(DBStoreGetUploadValidationErrorsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetUploadValidationErrors method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreGetUploadValidationErrorsFunc) SetDefaultHook(hook func(context.Context, int) ([]dbstore.UploadValidationError, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetUploadValidationErrors method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *DBStoreGetUploadValidationErrorsFunc) PushHook(hook func(context.Context, int) ([]dbstore.UploadValidationError, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreGetUploadValidationErrorsFunc) SetDefaultReturn(r0 []dbstore.UploadValidationError, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreGetUploadValidationErrorsFunc) PushReturn(r0 []dbstore.UploadValidationError, r1 error) {
+	f.PushHook(func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreGetUploadValidationErrorsFunc) nextHook() func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreGetUploadValidationErrorsFunc) appendCall(r0 DBStoreGetUploadValidationErrorsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreGetUploadValidationErrorsFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreGetUploadValidationErrorsFunc) History() []DBStoreGetUploadValidationErrorsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreGetUploadValidationErrorsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreGetUploadValidationErrorsFuncCall is an object that describes an
+// invocation of method GetUploadValidationErrors on an instance of
+// MockDBStore.
+type DBStoreGetUploadValidationErrorsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []dbstore.UploadValidationError
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreGetUploadValidationErrorsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreGetUploadValidationErrorsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreGetUploadsFunc describes the behavior when the GetUploads method
 // of the parent MockDBStore instance is invoked.
 type DBStoreGetUploadsFunc struct {
@@ -4924,12 +5050,30 @@ func (c EnqueuerGitserverClientResolveRevisionFuncCall) Results() []interface{}
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockGitserverClient struct {
+	// CommitDateFunc is an instance of a mock function object controlling
+	// the behavior of the method CommitDate.
+	CommitDateFunc *GitserverClientCommitDateFunc
 	// CommitExistsFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitExists.
 	CommitExistsFunc *GitserverClientCommitExistsFunc
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *GitserverClientCommitGraphFunc
+	// CommitsOnBranchFunc is an instance of a mock function object
+	// controlling the behavior of the method CommitsOnBranch.
+	CommitsOnBranchFunc *GitserverClientCommitsOnBranchFunc
+	// MergeBaseFunc is an instance of a mock function object controlling
+	// the behavior of the method MergeBase.
+	MergeBaseFunc *GitserverClientMergeBaseFunc
+	// RawContentsFunc is an instance of a mock function object controlling
+	// the behavior of the method RawContents.
+	RawContentsFunc *GitserverClientRawContentsFunc
+	// RefDescriptionsFunc is an instance of a mock function object
+	// controlling the behavior of the method RefDescriptions.
+	RefDescriptionsFunc *GitserverClientRefDescriptionsFunc
+	// SearchWordFunc is an instance of a mock function object controlling
+	// the behavior of the method SearchWord.
+	SearchWordFunc *GitserverClientSearchWordFunc
 }
 
 // NewMockGitserverClient creates a new mock of the GitserverClient
@@ -4937,6 +5081,11 @@ type MockGitserverClient struct {
 // overwritten.
 func NewMockGitserverClient() *MockGitserverClient {
 	return &MockGitserverClient{
+		CommitDateFunc: &GitserverClientCommitDateFunc{
+			defaultHook: func(context.Context, int, string) (time.Time, error) {
+				return time.Time{}, nil
+			},
+		},
 		CommitExistsFunc: &GitserverClientCommitExistsFunc{
 			defaultHook: func(context.Context, int, string) (bool, error) {
 				return false, nil
@@ -4947,6 +5096,31 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return nil, nil
 			},
 		},
+		CommitsOnBranchFunc: &GitserverClientCommitsOnBranchFunc{
+			defaultHook: func(context.Context, int, string, *time.Time, int) ([]string, error) {
+				return nil, nil
+			},
+		},
+		MergeBaseFunc: &GitserverClientMergeBaseFunc{
+			defaultHook: func(int, string, string) (string, bool) {
+				return "", false
+			},
+		},
+		RawContentsFunc: &GitserverClientRawContentsFunc{
+			defaultHook: func(context.Context, int, string, string) ([]byte, error) {
+				return nil, nil
+			},
+		},
+		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
+			defaultHook: func(context.Context, int) (map[string]gitserver.RefDescription, error) {
+				return nil, nil
+			},
+		},
+		SearchWordFunc: &GitserverClientSearchWordFunc{
+			defaultHook: func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -4955,13 +5129,143 @@ func NewMockGitserverClient() *MockGitserverClient {
 // overwritten.
 func NewMockGitserverClientFrom(i GitserverClient) *MockGitserverClient {
 	return &MockGitserverClient{
+		CommitDateFunc: &GitserverClientCommitDateFunc{
+			defaultHook: i.CommitDate,
+		},
 		CommitExistsFunc: &GitserverClientCommitExistsFunc{
 			defaultHook: i.CommitExists,
 		},
 		CommitGraphFunc: &GitserverClientCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
+		CommitsOnBranchFunc: &GitserverClientCommitsOnBranchFunc{
+			defaultHook: i.CommitsOnBranch,
+		},
+		MergeBaseFunc: &GitserverClientMergeBaseFunc{
+			defaultHook: i.MergeBase,
+		},
+		RawContentsFunc: &GitserverClientRawContentsFunc{
+			defaultHook: i.RawContents,
+		},
+		RefDescriptionsFunc: &GitserverClientRefDescriptionsFunc{
+			defaultHook: i.RefDescriptions,
+		},
+		SearchWordFunc: &GitserverClientSearchWordFunc{
+			defaultHook: i.SearchWord,
+		},
+	}
+}
+
+// GitserverClientCommitDateFunc describes the behavior when the CommitDate
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientCommitDateFunc struct {
+	defaultHook func(context.Context, int, string) (time.Time, error)
+	hooks       []func(context.Context, int, string) (time.Time, error)
+	history     []GitserverClientCommitDateFuncCall
+	mutex       sync.Mutex
+}
+
+// CommitDate delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) CommitDate(v0 context.Context, v1 int, v2 string) (time.Time, error) {
+	r0, r1 := m.CommitDateFunc.nextHook()(v0, v1, v2)
+	m.CommitDateFunc.appendCall(GitserverClientCommitDateFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CommitDate method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientCommitDateFunc) SetDefaultHook(hook func(context.Context, int, string) (time.Time, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CommitDate method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientCommitDateFunc) PushHook(hook func(context.Context, int, string) (time.Time, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientCommitDateFunc) SetDefaultReturn(r0 time.Time, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) (time.Time, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientCommitDateFunc) PushReturn(r0 time.Time, r1 error) {
+	f.PushHook(func(context.Context, int, string) (time.Time, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientCommitDateFunc) nextHook() func(context.Context, int, string) (time.Time, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientCommitDateFunc) appendCall(r0 GitserverClientCommitDateFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientCommitDateFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientCommitDateFunc) History() []GitserverClientCommitDateFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientCommitDateFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientCommitDateFuncCall is an object that describes an
+// invocation of method CommitDate on an instance of MockGitserverClient.
+type GitserverClientCommitDateFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 time.Time
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientCommitDateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientCommitDateFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // GitserverClientCommitExistsFunc describes the behavior when the
@@ -5189,81 +5493,155 @@ func (c GitserverClientCommitGraphFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// MockIndexEnqueuer is a mock implementation of the IndexEnqueuer interface
-// (from the package
-// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
-// used for unit testing.
-type MockIndexEnqueuer struct {
-	// InferIndexConfigurationFunc is an instance of a mock function object
-	// controlling the behavior of the method InferIndexConfiguration.
-	InferIndexConfigurationFunc *IndexEnqueuerInferIndexConfigurationFunc
-	// QueueIndexesFunc is an instance of a mock function object controlling
-	// the behavior of the method QueueIndexes.
-	QueueIndexesFunc *IndexEnqueuerQueueIndexesFunc
+// GitserverClientCommitsOnBranchFunc describes the behavior when the
+// CommitsOnBranch method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientCommitsOnBranchFunc struct {
+	defaultHook func(context.Context, int, string, *time.Time, int) ([]string, error)
+	hooks       []func(context.Context, int, string, *time.Time, int) ([]string, error)
+	history     []GitserverClientCommitsOnBranchFuncCall
+	mutex       sync.Mutex
 }
 
-// NewMockIndexEnqueuer creates a new mock of the IndexEnqueuer interface.
-// All methods return zero values for all results, unless overwritten.
-func NewMockIndexEnqueuer() *MockIndexEnqueuer {
-	return &MockIndexEnqueuer{
-		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
-			defaultHook: func(context.Context, int) (*config.IndexConfiguration, error) {
-				return nil, nil
-			},
-		},
-		QueueIndexesFunc: &IndexEnqueuerQueueIndexesFunc{
-			defaultHook: func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
-				return nil, nil
-			},
-		},
-	}
+// CommitsOnBranch delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) CommitsOnBranch(v0 context.Context, v1 int, v2 string, v3 *time.Time, v4 int) ([]string, error) {
+	r0, r1 := m.CommitsOnBranchFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.CommitsOnBranchFunc.appendCall(GitserverClientCommitsOnBranchFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
 }
 
-// NewMockIndexEnqueuerFrom creates a new mock of the MockIndexEnqueuer
-// interface. All methods delegate to the given implementation, unless
-// overwritten.
-func NewMockIndexEnqueuerFrom(i IndexEnqueuer) *MockIndexEnqueuer {
-	return &MockIndexEnqueuer{
-		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
-			defaultHook: i.InferIndexConfiguration,
-		},
-		QueueIndexesFunc: &IndexEnqueuerQueueIndexesFunc{
-			defaultHook: i.QueueIndexes,
-		},
-	}
+// SetDefaultHook sets function that is called when the CommitsOnBranch
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientCommitsOnBranchFunc) SetDefaultHook(hook func(context.Context, int, string, *time.Time, int) ([]string, error)) {
+	f.defaultHook = hook
 }
 
-// IndexEnqueuerInferIndexConfigurationFunc describes the behavior when the
-// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
-// is invoked.
-type IndexEnqueuerInferIndexConfigurationFunc struct {
-	defaultHook func(context.Context, int) (*config.IndexConfiguration, error)
-	hooks       []func(context.Context, int) (*config.IndexConfiguration, error)
-	history     []IndexEnqueuerInferIndexConfigurationFuncCall
-	mutex       sync.Mutex
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CommitsOnBranch method of the parent MockGitserverClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverClientCommitsOnBranchFunc) PushHook(hook func(context.Context, int, string, *time.Time, int) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
 }
 
-// InferIndexConfiguration delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockIndexEnqueuer) InferIndexConfiguration(v0 context.Context, v1 int) (*config.IndexConfiguration, error) {
-	r0, r1 := m.InferIndexConfigurationFunc.nextHook()(v0, v1)
-	m.InferIndexConfigurationFunc.appendCall(IndexEnqueuerInferIndexConfigurationFuncCall{v0, v1, r0, r1})
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientCommitsOnBranchFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, *time.Time, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientCommitsOnBranchFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, int, string, *time.Time, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientCommitsOnBranchFunc) nextHook() func(context.Context, int, string, *time.Time, int) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientCommitsOnBranchFunc) appendCall(r0 GitserverClientCommitsOnBranchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientCommitsOnBranchFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientCommitsOnBranchFunc) History() []GitserverClientCommitsOnBranchFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientCommitsOnBranchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientCommitsOnBranchFuncCall is an object that describes an
+// invocation of method CommitsOnBranch on an instance of
+// MockGitserverClient.
+type GitserverClientCommitsOnBranchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 *time.Time
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientCommitsOnBranchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientCommitsOnBranchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// GitserverClientMergeBaseFunc describes the behavior when the MergeBase
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientMergeBaseFunc struct {
+	defaultHook func(int, string, string) (string, bool)
+	hooks       []func(int, string, string) (string, bool)
+	history     []GitserverClientMergeBaseFuncCall
+	mutex       sync.Mutex
+}
+
+// MergeBase delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockGitserverClient) MergeBase(v0 int, v1 string, v2 string) (string, bool) {
+	r0, r1 := m.MergeBaseFunc.nextHook()(v0, v1, v2)
+	m.MergeBaseFunc.appendCall(GitserverClientMergeBaseFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
-// is invoked and the hook queue is empty.
-func (f *IndexEnqueuerInferIndexConfigurationFunc) SetDefaultHook(hook func(context.Context, int) (*config.IndexConfiguration, error)) {
+// SetDefaultHook sets function that is called when the MergeBase method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientMergeBaseFunc) SetDefaultHook(hook func(int, string, string) (string, bool)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
-// invokes the hook at the front of the queue and discards it. After the
-// queue is empty, the default hook function is invoked for any future
-// action.
-func (f *IndexEnqueuerInferIndexConfigurationFunc) PushHook(hook func(context.Context, int) (*config.IndexConfiguration, error)) {
+// MergeBase method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientMergeBaseFunc) PushHook(hook func(int, string, string) (string, bool)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5271,21 +5649,21 @@ func (f *IndexEnqueuerInferIndexConfigurationFunc) PushHook(hook func(context.Co
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *IndexEnqueuerInferIndexConfigurationFunc) SetDefaultReturn(r0 *config.IndexConfiguration, r1 error) {
-	f.SetDefaultHook(func(context.Context, int) (*config.IndexConfiguration, error) {
+func (f *GitserverClientMergeBaseFunc) SetDefaultReturn(r0 string, r1 bool) {
+	f.SetDefaultHook(func(int, string, string) (string, bool) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *IndexEnqueuerInferIndexConfigurationFunc) PushReturn(r0 *config.IndexConfiguration, r1 error) {
-	f.PushHook(func(context.Context, int) (*config.IndexConfiguration, error) {
+func (f *GitserverClientMergeBaseFunc) PushReturn(r0 string, r1 bool) {
+	f.PushHook(func(int, string, string) (string, bool) {
 		return r0, r1
 	})
 }
 
-func (f *IndexEnqueuerInferIndexConfigurationFunc) nextHook() func(context.Context, int) (*config.IndexConfiguration, error) {
+func (f *GitserverClientMergeBaseFunc) nextHook() func(int, string, string) (string, bool) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5298,83 +5676,84 @@ func (f *IndexEnqueuerInferIndexConfigurationFunc) nextHook() func(context.Conte
 	return hook
 }
 
-func (f *IndexEnqueuerInferIndexConfigurationFunc) appendCall(r0 IndexEnqueuerInferIndexConfigurationFuncCall) {
+func (f *GitserverClientMergeBaseFunc) appendCall(r0 GitserverClientMergeBaseFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of
-// IndexEnqueuerInferIndexConfigurationFuncCall objects describing the
-// invocations of this function.
-func (f *IndexEnqueuerInferIndexConfigurationFunc) History() []IndexEnqueuerInferIndexConfigurationFuncCall {
+// History returns a sequence of GitserverClientMergeBaseFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientMergeBaseFunc) History() []GitserverClientMergeBaseFuncCall {
 	f.mutex.Lock()
-	history := make([]IndexEnqueuerInferIndexConfigurationFuncCall, len(f.history))
+	history := make([]GitserverClientMergeBaseFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// IndexEnqueuerInferIndexConfigurationFuncCall is an object that describes
-// an invocation of method InferIndexConfiguration on an instance of
-// MockIndexEnqueuer.
-type IndexEnqueuerInferIndexConfigurationFuncCall struct {
+// GitserverClientMergeBaseFuncCall is an object that describes an
+// invocation of method MergeBase on an instance of MockGitserverClient.
+type GitserverClientMergeBaseFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
-	Arg0 context.Context
+	Arg0 int
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *config.IndexConfiguration
+	Result0 string
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 error
+	Result1 bool
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c IndexEnqueuerInferIndexConfigurationFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+func (c GitserverClientMergeBaseFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c IndexEnqueuerInferIndexConfigurationFuncCall) Results() []interface{} {
+func (c GitserverClientMergeBaseFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// IndexEnqueuerQueueIndexesFunc describes the behavior when the
-// QueueIndexes method of the parent MockIndexEnqueuer instance is invoked.
-type IndexEnqueuerQueueIndexesFunc struct {
-	defaultHook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)
-	hooks       []func(context.Context, int, string, string, bool) ([]dbstore.Index, error)
-	history     []IndexEnqueuerQueueIndexesFuncCall
+// GitserverClientRawContentsFunc describes the behavior when the
+// RawContents method of the parent MockGitserverClient instance is invoked.
+type GitserverClientRawContentsFunc struct {
+	defaultHook func(context.Context, int, string, string) ([]byte, error)
+	hooks       []func(context.Context, int, string, string) ([]byte, error)
+	history     []GitserverClientRawContentsFuncCall
 	mutex       sync.Mutex
 }
 
-// QueueIndexes delegates to the next hook function in the queue and stores
+// RawContents delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockIndexEnqueuer) QueueIndexes(v0 context.Context, v1 int, v2 string, v3 string, v4 bool) ([]dbstore.Index, error) {
-	r0, r1 := m.QueueIndexesFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.QueueIndexesFunc.appendCall(IndexEnqueuerQueueIndexesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+func (m *MockGitserverClient) RawContents(v0 context.Context, v1 int, v2 string, v3 string) ([]byte, error) {
+	r0, r1 := m.RawContentsFunc.nextHook()(v0, v1, v2, v3)
+	m.RawContentsFunc.appendCall(GitserverClientRawContentsFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the QueueIndexes method
-// of the parent MockIndexEnqueuer instance is invoked and the hook queue is
-// empty.
-func (f *IndexEnqueuerQueueIndexesFunc) SetDefaultHook(hook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)) {
+// SetDefaultHook sets function that is called when the RawContents method
+// of the parent MockGitserverClient instance is invoked and the hook queue
+// is empty.
+func (f *GitserverClientRawContentsFunc) SetDefaultHook(hook func(context.Context, int, string, string) ([]byte, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// QueueIndexes method of the parent MockIndexEnqueuer instance invokes the
+// RawContents method of the parent MockGitserverClient instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *IndexEnqueuerQueueIndexesFunc) PushHook(hook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)) {
+func (f *GitserverClientRawContentsFunc) PushHook(hook func(context.Context, int, string, string) ([]byte, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5382,21 +5761,21 @@ func (f *IndexEnqueuerQueueIndexesFunc) PushHook(hook func(context.Context, int,
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *IndexEnqueuerQueueIndexesFunc) SetDefaultReturn(r0 []dbstore.Index, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+func (f *GitserverClientRawContentsFunc) SetDefaultReturn(r0 []byte, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) ([]byte, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *IndexEnqueuerQueueIndexesFunc) PushReturn(r0 []dbstore.Index, r1 error) {
-	f.PushHook(func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+func (f *GitserverClientRawContentsFunc) PushReturn(r0 []byte, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) ([]byte, error) {
 		return r0, r1
 	})
 }
 
-func (f *IndexEnqueuerQueueIndexesFunc) nextHook() func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+func (f *GitserverClientRawContentsFunc) nextHook() func(context.Context, int, string, string) ([]byte, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5409,26 +5788,26 @@ func (f *IndexEnqueuerQueueIndexesFunc) nextHook() func(context.Context, int, st
 	return hook
 }
 
-func (f *IndexEnqueuerQueueIndexesFunc) appendCall(r0 IndexEnqueuerQueueIndexesFuncCall) {
+func (f *GitserverClientRawContentsFunc) appendCall(r0 GitserverClientRawContentsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of IndexEnqueuerQueueIndexesFuncCall objects
+// History returns a sequence of GitserverClientRawContentsFuncCall objects
 // describing the invocations of this function.
-func (f *IndexEnqueuerQueueIndexesFunc) History() []IndexEnqueuerQueueIndexesFuncCall {
+func (f *GitserverClientRawContentsFunc) History() []GitserverClientRawContentsFuncCall {
 	f.mutex.Lock()
-	history := make([]IndexEnqueuerQueueIndexesFuncCall, len(f.history))
+	history := make([]GitserverClientRawContentsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// IndexEnqueuerQueueIndexesFuncCall is an object that describes an
-// invocation of method QueueIndexes on an instance of MockIndexEnqueuer.
-type IndexEnqueuerQueueIndexesFuncCall struct {
+// GitserverClientRawContentsFuncCall is an object that describes an
+// invocation of method RawContents on an instance of MockGitserverClient.
+type GitserverClientRawContentsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -5441,12 +5820,9 @@ type IndexEnqueuerQueueIndexesFuncCall struct {
 	// Arg3 is the value of the 4th argument passed to this method
 	// invocation.
 	Arg3 string
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 bool
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []dbstore.Index
+	Result0 []byte
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -5454,210 +5830,1023 @@ type IndexEnqueuerQueueIndexesFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c IndexEnqueuerQueueIndexesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c GitserverClientRawContentsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c IndexEnqueuerQueueIndexesFuncCall) Results() []interface{} {
+func (c GitserverClientRawContentsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// MockLSIFStore is a mock implementation of the LSIFStore interface (from
-// the package
-// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
-// used for unit testing.
-type MockLSIFStore struct {
-	// BulkMonikerResultsFunc is an instance of a mock function object
-	// controlling the behavior of the method BulkMonikerResults.
-	BulkMonikerResultsFunc *LSIFStoreBulkMonikerResultsFunc
-	// DefinitionsFunc is an instance of a mock function object controlling
-	// the behavior of the method Definitions.
-	DefinitionsFunc *LSIFStoreDefinitionsFunc
-	// DiagnosticsFunc is an instance of a mock function object controlling
-	// the behavior of the method Diagnostics.
-	DiagnosticsFunc *LSIFStoreDiagnosticsFunc
-	// DocumentationAtPositionFunc is an instance of a mock function object
-	// controlling the behavior of the method DocumentationAtPosition.
-	DocumentationAtPositionFunc *LSIFStoreDocumentationAtPositionFunc
-	// DocumentationDefinitionsFunc is an instance of a mock function object
-	// controlling the behavior of the method DocumentationDefinitions.
-	DocumentationDefinitionsFunc *LSIFStoreDocumentationDefinitionsFunc
-	// DocumentationPageFunc is an instance of a mock function object
-	// controlling the behavior of the method DocumentationPage.
-	DocumentationPageFunc *LSIFStoreDocumentationPageFunc
-	// DocumentationPathInfoFunc is an instance of a mock function object
-	// controlling the behavior of the method DocumentationPathInfo.
-	DocumentationPathInfoFunc *LSIFStoreDocumentationPathInfoFunc
-	// ExistsFunc is an instance of a mock function object controlling the
-	// behavior of the method Exists.
-	ExistsFunc *LSIFStoreExistsFunc
-	// HoverFunc is an instance of a mock function object controlling the
-	// behavior of the method Hover.
-	HoverFunc *LSIFStoreHoverFunc
-	// MonikersByPositionFunc is an instance of a mock function object
-	// controlling the behavior of the method MonikersByPosition.
-	MonikersByPositionFunc *LSIFStoreMonikersByPositionFunc
-	// PackageInformationFunc is an instance of a mock function object
-	// controlling the behavior of the method PackageInformation.
-	PackageInformationFunc *LSIFStorePackageInformationFunc
-	// RangesFunc is an instance of a mock function object controlling the
-	// behavior of the method Ranges.
-	RangesFunc *LSIFStoreRangesFunc
-	// ReferencesFunc is an instance of a mock function object controlling
-	// the behavior of the method References.
-	ReferencesFunc *LSIFStoreReferencesFunc
+// GitserverClientRefDescriptionsFunc describes the behavior when the
+// RefDescriptions method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientRefDescriptionsFunc struct {
+	defaultHook func(context.Context, int) (map[string]gitserver.RefDescription, error)
+	hooks       []func(context.Context, int) (map[string]gitserver.RefDescription, error)
+	history     []GitserverClientRefDescriptionsFuncCall
+	mutex       sync.Mutex
 }
 
-// NewMockLSIFStore creates a new mock of the LSIFStore interface. All
-// methods return zero values for all results, unless overwritten.
-func NewMockLSIFStore() *MockLSIFStore {
-	return &MockLSIFStore{
-		BulkMonikerResultsFunc: &LSIFStoreBulkMonikerResultsFunc{
-			defaultHook: func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
-				return nil, 0, nil
-			},
-		},
-		DefinitionsFunc: &LSIFStoreDefinitionsFunc{
-			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
-				return nil, 0, nil
-			},
-		},
-		DiagnosticsFunc: &LSIFStoreDiagnosticsFunc{
-			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
-				return nil, 0, nil
-			},
-		},
-		DocumentationAtPositionFunc: &LSIFStoreDocumentationAtPositionFunc{
-			defaultHook: func(context.Context, int, string, int, int) ([]string, error) {
-				return nil, nil
-			},
-		},
-		DocumentationDefinitionsFunc: &LSIFStoreDocumentationDefinitionsFunc{
-			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
-				return nil, 0, nil
-			},
-		},
-		DocumentationPageFunc: &LSIFStoreDocumentationPageFunc{
-			defaultHook: func(context.Context, int, string) (*precise.DocumentationPageData, error) {
-				return nil, nil
-			},
-		},
-		DocumentationPathInfoFunc: &LSIFStoreDocumentationPathInfoFunc{
-			defaultHook: func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
-				return nil, nil
-			},
-		},
-		ExistsFunc: &LSIFStoreExistsFunc{
-			defaultHook: func(context.Context, int, string) (bool, error) {
-				return false, nil
-			},
-		},
-		HoverFunc: &LSIFStoreHoverFunc{
-			defaultHook: func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
+// RefDescriptions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) RefDescriptions(v0 context.Context, v1 int) (map[string]gitserver.RefDescription, error) {
+	r0, r1 := m.RefDescriptionsFunc.nextHook()(v0, v1)
+	m.RefDescriptionsFunc.appendCall(GitserverClientRefDescriptionsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RefDescriptions
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientRefDescriptionsFunc) SetDefaultHook(hook func(context.Context, int) (map[string]gitserver.RefDescription, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RefDescriptions method of the parent MockGitserverClient instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverClientRefDescriptionsFunc) PushHook(hook func(context.Context, int) (map[string]gitserver.RefDescription, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientRefDescriptionsFunc) SetDefaultReturn(r0 map[string]gitserver.RefDescription, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (map[string]gitserver.RefDescription, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientRefDescriptionsFunc) PushReturn(r0 map[string]gitserver.RefDescription, r1 error) {
+	f.PushHook(func(context.Context, int) (map[string]gitserver.RefDescription, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientRefDescriptionsFunc) nextHook() func(context.Context, int) (map[string]gitserver.RefDescription, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientRefDescriptionsFunc) appendCall(r0 GitserverClientRefDescriptionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientRefDescriptionsFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientRefDescriptionsFunc) History() []GitserverClientRefDescriptionsFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientRefDescriptionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientRefDescriptionsFuncCall is an object that describes an
+// invocation of method RefDescriptions on an instance of
+// MockGitserverClient.
+type GitserverClientRefDescriptionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string]gitserver.RefDescription
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientRefDescriptionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientRefDescriptionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// GitserverClientSearchWordFunc describes the behavior when the SearchWord
+// method of the parent MockGitserverClient instance is invoked.
+type GitserverClientSearchWordFunc struct {
+	defaultHook func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error)
+	hooks       []func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error)
+	history     []GitserverClientSearchWordFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchWord delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) SearchWord(v0 context.Context, v1 int, v2 string, v3 string, v4 []string, v5 int) ([]gitserver.WordMatch, error) {
+	r0, r1 := m.SearchWordFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.SearchWordFunc.appendCall(GitserverClientSearchWordFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchWord method of
+// the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientSearchWordFunc) SetDefaultHook(hook func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchWord method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientSearchWordFunc) PushHook(hook func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientSearchWordFunc) SetDefaultReturn(r0 []gitserver.WordMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientSearchWordFunc) PushReturn(r0 []gitserver.WordMatch, r1 error) {
+	f.PushHook(func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientSearchWordFunc) nextHook() func(context.Context, int, string, string, []string, int) ([]gitserver.WordMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientSearchWordFunc) appendCall(r0 GitserverClientSearchWordFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientSearchWordFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientSearchWordFunc) History() []GitserverClientSearchWordFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientSearchWordFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientSearchWordFuncCall is an object that describes an
+// invocation of method SearchWord on an instance of MockGitserverClient.
+type GitserverClientSearchWordFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 []string
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitserver.WordMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientSearchWordFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientSearchWordFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// MockIndexEnqueuer is a mock implementation of the IndexEnqueuer interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
+// used for unit testing.
+type MockIndexEnqueuer struct {
+	// InferIndexConfigurationFunc is an instance of a mock function object
+	// controlling the behavior of the method InferIndexConfiguration.
+	InferIndexConfigurationFunc *IndexEnqueuerInferIndexConfigurationFunc
+	// QueueIndexesFunc is an instance of a mock function object controlling
+	// the behavior of the method QueueIndexes.
+	QueueIndexesFunc *IndexEnqueuerQueueIndexesFunc
+}
+
+// NewMockIndexEnqueuer creates a new mock of the IndexEnqueuer interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockIndexEnqueuer() *MockIndexEnqueuer {
+	return &MockIndexEnqueuer{
+		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
+			defaultHook: func(context.Context, int) (*config.IndexConfiguration, error) {
+				return nil, nil
+			},
+		},
+		QueueIndexesFunc: &IndexEnqueuerQueueIndexesFunc{
+			defaultHook: func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+				return nil, nil
+			},
+		},
+	}
+}
+
+// NewMockIndexEnqueuerFrom creates a new mock of the MockIndexEnqueuer
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockIndexEnqueuerFrom(i IndexEnqueuer) *MockIndexEnqueuer {
+	return &MockIndexEnqueuer{
+		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
+			defaultHook: i.InferIndexConfiguration,
+		},
+		QueueIndexesFunc: &IndexEnqueuerQueueIndexesFunc{
+			defaultHook: i.QueueIndexes,
+		},
+	}
+}
+
+// IndexEnqueuerInferIndexConfigurationFunc describes the behavior when the
+// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
+// is invoked.
+type IndexEnqueuerInferIndexConfigurationFunc struct {
+	defaultHook func(context.Context, int) (*config.IndexConfiguration, error)
+	hooks       []func(context.Context, int) (*config.IndexConfiguration, error)
+	history     []IndexEnqueuerInferIndexConfigurationFuncCall
+	mutex       sync.Mutex
+}
+
+// InferIndexConfiguration delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockIndexEnqueuer) InferIndexConfiguration(v0 context.Context, v1 int) (*config.IndexConfiguration, error) {
+	r0, r1 := m.InferIndexConfigurationFunc.nextHook()(v0, v1)
+	m.InferIndexConfigurationFunc.appendCall(IndexEnqueuerInferIndexConfigurationFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
+// is invoked and the hook queue is empty.
+func (f *IndexEnqueuerInferIndexConfigurationFunc) SetDefaultHook(hook func(context.Context, int) (*config.IndexConfiguration, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InferIndexConfiguration method of the parent MockIndexEnqueuer instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *IndexEnqueuerInferIndexConfigurationFunc) PushHook(hook func(context.Context, int) (*config.IndexConfiguration, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *IndexEnqueuerInferIndexConfigurationFunc) SetDefaultReturn(r0 *config.IndexConfiguration, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (*config.IndexConfiguration, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *IndexEnqueuerInferIndexConfigurationFunc) PushReturn(r0 *config.IndexConfiguration, r1 error) {
+	f.PushHook(func(context.Context, int) (*config.IndexConfiguration, error) {
+		return r0, r1
+	})
+}
+
+func (f *IndexEnqueuerInferIndexConfigurationFunc) nextHook() func(context.Context, int) (*config.IndexConfiguration, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *IndexEnqueuerInferIndexConfigurationFunc) appendCall(r0 IndexEnqueuerInferIndexConfigurationFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// IndexEnqueuerInferIndexConfigurationFuncCall objects describing the
+// invocations of this function.
+func (f *IndexEnqueuerInferIndexConfigurationFunc) History() []IndexEnqueuerInferIndexConfigurationFuncCall {
+	f.mutex.Lock()
+	history := make([]IndexEnqueuerInferIndexConfigurationFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// IndexEnqueuerInferIndexConfigurationFuncCall is an object that describes
+// an invocation of method InferIndexConfiguration on an instance of
+// MockIndexEnqueuer.
+type IndexEnqueuerInferIndexConfigurationFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *config.IndexConfiguration
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c IndexEnqueuerInferIndexConfigurationFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c IndexEnqueuerInferIndexConfigurationFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// IndexEnqueuerQueueIndexesFunc describes the behavior when the
+// QueueIndexes method of the parent MockIndexEnqueuer instance is invoked.
+type IndexEnqueuerQueueIndexesFunc struct {
+	defaultHook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)
+	hooks       []func(context.Context, int, string, string, bool) ([]dbstore.Index, error)
+	history     []IndexEnqueuerQueueIndexesFuncCall
+	mutex       sync.Mutex
+}
+
+// QueueIndexes delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockIndexEnqueuer) QueueIndexes(v0 context.Context, v1 int, v2 string, v3 string, v4 bool) ([]dbstore.Index, error) {
+	r0, r1 := m.QueueIndexesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.QueueIndexesFunc.appendCall(IndexEnqueuerQueueIndexesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the QueueIndexes method
+// of the parent MockIndexEnqueuer instance is invoked and the hook queue is
+// empty.
+func (f *IndexEnqueuerQueueIndexesFunc) SetDefaultHook(hook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// QueueIndexes method of the parent MockIndexEnqueuer instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *IndexEnqueuerQueueIndexesFunc) PushHook(hook func(context.Context, int, string, string, bool) ([]dbstore.Index, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *IndexEnqueuerQueueIndexesFunc) SetDefaultReturn(r0 []dbstore.Index, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *IndexEnqueuerQueueIndexesFunc) PushReturn(r0 []dbstore.Index, r1 error) {
+	f.PushHook(func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+		return r0, r1
+	})
+}
+
+func (f *IndexEnqueuerQueueIndexesFunc) nextHook() func(context.Context, int, string, string, bool) ([]dbstore.Index, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *IndexEnqueuerQueueIndexesFunc) appendCall(r0 IndexEnqueuerQueueIndexesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of IndexEnqueuerQueueIndexesFuncCall objects
+// describing the invocations of this function.
+func (f *IndexEnqueuerQueueIndexesFunc) History() []IndexEnqueuerQueueIndexesFuncCall {
+	f.mutex.Lock()
+	history := make([]IndexEnqueuerQueueIndexesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// IndexEnqueuerQueueIndexesFuncCall is an object that describes an
+// invocation of method QueueIndexes on an instance of MockIndexEnqueuer.
+type IndexEnqueuerQueueIndexesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 bool
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []dbstore.Index
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c IndexEnqueuerQueueIndexesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c IndexEnqueuerQueueIndexesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// MockLSIFStore is a mock implementation of the LSIFStore interface (from
+// the package
+// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
+// used for unit testing.
+type MockLSIFStore struct {
+	// BulkMonikerResultsFunc is an instance of a mock function object
+	// controlling the behavior of the method BulkMonikerResults.
+	BulkMonikerResultsFunc *LSIFStoreBulkMonikerResultsFunc
+	// DefinitionsFunc is an instance of a mock function object controlling
+	// the behavior of the method Definitions.
+	DefinitionsFunc *LSIFStoreDefinitionsFunc
+	// DiagnosticsFunc is an instance of a mock function object controlling
+	// the behavior of the method Diagnostics.
+	DiagnosticsFunc *LSIFStoreDiagnosticsFunc
+	// DocumentationAtPositionFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationAtPosition.
+	DocumentationAtPositionFunc *LSIFStoreDocumentationAtPositionFunc
+	// DocumentationDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationDefinitions.
+	DocumentationDefinitionsFunc *LSIFStoreDocumentationDefinitionsFunc
+	// DocumentationPageFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationPage.
+	DocumentationPageFunc *LSIFStoreDocumentationPageFunc
+	// DocumentationPathInfoFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationPathInfo.
+	DocumentationPathInfoFunc *LSIFStoreDocumentationPathInfoFunc
+	// DocumentationSearchFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationSearch.
+	DocumentationSearchFunc *LSIFStoreDocumentationSearchFunc
+	// EnclosedRangesFunc is an instance of a mock function object
+	// controlling the behavior of the method EnclosedRanges.
+	EnclosedRangesFunc *LSIFStoreEnclosedRangesFunc
+	// EnclosingDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method EnclosingDefinitions.
+	EnclosingDefinitionsFunc *LSIFStoreEnclosingDefinitionsFunc
+	// ExistsFunc is an instance of a mock function object controlling the
+	// behavior of the method Exists.
+	ExistsFunc *LSIFStoreExistsFunc
+	// HoverFunc is an instance of a mock function object controlling the
+	// behavior of the method Hover.
+	HoverFunc *LSIFStoreHoverFunc
+	// ImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method Implementations.
+	ImplementationsFunc *LSIFStoreImplementationsFunc
+	// MonikersByPositionFunc is an instance of a mock function object
+	// controlling the behavior of the method MonikersByPosition.
+	MonikersByPositionFunc *LSIFStoreMonikersByPositionFunc
+	// PackageInformationFunc is an instance of a mock function object
+	// controlling the behavior of the method PackageInformation.
+	PackageInformationFunc *LSIFStorePackageInformationFunc
+	// RangesFunc is an instance of a mock function object controlling the
+	// behavior of the method Ranges.
+	RangesFunc *LSIFStoreRangesFunc
+	// ReferencesFunc is an instance of a mock function object controlling
+	// the behavior of the method References.
+	ReferencesFunc *LSIFStoreReferencesFunc
+	// TypeDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method TypeDefinitions.
+	TypeDefinitionsFunc *LSIFStoreTypeDefinitionsFunc
+}
+
+// NewMockLSIFStore creates a new mock of the LSIFStore interface. All
+// methods return zero values for all results, unless overwritten.
+func NewMockLSIFStore() *MockLSIFStore {
+	return &MockLSIFStore{
+		BulkMonikerResultsFunc: &LSIFStoreBulkMonikerResultsFunc{
+			defaultHook: func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+		DefinitionsFunc: &LSIFStoreDefinitionsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+		DiagnosticsFunc: &LSIFStoreDiagnosticsFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
+				return nil, 0, nil
+			},
+		},
+		DocumentationAtPositionFunc: &LSIFStoreDocumentationAtPositionFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]string, error) {
+				return nil, nil
+			},
+		},
+		DocumentationDefinitionsFunc: &LSIFStoreDocumentationDefinitionsFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+		DocumentationPageFunc: &LSIFStoreDocumentationPageFunc{
+			defaultHook: func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+				return nil, nil
+			},
+		},
+		DocumentationPathInfoFunc: &LSIFStoreDocumentationPathInfoFunc{
+			defaultHook: func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
+				return nil, nil
+			},
+		},
+		DocumentationSearchFunc: &LSIFStoreDocumentationSearchFunc{
+			defaultHook: func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error) {
+				return nil, nil
+			},
+		},
+		EnclosedRangesFunc: &LSIFStoreEnclosedRangesFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.Range, error) {
+				return nil, nil
+			},
+		},
+		EnclosingDefinitionsFunc: &LSIFStoreEnclosingDefinitionsFunc{
+			defaultHook: func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error) {
+				return nil, nil
+			},
+		},
+		ExistsFunc: &LSIFStoreExistsFunc{
+			defaultHook: func(context.Context, int, string) (bool, error) {
+				return false, nil
+			},
+		},
+		HoverFunc: &LSIFStoreHoverFunc{
+			defaultHook: func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
 				return "", lsifstore.Range{}, false, nil
 			},
 		},
+		ImplementationsFunc: &LSIFStoreImplementationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+		MonikersByPositionFunc: &LSIFStoreMonikersByPositionFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([][]precise.MonikerData, error) {
+				return nil, nil
+			},
+		},
+		PackageInformationFunc: &LSIFStorePackageInformationFunc{
+			defaultHook: func(context.Context, int, string, string) (precise.PackageInformationData, bool, error) {
+				return precise.PackageInformationData{}, false, nil
+			},
+		},
+		RangesFunc: &LSIFStoreRangesFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.CodeIntelligenceRange, error) {
+				return nil, nil
+			},
+		},
+		ReferencesFunc: &LSIFStoreReferencesFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+		TypeDefinitionsFunc: &LSIFStoreTypeDefinitionsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
+	}
+}
+
+// NewMockLSIFStoreFrom creates a new mock of the MockLSIFStore interface.
+// All methods delegate to the given implementation, unless overwritten.
+func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
+	return &MockLSIFStore{
+		BulkMonikerResultsFunc: &LSIFStoreBulkMonikerResultsFunc{
+			defaultHook: i.BulkMonikerResults,
+		},
+		DefinitionsFunc: &LSIFStoreDefinitionsFunc{
+			defaultHook: i.Definitions,
+		},
+		DiagnosticsFunc: &LSIFStoreDiagnosticsFunc{
+			defaultHook: i.Diagnostics,
+		},
+		DocumentationAtPositionFunc: &LSIFStoreDocumentationAtPositionFunc{
+			defaultHook: i.DocumentationAtPosition,
+		},
+		DocumentationDefinitionsFunc: &LSIFStoreDocumentationDefinitionsFunc{
+			defaultHook: i.DocumentationDefinitions,
+		},
+		DocumentationPageFunc: &LSIFStoreDocumentationPageFunc{
+			defaultHook: i.DocumentationPage,
+		},
+		DocumentationPathInfoFunc: &LSIFStoreDocumentationPathInfoFunc{
+			defaultHook: i.DocumentationPathInfo,
+		},
+		DocumentationSearchFunc: &LSIFStoreDocumentationSearchFunc{
+			defaultHook: i.DocumentationSearch,
+		},
+		EnclosedRangesFunc: &LSIFStoreEnclosedRangesFunc{
+			defaultHook: i.EnclosedRanges,
+		},
+		EnclosingDefinitionsFunc: &LSIFStoreEnclosingDefinitionsFunc{
+			defaultHook: i.EnclosingDefinitions,
+		},
+		ExistsFunc: &LSIFStoreExistsFunc{
+			defaultHook: i.Exists,
+		},
+		HoverFunc: &LSIFStoreHoverFunc{
+			defaultHook: i.Hover,
+		},
+		ImplementationsFunc: &LSIFStoreImplementationsFunc{
+			defaultHook: i.Implementations,
+		},
 		MonikersByPositionFunc: &LSIFStoreMonikersByPositionFunc{
-			defaultHook: func(context.Context, int, string, int, int) ([][]precise.MonikerData, error) {
-				return nil, nil
-			},
+			defaultHook: i.MonikersByPosition,
 		},
 		PackageInformationFunc: &LSIFStorePackageInformationFunc{
-			defaultHook: func(context.Context, int, string, string) (precise.PackageInformationData, bool, error) {
-				return precise.PackageInformationData{}, false, nil
-			},
+			defaultHook: i.PackageInformation,
 		},
 		RangesFunc: &LSIFStoreRangesFunc{
-			defaultHook: func(context.Context, int, string, int, int) ([]lsifstore.CodeIntelligenceRange, error) {
-				return nil, nil
-			},
+			defaultHook: i.Ranges,
 		},
 		ReferencesFunc: &LSIFStoreReferencesFunc{
-			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
-				return nil, 0, nil
-			},
+			defaultHook: i.References,
 		},
+		TypeDefinitionsFunc: &LSIFStoreTypeDefinitionsFunc{
+			defaultHook: i.TypeDefinitions,
+		},
+	}
+}
+
+// LSIFStoreBulkMonikerResultsFunc describes the behavior when the
+// BulkMonikerResults method of the parent MockLSIFStore instance is
+// invoked.
+type LSIFStoreBulkMonikerResultsFunc struct {
+	defaultHook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreBulkMonikerResultsFuncCall
+	mutex       sync.Mutex
+}
+
+// BulkMonikerResults delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) BulkMonikerResults(v0 context.Context, v1 string, v2 []int, v3 []precise.MonikerData, v4 int, v5 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.BulkMonikerResultsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.BulkMonikerResultsFunc.appendCall(LSIFStoreBulkMonikerResultsFuncCall{v0, v1, v2, v3, v4, v5, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the BulkMonikerResults
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreBulkMonikerResultsFunc) SetDefaultHook(hook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// BulkMonikerResults method of the parent MockLSIFStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LSIFStoreBulkMonikerResultsFunc) PushHook(hook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreBulkMonikerResultsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreBulkMonikerResultsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LSIFStoreBulkMonikerResultsFunc) nextHook() func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreBulkMonikerResultsFunc) appendCall(r0 LSIFStoreBulkMonikerResultsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreBulkMonikerResultsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreBulkMonikerResultsFunc) History() []LSIFStoreBulkMonikerResultsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreBulkMonikerResultsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreBulkMonikerResultsFuncCall is an object that describes an
+// invocation of method BulkMonikerResults on an instance of MockLSIFStore.
+type LSIFStoreBulkMonikerResultsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []precise.MonikerData
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []lsifstore.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreBulkMonikerResultsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreBulkMonikerResultsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// LSIFStoreDefinitionsFunc describes the behavior when the Definitions
+// method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreDefinitionsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// Definitions delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLSIFStore) Definitions(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.DefinitionsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.DefinitionsFunc.appendCall(LSIFStoreDefinitionsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the Definitions method
+// of the parent MockLSIFStore instance is invoked and the hook queue is
+// empty.
+func (f *LSIFStoreDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Definitions method of the parent MockLSIFStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LSIFStoreDefinitionsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreDefinitionsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreDefinitionsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LSIFStoreDefinitionsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreDefinitionsFunc) appendCall(r0 LSIFStoreDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreDefinitionsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreDefinitionsFunc) History() []LSIFStoreDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreDefinitionsFuncCall is an object that describes an invocation of
+// method Definitions on an instance of MockLSIFStore.
+type LSIFStoreDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []lsifstore.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
 }
 
-// NewMockLSIFStoreFrom creates a new mock of the MockLSIFStore interface.
-// All methods delegate to the given implementation, unless overwritten.
-func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
-	return &MockLSIFStore{
-		BulkMonikerResultsFunc: &LSIFStoreBulkMonikerResultsFunc{
-			defaultHook: i.BulkMonikerResults,
-		},
-		DefinitionsFunc: &LSIFStoreDefinitionsFunc{
-			defaultHook: i.Definitions,
-		},
-		DiagnosticsFunc: &LSIFStoreDiagnosticsFunc{
-			defaultHook: i.Diagnostics,
-		},
-		DocumentationAtPositionFunc: &LSIFStoreDocumentationAtPositionFunc{
-			defaultHook: i.DocumentationAtPosition,
-		},
-		DocumentationDefinitionsFunc: &LSIFStoreDocumentationDefinitionsFunc{
-			defaultHook: i.DocumentationDefinitions,
-		},
-		DocumentationPageFunc: &LSIFStoreDocumentationPageFunc{
-			defaultHook: i.DocumentationPage,
-		},
-		DocumentationPathInfoFunc: &LSIFStoreDocumentationPathInfoFunc{
-			defaultHook: i.DocumentationPathInfo,
-		},
-		ExistsFunc: &LSIFStoreExistsFunc{
-			defaultHook: i.Exists,
-		},
-		HoverFunc: &LSIFStoreHoverFunc{
-			defaultHook: i.Hover,
-		},
-		MonikersByPositionFunc: &LSIFStoreMonikersByPositionFunc{
-			defaultHook: i.MonikersByPosition,
-		},
-		PackageInformationFunc: &LSIFStorePackageInformationFunc{
-			defaultHook: i.PackageInformation,
-		},
-		RangesFunc: &LSIFStoreRangesFunc{
-			defaultHook: i.Ranges,
-		},
-		ReferencesFunc: &LSIFStoreReferencesFunc{
-			defaultHook: i.References,
-		},
-	}
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
 }
 
-// LSIFStoreBulkMonikerResultsFunc describes the behavior when the
-// BulkMonikerResults method of the parent MockLSIFStore instance is
-// invoked.
-type LSIFStoreBulkMonikerResultsFunc struct {
-	defaultHook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)
-	hooks       []func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)
-	history     []LSIFStoreBulkMonikerResultsFuncCall
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// LSIFStoreDiagnosticsFunc describes the behavior when the Diagnostics
+// method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreDiagnosticsFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)
+	hooks       []func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)
+	history     []LSIFStoreDiagnosticsFuncCall
 	mutex       sync.Mutex
 }
 
-// BulkMonikerResults delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) BulkMonikerResults(v0 context.Context, v1 string, v2 []int, v3 []precise.MonikerData, v4 int, v5 int) ([]lsifstore.Location, int, error) {
-	r0, r1, r2 := m.BulkMonikerResultsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
-	m.BulkMonikerResultsFunc.appendCall(LSIFStoreBulkMonikerResultsFuncCall{v0, v1, v2, v3, v4, v5, r0, r1, r2})
+// Diagnostics delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLSIFStore) Diagnostics(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]lsifstore.Diagnostic, int, error) {
+	r0, r1, r2 := m.DiagnosticsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.DiagnosticsFunc.appendCall(LSIFStoreDiagnosticsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
 	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the BulkMonikerResults
-// method of the parent MockLSIFStore instance is invoked and the hook queue
-// is empty.
-func (f *LSIFStoreBulkMonikerResultsFunc) SetDefaultHook(hook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)) {
+// SetDefaultHook sets function that is called when the Diagnostics method
+// of the parent MockLSIFStore instance is invoked and the hook queue is
+// empty.
+func (f *LSIFStoreDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// BulkMonikerResults method of the parent MockLSIFStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *LSIFStoreBulkMonikerResultsFunc) PushHook(hook func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error)) {
+// Diagnostics method of the parent MockLSIFStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LSIFStoreDiagnosticsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5665,21 +6854,21 @@ func (f *LSIFStoreBulkMonikerResultsFunc) PushHook(hook func(context.Context, st
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreBulkMonikerResultsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDiagnosticsFunc) SetDefaultReturn(r0 []lsifstore.Diagnostic, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
 		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreBulkMonikerResultsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.PushHook(func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDiagnosticsFunc) PushReturn(r0 []lsifstore.Diagnostic, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *LSIFStoreBulkMonikerResultsFunc) nextHook() func(context.Context, string, []int, []precise.MonikerData, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDiagnosticsFunc) nextHook() func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5692,96 +6881,216 @@ func (f *LSIFStoreBulkMonikerResultsFunc) nextHook() func(context.Context, strin
 	return hook
 }
 
-func (f *LSIFStoreBulkMonikerResultsFunc) appendCall(r0 LSIFStoreBulkMonikerResultsFuncCall) {
+func (f *LSIFStoreDiagnosticsFunc) appendCall(r0 LSIFStoreDiagnosticsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreBulkMonikerResultsFuncCall objects
+// History returns a sequence of LSIFStoreDiagnosticsFuncCall objects
 // describing the invocations of this function.
-func (f *LSIFStoreBulkMonikerResultsFunc) History() []LSIFStoreBulkMonikerResultsFuncCall {
+func (f *LSIFStoreDiagnosticsFunc) History() []LSIFStoreDiagnosticsFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreBulkMonikerResultsFuncCall, len(f.history))
+	history := make([]LSIFStoreDiagnosticsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreBulkMonikerResultsFuncCall is an object that describes an
-// invocation of method BulkMonikerResults on an instance of MockLSIFStore.
-type LSIFStoreBulkMonikerResultsFuncCall struct {
+// LSIFStoreDiagnosticsFuncCall is an object that describes an invocation of
+// method Diagnostics on an instance of MockLSIFStore.
+type LSIFStoreDiagnosticsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 string
+	Arg1 int
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 []int
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []lsifstore.Diagnostic
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreDiagnosticsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreDiagnosticsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// LSIFStoreDocumentationAtPositionFunc describes the behavior when the
+// DocumentationAtPosition method of the parent MockLSIFStore instance is
+// invoked.
+type LSIFStoreDocumentationAtPositionFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]string, error)
+	hooks       []func(context.Context, int, string, int, int) ([]string, error)
+	history     []LSIFStoreDocumentationAtPositionFuncCall
+	mutex       sync.Mutex
+}
+
+// DocumentationAtPosition delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) DocumentationAtPosition(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]string, error) {
+	r0, r1 := m.DocumentationAtPositionFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.DocumentationAtPositionFunc.appendCall(LSIFStoreDocumentationAtPositionFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// DocumentationAtPosition method of the parent MockLSIFStore instance is
+// invoked and the hook queue is empty.
+func (f *LSIFStoreDocumentationAtPositionFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DocumentationAtPosition method of the parent MockLSIFStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LSIFStoreDocumentationAtPositionFunc) PushHook(hook func(context.Context, int, string, int, int) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreDocumentationAtPositionFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreDocumentationAtPositionFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *LSIFStoreDocumentationAtPositionFunc) nextHook() func(context.Context, int, string, int, int) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreDocumentationAtPositionFunc) appendCall(r0 LSIFStoreDocumentationAtPositionFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreDocumentationAtPositionFuncCall
+// objects describing the invocations of this function.
+func (f *LSIFStoreDocumentationAtPositionFunc) History() []LSIFStoreDocumentationAtPositionFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreDocumentationAtPositionFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreDocumentationAtPositionFuncCall is an object that describes an
+// invocation of method DocumentationAtPosition on an instance of
+// MockLSIFStore.
+type LSIFStoreDocumentationAtPositionFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
 	// Arg3 is the value of the 4th argument passed to this method
 	// invocation.
-	Arg3 []precise.MonikerData
+	Arg3 int
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
 	Arg4 int
-	// Arg5 is the value of the 6th argument passed to this method
-	// invocation.
-	Arg5 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []lsifstore.Location
+	Result0 []string
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 int
-	// Result2 is the value of the 3rd result returned from this method
-	// invocation.
-	Result2 error
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreBulkMonikerResultsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+func (c LSIFStoreDocumentationAtPositionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreBulkMonikerResultsFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c LSIFStoreDocumentationAtPositionFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// LSIFStoreDefinitionsFunc describes the behavior when the Definitions
-// method of the parent MockLSIFStore instance is invoked.
-type LSIFStoreDefinitionsFunc struct {
-	defaultHook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
-	hooks       []func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
-	history     []LSIFStoreDefinitionsFuncCall
+// LSIFStoreDocumentationDefinitionsFunc describes the behavior when the
+// DocumentationDefinitions method of the parent MockLSIFStore instance is
+// invoked.
+type LSIFStoreDocumentationDefinitionsFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreDocumentationDefinitionsFuncCall
 	mutex       sync.Mutex
 }
 
-// Definitions delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockLSIFStore) Definitions(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]lsifstore.Location, int, error) {
-	r0, r1, r2 := m.DefinitionsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
-	m.DefinitionsFunc.appendCall(LSIFStoreDefinitionsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+// DocumentationDefinitions delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) DocumentationDefinitions(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.DocumentationDefinitionsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.DocumentationDefinitionsFunc.appendCall(LSIFStoreDocumentationDefinitionsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
 	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the Definitions method
-// of the parent MockLSIFStore instance is invoked and the hook queue is
-// empty.
-func (f *LSIFStoreDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+// SetDefaultHook sets function that is called when the
+// DocumentationDefinitions method of the parent MockLSIFStore instance is
+// invoked and the hook queue is empty.
+func (f *LSIFStoreDocumentationDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Definitions method of the parent MockLSIFStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *LSIFStoreDefinitionsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+// DocumentationDefinitions method of the parent MockLSIFStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LSIFStoreDocumentationDefinitionsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5789,21 +7098,21 @@ func (f *LSIFStoreDefinitionsFunc) PushHook(hook func(context.Context, int, stri
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDefinitionsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDocumentationDefinitionsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
 		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDefinitionsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDocumentationDefinitionsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *LSIFStoreDefinitionsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDocumentationDefinitionsFunc) nextHook() func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5816,26 +7125,27 @@ func (f *LSIFStoreDefinitionsFunc) nextHook() func(context.Context, int, string,
 	return hook
 }
 
-func (f *LSIFStoreDefinitionsFunc) appendCall(r0 LSIFStoreDefinitionsFuncCall) {
+func (f *LSIFStoreDocumentationDefinitionsFunc) appendCall(r0 LSIFStoreDocumentationDefinitionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDefinitionsFuncCall objects
-// describing the invocations of this function.
-func (f *LSIFStoreDefinitionsFunc) History() []LSIFStoreDefinitionsFuncCall {
+// History returns a sequence of LSIFStoreDocumentationDefinitionsFuncCall
+// objects describing the invocations of this function.
+func (f *LSIFStoreDocumentationDefinitionsFunc) History() []LSIFStoreDocumentationDefinitionsFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDefinitionsFuncCall, len(f.history))
+	history := make([]LSIFStoreDocumentationDefinitionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDefinitionsFuncCall is an object that describes an invocation of
-// method Definitions on an instance of MockLSIFStore.
-type LSIFStoreDefinitionsFuncCall struct {
+// LSIFStoreDocumentationDefinitionsFuncCall is an object that describes an
+// invocation of method DocumentationDefinitions on an instance of
+// MockLSIFStore.
+type LSIFStoreDocumentationDefinitionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -5851,12 +7161,6 @@ type LSIFStoreDefinitionsFuncCall struct {
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
 	Arg4 int
-	// Arg5 is the value of the 6th argument passed to this method
-	// invocation.
-	Arg5 int
-	// Arg6 is the value of the 7th argument passed to this method
-	// invocation.
-	Arg6 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 []lsifstore.Location
@@ -5870,45 +7174,45 @@ type LSIFStoreDefinitionsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreDefinitionsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+func (c LSIFStoreDocumentationDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDefinitionsFuncCall) Results() []interface{} {
+func (c LSIFStoreDocumentationDefinitionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// LSIFStoreDiagnosticsFunc describes the behavior when the Diagnostics
-// method of the parent MockLSIFStore instance is invoked.
-type LSIFStoreDiagnosticsFunc struct {
-	defaultHook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)
-	hooks       []func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)
-	history     []LSIFStoreDiagnosticsFuncCall
+// LSIFStoreDocumentationPageFunc describes the behavior when the
+// DocumentationPage method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreDocumentationPageFunc struct {
+	defaultHook func(context.Context, int, string) (*precise.DocumentationPageData, error)
+	hooks       []func(context.Context, int, string) (*precise.DocumentationPageData, error)
+	history     []LSIFStoreDocumentationPageFuncCall
 	mutex       sync.Mutex
 }
 
-// Diagnostics delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockLSIFStore) Diagnostics(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]lsifstore.Diagnostic, int, error) {
-	r0, r1, r2 := m.DiagnosticsFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.DiagnosticsFunc.appendCall(LSIFStoreDiagnosticsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
-	return r0, r1, r2
+// DocumentationPage delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) DocumentationPage(v0 context.Context, v1 int, v2 string) (*precise.DocumentationPageData, error) {
+	r0, r1 := m.DocumentationPageFunc.nextHook()(v0, v1, v2)
+	m.DocumentationPageFunc.appendCall(LSIFStoreDocumentationPageFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the Diagnostics method
-// of the parent MockLSIFStore instance is invoked and the hook queue is
-// empty.
-func (f *LSIFStoreDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)) {
+// SetDefaultHook sets function that is called when the DocumentationPage
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreDocumentationPageFunc) SetDefaultHook(hook func(context.Context, int, string) (*precise.DocumentationPageData, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Diagnostics method of the parent MockLSIFStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *LSIFStoreDiagnosticsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error)) {
+// DocumentationPage method of the parent MockLSIFStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LSIFStoreDocumentationPageFunc) PushHook(hook func(context.Context, int, string) (*precise.DocumentationPageData, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -5916,21 +7220,21 @@ func (f *LSIFStoreDiagnosticsFunc) PushHook(hook func(context.Context, int, stri
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDiagnosticsFunc) SetDefaultReturn(r0 []lsifstore.Diagnostic, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
-		return r0, r1, r2
+func (f *LSIFStoreDocumentationPageFunc) SetDefaultReturn(r0 *precise.DocumentationPageData, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDiagnosticsFunc) PushReturn(r0 []lsifstore.Diagnostic, r1 int, r2 error) {
-	f.PushHook(func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
-		return r0, r1, r2
+func (f *LSIFStoreDocumentationPageFunc) PushReturn(r0 *precise.DocumentationPageData, r1 error) {
+	f.PushHook(func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+		return r0, r1
 	})
 }
 
-func (f *LSIFStoreDiagnosticsFunc) nextHook() func(context.Context, int, string, int, int) ([]lsifstore.Diagnostic, int, error) {
+func (f *LSIFStoreDocumentationPageFunc) nextHook() func(context.Context, int, string) (*precise.DocumentationPageData, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -5943,26 +7247,26 @@ func (f *LSIFStoreDiagnosticsFunc) nextHook() func(context.Context, int, string,
 	return hook
 }
 
-func (f *LSIFStoreDiagnosticsFunc) appendCall(r0 LSIFStoreDiagnosticsFuncCall) {
+func (f *LSIFStoreDocumentationPageFunc) appendCall(r0 LSIFStoreDocumentationPageFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDiagnosticsFuncCall objects
+// History returns a sequence of LSIFStoreDocumentationPageFuncCall objects
 // describing the invocations of this function.
-func (f *LSIFStoreDiagnosticsFunc) History() []LSIFStoreDiagnosticsFuncCall {
+func (f *LSIFStoreDocumentationPageFunc) History() []LSIFStoreDocumentationPageFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDiagnosticsFuncCall, len(f.history))
+	history := make([]LSIFStoreDocumentationPageFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDiagnosticsFuncCall is an object that describes an invocation of
-// method Diagnostics on an instance of MockLSIFStore.
-type LSIFStoreDiagnosticsFuncCall struct {
+// LSIFStoreDocumentationPageFuncCall is an object that describes an
+// invocation of method DocumentationPage on an instance of MockLSIFStore.
+type LSIFStoreDocumentationPageFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -5972,66 +7276,56 @@ type LSIFStoreDiagnosticsFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []lsifstore.Diagnostic
+	Result0 *precise.DocumentationPageData
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 int
-	// Result2 is the value of the 3rd result returned from this method
-	// invocation.
-	Result2 error
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreDiagnosticsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c LSIFStoreDocumentationPageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDiagnosticsFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c LSIFStoreDocumentationPageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// LSIFStoreDocumentationAtPositionFunc describes the behavior when the
-// DocumentationAtPosition method of the parent MockLSIFStore instance is
+// LSIFStoreDocumentationPathInfoFunc describes the behavior when the
+// DocumentationPathInfo method of the parent MockLSIFStore instance is
 // invoked.
-type LSIFStoreDocumentationAtPositionFunc struct {
-	defaultHook func(context.Context, int, string, int, int) ([]string, error)
-	hooks       []func(context.Context, int, string, int, int) ([]string, error)
-	history     []LSIFStoreDocumentationAtPositionFuncCall
+type LSIFStoreDocumentationPathInfoFunc struct {
+	defaultHook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)
+	hooks       []func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)
+	history     []LSIFStoreDocumentationPathInfoFuncCall
 	mutex       sync.Mutex
 }
 
-// DocumentationAtPosition delegates to the next hook function in the queue
+// DocumentationPathInfo delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) DocumentationAtPosition(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]string, error) {
-	r0, r1 := m.DocumentationAtPositionFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.DocumentationAtPositionFunc.appendCall(LSIFStoreDocumentationAtPositionFuncCall{v0, v1, v2, v3, v4, r0, r1})
+func (m *MockLSIFStore) DocumentationPathInfo(v0 context.Context, v1 int, v2 string) (*precise.DocumentationPathInfoData, error) {
+	r0, r1 := m.DocumentationPathInfoFunc.nextHook()(v0, v1, v2)
+	m.DocumentationPathInfoFunc.appendCall(LSIFStoreDocumentationPathInfoFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
 // SetDefaultHook sets function that is called when the
-// DocumentationAtPosition method of the parent MockLSIFStore instance is
+// DocumentationPathInfo method of the parent MockLSIFStore instance is
 // invoked and the hook queue is empty.
-func (f *LSIFStoreDocumentationAtPositionFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]string, error)) {
+func (f *LSIFStoreDocumentationPathInfoFunc) SetDefaultHook(hook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// DocumentationAtPosition method of the parent MockLSIFStore instance
-// invokes the hook at the front of the queue and discards it. After the
-// queue is empty, the default hook function is invoked for any future
-// action.
-func (f *LSIFStoreDocumentationAtPositionFunc) PushHook(hook func(context.Context, int, string, int, int) ([]string, error)) {
+// DocumentationPathInfo method of the parent MockLSIFStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LSIFStoreDocumentationPathInfoFunc) PushHook(hook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -6039,21 +7333,21 @@ func (f *LSIFStoreDocumentationAtPositionFunc) PushHook(hook func(context.Contex
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDocumentationAtPositionFunc) SetDefaultReturn(r0 []string, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]string, error) {
+func (f *LSIFStoreDocumentationPathInfoFunc) SetDefaultReturn(r0 *precise.DocumentationPathInfoData, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDocumentationAtPositionFunc) PushReturn(r0 []string, r1 error) {
-	f.PushHook(func(context.Context, int, string, int, int) ([]string, error) {
+func (f *LSIFStoreDocumentationPathInfoFunc) PushReturn(r0 *precise.DocumentationPathInfoData, r1 error) {
+	f.PushHook(func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
 		return r0, r1
 	})
 }
 
-func (f *LSIFStoreDocumentationAtPositionFunc) nextHook() func(context.Context, int, string, int, int) ([]string, error) {
+func (f *LSIFStoreDocumentationPathInfoFunc) nextHook() func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -6066,27 +7360,27 @@ func (f *LSIFStoreDocumentationAtPositionFunc) nextHook() func(context.Context,
 	return hook
 }
 
-func (f *LSIFStoreDocumentationAtPositionFunc) appendCall(r0 LSIFStoreDocumentationAtPositionFuncCall) {
+func (f *LSIFStoreDocumentationPathInfoFunc) appendCall(r0 LSIFStoreDocumentationPathInfoFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDocumentationAtPositionFuncCall
+// History returns a sequence of LSIFStoreDocumentationPathInfoFuncCall
 // objects describing the invocations of this function.
-func (f *LSIFStoreDocumentationAtPositionFunc) History() []LSIFStoreDocumentationAtPositionFuncCall {
+func (f *LSIFStoreDocumentationPathInfoFunc) History() []LSIFStoreDocumentationPathInfoFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDocumentationAtPositionFuncCall, len(f.history))
+	history := make([]LSIFStoreDocumentationPathInfoFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDocumentationAtPositionFuncCall is an object that describes an
-// invocation of method DocumentationAtPosition on an instance of
+// LSIFStoreDocumentationPathInfoFuncCall is an object that describes an
+// invocation of method DocumentationPathInfo on an instance of
 // MockLSIFStore.
-type LSIFStoreDocumentationAtPositionFuncCall struct {
+type LSIFStoreDocumentationPathInfoFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -6096,63 +7390,56 @@ type LSIFStoreDocumentationAtPositionFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []string
+	Result0 *precise.DocumentationPathInfoData
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
-// invocation.
-func (c LSIFStoreDocumentationAtPositionFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+// invocation.
+func (c LSIFStoreDocumentationPathInfoFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDocumentationAtPositionFuncCall) Results() []interface{} {
+func (c LSIFStoreDocumentationPathInfoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// LSIFStoreDocumentationDefinitionsFunc describes the behavior when the
-// DocumentationDefinitions method of the parent MockLSIFStore instance is
+// LSIFStoreDocumentationSearchFunc describes the behavior when the
+// DocumentationSearch method of the parent MockLSIFStore instance is
 // invoked.
-type LSIFStoreDocumentationDefinitionsFunc struct {
-	defaultHook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)
-	hooks       []func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)
-	history     []LSIFStoreDocumentationDefinitionsFuncCall
+type LSIFStoreDocumentationSearchFunc struct {
+	defaultHook func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error)
+	hooks       []func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error)
+	history     []LSIFStoreDocumentationSearchFuncCall
 	mutex       sync.Mutex
 }
 
-// DocumentationDefinitions delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) DocumentationDefinitions(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]lsifstore.Location, int, error) {
-	r0, r1, r2 := m.DocumentationDefinitionsFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.DocumentationDefinitionsFunc.appendCall(LSIFStoreDocumentationDefinitionsFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
-	return r0, r1, r2
+// DocumentationSearch delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) DocumentationSearch(v0 context.Context, v1 lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error) {
+	r0, r1 := m.DocumentationSearchFunc.nextHook()(v0, v1)
+	m.DocumentationSearchFunc.appendCall(LSIFStoreDocumentationSearchFuncCall{v0, v1, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// DocumentationDefinitions method of the parent MockLSIFStore instance is
-// invoked and the hook queue is empty.
-func (f *LSIFStoreDocumentationDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)) {
+// SetDefaultHook sets function that is called when the DocumentationSearch
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreDocumentationSearchFunc) SetDefaultHook(hook func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// DocumentationDefinitions method of the parent MockLSIFStore instance
-// invokes the hook at the front of the queue and discards it. After the
-// queue is empty, the default hook function is invoked for any future
-// action.
-func (f *LSIFStoreDocumentationDefinitionsFunc) PushHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error)) {
+// DocumentationSearch method of the parent MockLSIFStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LSIFStoreDocumentationSearchFunc) PushHook(hook func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -6160,21 +7447,21 @@ func (f *LSIFStoreDocumentationDefinitionsFunc) PushHook(hook func(context.Conte
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDocumentationDefinitionsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
-		return r0, r1, r2
+func (f *LSIFStoreDocumentationSearchFunc) SetDefaultReturn(r0 []lsifstore.DocumentationSearchResult, r1 error) {
+	f.SetDefaultHook(func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDocumentationDefinitionsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
-	f.PushHook(func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
-		return r0, r1, r2
+func (f *LSIFStoreDocumentationSearchFunc) PushReturn(r0 []lsifstore.DocumentationSearchResult, r1 error) {
+	f.PushHook(func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error) {
+		return r0, r1
 	})
 }
 
-func (f *LSIFStoreDocumentationDefinitionsFunc) nextHook() func(context.Context, int, string, int, int) ([]lsifstore.Location, int, error) {
+func (f *LSIFStoreDocumentationSearchFunc) nextHook() func(context.Context, lsifstore.DocumentationSearchOptions) ([]lsifstore.DocumentationSearchResult, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -6187,94 +7474,81 @@ func (f *LSIFStoreDocumentationDefinitionsFunc) nextHook() func(context.Context,
 	return hook
 }
 
-func (f *LSIFStoreDocumentationDefinitionsFunc) appendCall(r0 LSIFStoreDocumentationDefinitionsFuncCall) {
+func (f *LSIFStoreDocumentationSearchFunc) appendCall(r0 LSIFStoreDocumentationSearchFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDocumentationDefinitionsFuncCall
+// History returns a sequence of LSIFStoreDocumentationSearchFuncCall
 // objects describing the invocations of this function.
-func (f *LSIFStoreDocumentationDefinitionsFunc) History() []LSIFStoreDocumentationDefinitionsFuncCall {
+func (f *LSIFStoreDocumentationSearchFunc) History() []LSIFStoreDocumentationSearchFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDocumentationDefinitionsFuncCall, len(f.history))
+	history := make([]LSIFStoreDocumentationSearchFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDocumentationDefinitionsFuncCall is an object that describes an
-// invocation of method DocumentationDefinitions on an instance of
-// MockLSIFStore.
-type LSIFStoreDocumentationDefinitionsFuncCall struct {
+// LSIFStoreDocumentationSearchFuncCall is an object that describes an
+// invocation of method DocumentationSearch on an instance of MockLSIFStore.
+type LSIFStoreDocumentationSearchFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
+	Arg1 lsifstore.DocumentationSearchOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []lsifstore.Location
+	Result0 []lsifstore.DocumentationSearchResult
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 int
-	// Result2 is the value of the 3rd result returned from this method
-	// invocation.
-	Result2 error
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreDocumentationDefinitionsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c LSIFStoreDocumentationSearchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDocumentationDefinitionsFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c LSIFStoreDocumentationSearchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// LSIFStoreDocumentationPageFunc describes the behavior when the
-// DocumentationPage method of the parent MockLSIFStore instance is invoked.
-type LSIFStoreDocumentationPageFunc struct {
-	defaultHook func(context.Context, int, string) (*precise.DocumentationPageData, error)
-	hooks       []func(context.Context, int, string) (*precise.DocumentationPageData, error)
-	history     []LSIFStoreDocumentationPageFuncCall
+// LSIFStoreEnclosedRangesFunc describes the behavior when the
+// EnclosedRanges method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreEnclosedRangesFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]lsifstore.Range, error)
+	hooks       []func(context.Context, int, string, int, int) ([]lsifstore.Range, error)
+	history     []LSIFStoreEnclosedRangesFuncCall
 	mutex       sync.Mutex
 }
 
-// DocumentationPage delegates to the next hook function in the queue and
+// EnclosedRanges delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) DocumentationPage(v0 context.Context, v1 int, v2 string) (*precise.DocumentationPageData, error) {
-	r0, r1 := m.DocumentationPageFunc.nextHook()(v0, v1, v2)
-	m.DocumentationPageFunc.appendCall(LSIFStoreDocumentationPageFuncCall{v0, v1, v2, r0, r1})
+func (m *MockLSIFStore) EnclosedRanges(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]lsifstore.Range, error) {
+	r0, r1 := m.EnclosedRangesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.EnclosedRangesFunc.appendCall(LSIFStoreEnclosedRangesFuncCall{v0, v1, v2, v3, v4, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the DocumentationPage
+// SetDefaultHook sets function that is called when the EnclosedRanges
 // method of the parent MockLSIFStore instance is invoked and the hook queue
 // is empty.
-func (f *LSIFStoreDocumentationPageFunc) SetDefaultHook(hook func(context.Context, int, string) (*precise.DocumentationPageData, error)) {
+func (f *LSIFStoreEnclosedRangesFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Range, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// DocumentationPage method of the parent MockLSIFStore instance invokes the
+// EnclosedRanges method of the parent MockLSIFStore instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *LSIFStoreDocumentationPageFunc) PushHook(hook func(context.Context, int, string) (*precise.DocumentationPageData, error)) {
+func (f *LSIFStoreEnclosedRangesFunc) PushHook(hook func(context.Context, int, string, int, int) ([]lsifstore.Range, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -6282,21 +7556,21 @@ func (f *LSIFStoreDocumentationPageFunc) PushHook(hook func(context.Context, int
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDocumentationPageFunc) SetDefaultReturn(r0 *precise.DocumentationPageData, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+func (f *LSIFStoreEnclosedRangesFunc) SetDefaultReturn(r0 []lsifstore.Range, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]lsifstore.Range, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDocumentationPageFunc) PushReturn(r0 *precise.DocumentationPageData, r1 error) {
-	f.PushHook(func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+func (f *LSIFStoreEnclosedRangesFunc) PushReturn(r0 []lsifstore.Range, r1 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]lsifstore.Range, error) {
 		return r0, r1
 	})
 }
 
-func (f *LSIFStoreDocumentationPageFunc) nextHook() func(context.Context, int, string) (*precise.DocumentationPageData, error) {
+func (f *LSIFStoreEnclosedRangesFunc) nextHook() func(context.Context, int, string, int, int) ([]lsifstore.Range, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -6309,26 +7583,26 @@ func (f *LSIFStoreDocumentationPageFunc) nextHook() func(context.Context, int, s
 	return hook
 }
 
-func (f *LSIFStoreDocumentationPageFunc) appendCall(r0 LSIFStoreDocumentationPageFuncCall) {
+func (f *LSIFStoreEnclosedRangesFunc) appendCall(r0 LSIFStoreEnclosedRangesFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDocumentationPageFuncCall objects
+// History returns a sequence of LSIFStoreEnclosedRangesFuncCall objects
 // describing the invocations of this function.
-func (f *LSIFStoreDocumentationPageFunc) History() []LSIFStoreDocumentationPageFuncCall {
+func (f *LSIFStoreEnclosedRangesFunc) History() []LSIFStoreEnclosedRangesFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDocumentationPageFuncCall, len(f.history))
+	history := make([]LSIFStoreEnclosedRangesFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDocumentationPageFuncCall is an object that describes an
-// invocation of method DocumentationPage on an instance of MockLSIFStore.
-type LSIFStoreDocumentationPageFuncCall struct {
+// LSIFStoreEnclosedRangesFuncCall is an object that describes an invocation
+// of method EnclosedRanges on an instance of MockLSIFStore.
+type LSIFStoreEnclosedRangesFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -6338,9 +7612,15 @@ type LSIFStoreDocumentationPageFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *precise.DocumentationPageData
+	Result0 []lsifstore.Range
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -6348,46 +7628,46 @@ type LSIFStoreDocumentationPageFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreDocumentationPageFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c LSIFStoreEnclosedRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDocumentationPageFuncCall) Results() []interface{} {
+func (c LSIFStoreEnclosedRangesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// LSIFStoreDocumentationPathInfoFunc describes the behavior when the
-// DocumentationPathInfo method of the parent MockLSIFStore instance is
+// LSIFStoreEnclosingDefinitionsFunc describes the behavior when the
+// EnclosingDefinitions method of the parent MockLSIFStore instance is
 // invoked.
-type LSIFStoreDocumentationPathInfoFunc struct {
-	defaultHook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)
-	hooks       []func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)
-	history     []LSIFStoreDocumentationPathInfoFuncCall
+type LSIFStoreEnclosingDefinitionsFunc struct {
+	defaultHook func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error)
+	hooks       []func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error)
+	history     []LSIFStoreEnclosingDefinitionsFuncCall
 	mutex       sync.Mutex
 }
 
-// DocumentationPathInfo delegates to the next hook function in the queue
-// and stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) DocumentationPathInfo(v0 context.Context, v1 int, v2 string) (*precise.DocumentationPathInfoData, error) {
-	r0, r1 := m.DocumentationPathInfoFunc.nextHook()(v0, v1, v2)
-	m.DocumentationPathInfoFunc.appendCall(LSIFStoreDocumentationPathInfoFuncCall{v0, v1, v2, r0, r1})
+// EnclosingDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) EnclosingDefinitions(v0 context.Context, v1 int, v2 string, v3 []lsifstore.Range) (map[int]lsifstore.Range, error) {
+	r0, r1 := m.EnclosingDefinitionsFunc.nextHook()(v0, v1, v2, v3)
+	m.EnclosingDefinitionsFunc.appendCall(LSIFStoreEnclosingDefinitionsFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// DocumentationPathInfo method of the parent MockLSIFStore instance is
-// invoked and the hook queue is empty.
-func (f *LSIFStoreDocumentationPathInfoFunc) SetDefaultHook(hook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)) {
+// SetDefaultHook sets function that is called when the EnclosingDefinitions
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreEnclosingDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// DocumentationPathInfo method of the parent MockLSIFStore instance invokes
+// EnclosingDefinitions method of the parent MockLSIFStore instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
-func (f *LSIFStoreDocumentationPathInfoFunc) PushHook(hook func(context.Context, int, string) (*precise.DocumentationPathInfoData, error)) {
+func (f *LSIFStoreEnclosingDefinitionsFunc) PushHook(hook func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -6395,21 +7675,21 @@ func (f *LSIFStoreDocumentationPathInfoFunc) PushHook(hook func(context.Context,
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreDocumentationPathInfoFunc) SetDefaultReturn(r0 *precise.DocumentationPathInfoData, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
+func (f *LSIFStoreEnclosingDefinitionsFunc) SetDefaultReturn(r0 map[int]lsifstore.Range, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreDocumentationPathInfoFunc) PushReturn(r0 *precise.DocumentationPathInfoData, r1 error) {
-	f.PushHook(func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
+func (f *LSIFStoreEnclosingDefinitionsFunc) PushReturn(r0 map[int]lsifstore.Range, r1 error) {
+	f.PushHook(func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error) {
 		return r0, r1
 	})
 }
 
-func (f *LSIFStoreDocumentationPathInfoFunc) nextHook() func(context.Context, int, string) (*precise.DocumentationPathInfoData, error) {
+func (f *LSIFStoreEnclosingDefinitionsFunc) nextHook() func(context.Context, int, string, []lsifstore.Range) (map[int]lsifstore.Range, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -6422,27 +7702,27 @@ func (f *LSIFStoreDocumentationPathInfoFunc) nextHook() func(context.Context, in
 	return hook
 }
 
-func (f *LSIFStoreDocumentationPathInfoFunc) appendCall(r0 LSIFStoreDocumentationPathInfoFuncCall) {
+func (f *LSIFStoreEnclosingDefinitionsFunc) appendCall(r0 LSIFStoreEnclosingDefinitionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreDocumentationPathInfoFuncCall
+// History returns a sequence of LSIFStoreEnclosingDefinitionsFuncCall
 // objects describing the invocations of this function.
-func (f *LSIFStoreDocumentationPathInfoFunc) History() []LSIFStoreDocumentationPathInfoFuncCall {
+func (f *LSIFStoreEnclosingDefinitionsFunc) History() []LSIFStoreEnclosingDefinitionsFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreDocumentationPathInfoFuncCall, len(f.history))
+	history := make([]LSIFStoreEnclosingDefinitionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreDocumentationPathInfoFuncCall is an object that describes an
-// invocation of method DocumentationPathInfo on an instance of
+// LSIFStoreEnclosingDefinitionsFuncCall is an object that describes an
+// invocation of method EnclosingDefinitions on an instance of
 // MockLSIFStore.
-type LSIFStoreDocumentationPathInfoFuncCall struct {
+type LSIFStoreEnclosingDefinitionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -6452,9 +7732,12 @@ type LSIFStoreDocumentationPathInfoFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 []lsifstore.Range
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *precise.DocumentationPathInfoData
+	Result0 map[int]lsifstore.Range
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -6462,13 +7745,13 @@ type LSIFStoreDocumentationPathInfoFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreDocumentationPathInfoFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c LSIFStoreEnclosingDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreDocumentationPathInfoFuncCall) Results() []interface{} {
+func (c LSIFStoreEnclosingDefinitionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -6600,17 +7883,141 @@ func (m *MockLSIFStore) Hover(v0 context.Context, v1 int, v2 string, v3 int, v4
 	return r0, r1, r2, r3
 }
 
-// SetDefaultHook sets function that is called when the Hover method of the
-// parent MockLSIFStore instance is invoked and the hook queue is empty.
-func (f *LSIFStoreHoverFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error)) {
+// SetDefaultHook sets function that is called when the Hover method of the
+// parent MockLSIFStore instance is invoked and the hook queue is empty.
+func (f *LSIFStoreHoverFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Hover method of the parent MockLSIFStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *LSIFStoreHoverFunc) PushHook(hook func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreHoverFunc) SetDefaultReturn(r0 string, r1 lsifstore.Range, r2 bool, r3 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreHoverFunc) PushReturn(r0 string, r1 lsifstore.Range, r2 bool, r3 error) {
+	f.PushHook(func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
+		return r0, r1, r2, r3
+	})
+}
+
+func (f *LSIFStoreHoverFunc) nextHook() func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreHoverFunc) appendCall(r0 LSIFStoreHoverFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreHoverFuncCall objects describing
+// the invocations of this function.
+func (f *LSIFStoreHoverFunc) History() []LSIFStoreHoverFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreHoverFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreHoverFuncCall is an object that describes an invocation of
+// method Hover on an instance of MockLSIFStore.
+type LSIFStoreHoverFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 lsifstore.Range
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 bool
+	// Result3 is the value of the 4th result returned from this method
+	// invocation.
+	Result3 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreHoverFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreHoverFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
+}
+
+// LSIFStoreImplementationsFunc describes the behavior when the
+// Implementations method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreImplementationsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// Implementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) Implementations(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.ImplementationsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.ImplementationsFunc.appendCall(LSIFStoreImplementationsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the Implementations
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreImplementationsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Hover method of the parent MockLSIFStore instance invokes the hook at the
-// front of the queue and discards it. After the queue is empty, the default
-// hook function is invoked for any future action.
-func (f *LSIFStoreHoverFunc) PushHook(hook func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error)) {
+// Implementations method of the parent MockLSIFStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LSIFStoreImplementationsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -6618,21 +8025,21 @@ func (f *LSIFStoreHoverFunc) PushHook(hook func(context.Context, int, string, in
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *LSIFStoreHoverFunc) SetDefaultReturn(r0 string, r1 lsifstore.Range, r2 bool, r3 error) {
-	f.SetDefaultHook(func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
-		return r0, r1, r2, r3
+func (f *LSIFStoreImplementationsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *LSIFStoreHoverFunc) PushReturn(r0 string, r1 lsifstore.Range, r2 bool, r3 error) {
-	f.PushHook(func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
-		return r0, r1, r2, r3
+func (f *LSIFStoreImplementationsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
 	})
 }
 
-func (f *LSIFStoreHoverFunc) nextHook() func(context.Context, int, string, int, int) (string, lsifstore.Range, bool, error) {
+func (f *LSIFStoreImplementationsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -6645,26 +8052,26 @@ func (f *LSIFStoreHoverFunc) nextHook() func(context.Context, int, string, int,
 	return hook
 }
 
-func (f *LSIFStoreHoverFunc) appendCall(r0 LSIFStoreHoverFuncCall) {
+func (f *LSIFStoreImplementationsFunc) appendCall(r0 LSIFStoreImplementationsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LSIFStoreHoverFuncCall objects describing
-// the invocations of this function.
-func (f *LSIFStoreHoverFunc) History() []LSIFStoreHoverFuncCall {
+// History returns a sequence of LSIFStoreImplementationsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreImplementationsFunc) History() []LSIFStoreImplementationsFuncCall {
 	f.mutex.Lock()
-	history := make([]LSIFStoreHoverFuncCall, len(f.history))
+	history := make([]LSIFStoreImplementationsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LSIFStoreHoverFuncCall is an object that describes an invocation of
-// method Hover on an instance of MockLSIFStore.
-type LSIFStoreHoverFuncCall struct {
+// LSIFStoreImplementationsFuncCall is an object that describes an
+// invocation of method Implementations on an instance of MockLSIFStore.
+type LSIFStoreImplementationsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -6680,30 +8087,33 @@ type LSIFStoreHoverFuncCall struct {
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
 	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 string
+	Result0 []lsifstore.Location
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 lsifstore.Range
+	Result1 int
 	// Result2 is the value of the 3rd result returned from this method
 	// invocation.
-	Result2 bool
-	// Result3 is the value of the 4th result returned from this method
-	// invocation.
-	Result3 error
+	Result2 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LSIFStoreHoverFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c LSIFStoreImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LSIFStoreHoverFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
+func (c LSIFStoreImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
 // LSIFStoreMonikersByPositionFunc describes the behavior when the
@@ -7187,6 +8597,133 @@ func (c LSIFStoreReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// LSIFStoreTypeDefinitionsFunc describes the behavior when the
+// TypeDefinitions method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreTypeDefinitionsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreTypeDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// TypeDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) TypeDefinitions(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.TypeDefinitionsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.TypeDefinitionsFunc.appendCall(LSIFStoreTypeDefinitionsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the TypeDefinitions
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreTypeDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// TypeDefinitions method of the parent MockLSIFStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LSIFStoreTypeDefinitionsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreTypeDefinitionsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreTypeDefinitionsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LSIFStoreTypeDefinitionsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreTypeDefinitionsFunc) appendCall(r0 LSIFStoreTypeDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreTypeDefinitionsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreTypeDefinitionsFunc) History() []LSIFStoreTypeDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreTypeDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreTypeDefinitionsFuncCall is an object that describes an
+// invocation of method TypeDefinitions on an instance of MockLSIFStore.
+type LSIFStoreTypeDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []lsifstore.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreTypeDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreTypeDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // MockRepoUpdaterClient is a mock implementation of the RepoUpdaterClient
 // interface (from the package
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
@@ -7332,3 +8869,144 @@ func (c RepoUpdaterClientEnqueueRepoUpdateFuncCall) Args() []interface{} {
 func (c RepoUpdaterClientEnqueueRepoUpdateFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// MockSymbolsClient is a mock implementation of the SymbolsClient interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
+// used for unit testing.
+type MockSymbolsClient struct {
+	// SearchFunc is an instance of a mock function object controlling the
+	// behavior of the method Search.
+	SearchFunc *SymbolsClientSearchFunc
+}
+
+// NewMockSymbolsClient creates a new mock of the SymbolsClient interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockSymbolsClient() *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: func(context.Context, search.SymbolsParameters) (*result.Symbols, error) {
+				return nil, nil
+			},
+		},
+	}
+}
+
+// NewMockSymbolsClientFrom creates a new mock of the MockSymbolsClient
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockSymbolsClientFrom(i SymbolsClient) *MockSymbolsClient {
+	return &MockSymbolsClient{
+		SearchFunc: &SymbolsClientSearchFunc{
+			defaultHook: i.Search,
+		},
+	}
+}
+
+// SymbolsClientSearchFunc describes the behavior when the Search method of
+// the parent MockSymbolsClient instance is invoked.
+type SymbolsClientSearchFunc struct {
+	defaultHook func(context.Context, search.SymbolsParameters) (*result.Symbols, error)
+	hooks       []func(context.Context, search.SymbolsParameters) (*result.Symbols, error)
+	history     []SymbolsClientSearchFuncCall
+	mutex       sync.Mutex
+}
+
+// Search delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockSymbolsClient) Search(v0 context.Context, v1 search.SymbolsParameters) (*result.Symbols, error) {
+	r0, r1 := m.SearchFunc.nextHook()(v0, v1)
+	m.SearchFunc.appendCall(SymbolsClientSearchFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Search method of the
+// parent MockSymbolsClient instance is invoked and the hook queue is empty.
+func (f *SymbolsClientSearchFunc) SetDefaultHook(hook func(context.Context, search.SymbolsParameters) (*result.Symbols, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Search method of the parent MockSymbolsClient instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *SymbolsClientSearchFunc) PushHook(hook func(context.Context, search.SymbolsParameters) (*result.Symbols, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *SymbolsClientSearchFunc) SetDefaultReturn(r0 *result.Symbols, r1 error) {
+	f.SetDefaultHook(func(context.Context, search.SymbolsParameters) (*result.Symbols, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *SymbolsClientSearchFunc) PushReturn(r0 *result.Symbols, r1 error) {
+	f.PushHook(func(context.Context, search.SymbolsParameters) (*result.Symbols, error) {
+		return r0, r1
+	})
+}
+
+func (f *SymbolsClientSearchFunc) nextHook() func(context.Context, search.SymbolsParameters) (*result.Symbols, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SymbolsClientSearchFunc) appendCall(r0 SymbolsClientSearchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SymbolsClientSearchFuncCall objects
+// describing the invocations of this function.
+func (f *SymbolsClientSearchFunc) History() []SymbolsClientSearchFuncCall {
+	f.mutex.Lock()
+	history := make([]SymbolsClientSearchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SymbolsClientSearchFuncCall is an object that describes an invocation of
+// method Search on an instance of MockSymbolsClient.
+type SymbolsClientSearchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 search.SymbolsParameters
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *result.Symbols
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SymbolsClientSearchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
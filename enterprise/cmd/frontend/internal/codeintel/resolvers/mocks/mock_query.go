@@ -40,12 +40,27 @@ type MockQueryResolver struct {
 	// HoverFunc is an instance of a mock function object controlling the
 	// behavior of the method Hover.
 	HoverFunc *QueryResolverHoverFunc
+	// ImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method Implementations.
+	ImplementationsFunc *QueryResolverImplementationsFunc
+	// IncomingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method IncomingCalls.
+	IncomingCallsFunc *QueryResolverIncomingCallsFunc
+	// OutgoingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method OutgoingCalls.
+	OutgoingCallsFunc *QueryResolverOutgoingCallsFunc
+	// PreciseFunc is an instance of a mock function object controlling the
+	// behavior of the method Precise.
+	PreciseFunc *QueryResolverPreciseFunc
 	// RangesFunc is an instance of a mock function object controlling the
 	// behavior of the method Ranges.
 	RangesFunc *QueryResolverRangesFunc
 	// ReferencesFunc is an instance of a mock function object controlling
 	// the behavior of the method References.
 	ReferencesFunc *QueryResolverReferencesFunc
+	// TypeDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method TypeDefinitions.
+	TypeDefinitionsFunc *QueryResolverTypeDefinitionsFunc
 }
 
 // NewMockQueryResolver creates a new mock of the QueryResolver interface.
@@ -92,6 +107,26 @@ func NewMockQueryResolver() *MockQueryResolver {
 				return "", lsifstore.Range{}, false, nil
 			},
 		},
+		ImplementationsFunc: &QueryResolverImplementationsFunc{
+			defaultHook: func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+				return nil, nil
+			},
+		},
+		IncomingCallsFunc: &QueryResolverIncomingCallsFunc{
+			defaultHook: func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+				return nil, nil
+			},
+		},
+		OutgoingCallsFunc: &QueryResolverOutgoingCallsFunc{
+			defaultHook: func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+				return nil, nil
+			},
+		},
+		PreciseFunc: &QueryResolverPreciseFunc{
+			defaultHook: func() bool {
+				return false
+			},
+		},
 		RangesFunc: &QueryResolverRangesFunc{
 			defaultHook: func(context.Context, int, int) ([]resolvers.AdjustedCodeIntelligenceRange, error) {
 				return nil, nil
@@ -102,6 +137,11 @@ func NewMockQueryResolver() *MockQueryResolver {
 				return nil, "", nil
 			},
 		},
+		TypeDefinitionsFunc: &QueryResolverTypeDefinitionsFunc{
+			defaultHook: func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -134,12 +174,27 @@ func NewMockQueryResolverFrom(i resolvers.QueryResolver) *MockQueryResolver {
 		HoverFunc: &QueryResolverHoverFunc{
 			defaultHook: i.Hover,
 		},
+		ImplementationsFunc: &QueryResolverImplementationsFunc{
+			defaultHook: i.Implementations,
+		},
+		IncomingCallsFunc: &QueryResolverIncomingCallsFunc{
+			defaultHook: i.IncomingCalls,
+		},
+		OutgoingCallsFunc: &QueryResolverOutgoingCallsFunc{
+			defaultHook: i.OutgoingCalls,
+		},
+		PreciseFunc: &QueryResolverPreciseFunc{
+			defaultHook: i.Precise,
+		},
 		RangesFunc: &QueryResolverRangesFunc{
 			defaultHook: i.Ranges,
 		},
 		ReferencesFunc: &QueryResolverReferencesFunc{
 			defaultHook: i.References,
 		},
+		TypeDefinitionsFunc: &QueryResolverTypeDefinitionsFunc{
+			defaultHook: i.TypeDefinitions,
+		},
 	}
 }
 
@@ -1054,6 +1109,449 @@ func (c QueryResolverHoverFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// QueryResolverImplementationsFunc describes the behavior when the
+// Implementations method of the parent MockQueryResolver instance is
+// invoked.
+type QueryResolverImplementationsFunc struct {
+	defaultHook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)
+	hooks       []func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)
+	history     []QueryResolverImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// Implementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockQueryResolver) Implementations(v0 context.Context, v1 int, v2 int) ([]resolvers.AdjustedLocation, error) {
+	r0, r1 := m.ImplementationsFunc.nextHook()(v0, v1, v2)
+	m.ImplementationsFunc.appendCall(QueryResolverImplementationsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Implementations
+// method of the parent MockQueryResolver instance is invoked and the hook
+// queue is empty.
+func (f *QueryResolverImplementationsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Implementations method of the parent MockQueryResolver instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *QueryResolverImplementationsFunc) PushHook(hook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *QueryResolverImplementationsFunc) SetDefaultReturn(r0 []resolvers.AdjustedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *QueryResolverImplementationsFunc) PushReturn(r0 []resolvers.AdjustedLocation, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *QueryResolverImplementationsFunc) nextHook() func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *QueryResolverImplementationsFunc) appendCall(r0 QueryResolverImplementationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of QueryResolverImplementationsFuncCall
+// objects describing the invocations of this function.
+func (f *QueryResolverImplementationsFunc) History() []QueryResolverImplementationsFuncCall {
+	f.mutex.Lock()
+	history := make([]QueryResolverImplementationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// QueryResolverImplementationsFuncCall is an object that describes an
+// invocation of method Implementations on an instance of MockQueryResolver.
+type QueryResolverImplementationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []resolvers.AdjustedLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c QueryResolverImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c QueryResolverImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// QueryResolverIncomingCallsFunc describes the behavior when the
+// IncomingCalls method of the parent MockQueryResolver instance is invoked.
+type QueryResolverIncomingCallsFunc struct {
+	defaultHook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)
+	hooks       []func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)
+	history     []QueryResolverIncomingCallsFuncCall
+	mutex       sync.Mutex
+}
+
+// IncomingCalls delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockQueryResolver) IncomingCalls(v0 context.Context, v1 int, v2 int, v3 int) ([]resolvers.CallHierarchyItem, error) {
+	r0, r1 := m.IncomingCallsFunc.nextHook()(v0, v1, v2, v3)
+	m.IncomingCallsFunc.appendCall(QueryResolverIncomingCallsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IncomingCalls method
+// of the parent MockQueryResolver instance is invoked and the hook queue is
+// empty.
+func (f *QueryResolverIncomingCallsFunc) SetDefaultHook(hook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IncomingCalls method of the parent MockQueryResolver instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *QueryResolverIncomingCallsFunc) PushHook(hook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *QueryResolverIncomingCallsFunc) SetDefaultReturn(r0 []resolvers.CallHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *QueryResolverIncomingCallsFunc) PushReturn(r0 []resolvers.CallHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+func (f *QueryResolverIncomingCallsFunc) nextHook() func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *QueryResolverIncomingCallsFunc) appendCall(r0 QueryResolverIncomingCallsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of QueryResolverIncomingCallsFuncCall objects
+// describing the invocations of this function.
+func (f *QueryResolverIncomingCallsFunc) History() []QueryResolverIncomingCallsFuncCall {
+	f.mutex.Lock()
+	history := make([]QueryResolverIncomingCallsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// QueryResolverIncomingCallsFuncCall is an object that describes an
+// invocation of method IncomingCalls on an instance of MockQueryResolver.
+type QueryResolverIncomingCallsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []resolvers.CallHierarchyItem
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c QueryResolverIncomingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c QueryResolverIncomingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// QueryResolverOutgoingCallsFunc describes the behavior when the
+// OutgoingCalls method of the parent MockQueryResolver instance is invoked.
+type QueryResolverOutgoingCallsFunc struct {
+	defaultHook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)
+	hooks       []func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)
+	history     []QueryResolverOutgoingCallsFuncCall
+	mutex       sync.Mutex
+}
+
+// OutgoingCalls delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockQueryResolver) OutgoingCalls(v0 context.Context, v1 int, v2 int, v3 int) ([]resolvers.CallHierarchyItem, error) {
+	r0, r1 := m.OutgoingCallsFunc.nextHook()(v0, v1, v2, v3)
+	m.OutgoingCallsFunc.appendCall(QueryResolverOutgoingCallsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the OutgoingCalls method
+// of the parent MockQueryResolver instance is invoked and the hook queue is
+// empty.
+func (f *QueryResolverOutgoingCallsFunc) SetDefaultHook(hook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// OutgoingCalls method of the parent MockQueryResolver instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *QueryResolverOutgoingCallsFunc) PushHook(hook func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *QueryResolverOutgoingCallsFunc) SetDefaultReturn(r0 []resolvers.CallHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *QueryResolverOutgoingCallsFunc) PushReturn(r0 []resolvers.CallHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+		return r0, r1
+	})
+}
+
+func (f *QueryResolverOutgoingCallsFunc) nextHook() func(context.Context, int, int, int) ([]resolvers.CallHierarchyItem, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *QueryResolverOutgoingCallsFunc) appendCall(r0 QueryResolverOutgoingCallsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of QueryResolverOutgoingCallsFuncCall objects
+// describing the invocations of this function.
+func (f *QueryResolverOutgoingCallsFunc) History() []QueryResolverOutgoingCallsFuncCall {
+	f.mutex.Lock()
+	history := make([]QueryResolverOutgoingCallsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// QueryResolverOutgoingCallsFuncCall is an object that describes an
+// invocation of method OutgoingCalls on an instance of MockQueryResolver.
+type QueryResolverOutgoingCallsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []resolvers.CallHierarchyItem
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c QueryResolverOutgoingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c QueryResolverOutgoingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// QueryResolverPreciseFunc describes the behavior when the Precise method
+// of the parent MockQueryResolver instance is invoked.
+type QueryResolverPreciseFunc struct {
+	defaultHook func() bool
+	hooks       []func() bool
+	history     []QueryResolverPreciseFuncCall
+	mutex       sync.Mutex
+}
+
+// Precise delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockQueryResolver) Precise() bool {
+	r0 := m.PreciseFunc.nextHook()()
+	m.PreciseFunc.appendCall(QueryResolverPreciseFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Precise method of
+// the parent MockQueryResolver instance is invoked and the hook queue is
+// empty.
+func (f *QueryResolverPreciseFunc) SetDefaultHook(hook func() bool) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Precise method of the parent MockQueryResolver instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *QueryResolverPreciseFunc) PushHook(hook func() bool) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *QueryResolverPreciseFunc) SetDefaultReturn(r0 bool) {
+	f.SetDefaultHook(func() bool {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *QueryResolverPreciseFunc) PushReturn(r0 bool) {
+	f.PushHook(func() bool {
+		return r0
+	})
+}
+
+func (f *QueryResolverPreciseFunc) nextHook() func() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *QueryResolverPreciseFunc) appendCall(r0 QueryResolverPreciseFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of QueryResolverPreciseFuncCall objects
+// describing the invocations of this function.
+func (f *QueryResolverPreciseFunc) History() []QueryResolverPreciseFuncCall {
+	f.mutex.Lock()
+	history := make([]QueryResolverPreciseFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// QueryResolverPreciseFuncCall is an object that describes an invocation of
+// method Precise on an instance of MockQueryResolver.
+type QueryResolverPreciseFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c QueryResolverPreciseFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c QueryResolverPreciseFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // QueryResolverRangesFunc describes the behavior when the Ranges method of
 // the parent MockQueryResolver instance is invoked.
 type QueryResolverRangesFunc struct {
@@ -1285,3 +1783,116 @@ func (c QueryResolverReferencesFuncCall) Args() []interface{} {
 func (c QueryResolverReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
+
+// QueryResolverTypeDefinitionsFunc describes the behavior when the
+// TypeDefinitions method of the parent MockQueryResolver instance is
+// invoked.
+type QueryResolverTypeDefinitionsFunc struct {
+	defaultHook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)
+	hooks       []func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)
+	history     []QueryResolverTypeDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// TypeDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockQueryResolver) TypeDefinitions(v0 context.Context, v1 int, v2 int) ([]resolvers.AdjustedLocation, error) {
+	r0, r1 := m.TypeDefinitionsFunc.nextHook()(v0, v1, v2)
+	m.TypeDefinitionsFunc.appendCall(QueryResolverTypeDefinitionsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the TypeDefinitions
+// method of the parent MockQueryResolver instance is invoked and the hook
+// queue is empty.
+func (f *QueryResolverTypeDefinitionsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// TypeDefinitions method of the parent MockQueryResolver instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *QueryResolverTypeDefinitionsFunc) PushHook(hook func(context.Context, int, int) ([]resolvers.AdjustedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *QueryResolverTypeDefinitionsFunc) SetDefaultReturn(r0 []resolvers.AdjustedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *QueryResolverTypeDefinitionsFunc) PushReturn(r0 []resolvers.AdjustedLocation, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *QueryResolverTypeDefinitionsFunc) nextHook() func(context.Context, int, int) ([]resolvers.AdjustedLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *QueryResolverTypeDefinitionsFunc) appendCall(r0 QueryResolverTypeDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of QueryResolverTypeDefinitionsFuncCall
+// objects describing the invocations of this function.
+func (f *QueryResolverTypeDefinitionsFunc) History() []QueryResolverTypeDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]QueryResolverTypeDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// QueryResolverTypeDefinitionsFuncCall is an object that describes an
+// invocation of method TypeDefinitions on an instance of MockQueryResolver.
+type QueryResolverTypeDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []resolvers.AdjustedLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c QueryResolverTypeDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c QueryResolverTypeDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
@@ -9,6 +9,8 @@ import (
 	graphqlbackend "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	resolvers "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers"
 	dbstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	search "github.com/sourcegraph/sourcegraph/internal/search"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
 	config "github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 )
 
@@ -34,6 +36,9 @@ type MockResolver struct {
 	// DeleteUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteUploadByID.
 	DeleteUploadByIDFunc *ResolverDeleteUploadByIDFunc
+	// DocumentationSearchFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentationSearch.
+	DocumentationSearchFunc *ResolverDocumentationSearchFunc
 	// GetConfigurationPoliciesFunc is an instance of a mock function object
 	// controlling the behavior of the method GetConfigurationPolicies.
 	GetConfigurationPoliciesFunc *ResolverGetConfigurationPoliciesFunc
@@ -50,6 +55,10 @@ type MockResolver struct {
 	// GetUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadByID.
 	GetUploadByIDFunc *ResolverGetUploadByIDFunc
+	// GetUploadValidationErrorsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetUploadValidationErrors.
+	GetUploadValidationErrorsFunc *ResolverGetUploadValidationErrorsFunc
 	// GetUploadsByIDsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadsByIDs.
 	GetUploadsByIDsFunc *ResolverGetUploadsByIDsFunc
@@ -63,6 +72,9 @@ type MockResolver struct {
 	// object controlling the behavior of the method
 	// InferredIndexConfiguration.
 	InferredIndexConfigurationFunc *ResolverInferredIndexConfigurationFunc
+	// PreviewRetentionFunc is an instance of a mock function object
+	// controlling the behavior of the method PreviewRetention.
+	PreviewRetentionFunc *ResolverPreviewRetentionFunc
 	// QueryResolverFunc is an instance of a mock function object
 	// controlling the behavior of the method QueryResolver.
 	QueryResolverFunc *ResolverQueryResolverFunc
@@ -112,6 +124,11 @@ func NewMockResolver() *MockResolver {
 				return nil
 			},
 		},
+		DocumentationSearchFunc: &ResolverDocumentationSearchFunc{
+			defaultHook: func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+				return nil, nil
+			},
+		},
 		GetConfigurationPoliciesFunc: &ResolverGetConfigurationPoliciesFunc{
 			defaultHook: func(context.Context, dbstore.GetConfigurationPoliciesOptions) ([]dbstore.ConfigurationPolicy, error) {
 				return nil, nil
@@ -137,6 +154,11 @@ func NewMockResolver() *MockResolver {
 				return dbstore.Upload{}, false, nil
 			},
 		},
+		GetUploadValidationErrorsFunc: &ResolverGetUploadValidationErrorsFunc{
+			defaultHook: func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+				return nil, nil
+			},
+		},
 		GetUploadsByIDsFunc: &ResolverGetUploadsByIDsFunc{
 			defaultHook: func(context.Context, ...int) ([]dbstore.Upload, error) {
 				return nil, nil
@@ -157,6 +179,11 @@ func NewMockResolver() *MockResolver {
 				return nil, false, nil
 			},
 		},
+		PreviewRetentionFunc: &ResolverPreviewRetentionFunc{
+			defaultHook: func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error) {
+				return nil, nil, nil
+			},
+		},
 		QueryResolverFunc: &ResolverQueryResolverFunc{
 			defaultHook: func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
 				return nil, nil
@@ -204,6 +231,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		DeleteUploadByIDFunc: &ResolverDeleteUploadByIDFunc{
 			defaultHook: i.DeleteUploadByID,
 		},
+		DocumentationSearchFunc: &ResolverDocumentationSearchFunc{
+			defaultHook: i.DocumentationSearch,
+		},
 		GetConfigurationPoliciesFunc: &ResolverGetConfigurationPoliciesFunc{
 			defaultHook: i.GetConfigurationPolicies,
 		},
@@ -219,6 +249,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		GetUploadByIDFunc: &ResolverGetUploadByIDFunc{
 			defaultHook: i.GetUploadByID,
 		},
+		GetUploadValidationErrorsFunc: &ResolverGetUploadValidationErrorsFunc{
+			defaultHook: i.GetUploadValidationErrors,
+		},
 		GetUploadsByIDsFunc: &ResolverGetUploadsByIDsFunc{
 			defaultHook: i.GetUploadsByIDs,
 		},
@@ -231,6 +264,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		InferredIndexConfigurationFunc: &ResolverInferredIndexConfigurationFunc{
 			defaultHook: i.InferredIndexConfiguration,
 		},
+		PreviewRetentionFunc: &ResolverPreviewRetentionFunc{
+			defaultHook: i.PreviewRetention,
+		},
 		QueryResolverFunc: &ResolverQueryResolverFunc{
 			defaultHook: i.QueryResolver,
 		},
@@ -792,6 +828,116 @@ func (c ResolverDeleteUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// ResolverDocumentationSearchFunc describes the behavior when the
+// DocumentationSearch method of the parent MockResolver instance is
+// invoked.
+type ResolverDocumentationSearchFunc struct {
+	defaultHook func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error)
+	hooks       []func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error)
+	history     []ResolverDocumentationSearchFuncCall
+	mutex       sync.Mutex
+}
+
+// DocumentationSearch delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) DocumentationSearch(v0 context.Context, v1 search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+	r0, r1 := m.DocumentationSearchFunc.nextHook()(v0, v1)
+	m.DocumentationSearchFunc.appendCall(ResolverDocumentationSearchFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DocumentationSearch
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverDocumentationSearchFunc) SetDefaultHook(hook func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DocumentationSearch method of the parent MockResolver instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *ResolverDocumentationSearchFunc) PushHook(hook func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverDocumentationSearchFunc) SetDefaultReturn(r0 []*result.DocumentationMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverDocumentationSearchFunc) PushReturn(r0 []*result.DocumentationMatch, r1 error) {
+	f.PushHook(func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverDocumentationSearchFunc) nextHook() func(context.Context, search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverDocumentationSearchFunc) appendCall(r0 ResolverDocumentationSearchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverDocumentationSearchFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverDocumentationSearchFunc) History() []ResolverDocumentationSearchFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverDocumentationSearchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverDocumentationSearchFuncCall is an object that describes an
+// invocation of method DocumentationSearch on an instance of MockResolver.
+type ResolverDocumentationSearchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 search.DocumentationParameters
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*result.DocumentationMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverDocumentationSearchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverDocumentationSearchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverGetConfigurationPoliciesFunc describes the behavior when the
 // GetConfigurationPolicies method of the parent MockResolver instance is
 // invoked.
@@ -1359,6 +1505,118 @@ func (c ResolverGetUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ResolverGetUploadValidationErrorsFunc describes the behavior when the
+// GetUploadValidationErrors method of the parent MockResolver instance is
+// invoked.
+type ResolverGetUploadValidationErrorsFunc struct {
+	defaultHook func(context.Context, int) ([]dbstore.UploadValidationError, error)
+	hooks       []func(context.Context, int) ([]dbstore.UploadValidationError, error)
+	history     []ResolverGetUploadValidationErrorsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetUploadValidationErrors delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockResolver) GetUploadValidationErrors(v0 context.Context, v1 int) ([]dbstore.UploadValidationError, error) {
+	r0, r1 := m.GetUploadValidationErrorsFunc.nextHook()(v0, v1)
+	m.GetUploadValidationErrorsFunc.appendCall(ResolverGetUploadValidationErrorsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetUploadValidationErrors method of the parent MockResolver instance is
+// invoked and the hook queue is empty.
+func (f *ResolverGetUploadValidationErrorsFunc) SetDefaultHook(hook func(context.Context, int) ([]dbstore.UploadValidationError, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetUploadValidationErrors method of the parent MockResolver instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ResolverGetUploadValidationErrorsFunc) PushHook(hook func(context.Context, int) ([]dbstore.UploadValidationError, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverGetUploadValidationErrorsFunc) SetDefaultReturn(r0 []dbstore.UploadValidationError, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverGetUploadValidationErrorsFunc) PushReturn(r0 []dbstore.UploadValidationError, r1 error) {
+	f.PushHook(func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverGetUploadValidationErrorsFunc) nextHook() func(context.Context, int) ([]dbstore.UploadValidationError, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverGetUploadValidationErrorsFunc) appendCall(r0 ResolverGetUploadValidationErrorsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverGetUploadValidationErrorsFuncCall
+// objects describing the invocations of this function.
+func (f *ResolverGetUploadValidationErrorsFunc) History() []ResolverGetUploadValidationErrorsFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverGetUploadValidationErrorsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverGetUploadValidationErrorsFuncCall is an object that describes an
+// invocation of method GetUploadValidationErrors on an instance of
+// MockResolver.
+type ResolverGetUploadValidationErrorsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []dbstore.UploadValidationError
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverGetUploadValidationErrorsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverGetUploadValidationErrorsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverGetUploadsByIDsFunc describes the behavior when the
 // GetUploadsByIDs method of the parent MockResolver instance is invoked.
 type ResolverGetUploadsByIDsFunc struct {
@@ -1808,6 +2066,121 @@ func (c ResolverInferredIndexConfigurationFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ResolverPreviewRetentionFunc describes the behavior when the
+// PreviewRetention method of the parent MockResolver instance is invoked.
+type ResolverPreviewRetentionFunc struct {
+	defaultHook func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error)
+	hooks       []func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error)
+	history     []ResolverPreviewRetentionFuncCall
+	mutex       sync.Mutex
+}
+
+// PreviewRetention delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) PreviewRetention(v0 context.Context, v1 int, v2 *int) ([]dbstore.Upload, []dbstore.Upload, error) {
+	r0, r1, r2 := m.PreviewRetentionFunc.nextHook()(v0, v1, v2)
+	m.PreviewRetentionFunc.appendCall(ResolverPreviewRetentionFuncCall{v0, v1, v2, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the PreviewRetention
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverPreviewRetentionFunc) SetDefaultHook(hook func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// PreviewRetention method of the parent MockResolver instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ResolverPreviewRetentionFunc) PushHook(hook func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverPreviewRetentionFunc) SetDefaultReturn(r0 []dbstore.Upload, r1 []dbstore.Upload, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverPreviewRetentionFunc) PushReturn(r0 []dbstore.Upload, r1 []dbstore.Upload, r2 error) {
+	f.PushHook(func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *ResolverPreviewRetentionFunc) nextHook() func(context.Context, int, *int) ([]dbstore.Upload, []dbstore.Upload, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverPreviewRetentionFunc) appendCall(r0 ResolverPreviewRetentionFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverPreviewRetentionFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverPreviewRetentionFunc) History() []ResolverPreviewRetentionFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverPreviewRetentionFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverPreviewRetentionFuncCall is an object that describes an
+// invocation of method PreviewRetention on an instance of MockResolver.
+type ResolverPreviewRetentionFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 *int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []dbstore.Upload
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 []dbstore.Upload
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverPreviewRetentionFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverPreviewRetentionFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // ResolverQueryResolverFunc describes the behavior when the QueryResolver
 // method of the parent MockResolver instance is invoked.
 type ResolverQueryResolverFunc struct {
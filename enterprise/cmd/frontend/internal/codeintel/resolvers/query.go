@@ -49,9 +49,16 @@ func (a *AdjustedCodeIntelligenceRange) ToDocumentation() *Documentation {
 // specifics (auth, validation, marshaling, etc.). This resolver is wrapped by a symmetrics resolver
 // in this package's graphql subpackage, which is exposed directly by the API.
 type QueryResolver interface {
+	// Precise returns true if the results of this resolver are sourced from precise code
+	// intelligence data. Search-based resolvers used as a fallback return false.
+	Precise() bool
 	Ranges(ctx context.Context, startLine, endLine int) ([]AdjustedCodeIntelligenceRange, error)
 	Definitions(ctx context.Context, line, character int) ([]AdjustedLocation, error)
 	References(ctx context.Context, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error)
+	TypeDefinitions(ctx context.Context, line, character int) ([]AdjustedLocation, error)
+	Implementations(ctx context.Context, line, character int) ([]AdjustedLocation, error)
+	IncomingCalls(ctx context.Context, line, character, depth int) ([]CallHierarchyItem, error)
+	OutgoingCalls(ctx context.Context, line, character, depth int) ([]CallHierarchyItem, error)
 	Hover(ctx context.Context, line, character int) (string, lsifstore.Range, bool, error)
 	Diagnostics(ctx context.Context, limit int) ([]AdjustedDiagnostic, int, error)
 	DocumentationPage(ctx context.Context, pathID string) (*precise.DocumentationPageData, error)
@@ -117,3 +124,5 @@ func newQueryResolver(
 		uploads:             uploads,
 	}
 }
+
+func (r *queryResolver) Precise() bool { return true }
@@ -0,0 +1,475 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+const slowCallHierarchyRequestThreshold = 5 * time.Second
+
+// CallHierarchyLimit is the maximum number of call sites gathered for a single definition.
+const CallHierarchyLimit = 100
+
+// MaximumCallHierarchyDepth is the maximum number of levels of a call hierarchy.
+const MaximumCallHierarchyDepth = 5
+
+// maximumCallHierarchyItems is the maximum number of items of a call hierarchy. This bounds the
+// amount of work done for symbols with many callers (or callees) at each level.
+const maximumCallHierarchyItems = 500
+
+// CallHierarchyItem is a definition within a call hierarchy along with the call sites that connect it
+// to the parent level of the hierarchy. For incoming calls, the call sites occur within the definition
+// of this item and reference the parent definition. For outgoing calls, the call sites occur within the
+// parent definition and reference the definition of this item. The next level of the hierarchy is held
+// in Calls, which is empty at the requested depth.
+type CallHierarchyItem struct {
+	Definition AdjustedLocation
+	CallSites  []AdjustedLocation
+	Calls      []CallHierarchyItem
+}
+
+// IncomingCalls returns the definitions calling the symbol at the given position, i.e., the definitions
+// enclosing each reference of the symbol, recursively up to the given depth. Enclosing definitions are
+// only known for indexes that emit the full range of their definitions.
+func (r *queryResolver) IncomingCalls(ctx context.Context, line, character, depth int) (_ []CallHierarchyItem, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "IncomingCalls", r.operations.incomingCalls, slowCallHierarchyRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("numUploads", len(r.uploads)),
+			log.String("uploads", uploadIDsToString(r.uploads)),
+			log.Int("line", line),
+			log.Int("character", character),
+			log.Int("depth", depth),
+		},
+	})
+	defer endObservation()
+
+	traversal, definitions, err := r.newCallHierarchyTraversal(ctx, line, character)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := traversal.incoming(ctx, definitions, clampCallHierarchyDepth(depth))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numItems", maximumCallHierarchyItems-traversal.remaining))
+
+	return items, nil
+}
+
+// OutgoingCalls returns the definitions of the symbols referenced from within the definition at the given
+// position, recursively up to the given depth. References to symbols defined within the definition itself
+// (e.g., local variables) are not included. Enclosed ranges are only known for indexes that emit the full
+// range of their definitions.
+func (r *queryResolver) OutgoingCalls(ctx context.Context, line, character, depth int) (_ []CallHierarchyItem, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "OutgoingCalls", r.operations.outgoingCalls, slowCallHierarchyRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("numUploads", len(r.uploads)),
+			log.String("uploads", uploadIDsToString(r.uploads)),
+			log.Int("line", line),
+			log.Int("character", character),
+			log.Int("depth", depth),
+		},
+	})
+	defer endObservation()
+
+	traversal, definitions, err := r.newCallHierarchyTraversal(ctx, line, character)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := traversal.outgoing(ctx, definitions, clampCallHierarchyDepth(depth))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numItems", maximumCallHierarchyItems-traversal.remaining))
+
+	return items, nil
+}
+
+func clampCallHierarchyDepth(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	if depth > MaximumCallHierarchyDepth {
+		return MaximumCallHierarchyDepth
+	}
+	return depth
+}
+
+// callHierarchyTraversal holds the state shared by all levels of a single call hierarchy request.
+type callHierarchyTraversal struct {
+	r *queryResolver
+
+	// uploadsByID caches the upload records of every index visited by the traversal.
+	uploadsByID map[int]dbstore.Dump
+
+	// visited holds the definitions whose calls have already been expanded. This prevents
+	// the traversal from looping on recursive calls.
+	visited map[lsifstore.Location]struct{}
+
+	// remaining is the number of items that can still be added to the hierarchy.
+	remaining int
+}
+
+// newCallHierarchyTraversal creates a new traversal and returns the location of the requested position
+// within each visible upload. These locations are the roots of the call hierarchy.
+func (r *queryResolver) newCallHierarchyTraversal(ctx context.Context, line, character int) (*callHierarchyTraversal, []lsifstore.Location, error) {
+	adjustedUploads, err := r.adjustUploads(ctx, line, character)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	traversal := &callHierarchyTraversal{
+		r:           r,
+		uploadsByID: make(map[int]dbstore.Dump, len(adjustedUploads)),
+		visited:     map[lsifstore.Location]struct{}{},
+		remaining:   maximumCallHierarchyItems,
+	}
+
+	definitions := make([]lsifstore.Location, 0, len(adjustedUploads))
+	for i := range adjustedUploads {
+		traversal.uploadsByID[adjustedUploads[i].Upload.ID] = adjustedUploads[i].Upload
+
+		definitions = append(definitions, lsifstore.Location{
+			DumpID: adjustedUploads[i].Upload.ID,
+			Path:   adjustedUploads[i].AdjustedPathInBundle,
+			Range: lsifstore.Range{
+				Start: adjustedUploads[i].AdjustedPosition,
+				End:   adjustedUploads[i].AdjustedPosition,
+			},
+		})
+	}
+
+	return traversal, definitions, nil
+}
+
+// callHierarchyEdge pairs a definition with the call sites that connect it to a parent definition.
+type callHierarchyEdge struct {
+	definition lsifstore.Location
+	callSites  []lsifstore.Location
+}
+
+// incoming returns the items calling any of the given definitions, which are all locations of the
+// same symbol.
+func (t *callHierarchyTraversal) incoming(ctx context.Context, definitions []lsifstore.Location, depth int) ([]CallHierarchyItem, error) {
+	references, err := t.references(ctx, definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := t.enclosingDefinitions(ctx, references)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.items(ctx, edges, depth, t.incoming)
+}
+
+// outgoing returns the items called from within any of the given definitions, which are all locations
+// of the same symbol.
+func (t *callHierarchyTraversal) outgoing(ctx context.Context, definitions []lsifstore.Location, depth int) ([]CallHierarchyItem, error) {
+	var edges []callHierarchyEdge
+	edgeIndexes := map[lsifstore.Location]int{}
+
+	for _, definition := range definitions {
+		ranges, err := t.r.lsifStore.EnclosedRanges(ctx, definition.DumpID, definition.Path, definition.Range.Start.Line, definition.Range.Start.Character)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.EnclosedRanges")
+		}
+		if len(ranges) > CallHierarchyLimit {
+			ranges = ranges[:CallHierarchyLimit]
+		}
+
+		// Symbols defined within the definition itself are not calls
+		enclosed := make(map[lsifstore.Location]struct{}, len(ranges))
+		for _, rn := range ranges {
+			enclosed[lsifstore.Location{DumpID: definition.DumpID, Path: definition.Path, Range: rn}] = struct{}{}
+		}
+
+		for _, rn := range ranges {
+			callSite := lsifstore.Location{DumpID: definition.DumpID, Path: definition.Path, Range: rn}
+
+			callees, err := t.definitions(ctx, callSite)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, callee := range callees {
+				if _, ok := enclosed[callee]; ok {
+					continue
+				}
+
+				i, ok := edgeIndexes[callee]
+				if !ok {
+					i = len(edges)
+					edgeIndexes[callee] = i
+					edges = append(edges, callHierarchyEdge{definition: callee})
+				}
+				edges[i].callSites = append(edges[i].callSites, callSite)
+			}
+		}
+	}
+
+	return t.items(ctx, edges, depth, t.outgoing)
+}
+
+// items converts the given edges into call hierarchy items, expanding the next level of each item via
+// the given function until the given depth is reached.
+func (t *callHierarchyTraversal) items(ctx context.Context, edges []callHierarchyEdge, depth int, next func(ctx context.Context, definitions []lsifstore.Location, depth int) ([]CallHierarchyItem, error)) ([]CallHierarchyItem, error) {
+	if len(edges) > t.remaining {
+		edges = edges[:t.remaining]
+	}
+	t.remaining -= len(edges)
+
+	items := make([]CallHierarchyItem, 0, len(edges))
+	for _, edge := range edges {
+		definition, err := t.r.adjustLocation(ctx, t.uploadsByID[edge.definition.DumpID], edge.definition)
+		if err != nil {
+			return nil, err
+		}
+
+		callSites, err := t.r.adjustLocations(ctx, t.uploadsByID, edge.callSites)
+		if err != nil {
+			return nil, err
+		}
+
+		item := CallHierarchyItem{
+			Definition: definition,
+			CallSites:  callSites,
+		}
+
+		if _, ok := t.visited[edge.definition]; !ok && depth > 1 && t.remaining > 0 {
+			t.visited[edge.definition] = struct{}{}
+
+			if item.Calls, err = next(ctx, []lsifstore.Location{edge.definition}, depth-1); err != nil {
+				return nil, err
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// references returns the locations referencing the symbol defined at any of the given locations. This
+// includes references within indexes that import the symbol. The given locations are not included.
+func (t *callHierarchyTraversal) references(ctx context.Context, definitions []lsifstore.Location) ([]lsifstore.Location, error) {
+	adjustedUploads := t.adjustedUploads(definitions)
+
+	var locations []lsifstore.Location
+	for i := range adjustedUploads {
+		uploadLocations, _, err := t.r.lsifStore.References(
+			ctx,
+			adjustedUploads[i].Upload.ID,
+			adjustedUploads[i].AdjustedPathInBundle,
+			adjustedUploads[i].AdjustedPosition.Line,
+			adjustedUploads[i].AdjustedPosition.Character,
+			CallHierarchyLimit,
+			0,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.References")
+		}
+
+		locations = append(locations, uploadLocations...)
+	}
+
+	orderedMonikers, err := t.r.orderedMonikers(ctx, adjustedUploads, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orderedMonikers) > 0 {
+		definitionUploads, err := t.r.definitionUploads(ctx, orderedMonikers)
+		if err != nil {
+			return nil, err
+		}
+
+		ids, _, _, err := t.r.uploadIDsWithReferences(ctx, orderedMonikers, nil, maximumIndexesPerMonikerSearch, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		referenceUploads, err := t.r.uploadsByIDs(ctx, ids, t.uploadsByID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Search the indexes defining or referencing the symbol, other than the ones we have
+		// already traversed above.
+		var uploads []dbstore.Dump
+		for _, upload := range append(definitionUploads, referenceUploads...) {
+			if !containsUpload(adjustedUploads, upload.ID) && !containsDump(uploads, upload.ID) {
+				uploads = append(uploads, upload)
+			}
+			t.uploadsByID[upload.ID] = upload
+		}
+
+		if len(uploads) > 0 {
+			remoteLocations, _, err := t.r.monikerLocations(ctx, uploads, orderedMonikers, "references", CallHierarchyLimit, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			locations = append(locations, remoteLocations...)
+		}
+	}
+
+	filtered := locations[:0]
+	seen := make(map[lsifstore.Location]struct{}, len(locations))
+	for _, location := range locations {
+		if _, ok := seen[location]; ok || isDefinitionLocation(adjustedUploads, location) {
+			continue
+		}
+
+		seen[location] = struct{}{}
+		filtered = append(filtered, location)
+	}
+
+	return filtered, nil
+}
+
+// definitions returns the locations defining the symbol referenced at the given location. Definitions
+// within other indexes are found via a moniker search.
+func (t *callHierarchyTraversal) definitions(ctx context.Context, location lsifstore.Location) ([]lsifstore.Location, error) {
+	locations, _, err := t.r.lsifStore.Definitions(ctx, location.DumpID, location.Path, location.Range.Start.Line, location.Range.Start.Character, DefinitionsLimit, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifStore.Definitions")
+	}
+	if len(locations) > 0 {
+		return locations, nil
+	}
+
+	orderedMonikers, err := t.r.orderedMonikers(ctx, t.adjustedUploads([]lsifstore.Location{location}), "import")
+	if err != nil || len(orderedMonikers) == 0 {
+		return nil, err
+	}
+
+	uploads, err := t.r.definitionUploads(ctx, orderedMonikers)
+	if err != nil || len(uploads) == 0 {
+		return nil, err
+	}
+	for i := range uploads {
+		t.uploadsByID[uploads[i].ID] = uploads[i]
+	}
+
+	locations, _, err = t.r.monikerLocations(ctx, uploads, orderedMonikers, "definitions", DefinitionsLimit, 0)
+	return locations, err
+}
+
+// enclosingDefinitions groups the given locations by the definition enclosing them. Locations without an
+// enclosing definition are dropped. The resulting edges are ordered by first occurrence.
+func (t *callHierarchyTraversal) enclosingDefinitions(ctx context.Context, locations []lsifstore.Location) ([]callHierarchyEdge, error) {
+	type documentKey struct {
+		dumpID int
+		path   string
+	}
+
+	var documentKeys []documentKey
+	rangesByDocument := map[documentKey][]lsifstore.Range{}
+	for _, location := range locations {
+		key := documentKey{location.DumpID, location.Path}
+		if _, ok := rangesByDocument[key]; !ok {
+			documentKeys = append(documentKeys, key)
+		}
+		rangesByDocument[key] = append(rangesByDocument[key], location.Range)
+	}
+
+	var edges []callHierarchyEdge
+	edgeIndexes := map[lsifstore.Location]int{}
+
+	for _, key := range documentKeys {
+		ranges := rangesByDocument[key]
+
+		enclosingRanges, err := t.r.lsifStore.EnclosingDefinitions(ctx, key.dumpID, key.path, ranges)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.EnclosingDefinitions")
+		}
+
+		for i, rn := range ranges {
+			enclosingRange, ok := enclosingRanges[i]
+			if !ok {
+				continue
+			}
+
+			definition := lsifstore.Location{DumpID: key.dumpID, Path: key.path, Range: enclosingRange}
+
+			j, ok := edgeIndexes[definition]
+			if !ok {
+				j = len(edges)
+				edgeIndexes[definition] = j
+				edges = append(edges, callHierarchyEdge{definition: definition})
+			}
+			edges[j].callSites = append(edges[j].callSites, lsifstore.Location{DumpID: key.dumpID, Path: key.path, Range: rn})
+		}
+	}
+
+	return edges, nil
+}
+
+// adjustedUploads converts the given locations into the adjusted upload values expected by the query
+// helpers. The locations are already relative to their index, so no adjustment takes place.
+func (t *callHierarchyTraversal) adjustedUploads(locations []lsifstore.Location) []adjustedUpload {
+	adjustedUploads := make([]adjustedUpload, 0, len(locations))
+	for _, location := range locations {
+		upload := t.uploadsByID[location.DumpID]
+
+		adjustedUploads = append(adjustedUploads, adjustedUpload{
+			Upload:               upload,
+			AdjustedPath:         upload.Root + location.Path,
+			AdjustedPosition:     location.Range.Start,
+			AdjustedPathInBundle: location.Path,
+		})
+	}
+
+	return adjustedUploads
+}
+
+// isDefinitionLocation returns true if the given location encloses the position of one of the given
+// adjusted uploads.
+func isDefinitionLocation(adjustedUploads []adjustedUpload, location lsifstore.Location) bool {
+	for i := range adjustedUploads {
+		if location.DumpID == adjustedUploads[i].Upload.ID && location.Path == adjustedUploads[i].AdjustedPathInBundle {
+			if rangeContainsPosition(location.Range, adjustedUploads[i].AdjustedPosition) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsUpload(adjustedUploads []adjustedUpload, id int) bool {
+	for i := range adjustedUploads {
+		if adjustedUploads[i].Upload.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsDump(uploads []dbstore.Dump, id int) bool {
+	for i := range uploads {
+		if uploads[i].ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestIncomingCalls(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+
+	// References of the requested symbol, then of its caller in a.go, then of its caller in b.go
+	mockLSIFStore.ReferencesFunc.PushReturn([]lsifstore.Location{
+		{DumpID: 50, Path: "a.go", Range: testRange1},
+		{DumpID: 50, Path: "a.go", Range: testRange2},
+		{DumpID: 50, Path: "b.go", Range: testRange1},
+	}, 3, nil)
+	mockLSIFStore.ReferencesFunc.PushReturn([]lsifstore.Location{
+		{DumpID: 50, Path: "c.go", Range: testRange5},
+	}, 1, nil)
+
+	enclosingRanges := map[string]lsifstore.Range{
+		"a.go": testRange3,
+		"b.go": testRange4,
+		"c.go": testRange1,
+	}
+	mockLSIFStore.EnclosingDefinitionsFunc.SetDefaultHook(func(ctx context.Context, bundleID int, path string, ranges []lsifstore.Range) (map[int]lsifstore.Range, error) {
+		enclosing := map[int]lsifstore.Range{}
+		for i := range ranges {
+			enclosing[i] = enclosingRanges[path]
+		}
+		return enclosing, nil
+	})
+
+	uploads := []dbstore.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	items, err := resolver.IncomingCalls(context.Background(), 10, 20, 2)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	expectedItems := []CallHierarchyItem{
+		{
+			Definition: AdjustedLocation{Dump: uploads[0], Path: "sub1/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange3},
+			CallSites: []AdjustedLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+				{Dump: uploads[0], Path: "sub1/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange2},
+			},
+			Calls: []CallHierarchyItem{
+				{
+					Definition: AdjustedLocation{Dump: uploads[0], Path: "sub1/c.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+					CallSites: []AdjustedLocation{
+						{Dump: uploads[0], Path: "sub1/c.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange5},
+					},
+				},
+			},
+		},
+		{
+			Definition: AdjustedLocation{Dump: uploads[0], Path: "sub1/b.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange4},
+			CallSites: []AdjustedLocation{
+				{Dump: uploads[0], Path: "sub1/b.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+			},
+			Calls: []CallHierarchyItem{},
+		},
+	}
+	if diff := cmp.Diff(expectedItems, items); diff != "" {
+		t.Errorf("unexpected items (-want +got):\n%s", diff)
+	}
+}
+
+func TestOutgoingCalls(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+
+	mockLSIFStore.EnclosedRangesFunc.PushReturn([]lsifstore.Range{testRange1, testRange2, testRange3}, nil)
+
+	// testRange1 calls a function in another file, testRange2 declares a local variable which
+	// is referenced by testRange3.
+	mockLSIFStore.DefinitionsFunc.PushReturn([]lsifstore.Location{{DumpID: 50, Path: "b.go", Range: testRange4}}, 1, nil)
+	mockLSIFStore.DefinitionsFunc.PushReturn([]lsifstore.Location{{DumpID: 50, Path: "deadbeef", Range: testRange2}}, 1, nil)
+	mockLSIFStore.DefinitionsFunc.PushReturn([]lsifstore.Location{{DumpID: 50, Path: "deadbeef", Range: testRange2}}, 1, nil)
+
+	uploads := []dbstore.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	items, err := resolver.OutgoingCalls(context.Background(), 10, 20, 1)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+
+	expectedItems := []CallHierarchyItem{
+		{
+			Definition: AdjustedLocation{Dump: uploads[0], Path: "sub1/b.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange4},
+			CallSites: []AdjustedLocation{
+				{Dump: uploads[0], Path: "sub1/deadbeef", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedItems, items); diff != "" {
+		t.Errorf("unexpected items (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,144 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+const slowImplementationsRequestThreshold = time.Second
+
+// ImplementationsLimit is maximum the number of locations returned from Implementations.
+const ImplementationsLimit = 100
+
+// Implementations returns the list of source locations that implement the symbol at the given position.
+// Implementations within the visible indexes are found via an LSIF graph traversal; implementations in
+// other indexes are found via a moniker search over the indexes that import the symbol.
+func (r *queryResolver) Implementations(ctx context.Context, line, character int) (_ []AdjustedLocation, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "Implementations", r.operations.implementations, slowImplementationsRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("numUploads", len(r.uploads)),
+			log.String("uploads", uploadIDsToString(r.uploads)),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer endObservation()
+
+	// Adjust the path and position for each visible upload based on its git difference to
+	// the target commit.
+
+	adjustedUploads, err := r.adjustUploads(ctx, line, character)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadsByID := make(map[int]dbstore.Dump, len(adjustedUploads))
+	for i := range adjustedUploads {
+		uploadsByID[adjustedUploads[i].Upload.ID] = adjustedUploads[i].Upload
+	}
+
+	// Gather the "local" implementation locations that are reachable via an implementationResult
+	// vertex. Unlike definitions, every visible index may contribute implementations.
+
+	var locations []lsifstore.Location
+	for i := range adjustedUploads {
+		traceLog(log.Int("uploadID", adjustedUploads[i].Upload.ID))
+
+		uploadLocations, _, err := r.lsifStore.Implementations(
+			ctx,
+			adjustedUploads[i].Upload.ID,
+			adjustedUploads[i].AdjustedPathInBundle,
+			adjustedUploads[i].AdjustedPosition.Line,
+			adjustedUploads[i].AdjustedPosition.Character,
+			ImplementationsLimit-len(locations),
+			0,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.Implementations")
+		}
+
+		locations = append(locations, uploadLocations...)
+		if len(locations) >= ImplementationsLimit {
+			return r.adjustLocations(ctx, uploadsByID, locations)
+		}
+	}
+	traceLog(log.Int("numLocalLocations", len(locations)))
+
+	// Gather all monikers attached to the ranges enclosing the requested position. Implementations
+	// of the symbol in other indexes are keyed by the moniker of the symbol they implement.
+	orderedMonikers, err := r.orderedMonikers(ctx, adjustedUploads, "")
+	if err != nil {
+		return nil, err
+	}
+	traceLog(
+		log.Int("numMonikers", len(orderedMonikers)),
+		log.String("monikers", monikersToString(orderedMonikers)),
+	)
+	if len(orderedMonikers) == 0 {
+		return r.adjustLocations(ctx, uploadsByID, locations)
+	}
+
+	// Determine the set of remote uploads over which we need to perform a moniker search. An index
+	// can only implement a symbol it imports, so these are the indexes referencing one of the ordered
+	// monikers. This should not include any of the indexes we have already traversed above.
+	ignoreIDs := make([]int, 0, len(adjustedUploads))
+	for i := range adjustedUploads {
+		ignoreIDs = append(ignoreIDs, adjustedUploads[i].Upload.ID)
+	}
+
+	ids, _, _, err := r.uploadIDsWithReferences(ctx, orderedMonikers, ignoreIDs, maximumIndexesPerMonikerSearch, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := r.uploadsByIDs(ctx, ids, uploadsByID)
+	if err != nil {
+		return nil, err
+	}
+	traceLog(
+		log.Int("numImplementationUploads", len(uploads)),
+		log.String("implementationUploads", uploadIDsToString(uploads)),
+	)
+
+	// Perform the moniker search
+	remoteLocations, _, err := r.monikerLocations(ctx, uploads, orderedMonikers, "implementations", ImplementationsLimit-len(locations), 0)
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRemoteLocations", len(remoteLocations)))
+
+	for _, location := range remoteLocations {
+		if _, ok := uploadsByID[location.DumpID]; ok {
+			// Already gathered by the graph traversal above
+			continue
+		}
+
+		locations = append(locations, location)
+	}
+
+	for i := range uploads {
+		uploadsByID[uploads[i].ID] = uploads[i]
+	}
+
+	// Adjust the locations back to the appropriate range in the target commits. This adjusts
+	// locations within the repository the user is browsing so that it appears all implementations
+	// are occurring at the same commit they are looking at.
+
+	adjustedLocations, err := r.adjustLocations(ctx, uploadsByID, locations)
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numAdjustedLocations", len(adjustedLocations)))
+
+	return adjustedLocations, nil
+}
@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestImplementations(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+
+	// Implementations from every visible upload are gathered
+	mockLSIFStore.ImplementationsFunc.PushReturn([]lsifstore.Location{
+		{DumpID: 50, Path: "a.go", Range: testRange1},
+	}, 1, nil)
+	mockLSIFStore.ImplementationsFunc.PushReturn(nil, 0, nil)
+	mockLSIFStore.ImplementationsFunc.PushReturn([]lsifstore.Location{
+		{DumpID: 52, Path: "b.go", Range: testRange2},
+		{DumpID: 52, Path: "c.go", Range: testRange3},
+	}, 2, nil)
+
+	uploads := []dbstore.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+		{ID: 52, Commit: "deadbeef", Root: "sub3/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, err := resolver.Implementations(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatalf("unexpected error querying implementations: %s", err)
+	}
+
+	expectedLocations := []AdjustedLocation{
+		{Dump: uploads[0], Path: "sub1/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+		{Dump: uploads[2], Path: "sub3/b.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange2},
+		{Dump: uploads[2], Path: "sub3/c.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange3},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	// No monikers are attached to the position, so no remote search is performed
+	if history := mockDBStore.ReferenceIDsAndFiltersFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of calls to ReferenceIDsAndFilters. want=%d have=%d", 0, len(history))
+	}
+}
@@ -0,0 +1,379 @@
+package resolvers
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// SearchBasedReferencesLimit is the maximum number of textual matches considered by the search-based
+// references query. Results past this limit are not reachable by pagination.
+const SearchBasedReferencesLimit = 1000
+
+// searchBasedQueryResolver is an imprecise QueryResolver used for paths without any precise code
+// intelligence data. Definitions are symbols with the same name as the token under the requested
+// position in files of the same language (as indexed by the symbols service). References are all
+// whole-word textual matches of that token in files of the same language. Both result sets are
+// ranked so that locations in the same file, in imported directories, and in nearby directories
+// come first. All other queries return empty results.
+type searchBasedQueryResolver struct {
+	gitserverClient GitserverClient
+	symbolsClient   SymbolsClient
+	repositoryName  api.RepoName
+	repositoryID    int
+	commit          string
+	path            string
+	operations      *operations
+}
+
+// NewSearchBasedQueryResolver creates a new search-based query resolver for the given repository,
+// commit, and path.
+func NewSearchBasedQueryResolver(
+	gitserverClient GitserverClient,
+	symbolsClient SymbolsClient,
+	repositoryName api.RepoName,
+	repositoryID int,
+	commit string,
+	path string,
+	operations *operations,
+) QueryResolver {
+	return &searchBasedQueryResolver{
+		gitserverClient: gitserverClient,
+		symbolsClient:   symbolsClient,
+		repositoryName:  repositoryName,
+		repositoryID:    repositoryID,
+		commit:          commit,
+		path:            path,
+		operations:      operations,
+	}
+}
+
+func (r *searchBasedQueryResolver) Precise() bool { return false }
+
+// Definitions returns the symbols with the same name as the token under the given position.
+func (r *searchBasedQueryResolver) Definitions(ctx context.Context, line, character int) (_ []AdjustedLocation, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "SearchBasedDefinitions", r.operations.searchBasedDefinitions, slowDefinitionsRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer endObservation()
+
+	contents, token, err := r.tokenAt(ctx, line, character)
+	if err != nil || token == "" {
+		return nil, err
+	}
+	traceLog(log.String("token", token))
+
+	symbols, err := r.symbolsClient.Search(ctx, search.SymbolsParameters{
+		Repo:            r.repositoryName,
+		CommitID:        api.CommitID(r.commit),
+		Query:           "^" + regexp.QuoteMeta(token) + "$",
+		IsRegExp:        true,
+		IsCaseSensitive: true,
+		IncludePatterns: sameLanguageIncludePatterns(r.path),
+		First:           DefinitionsLimit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "symbolsClient.Search")
+	}
+	if symbols == nil {
+		return nil, nil
+	}
+
+	ranker := newProximityRanker(r.path, contents)
+	sort.SliceStable(*symbols, func(i, j int) bool {
+		return ranker.less((*symbols)[i].Path, (*symbols)[j].Path)
+	})
+
+	locations := make([]AdjustedLocation, 0, len(*symbols))
+	for _, symbol := range *symbols {
+		symbolRange := symbol.Range()
+
+		locations = append(locations, r.adjustedLocation(symbol.Path, lsifstore.Range{
+			Start: lsifstore.Position{Line: symbolRange.Start.Line, Character: symbolRange.Start.Character},
+			End:   lsifstore.Position{Line: symbolRange.End.Line, Character: symbolRange.End.Character},
+		}))
+	}
+	traceLog(log.Int("numLocations", len(locations)))
+
+	return locations, nil
+}
+
+// References returns the whole-word textual matches of the token under the given position. The
+// cursor is the offset of the next page of results into the ranked list of matches.
+func (r *searchBasedQueryResolver) References(ctx context.Context, line, character, limit int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "SearchBasedReferences", r.operations.searchBasedReferences, slowReferencesRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer endObservation()
+
+	offset := 0
+	if rawCursor != "" {
+		if offset, err = strconv.Atoi(rawCursor); err != nil || offset < 0 {
+			return nil, "", errors.Errorf("illegal cursor %q", rawCursor)
+		}
+	}
+
+	contents, token, err := r.tokenAt(ctx, line, character)
+	if err != nil || token == "" {
+		return nil, "", err
+	}
+	traceLog(log.String("token", token))
+
+	matches, err := r.gitserverClient.SearchWord(ctx, r.repositoryID, r.commit, token, sameLanguagePathspecs(r.path), SearchBasedReferencesLimit)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "gitserverClient.SearchWord")
+	}
+	traceLog(log.Int("numMatches", len(matches)))
+
+	ranker := newProximityRanker(r.path, contents)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return ranker.less(matches[i].Path, matches[j].Path)
+	})
+
+	if offset >= len(matches) {
+		return nil, "", nil
+	}
+	page := matches[offset:]
+	nextCursor := ""
+	if len(page) > limit {
+		page = page[:limit]
+		nextCursor = strconv.Itoa(offset + limit)
+	}
+
+	locations := make([]AdjustedLocation, 0, len(page))
+	for _, match := range page {
+		locations = append(locations, r.adjustedLocation(match.Path, wordMatchRange(match, token)))
+	}
+
+	return locations, nextCursor, nil
+}
+
+func (r *searchBasedQueryResolver) Ranges(ctx context.Context, startLine, endLine int) ([]AdjustedCodeIntelligenceRange, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) TypeDefinitions(ctx context.Context, line, character int) ([]AdjustedLocation, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) Implementations(ctx context.Context, line, character int) ([]AdjustedLocation, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) IncomingCalls(ctx context.Context, line, character, depth int) ([]CallHierarchyItem, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) OutgoingCalls(ctx context.Context, line, character, depth int) ([]CallHierarchyItem, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) Hover(ctx context.Context, line, character int) (string, lsifstore.Range, bool, error) {
+	return "", lsifstore.Range{}, false, nil
+}
+
+func (r *searchBasedQueryResolver) Diagnostics(ctx context.Context, limit int) ([]AdjustedDiagnostic, int, error) {
+	return nil, 0, nil
+}
+
+func (r *searchBasedQueryResolver) DocumentationPage(ctx context.Context, pathID string) (*precise.DocumentationPageData, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) DocumentationPathInfo(ctx context.Context, pathID string) (*precise.DocumentationPathInfoData, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) Documentation(ctx context.Context, line int, character int) ([]*Documentation, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) DocumentationDefinitions(ctx context.Context, pathID string) ([]AdjustedLocation, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) DocumentationReferences(ctx context.Context, pathID string, limit int, rawCursor string) ([]AdjustedLocation, string, error) {
+	return nil, "", nil
+}
+
+// tokenAt returns the contents of the resolver's path along with the identifier enclosing the
+// given position. An empty token is returned if the position does not fall on an identifier.
+func (r *searchBasedQueryResolver) tokenAt(ctx context.Context, line, character int) (string, string, error) {
+	contents, err := r.gitserverClient.RawContents(ctx, r.repositoryID, r.commit, r.path)
+	if err != nil {
+		return "", "", errors.Wrap(err, "gitserverClient.RawContents")
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	if line < 0 || line >= len(lines) {
+		return string(contents), "", nil
+	}
+
+	return string(contents), identifierAt([]rune(lines[line]), character), nil
+}
+
+// adjustedLocation creates a location within the resolver's repository and commit. There is no
+// upload associated with a search-based location, so only the repository and commit of the dump
+// are populated.
+func (r *searchBasedQueryResolver) adjustedLocation(path string, rn lsifstore.Range) AdjustedLocation {
+	return AdjustedLocation{
+		Dump: store.Dump{
+			RepositoryID:   r.repositoryID,
+			RepositoryName: string(r.repositoryName),
+			Commit:         r.commit,
+		},
+		Path:           path,
+		AdjustedCommit: r.commit,
+		AdjustedRange:  rn,
+	}
+}
+
+// identifierAt returns the identifier enclosing the given character of the given line. If the
+// character immediately follows an identifier (e.g. the cursor is at the end of a word), that
+// identifier is returned. Numeric literals are not considered identifiers.
+func identifierAt(line []rune, character int) string {
+	if character < 0 || character > len(line) {
+		return ""
+	}
+	if character == len(line) || !isIdentifierRune(line[character]) {
+		if character == 0 || !isIdentifierRune(line[character-1]) {
+			return ""
+		}
+		character--
+	}
+
+	start := character
+	for start > 0 && isIdentifierRune(line[start-1]) {
+		start--
+	}
+	end := character
+	for end < len(line) && isIdentifierRune(line[end]) {
+		end++
+	}
+
+	if unicode.IsDigit(line[start]) {
+		return ""
+	}
+
+	return string(line[start:end])
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// sameLanguageIncludePatterns returns the symbols service include patterns matching the files
+// that share an extension with the given path.
+func sameLanguageIncludePatterns(filename string) []string {
+	if extension := path.Ext(filename); extension != "" {
+		return []string{regexp.QuoteMeta(extension) + "$"}
+	}
+
+	return nil
+}
+
+// sameLanguagePathspecs returns the git pathspecs matching the files that share an extension with
+// the given path.
+func sameLanguagePathspecs(filename string) []string {
+	if extension := path.Ext(filename); extension != "" {
+		return []string{"*" + extension}
+	}
+
+	return nil
+}
+
+// wordMatchRange returns the range of the given token starting at the given match.
+func wordMatchRange(match gitserver.WordMatch, token string) lsifstore.Range {
+	return lsifstore.Range{
+		Start: lsifstore.Position{Line: match.Line, Character: match.Character},
+		End:   lsifstore.Position{Line: match.Line, Character: match.Character + len(token)},
+	}
+}
+
+// proximityRanker orders candidate paths by their likely relevance to a source file. Candidates
+// in the source file itself rank highest, followed by candidates in a directory that the source
+// file appears to import, followed by candidates sharing the longest directory prefix with the
+// source file.
+type proximityRanker struct {
+	path     string
+	dirs     []string
+	contents string
+}
+
+func newProximityRanker(path, contents string) *proximityRanker {
+	return &proximityRanker{
+		path:     path,
+		dirs:     splitDirs(path),
+		contents: contents,
+	}
+}
+
+// less returns true if the first path should be ordered before the second path.
+func (r *proximityRanker) less(a, b string) bool {
+	if sameFileA, sameFileB := a == r.path, b == r.path; sameFileA != sameFileB {
+		return sameFileA
+	}
+	if importedA, importedB := r.imported(a), r.imported(b); importedA != importedB {
+		return importedA
+	}
+
+	return r.sharedDirs(a) > r.sharedDirs(b)
+}
+
+// imported returns true if the directory of the given path (or the path itself, sans extension) is
+// mentioned in the source file. This is a language-agnostic approximation of an import statement
+// that covers module paths (e.g. Go) as well as path-like imports (e.g. TypeScript).
+func (r *proximityRanker) imported(candidate string) bool {
+	if dir := path.Dir(candidate); dir != "." && dir != path.Dir(r.path) && strings.Contains(r.contents, dir) {
+		return true
+	}
+
+	return strings.Contains(r.contents, strings.TrimSuffix(candidate, path.Ext(candidate)))
+}
+
+// sharedDirs returns the number of leading directories the given path shares with the source file.
+func (r *proximityRanker) sharedDirs(candidate string) int {
+	dirs := splitDirs(candidate)
+
+	i := 0
+	for i < len(dirs) && i < len(r.dirs) && dirs[i] == r.dirs[i] {
+		i++
+	}
+
+	return i
+}
+
+func splitDirs(filename string) []string {
+	if dir := path.Dir(filename); dir != "." {
+		return strings.Split(dir, "/")
+	}
+
+	return nil
+}
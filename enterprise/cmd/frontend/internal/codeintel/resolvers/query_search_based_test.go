@@ -0,0 +1,198 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+const searchBasedTestContents = `package main
+
+import "github.com/test/repo/lib/util"
+
+func main() {
+	util.Frobnicate()
+}
+`
+
+func TestSearchBasedDefinitions(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(searchBasedTestContents), nil)
+	mockSymbolsClient := NewMockSymbolsClient()
+	mockSymbolsClient.SearchFunc.SetDefaultReturn(&result.Symbols{
+		{Name: "Frobnicate", Path: "other/frob.go", Line: 3, Pattern: "/^func Frobnicate() {$/"},
+		{Name: "Frobnicate", Path: "cmd/main/frob.go", Line: 5, Pattern: "/^func Frobnicate() {$/"},
+		{Name: "Frobnicate", Path: "lib/util/frob.go", Line: 7, Pattern: "/^func Frobnicate() {$/"},
+	}, nil)
+
+	resolver := NewSearchBasedQueryResolver(
+		mockGitserverClient,
+		mockSymbolsClient,
+		"github.com/test/repo",
+		42,
+		"deadbeef",
+		"cmd/main/main.go",
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, err := resolver.Definitions(context.Background(), 5, 10)
+	if err != nil {
+		t.Fatalf("unexpected error querying definitions: %s", err)
+	}
+
+	if calls := mockSymbolsClient.SearchFunc.History(); len(calls) != 1 {
+		t.Fatalf("unexpected number of symbol searches. want=%d have=%d", 1, len(calls))
+	} else {
+		expectedArgs := search.SymbolsParameters{
+			Repo:            "github.com/test/repo",
+			CommitID:        "deadbeef",
+			Query:           "^Frobnicate$",
+			IsRegExp:        true,
+			IsCaseSensitive: true,
+			IncludePatterns: []string{`\.go$`},
+			First:           DefinitionsLimit,
+		}
+		if diff := cmp.Diff(expectedArgs, calls[0].Arg1); diff != "" {
+			t.Errorf("unexpected symbol search arguments (-want +got):\n%s", diff)
+		}
+	}
+
+	dump := dbstore.Dump{RepositoryID: 42, RepositoryName: "github.com/test/repo", Commit: "deadbeef"}
+	expectedLocations := []AdjustedLocation{
+		{Dump: dump, Path: "lib/util/frob.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(6, 5, 6, 15)},
+		{Dump: dump, Path: "cmd/main/frob.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(4, 5, 4, 15)},
+		{Dump: dump, Path: "other/frob.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(2, 5, 2, 15)},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchBasedDefinitionsNoIdentifier(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(searchBasedTestContents), nil)
+	mockSymbolsClient := NewMockSymbolsClient()
+
+	resolver := NewSearchBasedQueryResolver(
+		mockGitserverClient,
+		mockSymbolsClient,
+		"github.com/test/repo",
+		42,
+		"deadbeef",
+		"cmd/main/main.go",
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, err := resolver.Definitions(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error querying definitions: %s", err)
+	}
+	if len(adjustedLocations) != 0 {
+		t.Errorf("unexpected locations. want=%d have=%d", 0, len(adjustedLocations))
+	}
+	if calls := mockSymbolsClient.SearchFunc.History(); len(calls) != 0 {
+		t.Errorf("unexpected number of symbol searches. want=%d have=%d", 0, len(calls))
+	}
+}
+
+func TestSearchBasedReferences(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(searchBasedTestContents), nil)
+	mockGitserverClient.SearchWordFunc.SetDefaultReturn([]gitserver.WordMatch{
+		{Path: "other/frob.go", Line: 10, Character: 1},
+		{Path: "cmd/main/main.go", Line: 5, Character: 6},
+		{Path: "lib/util/frob.go", Line: 6, Character: 5},
+	}, nil)
+
+	resolver := NewSearchBasedQueryResolver(
+		mockGitserverClient,
+		NewMockSymbolsClient(),
+		"github.com/test/repo",
+		42,
+		"deadbeef",
+		"cmd/main/main.go",
+		newOperations(&observation.TestContext),
+	)
+
+	// cursor at the end of the identifier
+	adjustedLocations, cursor, err := resolver.References(context.Background(), 5, 16, 2, "")
+	if err != nil {
+		t.Fatalf("unexpected error querying references: %s", err)
+	}
+
+	if calls := mockGitserverClient.SearchWordFunc.History(); len(calls) != 1 {
+		t.Fatalf("unexpected number of word searches. want=%d have=%d", 1, len(calls))
+	} else {
+		if calls[0].Arg3 != "Frobnicate" {
+			t.Errorf("unexpected word. want=%q have=%q", "Frobnicate", calls[0].Arg3)
+		}
+		if diff := cmp.Diff([]string{"*.go"}, calls[0].Arg4); diff != "" {
+			t.Errorf("unexpected pathspecs (-want +got):\n%s", diff)
+		}
+	}
+
+	dump := dbstore.Dump{RepositoryID: 42, RepositoryName: "github.com/test/repo", Commit: "deadbeef"}
+	expectedLocations := []AdjustedLocation{
+		{Dump: dump, Path: "cmd/main/main.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(5, 6, 5, 16)},
+		{Dump: dump, Path: "lib/util/frob.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(6, 5, 6, 15)},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+	if cursor != "2" {
+		t.Errorf("unexpected cursor. want=%q have=%q", "2", cursor)
+	}
+
+	adjustedLocations, cursor, err = resolver.References(context.Background(), 5, 16, 2, cursor)
+	if err != nil {
+		t.Fatalf("unexpected error querying references: %s", err)
+	}
+
+	expectedLocations = []AdjustedLocation{
+		{Dump: dump, Path: "other/frob.go", AdjustedCommit: "deadbeef", AdjustedRange: newRange(10, 1, 10, 11)},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+	if cursor != "" {
+		t.Errorf("unexpected cursor. want=%q have=%q", "", cursor)
+	}
+}
+
+func TestIdentifierAt(t *testing.T) {
+	testCases := []struct {
+		line      string
+		character int
+		expected  string
+	}{
+		{"\tutil.Frobnicate()", 1, "util"},
+		{"\tutil.Frobnicate()", 5, "util"},
+		{"\tutil.Frobnicate()", 6, "Frobnicate"},
+		{"\tutil.Frobnicate()", 12, "Frobnicate"},
+		{"\tutil.Frobnicate()", 17, ""},
+		{"\tutil.Frobnicate()", 0, ""},
+		{"x := 1234", 7, ""},
+		{"const $el = 1", 8, "$el"},
+		{"foo", 3, "foo"},
+		{"foo", 4, ""},
+	}
+
+	for _, testCase := range testCases {
+		if token := identifierAt([]rune(testCase.line), testCase.character); token != testCase.expected {
+			t.Errorf("unexpected identifier at %q:%d. want=%q have=%q", testCase.line, testCase.character, testCase.expected, token)
+		}
+	}
+}
+
+func newRange(startLine, startCharacter, endLine, endCharacter int) lsifstore.Range {
+	return lsifstore.Range{
+		Start: lsifstore.Position{Line: startLine, Character: startCharacter},
+		End:   lsifstore.Position{Line: endLine, Character: endCharacter},
+	}
+}
@@ -0,0 +1,72 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+const slowTypeDefinitionsRequestThreshold = time.Second
+
+// TypeDefinitionsLimit is maximum the number of locations returned from TypeDefinitions.
+const TypeDefinitionsLimit = 100
+
+// TypeDefinitions returns the list of source locations that define the type of the symbol at the given
+// position. Unlike Definitions, type definitions are only resolved within the visible indexes: there is
+// no moniker search over remote indexes.
+func (r *queryResolver) TypeDefinitions(ctx context.Context, line, character int) (_ []AdjustedLocation, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "TypeDefinitions", r.operations.typeDefinitions, slowTypeDefinitionsRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("numUploads", len(r.uploads)),
+			log.String("uploads", uploadIDsToString(r.uploads)),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer endObservation()
+
+	// Adjust the path and position for each visible upload based on its git difference to
+	// the target commit.
+
+	adjustedUploads, err := r.adjustUploads(ctx, line, character)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gather the type definition locations that are reachable via a typeDefinitionResult vertex.
+	// The first index with a result wins, as with local definitions.
+
+	for i := range adjustedUploads {
+		traceLog(log.Int("uploadID", adjustedUploads[i].Upload.ID))
+
+		locations, _, err := r.lsifStore.TypeDefinitions(
+			ctx,
+			adjustedUploads[i].Upload.ID,
+			adjustedUploads[i].AdjustedPathInBundle,
+			adjustedUploads[i].AdjustedPosition.Line,
+			adjustedUploads[i].AdjustedPosition.Character,
+			TypeDefinitionsLimit,
+			0,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "lsifStore.TypeDefinitions")
+		}
+		if len(locations) > 0 {
+			uploadsByID := map[int]dbstore.Dump{
+				adjustedUploads[i].Upload.ID: adjustedUploads[i].Upload,
+			}
+
+			return r.adjustLocations(ctx, uploadsByID, locations)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestTypeDefinitions(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+
+	locations := []lsifstore.Location{
+		{DumpID: 51, Path: "a.go", Range: testRange1},
+		{DumpID: 51, Path: "b.go", Range: testRange2},
+	}
+	mockLSIFStore.TypeDefinitionsFunc.PushReturn(nil, 0, nil)
+	mockLSIFStore.TypeDefinitionsFunc.PushReturn(locations, len(locations), nil)
+
+	uploads := []dbstore.Dump{
+		{ID: 50, Commit: "deadbeef", Root: "sub1/"},
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+		{ID: 52, Commit: "deadbeef", Root: "sub3/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, err := resolver.TypeDefinitions(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatalf("unexpected error querying type definitions: %s", err)
+	}
+
+	expectedLocations := []AdjustedLocation{
+		{Dump: uploads[1], Path: "sub2/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+		{Dump: uploads[1], Path: "sub2/b.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange2},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockLSIFStore.TypeDefinitionsFunc.History(); len(history) != 2 {
+		t.Errorf("unexpected number of calls to TypeDefinitions. want=%d have=%d", 2, len(history))
+	}
+}
@@ -9,6 +9,8 @@ import (
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 )
 
@@ -21,6 +23,7 @@ type Resolver interface {
 	GetUploadByID(ctx context.Context, id int) (store.Upload, bool, error)
 	GetIndexByID(ctx context.Context, id int) (store.Index, bool, error)
 	GetUploadsByIDs(ctx context.Context, ids ...int) ([]store.Upload, error)
+	GetUploadValidationErrors(ctx context.Context, uploadID int) ([]store.UploadValidationError, error)
 	GetIndexesByIDs(ctx context.Context, ids ...int) ([]store.Index, error)
 	UploadConnectionResolver(opts store.GetUploadsOptions) *UploadsResolver
 	IndexConnectionResolver(opts store.GetIndexesOptions) *IndexesResolver
@@ -34,15 +37,18 @@ type Resolver interface {
 	CreateConfigurationPolicy(ctx context.Context, configurationPolicy store.ConfigurationPolicy) (store.ConfigurationPolicy, error)
 	UpdateConfigurationPolicy(ctx context.Context, policy store.ConfigurationPolicy) (err error)
 	DeleteConfigurationPolicyByID(ctx context.Context, id int) (err error)
+	PreviewRetention(ctx context.Context, repositoryID int, policyID *int) (retained, expired []store.Upload, err error)
 	IndexConfiguration(ctx context.Context, repositoryID int) ([]byte, bool, error)
 	InferredIndexConfiguration(ctx context.Context, repositoryID int) (*config.IndexConfiguration, bool, error)
 	UpdateIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int, configuration string) error
+	DocumentationSearch(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error)
 }
 
 type resolver struct {
 	dbStore         DBStore
 	lsifStore       LSIFStore
 	gitserverClient GitserverClient
+	symbolsClient   SymbolsClient
 	indexEnqueuer   IndexEnqueuer
 	hunkCache       HunkCache
 	operations      *operations
@@ -53,17 +59,19 @@ func NewResolver(
 	dbStore DBStore,
 	lsifStore LSIFStore,
 	gitserverClient GitserverClient,
+	symbolsClient SymbolsClient,
 	indexEnqueuer IndexEnqueuer,
 	hunkCache HunkCache,
 	observationContext *observation.Context,
 ) Resolver {
-	return newResolver(dbStore, lsifStore, gitserverClient, indexEnqueuer, hunkCache, observationContext)
+	return newResolver(dbStore, lsifStore, gitserverClient, symbolsClient, indexEnqueuer, hunkCache, observationContext)
 }
 
 func newResolver(
 	dbStore DBStore,
 	lsifStore LSIFStore,
 	gitserverClient GitserverClient,
+	symbolsClient SymbolsClient,
 	indexEnqueuer IndexEnqueuer,
 	hunkCache HunkCache,
 	observationContext *observation.Context,
@@ -72,6 +80,7 @@ func newResolver(
 		dbStore:         dbStore,
 		lsifStore:       lsifStore,
 		gitserverClient: gitserverClient,
+		symbolsClient:   symbolsClient,
 		indexEnqueuer:   indexEnqueuer,
 		hunkCache:       hunkCache,
 		operations:      newOperations(observationContext),
@@ -82,6 +91,10 @@ func (r *resolver) GetUploadByID(ctx context.Context, id int) (store.Upload, boo
 	return r.dbStore.GetUploadByID(ctx, id)
 }
 
+func (r *resolver) GetUploadValidationErrors(ctx context.Context, uploadID int) ([]store.UploadValidationError, error) {
+	return r.dbStore.GetUploadValidationErrors(ctx, uploadID)
+}
+
 func (r *resolver) GetIndexByID(ctx context.Context, id int) (store.Index, bool, error) {
 	return r.dbStore.GetIndexByID(ctx, id)
 }
@@ -138,6 +151,7 @@ func (r *resolver) QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataA
 			log.String("path", args.Path),
 			log.Bool("exactPath", args.ExactPath),
 			log.String("toolName", args.ToolName),
+			log.Bool("searchBased", args.SearchBased),
 		},
 	})
 	defer endObservation()
@@ -154,9 +168,27 @@ func (r *resolver) QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataA
 		args.ExactPath,
 		args.ToolName,
 	)
-	if err != nil || len(dumps) == 0 {
+	if err != nil {
 		return nil, err
 	}
+	if len(dumps) == 0 {
+		if !args.SearchBased || r.symbolsClient == nil {
+			return nil, nil
+		}
+
+		// There is no precise code intelligence data for this path and the caller asked
+		// for a fallback. Use imprecise, search-based code navigation backed by the symbols
+		// service.
+		return NewSearchBasedQueryResolver(
+			r.gitserverClient,
+			r.symbolsClient,
+			args.Repo.Name,
+			int(args.Repo.ID),
+			string(args.Commit),
+			args.Path,
+			r.operations,
+		), nil
+	}
 
 	return NewQueryResolver(
 		r.dbStore,
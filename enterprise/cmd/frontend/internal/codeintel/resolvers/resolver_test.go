@@ -15,7 +15,7 @@ func TestQueryResolver(t *testing.T) {
 	mockLSIFStore := NewMockLSIFStore()
 	mockGitserverClient := NewMockGitserverClient()
 
-	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	queryResolver, err := resolver.QueryResolver(context.Background(), &gql.GitBlobLSIFDataArgs{
 		Repo:      &types.Repo{ID: 50},
 		Commit:    api.CommitID("deadbeef"),
@@ -31,3 +31,53 @@ func TestQueryResolver(t *testing.T) {
 		t.Errorf("expected nil-valued resolver")
 	}
 }
+
+func TestQueryResolverWithSymbolsClient(t *testing.T) {
+	mockDBStore := NewMockDBStore() // returns no dumps
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockSymbolsClient := NewMockSymbolsClient()
+
+	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, mockSymbolsClient, nil, nil, &observation.TestContext)
+	queryResolver, err := resolver.QueryResolver(context.Background(), &gql.GitBlobLSIFDataArgs{
+		Repo:      &types.Repo{ID: 50, Name: "github.com/test/repo"},
+		Commit:    api.CommitID("deadbeef"),
+		Path:      "/foo/bar.go",
+		ExactPath: true,
+		ToolName:  "lsif-go",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if queryResolver != nil {
+		t.Errorf("expected nil-valued resolver without searchBased")
+	}
+}
+
+func TestQueryResolverSearchBasedFallback(t *testing.T) {
+	mockDBStore := NewMockDBStore() // returns no dumps
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockSymbolsClient := NewMockSymbolsClient()
+
+	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, mockSymbolsClient, nil, nil, &observation.TestContext)
+	queryResolver, err := resolver.QueryResolver(context.Background(), &gql.GitBlobLSIFDataArgs{
+		Repo:        &types.Repo{ID: 50, Name: "github.com/test/repo"},
+		Commit:      api.CommitID("deadbeef"),
+		Path:        "/foo/bar.go",
+		ExactPath:   true,
+		ToolName:    "lsif-go",
+		SearchBased: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if queryResolver == nil {
+		t.Fatalf("expected search-based resolver")
+	}
+	if queryResolver.Precise() {
+		t.Errorf("expected imprecise resolver")
+	}
+}
@@ -0,0 +1,90 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+const retentionPreviewUploadBatchSize = 100
+
+// PreviewRetention evaluates data retention policies against the completed uploads of the given repository
+// and returns the uploads that would be retained and the uploads that would be expired. If a policy identifier
+// is supplied, only that policy is evaluated (as if its retention were enabled); otherwise all global policies
+// and all policies attached to the repository are evaluated. This method does not modify any data.
+func (r *resolver) PreviewRetention(ctx context.Context, repositoryID int, policyID *int) (retained, expired []store.Upload, err error) {
+	configurationPolicies, err := r.retentionPolicies(ctx, repositoryID, policyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commitMap, err := policies.NewMatcher(r.gitserverClient).CommitsDescribedByPolicy(ctx, repositoryID, configurationPolicies, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for offset := 0; ; {
+		uploads, totalCount, err := r.dbStore.GetUploads(ctx, store.GetUploadsOptions{
+			RepositoryID: repositoryID,
+			State:        "completed",
+			Limit:        retentionPreviewUploadBatchSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, expiredIDs := policies.PartitionUploads(uploads, commitMap)
+		expiredIDSet := make(map[int]struct{}, len(expiredIDs))
+		for _, id := range expiredIDs {
+			expiredIDSet[id] = struct{}{}
+		}
+
+		for _, upload := range uploads {
+			if _, ok := expiredIDSet[upload.ID]; ok {
+				expired = append(expired, upload)
+			} else {
+				retained = append(retained, upload)
+			}
+		}
+
+		if offset += len(uploads); len(uploads) == 0 || offset >= totalCount {
+			return retained, expired, nil
+		}
+	}
+}
+
+// retentionPolicies returns the configuration policies to evaluate for a retention preview of the given
+// repository.
+func (r *resolver) retentionPolicies(ctx context.Context, repositoryID int, policyID *int) ([]store.ConfigurationPolicy, error) {
+	if policyID != nil {
+		policy, exists, err := r.dbStore.GetConfigurationPolicyByID(ctx, *policyID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.Errorf("unknown configuration policy %d", *policyID)
+		}
+
+		policy.RetentionEnabled = true
+		return []store.ConfigurationPolicy{policy}, nil
+	}
+
+	globalPolicies, err := r.dbStore.GetConfigurationPolicies(ctx, store.GetConfigurationPoliciesOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryPolicies, err := r.dbStore.GetConfigurationPolicies(ctx, store.GetConfigurationPoliciesOptions{
+		RepositoryID: repositoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(globalPolicies, repositoryPolicies...), nil
+}
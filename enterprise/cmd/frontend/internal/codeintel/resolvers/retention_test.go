@@ -0,0 +1,61 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestPreviewRetention(t *testing.T) {
+	uploads := []store.Upload{
+		{ID: 1, Commit: "deadbeef01"},
+		{ID: 2, Commit: "deadbeef02"},
+		{ID: 3, Commit: "deadbeef03"},
+		{ID: 4, Commit: "deadbeef04", VisibleAtTip: true},
+	}
+
+	mockDBStore := NewMockDBStore()
+	mockDBStore.GetConfigurationPolicyByIDFunc.SetDefaultReturn(store.ConfigurationPolicy{
+		ID:               1,
+		Type:             "GIT_TAG",
+		Pattern:          "v*",
+		RetentionEnabled: false, // evaluated regardless
+	}, true, nil)
+	mockDBStore.GetUploadsFunc.SetDefaultHook(func(ctx context.Context, opts store.GetUploadsOptions) ([]store.Upload, int, error) {
+		if opts.Offset >= len(uploads) {
+			return nil, len(uploads), nil
+		}
+		return uploads[opts.Offset:], len(uploads), nil
+	})
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string]gitserver.RefDescription{
+		"deadbeef01": {Name: "main", Type: gitserver.RefTypeBranch, IsDefaultBranch: true, CreatedDate: time.Now()},
+		"deadbeef02": {Name: "v1.2.3", Type: gitserver.RefTypeTag, CreatedDate: time.Now()},
+		"deadbeef03": {Name: "feature", Type: gitserver.RefTypeBranch, CreatedDate: time.Now()},
+	}, nil)
+
+	policyID := 1
+	resolver := newResolver(mockDBStore, NewMockLSIFStore(), mockGitserverClient, nil, nil, nil, &observation.TestContext)
+	retained, expired, err := resolver.PreviewRetention(context.Background(), 50, &policyID)
+	if err != nil {
+		t.Fatalf("unexpected error previewing retention: %s", err)
+	}
+
+	if diff := cmp.Diff([]store.Upload{uploads[0], uploads[1], uploads[3]}, retained); diff != "" {
+		t.Errorf("unexpected retained uploads (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]store.Upload{uploads[2]}, expired); diff != "" {
+		t.Errorf("unexpected expired uploads (-want +got):\n%s", diff)
+	}
+
+	if history := mockDBStore.GetConfigurationPoliciesFunc.History(); len(history) != 0 {
+		t.Errorf("expected only the requested policy to be evaluated")
+	}
+}
@@ -11,7 +11,7 @@ type QueueAllocation struct {
 }
 
 var (
-	validQueueNames         = []string{"batches", "codeintel"}
+	validQueueNames         = []string{"batch-spec-workspaces", "batches", "codeintel"}
 	validCloudProviderNames = []string{"aws", "gcp"}
 )
 
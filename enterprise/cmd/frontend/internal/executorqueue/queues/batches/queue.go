@@ -6,6 +6,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/executorqueue/handler"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/background"
+	bstore "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -26,3 +27,17 @@ func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.
 		CanceledRecordsFetcher: store.FetchCanceled,
 	}
 }
+
+func WorkspaceExecutionQueueOptions(db dbutil.DB, config *Config, observationContext *observation.Context) handler.QueueOptions {
+	batchesStore := bstore.New(db, observationContext, nil)
+
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return transformBatchSpecWorkspaceExecutionJob(ctx, batchesStore, record.(*btypes.BatchSpecWorkspaceExecutionJob), config)
+	}
+
+	store := background.NewBatchSpecWorkspaceExecutorStore(basestore.NewHandleWithDB(db, sql.TxOptions{}), observationContext)
+	return handler.QueueOptions{
+		Store:             store,
+		RecordTransformer: recordTransformer,
+	}
+}
@@ -2,60 +2,31 @@ package batches
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 // transformRecord transforms a *btypes.BatchSpecExecution into an apiclient.Job.
 func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecExecution, config *Config) (apiclient.Job, error) {
-	// TODO: createAccessToken is a bit of technical debt until we figure out a
-	// better solution. The problem is that src-cli needs to make requests to
-	// the Sourcegraph instance *on behalf of the user*.
-	//
-	// Ideally we'd have something like one-time tokens that
-	// * we could hand to src-cli
-	// * are not visible to the user in the Sourcegraph web UI
-	// * valid only for the duration of the batch spec execution
-	// * and cleaned up after batch spec is executed
-	//
-	// Until then we create a fresh access token every time.
-	//
-	// GetOrCreate doesn't work because once an access token has been created
-	// in the database Sourcegraph can't access the plain-text token anymore.
-	// Only a hash for verification is kept in the database.
-	token, err := createAccessToken(ctx, db, exec.UserID)
-	if err != nil {
-		return apiclient.Job{}, err
-	}
-
-	frontendURL := conf.Get().ExternalURL
-
-	srcEndpoint, err := makeURL(frontendURL, config.Shared.FrontendUsername, config.Shared.FrontendPassword)
-	if err != nil {
-		return apiclient.Job{}, err
-	}
-
-	redactedSrcEndpoint, err := makeURL(frontendURL, "USERNAME_REMOVED", "PASSWORD_REMOVED")
+	cliEnv, redactedValues, err := makeSrcCLIEnv(ctx, db, exec.UserID, config)
 	if err != nil {
 		return apiclient.Job{}, err
 	}
 
-	cliEnv := []string{
-		fmt.Sprintf("SRC_ENDPOINT=%s", srcEndpoint),
-		fmt.Sprintf("SRC_ACCESS_TOKEN=%s", token),
-
-		// TODO: This is wrong here, it should be set on the executor machine
-		fmt.Sprintf("HOME=%s", os.Getenv("HOME")),
-		fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
-	}
-
 	var namespaceName string
 	if exec.NamespaceUserID != 0 {
 		user, err := database.Users(db).GetByID(ctx, exec.NamespaceUserID)
@@ -88,26 +59,147 @@ func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecEx
 				Env: cliEnv,
 			},
 		},
-		RedactedValues: map[string]string{
-			// 🚨 SECURITY: Catch leak of upload endpoint. This is necessary in addition
-			// to the below in case the username or password contains illegal URL characters,
-			// which are then urlencoded and are not replaceable via byte comparison.
-			srcEndpoint: redactedSrcEndpoint,
-
-			// 🚨 SECURITY: Catch uses of fragments pulled from URL to construct another target
-			// (in src-cli). We only pass the constructed URL to src-cli, which we trust not to
-			// ship the values to a third party, but not to trust to ensure the values are absent
-			// from the command's stdout or stderr streams.
-			config.Shared.FrontendUsername: "USERNAME_REMOVED",
-			config.Shared.FrontendPassword: "PASSWORD_REMOVED",
-
-			// 🚨 SECURITY: Redact the access token used for src-cli to talk to
-			// Sourcegraph instance.
-			token: "SRC_ACCESS_TOKEN_REMOVED",
+		RedactedValues: redactedValues,
+	}, nil
+}
+
+// transformBatchSpecWorkspaceExecutionJob transforms a
+// *btypes.BatchSpecWorkspaceExecutionJob into an apiclient.Job.
+func transformBatchSpecWorkspaceExecutionJob(ctx context.Context, s *store.Store, job *btypes.BatchSpecWorkspaceExecutionJob, config *Config) (apiclient.Job, error) {
+	workspace, err := s.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: job.BatchSpecWorkspaceID})
+	if err != nil {
+		return apiclient.Job{}, errors.Wrapf(err, "fetching workspace %d", job.BatchSpecWorkspaceID)
+	}
+
+	batchSpec, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return apiclient.Job{}, errors.Wrap(err, "fetching batch spec")
+	}
+
+	// 🚨 SECURITY: The repository is loaded on behalf of the user that
+	// created the batch spec, so that we never hand out a repository they
+	// don't have access to.
+	repo, err := s.Repos().Get(actor.WithActor(ctx, actor.FromUser(batchSpec.UserID)), workspace.RepoID)
+	if err != nil {
+		return apiclient.Job{}, errors.Wrap(err, "fetching repo")
+	}
+
+	executionInput := batcheslib.WorkspacesExecutionInput{
+		BatchChangeAttributes: batcheslib.BatchChangeAttributes{
+			Name:        batchSpec.Spec.Name,
+			Description: batchSpec.Spec.Description,
+		},
+		Repository: batcheslib.WorkspaceRepo{
+			ID:   string(relay.MarshalID("Repository", repo.ID)),
+			Name: string(repo.Name),
 		},
+		Branch: batcheslib.WorkspaceBranch{
+			Name:   workspace.Branch,
+			Target: batcheslib.Commit{OID: workspace.Commit},
+		},
+		Path:               workspace.Path,
+		OnlyFetchWorkspace: workspace.OnlyFetchWorkspace,
+		Steps:              workspace.Steps,
+		SearchResultPaths:  workspace.FileMatches,
+		ChangesetTemplate:  batchSpec.Spec.ChangesetTemplate,
+		TransformChanges:   batchSpec.Spec.TransformChanges,
+	}
+
+	marshaledInput, err := json.Marshal(executionInput)
+	if err != nil {
+		return apiclient.Job{}, err
+	}
+
+	cliEnv, redactedValues, err := makeSrcCLIEnv(ctx, s.DB(), batchSpec.UserID, config)
+	if err != nil {
+		return apiclient.Job{}, err
+	}
+
+	return apiclient.Job{
+		ID:                  int(job.ID),
+		VirtualMachineFiles: map[string]string{"input.json": string(marshaledInput)},
+		CliSteps: []apiclient.CliStep{
+			{
+				Commands: []string{
+					"batch",
+					"exec",
+					"-f", "input.json",
+					"-text-only",
+				},
+				Dir: ".",
+				Env: cliEnv,
+			},
+		},
+		RedactedValues: redactedValues,
 	}, nil
 }
 
+// makeSrcCLIEnv creates a fresh access token for the given user and returns the
+// environment src-cli needs to talk to the Sourcegraph instance on their
+// behalf, along with the values that need to be redacted from the job output.
+func makeSrcCLIEnv(ctx context.Context, db dbutil.DB, userID int32, config *Config) (cliEnv []string, redactedValues map[string]string, err error) {
+	// TODO: createAccessToken is a bit of technical debt until we figure out a
+	// better solution. The problem is that src-cli needs to make requests to
+	// the Sourcegraph instance *on behalf of the user*.
+	//
+	// Ideally we'd have something like one-time tokens that
+	// * we could hand to src-cli
+	// * are not visible to the user in the Sourcegraph web UI
+	// * valid only for the duration of the batch spec execution
+	// * and cleaned up after batch spec is executed
+	//
+	// Until then we create a fresh access token every time.
+	//
+	// GetOrCreate doesn't work because once an access token has been created
+	// in the database Sourcegraph can't access the plain-text token anymore.
+	// Only a hash for verification is kept in the database.
+	token, err := createAccessToken(ctx, db, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frontendURL := conf.Get().ExternalURL
+
+	srcEndpoint, err := makeURL(frontendURL, config.Shared.FrontendUsername, config.Shared.FrontendPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redactedSrcEndpoint, err := makeURL(frontendURL, "USERNAME_REMOVED", "PASSWORD_REMOVED")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cliEnv = []string{
+		fmt.Sprintf("SRC_ENDPOINT=%s", srcEndpoint),
+		fmt.Sprintf("SRC_ACCESS_TOKEN=%s", token),
+
+		// TODO: This is wrong here, it should be set on the executor machine
+		fmt.Sprintf("HOME=%s", os.Getenv("HOME")),
+		fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
+	}
+
+	redactedValues = map[string]string{
+		// 🚨 SECURITY: Catch leak of upload endpoint. This is necessary in addition
+		// to the below in case the username or password contains illegal URL characters,
+		// which are then urlencoded and are not replaceable via byte comparison.
+		srcEndpoint: redactedSrcEndpoint,
+
+		// 🚨 SECURITY: Catch uses of fragments pulled from URL to construct another target
+		// (in src-cli). We only pass the constructed URL to src-cli, which we trust not to
+		// ship the values to a third party, but not to trust to ensure the values are absent
+		// from the command's stdout or stderr streams.
+		config.Shared.FrontendUsername: "USERNAME_REMOVED",
+		config.Shared.FrontendPassword: "PASSWORD_REMOVED",
+
+		// 🚨 SECURITY: Redact the access token used for src-cli to talk to
+		// Sourcegraph instance.
+		token: "SRC_ACCESS_TOKEN_REMOVED",
+	}
+
+	return cliEnv, redactedValues, nil
+}
+
 const (
 	accessTokenNote  = "batch-spec-execution"
 	accessTokenScope = "user:all"
@@ -10,10 +10,14 @@ import (
 type Config struct {
 	env.BaseConfig
 
-	UploadStoreConfig  *uploadstore.Config
-	WorkerPollInterval time.Duration
-	WorkerConcurrency  int
-	WorkerBudget       int64
+	UploadStoreConfig    *uploadstore.Config
+	WorkerPollInterval   time.Duration
+	WorkerConcurrency    int
+	WorkerBudget         int64
+	HoverMemoryLimit     int64
+	HoverSpillDir        string
+	EnableValidation     bool
+	ValidationErrorLimit int
 }
 
 func (c *Config) Load() {
@@ -24,4 +28,8 @@ func (c *Config) Load() {
 	c.WorkerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", "1s", "Interval between queries to the upload queue.")
 	c.WorkerConcurrency = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY", "1", "The maximum number of indexes that can be processed concurrently.")
 	c.WorkerBudget = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_BUDGET", "0", "The amount of compressed input data (in bytes) a worker can process concurrently. Zero acts as an infinite budget."))
+	c.HoverMemoryLimit = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_HOVER_MEMORY_LIMIT", "0", "The amount of hover text (in bytes) held in memory while processing a single upload. Hover text exceeding this limit is written to a temporary file. Zero disables the limit."))
+	c.HoverSpillDir = c.GetOptional("PRECISE_CODE_INTEL_WORKER_HOVER_SPILL_DIR", "The directory in which hover text is written when processing an upload exceeds the hover memory limit. Defaults to the system temporary directory.")
+	c.EnableValidation = c.GetBool("PRECISE_CODE_INTEL_WORKER_ENABLE_VALIDATION", "false", "Whether or not to validate the raw LSIF data of each upload and record the detected problems. Validation holds the entire index in memory.")
+	c.ValidationErrorLimit = c.GetInt("PRECISE_CODE_INTEL_WORKER_VALIDATION_ERROR_LIMIT", "100", "The maximum number of validation problems recorded for a single upload.")
 }
@@ -16,6 +16,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
@@ -28,13 +29,16 @@ import (
 )
 
 type handler struct {
-	dbStore         DBStore
-	workerStore     dbworkerstore.Store
-	lsifStore       LSIFStore
-	uploadStore     uploadstore.Store
-	gitserverClient GitserverClient
-	enableBudget    bool
-	budgetRemaining int64
+	dbStore              DBStore
+	workerStore          dbworkerstore.Store
+	lsifStore            LSIFStore
+	uploadStore          uploadstore.Store
+	gitserverClient      GitserverClient
+	enableBudget         bool
+	budgetRemaining      int64
+	hoverOptions         conversion.HoverOptions
+	enableValidation     bool
+	validationErrorLimit int
 }
 
 var (
@@ -99,15 +103,25 @@ func (h *handler) handle(ctx context.Context, upload store.Upload) (requeued boo
 		return directoryChildren, nil
 	}
 
+	var validationErrors []store.UploadValidationError
+	if h.enableValidation {
+		var validationErr error
+		if validationErrors, validationErr = validateUpload(ctx, h.uploadStore, upload.ID, h.validationErrorLimit); validationErr != nil {
+			// Malformed input is reported by the conversion step below; validation is a best-effort
+			// report and should never be the reason an upload fails to process.
+			log15.Warn("Failed to validate upload", "err", validationErr, "uploadID", upload.ID)
+		}
+	}
+
 	return false, withUploadData(ctx, h.uploadStore, upload.ID, func(r io.Reader) (err error) {
-		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
+		groupedBundleData, err := conversion.CorrelateWithHoverOptions(ctx, r, upload.Root, getChildren, h.hoverOptions)
 		if err != nil {
-			return errors.Wrap(err, "conversion.Correlate")
+			return errors.Wrap(err, "conversion.CorrelateWithHoverOptions")
 		}
 
 		// Note: this is writing to a different database than the block below, so we need to use a
 		// different transaction context (managed by the writeData function).
-		if err := writeData(ctx, h.lsifStore, upload.ID, groupedBundleData); err != nil {
+		if err := writeData(ctx, h.lsifStore, upload, groupedBundleData); err != nil {
 			if isUniqueConstraintViolation(err) {
 				// If this is a unique constraint violation, then we've previously processed this same
 				// upload record up to this point, but failed to perform the transaction below. We can
@@ -143,6 +157,13 @@ func (h *handler) handle(ctx context.Context, upload store.Upload) (requeued boo
 				return errors.Wrap(err, "store.UpdatePackageReferences")
 			}
 
+			// Replace any validation errors recorded by a previous attempt to process this upload.
+			if h.enableValidation {
+				if err := tx.UpdateUploadValidationErrors(ctx, upload.ID, validationErrors); err != nil {
+					return errors.Wrap(err, "store.UpdateUploadValidationErrors")
+				}
+			}
+
 			// Before we mark the upload as complete, we need to delete any existing completed uploads
 			// that have the same repository_id, commit, root, and indexer values. Otherwise the transaction
 			// will fail as these values form a unique constraint.
@@ -214,10 +235,22 @@ func requeueIfCloning(ctx context.Context, workerStore dbworkerstore.Store, uplo
 // consumer should expect raw newline-delimited JSON content. If the function returns without
 // an error, the upload file will be deleted.
 func withUploadData(ctx context.Context, uploadStore uploadstore.Store, id int, fn func(r io.Reader) error) error {
-	uploadFilename := fmt.Sprintf("upload-%d.lsif.gz", id)
+	if err := readUploadData(ctx, uploadStore, id, fn); err != nil {
+		return err
+	}
+
+	if err := uploadStore.Delete(ctx, uploadFilenameForID(id)); err != nil {
+		log15.Warn("Failed to delete upload file", "err", err, "filename", uploadFilenameForID(id))
+	}
 
+	return nil
+}
+
+// readUploadData will invoke the given function with a reader of the upload's raw data. The
+// consumer should expect raw newline-delimited JSON content. The upload file is not modified.
+func readUploadData(ctx context.Context, uploadStore uploadstore.Store, id int, fn func(r io.Reader) error) error {
 	// Pull raw uploaded data from bucket
-	rc, err := uploadStore.Get(ctx, uploadFilename)
+	rc, err := uploadStore.Get(ctx, uploadFilenameForID(id))
 	if err != nil {
 		return errors.Wrap(err, "uploadStore.Get")
 	}
@@ -229,19 +262,21 @@ func withUploadData(ctx context.Context, uploadStore uploadstore.Store, id int,
 	}
 	defer rc.Close()
 
-	if err := fn(rc); err != nil {
-		return err
-	}
-
-	if err := uploadStore.Delete(ctx, uploadFilename); err != nil {
-		log15.Warn("Failed to delete upload file", "err", err, "filename", uploadFilename)
-	}
+	return fn(rc)
+}
 
-	return nil
+func uploadFilenameForID(id int) string {
+	return fmt.Sprintf("upload-%d.lsif.gz", id)
 }
 
 // writeData transactionally writes the given grouped bundle data into the given LSIF store.
-func writeData(ctx context.Context, lsifStore LSIFStore, id int, groupedBundleData *precise.GroupedBundleDataChans) (err error) {
+func writeData(ctx context.Context, lsifStore LSIFStore, upload store.Upload, groupedBundleData *precise.GroupedBundleDataChans) (err error) {
+	id := upload.ID
+	documentationSearchMeta := lsifstore.DocumentationSearchMeta{
+		RepositoryID: upload.RepositoryID,
+		Language:     lsifstore.IndexerLanguage(upload.Indexer),
+	}
+
 	tx, err := lsifStore.Transact(ctx)
 	if err != nil {
 		return err
@@ -263,7 +298,10 @@ func writeData(ctx context.Context, lsifStore LSIFStore, id int, groupedBundleDa
 	if err := tx.WriteReferences(ctx, id, groupedBundleData.References); err != nil {
 		return errors.Wrap(err, "store.WriteReferences")
 	}
-	if err := tx.WriteDocumentationPages(ctx, id, groupedBundleData.DocumentationPages); err != nil {
+	if err := tx.WriteImplementations(ctx, id, groupedBundleData.Implementations); err != nil {
+		return errors.Wrap(err, "store.WriteImplementations")
+	}
+	if err := tx.WriteDocumentationPages(ctx, id, documentationSearchMeta, groupedBundleData.DocumentationPages); err != nil {
 		return errors.Wrap(err, "store.WriteDocumentationPages")
 	}
 	if err := tx.WriteDocumentationPathInfo(ctx, id, groupedBundleData.DocumentationPathInfo); err != nil {
@@ -148,6 +148,70 @@ func TestHandle(t *testing.T) {
 	}
 }
 
+func TestHandleValidation(t *testing.T) {
+	setupRepoMocks(t)
+
+	upload := dbstore.Upload{
+		ID:           42,
+		Root:         "root/",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "lsif-go",
+	}
+
+	mockWorkerStore := NewMockWorkerStore()
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := NewMockGitserverClient()
+
+	// Set default transaction behavior
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockLSIFStore.TransactFunc.SetDefaultReturn(mockLSIFStore, nil)
+	mockLSIFStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+
+	// Give correlation package a valid input dump (with overlapping ranges)
+	mockUploadStore.GetFunc.SetDefaultHook(copyTestDump)
+
+	// Allowlist all files in dump
+	gitserverClient.DirectoryChildrenFunc.SetDefaultReturn(map[string][]string{
+		"": {"foo.go", "bar.go"},
+	}, nil)
+
+	handler := &handler{
+		dbStore:              mockDBStore,
+		workerStore:          mockWorkerStore,
+		lsifStore:            mockLSIFStore,
+		uploadStore:          mockUploadStore,
+		gitserverClient:      gitserverClient,
+		enableValidation:     true,
+		validationErrorLimit: 1,
+	}
+
+	requeued, err := handler.handle(context.Background(), upload)
+	if err != nil {
+		t.Fatalf("unexpected error handling upload: %s", err)
+	} else if requeued {
+		t.Errorf("unexpected requeue")
+	}
+
+	expectedValidationErrors := []dbstore.UploadValidationError{
+		{Severity: "WARNING", Message: "ranges overlap in document 2", RelevantLines: []int{4, 5}},
+	}
+	if calls := mockDBStore.UpdateUploadValidationErrorsFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of UpdateUploadValidationErrors calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 42 {
+		t.Errorf("unexpected UpdateUploadValidationErrors upload id. want=%d have=%d", 42, calls[0].Arg1)
+	} else if diff := cmp.Diff(expectedValidationErrors, calls[0].Arg2); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+
+	if len(mockUploadStore.DeleteFunc.History()) != 1 {
+		t.Errorf("unexpected number of Delete calls. want=%d have=%d", 1, len(mockUploadStore.DeleteFunc.History()))
+	}
+}
+
 func TestHandleError(t *testing.T) {
 	setupRepoMocks(t)
 
@@ -26,6 +26,7 @@ type DBStore interface {
 	DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string) error
 	InsertDependencyIndexingJob(ctx context.Context, uploadID int) (int, error)
 	UpdateCommitedAt(ctx context.Context, dumpID int, committedAt time.Time) error
+	UpdateUploadValidationErrors(ctx context.Context, uploadID int, validationErrors []dbstore.UploadValidationError) error
 }
 
 type DBStoreShim struct {
@@ -54,7 +55,8 @@ type LSIFStore interface {
 	WriteResultChunks(ctx context.Context, bundleID int, resultChunks chan precise.IndexedResultChunkData) error
 	WriteDefinitions(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) error
 	WriteReferences(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) error
-	WriteDocumentationPages(ctx context.Context, bundleID int, documentation chan *precise.DocumentationPageData) error
+	WriteImplementations(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) error
+	WriteDocumentationPages(ctx context.Context, bundleID int, meta lsifstore.DocumentationSearchMeta, documentation chan *precise.DocumentationPageData) error
 	WriteDocumentationPathInfo(ctx context.Context, bundleID int, documentation chan *precise.DocumentationPathInfoData) error
 	WriteDocumentationMappings(ctx context.Context, bundleID int, mappings chan precise.DocumentationMapping) error
 }
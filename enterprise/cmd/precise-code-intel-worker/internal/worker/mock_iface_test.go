@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	dbstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	lsifstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
 	api "github.com/sourcegraph/sourcegraph/internal/api"
 	basestore "github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	precise "github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
@@ -48,6 +50,10 @@ type MockDBStore struct {
 	// UpdatePackagesFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdatePackages.
 	UpdatePackagesFunc *DBStoreUpdatePackagesFunc
+	// UpdateUploadValidationErrorsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// UpdateUploadValidationErrors.
+	UpdateUploadValidationErrorsFunc *DBStoreUpdateUploadValidationErrorsFunc
 	// WithFunc is an instance of a mock function object controlling the
 	// behavior of the method With.
 	WithFunc *DBStoreWithFunc
@@ -107,6 +113,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		UpdateUploadValidationErrorsFunc: &DBStoreUpdateUploadValidationErrorsFunc{
+			defaultHook: func(context.Context, int, []dbstore.UploadValidationError) error {
+				return nil
+			},
+		},
 		WithFunc: &DBStoreWithFunc{
 			defaultHook: func(basestore.ShareableStore) DBStore {
 				return nil
@@ -149,6 +160,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		UpdatePackagesFunc: &DBStoreUpdatePackagesFunc{
 			defaultHook: i.UpdatePackages,
 		},
+		UpdateUploadValidationErrorsFunc: &DBStoreUpdateUploadValidationErrorsFunc{
+			defaultHook: i.UpdateUploadValidationErrors,
+		},
 		WithFunc: &DBStoreWithFunc{
 			defaultHook: i.With,
 		},
@@ -1234,6 +1248,118 @@ func (c DBStoreUpdatePackagesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBStoreUpdateUploadValidationErrorsFunc describes the behavior when the
+// UpdateUploadValidationErrors method of the parent MockDBStore instance is
+// invoked.
+type DBStoreUpdateUploadValidationErrorsFunc struct {
+	defaultHook func(context.Context, int, []dbstore.UploadValidationError) error
+	hooks       []func(context.Context, int, []dbstore.UploadValidationError) error
+	history     []DBStoreUpdateUploadValidationErrorsFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateUploadValidationErrors delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) UpdateUploadValidationErrors(v0 context.Context, v1 int, v2 []dbstore.UploadValidationError) error {
+	r0 := m.UpdateUploadValidationErrorsFunc.nextHook()(v0, v1, v2)
+	m.UpdateUploadValidationErrorsFunc.appendCall(DBStoreUpdateUploadValidationErrorsFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateUploadValidationErrors method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreUpdateUploadValidationErrorsFunc) SetDefaultHook(hook func(context.Context, int, []dbstore.UploadValidationError) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateUploadValidationErrors method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *DBStoreUpdateUploadValidationErrorsFunc) PushHook(hook func(context.Context, int, []dbstore.UploadValidationError) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreUpdateUploadValidationErrorsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []dbstore.UploadValidationError) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreUpdateUploadValidationErrorsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []dbstore.UploadValidationError) error {
+		return r0
+	})
+}
+
+func (f *DBStoreUpdateUploadValidationErrorsFunc) nextHook() func(context.Context, int, []dbstore.UploadValidationError) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreUpdateUploadValidationErrorsFunc) appendCall(r0 DBStoreUpdateUploadValidationErrorsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreUpdateUploadValidationErrorsFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreUpdateUploadValidationErrorsFunc) History() []DBStoreUpdateUploadValidationErrorsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreUpdateUploadValidationErrorsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreUpdateUploadValidationErrorsFuncCall is an object that describes
+// an invocation of method UpdateUploadValidationErrors on an instance of
+// MockDBStore.
+type DBStoreUpdateUploadValidationErrorsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []dbstore.UploadValidationError
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreUpdateUploadValidationErrorsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreUpdateUploadValidationErrorsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBStoreWithFunc describes the behavior when the With method of the parent
 // MockDBStore instance is invoked.
 type DBStoreWithFunc struct {
@@ -1764,6 +1890,9 @@ type MockLSIFStore struct {
 	// WriteDocumentsFunc is an instance of a mock function object
 	// controlling the behavior of the method WriteDocuments.
 	WriteDocumentsFunc *LSIFStoreWriteDocumentsFunc
+	// WriteImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method WriteImplementations.
+	WriteImplementationsFunc *LSIFStoreWriteImplementationsFunc
 	// WriteMetaFunc is an instance of a mock function object controlling
 	// the behavior of the method WriteMeta.
 	WriteMetaFunc *LSIFStoreWriteMetaFunc
@@ -1814,6 +1943,11 @@ func NewMockLSIFStore() *MockLSIFStore {
 				return nil
 			},
 		},
+		WriteImplementationsFunc: &LSIFStoreWriteImplementationsFunc{
+			defaultHook: func(context.Context, int, chan precise.MonikerLocations) error {
+				return nil
+			},
+		},
 		WriteMetaFunc: &LSIFStoreWriteMetaFunc{
 			defaultHook: func(context.Context, int, precise.MetaData) error {
 				return nil
@@ -1857,6 +1991,9 @@ func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
 		WriteDocumentsFunc: &LSIFStoreWriteDocumentsFunc{
 			defaultHook: i.WriteDocuments,
 		},
+		WriteImplementationsFunc: &LSIFStoreWriteImplementationsFunc{
+			defaultHook: i.WriteImplementations,
+		},
 		WriteMetaFunc: &LSIFStoreWriteMetaFunc{
 			defaultHook: i.WriteMeta,
 		},
@@ -2301,24 +2438,24 @@ func (c LSIFStoreWriteDocumentationMappingsFuncCall) Results() []interface{} {
 // WriteDocumentationPages method of the parent MockLSIFStore instance is
 // invoked.
 type LSIFStoreWriteDocumentationPagesFunc struct {
-	defaultHook func(context.Context, int, chan *precise.DocumentationPageData) error
-	hooks       []func(context.Context, int, chan *precise.DocumentationPageData) error
+	defaultHook func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error
+	hooks       []func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error
 	history     []LSIFStoreWriteDocumentationPagesFuncCall
 	mutex       sync.Mutex
 }
 
 // WriteDocumentationPages delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockLSIFStore) WriteDocumentationPages(v0 context.Context, v1 int, v2 chan *precise.DocumentationPageData) error {
-	r0 := m.WriteDocumentationPagesFunc.nextHook()(v0, v1, v2)
-	m.WriteDocumentationPagesFunc.appendCall(LSIFStoreWriteDocumentationPagesFuncCall{v0, v1, v2, r0})
+func (m *MockLSIFStore) WriteDocumentationPages(v0 context.Context, v1 int, v2 lsifstore.DocumentationSearchMeta, v3 chan *precise.DocumentationPageData) error {
+	r0 := m.WriteDocumentationPagesFunc.nextHook()(v0, v1, v2, v3)
+	m.WriteDocumentationPagesFunc.appendCall(LSIFStoreWriteDocumentationPagesFuncCall{v0, v1, v2, v3, r0})
 	return r0
 }
 
 // SetDefaultHook sets function that is called when the
 // WriteDocumentationPages method of the parent MockLSIFStore instance is
 // invoked and the hook queue is empty.
-func (f *LSIFStoreWriteDocumentationPagesFunc) SetDefaultHook(hook func(context.Context, int, chan *precise.DocumentationPageData) error) {
+func (f *LSIFStoreWriteDocumentationPagesFunc) SetDefaultHook(hook func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error) {
 	f.defaultHook = hook
 }
 
@@ -2327,7 +2464,7 @@ func (f *LSIFStoreWriteDocumentationPagesFunc) SetDefaultHook(hook func(context.
 // invokes the hook at the front of the queue and discards it. After the
 // queue is empty, the default hook function is invoked for any future
 // action.
-func (f *LSIFStoreWriteDocumentationPagesFunc) PushHook(hook func(context.Context, int, chan *precise.DocumentationPageData) error) {
+func (f *LSIFStoreWriteDocumentationPagesFunc) PushHook(hook func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2336,7 +2473,7 @@ func (f *LSIFStoreWriteDocumentationPagesFunc) PushHook(hook func(context.Contex
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
 func (f *LSIFStoreWriteDocumentationPagesFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int, chan *precise.DocumentationPageData) error {
+	f.SetDefaultHook(func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error {
 		return r0
 	})
 }
@@ -2344,12 +2481,12 @@ func (f *LSIFStoreWriteDocumentationPagesFunc) SetDefaultReturn(r0 error) {
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
 func (f *LSIFStoreWriteDocumentationPagesFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int, chan *precise.DocumentationPageData) error {
+	f.PushHook(func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error {
 		return r0
 	})
 }
 
-func (f *LSIFStoreWriteDocumentationPagesFunc) nextHook() func(context.Context, int, chan *precise.DocumentationPageData) error {
+func (f *LSIFStoreWriteDocumentationPagesFunc) nextHook() func(context.Context, int, lsifstore.DocumentationSearchMeta, chan *precise.DocumentationPageData) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2391,7 +2528,10 @@ type LSIFStoreWriteDocumentationPagesFuncCall struct {
 	Arg1 int
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 chan *precise.DocumentationPageData
+	Arg2 lsifstore.DocumentationSearchMeta
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 chan *precise.DocumentationPageData
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -2400,7 +2540,7 @@ type LSIFStoreWriteDocumentationPagesFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c LSIFStoreWriteDocumentationPagesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
@@ -2630,6 +2770,117 @@ func (c LSIFStoreWriteDocumentsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// LSIFStoreWriteImplementationsFunc describes the behavior when the
+// WriteImplementations method of the parent MockLSIFStore instance is
+// invoked.
+type LSIFStoreWriteImplementationsFunc struct {
+	defaultHook func(context.Context, int, chan precise.MonikerLocations) error
+	hooks       []func(context.Context, int, chan precise.MonikerLocations) error
+	history     []LSIFStoreWriteImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// WriteImplementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) WriteImplementations(v0 context.Context, v1 int, v2 chan precise.MonikerLocations) error {
+	r0 := m.WriteImplementationsFunc.nextHook()(v0, v1, v2)
+	m.WriteImplementationsFunc.appendCall(LSIFStoreWriteImplementationsFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the WriteImplementations
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreWriteImplementationsFunc) SetDefaultHook(hook func(context.Context, int, chan precise.MonikerLocations) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WriteImplementations method of the parent MockLSIFStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LSIFStoreWriteImplementationsFunc) PushHook(hook func(context.Context, int, chan precise.MonikerLocations) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreWriteImplementationsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, chan precise.MonikerLocations) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreWriteImplementationsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, chan precise.MonikerLocations) error {
+		return r0
+	})
+}
+
+func (f *LSIFStoreWriteImplementationsFunc) nextHook() func(context.Context, int, chan precise.MonikerLocations) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreWriteImplementationsFunc) appendCall(r0 LSIFStoreWriteImplementationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreWriteImplementationsFuncCall
+// objects describing the invocations of this function.
+func (f *LSIFStoreWriteImplementationsFunc) History() []LSIFStoreWriteImplementationsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreWriteImplementationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreWriteImplementationsFuncCall is an object that describes an
+// invocation of method WriteImplementations on an instance of
+// MockLSIFStore.
+type LSIFStoreWriteImplementationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 chan precise.MonikerLocations
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreWriteImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreWriteImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // LSIFStoreWriteMetaFunc describes the behavior when the WriteMeta method
 // of the parent MockLSIFStore instance is invoked.
 type LSIFStoreWriteMetaFunc struct {
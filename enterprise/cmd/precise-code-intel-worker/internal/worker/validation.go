@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"io"
+
+	"github.com/cockroachdb/errors"
+
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/reader"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/validation"
+)
+
+const (
+	// validationSeverityError marks a problem with an individual vertex or edge. Such elements
+	// may be partially or entirely ignored while processing the upload.
+	validationSeverityError = "ERROR"
+
+	// validationSeverityWarning marks a problem with the relationships between otherwise valid
+	// elements, such as unreachable vertices or overlapping ranges. Such problems may cause
+	// missing or incorrect navigation for the affected ranges.
+	validationSeverityWarning = "WARNING"
+)
+
+// validateUpload runs the LSIF validator over the raw data of the given upload and returns at most
+// limit of the problems it detects. A non-positive limit returns all detected problems. Relationship
+// checks are only performed when no individual element has a problem, mirroring the standalone
+// lsif-validate tool.
+func validateUpload(ctx context.Context, uploadStore uploadstore.Store, id, limit int) ([]store.UploadValidationError, error) {
+	validationContext := validation.NewValidationContext()
+	validator := &validation.Validator{Context: validationContext}
+
+	if err := readUploadData(ctx, uploadStore, id, func(r io.Reader) error {
+		if err := validator.ValidateElements(r); err != nil {
+			return errors.Wrap(err, "validator.ValidateElements")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(validationContext.Errors) > 0 {
+		return convertValidationErrors(validationContext.Errors, validationSeverityError, limit), nil
+	}
+
+	validator.ValidateRelationships()
+	return convertValidationErrors(validationContext.Errors, validationSeverityWarning, limit), nil
+}
+
+// convertValidationErrors converts at most limit of the given validator errors into upload validation
+// errors with the given severity. A non-positive limit converts all of the given errors.
+func convertValidationErrors(validationErrors []*reader.ValidationError, severity string, limit int) []store.UploadValidationError {
+	if limit > 0 && len(validationErrors) > limit {
+		validationErrors = validationErrors[:limit]
+	}
+
+	converted := make([]store.UploadValidationError, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		relevantLines := make([]int, 0, len(validationError.RelevantLines))
+		for _, lineContext := range validationError.RelevantLines {
+			relevantLines = append(relevantLines, lineContext.Index)
+		}
+
+		converted = append(converted, store.UploadValidationError{
+			Severity:      severity,
+			Message:       validationError.Message,
+			RelevantLines: relevantLines,
+		})
+	}
+
+	return converted
+}
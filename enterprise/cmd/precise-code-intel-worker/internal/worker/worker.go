@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion"
 )
 
 // UploadHeartbeatInterval is the duration between heartbeat updates to the upload job records.
@@ -23,18 +24,24 @@ func NewWorker(
 	pollInterval time.Duration,
 	numProcessorRoutines int,
 	budgetMax int64,
+	hoverOptions conversion.HoverOptions,
+	enableValidation bool,
+	validationErrorLimit int,
 	workerMetrics workerutil.WorkerMetrics,
 ) *workerutil.Worker {
 	rootContext := actor.WithActor(context.Background(), &actor.Actor{Internal: true})
 
 	handler := &handler{
-		dbStore:         dbStore,
-		workerStore:     workerStore,
-		lsifStore:       lsifStore,
-		uploadStore:     uploadStore,
-		gitserverClient: gitserverClient,
-		enableBudget:    budgetMax > 0,
-		budgetRemaining: budgetMax,
+		dbStore:              dbStore,
+		workerStore:          workerStore,
+		lsifStore:            lsifStore,
+		uploadStore:          uploadStore,
+		gitserverClient:      gitserverClient,
+		enableBudget:         budgetMax > 0,
+		budgetRemaining:      budgetMax,
+		hoverOptions:         hoverOptions,
+		enableValidation:     enableValidation,
+		validationErrorLimit: validationErrorLimit,
 	}
 
 	return dbworker.NewWorker(rootContext, workerStore, handler, workerutil.WorkerOptions{
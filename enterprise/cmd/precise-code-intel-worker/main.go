@@ -35,6 +35,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion"
 )
 
 const addr = ":3188"
@@ -106,6 +107,12 @@ func main() {
 		config.WorkerPollInterval,
 		config.WorkerConcurrency,
 		config.WorkerBudget,
+		conversion.HoverOptions{
+			HoverMemoryLimit: config.HoverMemoryLimit,
+			HoverSpillDir:    config.HoverSpillDir,
+		},
+		config.EnableValidation,
+		config.ValidationErrorLimit,
 		makeWorkerMetrics(observationContext),
 	)
 
@@ -298,6 +298,8 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 	}
 
 	var repoSpecs, includeContainsSpecs, excludeContainsSpecs []api.ExternalRepoSpec
+	// The sub-repo permissions by provider, for the providers which returned them.
+	subRepoPerms := make(map[authz.Provider]map[extsvc.RepoID]*authz.SubRepoPermissions)
 	for _, acct := range accts {
 		provider := byServiceID[acct.ServiceID]
 		if provider == nil {
@@ -352,6 +354,10 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 			continue
 		}
 
+		if extIDs.SubRepoPermissions != nil {
+			subRepoPerms[provider] = extIDs.SubRepoPermissions
+		}
+
 		if len(extIDs.Exacts) > 0 {
 			for _, exact := range extIDs.Exacts {
 				repoSpecs = append(repoSpecs,
@@ -409,6 +415,20 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 		repoNames = append(repoNames, rs...)
 	}
 
+	// Save sub-repo permissions to database before the repository permissions
+	// which they restrict, replacing those previously synced from the same code
+	// hosts.
+	for provider, perms := range subRepoPerms {
+		byID := make(map[string]authz.SubRepoPermissions, len(perms))
+		for id, rules := range perms {
+			byID[string(id)] = *rules
+		}
+		err = edb.SubRepoPermsWith(s.permsStore).SetForUserAndService(ctx, user.ID, provider.ServiceType(), provider.ServiceID(), byID)
+		if err != nil {
+			return errors.Wrap(err, "set sub-repo permissions")
+		}
+	}
+
 	// Save permissions to database
 	p := &authz.UserPermissions{
 		UserID: user.ID,
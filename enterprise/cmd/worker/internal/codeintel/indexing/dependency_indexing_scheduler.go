@@ -2,18 +2,22 @@ package indexing
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/gomodule/redigo/redis"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/redispool"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -24,23 +28,44 @@ var schemeToExternalService = map[string]string{
 	"semanticdb": extsvc.KindJVMPackages,
 }
 
+// DependencyIndexingSchedulerOptions configures the dependency indexing scheduler.
+type DependencyIndexingSchedulerOptions struct {
+	// MaximumPackagesPerRepositoryPerHour is the maximum number of dependencies for which
+	// index jobs are enqueued on behalf of a single dependent repository per hour. Jobs
+	// with dependencies over the limit are requeued once the limit resets.
+	MaximumPackagesPerRepositoryPerHour int
+
+	// RequeueDelay is the delay before a job is retried when one of the repositories
+	// providing its dependencies is not yet ready to be indexed.
+	RequeueDelay time.Duration
+
+	// MaximumRequeueAge is the age after which a job is no longer retried when one of
+	// the repositories providing its dependencies is not yet ready to be indexed.
+	MaximumRequeueAge time.Duration
+}
+
 // NewDependencyIndexingScheduler returns a new worker instance that processes
 // records from lsif_dependency_indexing_jobs.
 func NewDependencyIndexingScheduler(
 	dbStore DBStore,
 	workerStore dbworkerstore.Store,
 	externalServiceStore ExternalServiceStore,
-	enqueuer IndexEnqueuer,
+	indexEnqueuer IndexEnqueuer,
 	pollInterval time.Duration,
 	numProcessorRoutines int,
+	options DependencyIndexingSchedulerOptions,
 	workerMetrics workerutil.WorkerMetrics,
 ) *workerutil.Worker {
 	rootContext := actor.WithActor(context.Background(), &actor.Actor{Internal: true})
 
 	handler := &dependencyIndexingSchedulerHandler{
-		dbStore:       dbStore,
-		extsvcStore:   externalServiceStore,
-		indexEnqueuer: enqueuer,
+		dbStore:           dbStore,
+		workerStore:       workerStore,
+		extsvcStore:       externalServiceStore,
+		indexEnqueuer:     indexEnqueuer,
+		limiter:           newRepositoryLimiter(redispool.Store, options.MaximumPackagesPerRepositoryPerHour),
+		requeueDelay:      options.RequeueDelay,
+		maximumRequeueAge: options.MaximumRequeueAge,
 	}
 
 	return dbworker.NewWorker(rootContext, workerStore, handler, workerutil.WorkerOptions{
@@ -53,17 +78,29 @@ func NewDependencyIndexingScheduler(
 }
 
 type dependencyIndexingSchedulerHandler struct {
-	dbStore       DBStore
-	indexEnqueuer IndexEnqueuer
-	extsvcStore   ExternalServiceStore
+	dbStore           DBStore
+	workerStore       dbworkerstore.Store
+	indexEnqueuer     IndexEnqueuer
+	extsvcStore       ExternalServiceStore
+	limiter           RepositoryLimiter
+	requeueDelay      time.Duration
+	maximumRequeueAge time.Duration
 }
 
 var _ workerutil.Handler = &dependencyIndexingSchedulerHandler{}
 
 // Handle iterates all import monikers associated with a given upload that has
-// recently completed processing. Each moniker is interpreted according to its
-// scheme to determine the dependent repository and commit. A set of indexing
-// jobs are enqueued for each repository and commit pair.
+// recently completed processing and that are not provided by any other completed
+// upload. Each moniker is interpreted according to its scheme to determine the
+// dependent repository and commit. A set of indexing jobs are enqueued for each
+// repository and commit pair.
+//
+// If a repository providing a dependency is still being cloned (or has not yet been
+// created by an external service sync), the job is requeued so that the dependency
+// is indexed once its repository becomes available. Likewise, if the dependent
+// repository has reached its hourly limit, the job is requeued once the limit resets.
+// Dependencies which are already indexed or queued for indexing don't count towards
+// the limit.
 func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, record workerutil.Record) error {
 	if !indexSchedulerEnabled() {
 		return nil
@@ -73,14 +110,15 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, record
 
 	job := record.(dbstore.DependencyIndexingJob)
 
-	shouldIndex, err := h.shouldIndexDependencies(ctx, h.dbStore, job.UploadID)
+	upload, _, err := h.dbStore.GetUploadByID(ctx, job.UploadID)
 	if err != nil {
-		errs = append(errs, errors.Wrap(err, "indexing.shouldIndexDependencies"))
+		errs = append(errs, errors.Wrap(err, "dbstore.GetUploadByID"))
 	}
+	shouldIndex := shouldIndexDependencies(upload)
 
-	scanner, err := h.dbStore.ReferencesForUpload(ctx, job.UploadID)
+	scanner, err := h.dbStore.UnresolvedReferencesForUpload(ctx, job.UploadID)
 	if err != nil {
-		return errors.Wrap(err, "dbstore.ReferencesForUpload")
+		return errors.Wrap(err, "dbstore.UnresolvedReferencesForUpload")
 	}
 	defer func() {
 		if closeErr := scanner.Close(); closeErr != nil {
@@ -88,15 +126,24 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, record
 		}
 	}()
 
+	var allow func() (bool, error)
+	if h.limiter != nil {
+		allow = func() (bool, error) {
+			return h.limiter.Allow(ctx, upload.RepositoryID)
+		}
+	}
+
 	var (
 		kinds                      []string
 		oldDependencyReposInserted int
 		newDependencyReposInserted int
+		numRateLimited             int
+		numNotReady                int
 	)
 	for {
 		packageReference, exists, err := scanner.Next()
 		if err != nil {
-			return errors.Wrap(err, "dbstore.ReferencesForUpload.Next")
+			return errors.Wrap(err, "dbstore.UnresolvedReferencesForUpload.Next")
 		}
 		if !exists {
 			break
@@ -109,8 +156,18 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, record
 		}
 
 		if shouldIndex {
-			if err := h.indexEnqueuer.QueueIndexesForPackage(ctx, pkg); err != nil {
-				errs = append(errs, errors.Wrap(err, "enqueuer.QueueIndexesForPackage"))
+			if numRateLimited > 0 {
+				// The limit of the dependent repository has been reached. Don't resolve the
+				// remaining dependencies until the job is retried.
+				numRateLimited++
+			} else if err := h.indexEnqueuer.QueueIndexesForPackage(ctx, pkg, allow); err != nil {
+				if errors.Is(err, enqueuer.ErrPackageRepositoryNotReady) {
+					numNotReady++
+				} else if errors.Is(err, enqueuer.ErrRateLimited) {
+					numRateLimited++
+				} else {
+					errs = append(errs, errors.Wrap(err, "enqueuer.QueueIndexesForPackage"))
+				}
 			}
 		}
 
@@ -161,6 +218,32 @@ func (h *dependencyIndexingSchedulerHandler) Handle(ctx context.Context, record
 		log15.Info("no package schema kinds to sync external services for", "upload", job.UploadID, "job", job.ID)
 	}
 
+	var requeueAt time.Time
+	if numNotReady > 0 {
+		if time.Since(job.QueuedAt) < h.maximumRequeueAge {
+			log15.Info("requeueing dependency indexing job for repositories not yet ready",
+				"upload", job.UploadID, "job", job.ID, "num", numNotReady)
+
+			requeueAt = time.Now().Add(h.requeueDelay)
+		} else {
+			log15.Warn("giving up on dependencies whose repositories are not yet ready",
+				"upload", job.UploadID, "job", job.ID, "num", numNotReady)
+		}
+	}
+	if numRateLimited > 0 {
+		log15.Info("requeueing dependency indexing job for repository over its rate limit",
+			"upload", job.UploadID, "job", job.ID, "repositoryID", upload.RepositoryID, "num", numRateLimited)
+
+		if requeueAt.IsZero() {
+			requeueAt = nextRepositoryLimitWindow(time.Now())
+		}
+	}
+	if !requeueAt.IsZero() {
+		if err := h.workerStore.Requeue(ctx, job.ID, requeueAt); err != nil {
+			errs = append(errs, errors.Wrap(err, "store.Requeue"))
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -190,13 +273,68 @@ func (h *dependencyIndexingSchedulerHandler) insertDependencyRepo(ctx context.Co
 // shouldIndexDependencies returns true if the given upload should undergo dependency
 // indexing. Currently, we're only enabling dependency indexing for a repositories that
 // were indexed via lsif-go and lsif-java.
-func (h *dependencyIndexingSchedulerHandler) shouldIndexDependencies(ctx context.Context, store DBStore, uploadID int) (bool, error) {
-	upload, _, err := store.GetUploadByID(ctx, uploadID)
+func shouldIndexDependencies(upload dbstore.Upload) bool {
+	return upload.Indexer == "lsif-go" || upload.Indexer == "lsif-java"
+}
+
+// RepositoryLimiter limits the rate at which dependency index jobs are enqueued on
+// behalf of each dependent repository.
+type RepositoryLimiter interface {
+	// Allow reports whether an index job may be enqueued now on behalf of the given
+	// repository, and counts it towards the limit of the repository if so.
+	Allow(ctx context.Context, repositoryID int) (bool, error)
+}
+
+// repositoryLimiter limits the number of dependency index jobs enqueued on behalf of
+// each dependent repository per hour, so that a single large repository (e.g. a
+// monorepo with thousands of dependencies) cannot flood the index queue. The counts
+// are kept in redis, so that the limit is shared by all worker instances and survives
+// restarts. Limits reset at the start of every hour.
+type repositoryLimiter struct {
+	pool           *redis.Pool
+	maximumPerHour int
+}
+
+// newRepositoryLimiter creates a limiter that allows the given number of events per
+// repository per hour. A non-positive maximum disables the limit.
+func newRepositoryLimiter(pool *redis.Pool, maximumPerHour int) RepositoryLimiter {
+	if maximumPerHour <= 0 {
+		return unlimitedRepositoryLimiter{}
+	}
+
+	return &repositoryLimiter{pool: pool, maximumPerHour: maximumPerHour}
+}
+
+func (l *repositoryLimiter) Allow(ctx context.Context, repositoryID int) (bool, error) {
+	window := time.Now().Truncate(time.Hour)
+	key := fmt.Sprintf("codeintel:dependency-indexing-limit:%d:%d", repositoryID, window.Unix())
+
+	c := l.pool.Get()
+	defer c.Close()
+
+	count, err := redis.Int(c.Do("INCR", key))
 	if err != nil {
-		return false, errors.Wrap(err, "dbstore.GetUploadByID")
+		return false, errors.Wrap(err, "redis INCR")
 	}
+	if count == 1 {
+		if _, err := c.Do("EXPIRE", key, int(2*time.Hour/time.Second)); err != nil {
+			return false, errors.Wrap(err, "redis EXPIRE")
+		}
+	}
+
+	return count <= l.maximumPerHour, nil
+}
+
+// nextRepositoryLimitWindow returns the time at which the limits of repositoryLimiter
+// reset after now.
+func nextRepositoryLimitWindow(now time.Time) time.Time {
+	return now.Truncate(time.Hour).Add(time.Hour)
+}
+
+type unlimitedRepositoryLimiter struct{}
 
-	return upload.Indexer == "lsif-go" || upload.Indexer == "lsif-java", nil
+func (unlimitedRepositoryLimiter) Allow(ctx context.Context, repositoryID int) (bool, error) {
+	return true, nil
 }
 
 func kindExists(kinds []string, kind string) bool {
@@ -4,11 +4,14 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	lsifstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -18,7 +21,7 @@ func TestDependencyIndexingSchedulerHandler(t *testing.T) {
 	mockScanner := NewMockPackageReferenceScanner()
 	mockDBStore.WithFunc.SetDefaultReturn(mockDBStore)
 	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(dbstore.Upload{ID: 42, RepositoryID: 50, Indexer: "lsif-go"}, true, nil)
-	mockDBStore.ReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
+	mockDBStore.UnresolvedReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
 
 	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name1", Version: "v2.2.0"}}, true, nil)
 	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name1", Version: "v3.2.0"}}, true, nil)
@@ -87,7 +90,7 @@ func TestDependencyIndexingSchedulerHandlerShouldSkipRepository(t *testing.T) {
 	mockScanner := NewMockPackageReferenceScanner()
 	mockDBStore.WithFunc.SetDefaultReturn(mockDBStore)
 	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(dbstore.Upload{ID: 42, RepositoryID: 51, Indexer: "lsif-tsc"}, true, nil)
-	mockDBStore.ReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
+	mockDBStore.UnresolvedReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
 
 	indexEnqueuer := NewMockIndexEnqueuer()
 
@@ -108,3 +111,145 @@ func TestDependencyIndexingSchedulerHandlerShouldSkipRepository(t *testing.T) {
 		t.Errorf("unexpected number of calls to QueueIndexesForPackage. want=%d have=%d", 0, len(indexEnqueuer.QueueIndexesFunc.History()))
 	}
 }
+
+func TestDependencyIndexingSchedulerHandlerRepositoryRateLimit(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockWorkerStore := workerstoremocks.NewMockStore()
+	mockExtSvcStore := NewMockExternalServiceStore()
+	mockScanner := NewMockPackageReferenceScanner()
+	mockDBStore.WithFunc.SetDefaultReturn(mockDBStore)
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(dbstore.Upload{ID: 42, RepositoryID: 50, Indexer: "lsif-go"}, true, nil)
+	mockDBStore.UnresolvedReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
+
+	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name1", Version: "v1.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name1", Version: "v2.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name2", Version: "v1.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name2", Version: "v2.2.0"}}, true, nil)
+	mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name3", Version: "v1.2.0"}}, true, nil)
+	mockScanner.NextFunc.SetDefaultReturn(lsifstore.PackageReference{}, false, nil)
+
+	// The first package is already queued for indexing, so it doesn't count towards the limit.
+	indexEnqueuer := NewMockIndexEnqueuer()
+	indexEnqueuer.QueueIndexesForPackageFunc.PushReturn(nil)
+	indexEnqueuer.QueueIndexesForPackageFunc.SetDefaultHook(func(ctx context.Context, pkg precise.Package, allow func() (bool, error)) error {
+		if ok, err := allow(); err != nil {
+			return err
+		} else if !ok {
+			return enqueuer.ErrRateLimited
+		}
+		return nil
+	})
+
+	limiter := &fakeRepositoryLimiter{maximum: 2}
+	handler := &dependencyIndexingSchedulerHandler{
+		dbStore:       mockDBStore,
+		workerStore:   mockWorkerStore,
+		indexEnqueuer: indexEnqueuer,
+		extsvcStore:   mockExtSvcStore,
+		limiter:       limiter,
+	}
+
+	job := dbstore.DependencyIndexingJob{
+		ID:       23,
+		UploadID: 42,
+		QueuedAt: time.Now().Add(-time.Hour * 24),
+	}
+	if err := handler.Handle(context.Background(), job); err != nil {
+		t.Fatalf("unexpected error performing update: %s", err)
+	}
+
+	// The remaining packages are not resolved once the limit has been reached.
+	if len(indexEnqueuer.QueueIndexesForPackageFunc.History()) != 4 {
+		t.Errorf("unexpected number of calls to QueueIndexesForPackage. want=%d have=%d", 4, len(indexEnqueuer.QueueIndexesForPackageFunc.History()))
+	}
+	if diff := cmp.Diff(map[int]int{50: 3}, limiter.counts); diff != "" {
+		t.Errorf("unexpected limiter counts (-want +got):\n%s", diff)
+	}
+
+	// Rate-limited jobs are requeued regardless of their age.
+	if len(mockWorkerStore.RequeueFunc.History()) != 1 {
+		t.Errorf("unexpected number of calls to Requeue. want=%d have=%d", 1, len(mockWorkerStore.RequeueFunc.History()))
+	} else if call := mockWorkerStore.RequeueFunc.History()[0]; call.Arg1 != 23 || !call.Arg2.After(time.Now()) {
+		t.Errorf("unexpected requeue. want job %d in the future, have job %d at %s", 23, call.Arg1, call.Arg2)
+	}
+}
+
+// fakeRepositoryLimiter allows maximum events per repository.
+type fakeRepositoryLimiter struct {
+	maximum int
+	counts  map[int]int
+}
+
+func (l *fakeRepositoryLimiter) Allow(ctx context.Context, repositoryID int) (bool, error) {
+	if l.counts == nil {
+		l.counts = map[int]int{}
+	}
+	l.counts[repositoryID]++
+	return l.counts[repositoryID] <= l.maximum, nil
+}
+
+func TestNextRepositoryLimitWindow(t *testing.T) {
+	now := time.Date(2021, 9, 1, 12, 34, 56, 0, time.UTC)
+	if want, have := time.Date(2021, 9, 1, 13, 0, 0, 0, time.UTC), nextRepositoryLimitWindow(now); !have.Equal(want) {
+		t.Errorf("unexpected window. want=%s have=%s", want, have)
+	}
+}
+
+func TestDependencyIndexingSchedulerHandlerRequeueNotReady(t *testing.T) {
+	testCases := []struct {
+		queuedAt        time.Time
+		expectedRequeue bool
+	}{
+		{queuedAt: time.Now().Add(-time.Minute), expectedRequeue: true},
+		{queuedAt: time.Now().Add(-time.Hour * 2), expectedRequeue: false},
+	}
+
+	for _, testCase := range testCases {
+		mockDBStore := NewMockDBStore()
+		mockWorkerStore := workerstoremocks.NewMockStore()
+		mockExtSvcStore := NewMockExternalServiceStore()
+		mockScanner := NewMockPackageReferenceScanner()
+		mockDBStore.WithFunc.SetDefaultReturn(mockDBStore)
+		mockDBStore.GetUploadByIDFunc.SetDefaultReturn(dbstore.Upload{ID: 42, RepositoryID: 50, Indexer: "lsif-go"}, true, nil)
+		mockDBStore.UnresolvedReferencesForUploadFunc.SetDefaultReturn(mockScanner, nil)
+
+		mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name1", Version: "v1.2.0"}}, true, nil)
+		mockScanner.NextFunc.PushReturn(lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 42, Scheme: "test", Name: "name2", Version: "v1.2.0"}}, true, nil)
+		mockScanner.NextFunc.SetDefaultReturn(lsifstore.PackageReference{}, false, nil)
+
+		indexEnqueuer := NewMockIndexEnqueuer()
+		indexEnqueuer.QueueIndexesForPackageFunc.PushReturn(enqueuer.ErrPackageRepositoryNotReady)
+
+		handler := &dependencyIndexingSchedulerHandler{
+			dbStore:           mockDBStore,
+			workerStore:       mockWorkerStore,
+			indexEnqueuer:     indexEnqueuer,
+			extsvcStore:       mockExtSvcStore,
+			requeueDelay:      time.Minute,
+			maximumRequeueAge: time.Hour,
+		}
+
+		job := dbstore.DependencyIndexingJob{
+			ID:       23,
+			UploadID: 42,
+			QueuedAt: testCase.queuedAt,
+		}
+		if err := handler.Handle(context.Background(), job); err != nil {
+			t.Fatalf("unexpected error performing update: %s", err)
+		}
+
+		if len(indexEnqueuer.QueueIndexesForPackageFunc.History()) != 2 {
+			t.Errorf("unexpected number of calls to QueueIndexesForPackage. want=%d have=%d", 2, len(indexEnqueuer.QueueIndexesForPackageFunc.History()))
+		}
+
+		if !testCase.expectedRequeue {
+			if len(mockWorkerStore.RequeueFunc.History()) != 0 {
+				t.Errorf("unexpected number of calls to Requeue. want=%d have=%d", 0, len(mockWorkerStore.RequeueFunc.History()))
+			}
+		} else if len(mockWorkerStore.RequeueFunc.History()) != 1 {
+			t.Errorf("unexpected number of calls to Requeue. want=%d have=%d", 1, len(mockWorkerStore.RequeueFunc.History()))
+		} else if id := mockWorkerStore.RequeueFunc.History()[0].Arg1; id != 23 {
+			t.Errorf("unexpected requeued job. want=%d have=%d", 23, id)
+		}
+	}
+}
@@ -19,7 +19,7 @@ type DBStore interface {
 	GetAutoindexDisabledRepositories(ctx context.Context) ([]int, error)
 	GetUploads(ctx context.Context, opts dbstore.GetUploadsOptions) ([]dbstore.Upload, int, error)
 	GetUploadByID(ctx context.Context, id int) (dbstore.Upload, bool, error)
-	ReferencesForUpload(ctx context.Context, uploadID int) (dbstore.PackageReferenceScanner, error)
+	UnresolvedReferencesForUpload(ctx context.Context, uploadID int) (dbstore.PackageReferenceScanner, error)
 	InsertCloneableDependencyRepo(ctx context.Context, dependency precise.Package) (bool, error)
 }
 
@@ -55,5 +55,5 @@ type GitserverClient interface {
 
 type IndexEnqueuer interface {
 	QueueIndexes(ctx context.Context, repositoryID int, rev, configuration string, force bool) ([]dbstore.Index, error)
-	QueueIndexesForPackage(ctx context.Context, pkg precise.Package) error
+	QueueIndexesForPackage(ctx context.Context, pkg precise.Package, allow func() (bool, error)) error
 }
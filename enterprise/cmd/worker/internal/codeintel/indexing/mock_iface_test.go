@@ -39,9 +39,10 @@ type MockDBStore struct {
 	// object controlling the behavior of the method
 	// InsertCloneableDependencyRepo.
 	InsertCloneableDependencyRepoFunc *DBStoreInsertCloneableDependencyRepoFunc
-	// ReferencesForUploadFunc is an instance of a mock function object
-	// controlling the behavior of the method ReferencesForUpload.
-	ReferencesForUploadFunc *DBStoreReferencesForUploadFunc
+	// UnresolvedReferencesForUploadFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// UnresolvedReferencesForUpload.
+	UnresolvedReferencesForUploadFunc *DBStoreUnresolvedReferencesForUploadFunc
 	// WithFunc is an instance of a mock function object controlling the
 	// behavior of the method With.
 	WithFunc *DBStoreWithFunc
@@ -76,7 +77,7 @@ func NewMockDBStore() *MockDBStore {
 				return false, nil
 			},
 		},
-		ReferencesForUploadFunc: &DBStoreReferencesForUploadFunc{
+		UnresolvedReferencesForUploadFunc: &DBStoreUnresolvedReferencesForUploadFunc{
 			defaultHook: func(context.Context, int) (dbstore.PackageReferenceScanner, error) {
 				return nil, nil
 			},
@@ -108,8 +109,8 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		InsertCloneableDependencyRepoFunc: &DBStoreInsertCloneableDependencyRepoFunc{
 			defaultHook: i.InsertCloneableDependencyRepo,
 		},
-		ReferencesForUploadFunc: &DBStoreReferencesForUploadFunc{
-			defaultHook: i.ReferencesForUpload,
+		UnresolvedReferencesForUploadFunc: &DBStoreUnresolvedReferencesForUploadFunc{
+			defaultHook: i.UnresolvedReferencesForUpload,
 		},
 		WithFunc: &DBStoreWithFunc{
 			defaultHook: i.With,
@@ -674,35 +675,36 @@ func (c DBStoreInsertCloneableDependencyRepoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// DBStoreReferencesForUploadFunc describes the behavior when the
-// ReferencesForUpload method of the parent MockDBStore instance is invoked.
-type DBStoreReferencesForUploadFunc struct {
+// DBStoreUnresolvedReferencesForUploadFunc describes the behavior when the
+// UnresolvedReferencesForUpload method of the parent MockDBStore instance is
+// invoked.
+type DBStoreUnresolvedReferencesForUploadFunc struct {
 	defaultHook func(context.Context, int) (dbstore.PackageReferenceScanner, error)
 	hooks       []func(context.Context, int) (dbstore.PackageReferenceScanner, error)
-	history     []DBStoreReferencesForUploadFuncCall
+	history     []DBStoreUnresolvedReferencesForUploadFuncCall
 	mutex       sync.Mutex
 }
 
-// ReferencesForUpload delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockDBStore) ReferencesForUpload(v0 context.Context, v1 int) (dbstore.PackageReferenceScanner, error) {
-	r0, r1 := m.ReferencesForUploadFunc.nextHook()(v0, v1)
-	m.ReferencesForUploadFunc.appendCall(DBStoreReferencesForUploadFuncCall{v0, v1, r0, r1})
+// UnresolvedReferencesForUpload delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDBStore) UnresolvedReferencesForUpload(v0 context.Context, v1 int) (dbstore.PackageReferenceScanner, error) {
+	r0, r1 := m.UnresolvedReferencesForUploadFunc.nextHook()(v0, v1)
+	m.UnresolvedReferencesForUploadFunc.appendCall(DBStoreUnresolvedReferencesForUploadFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the ReferencesForUpload
-// method of the parent MockDBStore instance is invoked and the hook queue
-// is empty.
-func (f *DBStoreReferencesForUploadFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.PackageReferenceScanner, error)) {
+// SetDefaultHook sets function that is called when the
+// UnresolvedReferencesForUpload method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreUnresolvedReferencesForUploadFunc) SetDefaultHook(hook func(context.Context, int) (dbstore.PackageReferenceScanner, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// ReferencesForUpload method of the parent MockDBStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *DBStoreReferencesForUploadFunc) PushHook(hook func(context.Context, int) (dbstore.PackageReferenceScanner, error)) {
+// UnresolvedReferencesForUpload method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the queue
+// is empty, the default hook function is invoked for any future action.
+func (f *DBStoreUnresolvedReferencesForUploadFunc) PushHook(hook func(context.Context, int) (dbstore.PackageReferenceScanner, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -710,7 +712,7 @@ func (f *DBStoreReferencesForUploadFunc) PushHook(hook func(context.Context, int
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *DBStoreReferencesForUploadFunc) SetDefaultReturn(r0 dbstore.PackageReferenceScanner, r1 error) {
+func (f *DBStoreUnresolvedReferencesForUploadFunc) SetDefaultReturn(r0 dbstore.PackageReferenceScanner, r1 error) {
 	f.SetDefaultHook(func(context.Context, int) (dbstore.PackageReferenceScanner, error) {
 		return r0, r1
 	})
@@ -718,13 +720,13 @@ func (f *DBStoreReferencesForUploadFunc) SetDefaultReturn(r0 dbstore.PackageRefe
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *DBStoreReferencesForUploadFunc) PushReturn(r0 dbstore.PackageReferenceScanner, r1 error) {
+func (f *DBStoreUnresolvedReferencesForUploadFunc) PushReturn(r0 dbstore.PackageReferenceScanner, r1 error) {
 	f.PushHook(func(context.Context, int) (dbstore.PackageReferenceScanner, error) {
 		return r0, r1
 	})
 }
 
-func (f *DBStoreReferencesForUploadFunc) nextHook() func(context.Context, int) (dbstore.PackageReferenceScanner, error) {
+func (f *DBStoreUnresolvedReferencesForUploadFunc) nextHook() func(context.Context, int) (dbstore.PackageReferenceScanner, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -737,26 +739,27 @@ func (f *DBStoreReferencesForUploadFunc) nextHook() func(context.Context, int) (
 	return hook
 }
 
-func (f *DBStoreReferencesForUploadFunc) appendCall(r0 DBStoreReferencesForUploadFuncCall) {
+func (f *DBStoreUnresolvedReferencesForUploadFunc) appendCall(r0 DBStoreUnresolvedReferencesForUploadFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of DBStoreReferencesForUploadFuncCall objects
-// describing the invocations of this function.
-func (f *DBStoreReferencesForUploadFunc) History() []DBStoreReferencesForUploadFuncCall {
+// History returns a sequence of DBStoreUnresolvedReferencesForUploadFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreUnresolvedReferencesForUploadFunc) History() []DBStoreUnresolvedReferencesForUploadFuncCall {
 	f.mutex.Lock()
-	history := make([]DBStoreReferencesForUploadFuncCall, len(f.history))
+	history := make([]DBStoreUnresolvedReferencesForUploadFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// DBStoreReferencesForUploadFuncCall is an object that describes an
-// invocation of method ReferencesForUpload on an instance of MockDBStore.
-type DBStoreReferencesForUploadFuncCall struct {
+// DBStoreUnresolvedReferencesForUploadFuncCall is an object that describes an
+// invocation of method UnresolvedReferencesForUpload on an instance of
+// MockDBStore.
+type DBStoreUnresolvedReferencesForUploadFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -773,13 +776,13 @@ type DBStoreReferencesForUploadFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c DBStoreReferencesForUploadFuncCall) Args() []interface{} {
+func (c DBStoreUnresolvedReferencesForUploadFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c DBStoreReferencesForUploadFuncCall) Results() []interface{} {
+func (c DBStoreUnresolvedReferencesForUploadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -1824,7 +1827,7 @@ func NewMockIndexEnqueuer() *MockIndexEnqueuer {
 			},
 		},
 		QueueIndexesForPackageFunc: &IndexEnqueuerQueueIndexesForPackageFunc{
-			defaultHook: func(context.Context, precise.Package) error {
+			defaultHook: func(context.Context, precise.Package, func() (bool, error)) error {
 				return nil
 			},
 		},
@@ -1967,24 +1970,24 @@ func (c IndexEnqueuerQueueIndexesFuncCall) Results() []interface{} {
 // QueueIndexesForPackage method of the parent MockIndexEnqueuer instance is
 // invoked.
 type IndexEnqueuerQueueIndexesForPackageFunc struct {
-	defaultHook func(context.Context, precise.Package) error
-	hooks       []func(context.Context, precise.Package) error
+	defaultHook func(context.Context, precise.Package, func() (bool, error)) error
+	hooks       []func(context.Context, precise.Package, func() (bool, error)) error
 	history     []IndexEnqueuerQueueIndexesForPackageFuncCall
 	mutex       sync.Mutex
 }
 
 // QueueIndexesForPackage delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockIndexEnqueuer) QueueIndexesForPackage(v0 context.Context, v1 precise.Package) error {
-	r0 := m.QueueIndexesForPackageFunc.nextHook()(v0, v1)
-	m.QueueIndexesForPackageFunc.appendCall(IndexEnqueuerQueueIndexesForPackageFuncCall{v0, v1, r0})
+func (m *MockIndexEnqueuer) QueueIndexesForPackage(v0 context.Context, v1 precise.Package, v2 func() (bool, error)) error {
+	r0 := m.QueueIndexesForPackageFunc.nextHook()(v0, v1, v2)
+	m.QueueIndexesForPackageFunc.appendCall(IndexEnqueuerQueueIndexesForPackageFuncCall{v0, v1, v2, r0})
 	return r0
 }
 
 // SetDefaultHook sets function that is called when the
 // QueueIndexesForPackage method of the parent MockIndexEnqueuer instance is
 // invoked and the hook queue is empty.
-func (f *IndexEnqueuerQueueIndexesForPackageFunc) SetDefaultHook(hook func(context.Context, precise.Package) error) {
+func (f *IndexEnqueuerQueueIndexesForPackageFunc) SetDefaultHook(hook func(context.Context, precise.Package, func() (bool, error)) error) {
 	f.defaultHook = hook
 }
 
@@ -1993,7 +1996,7 @@ func (f *IndexEnqueuerQueueIndexesForPackageFunc) SetDefaultHook(hook func(conte
 // invokes the hook at the front of the queue and discards it. After the
 // queue is empty, the default hook function is invoked for any future
 // action.
-func (f *IndexEnqueuerQueueIndexesForPackageFunc) PushHook(hook func(context.Context, precise.Package) error) {
+func (f *IndexEnqueuerQueueIndexesForPackageFunc) PushHook(hook func(context.Context, precise.Package, func() (bool, error)) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2002,7 +2005,7 @@ func (f *IndexEnqueuerQueueIndexesForPackageFunc) PushHook(hook func(context.Con
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
 func (f *IndexEnqueuerQueueIndexesForPackageFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, precise.Package) error {
+	f.SetDefaultHook(func(context.Context, precise.Package, func() (bool, error)) error {
 		return r0
 	})
 }
@@ -2010,12 +2013,12 @@ func (f *IndexEnqueuerQueueIndexesForPackageFunc) SetDefaultReturn(r0 error) {
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
 func (f *IndexEnqueuerQueueIndexesForPackageFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, precise.Package) error {
+	f.PushHook(func(context.Context, precise.Package, func() (bool, error)) error {
 		return r0
 	})
 }
 
-func (f *IndexEnqueuerQueueIndexesForPackageFunc) nextHook() func(context.Context, precise.Package) error {
+func (f *IndexEnqueuerQueueIndexesForPackageFunc) nextHook() func(context.Context, precise.Package, func() (bool, error)) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2055,6 +2058,9 @@ type IndexEnqueuerQueueIndexesForPackageFuncCall struct {
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 precise.Package
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 func() (bool, error)
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -2063,7 +2069,7 @@ type IndexEnqueuerQueueIndexesForPackageFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c IndexEnqueuerQueueIndexesForPackageFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
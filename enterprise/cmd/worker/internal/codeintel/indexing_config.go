@@ -14,6 +14,10 @@ type indexingConfig struct {
 	AutoIndexingTaskInterval               time.Duration
 	DependencyIndexerSchedulerPollInterval time.Duration
 	DependencyIndexerSchedulerConcurrency  int
+
+	DependencyIndexerSchedulerMaximumPackagesPerRepositoryPerHour int
+	DependencyIndexerSchedulerRequeueDelay                        time.Duration
+	DependencyIndexerSchedulerMaximumRequeueAge                   time.Duration
 }
 
 var indexingConfigInst = &indexingConfig{}
@@ -26,4 +30,7 @@ func (c *indexingConfig) Load() {
 	c.AutoIndexingTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_AUTO_INDEXING_TASK_INTERVAL", "10m", "The frequency with which to run periodic codeintel auto-indexing tasks.")
 	c.DependencyIndexerSchedulerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_DEPENDENCY_INDEXER_SCHEDULER_POLL_INTERVAL", "1s", "Interval between queries to the dependency indexing job queue.")
 	c.DependencyIndexerSchedulerConcurrency = c.GetInt("PRECISE_CODE_INTEL_DEPENDENCY_INDEXER_SCHEDULER_CONCURRENCY", "1", "The maximum number of dependency graphs that can be processed concurrently.")
+	c.DependencyIndexerSchedulerMaximumPackagesPerRepositoryPerHour = c.GetInt("PRECISE_CODE_INTEL_DEPENDENCY_INDEXER_SCHEDULER_MAXIMUM_PACKAGES_PER_REPOSITORY_PER_HOUR", "100", "The maximum number of dependencies indexed on behalf of a single repository per hour. Set to zero to disable limit.")
+	c.DependencyIndexerSchedulerRequeueDelay = c.GetInterval("PRECISE_CODE_INTEL_DEPENDENCY_INDEXER_SCHEDULER_REQUEUE_DELAY", "1m", "The delay before retrying a dependency graph whose package repositories are still being cloned.")
+	c.DependencyIndexerSchedulerMaximumRequeueAge = c.GetInterval("PRECISE_CODE_INTEL_DEPENDENCY_INDEXER_SCHEDULER_MAXIMUM_REQUEUE_AGE", "6h", "The age after which a dependency graph is no longer retried while its package repositories are being cloned.")
 }
@@ -62,6 +62,12 @@ func (j *indexingJob) Routines(ctx context.Context) ([]goroutine.BackgroundRouti
 	indexEnqueuer := enqueuer.NewIndexEnqueuer(enqueuerDBStoreShim, gitserverClient, repoupdater.DefaultClient, indexingConfigInst.AutoIndexEnqueuerConfig, observationContext)
 	metrics := workerutil.NewMetrics(observationContext, "codeintel_dependency_index_processor", nil)
 
+	dependencyIndexingSchedulerOptions := indexing.DependencyIndexingSchedulerOptions{
+		MaximumPackagesPerRepositoryPerHour: indexingConfigInst.DependencyIndexerSchedulerMaximumPackagesPerRepositoryPerHour,
+		RequeueDelay:                        indexingConfigInst.DependencyIndexerSchedulerRequeueDelay,
+		MaximumRequeueAge:                   indexingConfigInst.DependencyIndexerSchedulerMaximumRequeueAge,
+	}
+
 	settingStore := database.Settings(db)
 	repoStore := database.Repos(db)
 
@@ -79,7 +85,7 @@ func (j *indexingJob) Routines(ctx context.Context) ([]goroutine.BackgroundRouti
 
 	routines := []goroutine.BackgroundRoutine{
 		indexing.NewIndexScheduler(dbStoreShim, settingStore, repoStore, indexEnqueuer, indexingConfigInst.AutoIndexingTaskInterval, observationContext),
-		indexing.NewDependencyIndexingScheduler(dbStoreShim, dependencyIndexStore, extSvcStore, indexEnqueuer, indexingConfigInst.DependencyIndexerSchedulerPollInterval, indexingConfigInst.DependencyIndexerSchedulerConcurrency, metrics),
+		indexing.NewDependencyIndexingScheduler(dbStoreShim, dependencyIndexStore, extSvcStore, indexEnqueuer, indexingConfigInst.DependencyIndexerSchedulerPollInterval, indexingConfigInst.DependencyIndexerSchedulerConcurrency, dependencyIndexingSchedulerOptions, metrics),
 	}
 
 	return routines, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 )
@@ -19,6 +20,10 @@ type DBStore interface {
 	DeleteUploadsWithoutRepository(ctx context.Context, now time.Time) (map[int]int, error)
 	HardDeleteUploadByID(ctx context.Context, ids ...int) error
 	SoftDeleteOldUploads(ctx context.Context, maxAge time.Duration, now time.Time) (int, error)
+	SoftDeleteExpiredUploads(ctx context.Context) (int, error)
+	SelectRepositoriesForRetentionScan(ctx context.Context, processDelay time.Duration, limit int, now time.Time) ([]int, error)
+	UpdateUploadRetention(ctx context.Context, protectedIDs, expiredIDs []int) error
+	GetConfigurationPolicies(ctx context.Context, opts dbstore.GetConfigurationPoliciesOptions) ([]dbstore.ConfigurationPolicy, error)
 	DeleteOldIndexes(ctx context.Context, maxAge time.Duration, now time.Time) (int, error)
 	DirtyRepositories(ctx context.Context) (map[int]int, error)
 	DeleteIndexesWithoutRepository(ctx context.Context, now time.Time) (map[int]int, error)
@@ -40,6 +45,10 @@ func (s *DBStoreShim) Transact(ctx context.Context) (DBStore, error) {
 	return &DBStoreShim{store}, nil
 }
 
+type PolicyMatcher interface {
+	CommitsDescribedByPolicy(ctx context.Context, repositoryID int, policies []dbstore.ConfigurationPolicy, now time.Time) (map[string][]policies.PolicyMatch, error)
+}
+
 type LSIFStore interface {
 	Clear(ctx context.Context, bundleIDs ...int) error
 }
@@ -9,6 +9,7 @@ import (
 type metrics struct {
 	// Expiration metrics
 	numUploadRecordsRemoved prometheus.Counter
+	numUploadRecordsExpired prometheus.Counter
 	numIndexRecordsRemoved  prometheus.Counter
 	numUploadsPurged        prometheus.Counter
 	numUploadResets         prometheus.Counter
@@ -42,6 +43,10 @@ func newMetrics(observationContext *observation.Context) *metrics {
 		"src_codeintel_background_upload_records_removed_total",
 		"The number of codeintel upload records removed.",
 	)
+	numUploadRecordsExpired := counter(
+		"src_codeintel_background_upload_records_expired_total",
+		"The number of codeintel upload records marked as expired by data retention policies.",
+	)
 	numIndexRecordsRemoved := counter(
 		"src_codeintel_background_index_records_removed_total",
 		"The number of codeintel index records removed.",
@@ -96,6 +101,7 @@ func newMetrics(observationContext *observation.Context) *metrics {
 
 	return &metrics{
 		numUploadRecordsRemoved:         numUploadRecordsRemoved,
+		numUploadRecordsExpired:         numUploadRecordsExpired,
 		numIndexRecordsRemoved:          numIndexRecordsRemoved,
 		numUploadsPurged:                numUploadsPurged,
 		numErrors:                       numErrors,
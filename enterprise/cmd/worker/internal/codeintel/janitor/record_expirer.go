@@ -5,31 +5,78 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/derision-test/glock"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 )
 
 type recordExpirer struct {
-	dbStore DBStore
-	ttl     time.Duration
-	metrics *metrics
+	dbStore                DBStore
+	policyMatcher          PolicyMatcher
+	ttl                    time.Duration
+	repositoryProcessDelay time.Duration
+	repositoryBatchSize    int
+	uploadBatchSize        int
+	metrics                *metrics
+	clock                  glock.Clock
 }
 
 var _ goroutine.Handler = &recordExpirer{}
 var _ goroutine.ErrorHandler = &recordExpirer{}
 
-// NewRecordExpirer returns a background routine that periodically removes upload
-// and index records that are older than the given TTL. Upload records which have
-// valid LSIF data (not just a historic upload failure record) will only be deleted
-// if it is not visible at the tip of its repository's default branch.
-func NewRecordExpirer(dbStore DBStore, ttl, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
-	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &recordExpirer{
-		dbStore: dbStore,
-		ttl:     ttl,
-		metrics: metrics,
-	})
+// NewRecordExpirer returns a background routine that periodically expires upload records
+// which are no longer retained by any configuration policy, and removes index records and
+// incomplete upload records that are older than the given TTL.
+//
+// Each invocation checks the completed uploads of a batch of repositories against the global
+// and repository-specific retention policies, then removes expired uploads that are neither
+// visible from the tip of a branch or tag nor a dependency of an upload that is retained.
+func NewRecordExpirer(
+	dbStore DBStore,
+	policyMatcher PolicyMatcher,
+	ttl time.Duration,
+	repositoryProcessDelay time.Duration,
+	repositoryBatchSize int,
+	uploadBatchSize int,
+	interval time.Duration,
+	metrics *metrics,
+) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, newRecordExpirer(
+		dbStore,
+		policyMatcher,
+		ttl,
+		repositoryProcessDelay,
+		repositoryBatchSize,
+		uploadBatchSize,
+		metrics,
+		glock.NewRealClock(),
+	))
+}
+
+func newRecordExpirer(
+	dbStore DBStore,
+	policyMatcher PolicyMatcher,
+	ttl time.Duration,
+	repositoryProcessDelay time.Duration,
+	repositoryBatchSize int,
+	uploadBatchSize int,
+	metrics *metrics,
+	clock glock.Clock,
+) *recordExpirer {
+	return &recordExpirer{
+		dbStore:                dbStore,
+		policyMatcher:          policyMatcher,
+		ttl:                    ttl,
+		repositoryProcessDelay: repositoryProcessDelay,
+		repositoryBatchSize:    repositoryBatchSize,
+		uploadBatchSize:        uploadBatchSize,
+		metrics:                metrics,
+		clock:                  clock,
+	}
 }
 
 func (e *recordExpirer) Handle(ctx context.Context) error {
@@ -50,13 +97,36 @@ func (e *recordExpirer) HandleError(err error) {
 }
 
 func (e *recordExpirer) expireUploads(ctx context.Context) error {
-	tx, err := e.dbStore.Transact(ctx)
+	now := e.clock.Now()
+
+	repositoryIDs, err := e.dbStore.SelectRepositoriesForRetentionScan(ctx, e.repositoryProcessDelay, e.repositoryBatchSize, now)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "SelectRepositoriesForRetentionScan")
+	}
+
+	if len(repositoryIDs) > 0 {
+		globalPolicies, err := e.dbStore.GetConfigurationPolicies(ctx, dbstore.GetConfigurationPoliciesOptions{})
+		if err != nil {
+			return errors.Wrap(err, "GetConfigurationPolicies")
+		}
+
+		for _, repositoryID := range repositoryIDs {
+			if err := e.handleRepository(ctx, repositoryID, globalPolicies, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	count, err := e.dbStore.SoftDeleteExpiredUploads(ctx)
+	if err != nil {
+		return errors.Wrap(err, "SoftDeleteExpiredUploads")
+	}
+	if count > 0 {
+		log15.Debug("Deleted expired upload records", "count", count)
+		e.metrics.numUploadRecordsRemoved.Add(float64(count))
 	}
-	defer func() { err = tx.Done(err) }()
 
-	count, err := tx.SoftDeleteOldUploads(ctx, e.ttl, time.Now())
+	count, err = e.dbStore.SoftDeleteOldUploads(ctx, e.ttl, now)
 	if err != nil {
 		return errors.Wrap(err, "SoftDeleteOldUploads")
 	}
@@ -68,6 +138,52 @@ func (e *recordExpirer) expireUploads(ctx context.Context) error {
 	return nil
 }
 
+// handleRepository marks each completed upload of the given repository as protected or expired
+// according to the given global policies and the policies attached to the repository.
+func (e *recordExpirer) handleRepository(ctx context.Context, repositoryID int, globalPolicies []dbstore.ConfigurationPolicy, now time.Time) error {
+	repositoryPolicies, err := e.dbStore.GetConfigurationPolicies(ctx, dbstore.GetConfigurationPoliciesOptions{
+		RepositoryID: repositoryID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "GetConfigurationPolicies")
+	}
+
+	combinedPolicies := make([]dbstore.ConfigurationPolicy, 0, len(globalPolicies)+len(repositoryPolicies))
+	combinedPolicies = append(combinedPolicies, globalPolicies...)
+	combinedPolicies = append(combinedPolicies, repositoryPolicies...)
+
+	commitMap, err := e.policyMatcher.CommitsDescribedByPolicy(ctx, repositoryID, combinedPolicies, now)
+	if err != nil {
+		return errors.Wrap(err, "policies.CommitsDescribedByPolicy")
+	}
+
+	for offset := 0; ; {
+		uploads, totalCount, err := e.dbStore.GetUploads(ctx, dbstore.GetUploadsOptions{
+			RepositoryID: repositoryID,
+			State:        "completed",
+			OldestFirst:  true,
+			Limit:        e.uploadBatchSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return errors.Wrap(err, "GetUploads")
+		}
+
+		protectedIDs, expiredIDs := policies.PartitionUploads(uploads, commitMap)
+		if err := e.dbStore.UpdateUploadRetention(ctx, protectedIDs, expiredIDs); err != nil {
+			return errors.Wrap(err, "UpdateUploadRetention")
+		}
+		if len(expiredIDs) > 0 {
+			log15.Debug("Expired upload records", "repositoryID", repositoryID, "count", len(expiredIDs))
+			e.metrics.numUploadRecordsExpired.Add(float64(len(expiredIDs)))
+		}
+
+		if offset += len(uploads); len(uploads) == 0 || offset >= totalCount {
+			return nil
+		}
+	}
+}
+
 func (e *recordExpirer) expireIndexes(ctx context.Context) error {
 	tx, err := e.dbStore.Transact(ctx)
 	if err != nil {
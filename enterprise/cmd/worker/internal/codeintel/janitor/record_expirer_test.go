@@ -0,0 +1,96 @@
+package janitor
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestRecordExpirer(t *testing.T) {
+	globalPolicies := []dbstore.ConfigurationPolicy{
+		{ID: 1, Type: "GIT_TAG", Pattern: "*", RetentionEnabled: true},
+	}
+	repositoryPolicies := map[int][]dbstore.ConfigurationPolicy{
+		50: {{ID: 2, Type: "GIT_TREE", Pattern: "main", RetentionEnabled: true}},
+	}
+	uploads := map[int][]dbstore.Upload{
+		50: {
+			{ID: 1, Commit: "deadbeef01"},
+			{ID: 2, Commit: "deadbeef02"},
+			{ID: 3, Commit: "deadbeef03", VisibleAtTip: true},
+		},
+		51: {
+			{ID: 4, Commit: "deadbeef04"},
+			{ID: 5, Commit: "deadbeef05"},
+		},
+	}
+	commitMaps := map[int]map[string][]policies.PolicyMatch{
+		50: {"deadbeef01": {{Name: "main"}}},
+		51: {"deadbeef05": {{Name: "v1.2.3"}}},
+	}
+
+	dbStore := NewMockDBStore()
+	dbStore.TransactFunc.SetDefaultReturn(dbStore, nil)
+	dbStore.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	dbStore.SelectRepositoriesForRetentionScanFunc.SetDefaultReturn([]int{50, 51}, nil)
+	dbStore.GetConfigurationPoliciesFunc.SetDefaultHook(func(ctx context.Context, opts dbstore.GetConfigurationPoliciesOptions) ([]dbstore.ConfigurationPolicy, error) {
+		if opts.RepositoryID == 0 {
+			return globalPolicies, nil
+		}
+		return repositoryPolicies[opts.RepositoryID], nil
+	})
+	dbStore.GetUploadsFunc.SetDefaultHook(func(ctx context.Context, opts dbstore.GetUploadsOptions) ([]dbstore.Upload, int, error) {
+		// Return a single upload per page to exercise pagination
+		repositoryUploads := uploads[opts.RepositoryID]
+		if opts.Offset >= len(repositoryUploads) {
+			return nil, len(repositoryUploads), nil
+		}
+		return repositoryUploads[opts.Offset : opts.Offset+1], len(repositoryUploads), nil
+	})
+
+	policyMatcher := NewMockPolicyMatcher()
+	policyMatcher.CommitsDescribedByPolicyFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, configurationPolicies []dbstore.ConfigurationPolicy, now time.Time) (map[string][]policies.PolicyMatch, error) {
+		return commitMaps[repositoryID], nil
+	})
+
+	expirer := newRecordExpirer(dbStore, policyMatcher, time.Hour, time.Hour, 100, 1, newMetrics(&observation.TestContext), glock.NewMockClock())
+	if err := expirer.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error running record expirer: %s", err)
+	}
+
+	var protectedIDs, expiredIDs []int
+	for _, call := range dbStore.UpdateUploadRetentionFunc.History() {
+		protectedIDs = append(protectedIDs, call.Arg1...)
+		expiredIDs = append(expiredIDs, call.Arg2...)
+	}
+	sort.Ints(protectedIDs)
+	sort.Ints(expiredIDs)
+
+	if diff := cmp.Diff([]int{1, 3, 5}, protectedIDs); diff != "" {
+		t.Errorf("unexpected protected uploads (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{2, 4}, expiredIDs); diff != "" {
+		t.Errorf("unexpected expired uploads (-want +got):\n%s", diff)
+	}
+
+	history := policyMatcher.CommitsDescribedByPolicyFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected number of calls to CommitsDescribedByPolicy. want=%d have=%d", 2, len(history))
+	}
+	expectedPolicies := append(append([]dbstore.ConfigurationPolicy(nil), globalPolicies...), repositoryPolicies[50]...)
+	if diff := cmp.Diff(expectedPolicies, history[0].Arg2); diff != "" {
+		t.Errorf("unexpected policies (-want +got):\n%s", diff)
+	}
+
+	if len(dbStore.SoftDeleteExpiredUploadsFunc.History()) != 1 {
+		t.Errorf("expected expired uploads to be deleted")
+	}
+}
@@ -15,6 +15,9 @@ type janitorConfig struct {
 	CommitResolverTaskInterval              time.Duration
 	CommitResolverMinimumTimeSinceLastCheck time.Duration
 	CommitResolverBatchSize                 int
+	RetentionRepositoryProcessDelay         time.Duration
+	RetentionRepositoryBatchSize            int
+	RetentionUploadBatchSize                int
 }
 
 var janitorConfigInst = &janitorConfig{}
@@ -26,4 +29,7 @@ func (c *janitorConfig) Load() {
 	c.CommitResolverTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_COMMIT_RESOLVER_TASK_INTERVAL", "10s", "The frequency with which to run the periodic commit resolver task.")
 	c.CommitResolverMinimumTimeSinceLastCheck = c.GetInterval("PRECISE_CODE_INTEL_COMMIT_RESOLVER_MINIMUM_TIME_SINCE_LAST_CHECK", "24h", "The minimum time the commit resolver will re-check an upload or index record.")
 	c.CommitResolverBatchSize = c.GetInt("PRECISE_CODE_INTEL_COMMIT_RESOLVER_BATCH_SIZE", "100", "The maximum number of unique commits to resolve at a time.")
+	c.RetentionRepositoryProcessDelay = c.GetInterval("PRECISE_CODE_INTEL_RETENTION_REPOSITORY_PROCESS_DELAY", "24h", "The minimum time between checks of the same repository against data retention policies.")
+	c.RetentionRepositoryBatchSize = c.GetInt("PRECISE_CODE_INTEL_RETENTION_REPOSITORY_BATCH_SIZE", "100", "The maximum number of repositories to check against data retention policies at a time.")
+	c.RetentionUploadBatchSize = c.GetInt("PRECISE_CODE_INTEL_RETENTION_UPLOAD_BATCH_SIZE", "100", "The maximum number of uploads to mark as protected or expired in a single query.")
 }
@@ -0,0 +1,3 @@
+package ranking
+
+//go:generate ../../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel/ranking -i DBStore -i LSIFStore -o mock_iface_test.go
@@ -0,0 +1,19 @@
+package ranking
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+type DBStore interface {
+	StaleReferenceCountRepositories(ctx context.Context, minimumTimeSinceLastUpdate time.Duration, limit int, now time.Time) ([]int, error)
+	DefaultBranchDumps(ctx context.Context, repositoryID int) ([]dbstore.Dump, error)
+	InboundPackageReferences(ctx context.Context, repositoryID, uploadID int) (dbstore.PackageReferenceScanner, error)
+	UpdateReferenceCounts(ctx context.Context, repositoryID int, pathCounts map[string]int, now time.Time) error
+}
+
+type LSIFStore interface {
+	DefinitionPaths(ctx context.Context, bundleID int) (map[string][]string, error)
+}
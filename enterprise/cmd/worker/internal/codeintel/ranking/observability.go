@@ -0,0 +1,25 @@
+package ranking
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type operations struct {
+	countReferences *observation.Operation
+}
+
+func newOperations(observationContext *observation.Context) *operations {
+	countReferences := observationContext.Operation(observation.Op{
+		Name: "codeintel.referenceCounter",
+		Metrics: metrics.NewOperationMetrics(
+			observationContext.Registerer,
+			"codeintel_reference_counter",
+			metrics.WithCountHelp("Total number of method invocations."),
+		),
+	})
+
+	return &operations{
+		countReferences: countReferences,
+	}
+}
@@ -0,0 +1,188 @@
+package ranking
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/derision-test/glock"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/bloomfilter"
+)
+
+// ReferenceCounter periodically calculates the number of uploads from other repositories that
+// reference the definitions of each repository with precise code intelligence. Counts are kept
+// per file, as the sum of the number of uploads referencing each of the symbols defined within
+// that file.
+type ReferenceCounter struct {
+	dbStore                    DBStore
+	lsifStore                  LSIFStore
+	minimumTimeSinceLastUpdate time.Duration
+	batchSize                  int
+	operations                 *operations
+	clock                      glock.Clock
+}
+
+var (
+	_ goroutine.Handler      = &ReferenceCounter{}
+	_ goroutine.ErrorHandler = &ReferenceCounter{}
+)
+
+// NewReferenceCounter returns a background routine that periodically re-calculates the inbound
+// reference counts of repositories whose counts are missing or stale.
+func NewReferenceCounter(
+	dbStore DBStore,
+	lsifStore LSIFStore,
+	minimumTimeSinceLastUpdate time.Duration,
+	batchSize int,
+	interval time.Duration,
+	observationContext *observation.Context,
+) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, newReferenceCounter(
+		dbStore,
+		lsifStore,
+		minimumTimeSinceLastUpdate,
+		batchSize,
+		observationContext,
+		glock.NewRealClock(),
+	))
+}
+
+func newReferenceCounter(
+	dbStore DBStore,
+	lsifStore LSIFStore,
+	minimumTimeSinceLastUpdate time.Duration,
+	batchSize int,
+	observationContext *observation.Context,
+	clock glock.Clock,
+) *ReferenceCounter {
+	return &ReferenceCounter{
+		dbStore:                    dbStore,
+		lsifStore:                  lsifStore,
+		minimumTimeSinceLastUpdate: minimumTimeSinceLastUpdate,
+		batchSize:                  batchSize,
+		operations:                 newOperations(observationContext),
+		clock:                      clock,
+	}
+}
+
+// Handle re-calculates the reference counts of a batch of repositories with stale counts.
+func (c *ReferenceCounter) Handle(ctx context.Context) error {
+	repositoryIDs, err := c.dbStore.StaleReferenceCountRepositories(ctx, c.minimumTimeSinceLastUpdate, c.batchSize, c.clock.Now())
+	if err != nil {
+		return errors.Wrap(err, "dbstore.StaleReferenceCountRepositories")
+	}
+
+	var countErr error
+	for _, repositoryID := range repositoryIDs {
+		if err := c.countReferences(ctx, repositoryID); err != nil {
+			if countErr == nil {
+				countErr = err
+			} else {
+				countErr = multierror.Append(countErr, err)
+			}
+		}
+	}
+
+	return countErr
+}
+
+func (c *ReferenceCounter) HandleError(err error) {
+	log15.Error("Failed to count references", "err", err)
+}
+
+// countReferences calculates and stores the per-path inbound reference counts
+// of the uploads visible from the tip of the default branch of the given repository.
+func (c *ReferenceCounter) countReferences(ctx context.Context, repositoryID int) (err error) {
+	ctx, traceLog, endObservation := c.operations.countReferences.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	dumps, err := c.dbStore.DefaultBranchDumps(ctx, repositoryID)
+	if err != nil {
+		return errors.Wrap(err, "dbstore.DefaultBranchDumps")
+	}
+	traceLog(log.Int("numDumps", len(dumps)))
+
+	pathCounts := map[string]int{}
+
+	for _, dump := range dumps {
+		definitionPaths, err := c.lsifStore.DefinitionPaths(ctx, dump.ID)
+		if err != nil {
+			return errors.Wrap(err, "lsifstore.DefinitionPaths")
+		}
+		if len(definitionPaths) == 0 {
+			continue
+		}
+
+		referencingUploads, err := c.referencingUploads(ctx, repositoryID, dump.ID, definitionPaths)
+		if err != nil {
+			return err
+		}
+
+		for identifier, uploadIDs := range referencingUploads {
+			for _, path := range definitionPaths[identifier] {
+				pathCounts[dump.Root+path] += len(uploadIDs)
+			}
+		}
+	}
+	traceLog(log.Int("numPaths", len(pathCounts)))
+
+	if err := c.dbStore.UpdateReferenceCounts(ctx, repositoryID, pathCounts, c.clock.Now()); err != nil {
+		return errors.Wrap(err, "dbstore.UpdateReferenceCounts")
+	}
+
+	return nil
+}
+
+// referencingUploads returns the set of uploads from other repositories that import each of the
+// identifiers defined by the given upload. Identifiers which are not imported by any upload are
+// omitted from the result.
+func (c *ReferenceCounter) referencingUploads(ctx context.Context, repositoryID, uploadID int, definitionPaths map[string][]string) (_ map[string]map[int]struct{}, err error) {
+	scanner, err := c.dbStore.InboundPackageReferences(ctx, repositoryID, uploadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "dbstore.InboundPackageReferences")
+	}
+	defer func() {
+		if closeErr := scanner.Close(); closeErr != nil {
+			err = multierror.Append(err, errors.Wrap(closeErr, "dbstore.InboundPackageReferences.Close"))
+		}
+	}()
+
+	referencingUploads := map[string]map[int]struct{}{}
+	for {
+		packageReference, exists, err := scanner.Next()
+		if err != nil {
+			return nil, errors.Wrap(err, "dbstore.InboundPackageReferences.Next")
+		}
+		if !exists {
+			break
+		}
+
+		// Each upload has an associated bloom filter encoding the set of identifiers it imports
+		// from the package. A false positive here only slightly inflates the reference count.
+		includesIdentifier, err := bloomfilter.Decode(packageReference.Filter)
+		if err != nil {
+			return nil, errors.Wrap(err, "bloomfilter.Decode")
+		}
+
+		for identifier := range definitionPaths {
+			if !includesIdentifier(identifier) {
+				continue
+			}
+
+			if _, ok := referencingUploads[identifier]; !ok {
+				referencingUploads[identifier] = map[int]struct{}{}
+			}
+			referencingUploads[identifier][packageReference.DumpID] = struct{}{}
+		}
+	}
+
+	return referencingUploads, nil
+}
@@ -0,0 +1,81 @@
+package ranking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/bloomfilter"
+)
+
+func TestReferenceCounter(t *testing.T) {
+	dbStore := NewMockDBStore()
+	lsifStore := NewMockLSIFStore()
+	clock := glock.NewMockClock()
+
+	dbStore.StaleReferenceCountRepositoriesFunc.SetDefaultReturn([]int{42}, nil)
+	dbStore.DefaultBranchDumpsFunc.SetDefaultReturn([]dbstore.Dump{
+		{ID: 1, RepositoryID: 42, Root: "sub1/"},
+		{ID: 2, RepositoryID: 42, Root: "sub2/"},
+	}, nil)
+
+	lsifStore.DefinitionPathsFunc.SetDefaultHook(func(ctx context.Context, bundleID int) (map[string][]string, error) {
+		if bundleID == 1 {
+			return map[string][]string{
+				"pkg:Foo": {"a.go"},
+				"pkg:Bar": {"a.go", "b.go"},
+				"pkg:Baz": {"c.go"},
+			}, nil
+		}
+
+		return nil, nil
+	})
+
+	filter1 := makeFilter(t, "pkg:Foo", "pkg:Bar")
+	filter2 := makeFilter(t, "pkg:Bar")
+	dbStore.InboundPackageReferencesFunc.SetDefaultReturn(dbstore.PackageReferenceScannerFromSlice(
+		lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 10, Scheme: "gomod", Name: "pkg", Version: "v1"}, Filter: filter1},
+		lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 11, Scheme: "gomod", Name: "pkg", Version: "v1"}, Filter: filter2},
+		lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 11, Scheme: "gomod", Name: "pkg", Version: "v2"}, Filter: filter2},
+	), nil)
+
+	counter := newReferenceCounter(dbStore, lsifStore, time.Hour, 100, &observation.TestContext, clock)
+	if err := counter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error counting references: %s", err)
+	}
+
+	if len(dbStore.InboundPackageReferencesFunc.History()) != 1 {
+		t.Fatalf("unexpected number of calls to InboundPackageReferences. want=%d have=%d", 1, len(dbStore.InboundPackageReferencesFunc.History()))
+	}
+
+	if history := dbStore.UpdateReferenceCountsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to UpdateReferenceCounts. want=%d have=%d", 1, len(history))
+	} else {
+		if history[0].Arg1 != 42 {
+			t.Errorf("unexpected repository id. want=%d have=%d", 42, history[0].Arg1)
+		}
+
+		expectedPathCounts := map[string]int{
+			"sub1/a.go": 3,
+			"sub1/b.go": 2,
+		}
+		if diff := cmp.Diff(expectedPathCounts, history[0].Arg2); diff != "" {
+			t.Errorf("unexpected path counts (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func makeFilter(t *testing.T, identifiers ...string) []byte {
+	filter, err := bloomfilter.CreateFilter(identifiers)
+	if err != nil {
+		t.Fatalf("unexpected error creating filter: %s", err)
+	}
+
+	return filter
+}
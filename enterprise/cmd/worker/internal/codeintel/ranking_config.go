@@ -0,0 +1,23 @@
+package codeintel
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+type rankingConfig struct {
+	env.BaseConfig
+
+	ReferenceCountTaskInterval               time.Duration
+	ReferenceCountMinimumTimeSinceLastUpdate time.Duration
+	ReferenceCountRepositoryBatchSize        int
+}
+
+var rankingConfigInst = &rankingConfig{}
+
+func (c *rankingConfig) Load() {
+	c.ReferenceCountTaskInterval = c.GetInterval("PRECISE_CODE_INTEL_REFERENCE_COUNT_TASK_INTERVAL", "1m", "The frequency with which to run the periodic reference count task.")
+	c.ReferenceCountMinimumTimeSinceLastUpdate = c.GetInterval("PRECISE_CODE_INTEL_REFERENCE_COUNT_MINIMUM_TIME_SINCE_LAST_UPDATE", "24h", "The minimum time between re-calculations of the inbound reference counts of a repository.")
+	c.ReferenceCountRepositoryBatchSize = c.GetInt("PRECISE_CODE_INTEL_REFERENCE_COUNT_REPOSITORY_BATCH_SIZE", "10", "The maximum number of repositories for which to calculate reference counts at a time.")
+}
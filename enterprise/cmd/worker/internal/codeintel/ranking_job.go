@@ -0,0 +1,57 @@
+package codeintel
+
+import (
+	"context"
+
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/shared"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/worker/internal/codeintel/ranking"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+type rankingJob struct{}
+
+func NewRankingJob() shared.Job {
+	return &rankingJob{}
+}
+
+func (j *rankingJob) Config() []env.Config {
+	return []env.Config{rankingConfigInst}
+}
+
+func (j *rankingJob) Routines(ctx context.Context) ([]goroutine.BackgroundRoutine, error) {
+	observationContext := &observation.Context{
+		Logger:     log15.Root(),
+		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Registerer: prometheus.DefaultRegisterer,
+	}
+
+	dbStore, err := InitDBStore()
+	if err != nil {
+		return nil, err
+	}
+
+	lsifStore, err := InitLSIFStore()
+	if err != nil {
+		return nil, err
+	}
+
+	routines := []goroutine.BackgroundRoutine{
+		ranking.NewReferenceCounter(
+			dbStore,
+			lsifStore,
+			rankingConfigInst.ReferenceCountMinimumTimeSinceLastUpdate,
+			rankingConfigInst.ReferenceCountRepositoryBatchSize,
+			rankingConfigInst.ReferenceCountTaskInterval,
+			observationContext,
+		),
+	}
+
+	return routines, nil
+}
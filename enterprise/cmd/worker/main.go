@@ -30,6 +30,7 @@ func main() {
 		"codeintel-commitgraph":    codeintel.NewCommitGraphJob(),
 		"codeintel-janitor":        codeintel.NewJanitorJob(),
 		"codeintel-auto-indexing":  codeintel.NewIndexingJob(),
+		"codeintel-ranking":        codeintel.NewRankingJob(),
 		"codehost-version-syncing": versions.NewSyncingJob(),
 		"insights-job":             insights.NewInsightsJob(),
 	})
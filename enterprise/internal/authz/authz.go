@@ -10,9 +10,11 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/authz/gitolite"
 	"github.com/sourcegraph/sourcegraph/internal/authz/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -53,6 +55,8 @@ func ProvidersFromConfig(
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindBitbucketCloud,
+			extsvc.KindGitolite,
 			extsvc.KindPerforce,
 		},
 		LimitOffset: &database.LimitOffset{
@@ -64,6 +68,8 @@ func ProvidersFromConfig(
 		gitHubConns          []*types.GitHubConnection
 		gitLabConns          []*types.GitLabConnection
 		bitbucketServerConns []*types.BitbucketServerConnection
+		bitbucketCloudConns  []*types.BitbucketCloudConnection
+		gitoliteConns        []*types.GitoliteConnection
 		perforceConns        []*types.PerforceConnection
 	)
 	for {
@@ -104,6 +110,16 @@ func ProvidersFromConfig(
 					URN:                       svc.URN(),
 					BitbucketServerConnection: c,
 				})
+			case *schema.BitbucketCloudConnection:
+				bitbucketCloudConns = append(bitbucketCloudConns, &types.BitbucketCloudConnection{
+					URN:                      svc.URN(),
+					BitbucketCloudConnection: c,
+				})
+			case *schema.GitoliteConnection:
+				gitoliteConns = append(gitoliteConns, &types.GitoliteConnection{
+					URN:                svc.URN(),
+					GitoliteConnection: c,
+				})
 			case *schema.PerforceConnection:
 				perforceConns = append(perforceConns, &types.PerforceConnection{
 					URN:                svc.URN(),
@@ -142,6 +158,20 @@ func ProvidersFromConfig(
 		warnings = append(warnings, bbsWarnings...)
 	}
 
+	if len(bitbucketCloudConns) > 0 {
+		bbcProviders, bbcProblems, bbcWarnings := bitbucketcloud.NewAuthzProviders(bitbucketCloudConns)
+		providers = append(providers, bbcProviders...)
+		seriousProblems = append(seriousProblems, bbcProblems...)
+		warnings = append(warnings, bbcWarnings...)
+	}
+
+	if len(gitoliteConns) > 0 {
+		gtProviders, gtProblems, gtWarnings := gitolite.NewAuthzProviders(gitoliteConns)
+		providers = append(providers, gtProviders...)
+		seriousProblems = append(seriousProblems, gtProblems...)
+		warnings = append(warnings, gtWarnings...)
+	}
+
 	if len(perforceConns) > 0 {
 		pfProviders, pfProblems, pfWarnings := perforce.NewAuthzProviders(perforceConns)
 		providers = append(providers, pfProviders...)
@@ -0,0 +1,210 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types/scheduler/config"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+const autoMergeInterval = 1 * time.Minute
+
+// autoMergeRetryAfter is the time after which a changeset whose merge job
+// didn't succeed is considered for auto-merging again.
+const autoMergeRetryAfter = 1 * time.Hour
+
+func newAutoMergeJob(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	m := &autoMerger{store: s, now: time.Now}
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		autoMergeInterval,
+		goroutine.NewHandlerWithErrorMessage("auto-merge batch changes changesets", func(ctx context.Context) error {
+			// Merges are only enqueued while the rollout windows allow
+			// changesets to be processed.
+			if !config.ActiveWindow().IsOpen(m.now()) {
+				return nil
+			}
+			return m.run(ctx)
+		}),
+	)
+}
+
+// autoMerger enqueues merge jobs for the changesets of batch changes that
+// have autoMerge configured in their batch spec, once they are approved and
+// their checks have passed.
+type autoMerger struct {
+	store *store.Store
+	now   func() time.Time
+}
+
+func (m *autoMerger) run(ctx context.Context) error {
+	batchChanges, _, err := m.store.ListBatchChanges(ctx, store.ListBatchChangesOpts{
+		State:         btypes.BatchChangeStateOpen,
+		OnlyAutoMerge: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing batch changes")
+	}
+
+	var errs *multierror.Error
+	for _, bc := range batchChanges {
+		if err := m.enqueueMerges(ctx, bc); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "batch change %d", bc.ID))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (m *autoMerger) enqueueMerges(ctx context.Context, bc *btypes.BatchChange) (err error) {
+	spec, err := m.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: bc.BatchSpecID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch spec")
+	}
+	autoMerge := spec.Spec.AutoMerge
+	if autoMerge == nil {
+		return nil
+	}
+
+	// 🚨 SECURITY: The merge is performed with the credentials of the user
+	// that last applied the batch change, since they configured autoMerge.
+	if bc.LastApplierID == 0 {
+		return nil
+	}
+
+	now := m.now()
+
+	opts := store.ListAutoMergeableChangesetsOpts{
+		BatchChangeID: bc.ID,
+		RetryAfter:    now.Add(-autoMergeRetryAfter),
+	}
+	remaining := 0
+	if autoMerge.MaxPerHour > 0 {
+		count, err := m.store.CountChangesetJobs(ctx, store.CountChangesetJobsOpts{
+			BatchChangeID: bc.ID,
+			JobType:       btypes.ChangesetJobTypeMerge,
+			CreatedAfter:  now.Add(-1 * time.Hour),
+		})
+		if err != nil {
+			return errors.Wrap(err, "counting merge jobs")
+		}
+
+		remaining = autoMerge.MaxPerHour - count
+		if remaining <= 0 {
+			return nil
+		}
+	}
+
+	var requiredChecks []string
+	if spec.Spec.ChangesetTemplate != nil {
+		requiredChecks = spec.Spec.ChangesetTemplate.RequiredChecks
+	}
+
+	cs, err := listChangesetsToMerge(ctx, m.store.ListAutoMergeableChangesets, opts, requiredChecks, remaining)
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+	if len(cs) == 0 {
+		return nil
+	}
+
+	bulkGroupID, err := store.RandomID()
+	if err != nil {
+		return errors.Wrap(err, "creating bulkGroupID failed")
+	}
+
+	jobs := make([]*btypes.ChangesetJob, 0, len(cs))
+	for _, c := range cs {
+		jobs = append(jobs, &btypes.ChangesetJob{
+			BulkGroup:     bulkGroupID,
+			ChangesetID:   c.ID,
+			BatchChangeID: bc.ID,
+			UserID:        bc.LastApplierID,
+			State:         btypes.ChangesetJobStateQueued,
+			JobType:       btypes.ChangesetJobTypeMerge,
+			Payload:       mergePayloadFor(autoMerge.Strategy, c.ExternalServiceType),
+		})
+	}
+
+	return m.store.CreateChangesetJob(ctx, jobs...)
+}
+
+// listChangesetsToMerge pages through the changesets listed by list until
+// limit changesets on which the required checks have passed are found, or
+// lists all of them if limit is 0. Changesets whose required checks haven't
+// passed are skipped before the limit is applied, so that they don't take up
+// the hourly quota of changesets that can be merged.
+func listChangesetsToMerge(
+	ctx context.Context,
+	list func(context.Context, store.ListAutoMergeableChangesetsOpts) (btypes.Changesets, error),
+	opts store.ListAutoMergeableChangesetsOpts,
+	requiredChecks []string,
+	limit int,
+) (btypes.Changesets, error) {
+	opts.Limit = limit
+
+	var cs btypes.Changesets
+	for {
+		page, err := list(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return cs, nil
+		}
+		last := page[len(page)-1].ID
+
+		cs = append(cs, withRequiredChecksPassed(page, requiredChecks)...)
+		if limit > 0 && len(cs) >= limit {
+			return cs[:limit], nil
+		}
+		if limit == 0 || len(page) < limit {
+			return cs, nil
+		}
+		opts.Cursor = last + 1
+	}
+}
+
+// withRequiredChecksPassed returns the changesets on which all of the given
+// required checks have passed.
+func withRequiredChecksPassed(cs btypes.Changesets, required []string) btypes.Changesets {
+	if len(required) == 0 {
+		return cs
+	}
+
+	filtered := cs[:0]
+	for _, c := range cs {
+		if len(c.RequiredChecksNotPassed(required)) == 0 {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// mergePayloadFor returns the merge payload for a changeset on the given
+// code host type, based on the configured strategy.
+func mergePayloadFor(strategy batcheslib.AutoMergeStrategy, externalServiceType string) *btypes.ChangesetJobMergePayload {
+	var s batcheslib.MergeStrategy
+	switch externalServiceType {
+	case extsvc.TypeGitHub:
+		s = strategy.GitHub
+	case extsvc.TypeGitLab:
+		s = strategy.GitLab
+	case extsvc.TypeBitbucketServer:
+		s = strategy.BitbucketServer
+	}
+
+	return &btypes.ChangesetJobMergePayload{
+		Squash: s == batcheslib.MergeStrategySquash,
+		Rebase: s == batcheslib.MergeStrategyRebase,
+	}
+}
@@ -0,0 +1,107 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestMergePayloadFor(t *testing.T) {
+	strategy := batcheslib.AutoMergeStrategy{
+		GitHub: batcheslib.MergeStrategyRebase,
+		GitLab: batcheslib.MergeStrategySquash,
+	}
+
+	for typ, want := range map[string]*btypes.ChangesetJobMergePayload{
+		extsvc.TypeGitHub:          {Rebase: true},
+		extsvc.TypeGitLab:          {Squash: true},
+		extsvc.TypeBitbucketServer: {},
+	} {
+		t.Run(typ, func(t *testing.T) {
+			have := mergePayloadFor(strategy, typ)
+			if diff := cmp.Diff(want, have); diff != "" {
+				t.Fatalf("wrong payload (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithRequiredChecksPassed(t *testing.T) {
+	passed := &btypes.Changeset{ID: 1, ExternalChecks: []btypes.ChangesetCheck{
+		{Name: "build", State: btypes.ChangesetCheckStatePassed},
+		{Name: "lint", State: btypes.ChangesetCheckStatePassed},
+	}}
+	failed := &btypes.Changeset{ID: 2, ExternalChecks: []btypes.ChangesetCheck{
+		{Name: "build", State: btypes.ChangesetCheckStatePassed},
+		{Name: "lint", State: btypes.ChangesetCheckStateFailed},
+	}}
+	missing := &btypes.Changeset{ID: 3, ExternalChecks: []btypes.ChangesetCheck{
+		{Name: "build", State: btypes.ChangesetCheckStatePassed},
+	}}
+
+	t.Run("no required checks", func(t *testing.T) {
+		have := withRequiredChecksPassed(btypes.Changesets{passed, failed, missing}, nil)
+		if len(have) != 3 {
+			t.Fatalf("wrong number of changesets. want=3, have=%d", len(have))
+		}
+	})
+
+	t.Run("required checks", func(t *testing.T) {
+		have := withRequiredChecksPassed(btypes.Changesets{passed, failed, missing}, []string{"build", "lint"})
+		if diff := cmp.Diff(btypes.Changesets{passed}, have); diff != "" {
+			t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+		}
+	})
+}
+
+func TestListChangesetsToMerge(t *testing.T) {
+	changeset := func(id int64, lint btypes.ChangesetCheckState) *btypes.Changeset {
+		return &btypes.Changeset{ID: id, ExternalChecks: []btypes.ChangesetCheck{{Name: "lint", State: lint}}}
+	}
+	all := btypes.Changesets{
+		changeset(1, btypes.ChangesetCheckStateFailed),
+		changeset(2, btypes.ChangesetCheckStateFailed),
+		changeset(3, btypes.ChangesetCheckStatePassed),
+		changeset(4, btypes.ChangesetCheckStateFailed),
+		changeset(5, btypes.ChangesetCheckStatePassed),
+		changeset(6, btypes.ChangesetCheckStatePassed),
+	}
+
+	list := func(ctx context.Context, opts store.ListAutoMergeableChangesetsOpts) (btypes.Changesets, error) {
+		var page btypes.Changesets
+		for _, c := range all {
+			if c.ID >= opts.Cursor && (opts.Limit == 0 || len(page) < opts.Limit) {
+				page = append(page, c)
+			}
+		}
+		return page, nil
+	}
+
+	for _, tc := range []struct {
+		name           string
+		requiredChecks []string
+		limit          int
+		want           []int64
+	}{
+		{name: "no limit", requiredChecks: []string{"lint"}, want: []int64{3, 5, 6}},
+		{name: "failing checks don't take up the limit", requiredChecks: []string{"lint"}, limit: 2, want: []int64{3, 5}},
+		{name: "fewer passing than limit", requiredChecks: []string{"lint"}, limit: 5, want: []int64{3, 5, 6}},
+		{name: "no required checks", limit: 2, want: []int64{1, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := listChangesetsToMerge(context.Background(), list, store.ListAutoMergeableChangesetsOpts{}, tc.requiredChecks, tc.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have.IDs()); diff != "" {
+				t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 	reconcilerWorkerStore := NewReconcilerDBWorkerStore(batchesStore.Handle(), observationContext)
 	bulkProcessorWorkerStore := NewBulkOperationDBWorkerStore(batchesStore.Handle(), observationContext)
 	specExecutionWorkerStore := NewExecutorStore(batchesStore.Handle(), observationContext)
+	batchSpecResolutionWorkerStore := NewBatchSpecResolutionDBWorkerStore(batchesStore.Handle(), observationContext)
+	batchSpecWorkspaceExecutionWorkerStore := NewBatchSpecWorkspaceExecutorStore(batchesStore.Handle(), observationContext)
 
 	routines := []goroutine.BackgroundRoutine{
 		newReconcilerWorker(ctx, batchesStore, reconcilerWorkerStore, gitserver.DefaultClient, sourcer, metrics),
@@ -26,12 +28,20 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 
 		newSpecExpireJob(ctx, batchesStore),
 
+		newAutoMergeJob(ctx, batchesStore),
+		newRebaseJob(ctx, batchesStore),
+
 		scheduler.NewScheduler(ctx, batchesStore),
 
 		newBulkOperationWorker(ctx, batchesStore, bulkProcessorWorkerStore, sourcer, metrics),
 		newBulkOperationWorkerResetter(bulkProcessorWorkerStore, metrics),
 
 		newBatchSpecExecutionResetter(specExecutionWorkerStore, metrics),
+
+		newBatchSpecResolutionWorker(ctx, batchesStore, batchSpecResolutionWorkerStore, metrics),
+		newBatchSpecResolutionWorkerResetter(batchSpecResolutionWorkerStore, metrics),
+
+		newBatchSpecWorkspaceExecutionResetter(batchSpecWorkspaceExecutionWorkerStore, metrics),
 	}
 	return routines
 }
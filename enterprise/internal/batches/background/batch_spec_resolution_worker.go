@@ -0,0 +1,168 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// batchSpecResolutionMaxNumRetries is the maximum number of attempts the
+// resolution worker makes to resolve the workspaces of a batch spec when it
+// fails.
+const batchSpecResolutionMaxNumRetries = 3
+
+// batchSpecResolutionMaxNumResets is the maximum number of attempts the
+// resolution worker makes to resolve the workspaces of a batch spec when it
+// stalls (process crashes, etc.).
+const batchSpecResolutionMaxNumResets = 60
+
+// newBatchSpecResolutionWorker creates a dbworker.Worker that fetches enqueued
+// batch_spec_resolution_jobs from the database and resolves the workspaces of
+// the batch spec they point to.
+func newBatchSpecResolutionWorker(
+	ctx context.Context,
+	s *store.Store,
+	workerStore dbworkerstore.Store,
+	metrics batchChangesMetrics,
+) *workerutil.Worker {
+	e := &batchSpecWorkspaceCreator{store: s}
+
+	options := workerutil.WorkerOptions{
+		Name:              "batch_changes_batch_spec_resolution_worker",
+		NumHandlers:       5,
+		Interval:          1 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics.batchSpecResolutionWorkerMetrics,
+	}
+
+	worker := dbworker.NewWorker(ctx, workerStore, e.HandlerFunc(), options)
+	return worker
+}
+
+// newBatchSpecResolutionWorkerResetter creates a dbworker.Resetter that
+// re-enqueues lost batch_spec_resolution_jobs for processing.
+func newBatchSpecResolutionWorkerResetter(workerStore dbworkerstore.Store, metrics batchChangesMetrics) *dbworker.Resetter {
+	options := dbworker.ResetterOptions{
+		Name:     "batch_changes_batch_spec_resolution_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics.batchSpecResolutionWorkerResetterMetrics,
+	}
+
+	resetter := dbworker.NewResetter(workerStore, options)
+	return resetter
+}
+
+func NewBatchSpecResolutionDBWorkerStore(handle *basestore.TransactableHandle, observationContext *observation.Context) dbworkerstore.Store {
+	options := dbworkerstore.Options{
+		Name:              "batch_changes_batch_spec_resolution_worker_store",
+		TableName:         "batch_spec_resolution_jobs",
+		ColumnExpressions: store.BatchSpecResolutionJobColumns.ToSqlf(),
+		Scan:              scanFirstBatchSpecResolutionJobRecord,
+
+		OrderByExpression: sqlf.Sprintf("batch_spec_resolution_jobs.state = 'errored', batch_spec_resolution_jobs.updated_at DESC"),
+
+		StalledMaxAge: 60 * time.Second,
+		MaxNumResets:  batchSpecResolutionMaxNumResets,
+
+		RetryAfter:    5 * time.Second,
+		MaxNumRetries: batchSpecResolutionMaxNumRetries,
+	}
+
+	return dbworkerstore.NewWithMetrics(handle, options, observationContext)
+}
+
+// scanFirstBatchSpecResolutionJobRecord wraps
+// store.ScanFirstBatchSpecResolutionJob to return a generic workerutil.Record.
+func scanFirstBatchSpecResolutionJobRecord(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	return store.ScanFirstBatchSpecResolutionJob(rows, err)
+}
+
+// batchSpecWorkspaceCreator resolves the workspaces of a batch spec, persists
+// them as batch_spec_workspaces and enqueues an execution job for each of
+// them.
+type batchSpecWorkspaceCreator struct {
+	store *store.Store
+}
+
+func (r *batchSpecWorkspaceCreator) HandlerFunc() workerutil.HandlerFunc {
+	return func(ctx context.Context, record workerutil.Record) (err error) {
+		job := record.(*btypes.BatchSpecResolutionJob)
+
+		return r.process(ctx, service.NewWorkspaceResolver, job)
+	}
+}
+
+func (r *batchSpecWorkspaceCreator) process(ctx context.Context, newResolver service.WorkspaceResolverBuilder, job *btypes.BatchSpecResolutionJob) (err error) {
+	spec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: job.BatchSpecID})
+	if err != nil {
+		return err
+	}
+
+	if spec.UserID == 0 {
+		return errors.Newf("batch spec %d has no creator", spec.ID)
+	}
+
+	// 🚨 SECURITY: The workspaces are resolved on behalf of the user that
+	// created the batch spec, so that only repositories that they have access
+	// to are included.
+	ctx = actor.WithActor(ctx, actor.FromUser(spec.UserID))
+
+	// Resolving the workspaces runs searches and talks to gitserver, which can
+	// take a long time, so it happens before the transaction is opened.
+	workspaces, err := newResolver(r.store).ResolveWorkspacesForBatchSpec(ctx, spec.Spec, service.ResolveWorkspacesForBatchSpecOpts{
+		AllowUnsupported: job.AllowUnsupported,
+		AllowIgnored:     job.AllowIgnored,
+	})
+	if err != nil {
+		return err
+	}
+
+	ws := make([]*btypes.BatchSpecWorkspace, 0, len(workspaces))
+	for _, w := range workspaces {
+		ws = append(ws, &btypes.BatchSpecWorkspace{
+			BatchSpecID:        spec.ID,
+			ChangesetSpecIDs:   []int64{},
+			RepoID:             w.Repo.ID,
+			Branch:             w.Branch,
+			Commit:             string(w.Commit),
+			Path:               w.Path,
+			FileMatches:        w.FileMatches,
+			OnlyFetchWorkspace: w.OnlyFetchWorkspace,
+			Steps:              w.Steps,
+		})
+	}
+
+	if len(ws) == 0 {
+		return nil
+	}
+
+	tx, err := r.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.CreateBatchSpecWorkspace(ctx, ws...); err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(ws))
+	for _, w := range ws {
+		ids = append(ids, w.ID)
+	}
+
+	return tx.CreateBatchSpecWorkspaceExecutionJobs(ctx, ids...)
+}
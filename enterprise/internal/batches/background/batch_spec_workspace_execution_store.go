@@ -0,0 +1,263 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+var batchSpecWorkspaceExecutionWorkerStoreOptions = dbworkerstore.Options{
+	Name:              "batch_spec_workspace_execution_worker_store",
+	TableName:         "batch_spec_workspace_execution_jobs",
+	ColumnExpressions: store.BatchSpecWorkspaceExecutionJobColumns.ToSqlf(),
+	Scan:              scanFirstBatchSpecWorkspaceExecutionJobRecord,
+	OrderByExpression: sqlf.Sprintf("batch_spec_workspace_execution_jobs.created_at, batch_spec_workspace_execution_jobs.id"),
+	StalledMaxAge:     executorStalledJobMaximumAge,
+	MaxNumResets:      executorMaximumNumResets,
+	// Explicitly disable retries.
+	MaxNumRetries: 0,
+}
+
+// NewBatchSpecWorkspaceExecutorStore creates a dbworker store that wraps the
+// batch_spec_workspace_execution_jobs table.
+func NewBatchSpecWorkspaceExecutorStore(handle *basestore.TransactableHandle, observationContext *observation.Context) dbworkerstore.Store {
+	return &batchSpecWorkspaceExecutorStore{
+		Store:              dbworkerstore.NewWithMetrics(handle, batchSpecWorkspaceExecutionWorkerStoreOptions, observationContext),
+		observationContext: observationContext,
+	}
+}
+
+var _ dbworkerstore.Store = &batchSpecWorkspaceExecutorStore{}
+
+// batchSpecWorkspaceExecutorStore is a thin wrapper around
+// dbworkerstore.Store that extracts the changeset specs created by src-cli
+// out of the ExecutionLogEntry field and attaches them to the batch spec and
+// the workspace when marking a job as complete.
+type batchSpecWorkspaceExecutorStore struct {
+	dbworkerstore.Store
+
+	observationContext *observation.Context
+}
+
+// markBatchSpecWorkspaceExecutionJobCompleteQuery is taken from
+// internal/workerutil/dbworker/store/store.go
+//
+// If that one changes we need to update this one here too.
+const markBatchSpecWorkspaceExecutionJobCompleteQuery = `
+UPDATE batch_spec_workspace_execution_jobs
+SET state = 'completed', finished_at = clock_timestamp()
+WHERE id = %s AND state = 'processing' AND worker_hostname = %s
+RETURNING id
+`
+
+func (s *batchSpecWorkspaceExecutorStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (_ bool, err error) {
+	batchesStore := store.New(s.Store.Handle().DB(), s.observationContext, nil)
+
+	// The job is marked as complete and its changeset specs are attached in the
+	// same transaction, so that a completed job always has its changeset specs
+	// attached, and a job which is no longer processed by this worker doesn't
+	// attach any.
+	tx, err := batchesStore.Transact(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(markBatchSpecWorkspaceExecutionJobCompleteQuery, id, options.WorkerHostname)))
+	if err != nil || !ok {
+		return false, tx.Done(err)
+	}
+
+	if err := attachChangesetSpecs(ctx, tx, int64(id)); err != nil {
+		// If we couldn't attach the changeset specs, we roll back and mark the
+		// job as failed.
+		if rollbackErr := tx.Done(err); rollbackErr != err {
+			return false, rollbackErr
+		}
+		return s.Store.MarkFailed(ctx, id, fmt.Sprintf("failed to attach changeset specs: %s", err), options)
+	}
+
+	return true, tx.Done(nil)
+}
+
+// attachChangesetSpecs extracts the changeset specs that were uploaded by
+// the given job from its execution logs, and links them to the batch spec
+// and the workspace the job belongs to. It must be called in a transaction.
+func attachChangesetSpecs(ctx context.Context, tx *store.Store, jobID int64) error {
+	job, err := tx.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{ID: jobID})
+	if err != nil {
+		return err
+	}
+
+	workspace, err := tx.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: job.BatchSpecWorkspaceID})
+	if err != nil {
+		return err
+	}
+
+	spec, err := tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return err
+	}
+
+	randIDs, err := extractChangesetSpecRandIDs(job.ExecutionLogs)
+	if err != nil {
+		return err
+	}
+
+	// The workspace didn't produce any changes.
+	if len(randIDs) == 0 {
+		return tx.SetBatchSpecWorkspaceChangesetSpecs(ctx, workspace.ID, []int64{})
+	}
+
+	specs, _, err := tx.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{RandIDs: randIDs})
+	if err != nil {
+		return err
+	}
+	if len(specs) != len(randIDs) {
+		return errors.Newf("found %d changeset specs, expected %d", len(specs), len(randIDs))
+	}
+
+	ids := make([]int64, 0, len(specs))
+	for _, c := range specs {
+		// 🚨 SECURITY: Only changeset specs that were created by the user who
+		// created the batch spec can be attached to it.
+		if c.UserID != spec.UserID {
+			return errors.Newf("changeset spec %d was not created by the batch spec user", c.ID)
+		}
+
+		c.BatchSpecID = spec.ID
+		if err := tx.UpdateChangesetSpec(ctx, c); err != nil {
+			return err
+		}
+		ids = append(ids, c.ID)
+	}
+
+	// If the workspace was executed again because the base branch of its
+	// changesets moved, the new changeset specs replace the ones produced by
+	// the previous execution.
+	if len(workspace.ChangesetSpecIDs) > 0 {
+		if err := supersedeChangesetSpecs(ctx, tx, workspace.ChangesetSpecIDs, specs); err != nil {
+			return err
+		}
+	}
+
+	return tx.SetBatchSpecWorkspaceChangesetSpecs(ctx, workspace.ID, ids)
+}
+
+// supersedeChangesetSpecs matches the given new changeset specs to the
+// previous changeset specs of a workspace by their head ref, and makes them
+// the current specs of the changesets that were created from the previous
+// ones.
+func supersedeChangesetSpecs(ctx context.Context, tx *store.Store, previousIDs []int64, specs btypes.ChangesetSpecs) error {
+	previous, _, err := tx.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{IDs: previousIDs})
+	if err != nil {
+		return err
+	}
+
+	byHeadRef := make(map[string]int64, len(previous))
+	for _, p := range previous {
+		if p.Spec.IsImportingExisting() {
+			continue
+		}
+		byHeadRef[p.Spec.HeadRef] = p.ID
+	}
+
+	for _, c := range specs {
+		if c.Spec.IsImportingExisting() {
+			continue
+		}
+		previousID, ok := byHeadRef[c.Spec.HeadRef]
+		if !ok {
+			continue
+		}
+		if err := tx.SupersedeChangesetSpec(ctx, previousID, c.ID, global.DefaultReconcilerEnqueueState()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var ErrNoChangesetSpecIDs = errors.New("no changeset ids found in execution logs")
+
+func extractChangesetSpecRandIDs(logs []workerutil.ExecutionLogEntry) ([]string, error) {
+	var (
+		entry workerutil.ExecutionLogEntry
+		found bool
+	)
+
+	for _, e := range logs {
+		if e.Key == "step.src.0" {
+			entry = e
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrNoChangesetSpecIDs
+	}
+
+	for _, l := range strings.Split(entry.Out, "\n") {
+		const outputLinePrefix = "stdout: "
+
+		if !strings.HasPrefix(l, outputLinePrefix) {
+			continue
+		}
+
+		jsonPart := l[len(outputLinePrefix):]
+
+		var e srcCLILogLine
+		if err := json.Unmarshal([]byte(jsonPart), &e); err != nil {
+			// If we can't unmarshal the line as JSON we skip it
+			continue
+		}
+
+		if e.Operation == operationUploadingChangesetSpecs && e.Status == "SUCCESS" {
+			rawIDs, ok := e.Metadata["ids"].([]interface{})
+			if !ok {
+				return nil, ErrNoChangesetSpecIDs
+			}
+
+			randIDs := make([]string, 0, len(rawIDs))
+			for _, raw := range rawIDs {
+				id, ok := raw.(string)
+				if !ok {
+					return nil, ErrNoChangesetSpecIDs
+				}
+
+				var randID string
+				if err := relay.UnmarshalSpec(graphql.ID(id), &randID); err != nil {
+					// If we can't extract the ID we simply return our main error
+					return nil, ErrNoChangesetSpecIDs
+				}
+				randIDs = append(randIDs, randID)
+			}
+
+			return randIDs, nil
+		}
+	}
+
+	return nil, ErrNoChangesetSpecIDs
+}
+
+const operationUploadingChangesetSpecs = "UPLOADING_CHANGESET_SPECS"
+
+// scanFirstBatchSpecWorkspaceExecutionJobRecord scans a slice of batch spec
+// workspace execution jobs and returns the first.
+func scanFirstBatchSpecWorkspaceExecutionJobRecord(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	return store.ScanFirstBatchSpecWorkspaceExecutionJob(rows, err)
+}
@@ -0,0 +1,215 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func TestExtractChangesetSpecRandIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "success",
+			out: `stdout: {"operation":"PREPARING_DOCKER_IMAGES","timestamp":"2021-07-06T09:38:51.481Z","status":"SUCCESS"}
+stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-07-06T09:38:51.528Z","status":"STARTED"}
+stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-07-06T09:38:51.535Z","status":"SUCCESS","metadata":{"ids":["Q2hhbmdlc2V0U3BlYzoiNkxIYWN5dkI3WDYi","Q2hhbmdlc2V0U3BlYzoiNkxIYWN5dkI3WDci"]}}
+`,
+			want: []string{"6LHacyvB7X6", "6LHacyvB7X7"},
+		},
+		{
+			name: "no changes",
+			out: `stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-07-06T09:38:51.535Z","status":"SUCCESS","metadata":{"ids":[]}}
+`,
+			want: []string{},
+		},
+		{
+			name: "no upload",
+			out: `stdout: {"operation":"PREPARING_DOCKER_IMAGES","timestamp":"2021-07-06T09:38:51.481Z","status":"SUCCESS"}
+`,
+			wantErr: ErrNoChangesetSpecIDs,
+		},
+		{
+			name: "invalid id",
+			out: `stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-07-06T09:38:51.535Z","status":"SUCCESS","metadata":{"ids":["horse"]}}
+`,
+			wantErr: ErrNoChangesetSpecIDs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := []workerutil.ExecutionLogEntry{
+				{
+					Key:        "step.src.0",
+					Command:    []string{"src", "batch", "exec", "-f", "input.json", "-text-only"},
+					StartTime:  time.Now().Add(-5 * time.Second),
+					Out:        tt.out,
+					DurationMs: intptr(200),
+				},
+			}
+
+			have, err := extractChangesetSpecRandIDs(logs)
+			if err != tt.wantErr {
+				t.Fatalf("wrong error. want=%v, have=%v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, have); diff != "" {
+				t.Fatalf("wrong rand ids extracted (-want +have):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("without log entry", func(t *testing.T) {
+		if _, err := extractChangesetSpecRandIDs(nil); err != ErrNoChangesetSpecIDs {
+			t.Fatalf("wrong error. want=%v, have=%v", ErrNoChangesetSpecIDs, err)
+		}
+	})
+}
+
+func TestBatchSpecWorkspaceExecutorStore_MarkComplete(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	user := ct.CreateTestUser(t, db, true)
+	otherUser := ct.CreateTestUser(t, db, false)
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 1)
+
+	s := store.New(db, &observation.TestContext, nil)
+	workStore := NewBatchSpecWorkspaceExecutorStore(s.Handle(), &observation.TestContext)
+
+	const workerHostname = "worker-1"
+
+	// setup creates a job which is processed by workerHostname, and which
+	// uploaded a changeset spec created by changesetSpecUser.
+	setup := func(t *testing.T, changesetSpecUser int32) (*btypes.BatchSpec, *btypes.BatchSpecWorkspaceExecutionJob, *btypes.ChangesetSpec) {
+		t.Helper()
+
+		batchSpec := ct.CreateBatchSpec(t, ctx, s, "mark-complete", user.ID)
+		workspace := &btypes.BatchSpecWorkspace{
+			BatchSpecID: batchSpec.ID,
+			RepoID:      repos[0].ID,
+			Branch:      "refs/heads/main",
+			Commit:      "d34db33f",
+			FileMatches: []string{},
+		}
+		if err := s.CreateBatchSpecWorkspace(ctx, workspace); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CreateBatchSpecWorkspaceExecutionJobs(ctx, workspace.ID); err != nil {
+			t.Fatal(err)
+		}
+		job, err := s.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{BatchSpecWorkspaceID: workspace.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_workspace_execution_jobs SET state = 'processing', worker_hostname = %s WHERE id = %s", workerHostname, job.ID)); err != nil {
+			t.Fatal(err)
+		}
+
+		changesetSpec := ct.CreateChangesetSpec(t, ctx, s, ct.TestSpecOpts{
+			User:    changesetSpecUser,
+			Repo:    repos[0].ID,
+			HeadRef: "refs/heads/my-branch",
+		})
+
+		entry := workerutil.ExecutionLogEntry{
+			Key:        "step.src.0",
+			Command:    []string{"src", "batch", "exec", "-f", "input.json", "-text-only"},
+			StartTime:  time.Now().Add(-5 * time.Second),
+			Out:        `stdout: {"operation":"UPLOADING_CHANGESET_SPECS","timestamp":"2021-07-06T09:38:51.535Z","status":"SUCCESS","metadata":{"ids":["` + string(relay.MarshalID("ChangesetSpec", changesetSpec.RandID)) + `"]}}` + "\n",
+			DurationMs: intptr(200),
+		}
+		if _, err := workStore.AddExecutionLogEntry(ctx, int(job.ID), entry, dbworkerstore.ExecutionLogEntryOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		return batchSpec, job, changesetSpec
+	}
+
+	// assertState asserts the state of the given job, and the batch spec the
+	// given changeset spec is attached to.
+	assertState := func(t *testing.T, job *btypes.BatchSpecWorkspaceExecutionJob, wantState btypes.BatchSpecWorkspaceExecutionJobState, changesetSpec *btypes.ChangesetSpec, wantBatchSpecID int64) {
+		t.Helper()
+
+		job, err := s.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{ID: job.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.State != wantState {
+			t.Errorf("wrong job state. want=%s, have=%s", wantState, job.State)
+		}
+
+		workspace, err := s.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: job.BatchSpecWorkspaceID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantChangesetSpecIDs := []int64{}
+		if wantBatchSpecID != 0 {
+			wantChangesetSpecIDs = append(wantChangesetSpecIDs, changesetSpec.ID)
+		}
+		if diff := cmp.Diff(wantChangesetSpecIDs, workspace.ChangesetSpecIDs); diff != "" {
+			t.Errorf("wrong workspace changeset specs (-want +have):\n%s", diff)
+		}
+
+		changesetSpec, err = s.GetChangesetSpec(ctx, store.GetChangesetSpecOpts{ID: changesetSpec.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changesetSpec.BatchSpecID != wantBatchSpecID {
+			t.Errorf("wrong changeset spec batch spec. want=%d, have=%d", wantBatchSpecID, changesetSpec.BatchSpecID)
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		batchSpec, job, changesetSpec := setup(t, user.ID)
+
+		ok, err := workStore.MarkComplete(ctx, int(job.ID), dbworkerstore.MarkFinalOptions{WorkerHostname: workerHostname})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("job was not marked as complete")
+		}
+
+		assertState(t, job, btypes.BatchSpecWorkspaceExecutionJobStateCompleted, changesetSpec, batchSpec.ID)
+	})
+
+	t.Run("changeset spec of another user", func(t *testing.T) {
+		_, job, changesetSpec := setup(t, otherUser.ID)
+
+		if _, err := workStore.MarkComplete(ctx, int(job.ID), dbworkerstore.MarkFinalOptions{WorkerHostname: workerHostname}); err != nil {
+			t.Fatal(err)
+		}
+
+		assertState(t, job, btypes.BatchSpecWorkspaceExecutionJobStateFailed, changesetSpec, 0)
+	})
+
+	t.Run("job processed by another worker", func(t *testing.T) {
+		_, job, changesetSpec := setup(t, user.ID)
+
+		ok, err := workStore.MarkComplete(ctx, int(job.ID), dbworkerstore.MarkFinalOptions{WorkerHostname: "worker-2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("job was marked as complete")
+		}
+
+		assertState(t, job, btypes.BatchSpecWorkspaceExecutionJobStateProcessing, changesetSpec, 0)
+	})
+}
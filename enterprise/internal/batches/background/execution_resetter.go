@@ -19,3 +19,16 @@ func newBatchSpecExecutionResetter(workerStore dbworkerstore.Store, metrics batc
 	resetter := dbworker.NewResetter(workerStore, options)
 	return resetter
 }
+
+// newBatchSpecWorkspaceExecutionResetter creates a dbworker.Resetter that
+// re-enqueues lost batch_spec_workspace_execution_jobs for processing.
+func newBatchSpecWorkspaceExecutionResetter(workerStore dbworkerstore.Store, metrics batchChangesMetrics) *dbworker.Resetter {
+	options := dbworker.ResetterOptions{
+		Name:     "batch_spec_workspace_executor_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics.batchSpecWorkspaceExecutionResetterMetrics,
+	}
+
+	resetter := dbworker.NewResetter(workerStore, options)
+	return resetter
+}
@@ -188,6 +188,8 @@ type srcCLILogLine struct {
 
 	Status  string `json:"status"`            // "STARTED", "PROGRESS", "SUCCESS", "FAILURE"
 	Message string `json:"message,omitempty"` // "70% done"
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 const operationCreatingBatchSpec = "CREATING_BATCH_SPEC"
@@ -0,0 +1,135 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+const rebaseInterval = 5 * time.Minute
+
+func newRebaseJob(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	r := &rebaser{store: s}
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		rebaseInterval,
+		goroutine.NewHandlerWithErrorMessage("rebase batch changes changesets", r.run),
+	)
+}
+
+// rebaser enqueues the changesets of batch changes that have
+// rerunOnBaseChange configured in their batch spec for reconciliation, once
+// the base branch of the changeset moved. The reconciler then plans and
+// executes the rebase.
+type rebaser struct {
+	store *store.Store
+}
+
+func (r *rebaser) run(ctx context.Context) error {
+	batchChanges, _, err := r.store.ListBatchChanges(ctx, store.ListBatchChangesOpts{
+		State:                 btypes.BatchChangeStateOpen,
+		OnlyRerunOnBaseChange: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing batch changes")
+	}
+
+	var errs *multierror.Error
+	for _, bc := range batchChanges {
+		if err := r.enqueueRebases(ctx, bc); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "batch change %d", bc.ID))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (r *rebaser) enqueueRebases(ctx context.Context, bc *btypes.BatchChange) error {
+	spec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: bc.BatchSpecID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch spec")
+	}
+	mode := spec.Spec.RerunOnBaseChange
+	if mode == "" {
+		return nil
+	}
+
+	published := btypes.ChangesetPublicationStatePublished
+	cs, _, err := r.store.ListChangesets(ctx, store.ListChangesetsOpts{
+		OwnedByBatchChangeID: bc.ID,
+		PublicationState:     &published,
+		ReconcilerStates:     []btypes.ReconcilerState{btypes.ReconcilerStateCompleted},
+		ExternalStates: []btypes.ChangesetExternalState{
+			btypes.ChangesetExternalStateOpen,
+			btypes.ChangesetExternalStateDraft,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+	if len(cs) == 0 {
+		return nil
+	}
+
+	specIDs := make([]int64, 0, len(cs))
+	for _, c := range cs {
+		if c.CurrentSpecID != 0 {
+			specIDs = append(specIDs, c.CurrentSpecID)
+		}
+	}
+	specs, _, err := r.store.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{IDs: specIDs})
+	if err != nil {
+		return errors.Wrap(err, "listing changeset specs")
+	}
+	specsByID := make(map[int64]*btypes.ChangesetSpec, len(specs))
+	for _, s := range specs {
+		specsByID[s.ID] = s
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(cs))
+	for _, c := range cs {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	reposByID, err := r.store.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return errors.Wrap(err, "loading repositories")
+	}
+
+	var errs *multierror.Error
+	for _, c := range cs {
+		s, ok := specsByID[c.CurrentSpecID]
+		if !ok || !c.RebaseWanted(mode) {
+			continue
+		}
+		repo, ok := reposByID[c.RepoID]
+		if !ok {
+			continue
+		}
+
+		// The reconciler only rebases once the base branch moved, so we
+		// don't enqueue the changeset before that either.
+		currentBaseRev, err := reconciler.CurrentBaseRev(ctx, repo.Name, c, s)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "changeset %d", c.ID))
+			continue
+		}
+		if !c.NeedsRebase(mode, s.Spec.BaseRev, currentBaseRev) {
+			continue
+		}
+		if err := r.store.EnqueueChangeset(ctx, c, global.DefaultReconcilerEnqueueState(), btypes.ReconcilerStateCompleted); err != nil {
+			return errors.Wrapf(err, "enqueueing changeset %d", c.ID)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
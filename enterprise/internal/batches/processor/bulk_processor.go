@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
@@ -161,11 +162,23 @@ func (b *bulkProcessor) mergeChangeset(ctx context.Context, job *btypes.Changese
 		return errors.Errorf("invalid payload type for changeset_job, want=%T have=%T", &btypes.ChangesetJobMergePayload{}, job.Payload)
 	}
 
+	if err := b.checkRequiredChecks(ctx, job); err != nil {
+		return err
+	}
+
 	cs := &sources.Changeset{
 		Changeset: b.ch,
 		Repo:      b.repo,
 	}
-	if err := b.css.MergeChangeset(ctx, cs, typedPayload.Squash); err != nil {
+	if typedPayload.Rebase {
+		rebaser, ok := b.css.(sources.RebaseMerger)
+		if !ok {
+			return errcode.MakeNonRetryable(errors.Errorf("rebase merges are not supported on %s", b.ch.ExternalServiceType))
+		}
+		if err := rebaser.RebaseMergeChangeset(ctx, cs); err != nil {
+			return err
+		}
+	} else if err := b.css.MergeChangeset(ctx, cs, typedPayload.Squash); err != nil {
 		return err
 	}
 
@@ -242,6 +255,14 @@ func (b *bulkProcessor) publishChangeset(ctx context.Context, job *btypes.Change
 		return errcode.MakeNonRetryable(errors.New("cannot publish a changeset that has a published value set in its changesetTemplate"))
 	}
 
+	// Undrafting a changeset marks it as ready for review, which is only done
+	// once its required checks have passed.
+	if !typedPayload.Draft && b.ch.ExternalState == btypes.ChangesetExternalStateDraft {
+		if err := b.checkRequiredChecks(ctx, job); err != nil {
+			return err
+		}
+	}
+
 	// Set the desired UI publication state.
 	if typedPayload.Draft {
 		b.ch.UiPublicationState = &btypes.ChangesetUiPublicationStateDraft
@@ -266,3 +287,28 @@ func (b *bulkProcessor) publishChangeset(ctx context.Context, job *btypes.Change
 
 	return nil
 }
+
+// checkRequiredChecks returns a non-retryable error if any of the checks
+// required by the batch spec of the job's batch change haven't passed on the
+// changeset.
+func (b *bulkProcessor) checkRequiredChecks(ctx context.Context, job *btypes.ChangesetJob) error {
+	batchChange, err := b.tx.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: job.BatchChangeID})
+	if err != nil {
+		return errors.Wrapf(err, "getting batch change %d", job.BatchChangeID)
+	}
+
+	batchSpec, err := b.tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return errors.Wrapf(err, "getting batch spec for batch change %d", batchChange.ID)
+	}
+
+	if batchSpec.Spec.ChangesetTemplate == nil {
+		return nil
+	}
+
+	if notPassed := b.ch.RequiredChecksNotPassed(batchSpec.Spec.ChangesetTemplate.RequiredChecks); len(notPassed) != 0 {
+		return errcode.MakeNonRetryable(errors.Errorf("required checks have not passed: %s", strings.Join(notPassed, ", ")))
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 func TestBulkProcessor(t *testing.T) {
@@ -177,10 +178,11 @@ func TestBulkProcessor(t *testing.T) {
 			sourcer: sources.NewFakeSourcer(nil, fake),
 		}
 		job := &types.ChangesetJob{
-			JobType:     types.ChangesetJobTypeMerge,
-			ChangesetID: changeset.ID,
-			UserID:      user.ID,
-			Payload:     &btypes.ChangesetJobMergePayload{},
+			JobType:       types.ChangesetJobTypeMerge,
+			ChangesetID:   changeset.ID,
+			UserID:        user.ID,
+			BatchChangeID: batchChange.ID,
+			Payload:       &btypes.ChangesetJobMergePayload{},
 		}
 		err := bp.Process(ctx, job)
 		if err != nil {
@@ -191,6 +193,110 @@ func TestBulkProcessor(t *testing.T) {
 		}
 	})
 
+	t.Run("Rebase merge job", func(t *testing.T) {
+		fake := &sources.FakeChangesetSource{}
+		bp := &bulkProcessor{
+			tx:      bstore,
+			sourcer: sources.NewFakeSourcer(nil, fake),
+		}
+		job := &types.ChangesetJob{
+			JobType:       types.ChangesetJobTypeMerge,
+			ChangesetID:   changeset.ID,
+			UserID:        user.ID,
+			BatchChangeID: batchChange.ID,
+			Payload:       &btypes.ChangesetJobMergePayload{Rebase: true},
+		}
+		err := bp.Process(ctx, job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fake.RebaseMergeChangesetCalled {
+			t.Fatal("expected RebaseMergeChangeset to be called but wasn't")
+		}
+		if fake.MergeChangesetCalled {
+			t.Fatal("expected MergeChangeset not to be called but was")
+		}
+	})
+
+	t.Run("Merge job with required checks", func(t *testing.T) {
+		spec := &types.BatchSpec{
+			UserID:          user.ID,
+			NamespaceUserID: user.ID,
+			Spec: &batcheslib.BatchSpec{
+				Name: "test-bulk-required-checks",
+				ChangesetTemplate: &batcheslib.ChangesetTemplate{
+					Branch:         "branch-name",
+					RequiredChecks: []string{"build", "lint"},
+				},
+			},
+		}
+		if err := bstore.CreateBatchSpec(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+		requiredChecksBatchChange := ct.CreateBatchChange(t, ctx, bstore, "test-bulk-required-checks", user.ID, spec.ID)
+
+		for name, tc := range map[string]struct {
+			checks  []types.ChangesetCheck
+			wantErr bool
+		}{
+			"not passed": {
+				checks: []types.ChangesetCheck{
+					{Name: "build", State: types.ChangesetCheckStatePassed},
+					{Name: "lint", State: types.ChangesetCheckStatePending},
+				},
+				wantErr: true,
+			},
+			"passed": {
+				checks: []types.ChangesetCheck{
+					{Name: "build", State: types.ChangesetCheckStatePassed},
+					{Name: "lint", State: types.ChangesetCheckStatePassed},
+				},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				c := ct.CreateChangeset(t, ctx, bstore, ct.TestChangesetOpts{
+					Repo:                repo.ID,
+					BatchChanges:        []types.BatchChangeAssoc{{BatchChangeID: requiredChecksBatchChange.ID}},
+					Metadata:            &github.PullRequest{},
+					ExternalServiceType: extsvc.TypeGitHub,
+					ExternalChecks:      tc.checks,
+				})
+
+				fake := &sources.FakeChangesetSource{}
+				bp := &bulkProcessor{
+					tx:      bstore,
+					sourcer: sources.NewFakeSourcer(nil, fake),
+				}
+				job := &types.ChangesetJob{
+					JobType:       types.ChangesetJobTypeMerge,
+					ChangesetID:   c.ID,
+					UserID:        user.ID,
+					BatchChangeID: requiredChecksBatchChange.ID,
+					Payload:       &btypes.ChangesetJobMergePayload{},
+				}
+				err := bp.Process(ctx, job)
+				if tc.wantErr {
+					if err == nil {
+						t.Fatal("unexpected nil error")
+					}
+					if !errcode.IsNonRetryable(err) {
+						t.Errorf("error is not non-retryable: %v", err)
+					}
+					if fake.MergeChangesetCalled {
+						t.Fatal("expected MergeChangeset not to be called but was")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !fake.MergeChangesetCalled {
+					t.Fatal("expected MergeChangeset to be called but wasn't")
+				}
+			})
+		}
+	})
+
 	t.Run("Close job", func(t *testing.T) {
 		fake := &sources.FakeChangesetSource{FakeMetadata: &github.PullRequest{}}
 		bp := &bulkProcessor{
@@ -2,6 +2,7 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -17,8 +18,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 // executePlan executes the given reconciler plan.
@@ -45,6 +48,10 @@ type executor struct {
 
 	css  sources.ChangesetSource
 	repo *types.Repo
+
+	// rebasedEvent is set by rebaseChangeset and recorded alongside the
+	// events of the changeset.
+	rebasedEvent *btypes.ChangesetRebasedEvent
 }
 
 func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
@@ -102,6 +109,9 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 		case btypes.ReconcilerOperationArchive:
 			e.archiveChangeset()
 
+		case btypes.ReconcilerOperationRebase:
+			err = e.rebaseChangeset(ctx)
+
 		default:
 			err = errors.Errorf("executor operation %q not implemented", op)
 		}
@@ -118,6 +128,15 @@ func (e *executor) Run(ctx context.Context, plan *Plan) (err error) {
 	}
 	state.SetDerivedState(ctx, e.tx.Repos(), e.ch, events)
 
+	if e.rebasedEvent != nil {
+		events = append(events, &btypes.ChangesetEvent{
+			ChangesetID: e.ch.ID,
+			Kind:        btypes.ChangesetEventKindBatchChangeRebased,
+			Key:         e.rebasedEvent.Key(),
+			Metadata:    e.rebasedEvent,
+		})
+	}
+
 	if err := e.tx.UpsertChangesetEvents(ctx, events...); err != nil {
 		log15.Error("UpsertChangesetEvents", "err", err)
 		return err
@@ -157,6 +176,147 @@ func (e *executor) pushChangesetPatch(ctx context.Context) (err error) {
 	return e.pushCommit(ctx, opts)
 }
 
+// rebaseChangeset rebases the changeset onto the current commit of its base
+// branch. If the changeset spec was produced by executing a batch spec on
+// Sourcegraph, the workspace it came from is re-executed against the new base
+// commit, which creates a new changeset spec once done. Otherwise the diff of
+// the changeset spec is re-applied on top of the new base commit and
+// force-pushed.
+func (e *executor) rebaseChangeset(ctx context.Context) (err error) {
+	baseRev, err := CurrentBaseRev(ctx, e.repo.Name, e.ch, e.spec)
+	if err != nil {
+		return err
+	}
+	if baseRev == e.spec.Spec.BaseRev {
+		// Nothing to rebase onto yet.
+		return nil
+	}
+
+	event := &btypes.ChangesetRebasedEvent{
+		PreviousBaseRev: e.spec.Spec.BaseRev,
+		BaseRev:         baseRev,
+		Conflict:        e.ch.HasMergeConflict(),
+		CreatedAt:       e.tx.Clock()(),
+	}
+
+	workspace, err := e.tx.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ChangesetSpecID: e.spec.ID})
+	if err != nil && err != store.ErrNoResults {
+		return errors.Wrap(err, "loading batch spec workspace")
+	}
+
+	if workspace != nil {
+		job, err := e.tx.GetBatchSpecWorkspaceExecutionJob(ctx, store.GetBatchSpecWorkspaceExecutionJobOpts{BatchSpecWorkspaceID: workspace.ID})
+		if err != nil && err != store.ErrNoResults {
+			return errors.Wrap(err, "loading batch spec workspace execution job")
+		}
+		if job != nil && (job.State == btypes.BatchSpecWorkspaceExecutionJobStateQueued || job.State == btypes.BatchSpecWorkspaceExecutionJobStateProcessing) {
+			// The workspace is already being re-executed.
+			return nil
+		}
+
+		if err := e.tx.SetBatchSpecWorkspaceCommit(ctx, workspace.ID, baseRev); err != nil {
+			return err
+		}
+		if err := e.tx.CreateBatchSpecWorkspaceExecutionJobs(ctx, workspace.ID); err != nil {
+			return err
+		}
+
+		event.Rerun = true
+		e.rebasedEvent = event
+		return nil
+	}
+
+	rebased, err := rebasedChangesetSpec(e.spec, baseRev)
+	if err != nil {
+		return err
+	}
+
+	pushConf, err := e.css.GitserverPushConfig(ctx, e.tx.ExternalServices(), e.repo)
+	if err != nil {
+		return err
+	}
+	opts, err := buildCommitOpts(e.repo, rebased, pushConf)
+	if err != nil {
+		return err
+	}
+	if err := e.pushCommit(ctx, opts); err != nil {
+		// If the diff doesn't apply to the new base commit anymore, retrying
+		// won't help: the batch spec needs to be executed again.
+		if errors.HasType(err, &protocol.CreateCommitFromPatchError{}) {
+			return errcode.MakeNonRetryable(errors.Wrap(err, "the changes no longer apply to the base branch, re-execute the batch spec to update them"))
+		}
+		return err
+	}
+
+	// The new changeset spec replaces the previous one in the batch spec, so
+	// that the batch spec describes what's been pushed to the code host.
+	if err := e.tx.CreateChangesetSpec(ctx, rebased); err != nil {
+		return err
+	}
+	e.spec.BatchSpecID = 0
+	if err := e.tx.UpdateChangesetSpec(ctx, e.spec); err != nil {
+		return err
+	}
+
+	e.ch.PreviousSpecID = e.ch.CurrentSpecID
+	e.ch.CurrentSpecID = rebased.ID
+	e.spec = rebased
+
+	e.rebasedEvent = event
+	return nil
+}
+
+// CurrentBaseRev returns the commit that the base branch of the changeset
+// created from the given changeset spec points to now, as far as gitserver
+// knows. It is called for every changeset that might need a rebase, so it
+// doesn't make gitserver fetch from the code host. A base branch which moved
+// since gitserver last updated the repository is picked up after the next
+// update.
+func CurrentBaseRev(ctx context.Context, repo api.RepoName, ch *btypes.Changeset, spec *btypes.ChangesetSpec) (string, error) {
+	opts := git.ResolveRevisionOptions{NoEnsureRevision: true}
+
+	// GitHub reports the commit the base branch points to, which might be
+	// newer than what gitserver has cloned so far. In that case we fall back
+	// to the base branch in gitserver.
+	if oid, _ := ch.BaseRefOid(); oid != "" && ch.ExternalServiceType == extsvc.TypeGitHub {
+		baseRev, err := git.ResolveRevision(ctx, repo, oid, opts)
+		if err == nil {
+			return string(baseRev), nil
+		}
+		if !errcode.IsNotFound(err) {
+			return "", errors.Wrap(err, "resolving base commit")
+		}
+	}
+
+	baseRev, err := git.ResolveRevision(ctx, repo, spec.Spec.BaseRef, opts)
+	if err != nil {
+		return "", errors.Wrap(err, "resolving base commit")
+	}
+	return string(baseRev), nil
+}
+
+// rebasedChangesetSpec returns a copy of the given changeset spec that applies
+// its changes to baseRev.
+func rebasedChangesetSpec(spec *btypes.ChangesetSpec, baseRev string) (*btypes.ChangesetSpec, error) {
+	desc := *spec.Spec
+	desc.BaseRev = baseRev
+
+	raw, err := json.Marshal(&desc)
+	if err != nil {
+		return nil, err
+	}
+
+	rebased := spec.Clone()
+	rebased.ID = 0
+	rebased.RandID = ""
+	rebased.Spec = &desc
+	rebased.RawSpec = string(raw)
+	rebased.CreatedAt = time.Time{}
+	rebased.UpdatedAt = time.Time{}
+
+	return rebased, nil
+}
+
 // publishChangeset creates the given changeset on its code host.
 func (e *executor) publishChangeset(ctx context.Context, asDraft bool) (err error) {
 	cs := &sources.Changeset{
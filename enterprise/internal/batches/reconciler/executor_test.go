@@ -21,13 +21,17 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
 	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	gitprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 func TestExecutor_ExecutePlan(t *testing.T) {
@@ -1101,3 +1105,46 @@ type mockInternalClient struct {
 func (c *mockInternalClient) ExternalURL(ctx context.Context) (string, error) {
 	return c.externalURL, c.err
 }
+
+func TestCurrentBaseRev(t *testing.T) {
+	spec := &btypes.ChangesetSpec{Spec: &batcheslib.ChangesetSpec{BaseRef: "refs/heads/main"}}
+	newGitHubChangeset := func(baseRefOid string) *btypes.Changeset {
+		return &btypes.Changeset{
+			ExternalServiceType: extsvc.TypeGitHub,
+			Metadata:            &github.PullRequest{BaseRefOid: baseRefOid},
+		}
+	}
+
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		if !opt.NoEnsureRevision {
+			t.Errorf("unexpected fetch of revision %q", spec)
+		}
+		switch spec {
+		case "refs/heads/main":
+			return "main-commit", nil
+		case "known-commit":
+			return "known-commit", nil
+		}
+		return "", &gitserver.RevisionNotFoundError{Repo: "repo", Spec: spec}
+	}
+	t.Cleanup(git.ResetMocks)
+
+	for name, tc := range map[string]struct {
+		changeset *btypes.Changeset
+		want      string
+	}{
+		"github base commit known to gitserver": {changeset: newGitHubChangeset("known-commit"), want: "known-commit"},
+		"github base commit not yet fetched":    {changeset: newGitHubChangeset("new-commit"), want: "main-commit"},
+		"other code hosts":                      {changeset: &btypes.Changeset{ExternalServiceType: extsvc.TypeGitLab}, want: "main-commit"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := CurrentBaseRev(context.Background(), "repo", tc.changeset, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Errorf("unexpected base rev. want=%q have=%q", tc.want, have)
+			}
+		})
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/cockroachdb/errors"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 var operationPrecedence = map[btypes.ReconcilerOperation]int{
 	btypes.ReconcilerOperationPush:         0,
 	btypes.ReconcilerOperationDetach:       0,
 	btypes.ReconcilerOperationArchive:      0,
+	btypes.ReconcilerOperationRebase:       0,
 	btypes.ReconcilerOperationImport:       1,
 	btypes.ReconcilerOperationPublish:      1,
 	btypes.ReconcilerOperationPublishDraft: 1,
@@ -242,6 +244,52 @@ func DeterminePlan(previousSpec, currentSpec *btypes.ChangesetSpec, ch *btypes.C
 	return pl, nil
 }
 
+// PlanRebase adds a rebase of the changeset onto currentBaseRev, the current
+// commit of its base branch, to the plan if the changeset needs one according
+// to mode. See btypes.Changeset.NeedsRebase.
+//
+// Nothing is added if the plan already pushes a new commit or removes the
+// changeset from its batch change.
+func (p *Plan) PlanRebase(mode batcheslib.RerunOnBaseChange, currentBaseRev string) {
+	if !p.rebaseWanted(mode) || !p.Changeset.NeedsRebase(mode, p.ChangesetSpec.Spec.BaseRev, currentBaseRev) {
+		return
+	}
+
+	p.AddOp(btypes.ReconcilerOperationRebase)
+	// Like after a regular push, we give the code host some time to pick up
+	// the new commit before syncing the changeset.
+	p.AddOp(btypes.ReconcilerOperationSleep)
+	p.AddOp(btypes.ReconcilerOperationSync)
+}
+
+// rebaseWanted returns true if the changeset of the plan may need a rebase
+// according to mode, depending on the current commit of its base branch.
+func (p *Plan) rebaseWanted(mode batcheslib.RerunOnBaseChange) bool {
+	if p.ChangesetSpec == nil {
+		return false
+	}
+
+	ch := p.Changeset
+	if !ch.Published() || ch.OwnedByBatchChangeID == 0 {
+		return false
+	}
+	if ch.ExternalState != btypes.ChangesetExternalStateOpen && ch.ExternalState != btypes.ChangesetExternalStateDraft {
+		return false
+	}
+
+	for _, op := range p.Ops {
+		switch op {
+		case btypes.ReconcilerOperationPush,
+			btypes.ReconcilerOperationClose,
+			btypes.ReconcilerOperationDetach,
+			btypes.ReconcilerOperationArchive:
+			return false
+		}
+	}
+
+	return ch.RebaseWanted(mode)
+}
+
 func reopenAfterDetach(ch *btypes.Changeset) bool {
 	closed := ch.ExternalState == btypes.ChangesetExternalStateClosed
 	if !closed {
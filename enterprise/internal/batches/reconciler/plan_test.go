@@ -6,6 +6,8 @@ import (
 	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 func TestDetermineReconcilerPlan(t *testing.T) {
@@ -420,6 +422,94 @@ func TestDetermineReconcilerPlan(t *testing.T) {
 	}
 }
 
+func TestPlanRebase(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name           string
+		mode           batcheslib.RerunOnBaseChange
+		metadata       *github.PullRequest
+		ops            Operations
+		wantOperations Operations
+	}{
+		{
+			name:           "not configured",
+			mode:           "",
+			metadata:       &github.PullRequest{BaseRefOid: "f00b4r", Mergeable: "CONFLICTING"},
+			wantOperations: Operations{},
+		},
+		{
+			name:           "up to date",
+			mode:           batcheslib.RerunOnBaseChangeOutdated,
+			metadata:       &github.PullRequest{BaseRefOid: "deadbeef", Mergeable: "MERGEABLE"},
+			wantOperations: Operations{},
+		},
+		{
+			name:     "outdated",
+			mode:     batcheslib.RerunOnBaseChangeOutdated,
+			metadata: &github.PullRequest{BaseRefOid: "f00b4r", Mergeable: "MERGEABLE"},
+			wantOperations: Operations{
+				btypes.ReconcilerOperationRebase,
+				btypes.ReconcilerOperationSleep,
+				btypes.ReconcilerOperationSync,
+			},
+		},
+		{
+			name:           "outdated but only conflicts",
+			mode:           batcheslib.RerunOnBaseChangeConflicts,
+			metadata:       &github.PullRequest{BaseRefOid: "f00b4r", Mergeable: "MERGEABLE"},
+			wantOperations: Operations{},
+		},
+		{
+			name:     "conflicting",
+			mode:     batcheslib.RerunOnBaseChangeConflicts,
+			metadata: &github.PullRequest{BaseRefOid: "f00b4r", Mergeable: "CONFLICTING"},
+			wantOperations: Operations{
+				btypes.ReconcilerOperationRebase,
+				btypes.ReconcilerOperationSleep,
+				btypes.ReconcilerOperationSync,
+			},
+		},
+		{
+			name:           "conflicting on current base",
+			mode:           batcheslib.RerunOnBaseChangeConflicts,
+			metadata:       &github.PullRequest{BaseRefOid: "deadbeef", Mergeable: "CONFLICTING"},
+			wantOperations: Operations{},
+		},
+		{
+			name:           "already pushing",
+			mode:           batcheslib.RerunOnBaseChangeConflicts,
+			metadata:       &github.PullRequest{BaseRefOid: "f00b4r", Mergeable: "CONFLICTING"},
+			ops:            Operations{btypes.ReconcilerOperationPush},
+			wantOperations: Operations{btypes.ReconcilerOperationPush},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := ct.BuildChangesetSpec(t, ct.TestSpecOpts{Published: true, BaseRev: "deadbeef"})
+			cs := ct.BuildChangeset(ct.TestChangesetOpts{
+				ExternalServiceType: extsvc.TypeGitHub,
+				ExternalState:       btypes.ChangesetExternalStateOpen,
+				PublicationState:    btypes.ChangesetPublicationStatePublished,
+				OwnedByBatchChange:  1234,
+				Metadata:            tc.metadata,
+			})
+
+			plan := &Plan{Changeset: cs, ChangesetSpec: spec}
+			for _, op := range tc.ops {
+				plan.AddOp(op)
+			}
+
+			// GitHub reports the current commit of the base branch.
+			plan.PlanRebase(tc.mode, tc.metadata.BaseRefOid)
+			if have, want := plan.Ops, tc.wantOperations; !have.Equal(want) {
+				t.Fatalf("incorrect plan determined, want=%v have=%v", want, have)
+			}
+		})
+	}
+}
+
 func uiPublicationStatePtr(state btypes.ChangesetUiPublicationState) *btypes.ChangesetUiPublicationState {
 	return &state
 }
@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 
+	"github.com/cockroachdb/errors"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 
@@ -88,6 +89,26 @@ func (r *Reconciler) process(ctx context.Context, tx *store.Store, ch *btypes.Ch
 		return err
 	}
 
+	if curr != nil && curr.BatchSpecID != 0 && ch.Published() {
+		batchSpec, err := tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: curr.BatchSpecID})
+		if err != nil {
+			return err
+		}
+		if mode := batchSpec.Spec.RerunOnBaseChange; plan.rebaseWanted(mode) {
+			repo, err := tx.Repos().Get(ctx, ch.RepoID)
+			if err != nil {
+				return errors.Wrap(err, "failed to load repository")
+			}
+			// The rebase is only an addition to the plan, so failing to find
+			// the base branch shouldn't prevent the rest of the plan.
+			if currentBaseRev, err := CurrentBaseRev(ctx, repo.Name, ch, curr); err != nil {
+				log15.Warn("Reconciler failed to resolve base commit", "changeset", ch.ID, "err", err)
+			} else {
+				plan.PlanRebase(mode, currentBaseRev)
+			}
+		}
+	}
+
 	log15.Info("Reconciler processing changeset", "changeset", ch.ID, "operations", plan.Ops)
 
 	return executePlan(
@@ -137,6 +137,98 @@ func (s *Service) CreateBatchSpec(ctx context.Context, opts CreateBatchSpecOpts)
 	return spec, nil
 }
 
+// EnqueueBatchSpecResolution enqueues a job that resolves the workspaces of
+// the given batch spec and enqueues an execution job for each of them. Only
+// the creator of the batch spec and site-admins can enqueue the resolution.
+func (s *Service) EnqueueBatchSpecResolution(ctx context.Context, batchSpecID int64, opts ResolveWorkspacesForBatchSpecOpts) (job *btypes.BatchSpecResolutionJob, err error) {
+	traceTitle := fmt.Sprintf("batchSpec: %d", batchSpecID)
+	tr, ctx := trace.New(ctx, "service.EnqueueBatchSpecResolution", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	spec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchSpecID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only the creator of the batch spec or site-admins can
+	// execute it.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), spec.UserID); err != nil {
+		return nil, err
+	}
+
+	// The workspaces of a batch spec are only resolved once, unless all
+	// previous attempts failed, so that they aren't executed more than once.
+	existing, err := s.store.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: spec.ID})
+	if err != nil && err != store.ErrNoResults {
+		return nil, err
+	}
+	if existing != nil && existing.State != btypes.BatchSpecResolutionJobStateFailed {
+		return nil, ErrBatchSpecAlreadyResolved
+	}
+
+	job = &btypes.BatchSpecResolutionJob{
+		BatchSpecID:      spec.ID,
+		AllowUnsupported: opts.AllowUnsupported,
+		AllowIgnored:     opts.AllowIgnored,
+	}
+	if err := s.store.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ErrBatchSpecAlreadyResolved is returned by EnqueueBatchSpecResolution if the
+// workspaces of the batch spec are being resolved or have been resolved
+// already.
+var ErrBatchSpecAlreadyResolved = errors.New("the workspaces of the batch spec have already been resolved")
+
+// ErrBatchSpecWorkspaceNotRetryable is returned by
+// RetryBatchSpecWorkspaceExecution if the newest execution job of the
+// workspace hasn't failed.
+var ErrBatchSpecWorkspaceNotRetryable = errors.New("the execution of the workspace can only be retried once it has failed")
+
+// RetryBatchSpecWorkspaceExecution re-enqueues the failed execution job of the
+// given batch spec workspace. Only the creator of the batch spec and
+// site-admins can retry the execution.
+func (s *Service) RetryBatchSpecWorkspaceExecution(ctx context.Context, workspaceID int64) (job *btypes.BatchSpecWorkspaceExecutionJob, err error) {
+	traceTitle := fmt.Sprintf("workspace: %d", workspaceID)
+	tr, ctx := trace.New(ctx, "service.RetryBatchSpecWorkspaceExecution", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	workspace, err := s.store.GetBatchSpecWorkspace(ctx, store.GetBatchSpecWorkspaceOpts{ID: workspaceID})
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only the creator of the batch spec or site-admins can
+	// execute it.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), spec.UserID); err != nil {
+		return nil, err
+	}
+
+	job, err = s.store.RetryBatchSpecWorkspaceExecutionJob(ctx, workspace.ID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, ErrBatchSpecWorkspaceNotRetryable
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
 // CreateChangesetSpec validates the given raw spec input and creates the ChangesetSpec.
 func (s *Service) CreateChangesetSpec(ctx context.Context, rawSpec string, userID int32) (spec *btypes.ChangesetSpec, err error) {
 	tr, ctx := trace.New(ctx, "Service.CreateChangesetSpec", fmt.Sprintf("User %d", userID))
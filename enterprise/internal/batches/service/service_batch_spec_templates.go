@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// ErrBatchSpecTemplateNameRequired is returned when a batch spec template
+// without a name is created or updated.
+var ErrBatchSpecTemplateNameRequired = errors.New("batch spec templates require a name")
+
+type CreateBatchSpecTemplateOpts struct {
+	Name        string
+	Description string
+	RawTemplate string
+	Inputs      []batcheslib.TemplateInput
+
+	// If both namespace IDs are zero, the template is created site-wide.
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// CreateBatchSpecTemplate creates a batch spec template after validating its
+// inputs.
+func (s *Service) CreateBatchSpecTemplate(ctx context.Context, opts CreateBatchSpecTemplateOpts) (tmpl *btypes.BatchSpecTemplate, err error) {
+	actor := actor.FromContext(ctx)
+	tr, ctx := trace.New(ctx, "Service.CreateBatchSpecTemplate", fmt.Sprintf("Actor %s", actor))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only site-admins can create site-wide templates, only
+	// users with access to the namespace can create templates in it.
+	if err := s.checkBatchSpecTemplateAdminAccess(ctx, opts.NamespaceUserID, opts.NamespaceOrgID); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchSpecTemplate(opts.Name, opts.RawTemplate, opts.Inputs); err != nil {
+		return nil, err
+	}
+
+	tmpl = &btypes.BatchSpecTemplate{
+		Name:            opts.Name,
+		Description:     opts.Description,
+		RawTemplate:     opts.RawTemplate,
+		Inputs:          opts.Inputs,
+		NamespaceUserID: opts.NamespaceUserID,
+		NamespaceOrgID:  opts.NamespaceOrgID,
+		CreatorID:       actor.UID,
+	}
+	return tmpl, s.store.CreateBatchSpecTemplate(ctx, tmpl)
+}
+
+type UpdateBatchSpecTemplateOpts struct {
+	ID          int64
+	Name        string
+	Description string
+	RawTemplate string
+	Inputs      []batcheslib.TemplateInput
+}
+
+// UpdateBatchSpecTemplate replaces the name, description, template, and inputs
+// of the given batch spec template.
+func (s *Service) UpdateBatchSpecTemplate(ctx context.Context, opts UpdateBatchSpecTemplateOpts) (tmpl *btypes.BatchSpecTemplate, err error) {
+	tr, ctx := trace.New(ctx, "Service.UpdateBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate %d", opts.ID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	tmpl, err = s.store.GetBatchSpecTemplate(ctx, store.GetBatchSpecTemplateOpts{ID: opts.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site-admins can update site-wide templates, only
+	// users with access to the namespace can update templates in it.
+	if err := s.checkBatchSpecTemplateAdminAccess(ctx, tmpl.NamespaceUserID, tmpl.NamespaceOrgID); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchSpecTemplate(opts.Name, opts.RawTemplate, opts.Inputs); err != nil {
+		return nil, err
+	}
+
+	tmpl.Name = opts.Name
+	tmpl.Description = opts.Description
+	tmpl.RawTemplate = opts.RawTemplate
+	tmpl.Inputs = opts.Inputs
+
+	return tmpl, s.store.UpdateBatchSpecTemplate(ctx, tmpl)
+}
+
+// DeleteBatchSpecTemplate deletes the batch spec template with the given ID.
+// Batch specs that were instantiated from it are not affected.
+func (s *Service) DeleteBatchSpecTemplate(ctx context.Context, id int64) (err error) {
+	tr, ctx := trace.New(ctx, "Service.DeleteBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate %d", id))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	tmpl, err := s.store.GetBatchSpecTemplate(ctx, store.GetBatchSpecTemplateOpts{ID: id})
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Only site-admins can delete site-wide templates, only
+	// users with access to the namespace can delete templates in it.
+	if err := s.checkBatchSpecTemplateAdminAccess(ctx, tmpl.NamespaceUserID, tmpl.NamespaceOrgID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteBatchSpecTemplate(ctx, id)
+}
+
+// GetBatchSpecTemplate returns the batch spec template with the given ID, if
+// the current user can see it: site-wide templates are visible to everyone,
+// other templates only to users with access to their namespace.
+func (s *Service) GetBatchSpecTemplate(ctx context.Context, id int64) (*btypes.BatchSpecTemplate, error) {
+	tmpl, err := s.store.GetBatchSpecTemplate(ctx, store.GetBatchSpecTemplateOpts{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Check that the current user has access to the namespace of
+	// the template.
+	if !tmpl.SiteWide() {
+		if err := s.CheckNamespaceAccess(ctx, tmpl.NamespaceUserID, tmpl.NamespaceOrgID); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
+
+type InstantiateBatchSpecTemplateOpts struct {
+	TemplateID int64
+	Values     map[string]interface{}
+
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// InstantiateBatchSpecTemplate renders the batch spec template with the given
+// values and creates a batch spec from the result in the given namespace. The
+// rendered batch spec is validated like any other batch spec.
+func (s *Service) InstantiateBatchSpecTemplate(ctx context.Context, opts InstantiateBatchSpecTemplateOpts) (spec *btypes.BatchSpec, err error) {
+	tr, ctx := trace.New(ctx, "Service.InstantiateBatchSpecTemplate", fmt.Sprintf("BatchSpecTemplate %d", opts.TemplateID))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: GetBatchSpecTemplate checks whether the current user can
+	// see the template. CreateBatchSpec checks the access to the namespace of
+	// the batch spec.
+	tmpl, err := s.GetBatchSpecTemplate(ctx, opts.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawSpec, err := tmpl.Render(opts.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreateBatchSpec(ctx, CreateBatchSpecOpts{
+		RawSpec:         rawSpec,
+		NamespaceUserID: opts.NamespaceUserID,
+		NamespaceOrgID:  opts.NamespaceOrgID,
+	})
+}
+
+// CanAdministerBatchSpecTemplate returns whether the current user can update
+// and delete the given batch spec template.
+func (s *Service) CanAdministerBatchSpecTemplate(ctx context.Context, tmpl *btypes.BatchSpecTemplate) (bool, error) {
+	err := s.checkBatchSpecTemplateAdminAccess(ctx, tmpl.NamespaceUserID, tmpl.NamespaceOrgID)
+	switch {
+	case err == nil:
+		return true, nil
+	case err == backend.ErrMustBeSiteAdmin, err == backend.ErrNotAnOrgMember, err == backend.ErrNotAuthenticated:
+		return false, nil
+	case errors.HasType(err, &backend.InsufficientAuthorizationError{}):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// checkBatchSpecTemplateAdminAccess checks whether the current user can
+// modify templates in the given namespace. If both namespace IDs are zero,
+// the site-wide namespace is checked.
+func (s *Service) checkBatchSpecTemplateAdminAccess(ctx context.Context, namespaceUserID, namespaceOrgID int32) error {
+	if namespaceUserID == 0 && namespaceOrgID == 0 {
+		return backend.CheckCurrentUserIsSiteAdmin(ctx, s.store.DB())
+	}
+	return s.CheckNamespaceAccess(ctx, namespaceUserID, namespaceOrgID)
+}
+
+func validateBatchSpecTemplate(name, rawTemplate string, inputs []batcheslib.TemplateInput) error {
+	if name == "" {
+		return ErrBatchSpecTemplateNameRequired
+	}
+	return batcheslib.ValidateBatchSpecTemplate(rawTemplate, inputs)
+}
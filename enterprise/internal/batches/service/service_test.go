@@ -9,6 +9,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -791,6 +792,99 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("EnqueueBatchSpecResolution", func(t *testing.T) {
+		spec := ct.CreateBatchSpec(t, ctx, s, "resolve-me", user.ID)
+
+		t.Run("success", func(t *testing.T) {
+			job, err := svc.EnqueueBatchSpecResolution(userCtx, spec.ID, ResolveWorkspacesForBatchSpecOpts{AllowIgnored: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			have, err := s.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: spec.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.ID != job.ID {
+				t.Fatalf("wrong job returned. want=%d, have=%d", job.ID, have.ID)
+			}
+			if !have.AllowIgnored || have.AllowUnsupported {
+				t.Fatalf("wrong options persisted: %+v", have)
+			}
+			if have.State != btypes.BatchSpecResolutionJobStateQueued {
+				t.Fatalf("job not queued. state=%s", have.State)
+			}
+		})
+
+		t.Run("already resolved", func(t *testing.T) {
+			if _, err := svc.EnqueueBatchSpecResolution(userCtx, spec.ID, ResolveWorkspacesForBatchSpecOpts{}); err != ErrBatchSpecAlreadyResolved {
+				t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecAlreadyResolved, err)
+			}
+
+			if err := s.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_resolution_jobs SET state = 'failed' WHERE batch_spec_id = %s", spec.ID)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.EnqueueBatchSpecResolution(userCtx, spec.ID, ResolveWorkspacesForBatchSpecOpts{}); err != nil {
+				t.Fatalf("resolution not re-enqueued after failure: %s", err)
+			}
+		})
+
+		t.Run("other user", func(t *testing.T) {
+			otherUser := ct.CreateTestUser(t, db, false)
+			otherCtx := actor.WithActor(context.Background(), actor.FromUser(otherUser.ID))
+
+			if _, err := svc.EnqueueBatchSpecResolution(otherCtx, spec.ID, ResolveWorkspacesForBatchSpecOpts{}); !errcode.IsUnauthorized(err) {
+				t.Fatalf("expected unauthorized error, got %+v", err)
+			}
+		})
+	})
+
+	t.Run("RetryBatchSpecWorkspaceExecution", func(t *testing.T) {
+		spec := ct.CreateBatchSpec(t, ctx, s, "retry-me", user.ID)
+
+		ws := &btypes.BatchSpecWorkspace{
+			BatchSpecID: spec.ID,
+			RepoID:      rs[0].ID,
+			Branch:      "refs/heads/main",
+			Commit:      "d34db33f",
+		}
+		if err := s.CreateBatchSpecWorkspace(ctx, ws); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CreateBatchSpecWorkspaceExecutionJobs(ctx, ws.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("not failed", func(t *testing.T) {
+			if _, err := svc.RetryBatchSpecWorkspaceExecution(userCtx, ws.ID); err != ErrBatchSpecWorkspaceNotRetryable {
+				t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecWorkspaceNotRetryable, err)
+			}
+		})
+
+		t.Run("failed", func(t *testing.T) {
+			if err := s.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_workspace_execution_jobs SET state = 'failed', failure_message = 'boom' WHERE batch_spec_workspace_id = %s", ws.ID)); err != nil {
+				t.Fatal(err)
+			}
+
+			otherUser := ct.CreateTestUser(t, db, false)
+			otherCtx := actor.WithActor(context.Background(), actor.FromUser(otherUser.ID))
+			if _, err := svc.RetryBatchSpecWorkspaceExecution(otherCtx, ws.ID); !errcode.IsUnauthorized(err) {
+				t.Fatalf("expected unauthorized error, got %+v", err)
+			}
+
+			job, err := svc.RetryBatchSpecWorkspaceExecution(userCtx, ws.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.State != btypes.BatchSpecWorkspaceExecutionJobStateQueued {
+				t.Fatalf("job not queued. state=%s", job.State)
+			}
+			if job.FailureMessage != nil {
+				t.Fatalf("failure message not reset: %q", *job.FailureMessage)
+			}
+		})
+	})
+
 	t.Run("FetchUsernameForBitbucketServerToken", func(t *testing.T) {
 		fakeSource := &sources.FakeChangesetSource{Username: "my-bbs-username"}
 		sourcer := sources.NewFakeSourcer(nil, fakeSource)
@@ -1101,6 +1195,127 @@ func TestService(t *testing.T) {
 
 		})
 	})
+
+	t.Run("BatchSpecTemplates", func(t *testing.T) {
+		const rawTemplate = `
+name: bump-${{ inputs.dependency }}
+on:
+  - repositoriesMatchingQuery: ${{ inputs.dependency }}
+steps:
+  - run: yarn upgrade ${{ inputs.dependency }}@${{ inputs.version }}
+    container: node:14
+changesetTemplate:
+  title: Bump ${{ inputs.dependency }} to ${{ inputs.version }}
+  branch: bump-${{ inputs.dependency }}
+  commit:
+    message: Bump ${{ inputs.dependency }}
+  published: false
+`
+		inputs := []batcheslib.TemplateInput{
+			{Name: "dependency", Type: batcheslib.TemplateInputTypeString},
+			{Name: "version", Type: batcheslib.TemplateInputTypeString},
+		}
+
+		t.Run("site-wide template by non-admin", func(t *testing.T) {
+			_, err := svc.CreateBatchSpecTemplate(userCtx, CreateBatchSpecTemplateOpts{
+				Name:        "bump-dependency",
+				RawTemplate: rawTemplate,
+				Inputs:      inputs,
+			})
+			if err != backend.ErrMustBeSiteAdmin {
+				t.Fatalf("wrong error. want=%s, have=%v", backend.ErrMustBeSiteAdmin, err)
+			}
+		})
+
+		t.Run("invalid template", func(t *testing.T) {
+			_, err := svc.CreateBatchSpecTemplate(userCtx, CreateBatchSpecTemplateOpts{
+				Name:            "bump-dependency",
+				RawTemplate:     rawTemplate,
+				Inputs:          inputs[:1],
+				NamespaceUserID: user.ID,
+			})
+			if !batcheslib.IsValidationError(err) {
+				t.Fatalf("expected validation error, got %+v", err)
+			}
+		})
+
+		tmpl, err := svc.CreateBatchSpecTemplate(adminCtx, CreateBatchSpecTemplateOpts{
+			Name:        "bump-dependency",
+			RawTemplate: rawTemplate,
+			Inputs:      inputs,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tmpl.CreatorID != admin.ID {
+			t.Fatalf("wrong creator. want=%d, have=%d", admin.ID, tmpl.CreatorID)
+		}
+
+		t.Run("update by non-admin", func(t *testing.T) {
+			_, err := svc.UpdateBatchSpecTemplate(userCtx, UpdateBatchSpecTemplateOpts{
+				ID:          tmpl.ID,
+				Name:        "bump",
+				RawTemplate: rawTemplate,
+				Inputs:      inputs,
+			})
+			if err != backend.ErrMustBeSiteAdmin {
+				t.Fatalf("wrong error. want=%s, have=%v", backend.ErrMustBeSiteAdmin, err)
+			}
+		})
+
+		t.Run("instantiate", func(t *testing.T) {
+			spec, err := svc.InstantiateBatchSpecTemplate(userCtx, InstantiateBatchSpecTemplateOpts{
+				TemplateID:      tmpl.ID,
+				Values:          map[string]interface{}{"dependency": "lodash", "version": "4.17.21"},
+				NamespaceUserID: user.ID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have, want := spec.Spec.Name, "bump-lodash"; have != want {
+				t.Fatalf("wrong name. want=%q, have=%q", want, have)
+			}
+			if have, want := spec.UserID, user.ID; have != want {
+				t.Fatalf("wrong user. want=%d, have=%d", want, have)
+			}
+		})
+
+		t.Run("instantiate with missing input", func(t *testing.T) {
+			_, err := svc.InstantiateBatchSpecTemplate(userCtx, InstantiateBatchSpecTemplateOpts{
+				TemplateID:      tmpl.ID,
+				Values:          map[string]interface{}{"dependency": "lodash"},
+				NamespaceUserID: user.ID,
+			})
+			if !batcheslib.IsValidationError(err) {
+				t.Fatalf("expected validation error, got %+v", err)
+			}
+		})
+
+		t.Run("template in other user's namespace", func(t *testing.T) {
+			otherUser := ct.CreateTestUser(t, db, false)
+			otherCtx := actor.WithActor(context.Background(), actor.FromUser(otherUser.ID))
+
+			private, err := svc.CreateBatchSpecTemplate(otherCtx, CreateBatchSpecTemplateOpts{
+				Name:            "bump-dependency",
+				RawTemplate:     rawTemplate,
+				Inputs:          inputs,
+				NamespaceUserID: otherUser.ID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := svc.GetBatchSpecTemplate(userCtx, private.ID); !errcode.IsUnauthorized(err) {
+				t.Fatalf("expected unauthorized error, got %+v", err)
+			}
+			if err := svc.DeleteBatchSpecTemplate(userCtx, private.ID); !errcode.IsUnauthorized(err) {
+				t.Fatalf("expected unauthorized error, got %+v", err)
+			}
+			if err := svc.DeleteBatchSpecTemplate(otherCtx, private.ID); err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
 }
 
 func testBatchChange(user int32, spec *btypes.BatchSpec) *btypes.BatchChange {
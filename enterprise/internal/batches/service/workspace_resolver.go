@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+	"github.com/hashicorp/go-multierror"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// RepoRevision describes a repository on a branch at a fixed revision.
+type RepoRevision struct {
+	Repo        *types.Repo
+	Branch      string
+	Commit      api.CommitID
+	FileMatches []string
+}
+
+// RepoWorkspace is a single directory in a RepoRevision in which the steps of
+// a batch spec are executed.
+type RepoWorkspace struct {
+	*RepoRevision
+	Path string
+
+	Steps              []batcheslib.Step
+	OnlyFetchWorkspace bool
+}
+
+// ResolveWorkspacesForBatchSpecOpts configures which repositories are
+// skipped during workspace resolution.
+type ResolveWorkspacesForBatchSpecOpts struct {
+	// AllowUnsupported includes repositories on code hosts that batch changes
+	// don't support.
+	AllowUnsupported bool
+	// AllowIgnored includes repositories that contain a .batchignore file.
+	AllowIgnored bool
+}
+
+// WorkspaceResolver resolves the `on` and `workspaces` attributes of a batch
+// spec into the list of workspaces in which the steps are executed.
+type WorkspaceResolver interface {
+	ResolveWorkspacesForBatchSpec(ctx context.Context, batchSpec *batcheslib.BatchSpec, opts ResolveWorkspacesForBatchSpecOpts) ([]*RepoWorkspace, error)
+}
+
+// WorkspaceResolverBuilder creates a WorkspaceResolver that uses the given
+// store.
+type WorkspaceResolverBuilder func(tx *store.Store) WorkspaceResolver
+
+// NewWorkspaceResolver returns a WorkspaceResolver that runs searches against
+// the frontend's internal streaming search API.
+func NewWorkspaceResolver(s *store.Store) WorkspaceResolver {
+	return &workspaceResolver{
+		store:               s,
+		frontendInternalURL: api.InternalClient.URL + "/.internal",
+	}
+}
+
+type workspaceResolver struct {
+	store               *store.Store
+	frontendInternalURL string
+}
+
+func (wr *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context, batchSpec *batcheslib.BatchSpec, opts ResolveWorkspacesForBatchSpecOpts) (workspaces []*RepoWorkspace, err error) {
+	tr, ctx := trace.New(ctx, "workspaceResolver.ResolveWorkspacesForBatchSpec", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	repos, err := wr.determineRepositories(ctx, batchSpec)
+	if err != nil {
+		return nil, err
+	}
+	tr.LogFields(log.Int("repos", len(repos)))
+
+	repos, err = wr.filterRepositories(ctx, repos, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err = wr.findWorkspaces(ctx, batchSpec, repos)
+	if err != nil {
+		return nil, err
+	}
+	tr.LogFields(log.Int("workspaces", len(workspaces)))
+
+	return workspaces, nil
+}
+
+// determineRepositories resolves all entries in `on` into a deduplicated list
+// of repository revisions.
+func (wr *workspaceResolver) determineRepositories(ctx context.Context, batchSpec *batcheslib.BatchSpec) ([]*RepoRevision, error) {
+	seen := map[api.RepoID]*RepoRevision{}
+	var repos []*RepoRevision
+
+	var errs *multierror.Error
+	for _, on := range batchSpec.On {
+		revs, err := wr.resolveRepositoriesOn(ctx, &on)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "resolving %q", on.String()))
+			continue
+		}
+
+		for _, rev := range revs {
+			// Repositories matched by more than one `on` entry are only
+			// executed once. File matches of later entries are merged into
+			// the first match.
+			if other, ok := seen[rev.Repo.ID]; ok {
+				other.FileMatches = mergeFileMatches(other.FileMatches, rev.FileMatches)
+				continue
+			}
+			seen[rev.Repo.ID] = rev
+			repos = append(repos, rev)
+		}
+	}
+
+	return repos, errs.ErrorOrNil()
+}
+
+func (wr *workspaceResolver) resolveRepositoriesOn(ctx context.Context, on *batcheslib.OnQueryOrRepository) ([]*RepoRevision, error) {
+	if on.RepositoriesMatchingQuery != "" {
+		return wr.resolveRepositoriesMatchingQuery(ctx, on.RepositoriesMatchingQuery)
+	}
+
+	if on.Repository != "" {
+		rev, err := wr.resolveRepositoryName(ctx, on.Repository, on.Branch)
+		if err != nil {
+			return nil, err
+		}
+		if rev == nil {
+			return nil, nil
+		}
+		return []*RepoRevision{rev}, nil
+	}
+
+	return nil, errors.New(`"on" element must have either "repositoriesMatchingQuery" or "repository" set`)
+}
+
+func (wr *workspaceResolver) resolveRepositoryName(ctx context.Context, name, branch string) (*RepoRevision, error) {
+	// 🚨 SECURITY: database.Repos.GetByName uses the authzFilter under the hood
+	// and returns an error if the user doesn't have access to the repository.
+	repo, err := wr.store.Repos().GetByName(ctx, api.RepoName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if branch == "" {
+		return wr.resolveDefaultBranch(ctx, repo)
+	}
+
+	commit, err := git.ResolveRevision(ctx, repo.Name, branch, git.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving branch %q", branch)
+	}
+
+	return &RepoRevision{
+		Repo:   repo,
+		Branch: git.EnsureRefPrefix(branch),
+		Commit: commit,
+	}, nil
+}
+
+func (wr *workspaceResolver) resolveDefaultBranch(ctx context.Context, repo *types.Repo) (*RepoRevision, error) {
+	branch, commit, err := git.GetDefaultBranch(ctx, repo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository is empty or not cloned yet, so there's nothing we
+	// could execute the steps on.
+	if branch == "" {
+		return nil, nil
+	}
+
+	return &RepoRevision{Repo: repo, Branch: branch, Commit: commit}, nil
+}
+
+func (wr *workspaceResolver) resolveRepositoriesMatchingQuery(ctx context.Context, query string) ([]*RepoRevision, error) {
+	query = setDefaultQueryCount(query)
+
+	fileMatches := map[string][]string{}
+	var repoNames []string
+	addRepo := func(name string) {
+		if _, ok := fileMatches[name]; !ok {
+			fileMatches[name] = []string{}
+			repoNames = append(repoNames, name)
+		}
+	}
+
+	err := wr.runSearch(ctx, query, func(matches []streamhttp.EventMatch) {
+		for _, match := range matches {
+			switch m := match.(type) {
+			case *streamhttp.EventRepoMatch:
+				addRepo(m.Repository)
+			case *streamhttp.EventContentMatch:
+				addRepo(m.Repository)
+				fileMatches[m.Repository] = append(fileMatches[m.Repository], m.Path)
+			case *streamhttp.EventPathMatch:
+				addRepo(m.Repository)
+				fileMatches[m.Repository] = append(fileMatches[m.Repository], m.Path)
+			case *streamhttp.EventSymbolMatch:
+				addRepo(m.Repository)
+				fileMatches[m.Repository] = append(fileMatches[m.Repository], m.Path)
+			case *streamhttp.EventCommitMatch:
+				addRepo(m.Repository)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repoNames) == 0 {
+		return nil, nil
+	}
+
+	// 🚨 SECURITY: The search runs with the internal actor, so we have to
+	// check repository permissions here. database.Repos.List uses the
+	// authzFilter under the hood and filters out repositories that the user
+	// doesn't have access to.
+	accessibleRepos, err := wr.store.Repos().List(ctx, database.ReposListOptions{Names: repoNames})
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make([]*RepoRevision, 0, len(accessibleRepos))
+	for _, repo := range accessibleRepos {
+		rev, err := wr.resolveDefaultBranch(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		if rev == nil {
+			continue
+		}
+		rev.FileMatches = mergeFileMatches(nil, fileMatches[string(repo.Name)])
+		revs = append(revs, rev)
+	}
+
+	return revs, nil
+}
+
+const batchIgnoreFilePath = ".batchignore"
+
+// filterRepositories removes repositories on unsupported code hosts and
+// repositories that opted out of batch changes through a .batchignore file,
+// unless the given opts allow them.
+func (wr *workspaceResolver) filterRepositories(ctx context.Context, repos []*RepoRevision, opts ResolveWorkspacesForBatchSpecOpts) ([]*RepoRevision, error) {
+	filtered := make([]*RepoRevision, 0, len(repos))
+	for _, repo := range repos {
+		if !opts.AllowUnsupported && !btypes.IsRepoSupported(&repo.Repo.ExternalRepo) {
+			continue
+		}
+
+		if !opts.AllowIgnored {
+			ignored, err := hasBatchIgnoreFile(ctx, repo)
+			if err != nil {
+				return nil, err
+			}
+			if ignored {
+				continue
+			}
+		}
+
+		filtered = append(filtered, repo)
+	}
+	return filtered, nil
+}
+
+func hasBatchIgnoreFile(ctx context.Context, r *RepoRevision) (bool, error) {
+	stat, err := git.Stat(ctx, r.Repo.Name, r.Commit, batchIgnoreFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !stat.IsDir(), nil
+}
+
+// findWorkspaces matches the given repositories against the `workspaces`
+// configuration of the batch spec and returns a workspace for every
+// directory the steps should be executed in. Repositories that aren't
+// matched by any configuration get a single workspace at their root.
+func (wr *workspaceResolver) findWorkspaces(ctx context.Context, batchSpec *batcheslib.BatchSpec, repos []*RepoRevision) ([]*RepoWorkspace, error) {
+	// Pre-compile all globs.
+	globs := make([]glob.Glob, 0, len(batchSpec.Workspaces))
+	for _, conf := range batchSpec.Workspaces {
+		in := conf.In
+		if in == "" {
+			in = "*"
+		}
+		g, err := glob.Compile(in)
+		if err != nil {
+			return nil, batcheslib.NewValidationError(errors.Wrapf(err, "compiling glob %q", in))
+		}
+		globs = append(globs, g)
+	}
+
+	matchedByConf := make([][]*RepoRevision, len(batchSpec.Workspaces))
+	var unmatched []*RepoRevision
+	for _, repo := range repos {
+		matched := -1
+		for i, g := range globs {
+			if !g.Match(string(repo.Repo.Name)) {
+				continue
+			}
+			if matched != -1 {
+				return nil, batcheslib.NewValidationError(errors.Errorf(
+					"repository %s matches multiple workspaces.in globs in the batch spec. glob: %q",
+					repo.Repo.Name, batchSpec.Workspaces[i].In,
+				))
+			}
+			matched = i
+		}
+
+		if matched == -1 {
+			unmatched = append(unmatched, repo)
+			continue
+		}
+		matchedByConf[matched] = append(matchedByConf[matched], repo)
+	}
+
+	var workspaces []*RepoWorkspace
+	for i, conf := range batchSpec.Workspaces {
+		if len(matchedByConf[i]) == 0 {
+			continue
+		}
+
+		dirsByRepo, err := wr.findDirectoriesContaining(ctx, matchedByConf[i], conf.RootAtLocationOf)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range matchedByConf[i] {
+			for _, dir := range dirsByRepo[repo.Repo.ID] {
+				workspaces = append(workspaces, &RepoWorkspace{
+					RepoRevision: &RepoRevision{
+						Repo:        repo.Repo,
+						Branch:      repo.Branch,
+						Commit:      repo.Commit,
+						FileMatches: fileMatchesInWorkspace(repo.FileMatches, dir),
+					},
+					Path:               dir,
+					Steps:              batchSpec.Steps,
+					OnlyFetchWorkspace: conf.OnlyFetchWorkspace,
+				})
+			}
+		}
+	}
+
+	for _, repo := range unmatched {
+		workspaces = append(workspaces, &RepoWorkspace{
+			RepoRevision: repo,
+			Path:         "",
+			Steps:        batchSpec.Steps,
+		})
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		if workspaces[i].Repo.Name != workspaces[j].Repo.Name {
+			return workspaces[i].Repo.Name < workspaces[j].Repo.Name
+		}
+		return workspaces[i].Path < workspaces[j].Path
+	})
+
+	return workspaces, nil
+}
+
+// findDirectoriesContaining searches for files named fileName in the given
+// repositories and returns the directories they're located in.
+func (wr *workspaceResolver) findDirectoriesContaining(ctx context.Context, repos []*RepoRevision, fileName string) (map[api.RepoID][]string, error) {
+	byName := make(map[string]*RepoRevision, len(repos))
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		byName[string(repo.Repo.Name)] = repo
+		names = append(names, regexp.QuoteMeta(string(repo.Repo.Name)))
+	}
+
+	query := fmt.Sprintf(
+		`file:(^|/)%s$ repo:^(%s)$ type:path count:all`,
+		regexp.QuoteMeta(fileName),
+		strings.Join(names, "|"),
+	)
+
+	seen := map[api.RepoID]map[string]struct{}{}
+	dirs := map[api.RepoID][]string{}
+	err := wr.runSearch(ctx, query, func(matches []streamhttp.EventMatch) {
+		for _, match := range matches {
+			m, ok := match.(*streamhttp.EventPathMatch)
+			if !ok {
+				continue
+			}
+
+			repo, ok := byName[m.Repository]
+			if !ok {
+				continue
+			}
+
+			dir := path.Dir(m.Path)
+			if dir == "." {
+				dir = ""
+			}
+
+			if _, ok := seen[repo.Repo.ID]; !ok {
+				seen[repo.Repo.ID] = map[string]struct{}{}
+			}
+			if _, ok := seen[repo.Repo.ID][dir]; ok {
+				continue
+			}
+			seen[repo.Repo.ID][dir] = struct{}{}
+			dirs[repo.Repo.ID] = append(dirs[repo.Repo.ID], dir)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dirs, nil
+}
+
+// runSearch runs the given query against the streaming search API and calls
+// onMatches for every batch of matches.
+func (wr *workspaceResolver) runSearch(ctx context.Context, query string, onMatches func(matches []streamhttp.EventMatch)) (err error) {
+	req, err := streamhttp.NewRequest(wr.frontendInternalURL, query)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := httpcli.InternalClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("search returned unexpected status code %d", resp.StatusCode)
+	}
+
+	var searchErrs *multierror.Error
+	dec := streamhttp.FrontendStreamDecoder{
+		OnMatches: onMatches,
+		OnError: func(ee *streamhttp.EventError) {
+			searchErrs = multierror.Append(searchErrs, errors.New(ee.Message))
+		},
+	}
+	if err := dec.ReadAll(resp.Body); err != nil {
+		return err
+	}
+
+	return searchErrs.ErrorOrNil()
+}
+
+var countRegex = regexp.MustCompile(`(?i)\bcount:(\d+|all)\b`)
+
+// setDefaultQueryCount makes sure that all results are returned by the search
+// if the query doesn't already specify a count.
+func setDefaultQueryCount(query string) string {
+	if countRegex.MatchString(query) {
+		return query
+	}
+	return query + " count:all"
+}
+
+// fileMatchesInWorkspace returns the subset of file matches that are located
+// in the workspace at dir.
+func fileMatchesInWorkspace(fileMatches []string, dir string) []string {
+	if dir == "" {
+		return fileMatches
+	}
+
+	prefix := dir + "/"
+	matches := []string{}
+	for _, m := range fileMatches {
+		if strings.HasPrefix(m, prefix) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// mergeFileMatches returns the sorted union of a and b.
+func mergeFileMatches(a, b []string) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for _, m := range a {
+		set[m] = struct{}{}
+	}
+	for _, m := range b {
+		set[m] = struct{}{}
+	}
+
+	merged := make([]string, 0, len(set))
+	for m := range set {
+		merged = append(merged, m)
+	}
+	sort.Strings(merged)
+	return merged
+}
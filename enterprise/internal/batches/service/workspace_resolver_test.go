@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestWorkspaceResolver(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := actor.WithInternalActor(context.Background())
+	db := dbtest.NewDB(t, "")
+	s := store.New(db, &observation.TestContext, nil)
+
+	rs, _ := ct.CreateTestRepos(t, ctx, db, 3)
+
+	defaultBranches := map[api.RepoName]api.CommitID{
+		rs[0].Name: "d34db33f",
+		rs[1].Name: "c0ff33",
+		rs[2].Name: "b4d455",
+	}
+	git.Mocks.GetDefaultBranch = func(repo api.RepoName) (string, api.CommitID, error) {
+		return "refs/heads/main", defaultBranches[repo], nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "f00b4r", nil
+	}
+	ignored := map[api.CommitID]bool{"b4d455": true}
+	git.Mocks.Stat = func(commit api.CommitID, name string) (fs.FileInfo, error) {
+		if name == batchIgnoreFilePath && ignored[commit] {
+			return &fileInfo{name: name}, nil
+		}
+		return nil, &os.PathError{Op: "ls-tree", Path: name, Err: os.ErrNotExist}
+	}
+	t.Cleanup(git.ResetMocks)
+
+	searchResults := map[string][]streamhttp.EventMatch{
+		"repohasfile:README count:all": {
+			&streamhttp.EventRepoMatch{Type: streamhttp.RepoMatchType, Repository: string(rs[0].Name)},
+			&streamhttp.EventRepoMatch{Type: streamhttp.RepoMatchType, Repository: string(rs[2].Name)},
+		},
+		"lang:go fmt.Println count:all": {
+			&streamhttp.EventContentMatch{Type: streamhttp.ContentMatchType, Repository: string(rs[1].Name), Path: "a/main.go"},
+			&streamhttp.EventContentMatch{Type: streamhttp.ContentMatchType, Repository: string(rs[1].Name), Path: "b/main.go"},
+		},
+		`file:(^|/)go\.mod$ repo:^(` + regexp.QuoteMeta(string(rs[1].Name)) + `)$ type:path count:all`: {
+			&streamhttp.EventPathMatch{Type: streamhttp.PathMatchType, Repository: string(rs[1].Name), Path: "a/go.mod"},
+			&streamhttp.EventPathMatch{Type: streamhttp.PathMatchType, Repository: string(rs[1].Name), Path: "b/go.mod"},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		matches, ok := searchResults[q]
+		if !ok {
+			t.Errorf("unexpected search query %q", q)
+		}
+
+		sw, err := streamhttp.NewWriter(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sw.Event("matches", matches); err != nil {
+			t.Fatal(err)
+		}
+		if err := sw.Event("done", map[string]interface{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resolver := &workspaceResolver{store: s, frontendInternalURL: srv.URL}
+
+	steps := []batcheslib.Step{{Run: "echo 1", Container: "alpine:3"}}
+
+	t.Run("repositoriesMatchingQuery", func(t *testing.T) {
+		spec := &batcheslib.BatchSpec{
+			On: []batcheslib.OnQueryOrRepository{
+				{RepositoriesMatchingQuery: "repohasfile:README"},
+				{RepositoriesMatchingQuery: "lang:go fmt.Println"},
+			},
+			Steps: steps,
+		}
+
+		have, err := resolver.ResolveWorkspacesForBatchSpec(ctx, spec, ResolveWorkspacesForBatchSpecOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*RepoWorkspace{
+			buildRepoWorkspace(rs[0], "d34db33f", "refs/heads/main", "", []string{}, steps),
+			buildRepoWorkspace(rs[1], "c0ff33", "refs/heads/main", "", []string{"a/main.go", "b/main.go"}, steps),
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("wrong workspaces (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("allow ignored", func(t *testing.T) {
+		spec := &batcheslib.BatchSpec{
+			On:    []batcheslib.OnQueryOrRepository{{RepositoriesMatchingQuery: "repohasfile:README"}},
+			Steps: steps,
+		}
+
+		have, err := resolver.ResolveWorkspacesForBatchSpec(ctx, spec, ResolveWorkspacesForBatchSpecOpts{AllowIgnored: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*RepoWorkspace{
+			buildRepoWorkspace(rs[0], "d34db33f", "refs/heads/main", "", []string{}, steps),
+			buildRepoWorkspace(rs[2], "b4d455", "refs/heads/main", "", []string{}, steps),
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("wrong workspaces (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("repository with branch", func(t *testing.T) {
+		spec := &batcheslib.BatchSpec{
+			On:    []batcheslib.OnQueryOrRepository{{Repository: string(rs[0].Name), Branch: "feature"}},
+			Steps: steps,
+		}
+
+		have, err := resolver.ResolveWorkspacesForBatchSpec(ctx, spec, ResolveWorkspacesForBatchSpecOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*RepoWorkspace{
+			buildRepoWorkspace(rs[0], "f00b4r", "refs/heads/feature", "", nil, steps),
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("wrong workspaces (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("workspaces", func(t *testing.T) {
+		spec := &batcheslib.BatchSpec{
+			On: []batcheslib.OnQueryOrRepository{
+				{RepositoriesMatchingQuery: "repohasfile:README"},
+				{RepositoriesMatchingQuery: "lang:go fmt.Println"},
+			},
+			Workspaces: []batcheslib.WorkspaceConfiguration{
+				{RootAtLocationOf: "go.mod", In: string(rs[1].Name), OnlyFetchWorkspace: true},
+			},
+			Steps: steps,
+		}
+
+		have, err := resolver.ResolveWorkspacesForBatchSpec(ctx, spec, ResolveWorkspacesForBatchSpecOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*RepoWorkspace{
+			buildRepoWorkspace(rs[0], "d34db33f", "refs/heads/main", "", []string{}, steps),
+			buildRepoWorkspace(rs[1], "c0ff33", "refs/heads/main", "a", []string{"a/main.go"}, steps),
+			buildRepoWorkspace(rs[1], "c0ff33", "refs/heads/main", "b", []string{"b/main.go"}, steps),
+		}
+		want[1].OnlyFetchWorkspace = true
+		want[2].OnlyFetchWorkspace = true
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatalf("wrong workspaces (-want +have):\n%s", diff)
+		}
+	})
+}
+
+func TestSetDefaultQueryCount(t *testing.T) {
+	for in, want := range map[string]string{
+		"count:10":             "count:10",
+		"foo count:all":        "foo count:all",
+		"repohasfile:README":   "repohasfile:README count:all",
+		"COUNT:5 repo:foo":     "COUNT:5 repo:foo",
+		"foo:count:bar lol":    "foo:count:bar lol count:all",
+		"r:sourcegraph count:": "r:sourcegraph count: count:all",
+	} {
+		if have := setDefaultQueryCount(in); have != want {
+			t.Errorf("setDefaultQueryCount(%q): have=%q want=%q", in, have, want)
+		}
+	}
+}
+
+func buildRepoWorkspace(repo *types.Repo, commit, branch, path string, fileMatches []string, steps []batcheslib.Step) *RepoWorkspace {
+	return &RepoWorkspace{
+		RepoRevision: &RepoRevision{
+			Repo:        repo,
+			Branch:      branch,
+			Commit:      api.CommitID(commit),
+			FileMatches: fileMatches,
+		},
+		Path:  path,
+		Steps: steps,
+	}
+}
+
+type fileInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (f *fileInfo) Name() string { return f.name }
+func (f *fileInfo) IsDir() bool  { return false }
@@ -76,6 +76,17 @@ type ChangesetSource interface {
 	MergeChangeset(ctx context.Context, ch *Changeset, squash bool) error
 }
 
+// RebaseMerger is implemented by ChangesetSources whose code host can merge a
+// changeset by rebasing its commits onto the base branch instead of creating a
+// merge commit.
+type RebaseMerger interface {
+	// RebaseMergeChangeset merges a Changeset on the code host by rebasing its
+	// commits, if in a mergeable state. If the changeset cannot be merged,
+	// because it is in an unmergeable state, ChangesetNotMergeableError must be
+	// returned.
+	RebaseMergeChangeset(ctx context.Context, ch *Changeset) error
+}
+
 // ChangesetNotMergeableError is returned by MergeChangeset if the changeset
 // could not be merged on the codehost, because some precondition is not met. This
 // is only returned, if the changeset is not mergeable. Other errors, such as
@@ -51,6 +51,7 @@ type FakeChangesetSource struct {
 	AuthenticatedUsernameCalled bool
 	ValidateAuthenticatorCalled bool
 	MergeChangesetCalled        bool
+	RebaseMergeChangesetCalled  bool
 
 	// The Changeset.HeadRef to be expected in CreateChangeset/UpdateChangeset calls.
 	WantHeadRef string
@@ -96,6 +97,7 @@ type FakeChangesetSource struct {
 }
 
 var _ ChangesetSource = &FakeChangesetSource{}
+var _ RebaseMerger = &FakeChangesetSource{}
 var _ DraftChangesetSource = &FakeChangesetSource{}
 
 func (s *FakeChangesetSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
@@ -283,3 +285,8 @@ func (s *FakeChangesetSource) MergeChangeset(ctx context.Context, c *Changeset,
 	s.MergeChangesetCalled = true
 	return s.Err
 }
+
+func (s *FakeChangesetSource) RebaseMergeChangeset(ctx context.Context, c *Changeset) error {
+	s.RebaseMergeChangesetCalled = true
+	return s.Err
+}
@@ -268,3 +268,21 @@ func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, squash b
 
 	return c.Changeset.SetMetadata(pr)
 }
+
+// RebaseMergeChangeset merges a Changeset on the code host by rebasing its
+// commits onto the base branch, if in a mergeable state.
+func (s GithubSource) RebaseMergeChangeset(ctx context.Context, c *Changeset) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+
+	if err := s.client.RebaseMergePullRequest(ctx, pr); err != nil {
+		if github.IsNotMergeable(err) {
+			return ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return err
+	}
+
+	return c.Changeset.SetMetadata(pr)
+}
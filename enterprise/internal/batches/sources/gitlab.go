@@ -269,6 +269,16 @@ func (s *GitLabSource) decorateMergeRequestData(ctx context.Context, project *gi
 		return errors.Wrap(err, "retrieving pipelines")
 	}
 
+	// Only the jobs of the latest pipeline are relevant for the checks of the
+	// merge request.
+	if latest := latestPipeline(pipelines); latest != nil {
+		jobs, err := s.getPipelineJobs(ctx, project, latest)
+		if err != nil {
+			return errors.Wrap(err, "retrieving pipeline jobs")
+		}
+		latest.Jobs = jobs
+	}
+
 	mr.Notes = notes
 	mr.Pipelines = pipelines
 	mr.ResourceStateEvents = events
@@ -383,6 +393,37 @@ func readPipelines(it func() ([]*gitlab.Pipeline, error)) ([]*gitlab.Pipeline, e
 	}
 }
 
+// latestPipeline returns the most recently created of the given pipelines.
+func latestPipeline(pipelines []*gitlab.Pipeline) *gitlab.Pipeline {
+	var latest *gitlab.Pipeline
+	for _, p := range pipelines {
+		if latest == nil || p.CreatedAt.After(latest.CreatedAt.Time) {
+			latest = p
+		}
+	}
+	return latest
+}
+
+// getPipelineJobs retrieves the jobs of a pipeline.
+func (s *GitLabSource) getPipelineJobs(ctx context.Context, project *gitlab.Project, pipeline *gitlab.Pipeline) ([]*gitlab.Job, error) {
+	it := s.client.GetPipelineJobs(ctx, project, pipeline.ID)
+
+	var jobs []*gitlab.Job
+	for {
+		page, err := it()
+		if err != nil {
+			return nil, errors.Wrap(err, "retrieving job page")
+		}
+		if len(page) == 0 {
+			// The terminal condition for the iterator is returning an empty
+			// slice with no error, so we can stop iterating here.
+			return jobs, nil
+		}
+
+		jobs = append(jobs, page...)
+	}
+}
+
 // UpdateChangeset updates the merge request on GitLab to reflect the local
 // state of the Changeset.
 func (s *GitLabSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
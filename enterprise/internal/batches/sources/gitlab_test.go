@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
@@ -528,9 +529,13 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			// A new merge request with a new IID.
 			mr := &gitlab.MergeRequest{IID: 43}
 			pipelines := []*gitlab.Pipeline{
-				{ID: 1},
-				{ID: 2},
-				{ID: 3},
+				{ID: 1, CreatedAt: gitlab.Time{Time: time.Unix(10, 0)}},
+				{ID: 2, CreatedAt: gitlab.Time{Time: time.Unix(30, 0)}},
+				{ID: 3, CreatedAt: gitlab.Time{Time: time.Unix(20, 0)}},
+			}
+			jobs := []*gitlab.Job{
+				{ID: 1, Name: "build", Status: gitlab.PipelineStatusSuccess},
+				{ID: 2, Name: "test", Status: gitlab.PipelineStatusRunning},
 			}
 
 			p := newGitLabChangesetSourceTestProvider(t)
@@ -540,6 +545,8 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			p.mockGetMergeRequestNotes(43, nil, 20, nil)
 			p.mockGetMergeRequestResourceStateEvents(43, nil, 20, nil)
 			p.mockGetMergeRequestPipelines(43, pipelines, 20, nil)
+			// Only the jobs of the latest pipeline are retrieved.
+			p.mockGetPipelineJobs(2, jobs, nil)
 
 			if err := p.source.LoadChangeset(p.ctx, p.changeset); err != nil {
 				t.Errorf("unexpected error: %+v", err)
@@ -547,6 +554,9 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			if diff := cmp.Diff(mr.Pipelines, pipelines); diff != "" {
 				t.Errorf("unexpected pipelines: %s", diff)
 			}
+			if diff := cmp.Diff(pipelines[1].Jobs, jobs); diff != "" {
+				t.Errorf("unexpected jobs: %s", diff)
+			}
 
 			// A subsequent load should result in the same pipelines. Since we
 			// changed the IID in the merge request, we do need to change the
@@ -947,6 +957,27 @@ func (p *gitLabChangesetSourceTestProvider) mockGetMergeRequestPipelines(expecte
 	}
 }
 
+func (p *gitLabChangesetSourceTestProvider) mockGetPipelineJobs(expectedPipeline gitlab.ID, jobs []*gitlab.Job, err error) {
+	gitlab.MockGetPipelineJobs = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, pipeline gitlab.ID) func() ([]*gitlab.Job, error) {
+		p.testCommonParams(ctx, client, project)
+		if expectedPipeline != pipeline {
+			p.t.Errorf("unexpected pipeline: have %d; want %d", pipeline, expectedPipeline)
+		}
+
+		done := false
+		return func() ([]*gitlab.Job, error) {
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				return []*gitlab.Job{}, nil
+			}
+			done = true
+			return jobs, nil
+		}
+	}
+}
+
 func (p *gitLabChangesetSourceTestProvider) mockGetOpenMergeRequestByRefs(mr *gitlab.MergeRequest, err error) {
 	gitlab.MockGetOpenMergeRequestByRefs = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, source, target string) (*gitlab.MergeRequest, error) {
 		p.testCommonParams(ctx, client, project)
@@ -984,6 +1015,7 @@ func (p *gitLabChangesetSourceTestProvider) unmock() {
 	gitlab.MockGetMergeRequestNotes = nil
 	gitlab.MockGetMergeRequestResourceStateEvents = nil
 	gitlab.MockGetMergeRequestPipelines = nil
+	gitlab.MockGetPipelineJobs = nil
 	gitlab.MockGetOpenMergeRequestByRefs = nil
 	gitlab.MockUpdateMergeRequest = nil
 	gitlab.MockCreateMergeRequestNote = nil
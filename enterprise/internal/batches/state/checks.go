@@ -0,0 +1,210 @@
+package state
+
+import (
+	"sort"
+	"time"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+)
+
+// computeChecks computes the individual checks on the latest commit of the
+// changeset, based on the current synced checks and any webhook events that
+// have arrived after the most recent sync. The checks are sorted by name.
+func computeChecks(c *btypes.Changeset, events ChangesetEvents) []btypes.ChangesetCheck {
+	var checks map[string]btypes.ChangesetCheck
+	switch m := c.Metadata.(type) {
+	case *github.PullRequest:
+		checks = computeGitHubChecks(c.UpdatedAt, m, events)
+
+	case *bitbucketserver.PullRequest:
+		checks = computeBitbucketBuildChecks(c.UpdatedAt, m, events)
+
+	case *gitlab.MergeRequest:
+		checks = computeGitLabChecks(c.UpdatedAt, m, events)
+	}
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	result := make([]btypes.ChangesetCheck, 0, len(checks))
+	for _, check := range checks {
+		result = append(result, check)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func computeGitHubChecks(lastSynced time.Time, pr *github.PullRequest, events []*btypes.ChangesetEvent) map[string]btypes.ChangesetCheck {
+	// Like in computeGitHubCheckState, we only consider the latest commit.
+	var latestCommitTime time.Time
+	var latestOID string
+	checksPerContext := make(map[string]btypes.ChangesetCheck)
+	checksPerCheckRun := make(map[string]btypes.ChangesetCheck)
+
+	if len(pr.Commits.Nodes) > 0 {
+		commit := pr.Commits.Nodes[0]
+		latestCommitTime = commit.Commit.CommittedDate
+		latestOID = commit.Commit.OID
+		for _, c := range commit.Commit.Status.Contexts {
+			checksPerContext[c.Context] = btypes.ChangesetCheck{
+				Name:  c.Context,
+				State: parseGithubCheckState(c.State),
+				URL:   c.TargetURL,
+			}
+		}
+		for _, s := range commit.Commit.CheckSuites.Nodes {
+			for _, r := range s.CheckRuns.Nodes {
+				if r.Name == "" {
+					continue
+				}
+				checksPerCheckRun[r.Name] = btypes.ChangesetCheck{
+					Name:  r.Name,
+					State: parseGithubCheckSuiteState(r.Status, r.Conclusion),
+					URL:   r.DetailsURL,
+				}
+			}
+		}
+	}
+
+	var statuses []*github.CommitStatus
+	for _, e := range events {
+		switch m := e.Metadata.(type) {
+		case *github.CommitStatus:
+			if m.ReceivedAt.After(lastSynced) {
+				statuses = append(statuses, m)
+			}
+		case *github.PullRequestCommit:
+			if m.Commit.CommittedDate.After(latestCommitTime) {
+				latestCommitTime = m.Commit.CommittedDate
+				latestOID = m.Commit.OID
+				// The commit statuses are now out of date.
+				for k := range checksPerContext {
+					delete(checksPerContext, k)
+				}
+			}
+		case *github.CheckRun:
+			// Check runs received before we recorded their names can't be
+			// matched to a check.
+			if m.ReceivedAt.After(lastSynced) && m.Name != "" {
+				checksPerCheckRun[m.Name] = btypes.ChangesetCheck{
+					Name:  m.Name,
+					State: parseGithubCheckSuiteState(m.Status, m.Conclusion),
+					URL:   m.DetailsURL,
+				}
+			}
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ReceivedAt.Before(statuses[j].ReceivedAt)
+	})
+	for _, s := range statuses {
+		if s.SHA != latestOID {
+			continue
+		}
+		checksPerContext[s.Context] = btypes.ChangesetCheck{
+			Name:  s.Context,
+			State: parseGithubCheckState(s.State),
+			URL:   s.TargetURL,
+		}
+	}
+
+	for name, check := range checksPerCheckRun {
+		checksPerContext[name] = check
+	}
+	return checksPerContext
+}
+
+func computeBitbucketBuildChecks(lastSynced time.Time, pr *bitbucketserver.PullRequest, events []*btypes.ChangesetEvent) map[string]btypes.ChangesetCheck {
+	var latestCommit bitbucketserver.Commit
+	for _, c := range pr.Commits {
+		if latestCommit.CommitterTimestamp <= c.CommitterTimestamp {
+			latestCommit = *c
+		}
+	}
+
+	checks := make(map[string]btypes.ChangesetCheck)
+	add := func(status *bitbucketserver.CommitStatus) {
+		check := bitbucketBuildCheck(status)
+		checks[check.Name] = check
+	}
+
+	// Checks from last sync
+	for _, status := range pr.CommitStatus {
+		add(status)
+	}
+
+	// Add any events we've received since our last sync
+	for _, e := range events {
+		m, ok := e.Metadata.(*bitbucketserver.CommitStatus)
+		if !ok || m.Commit != latestCommit.ID {
+			continue
+		}
+		if unixMilliToTime(m.Status.DateAdded).Before(lastSynced) {
+			continue
+		}
+		add(m)
+	}
+
+	return checks
+}
+
+// bitbucketBuildCheck returns the check for the given build status. Builds are
+// identified by their name, falling back to their key.
+func bitbucketBuildCheck(status *bitbucketserver.CommitStatus) btypes.ChangesetCheck {
+	name := status.Status.Name
+	if name == "" {
+		name = status.Status.Key
+	}
+	return btypes.ChangesetCheck{
+		Name:  name,
+		State: parseBitbucketBuildState(status.Status.State),
+		URL:   status.Status.Url,
+	}
+}
+
+func computeGitLabChecks(lastSynced time.Time, mr *gitlab.MergeRequest, events []*btypes.ChangesetEvent) map[string]btypes.ChangesetCheck {
+	// Like in computeGitLabCheckState, the latest pipeline wins: the checks
+	// are the jobs of that pipeline.
+	var latest *gitlab.Pipeline
+	for _, e := range events {
+		if m, ok := e.Metadata.(*gitlab.Pipeline); ok {
+			if latest == nil || latest.CreatedAt.Before(m.CreatedAt.Time) {
+				latest = m
+			}
+		}
+	}
+
+	if latest == nil || latest.CreatedAt.Before(lastSynced) {
+		latest = nil
+		for _, p := range mr.Pipelines {
+			if latest == nil || p.CreatedAt.After(latest.CreatedAt.Time) {
+				latest = p
+			}
+		}
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	// Sort the jobs by ID, so that retried jobs overwrite the earlier runs
+	// with the same name.
+	jobs := make([]*gitlab.Job, len(latest.Jobs))
+	copy(jobs, latest.Jobs)
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+	checks := make(map[string]btypes.ChangesetCheck, len(jobs))
+	for _, j := range jobs {
+		checks[j.Name] = btypes.ChangesetCheck{
+			Name:  j.Name,
+			State: parseGitLabPipelineStatus(j.Status),
+			URL:   j.WebURL,
+		}
+	}
+	return checks
+}
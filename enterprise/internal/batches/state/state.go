@@ -31,6 +31,7 @@ func SetDerivedState(ctx context.Context, repoStore *database.RepoStore, c *btyp
 	sort.Sort(events)
 
 	c.ExternalCheckState = computeCheckState(c, events)
+	c.ExternalChecks = computeChecks(c, events)
 
 	history, err := computeHistory(c, events)
 	if err != nil {
@@ -406,6 +406,111 @@ func TestComputeGitLabCheckState(t *testing.T) {
 	})
 }
 
+func TestComputeChecks(t *testing.T) {
+	t.Parallel()
+
+	now := timeutil.Now()
+	lastSynced := now.Add(-1 * time.Minute)
+
+	t.Run("github", func(t *testing.T) {
+		c := githubChangeset(lastSynced, "OPEN")
+		events := []*btypes.ChangesetEvent{
+			{
+				Kind: btypes.ChangesetEventKindCommitStatus,
+				Metadata: &github.CommitStatus{
+					Context:    "ci/build",
+					State:      "PENDING",
+					TargetURL:  "https://ci.example.com/1",
+					ReceivedAt: now.Add(-2 * time.Minute),
+				},
+			},
+			{
+				Kind: btypes.ChangesetEventKindCommitStatus,
+				Metadata: &github.CommitStatus{
+					Context:    "ci/build",
+					State:      "SUCCESS",
+					TargetURL:  "https://ci.example.com/2",
+					ReceivedAt: now,
+				},
+			},
+			{
+				Kind: btypes.ChangesetEventKindCheckRun,
+				Metadata: &github.CheckRun{
+					ID:         "cr1",
+					Name:       "lint",
+					DetailsURL: "https://github.com/checks/1",
+					Status:     "COMPLETED",
+					Conclusion: "FAILURE",
+					ReceivedAt: now,
+				},
+			},
+		}
+
+		have := computeChecks(c, events)
+		want := []btypes.ChangesetCheck{
+			{Name: "ci/build", State: btypes.ChangesetCheckStatePassed, URL: "https://ci.example.com/2"},
+			{Name: "lint", State: btypes.ChangesetCheckStateFailed, URL: "https://github.com/checks/1"},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong checks (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("bitbucket server", func(t *testing.T) {
+		c := bitbucketChangeset(lastSynced, "OPEN", "")
+		c.Metadata.(*bitbucketserver.PullRequest).CommitStatus = []*bitbucketserver.CommitStatus{
+			{Status: bitbucketserver.BuildStatus{Key: "build", State: "INPROGRESS"}},
+			{Status: bitbucketserver.BuildStatus{Key: "tests", Name: "Unit tests", State: "FAILED", Url: "https://ci.example.com/tests"}},
+		}
+
+		have := computeChecks(c, nil)
+		want := []btypes.ChangesetCheck{
+			{Name: "Unit tests", State: btypes.ChangesetCheckStateFailed, URL: "https://ci.example.com/tests"},
+			{Name: "build", State: btypes.ChangesetCheckStatePending},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong checks (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		c := gitLabChangeset(lastSynced, gitlab.MergeRequestStateOpened, nil)
+		c.Metadata.(*gitlab.MergeRequest).Pipelines = []*gitlab.Pipeline{
+			{
+				ID:        1,
+				CreatedAt: gitlab.Time{Time: now.Add(-10 * time.Minute)},
+				Jobs: []*gitlab.Job{
+					{ID: 1, Name: "build", Status: gitlab.PipelineStatusFailed},
+				},
+			},
+			{
+				ID:        2,
+				CreatedAt: gitlab.Time{Time: now.Add(-5 * time.Minute)},
+				Jobs: []*gitlab.Job{
+					{ID: 4, Name: "build", Status: gitlab.PipelineStatusSuccess, WebURL: "https://gitlab.com/jobs/4"},
+					{ID: 3, Name: "build", Status: gitlab.PipelineStatusFailed, WebURL: "https://gitlab.com/jobs/3"},
+					{ID: 5, Name: "deploy", Status: gitlab.PipelineStatusRunning},
+				},
+			},
+		}
+
+		have := computeChecks(c, nil)
+		want := []btypes.ChangesetCheck{
+			{Name: "build", State: btypes.ChangesetCheckStatePassed, URL: "https://gitlab.com/jobs/4"},
+			{Name: "deploy", State: btypes.ChangesetCheckStatePending},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong checks (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("no checks", func(t *testing.T) {
+		if have := computeChecks(githubChangeset(lastSynced, "OPEN"), nil); have != nil {
+			t.Errorf("expected no checks, got %+v", have)
+		}
+	})
+}
+
 func TestComputeReviewState(t *testing.T) {
 	t.Parallel()
 
@@ -405,6 +405,14 @@ type ListBatchChangesOpts struct {
 	NamespaceOrgID  int32
 
 	RepoID api.RepoID
+
+	// OnlyAutoMerge only returns batch changes whose current batch spec
+	// configures autoMerge.
+	OnlyAutoMerge bool
+
+	// OnlyRerunOnBaseChange only returns batch changes whose current batch
+	// spec configures rerunOnBaseChange.
+	OnlyRerunOnBaseChange bool
 }
 
 // ListBatchChanges lists batch changes with the given filters.
@@ -495,6 +503,18 @@ func listBatchChangesQuery(opts *ListBatchChangesOpts, repoAuthzConds *sqlf.Quer
 		)`, opts.RepoID, repoAuthzConds))
 	}
 
+	if opts.OnlyAutoMerge || opts.OnlyRerunOnBaseChange {
+		joins = append(joins, sqlf.Sprintf("INNER JOIN batch_specs ON batch_specs.id = batch_changes.batch_spec_id"))
+	}
+
+	if opts.OnlyAutoMerge {
+		preds = append(preds, sqlf.Sprintf("batch_specs.spec ? 'autoMerge'"))
+	}
+
+	if opts.OnlyRerunOnBaseChange {
+		preds = append(preds, sqlf.Sprintf("batch_specs.spec ? 'rerunOnBaseChange'"))
+	}
+
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("TRUE"))
 	}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// BatchSpecResolutionJobColumns are used by the batch spec resolution job
+// related Store methods and by the workerutil.Worker to load jobs.
+var BatchSpecResolutionJobColumns = SQLColumns{
+	"batch_spec_resolution_jobs.id",
+
+	"batch_spec_resolution_jobs.batch_spec_id",
+	"batch_spec_resolution_jobs.allow_unsupported",
+	"batch_spec_resolution_jobs.allow_ignored",
+
+	"batch_spec_resolution_jobs.state",
+	"batch_spec_resolution_jobs.failure_message",
+	"batch_spec_resolution_jobs.started_at",
+	"batch_spec_resolution_jobs.finished_at",
+	"batch_spec_resolution_jobs.process_after",
+	"batch_spec_resolution_jobs.num_resets",
+	"batch_spec_resolution_jobs.num_failures",
+	"batch_spec_resolution_jobs.execution_logs",
+	"batch_spec_resolution_jobs.worker_hostname",
+
+	"batch_spec_resolution_jobs.created_at",
+	"batch_spec_resolution_jobs.updated_at",
+}
+
+var batchSpecResolutionJobInsertColumns = []*sqlf.Query{
+	sqlf.Sprintf("batch_spec_id"),
+	sqlf.Sprintf("allow_unsupported"),
+	sqlf.Sprintf("allow_ignored"),
+	sqlf.Sprintf("created_at"),
+	sqlf.Sprintf("updated_at"),
+}
+
+// CreateBatchSpecResolutionJob creates the given BatchSpecResolutionJob.
+func (s *Store) CreateBatchSpecResolutionJob(ctx context.Context, j *btypes.BatchSpecResolutionJob) (err error) {
+	ctx, endObservation := s.operations.createBatchSpecResolutionJob.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchSpecID", int(j.BatchSpecID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = s.now()
+	}
+
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = j.CreatedAt
+	}
+
+	q := createBatchSpecResolutionJobQuery(j)
+	return s.query(ctx, q, func(sc scanner) error { return scanBatchSpecResolutionJob(j, sc) })
+}
+
+var createBatchSpecResolutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_jobs.go:CreateBatchSpecResolutionJob
+INSERT INTO batch_spec_resolution_jobs (%s)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING %s
+`
+
+func createBatchSpecResolutionJobQuery(j *btypes.BatchSpecResolutionJob) *sqlf.Query {
+	return sqlf.Sprintf(
+		createBatchSpecResolutionJobQueryFmtstr,
+		sqlf.Join(batchSpecResolutionJobInsertColumns, ", "),
+		j.BatchSpecID,
+		j.AllowUnsupported,
+		j.AllowIgnored,
+		j.CreatedAt,
+		j.UpdatedAt,
+		sqlf.Join(BatchSpecResolutionJobColumns.ToSqlf(), ", "),
+	)
+}
+
+// GetBatchSpecResolutionJobOpts captures the query options needed for getting
+// a BatchSpecResolutionJob.
+type GetBatchSpecResolutionJobOpts struct {
+	ID          int64
+	BatchSpecID int64
+}
+
+// GetBatchSpecResolutionJob gets a BatchSpecResolutionJob matching the given
+// options. If multiple jobs exist for the same batch spec, the newest one is
+// returned.
+func (s *Store) GetBatchSpecResolutionJob(ctx context.Context, opts GetBatchSpecResolutionJobOpts) (job *btypes.BatchSpecResolutionJob, err error) {
+	ctx, endObservation := s.operations.getBatchSpecResolutionJob.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(opts.ID)),
+		log.Int("batchSpecID", int(opts.BatchSpecID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := getBatchSpecResolutionJobQuery(&opts)
+	var j btypes.BatchSpecResolutionJob
+	err = s.query(ctx, q, func(sc scanner) (err error) {
+		return scanBatchSpecResolutionJob(&j, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if j.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &j, nil
+}
+
+var getBatchSpecResolutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_jobs.go:GetBatchSpecResolutionJob
+SELECT %s FROM batch_spec_resolution_jobs
+WHERE %s
+ORDER BY id DESC
+LIMIT 1
+`
+
+func getBatchSpecResolutionJobQuery(opts *GetBatchSpecResolutionJobOpts) *sqlf.Query {
+	var preds []*sqlf.Query
+
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_resolution_jobs.id = %s", opts.ID))
+	}
+
+	if opts.BatchSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_resolution_jobs.batch_spec_id = %s", opts.BatchSpecID))
+	}
+
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return sqlf.Sprintf(
+		getBatchSpecResolutionJobQueryFmtstr,
+		sqlf.Join(BatchSpecResolutionJobColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+func scanBatchSpecResolutionJob(j *btypes.BatchSpecResolutionJob, s scanner) error {
+	var executionLogs []dbworkerstore.ExecutionLogEntry
+
+	if err := s.Scan(
+		&j.ID,
+		&j.BatchSpecID,
+		&j.AllowUnsupported,
+		&j.AllowIgnored,
+		&j.State,
+		&j.FailureMessage,
+		&dbutil.NullTime{Time: &j.StartedAt},
+		&dbutil.NullTime{Time: &j.FinishedAt},
+		&dbutil.NullTime{Time: &j.ProcessAfter},
+		&j.NumResets,
+		&j.NumFailures,
+		pq.Array(&executionLogs),
+		&j.WorkerHostname,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	for _, entry := range executionLogs {
+		j.ExecutionLogs = append(j.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+	}
+
+	return nil
+}
+
+// ScanFirstBatchSpecResolutionJob scans a slice of batch spec resolution jobs
+// from the rows and returns the first.
+func ScanFirstBatchSpecResolutionJob(rows *sql.Rows, err error) (*btypes.BatchSpecResolutionJob, bool, error) {
+	jobs, err := scanBatchSpecResolutionJobs(rows, err)
+	if err != nil || len(jobs) == 0 {
+		return &btypes.BatchSpecResolutionJob{}, false, err
+	}
+	return jobs[0], true, nil
+}
+
+func scanBatchSpecResolutionJobs(rows *sql.Rows, queryErr error) ([]*btypes.BatchSpecResolutionJob, error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	var jobs []*btypes.BatchSpecResolutionJob
+	err := scanAll(rows, func(sc scanner) (err error) {
+		var j btypes.BatchSpecResolutionJob
+		if err = scanBatchSpecResolutionJob(&j, sc); err != nil {
+			return err
+		}
+		jobs = append(jobs, &j)
+		return nil
+	})
+
+	return jobs, err
+}
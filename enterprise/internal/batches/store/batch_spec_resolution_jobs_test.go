@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func testStoreBatchSpecResolutionJobs(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	jobs := make([]*btypes.BatchSpecResolutionJob, 0, 2)
+	for i := 0; i < cap(jobs); i++ {
+		spec := ct.CreateBatchSpec(t, ctx, s, "resolution-job-"+strconv.Itoa(i), int32(i+1))
+
+		job := &btypes.BatchSpecResolutionJob{
+			BatchSpecID:      spec.ID,
+			AllowUnsupported: i == 0,
+			AllowIgnored:     i == 1,
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, job := range jobs {
+			if err := s.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+
+			have := job
+			if have.ID == 0 {
+				t.Fatal("ID should not be zero")
+			}
+
+			want := &btypes.BatchSpecResolutionJob{
+				ID:               have.ID,
+				BatchSpecID:      have.BatchSpecID,
+				AllowUnsupported: have.AllowUnsupported,
+				AllowIgnored:     have.AllowIgnored,
+				State:            btypes.BatchSpecResolutionJobStateQueued,
+				CreatedAt:        clock.Now(),
+				UpdatedAt:        clock.Now(),
+			}
+
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("GetByID", func(t *testing.T) {
+			for i, job := range jobs {
+				t.Run(strconv.Itoa(i), func(t *testing.T) {
+					have, err := s.GetBatchSpecResolutionJob(ctx, GetBatchSpecResolutionJobOpts{ID: job.ID})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, job); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+			}
+		})
+
+		t.Run("GetByBatchSpecID", func(t *testing.T) {
+			for i, job := range jobs {
+				t.Run(strconv.Itoa(i), func(t *testing.T) {
+					have, err := s.GetBatchSpecResolutionJob(ctx, GetBatchSpecResolutionJobOpts{BatchSpecID: job.BatchSpecID})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, job); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+			}
+		})
+
+		t.Run("NoResults", func(t *testing.T) {
+			opts := GetBatchSpecResolutionJobOpts{ID: 0xdeadbeef}
+
+			_, have := s.GetBatchSpecResolutionJob(ctx, opts)
+			want := ErrNoResults
+
+			if have != want {
+				t.Fatalf("have err %v, want %v", have, want)
+			}
+		})
+	})
+}
@@ -0,0 +1,350 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgconn"
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// ErrBatchSpecTemplateNameTaken is returned when a batch spec template with
+// the same name already exists in the namespace.
+var ErrBatchSpecTemplateNameTaken = errors.New("a batch spec template with the given name already exists in the namespace")
+
+// batchSpecTemplateColumns are used by the batch spec template related Store
+// methods to query batch spec templates.
+var batchSpecTemplateColumns = []*sqlf.Query{
+	sqlf.Sprintf("batch_spec_templates.id"),
+	sqlf.Sprintf("batch_spec_templates.name"),
+	sqlf.Sprintf("batch_spec_templates.description"),
+	sqlf.Sprintf("batch_spec_templates.raw_template"),
+	sqlf.Sprintf("batch_spec_templates.inputs"),
+	sqlf.Sprintf("batch_spec_templates.namespace_user_id"),
+	sqlf.Sprintf("batch_spec_templates.namespace_org_id"),
+	sqlf.Sprintf("batch_spec_templates.creator_id"),
+	sqlf.Sprintf("batch_spec_templates.created_at"),
+	sqlf.Sprintf("batch_spec_templates.updated_at"),
+}
+
+// batchSpecTemplateInsertColumns is the list of batch_spec_templates columns
+// that are modified when updating/inserting batch spec templates.
+var batchSpecTemplateInsertColumns = []*sqlf.Query{
+	sqlf.Sprintf("name"),
+	sqlf.Sprintf("description"),
+	sqlf.Sprintf("raw_template"),
+	sqlf.Sprintf("inputs"),
+	sqlf.Sprintf("namespace_user_id"),
+	sqlf.Sprintf("namespace_org_id"),
+	sqlf.Sprintf("creator_id"),
+	sqlf.Sprintf("created_at"),
+	sqlf.Sprintf("updated_at"),
+}
+
+const batchSpecTemplateInsertColsFmt = `(%s, %s, %s, %s, %s, %s, %s, %s, %s)`
+
+// CreateBatchSpecTemplate creates the given BatchSpecTemplate.
+func (s *Store) CreateBatchSpecTemplate(ctx context.Context, t *btypes.BatchSpecTemplate) (err error) {
+	ctx, endObservation := s.operations.createBatchSpecTemplate.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q, err := s.createBatchSpecTemplateQuery(t)
+	if err != nil {
+		return err
+	}
+	err = s.query(ctx, q, func(sc scanner) error { return scanBatchSpecTemplate(t, sc) })
+	return translateBatchSpecTemplateError(err)
+}
+
+var createBatchSpecTemplateQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:CreateBatchSpecTemplate
+INSERT INTO batch_spec_templates (%s)
+VALUES ` + batchSpecTemplateInsertColsFmt + `
+RETURNING %s`
+
+func (s *Store) createBatchSpecTemplateQuery(t *btypes.BatchSpecTemplate) (*sqlf.Query, error) {
+	inputs, err := batchSpecTemplateInputsColumn(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = s.now()
+	}
+
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = t.CreatedAt
+	}
+
+	return sqlf.Sprintf(
+		createBatchSpecTemplateQueryFmtstr,
+		sqlf.Join(batchSpecTemplateInsertColumns, ", "),
+		t.Name,
+		t.Description,
+		t.RawTemplate,
+		inputs,
+		nullInt32Column(t.NamespaceUserID),
+		nullInt32Column(t.NamespaceOrgID),
+		nullInt32Column(t.CreatorID),
+		t.CreatedAt,
+		t.UpdatedAt,
+		sqlf.Join(batchSpecTemplateColumns, ", "),
+	), nil
+}
+
+// UpdateBatchSpecTemplate updates the given BatchSpecTemplate.
+func (s *Store) UpdateBatchSpecTemplate(ctx context.Context, t *btypes.BatchSpecTemplate) (err error) {
+	ctx, endObservation := s.operations.updateBatchSpecTemplate.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(t.ID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q, err := s.updateBatchSpecTemplateQuery(t)
+	if err != nil {
+		return err
+	}
+
+	err = s.query(ctx, q, func(sc scanner) error { return scanBatchSpecTemplate(t, sc) })
+	return translateBatchSpecTemplateError(err)
+}
+
+var updateBatchSpecTemplateQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:UpdateBatchSpecTemplate
+UPDATE batch_spec_templates
+SET (%s) = ` + batchSpecTemplateInsertColsFmt + `
+WHERE id = %s
+RETURNING %s`
+
+func (s *Store) updateBatchSpecTemplateQuery(t *btypes.BatchSpecTemplate) (*sqlf.Query, error) {
+	inputs, err := batchSpecTemplateInputsColumn(t)
+	if err != nil {
+		return nil, err
+	}
+
+	t.UpdatedAt = s.now()
+
+	return sqlf.Sprintf(
+		updateBatchSpecTemplateQueryFmtstr,
+		sqlf.Join(batchSpecTemplateInsertColumns, ", "),
+		t.Name,
+		t.Description,
+		t.RawTemplate,
+		inputs,
+		nullInt32Column(t.NamespaceUserID),
+		nullInt32Column(t.NamespaceOrgID),
+		nullInt32Column(t.CreatorID),
+		t.CreatedAt,
+		t.UpdatedAt,
+		t.ID,
+		sqlf.Join(batchSpecTemplateColumns, ", "),
+	), nil
+}
+
+// DeleteBatchSpecTemplate deletes the BatchSpecTemplate with the given ID.
+func (s *Store) DeleteBatchSpecTemplate(ctx context.Context, id int64) (err error) {
+	ctx, endObservation := s.operations.deleteBatchSpecTemplate.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(id)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Store.Exec(ctx, sqlf.Sprintf(deleteBatchSpecTemplateQueryFmtstr, id))
+}
+
+var deleteBatchSpecTemplateQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:DeleteBatchSpecTemplate
+DELETE FROM batch_spec_templates WHERE id = %s
+`
+
+// GetBatchSpecTemplateOpts captures the query options needed for getting a
+// BatchSpecTemplate.
+type GetBatchSpecTemplateOpts struct {
+	ID int64
+}
+
+// GetBatchSpecTemplate gets a BatchSpecTemplate matching the given options.
+func (s *Store) GetBatchSpecTemplate(ctx context.Context, opts GetBatchSpecTemplateOpts) (t *btypes.BatchSpecTemplate, err error) {
+	ctx, endObservation := s.operations.getBatchSpecTemplate.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(opts.ID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		getBatchSpecTemplateQueryFmtstr,
+		sqlf.Join(batchSpecTemplateColumns, ", "),
+		opts.ID,
+	)
+
+	var tmpl btypes.BatchSpecTemplate
+	err = s.query(ctx, q, func(sc scanner) error {
+		return scanBatchSpecTemplate(&tmpl, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tmpl.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &tmpl, nil
+}
+
+var getBatchSpecTemplateQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:GetBatchSpecTemplate
+SELECT %s FROM batch_spec_templates
+WHERE id = %s
+LIMIT 1
+`
+
+// ListBatchSpecTemplatesOpts captures the query options needed for listing
+// batch spec templates.
+type ListBatchSpecTemplatesOpts struct {
+	LimitOpts
+	Cursor int64
+
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+
+	// OnlySiteWide only returns templates that are not owned by a namespace.
+	OnlySiteWide bool
+
+	// AccessibleToUserID only returns templates that are site-wide, owned by
+	// the given user, or owned by an organization the user is a member of.
+	AccessibleToUserID int32
+}
+
+// ListBatchSpecTemplates lists BatchSpecTemplates with the given filters.
+func (s *Store) ListBatchSpecTemplates(ctx context.Context, opts ListBatchSpecTemplatesOpts) (ts []*btypes.BatchSpecTemplate, next int64, err error) {
+	ctx, endObservation := s.operations.listBatchSpecTemplates.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		listBatchSpecTemplatesQueryFmtstr+opts.LimitOpts.ToDB(),
+		sqlf.Join(batchSpecTemplateColumns, ", "),
+		sqlf.Join(append(batchSpecTemplatesPreds(opts), sqlf.Sprintf("batch_spec_templates.id >= %s", opts.Cursor)), "\n AND "),
+	)
+
+	ts = make([]*btypes.BatchSpecTemplate, 0, opts.DBLimit())
+	err = s.query(ctx, q, func(sc scanner) error {
+		var t btypes.BatchSpecTemplate
+		if err := scanBatchSpecTemplate(&t, sc); err != nil {
+			return err
+		}
+		ts = append(ts, &t)
+		return nil
+	})
+
+	if opts.Limit != 0 && len(ts) == opts.DBLimit() {
+		next = ts[len(ts)-1].ID
+		ts = ts[:len(ts)-1]
+	}
+
+	return ts, next, err
+}
+
+var listBatchSpecTemplatesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:ListBatchSpecTemplates
+SELECT %s FROM batch_spec_templates
+WHERE %s
+ORDER BY id ASC
+`
+
+// CountBatchSpecTemplates returns the number of batch spec templates matching
+// the given filters. Pagination options are ignored.
+func (s *Store) CountBatchSpecTemplates(ctx context.Context, opts ListBatchSpecTemplatesOpts) (count int, err error) {
+	ctx, endObservation := s.operations.countBatchSpecTemplates.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	preds := batchSpecTemplatesPreds(opts)
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return s.queryCount(ctx, sqlf.Sprintf(countBatchSpecTemplatesQueryFmtstr, sqlf.Join(preds, "\n AND ")))
+}
+
+var countBatchSpecTemplatesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_templates.go:CountBatchSpecTemplates
+SELECT COUNT(id)
+FROM batch_spec_templates
+WHERE %s
+`
+
+func batchSpecTemplatesPreds(opts ListBatchSpecTemplatesOpts) []*sqlf.Query {
+	var preds []*sqlf.Query
+
+	if opts.NamespaceUserID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_templates.namespace_user_id = %s", opts.NamespaceUserID))
+	}
+
+	if opts.NamespaceOrgID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_templates.namespace_org_id = %s", opts.NamespaceOrgID))
+	}
+
+	if opts.OnlySiteWide {
+		preds = append(preds, sqlf.Sprintf("batch_spec_templates.namespace_user_id IS NULL AND batch_spec_templates.namespace_org_id IS NULL"))
+	}
+
+	if opts.AccessibleToUserID != 0 {
+		preds = append(preds, sqlf.Sprintf(
+			batchSpecTemplatesAccessibleToUserFmtstr,
+			opts.AccessibleToUserID,
+			opts.AccessibleToUserID,
+		))
+	}
+
+	return preds
+}
+
+const batchSpecTemplatesAccessibleToUserFmtstr = `(
+	(batch_spec_templates.namespace_user_id IS NULL AND batch_spec_templates.namespace_org_id IS NULL)
+	OR batch_spec_templates.namespace_user_id = %s
+	OR batch_spec_templates.namespace_org_id IN (SELECT org_id FROM org_members WHERE user_id = %s)
+)`
+
+func batchSpecTemplateInputsColumn(t *btypes.BatchSpecTemplate) ([]byte, error) {
+	if t.Inputs == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t.Inputs)
+}
+
+func translateBatchSpecTemplateError(err error) error {
+	var e *pgconn.PgError
+	if errors.As(err, &e) && e.ConstraintName == "batch_spec_templates_unique_name" {
+		return ErrBatchSpecTemplateNameTaken
+	}
+	return err
+}
+
+func scanBatchSpecTemplate(t *btypes.BatchSpecTemplate, s scanner) error {
+	var inputs json.RawMessage
+
+	err := s.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Description,
+		&t.RawTemplate,
+		&inputs,
+		&dbutil.NullInt32{N: &t.NamespaceUserID},
+		&dbutil.NullInt32{N: &t.NamespaceOrgID},
+		&dbutil.NullInt32{N: &t.CreatorID},
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "scanning batch spec template")
+	}
+
+	if err := json.Unmarshal(inputs, &t.Inputs); err != nil {
+		return errors.Wrap(err, "scanBatchSpecTemplate: failed to unmarshal inputs")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func testStoreBatchSpecTemplates(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	user := ct.CreateTestUser(t, s.DB(), false)
+	otherUser := ct.CreateTestUser(t, s.DB(), false)
+	orgID := ct.InsertTestOrg(t, s.DB(), "batch-spec-templates")
+	if _, err := database.OrgMembers(s.DB()).Create(ctx, orgID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	templates := []*btypes.BatchSpecTemplate{
+		// Site-wide.
+		{Name: "bump-dependency"},
+		{Name: "bump-dependency", NamespaceUserID: user.ID},
+		{Name: "bump-dependency", NamespaceOrgID: orgID},
+		{Name: "bump-dependency", NamespaceUserID: otherUser.ID},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, tmpl := range templates {
+			tmpl.Description = "Bump a dependency"
+			tmpl.RawTemplate = "name: bump-${{ inputs.dependency }}"
+			tmpl.Inputs = []batcheslib.TemplateInput{{Name: "dependency", Type: batcheslib.TemplateInputTypeString}}
+			tmpl.CreatorID = user.ID
+
+			want := tmpl.Clone()
+			have := tmpl
+
+			if err := s.CreateBatchSpecTemplate(ctx, have); err != nil {
+				t.Fatal(err)
+			}
+
+			if have.ID == 0 {
+				t.Fatal("ID should not be zero")
+			}
+
+			want.ID = have.ID
+			want.CreatedAt = clock.Now()
+			want.UpdatedAt = clock.Now()
+
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
+
+	t.Run("Create duplicate name", func(t *testing.T) {
+		err := s.CreateBatchSpecTemplate(ctx, &btypes.BatchSpecTemplate{
+			Name:            "bump-dependency",
+			RawTemplate:     "name: duplicate",
+			NamespaceUserID: user.ID,
+		})
+		if err != ErrBatchSpecTemplateNameTaken {
+			t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecTemplateNameTaken, err)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := s.CountBatchSpecTemplates(ctx, ListBatchSpecTemplatesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := count, len(templates); have != want {
+			t.Fatalf("have count: %d, want: %d", have, want)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			opts ListBatchSpecTemplatesOpts
+			want []*btypes.BatchSpecTemplate
+		}{
+			"all":                {opts: ListBatchSpecTemplatesOpts{}, want: templates},
+			"site-wide":          {opts: ListBatchSpecTemplatesOpts{OnlySiteWide: true}, want: templates[:1]},
+			"user namespace":     {opts: ListBatchSpecTemplatesOpts{NamespaceUserID: user.ID}, want: templates[1:2]},
+			"org namespace":      {opts: ListBatchSpecTemplatesOpts{NamespaceOrgID: orgID}, want: templates[2:3]},
+			"accessible to user": {opts: ListBatchSpecTemplatesOpts{AccessibleToUserID: user.ID}, want: templates[:3]},
+			"accessible to other user": {
+				opts: ListBatchSpecTemplatesOpts{AccessibleToUserID: otherUser.ID},
+				want: []*btypes.BatchSpecTemplate{templates[0], templates[3]},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, _, err := s.ListBatchSpecTemplates(ctx, tc.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(have, tc.want); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		}
+
+		t.Run("With Limit and Cursor", func(t *testing.T) {
+			var cursor int64
+			for i := 1; i <= len(templates); i++ {
+				opts := ListBatchSpecTemplatesOpts{Cursor: cursor, LimitOpts: LimitOpts{Limit: 1}}
+				have, next, err := s.ListBatchSpecTemplates(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := templates[i-1 : i]
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatalf("opts: %+v, diff: %s", opts, diff)
+				}
+
+				cursor = next
+			}
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		clock.Add(1)
+
+		tmpl := templates[0]
+		tmpl.Description = "Bump a dependency to a version"
+		tmpl.Inputs = append(tmpl.Inputs, batcheslib.TemplateInput{Name: "version", Type: batcheslib.TemplateInputTypeString})
+
+		want := tmpl.Clone()
+		want.UpdatedAt = clock.Now()
+
+		if err := s.UpdateBatchSpecTemplate(ctx, tmpl); err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(tmpl, want); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("ByID", func(t *testing.T) {
+			for _, want := range templates {
+				have, err := s.GetBatchSpecTemplate(ctx, GetBatchSpecTemplateOpts{ID: want.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff := cmp.Diff(have, want); diff != "" {
+					t.Fatal(diff)
+				}
+			}
+		})
+
+		t.Run("NoResults", func(t *testing.T) {
+			_, have := s.GetBatchSpecTemplate(ctx, GetBatchSpecTemplateOpts{ID: 0xdeadbeef})
+			want := ErrNoResults
+
+			if have != want {
+				t.Fatalf("have err %v, want %v", have, want)
+			}
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		for _, tmpl := range templates {
+			if err := s.DeleteBatchSpecTemplate(ctx, tmpl.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		count, err := s.CountBatchSpecTemplates(ctx, ListBatchSpecTemplatesOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := count, 0; have != want {
+			t.Fatalf("have count: %d, want: %d", have, want)
+		}
+	})
+}
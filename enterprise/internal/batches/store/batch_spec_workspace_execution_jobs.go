@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// BatchSpecWorkspaceExecutionJobColumns are used by the batch spec workspace
+// execution job related Store methods and by the executor queue to load jobs.
+var BatchSpecWorkspaceExecutionJobColumns = SQLColumns{
+	"batch_spec_workspace_execution_jobs.id",
+
+	"batch_spec_workspace_execution_jobs.batch_spec_workspace_id",
+
+	"batch_spec_workspace_execution_jobs.state",
+	"batch_spec_workspace_execution_jobs.failure_message",
+	"batch_spec_workspace_execution_jobs.started_at",
+	"batch_spec_workspace_execution_jobs.finished_at",
+	"batch_spec_workspace_execution_jobs.process_after",
+	"batch_spec_workspace_execution_jobs.num_resets",
+	"batch_spec_workspace_execution_jobs.num_failures",
+	"batch_spec_workspace_execution_jobs.execution_logs",
+	"batch_spec_workspace_execution_jobs.worker_hostname",
+
+	"batch_spec_workspace_execution_jobs.created_at",
+	"batch_spec_workspace_execution_jobs.updated_at",
+}
+
+// CreateBatchSpecWorkspaceExecutionJobs creates one queued
+// BatchSpecWorkspaceExecutionJob for each of the given workspace IDs.
+func (s *Store) CreateBatchSpecWorkspaceExecutionJobs(ctx context.Context, workspaceIDs ...int64) (err error) {
+	ctx, endObservation := s.operations.createBatchSpecWorkspaceExecutionJobs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("count", len(workspaceIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if len(workspaceIDs) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	q := sqlf.Sprintf(createBatchSpecWorkspaceExecutionJobsQueryFmtstr, now, now, pq.Array(workspaceIDs))
+	return s.Exec(ctx, q)
+}
+
+var createBatchSpecWorkspaceExecutionJobsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:CreateBatchSpecWorkspaceExecutionJobs
+INSERT INTO batch_spec_workspace_execution_jobs (batch_spec_workspace_id, created_at, updated_at)
+SELECT id, %s, %s FROM batch_spec_workspaces WHERE id = ANY (%s)
+`
+
+// GetBatchSpecWorkspaceExecutionJobOpts captures the query options needed for
+// getting a BatchSpecWorkspaceExecutionJob.
+type GetBatchSpecWorkspaceExecutionJobOpts struct {
+	ID                   int64
+	BatchSpecWorkspaceID int64
+}
+
+// GetBatchSpecWorkspaceExecutionJob gets a BatchSpecWorkspaceExecutionJob
+// matching the given options. If multiple jobs exist for the same workspace,
+// the newest one is returned.
+func (s *Store) GetBatchSpecWorkspaceExecutionJob(ctx context.Context, opts GetBatchSpecWorkspaceExecutionJobOpts) (job *btypes.BatchSpecWorkspaceExecutionJob, err error) {
+	ctx, endObservation := s.operations.getBatchSpecWorkspaceExecutionJob.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(opts.ID)),
+		log.Int("batchSpecWorkspaceID", int(opts.BatchSpecWorkspaceID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := getBatchSpecWorkspaceExecutionJobQuery(&opts)
+	var j btypes.BatchSpecWorkspaceExecutionJob
+	err = s.query(ctx, q, func(sc scanner) (err error) {
+		return scanBatchSpecWorkspaceExecutionJob(&j, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if j.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &j, nil
+}
+
+var getBatchSpecWorkspaceExecutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:GetBatchSpecWorkspaceExecutionJob
+SELECT %s FROM batch_spec_workspace_execution_jobs
+WHERE %s
+ORDER BY id DESC
+LIMIT 1
+`
+
+func getBatchSpecWorkspaceExecutionJobQuery(opts *GetBatchSpecWorkspaceExecutionJobOpts) *sqlf.Query {
+	var preds []*sqlf.Query
+
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspace_execution_jobs.id = %s", opts.ID))
+	}
+
+	if opts.BatchSpecWorkspaceID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspace_execution_jobs.batch_spec_workspace_id = %s", opts.BatchSpecWorkspaceID))
+	}
+
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return sqlf.Sprintf(
+		getBatchSpecWorkspaceExecutionJobQueryFmtstr,
+		sqlf.Join(BatchSpecWorkspaceExecutionJobColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// ListBatchSpecWorkspaceExecutionJobsOpts captures the query options needed for
+// listing batch spec workspace execution jobs.
+type ListBatchSpecWorkspaceExecutionJobsOpts struct {
+	BatchSpecID    int64
+	State          btypes.BatchSpecWorkspaceExecutionJobState
+	WorkerHostname string
+}
+
+// ListBatchSpecWorkspaceExecutionJobs lists batch spec workspace execution
+// jobs with the given filters.
+func (s *Store) ListBatchSpecWorkspaceExecutionJobs(ctx context.Context, opts ListBatchSpecWorkspaceExecutionJobsOpts) (cs []*btypes.BatchSpecWorkspaceExecutionJob, err error) {
+	ctx, endObservation := s.operations.listBatchSpecWorkspaceExecutionJobs.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := listBatchSpecWorkspaceExecutionJobsQuery(opts)
+
+	cs = make([]*btypes.BatchSpecWorkspaceExecutionJob, 0)
+	err = s.query(ctx, q, func(sc scanner) error {
+		var c btypes.BatchSpecWorkspaceExecutionJob
+		if err := scanBatchSpecWorkspaceExecutionJob(&c, sc); err != nil {
+			return err
+		}
+		cs = append(cs, &c)
+		return nil
+	})
+
+	return cs, err
+}
+
+var listBatchSpecWorkspaceExecutionJobsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:ListBatchSpecWorkspaceExecutionJobs
+SELECT %s FROM batch_spec_workspace_execution_jobs
+INNER JOIN batch_spec_workspaces ON batch_spec_workspaces.id = batch_spec_workspace_execution_jobs.batch_spec_workspace_id
+WHERE %s
+ORDER BY batch_spec_workspace_execution_jobs.id ASC
+`
+
+func listBatchSpecWorkspaceExecutionJobsQuery(opts ListBatchSpecWorkspaceExecutionJobsOpts) *sqlf.Query {
+	preds := []*sqlf.Query{}
+
+	if opts.BatchSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.batch_spec_id = %s", opts.BatchSpecID))
+	}
+
+	if opts.State != "" {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspace_execution_jobs.state = %s", opts.State))
+	}
+
+	if opts.WorkerHostname != "" {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspace_execution_jobs.worker_hostname = %s", opts.WorkerHostname))
+	}
+
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return sqlf.Sprintf(
+		listBatchSpecWorkspaceExecutionJobsQueryFmtstr,
+		sqlf.Join(BatchSpecWorkspaceExecutionJobColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// RetryBatchSpecWorkspaceExecutionJob re-enqueues the most recent execution
+// job of the given workspace, if it ended in an errored or failed state.
+func (s *Store) RetryBatchSpecWorkspaceExecutionJob(ctx context.Context, workspaceID int64) (job *btypes.BatchSpecWorkspaceExecutionJob, err error) {
+	ctx, endObservation := s.operations.retryBatchSpecWorkspaceExecutionJob.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchSpecWorkspaceID", int(workspaceID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := sqlf.Sprintf(
+		retryBatchSpecWorkspaceExecutionJobQueryFmtstr,
+		workspaceID,
+		btypes.BatchSpecWorkspaceExecutionJobStateQueued,
+		s.now(),
+		btypes.BatchSpecWorkspaceExecutionJobStateErrored,
+		btypes.BatchSpecWorkspaceExecutionJobStateFailed,
+		sqlf.Join(BatchSpecWorkspaceExecutionJobColumns.ToSqlf(), ", "),
+	)
+
+	var j btypes.BatchSpecWorkspaceExecutionJob
+	err = s.query(ctx, q, func(sc scanner) error { return scanBatchSpecWorkspaceExecutionJob(&j, sc) })
+	if err != nil {
+		return nil, err
+	}
+
+	if j.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &j, nil
+}
+
+var retryBatchSpecWorkspaceExecutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspace_execution_jobs.go:RetryBatchSpecWorkspaceExecutionJob
+WITH candidate AS (
+	SELECT
+		id
+	FROM
+		batch_spec_workspace_execution_jobs
+	WHERE
+		batch_spec_workspace_id = %s
+	ORDER BY id DESC
+	LIMIT 1
+)
+UPDATE
+	batch_spec_workspace_execution_jobs
+SET
+	state = %s,
+	failure_message = NULL,
+	started_at = NULL,
+	finished_at = NULL,
+	process_after = NULL,
+	num_resets = 0,
+	num_failures = 0,
+	execution_logs = NULL,
+	worker_hostname = '',
+	updated_at = %s
+WHERE
+	id IN (SELECT id FROM candidate)
+	AND
+	-- Only jobs that ended unsuccessfully can be retried.
+	state IN (%s, %s)
+RETURNING %s
+`
+
+func scanBatchSpecWorkspaceExecutionJob(j *btypes.BatchSpecWorkspaceExecutionJob, s scanner) error {
+	var executionLogs []dbworkerstore.ExecutionLogEntry
+
+	if err := s.Scan(
+		&j.ID,
+		&j.BatchSpecWorkspaceID,
+		&j.State,
+		&j.FailureMessage,
+		&dbutil.NullTime{Time: &j.StartedAt},
+		&dbutil.NullTime{Time: &j.FinishedAt},
+		&dbutil.NullTime{Time: &j.ProcessAfter},
+		&j.NumResets,
+		&j.NumFailures,
+		pq.Array(&executionLogs),
+		&j.WorkerHostname,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	for _, entry := range executionLogs {
+		j.ExecutionLogs = append(j.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+	}
+
+	return nil
+}
+
+// ScanFirstBatchSpecWorkspaceExecutionJob scans a slice of batch spec
+// workspace execution jobs from the rows and returns the first.
+func ScanFirstBatchSpecWorkspaceExecutionJob(rows *sql.Rows, err error) (*btypes.BatchSpecWorkspaceExecutionJob, bool, error) {
+	jobs, err := scanBatchSpecWorkspaceExecutionJobs(rows, err)
+	if err != nil || len(jobs) == 0 {
+		return &btypes.BatchSpecWorkspaceExecutionJob{}, false, err
+	}
+	return jobs[0], true, nil
+}
+
+func scanBatchSpecWorkspaceExecutionJobs(rows *sql.Rows, queryErr error) ([]*btypes.BatchSpecWorkspaceExecutionJob, error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	var jobs []*btypes.BatchSpecWorkspaceExecutionJob
+	err := scanAll(rows, func(sc scanner) (err error) {
+		var j btypes.BatchSpecWorkspaceExecutionJob
+		if err = scanBatchSpecWorkspaceExecutionJob(&j, sc); err != nil {
+			return err
+		}
+		jobs = append(jobs, &j)
+		return nil
+	})
+
+	return jobs, err
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreBatchSpecWorkspaceExecutionJobs(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := ct.CreateBatchSpec(t, ctx, s, "workspace-execution-jobs", 1)
+
+	workspaces := make([]*btypes.BatchSpecWorkspace, 0, 2)
+	for i := 0; i < cap(workspaces); i++ {
+		workspaces = append(workspaces, &btypes.BatchSpecWorkspace{
+			BatchSpecID: spec.ID,
+			RepoID:      repo.ID,
+			Branch:      "refs/heads/main",
+			Commit:      "d34db33f",
+			FileMatches: []string{},
+		})
+	}
+	if err := s.CreateBatchSpecWorkspace(ctx, workspaces...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		if err := s.CreateBatchSpecWorkspaceExecutionJobs(ctx, workspaces[0].ID, workspaces[1].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, w := range workspaces {
+			have, err := s.GetBatchSpecWorkspaceExecutionJob(ctx, GetBatchSpecWorkspaceExecutionJobOpts{BatchSpecWorkspaceID: w.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := &btypes.BatchSpecWorkspaceExecutionJob{
+				ID:                   have.ID,
+				BatchSpecWorkspaceID: w.ID,
+				State:                btypes.BatchSpecWorkspaceExecutionJobStateQueued,
+				CreatedAt:            clock.Now(),
+				UpdatedAt:            clock.Now(),
+			}
+
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		have, err := s.ListBatchSpecWorkspaceExecutionJobs(ctx, ListBatchSpecWorkspaceExecutionJobsOpts{BatchSpecID: spec.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != len(workspaces) {
+			t.Fatalf("wrong number of jobs returned. want=%d, have=%d", len(workspaces), len(have))
+		}
+
+		have, err = s.ListBatchSpecWorkspaceExecutionJobs(ctx, ListBatchSpecWorkspaceExecutionJobsOpts{
+			BatchSpecID: spec.ID,
+			State:       btypes.BatchSpecWorkspaceExecutionJobStateCompleted,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("wrong number of jobs returned. want=%d, have=%d", 0, len(have))
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Run("Queued", func(t *testing.T) {
+			_, err := s.RetryBatchSpecWorkspaceExecutionJob(ctx, workspaces[0].ID)
+			if err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+		})
+
+		t.Run("Failed", func(t *testing.T) {
+			w := workspaces[1]
+			if err := s.Exec(ctx, sqlf.Sprintf("UPDATE batch_spec_workspace_execution_jobs SET state = 'failed', failure_message = 'boom', num_failures = 1 WHERE batch_spec_workspace_id = %s", w.ID)); err != nil {
+				t.Fatal(err)
+			}
+
+			have, err := s.RetryBatchSpecWorkspaceExecutionJob(ctx, w.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if have.State != btypes.BatchSpecWorkspaceExecutionJobStateQueued {
+				t.Fatalf("invalid state: have=%q", have.State)
+			}
+			if have.FailureMessage != nil {
+				t.Fatalf("failure message not reset: %q", *have.FailureMessage)
+			}
+			if have.NumFailures != 0 {
+				t.Fatalf("num_failures not reset: %d", have.NumFailures)
+			}
+		})
+	})
+}
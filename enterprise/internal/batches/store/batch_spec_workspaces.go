@@ -0,0 +1,354 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// batchSpecWorkspaceInsertColumns is the list of batch_spec_workspaces
+// columns that are modified in CreateBatchSpecWorkspace.
+var batchSpecWorkspaceInsertColumns = []string{
+	"batch_spec_id",
+	"changeset_spec_ids",
+	"repo_id",
+	"branch",
+	"commit",
+	"path",
+	"file_matches",
+	"only_fetch_workspace",
+	"steps",
+	"created_at",
+	"updated_at",
+}
+
+// BatchSpecWorkspaceColumns are used by the batch spec workspace related Store
+// methods to insert, update and query batch spec workspaces.
+var BatchSpecWorkspaceColumns = SQLColumns{
+	"batch_spec_workspaces.id",
+	"batch_spec_workspaces.batch_spec_id",
+	"batch_spec_workspaces.changeset_spec_ids",
+	"batch_spec_workspaces.repo_id",
+	"batch_spec_workspaces.branch",
+	"batch_spec_workspaces.commit",
+	"batch_spec_workspaces.path",
+	"batch_spec_workspaces.file_matches",
+	"batch_spec_workspaces.only_fetch_workspace",
+	"batch_spec_workspaces.steps",
+	"batch_spec_workspaces.created_at",
+	"batch_spec_workspaces.updated_at",
+}
+
+// CreateBatchSpecWorkspace creates the given batch spec workspaces.
+func (s *Store) CreateBatchSpecWorkspace(ctx context.Context, ws ...*btypes.BatchSpecWorkspace) (err error) {
+	ctx, endObservation := s.operations.createBatchSpecWorkspace.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("count", len(ws)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	inserter := func(inserter *batch.Inserter) error {
+		for _, w := range ws {
+			if w.CreatedAt.IsZero() {
+				w.CreatedAt = s.now()
+			}
+
+			if w.UpdatedAt.IsZero() {
+				w.UpdatedAt = w.CreatedAt
+			}
+
+			changesetSpecIDs, err := marshalChangesetSpecIDs(w.ChangesetSpecIDs)
+			if err != nil {
+				return err
+			}
+
+			fileMatches := w.FileMatches
+			if fileMatches == nil {
+				fileMatches = []string{}
+			}
+
+			steps := w.Steps
+			if steps == nil {
+				steps = []batcheslib.Step{}
+			}
+			marshaledSteps, err := json.Marshal(steps)
+			if err != nil {
+				return err
+			}
+
+			if err := inserter.Insert(
+				ctx,
+				w.BatchSpecID,
+				changesetSpecIDs,
+				w.RepoID,
+				w.Branch,
+				w.Commit,
+				w.Path,
+				pq.Array(fileMatches),
+				w.OnlyFetchWorkspace,
+				marshaledSteps,
+				w.CreatedAt,
+				w.UpdatedAt,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	i := -1
+	return batch.WithInserterWithReturn(
+		ctx,
+		s.Handle().DB(),
+		"batch_spec_workspaces",
+		batchSpecWorkspaceInsertColumns,
+		BatchSpecWorkspaceColumns,
+		func(rows *sql.Rows) error {
+			i++
+			return scanBatchSpecWorkspace(ws[i], rows)
+		},
+		inserter,
+	)
+}
+
+// GetBatchSpecWorkspaceOpts captures the query options needed for getting a BatchSpecWorkspace
+type GetBatchSpecWorkspaceOpts struct {
+	ID              int64
+	ChangesetSpecID int64
+}
+
+// GetBatchSpecWorkspace gets a BatchSpecWorkspace matching the given options.
+func (s *Store) GetBatchSpecWorkspace(ctx context.Context, opts GetBatchSpecWorkspaceOpts) (job *btypes.BatchSpecWorkspace, err error) {
+	ctx, endObservation := s.operations.getBatchSpecWorkspace.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(opts.ID)),
+		log.Int("changesetSpecID", int(opts.ChangesetSpecID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := getBatchSpecWorkspaceQuery(&opts)
+	var w btypes.BatchSpecWorkspace
+	err = s.query(ctx, q, func(sc scanner) (err error) {
+		return scanBatchSpecWorkspace(&w, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if w.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &w, nil
+}
+
+var getBatchSpecWorkspacesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:GetBatchSpecWorkspace
+SELECT %s FROM batch_spec_workspaces
+INNER JOIN repo ON repo.id = batch_spec_workspaces.repo_id
+WHERE %s
+ORDER BY batch_spec_workspaces.id DESC
+LIMIT 1
+`
+
+func getBatchSpecWorkspaceQuery(opts *GetBatchSpecWorkspaceOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+	}
+
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.id = %s", opts.ID))
+	}
+
+	if opts.ChangesetSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.changeset_spec_ids ? %s", strconv.FormatInt(opts.ChangesetSpecID, 10)))
+	}
+
+	return sqlf.Sprintf(
+		getBatchSpecWorkspacesQueryFmtstr,
+		sqlf.Join(BatchSpecWorkspaceColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// ListBatchSpecWorkspacesOpts captures the query options needed for
+// listing batch spec workspaces.
+type ListBatchSpecWorkspacesOpts struct {
+	LimitOpts
+	Cursor int64
+
+	BatchSpecID int64
+	IDs         []int64
+}
+
+// ListBatchSpecWorkspaces lists batch spec workspaces with the given filters.
+func (s *Store) ListBatchSpecWorkspaces(ctx context.Context, opts ListBatchSpecWorkspacesOpts) (cs []*btypes.BatchSpecWorkspace, next int64, err error) {
+	ctx, endObservation := s.operations.listBatchSpecWorkspaces.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	q := listBatchSpecWorkspacesQuery(opts)
+
+	cs = make([]*btypes.BatchSpecWorkspace, 0, opts.DBLimit())
+	err = s.query(ctx, q, func(sc scanner) error {
+		var c btypes.BatchSpecWorkspace
+		if err := scanBatchSpecWorkspace(&c, sc); err != nil {
+			return err
+		}
+		cs = append(cs, &c)
+		return nil
+	})
+
+	if opts.Limit != 0 && len(cs) == opts.DBLimit() {
+		next = cs[len(cs)-1].ID
+		cs = cs[:len(cs)-1]
+	}
+
+	return cs, next, err
+}
+
+var listBatchSpecWorkspacesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:ListBatchSpecWorkspaces
+SELECT %s FROM batch_spec_workspaces
+INNER JOIN repo ON repo.id = batch_spec_workspaces.repo_id
+WHERE %s
+ORDER BY id ASC
+`
+
+func listBatchSpecWorkspacesQuery(opts ListBatchSpecWorkspacesOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+	}
+
+	if opts.BatchSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.batch_spec_id = %s", opts.BatchSpecID))
+	}
+
+	if len(opts.IDs) != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.id = ANY (%s)", pq.Array(opts.IDs)))
+	}
+
+	if opts.Cursor > 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.id >= %s", opts.Cursor))
+	}
+
+	return sqlf.Sprintf(
+		listBatchSpecWorkspacesQueryFmtstr+opts.LimitOpts.ToDB(),
+		sqlf.Join(BatchSpecWorkspaceColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// SetBatchSpecWorkspaceChangesetSpecs records the IDs of the changeset specs
+// that were created by executing the given workspace.
+func (s *Store) SetBatchSpecWorkspaceChangesetSpecs(ctx context.Context, id int64, changesetSpecIDs []int64) (err error) {
+	ctx, endObservation := s.operations.setBatchSpecWorkspaceChangesetSpecs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(id)),
+		log.Int("count", len(changesetSpecIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	marshaled, err := marshalChangesetSpecIDs(changesetSpecIDs)
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(setBatchSpecWorkspaceChangesetSpecsQueryFmtstr, marshaled, s.now(), id))
+}
+
+var setBatchSpecWorkspaceChangesetSpecsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:SetBatchSpecWorkspaceChangesetSpecs
+UPDATE batch_spec_workspaces
+SET changeset_spec_ids = %s, updated_at = %s
+WHERE id = %s
+`
+
+// SetBatchSpecWorkspaceCommit updates the commit at which the given workspace
+// is executed, so that it can be re-executed against a newer base commit.
+func (s *Store) SetBatchSpecWorkspaceCommit(ctx context.Context, id int64, commit string) (err error) {
+	ctx, endObservation := s.operations.setBatchSpecWorkspaceCommit.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("ID", int(id)),
+		log.String("commit", commit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Exec(ctx, sqlf.Sprintf(setBatchSpecWorkspaceCommitQueryFmtstr, commit, s.now(), id))
+}
+
+var setBatchSpecWorkspaceCommitQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:SetBatchSpecWorkspaceCommit
+UPDATE batch_spec_workspaces
+SET commit = %s, updated_at = %s
+WHERE id = %s
+`
+
+func scanBatchSpecWorkspace(w *btypes.BatchSpecWorkspace, s scanner) error {
+	var changesetSpecIDs, steps json.RawMessage
+
+	if err := s.Scan(
+		&w.ID,
+		&w.BatchSpecID,
+		&changesetSpecIDs,
+		&w.RepoID,
+		&w.Branch,
+		&w.Commit,
+		&w.Path,
+		pq.Array(&w.FileMatches),
+		&w.OnlyFetchWorkspace,
+		&steps,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	ids, err := unmarshalChangesetSpecIDs(changesetSpecIDs)
+	if err != nil {
+		return err
+	}
+	w.ChangesetSpecIDs = ids
+
+	return json.Unmarshal(steps, &w.Steps)
+}
+
+// marshalChangesetSpecIDs marshals the given IDs into the JSON object that's
+// stored in batch_spec_workspaces.changeset_spec_ids.
+func marshalChangesetSpecIDs(ids []int64) ([]byte, error) {
+	m := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		m[strconv.FormatInt(id, 10)] = struct{}{}
+	}
+	return json.Marshal(m)
+}
+
+func unmarshalChangesetSpecIDs(raw json.RawMessage) ([]int64, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var m map[string]struct{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(m))
+	for k := range m {
+		id, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func testStoreBatchSpecWorkspaces(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := ct.CreateBatchSpec(t, ctx, s, "workspaces", 1)
+
+	workspaces := make([]*btypes.BatchSpecWorkspace, 0, 3)
+	for i := 0; i < cap(workspaces); i++ {
+		w := &btypes.BatchSpecWorkspace{
+			BatchSpecID:      spec.ID,
+			ChangesetSpecIDs: []int64{},
+			RepoID:           repo.ID,
+			Branch:           "refs/heads/main",
+			Commit:           "d34db33f",
+			Path:             "sub/dir/ectory-" + strconv.Itoa(i),
+			FileMatches:      []string{"a.go", "a/b/horse.go"},
+			Steps: []batcheslib.Step{
+				{Run: "echo lol", Container: "alpine:3"},
+			},
+		}
+
+		workspaces = append(workspaces, w)
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		if err := s.CreateBatchSpecWorkspace(ctx, workspaces...); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, have := range workspaces {
+			if have.ID == 0 {
+				t.Fatal("ID should not be zero")
+			}
+
+			want := have.Clone()
+			want.CreatedAt = clock.Now()
+			want.UpdatedAt = clock.Now()
+
+			if diff := cmp.Diff(have, want); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("GetByID", func(t *testing.T) {
+			for i, w := range workspaces {
+				t.Run(strconv.Itoa(i), func(t *testing.T) {
+					have, err := s.GetBatchSpecWorkspace(ctx, GetBatchSpecWorkspaceOpts{ID: w.ID})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if diff := cmp.Diff(have, w); diff != "" {
+						t.Fatal(diff)
+					}
+				})
+			}
+		})
+
+		t.Run("NoResults", func(t *testing.T) {
+			opts := GetBatchSpecWorkspaceOpts{ID: 0xdeadbeef}
+
+			_, have := s.GetBatchSpecWorkspace(ctx, opts)
+			want := ErrNoResults
+
+			if have != want {
+				t.Fatalf("have err %v, want %v", have, want)
+			}
+		})
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Run("ByBatchSpecID", func(t *testing.T) {
+			have, _, err := s.ListBatchSpecWorkspaces(ctx, ListBatchSpecWorkspacesOpts{BatchSpecID: spec.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(have, workspaces); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		t.Run("ByIDs", func(t *testing.T) {
+			have, _, err := s.ListBatchSpecWorkspaces(ctx, ListBatchSpecWorkspacesOpts{IDs: []int64{workspaces[1].ID}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(have, workspaces[1:2]); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
+		t.Run("WithLimit", func(t *testing.T) {
+			have, next, err := s.ListBatchSpecWorkspaces(ctx, ListBatchSpecWorkspacesOpts{
+				BatchSpecID: spec.ID,
+				LimitOpts:   LimitOpts{Limit: 2},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(have, workspaces[:2]); diff != "" {
+				t.Fatal(diff)
+			}
+
+			if have, want := next, workspaces[2].ID; have != want {
+				t.Fatalf("wrong next cursor. want=%d, have=%d", want, have)
+			}
+		})
+	})
+
+	t.Run("SetBatchSpecWorkspaceChangesetSpecs", func(t *testing.T) {
+		w := workspaces[0]
+		if err := s.SetBatchSpecWorkspaceChangesetSpecs(ctx, w.ID, []int64{3, 1, 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetBatchSpecWorkspace(ctx, GetBatchSpecWorkspaceOpts{ID: w.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(have.ChangesetSpecIDs, []int64{1, 2, 3}); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
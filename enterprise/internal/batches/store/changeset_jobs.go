@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
@@ -167,6 +168,49 @@ func getChangesetJobQuery(opts *GetChangesetJobOpts) *sqlf.Query {
 	)
 }
 
+// CountChangesetJobsOpts captures the query options needed for counting
+// changeset jobs.
+type CountChangesetJobsOpts struct {
+	BatchChangeID int64
+	JobType       btypes.ChangesetJobType
+	CreatedAfter  time.Time
+}
+
+// CountChangesetJobs returns the number of changeset jobs matching the given
+// options.
+func (s *Store) CountChangesetJobs(ctx context.Context, opts CountChangesetJobsOpts) (count int, err error) {
+	ctx, endObservation := s.operations.countChangesetJobs.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchChangeID", int(opts.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.queryCount(ctx, countChangesetJobsQuery(&opts))
+}
+
+var countChangesetJobsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_jobs.go:CountChangesetJobs
+SELECT COUNT(changeset_jobs.id) FROM changeset_jobs
+WHERE %s
+`
+
+func countChangesetJobsQuery(opts *CountChangesetJobsOpts) *sqlf.Query {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+
+	if opts.BatchChangeID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.batch_change_id = %s", opts.BatchChangeID))
+	}
+
+	if opts.JobType != "" {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.job_type = %s", opts.JobType))
+	}
+
+	if !opts.CreatedAfter.IsZero() {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.created_at > %s", opts.CreatedAfter))
+	}
+
+	return sqlf.Sprintf(countChangesetJobsQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
 func scanChangesetJob(c *btypes.ChangesetJob, s scanner) error {
 	var raw json.RawMessage
 	if err := s.Scan(
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -107,4 +108,29 @@ func testStoreChangesetJobs(t *testing.T, ctx context.Context, s *Store, clock c
 			}
 		})
 	})
+	t.Run("Count", func(t *testing.T) {
+		tcs := []struct {
+			opts CountChangesetJobsOpts
+			want int
+		}{
+			{opts: CountChangesetJobsOpts{}, want: len(jobs)},
+			{opts: CountChangesetJobsOpts{BatchChangeID: jobs[0].BatchChangeID}, want: 1},
+			{opts: CountChangesetJobsOpts{JobType: btypes.ChangesetJobTypeComment}, want: len(jobs)},
+			{opts: CountChangesetJobsOpts{JobType: btypes.ChangesetJobTypeMerge}, want: 0},
+			{opts: CountChangesetJobsOpts{CreatedAfter: clock.Now().Add(-1 * time.Minute)}, want: len(jobs)},
+			{opts: CountChangesetJobsOpts{CreatedAfter: clock.Now()}, want: 0},
+		}
+
+		for i, tc := range tcs {
+			t.Run(strconv.Itoa(i), func(t *testing.T) {
+				have, err := s.CountChangesetJobs(ctx, tc.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have != tc.want {
+					t.Fatalf("wrong count. want=%d, have=%d", tc.want, have)
+				}
+			})
+		}
+	})
 }
@@ -427,6 +427,10 @@ WHERE
   AND
   -- and it was never attached to a batch_spec
   batch_spec_id IS NULL
+  AND
+  -- and it is not attached to a changeset, which happens when it was
+  -- superseded by a spec created for a newer base commit
+  NOT EXISTS(SELECT 1 FROM changesets WHERE current_spec_id = cspecs.id OR previous_spec_id = cspecs.id)
 )
 OR
 (
@@ -45,6 +45,7 @@ var ChangesetColumns = []*sqlf.Query{
 	sqlf.Sprintf("changesets.external_state"),
 	sqlf.Sprintf("changesets.external_review_state"),
 	sqlf.Sprintf("changesets.external_check_state"),
+	sqlf.Sprintf("changesets.external_checks"),
 	sqlf.Sprintf("changesets.diff_stat_added"),
 	sqlf.Sprintf("changesets.diff_stat_changed"),
 	sqlf.Sprintf("changesets.diff_stat_deleted"),
@@ -81,6 +82,7 @@ var changesetInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("external_state"),
 	sqlf.Sprintf("external_review_state"),
 	sqlf.Sprintf("external_check_state"),
+	sqlf.Sprintf("external_checks"),
 	sqlf.Sprintf("diff_stat_added"),
 	sqlf.Sprintf("diff_stat_changed"),
 	sqlf.Sprintf("diff_stat_deleted"),
@@ -115,6 +117,7 @@ var changesetCodeHostStateInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("external_state"),
 	sqlf.Sprintf("external_review_state"),
 	sqlf.Sprintf("external_check_state"),
+	sqlf.Sprintf("external_checks"),
 	sqlf.Sprintf("diff_stat_added"),
 	sqlf.Sprintf("diff_stat_changed"),
 	sqlf.Sprintf("diff_stat_deleted"),
@@ -142,6 +145,11 @@ func (s *Store) changesetWriteQuery(q string, includeID bool, c *btypes.Changese
 		return nil, err
 	}
 
+	externalChecks, err := externalChecksColumn(c)
+	if err != nil {
+		return nil, err
+	}
+
 	// Not being able to find a title is fine, we just have a NULL in the database then.
 	title, _ := c.Title()
 
@@ -162,6 +170,7 @@ func (s *Store) changesetWriteQuery(q string, includeID bool, c *btypes.Changese
 		nullStringColumn(string(c.ExternalState)),
 		nullStringColumn(string(c.ExternalReviewState)),
 		nullStringColumn(string(c.ExternalCheckState)),
+		externalChecks,
 		c.DiffStatAdded,
 		c.DiffStatChanged,
 		c.DiffStatDeleted,
@@ -224,7 +233,7 @@ func (s *Store) CreateChangeset(ctx context.Context, c *btypes.Changeset) (err e
 var createChangesetQueryFmtstr = `
 -- source: enterprise/internal/batches/store.go:CreateChangeset
 INSERT INTO changesets (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s
 `
 
@@ -709,6 +718,47 @@ func (s *Store) enqueueChangesetQuery(cs *btypes.Changeset, resetState, currentS
 	)
 }
 
+// SupersedeChangesetSpec makes newSpecID the current changeset spec of the
+// changeset that currently uses oldSpecID, keeping oldSpecID as its previous
+// spec, and enqueues the changeset with the given reconciler state. The old
+// changeset spec is detached from its batch spec, so it can expire.
+func (s *Store) SupersedeChangesetSpec(ctx context.Context, oldSpecID, newSpecID int64, resetState btypes.ReconcilerState) (err error) {
+	ctx, endObservation := s.operations.supersedeChangesetSpec.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("oldSpecID", int(oldSpecID)),
+		log.Int("newSpecID", int(newSpecID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		supersedeChangesetSpecQueryFmtstr,
+		newSpecID,
+		resetState.ToDB(),
+		s.now(),
+		oldSpecID,
+		oldSpecID,
+	))
+}
+
+var supersedeChangesetSpecQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:SupersedeChangesetSpec
+WITH changeset AS (
+	UPDATE changesets
+	SET
+		previous_spec_id = current_spec_id,
+		current_spec_id = %s,
+		reconciler_state = %s,
+		num_resets = 0,
+		num_failures = 0,
+		failure_message = NULL,
+		updated_at = %s
+	WHERE current_spec_id = %s
+	RETURNING id
+)
+UPDATE changeset_specs
+SET batch_spec_id = NULL
+WHERE id = %s
+`
+
 // UpdateChangeset updates the given Changeset.
 func (s *Store) UpdateChangeset(ctx context.Context, cs *btypes.Changeset) (err error) {
 	ctx, endObservation := s.operations.updateChangeset.With(ctx, &err, observation.Args{LogFields: []log.Field{
@@ -731,7 +781,7 @@ func (s *Store) UpdateChangeset(ctx context.Context, cs *btypes.Changeset) (err
 var updateChangesetQueryFmtstr = `
 -- source: enterprise/internal/batches/store_changesets.go:UpdateChangeset
 UPDATE changesets
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   %s
@@ -826,6 +876,11 @@ func updateChangesetCodeHostStateQuery(c *btypes.Changeset) (*sqlf.Query, error)
 		return nil, err
 	}
 
+	externalChecks, err := externalChecksColumn(c)
+	if err != nil {
+		return nil, err
+	}
+
 	// Not being able to find a title is fine, we just have a NULL in the database then.
 	title, _ := c.Title()
 
@@ -839,6 +894,7 @@ func updateChangesetCodeHostStateQuery(c *btypes.Changeset) (*sqlf.Query, error)
 		nullStringColumn(string(c.ExternalState)),
 		nullStringColumn(string(c.ExternalReviewState)),
 		nullStringColumn(string(c.ExternalCheckState)),
+		externalChecks,
 		c.DiffStatAdded,
 		c.DiffStatChanged,
 		c.DiffStatDeleted,
@@ -855,7 +911,7 @@ func updateChangesetCodeHostStateQuery(c *btypes.Changeset) (*sqlf.Query, error)
 var updateChangesetCodeHostStateQueryFmtstr = `
 -- source: enterprise/internal/batches/store/changesets.go:UpdateChangesetCodeHostState
 UPDATE changesets
-SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+SET (%s) = (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 WHERE id = %s
 RETURNING
   %s
@@ -1124,7 +1180,7 @@ func (n jsonBatchChangeChangesetSet) Value() (driver.Value, error) {
 }
 
 func scanChangeset(t *btypes.Changeset, s scanner) error {
-	var metadata, syncState json.RawMessage
+	var metadata, syncState, externalChecks json.RawMessage
 
 	var (
 		externalState       string
@@ -1149,6 +1205,7 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 		&dbutil.NullString{S: &externalState},
 		&dbutil.NullString{S: &externalReviewState},
 		&dbutil.NullString{S: &externalCheckState},
+		&externalChecks,
 		&t.DiffStatAdded,
 		&t.DiffStatChanged,
 		&t.DiffStatDeleted,
@@ -1197,6 +1254,9 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 	if err = json.Unmarshal(metadata, t.Metadata); err != nil {
 		return errors.Wrapf(err, "scanChangeset: failed to unmarshal %q metadata", t.ExternalServiceType)
 	}
+	if err = unmarshalExternalChecks(externalChecks, t); err != nil {
+		return errors.Wrap(err, "scanChangeset: failed to unmarshal external checks")
+	}
 	if err = json.Unmarshal(syncState, &t.SyncState); err != nil {
 		return errors.Wrapf(err, "scanChangeset: failed to unmarshal sync state: %s", syncState)
 	}
@@ -1375,6 +1435,85 @@ WHERE
 	id = %d
 `
 
+// ListAutoMergeableChangesetsOpts captures the query options needed for
+// listing the changesets of a batch change that can be merged automatically.
+type ListAutoMergeableChangesetsOpts struct {
+	LimitOpts
+	BatchChangeID int64
+	// RetryAfter excludes changesets that had a merge job created after this
+	// point in time, so that failed merges aren't retried on every run.
+	RetryAfter time.Time
+	// Cursor excludes changesets with an ID below it, to page through the
+	// changesets in order.
+	Cursor int64
+}
+
+// ListAutoMergeableChangesets lists the open, approved changesets owned by a
+// batch change whose checks have passed and for which no merge job is
+// currently pending.
+func (s *Store) ListAutoMergeableChangesets(ctx context.Context, opts ListAutoMergeableChangesetsOpts) (cs btypes.Changesets, err error) {
+	ctx, endObservation := s.operations.listAutoMergeableChangesets.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("batchChangeID", int(opts.BatchChangeID)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	q := listAutoMergeableChangesetsQuery(&opts)
+
+	err = s.query(ctx, q, func(sc scanner) error {
+		var c btypes.Changeset
+		if err := scanChangeset(&c, sc); err != nil {
+			return err
+		}
+		cs = append(cs, &c)
+		return nil
+	})
+
+	return cs, err
+}
+
+const listAutoMergeableChangesetsFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:ListAutoMergeableChangesets
+SELECT %s FROM changesets
+INNER JOIN repo ON repo.id = changesets.repo_id
+WHERE
+	repo.deleted_at IS NULL AND
+	changesets.owned_by_batch_change_id = %s AND
+	changesets.id >= %s AND
+	NOT %s AND
+	changesets.publication_state = %s AND
+	changesets.reconciler_state = %s AND
+	changesets.external_state = %s AND
+	changesets.external_review_state = %s AND
+	changesets.external_check_state = %s AND
+	NOT EXISTS (
+		SELECT 1 FROM changeset_jobs
+		WHERE
+			changeset_jobs.changeset_id = changesets.id AND
+			changeset_jobs.job_type = %s AND
+			(changeset_jobs.state IN (%s, %s) OR changeset_jobs.created_at > %s)
+	)
+ORDER BY changesets.id ASC
+`
+
+func listAutoMergeableChangesetsQuery(opts *ListAutoMergeableChangesetsOpts) *sqlf.Query {
+	return sqlf.Sprintf(
+		listAutoMergeableChangesetsFmtstr+opts.LimitOpts.ToDB(),
+		sqlf.Join(ChangesetColumns, ", "),
+		opts.BatchChangeID,
+		opts.Cursor,
+		archivedInBatchChange(strconv.Itoa(int(opts.BatchChangeID))),
+		btypes.ChangesetPublicationStatePublished,
+		btypes.ReconcilerStateCompleted.ToDB(),
+		btypes.ChangesetExternalStateOpen,
+		btypes.ChangesetReviewStateApproved,
+		btypes.ChangesetCheckStatePassed,
+		btypes.ChangesetJobTypeMerge,
+		btypes.ChangesetJobStateQueued.ToDB(),
+		btypes.ChangesetJobStateProcessing.ToDB(),
+		opts.RetryAfter,
+	)
+}
+
 func archivedInBatchChange(batchChangeID string) *sqlf.Query {
 	return sqlf.Sprintf(
 		"(COALESCE((batch_change_ids->%s->>'isArchived')::bool, false) OR COALESCE((batch_change_ids->%s->>'archive')::bool, false))",
@@ -1461,3 +1600,25 @@ func uiPublicationStateColumn(c *btypes.Changeset) *string {
 	}
 	return uiPublicationState
 }
+
+func externalChecksColumn(c *btypes.Changeset) ([]byte, error) {
+	if len(c.ExternalChecks) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c.ExternalChecks)
+}
+
+func unmarshalExternalChecks(data json.RawMessage, c *btypes.Changeset) error {
+	var checks []btypes.ChangesetCheck
+	if err := json.Unmarshal(data, &checks); err != nil {
+		return err
+	}
+	// Leave the field nil for changesets without checks, just like before
+	// they were synced.
+	if len(checks) > 0 {
+		c.ExternalChecks = checks
+	} else {
+		c.ExternalChecks = nil
+	}
+	return nil
+}
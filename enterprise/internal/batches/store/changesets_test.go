@@ -110,6 +110,9 @@ func testStoreChangesets(t *testing.T, ctx context.Context, s *Store, clock ct.C
 				ExternalState:       btypes.ChangesetExternalStateOpen,
 				ExternalReviewState: btypes.ChangesetReviewStateApproved,
 				ExternalCheckState:  btypes.ChangesetCheckStatePassed,
+				ExternalChecks: []btypes.ChangesetCheck{
+					{Name: "ci/build", State: btypes.ChangesetCheckStatePassed, URL: "https://ci.example.com/build"},
+				},
 
 				CurrentSpecID:        int64(i) + 1,
 				PreviousSpecID:       int64(i) + 1,
@@ -1133,6 +1136,7 @@ func testStoreChangesets(t *testing.T, ctx context.Context, s *Store, clock ct.C
 		cs.ExternalState = btypes.ChangesetExternalStateDeleted
 		cs.ExternalReviewState = btypes.ChangesetReviewStateApproved
 		cs.ExternalCheckState = btypes.ChangesetCheckStateFailed
+		cs.ExternalChecks = []btypes.ChangesetCheck{{Name: "lint", State: btypes.ChangesetCheckStateFailed}}
 		cs.DiffStatAdded = intptr(100)
 		cs.DiffStatChanged = intptr(100)
 		cs.DiffStatDeleted = intptr(100)
@@ -2401,3 +2405,128 @@ func TestEnqueueChangesetsToClose(t *testing.T) {
 		ct.ReloadAndAssertChangeset(t, ctx, s, changeset, want)
 	}
 }
+
+func testStoreListAutoMergeableChangesets(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	rs := database.ReposWith(s)
+	es := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, es, extsvc.KindGitHub)
+	deletedRepo := ct.TestRepo(t, es, extsvc.KindGitHub).With(types.Opt.RepoDeletedAt(clock.Now()))
+	if err := rs.Create(ctx, repo, deletedRepo); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Delete(ctx, deletedRepo.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	const batchChangeID = 123
+
+	// mergeable returns options for a changeset that matches all criteria;
+	// the individual cases below then break one of them.
+	mergeable := func() ct.TestChangesetOpts {
+		return ct.TestChangesetOpts{
+			Repo:                repo.ID,
+			BatchChange:         batchChangeID,
+			OwnedByBatchChange:  batchChangeID,
+			PublicationState:    btypes.ChangesetPublicationStatePublished,
+			ReconcilerState:     btypes.ReconcilerStateCompleted,
+			ExternalState:       btypes.ChangesetExternalStateOpen,
+			ExternalReviewState: btypes.ChangesetReviewStateApproved,
+			ExternalCheckState:  btypes.ChangesetCheckStatePassed,
+		}
+	}
+
+	candidate := ct.CreateChangeset(t, ctx, s, mergeable())
+	withPendingJob := ct.CreateChangeset(t, ctx, s, mergeable())
+	withRecentJob := ct.CreateChangeset(t, ctx, s, mergeable())
+
+	for _, modify := range []func(*ct.TestChangesetOpts){
+		func(o *ct.TestChangesetOpts) { o.Repo = deletedRepo.ID },
+		func(o *ct.TestChangesetOpts) { o.OwnedByBatchChange = 0 },
+		func(o *ct.TestChangesetOpts) { o.IsArchived = true },
+		func(o *ct.TestChangesetOpts) { o.ReconcilerState = btypes.ReconcilerStateQueued },
+		func(o *ct.TestChangesetOpts) { o.ExternalState = btypes.ChangesetExternalStateDraft },
+		func(o *ct.TestChangesetOpts) { o.ExternalReviewState = btypes.ChangesetReviewStatePending },
+		func(o *ct.TestChangesetOpts) { o.ExternalCheckState = btypes.ChangesetCheckStatePending },
+	} {
+		opts := mergeable()
+		modify(&opts)
+		ct.CreateChangeset(t, ctx, s, opts)
+	}
+
+	if err := s.CreateChangesetJob(ctx, &btypes.ChangesetJob{
+		BatchChangeID: batchChangeID,
+		ChangesetID:   withPendingJob.ID,
+		JobType:       btypes.ChangesetJobTypeMerge,
+		Payload:       &btypes.ChangesetJobMergePayload{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	recentJob := &btypes.ChangesetJob{
+		BatchChangeID: batchChangeID,
+		ChangesetID:   withRecentJob.ID,
+		JobType:       btypes.ChangesetJobTypeMerge,
+		Payload:       &btypes.ChangesetJobMergePayload{},
+	}
+	if err := s.CreateChangesetJob(ctx, recentJob); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Exec(ctx, sqlf.Sprintf("UPDATE changeset_jobs SET state = 'failed' WHERE id = %s", recentJob.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("within retry period", func(t *testing.T) {
+		have, err := s.ListAutoMergeableChangesets(ctx, ListAutoMergeableChangesetsOpts{
+			BatchChangeID: batchChangeID,
+			RetryAfter:    clock.Now().Add(-1 * time.Minute),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{candidate.ID}, have.IDs()); diff != "" {
+			t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("after retry period", func(t *testing.T) {
+		have, err := s.ListAutoMergeableChangesets(ctx, ListAutoMergeableChangesetsOpts{
+			BatchChangeID: batchChangeID,
+			RetryAfter:    clock.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{candidate.ID, withRecentJob.ID}, have.IDs()); diff != "" {
+			t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		have, err := s.ListAutoMergeableChangesets(ctx, ListAutoMergeableChangesetsOpts{
+			LimitOpts:     LimitOpts{Limit: 1},
+			BatchChangeID: batchChangeID,
+			RetryAfter:    clock.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{candidate.ID}, have.IDs()); diff != "" {
+			t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		have, err := s.ListAutoMergeableChangesets(ctx, ListAutoMergeableChangesetsOpts{
+			BatchChangeID: batchChangeID,
+			RetryAfter:    clock.Now(),
+			Cursor:        candidate.ID + 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{withRecentJob.ID}, have.IDs()); diff != "" {
+			t.Fatalf("wrong changesets (-want +have):\n%s", diff)
+		}
+	})
+}
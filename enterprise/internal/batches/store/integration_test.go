@@ -22,9 +22,11 @@ func TestIntegration(t *testing.T) {
 		t.Run("Changesets", storeTest(db, nil, testStoreChangesets))
 		t.Run("ChangesetEvents", storeTest(db, nil, testStoreChangesetEvents))
 		t.Run("ChangesetScheduling", storeTest(db, nil, testStoreChangesetScheduling))
+		t.Run("ListAutoMergeableChangesets", storeTest(db, nil, testStoreListAutoMergeableChangesets))
 		t.Run("ListChangesetSyncData", storeTest(db, nil, testStoreListChangesetSyncData))
 		t.Run("ListChangesetsTextSearch", storeTest(db, nil, testStoreListChangesetsTextSearch))
 		t.Run("BatchSpecs", storeTest(db, nil, testStoreBatchSpecs))
+		t.Run("BatchSpecTemplates", storeTest(db, nil, testStoreBatchSpecTemplates))
 		t.Run("ChangesetSpecs", storeTest(db, nil, testStoreChangesetSpecs))
 		t.Run("GetRewirerMappingWithArchivedChangesets", storeTest(db, nil, testStoreGetRewirerMappingWithArchivedChangesets))
 		t.Run("ChangesetSpecsCurrentState", storeTest(db, nil, testStoreChangesetSpecsCurrentState))
@@ -35,6 +37,9 @@ func TestIntegration(t *testing.T) {
 		t.Run("ChangesetJobs", storeTest(db, nil, testStoreChangesetJobs))
 		t.Run("BulkOperations", storeTest(db, nil, testStoreBulkOperations))
 		t.Run("BatchSpecExecutions", storeTest(db, nil, testStoreChangesetSpecExecutions))
+		t.Run("BatchSpecResolutionJobs", storeTest(db, nil, testStoreBatchSpecResolutionJobs))
+		t.Run("BatchSpecWorkspaces", storeTest(db, nil, testStoreBatchSpecWorkspaces))
+		t.Run("BatchSpecWorkspaceExecutionJobs", storeTest(db, nil, testStoreBatchSpecWorkspaceExecutionJobs))
 
 		for name, key := range map[string]encryption.Key{
 			"no key":   nil,
@@ -151,6 +151,20 @@ type operations struct {
 	cancelBatchSpecExecution *observation.Operation
 	listBatchSpecExecutions  *observation.Operation
 
+	createBatchSpecResolutionJob *observation.Operation
+	getBatchSpecResolutionJob    *observation.Operation
+
+	createBatchSpecWorkspace            *observation.Operation
+	getBatchSpecWorkspace               *observation.Operation
+	listBatchSpecWorkspaces             *observation.Operation
+	setBatchSpecWorkspaceChangesetSpecs *observation.Operation
+	setBatchSpecWorkspaceCommit         *observation.Operation
+
+	createBatchSpecWorkspaceExecutionJobs *observation.Operation
+	getBatchSpecWorkspaceExecutionJob     *observation.Operation
+	listBatchSpecWorkspaceExecutionJobs   *observation.Operation
+	retryBatchSpecWorkspaceExecutionJob   *observation.Operation
+
 	createBatchSpec         *observation.Operation
 	updateBatchSpec         *observation.Operation
 	deleteBatchSpec         *observation.Operation
@@ -160,6 +174,13 @@ type operations struct {
 	listBatchSpecs          *observation.Operation
 	deleteExpiredBatchSpecs *observation.Operation
 
+	createBatchSpecTemplate *observation.Operation
+	updateBatchSpecTemplate *observation.Operation
+	deleteBatchSpecTemplate *observation.Operation
+	getBatchSpecTemplate    *observation.Operation
+	listBatchSpecTemplates  *observation.Operation
+	countBatchSpecTemplates *observation.Operation
+
 	getBulkOperation        *observation.Operation
 	listBulkOperations      *observation.Operation
 	countBulkOperations     *observation.Operation
@@ -172,6 +193,7 @@ type operations struct {
 
 	createChangesetJob *observation.Operation
 	getChangesetJob    *observation.Operation
+	countChangesetJobs *observation.Operation
 
 	createChangesetSpec         *observation.Operation
 	updateChangesetSpec         *observation.Operation
@@ -200,6 +222,8 @@ type operations struct {
 	getRepoChangesetsStats            *observation.Operation
 	enqueueNextScheduledChangeset     *observation.Operation
 	getChangesetPlaceInSchedulerQueue *observation.Operation
+	listAutoMergeableChangesets       *observation.Operation
+	supersedeChangesetSpec            *observation.Operation
 
 	listCodeHosts         *observation.Operation
 	getExternalServiceIDs *observation.Operation
@@ -256,6 +280,20 @@ func newOperations(observationContext *observation.Context) *operations {
 			cancelBatchSpecExecution: op("CancelBatchSpecExecution"),
 			listBatchSpecExecutions:  op("ListBatchSpecExecutions"),
 
+			createBatchSpecResolutionJob: op("CreateBatchSpecResolutionJob"),
+			getBatchSpecResolutionJob:    op("GetBatchSpecResolutionJob"),
+
+			createBatchSpecWorkspace:            op("CreateBatchSpecWorkspace"),
+			getBatchSpecWorkspace:               op("GetBatchSpecWorkspace"),
+			listBatchSpecWorkspaces:             op("ListBatchSpecWorkspaces"),
+			setBatchSpecWorkspaceChangesetSpecs: op("SetBatchSpecWorkspaceChangesetSpecs"),
+			setBatchSpecWorkspaceCommit:         op("SetBatchSpecWorkspaceCommit"),
+
+			createBatchSpecWorkspaceExecutionJobs: op("CreateBatchSpecWorkspaceExecutionJobs"),
+			getBatchSpecWorkspaceExecutionJob:     op("GetBatchSpecWorkspaceExecutionJob"),
+			listBatchSpecWorkspaceExecutionJobs:   op("ListBatchSpecWorkspaceExecutionJobs"),
+			retryBatchSpecWorkspaceExecutionJob:   op("RetryBatchSpecWorkspaceExecutionJob"),
+
 			createBatchSpec:         op("CreateBatchSpec"),
 			updateBatchSpec:         op("UpdateBatchSpec"),
 			deleteBatchSpec:         op("DeleteBatchSpec"),
@@ -265,6 +303,13 @@ func newOperations(observationContext *observation.Context) *operations {
 			listBatchSpecs:          op("ListBatchSpecs"),
 			deleteExpiredBatchSpecs: op("DeleteExpiredBatchSpecs"),
 
+			createBatchSpecTemplate: op("CreateBatchSpecTemplate"),
+			updateBatchSpecTemplate: op("UpdateBatchSpecTemplate"),
+			deleteBatchSpecTemplate: op("DeleteBatchSpecTemplate"),
+			getBatchSpecTemplate:    op("GetBatchSpecTemplate"),
+			listBatchSpecTemplates:  op("ListBatchSpecTemplates"),
+			countBatchSpecTemplates: op("CountBatchSpecTemplates"),
+
 			getBulkOperation:        op("GetBulkOperation"),
 			listBulkOperations:      op("ListBulkOperations"),
 			countBulkOperations:     op("CountBulkOperations"),
@@ -277,6 +322,7 @@ func newOperations(observationContext *observation.Context) *operations {
 
 			createChangesetJob: op("CreateChangesetJob"),
 			getChangesetJob:    op("GetChangesetJob"),
+			countChangesetJobs: op("CountChangesetJobs"),
 
 			createChangesetSpec:         op("CreateChangesetSpec"),
 			updateChangesetSpec:         op("UpdateChangesetSpec"),
@@ -305,6 +351,8 @@ func newOperations(observationContext *observation.Context) *operations {
 			getRepoChangesetsStats:            op("GetRepoChangesetsStats"),
 			enqueueNextScheduledChangeset:     op("EnqueueNextScheduledChangeset"),
 			getChangesetPlaceInSchedulerQueue: op("GetChangesetPlaceInSchedulerQueue"),
+			listAutoMergeableChangesets:       op("ListAutoMergeableChangesets"),
+			supersedeChangesetSpec:            op("SupersedeChangesetSpec"),
 
 			listCodeHosts:         op("ListCodeHosts"),
 			getExternalServiceIDs: op("GetExternalServiceIDs"),
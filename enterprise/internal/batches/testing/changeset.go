@@ -27,6 +27,7 @@ type TestChangesetOpts struct {
 	ExternalState       btypes.ChangesetExternalState
 	ExternalReviewState btypes.ChangesetReviewState
 	ExternalCheckState  btypes.ChangesetCheckState
+	ExternalChecks      []btypes.ChangesetCheck
 
 	DiffStatAdded   int32
 	DiffStatChanged int32
@@ -88,6 +89,7 @@ func BuildChangeset(opts TestChangesetOpts) *btypes.Changeset {
 		ExternalState:       opts.ExternalState,
 		ExternalReviewState: opts.ExternalReviewState,
 		ExternalCheckState:  opts.ExternalCheckState,
+		ExternalChecks:      opts.ExternalChecks,
 
 		PublicationState:   opts.PublicationState,
 		UiPublicationState: opts.UiPublicationState,
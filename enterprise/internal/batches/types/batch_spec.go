@@ -16,6 +16,9 @@ func NewBatchSpecFromRaw(rawSpec string) (_ *BatchSpec, err error) {
 		AllowArrayEnvironments: true,
 		AllowTransformChanges:  true,
 		AllowConditionalExec:   true,
+		AllowAutoMerge:         true,
+		AllowRerunOnBaseChange: true,
+		AllowRequiredChecks:    true,
 	})
 
 	return c, err
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// BatchSpecResolutionJobState defines the possible states of a
+// BatchSpecResolutionJob.
+type BatchSpecResolutionJobState string
+
+const (
+	BatchSpecResolutionJobStateQueued     BatchSpecResolutionJobState = "queued"
+	BatchSpecResolutionJobStateProcessing BatchSpecResolutionJobState = "processing"
+	BatchSpecResolutionJobStateErrored    BatchSpecResolutionJobState = "errored"
+	BatchSpecResolutionJobStateFailed     BatchSpecResolutionJobState = "failed"
+	BatchSpecResolutionJobStateCompleted  BatchSpecResolutionJobState = "completed"
+)
+
+// ToGraphQL returns the GraphQL representation of the worker state.
+func (s BatchSpecResolutionJobState) ToGraphQL() string { return strings.ToUpper(string(s)) }
+
+// BatchSpecResolutionJob is a job that resolves the `on` and `workspaces`
+// attributes of a BatchSpec into a list of BatchSpecWorkspaces and enqueues a
+// BatchSpecWorkspaceExecutionJob for each of them.
+type BatchSpecResolutionJob struct {
+	ID int64
+
+	BatchSpecID      int64
+	AllowUnsupported bool
+	AllowIgnored     bool
+
+	State          BatchSpecResolutionJobState
+	FailureMessage *string
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	ProcessAfter   time.Time
+	NumResets      int64
+	NumFailures    int64
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+	WorkerHostname string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (j *BatchSpecResolutionJob) RecordID() int {
+	return int(j.ID)
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// BatchSpecTemplate is a parameterised batch spec that can be instantiated
+// with values for its inputs. Templates without a namespace are available to
+// all users of the instance.
+type BatchSpecTemplate struct {
+	ID int64
+
+	Name        string
+	Description string
+
+	RawTemplate string
+	Inputs      []batcheslib.TemplateInput
+
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+
+	CreatorID int32
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a BatchSpecTemplate.
+func (t *BatchSpecTemplate) Clone() *BatchSpecTemplate {
+	tt := *t
+	tt.Inputs = append([]batcheslib.TemplateInput(nil), t.Inputs...)
+	return &tt
+}
+
+// SiteWide returns true if the template is not owned by a user or an
+// organization.
+func (t *BatchSpecTemplate) SiteWide() bool {
+	return t.NamespaceUserID == 0 && t.NamespaceOrgID == 0
+}
+
+// Render substitutes the given values for the inputs of the template and
+// returns the resulting raw batch spec.
+func (t *BatchSpecTemplate) Render(values map[string]interface{}) (string, error) {
+	return batcheslib.RenderBatchSpecTemplate(t.RawTemplate, t.Inputs, values)
+}
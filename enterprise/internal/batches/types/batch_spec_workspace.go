@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// BatchSpecWorkspace is a single repository/path combination in which the
+// steps of a BatchSpec are executed.
+type BatchSpecWorkspace struct {
+	ID int64
+
+	BatchSpecID      int64
+	ChangesetSpecIDs []int64
+
+	RepoID             api.RepoID
+	Branch             string
+	Commit             string
+	Path               string
+	FileMatches        []string
+	OnlyFetchWorkspace bool
+	Steps              []batcheslib.Step
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Clone returns a clone of a BatchSpecWorkspace.
+func (w *BatchSpecWorkspace) Clone() *BatchSpecWorkspace {
+	ww := *w
+	return &ww
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// BatchSpecWorkspaceExecutionJobState defines the possible states of a
+// BatchSpecWorkspaceExecutionJob.
+type BatchSpecWorkspaceExecutionJobState string
+
+const (
+	BatchSpecWorkspaceExecutionJobStateQueued     BatchSpecWorkspaceExecutionJobState = "queued"
+	BatchSpecWorkspaceExecutionJobStateProcessing BatchSpecWorkspaceExecutionJobState = "processing"
+	BatchSpecWorkspaceExecutionJobStateErrored    BatchSpecWorkspaceExecutionJobState = "errored"
+	BatchSpecWorkspaceExecutionJobStateFailed     BatchSpecWorkspaceExecutionJobState = "failed"
+	BatchSpecWorkspaceExecutionJobStateCompleted  BatchSpecWorkspaceExecutionJobState = "completed"
+)
+
+// Retryable returns whether a job in the given state can be retried.
+func (s BatchSpecWorkspaceExecutionJobState) Retryable() bool {
+	return s == BatchSpecWorkspaceExecutionJobStateErrored || s == BatchSpecWorkspaceExecutionJobStateFailed
+}
+
+// ToGraphQL returns the GraphQL representation of the worker state.
+func (s BatchSpecWorkspaceExecutionJobState) ToGraphQL() string { return strings.ToUpper(string(s)) }
+
+// BatchSpecWorkspaceExecutionJob is the executor job that runs the steps of a
+// BatchSpec in a single BatchSpecWorkspace.
+type BatchSpecWorkspaceExecutionJob struct {
+	ID int64
+
+	BatchSpecWorkspaceID int64
+
+	State          BatchSpecWorkspaceExecutionJobState
+	FailureMessage *string
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	ProcessAfter   time.Time
+	NumResets      int64
+	NumFailures    int64
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+	WorkerHostname string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (j *BatchSpecWorkspaceExecutionJob) RecordID() int {
+	return int(j.ID)
+}
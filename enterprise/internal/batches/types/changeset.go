@@ -229,6 +229,15 @@ func (s ChangesetCheckState) Valid() bool {
 	}
 }
 
+// ChangesetCheck is the state of a single check on the latest commit of a
+// Changeset: a GitHub commit status or check run, a GitLab pipeline job, or a
+// Bitbucket Server build status.
+type ChangesetCheck struct {
+	Name  string              `json:"name"`
+	State ChangesetCheckState `json:"state"`
+	URL   string              `json:"url,omitempty"`
+}
+
 // BatchChangeAssoc stores the details of a association to a BatchChange.
 type BatchChangeAssoc struct {
 	BatchChangeID int64 `json:"-"`
@@ -255,6 +264,7 @@ type Changeset struct {
 	ExternalState       ChangesetExternalState
 	ExternalReviewState ChangesetReviewState
 	ExternalCheckState  ChangesetCheckState
+	ExternalChecks      []ChangesetCheck
 	DiffStatAdded       *int32
 	DiffStatChanged     *int32
 	DiffStatDeleted     *int32
@@ -294,6 +304,10 @@ func (c *Changeset) Clone() *Changeset {
 	tt := *c
 	tt.BatchChanges = make([]BatchChangeAssoc, len(c.BatchChanges))
 	copy(tt.BatchChanges, c.BatchChanges)
+	if c.ExternalChecks != nil {
+		tt.ExternalChecks = make([]ChangesetCheck, len(c.ExternalChecks))
+		copy(tt.ExternalChecks, c.ExternalChecks)
+	}
 	return &tt
 }
 
@@ -709,6 +723,61 @@ func (c *Changeset) BaseRef() (string, error) {
 	}
 }
 
+// HasMergeConflict returns true if the codehost reports that the Changeset
+// can't be merged into its base branch because of conflicting changes. Not all
+// codehosts report this, in which case false is returned.
+func (c *Changeset) HasMergeConflict() bool {
+	switch m := c.Metadata.(type) {
+	case *github.PullRequest:
+		return m.Mergeable == "CONFLICTING"
+	case *gitlab.MergeRequest:
+		return m.HasConflicts
+	default:
+		return false
+	}
+}
+
+// RebaseWanted returns true if the Changeset should be rebased according to
+// mode once its base branch moves: always if mode is outdated, and only if the
+// codehost reports a merge conflict if mode is conflicts.
+func (c *Changeset) RebaseWanted(mode batches.RerunOnBaseChange) bool {
+	switch mode {
+	case batches.RerunOnBaseChangeOutdated:
+		return true
+	case batches.RerunOnBaseChangeConflicts:
+		return c.HasMergeConflict()
+	default:
+		return false
+	}
+}
+
+// NeedsRebase returns true if the Changeset, whose changes were created
+// against baseRev, should be rebased onto currentBaseRev, the commit its base
+// branch points to now. Rebasing onto the commit the changes were created
+// against can't resolve anything, so it's only needed once the base branch
+// moved.
+func (c *Changeset) NeedsRebase(mode batches.RerunOnBaseChange, baseRev, currentBaseRev string) bool {
+	return c.RebaseWanted(mode) && currentBaseRev != "" && currentBaseRev != baseRev
+}
+
+// RequiredChecksNotPassed returns the names of the given required checks that
+// haven't passed on the Changeset. Checks that haven't been reported by the
+// codehost yet haven't passed either.
+func (c *Changeset) RequiredChecksNotPassed(required []string) []string {
+	passed := make(map[string]bool, len(c.ExternalChecks))
+	for _, check := range c.ExternalChecks {
+		passed[check.Name] = check.State == ChangesetCheckStatePassed
+	}
+
+	var notPassed []string
+	for _, name := range required {
+		if !passed[name] {
+			notPassed = append(notPassed, name)
+		}
+	}
+	return notPassed
+}
+
 // AttachedTo returns true if the changeset is currently attached to the batch
 // change with the given batchChangeID.
 func (c *Changeset) AttachedTo(batchChangeID int64) bool {
@@ -981,6 +1050,9 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 		return ChangesetEventKindGitLabReopened, nil
 	case *gitlab.MergeRequestMergedEvent:
 		return ChangesetEventKindGitLabMerged, nil
+
+	case *ChangesetRebasedEvent:
+		return ChangesetEventKindBatchChangeRebased, nil
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("unknown changeset event kind for %T", e)
@@ -1061,6 +1133,8 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindGitLabReopened:
 			return new(gitlab.MergeRequestReopenedEvent), nil
 		}
+	case k == ChangesetEventKindBatchChangeRebased:
+		return new(ChangesetRebasedEvent), nil
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
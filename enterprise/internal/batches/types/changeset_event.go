@@ -78,6 +78,10 @@ const (
 	ChangesetEventKindGitLabMarkWorkInProgress   ChangesetEventKind = "gitlab:mark_wip"
 	ChangesetEventKindGitLabUnmarkWorkInProgress ChangesetEventKind = "gitlab:unmark_wip"
 
+	// ChangesetEventKindBatchChangeRebased is recorded by Sourcegraph itself,
+	// not by a code host, when a changeset is rebased onto a newer base commit.
+	ChangesetEventKindBatchChangeRebased ChangesetEventKind = "batchchange:rebased"
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -99,6 +103,29 @@ func (e *ChangesetEvent) Clone() *ChangesetEvent {
 	return &ee
 }
 
+// ChangesetRebasedEvent is the metadata of a ChangesetEventKindBatchChangeRebased
+// event.
+type ChangesetRebasedEvent struct {
+	// PreviousBaseRev is the base commit the changeset was based on before.
+	PreviousBaseRev string
+	// BaseRev is the new base commit of the changeset.
+	BaseRev string
+	// Conflict is true if the rebase was triggered by a merge conflict, and
+	// false if it was triggered by an outdated base.
+	Conflict bool
+	// Rerun is true if the changes were produced by re-executing the steps of
+	// the batch spec against BaseRev, instead of re-applying the previous
+	// diff.
+	Rerun bool
+
+	CreatedAt time.Time
+}
+
+// Key is a unique key identifying this event in the context of its changeset.
+func (e ChangesetRebasedEvent) Key() string {
+	return fmt.Sprintf("%s:%s", e.PreviousBaseRev, e.BaseRev)
+}
+
 // ReviewAuthor returns the author of the review if the ChangesetEvent is related to a review.
 // Returns an empty string if not a review event or the author has been deleted.
 func (e *ChangesetEvent) ReviewAuthor() string {
@@ -240,6 +267,8 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		// fall back to the event record we created when we received the
 		// webhook.
 		t = e.CreatedAt
+	case *ChangesetRebasedEvent:
+		t = ev.CreatedAt
 	}
 
 	return t
@@ -722,6 +751,11 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		// We always get the full event, so safe to replace it
 		*e = *o
 
+	case *ChangesetRebasedEvent:
+		o := o.Metadata.(*ChangesetRebasedEvent)
+		// The event is only ever written by Sourcegraph, so it's complete.
+		*e = *o
+
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
@@ -59,6 +59,9 @@ type ChangesetJobReenqueuePayload struct{}
 
 type ChangesetJobMergePayload struct {
 	Squash bool `json:"squash,omitempty"`
+	// Rebase merges the changeset by rebasing its commits onto the base
+	// branch. It takes precedence over Squash.
+	Rebase bool `json:"rebase,omitempty"`
 }
 
 type ChangesetJobClosePayload struct{}
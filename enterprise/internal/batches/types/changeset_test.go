@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+	"github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
 func TestChangeset_Clone(t *testing.T) {
@@ -452,6 +453,38 @@ func TestChangeset_BaseRef(t *testing.T) {
 	})
 }
 
+func TestChangeset_NeedsRebase(t *testing.T) {
+	mergeable := &Changeset{Metadata: &github.PullRequest{Mergeable: "MERGEABLE"}}
+	githubConflicting := &Changeset{Metadata: &github.PullRequest{Mergeable: "CONFLICTING"}}
+	gitlabConflicting := &Changeset{Metadata: &gitlab.MergeRequest{HasConflicts: true}}
+
+	tests := []struct {
+		name           string
+		changeset      *Changeset
+		mode           batches.RerunOnBaseChange
+		currentBaseRev string
+		want           bool
+	}{
+		{name: "not configured", changeset: githubConflicting, mode: "", currentBaseRev: "f00b4r", want: false},
+		{name: "outdated", changeset: mergeable, mode: batches.RerunOnBaseChangeOutdated, currentBaseRev: "f00b4r", want: true},
+		{name: "up to date", changeset: mergeable, mode: batches.RerunOnBaseChangeOutdated, currentBaseRev: "deadbeef", want: false},
+		{name: "unknown base", changeset: mergeable, mode: batches.RerunOnBaseChangeOutdated, currentBaseRev: "", want: false},
+		{name: "outdated without conflicts", changeset: mergeable, mode: batches.RerunOnBaseChangeConflicts, currentBaseRev: "f00b4r", want: false},
+		{name: "github conflicting", changeset: githubConflicting, mode: batches.RerunOnBaseChangeConflicts, currentBaseRev: "f00b4r", want: true},
+		{name: "github conflicting on current base", changeset: githubConflicting, mode: batches.RerunOnBaseChangeConflicts, currentBaseRev: "deadbeef", want: false},
+		{name: "gitlab conflicting", changeset: gitlabConflicting, mode: batches.RerunOnBaseChangeConflicts, currentBaseRev: "f00b4r", want: true},
+		{name: "gitlab conflicting on current base", changeset: gitlabConflicting, mode: batches.RerunOnBaseChangeConflicts, currentBaseRev: "deadbeef", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := tc.changeset.NeedsRebase(tc.mode, "deadbeef", tc.currentBaseRev); have != tc.want {
+				t.Fatalf("wrong result. want=%t, have=%t", tc.want, have)
+			}
+		})
+	}
+}
+
 func TestChangeset_Labels(t *testing.T) {
 	for name, tc := range map[string]struct {
 		meta interface{}
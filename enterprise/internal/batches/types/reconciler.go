@@ -16,6 +16,7 @@ const (
 	ReconcilerOperationSleep        ReconcilerOperation = "SLEEP"
 	ReconcilerOperationDetach       ReconcilerOperation = "DETACH"
 	ReconcilerOperationArchive      ReconcilerOperation = "ARCHIVE"
+	ReconcilerOperationRebase       ReconcilerOperation = "REBASE"
 )
 
 // Valid returns true if the given ReconcilerOperation is valid.
@@ -32,7 +33,8 @@ func (r ReconcilerOperation) Valid() bool {
 		ReconcilerOperationReopen,
 		ReconcilerOperationSleep,
 		ReconcilerOperationDetach,
-		ReconcilerOperationArchive:
+		ReconcilerOperationArchive,
+		ReconcilerOperationRebase:
 		return true
 	default:
 		return false
@@ -84,6 +84,18 @@ func (cfg *Configuration) HasRolloutWindows() bool {
 	return len(cfg.windows) != 0
 }
 
+// IsOpen returns true if changesets may be processed at the given time: either
+// because no rollout windows have been defined, or because the window in
+// effect at that time doesn't have a zero rate.
+func (cfg *Configuration) IsOpen(now time.Time) bool {
+	if !cfg.HasRolloutWindows() {
+		return true
+	}
+
+	window, _ := cfg.windowFor(now)
+	return window != nil && window.rate.n != 0
+}
+
 // Schedule returns the currently active schedule.
 func (cfg *Configuration) Schedule() *Schedule {
 	// If there are no rollout windows, then we return an unlimited schedule and
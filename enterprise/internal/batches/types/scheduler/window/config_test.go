@@ -157,6 +157,67 @@ func TestConfiguration_Estimate(t *testing.T) {
 	})
 }
 
+func TestConfiguration_IsOpen(t *testing.T) {
+	// Monday.
+	base := time.Date(2021, 7, 12, 0, 0, 0, 0, time.UTC)
+
+	weekdays := Window{
+		days:  newWeekdaySet(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+		start: timeOfDayPtr(9, 0),
+		end:   timeOfDayPtr(17, 0),
+		rate:  rate{n: 10, unit: ratePerHour},
+	}
+	lunch := Window{
+		days:  newWeekdaySet(),
+		start: timeOfDayPtr(12, 0),
+		end:   timeOfDayPtr(13, 0),
+		rate:  rate{n: 0},
+	}
+
+	for name, tc := range map[string]struct {
+		cfg  *Configuration
+		now  time.Time
+		want bool
+	}{
+		"no windows": {
+			cfg:  &Configuration{},
+			now:  base,
+			want: true,
+		},
+		"outside of any window": {
+			cfg:  &Configuration{windows: []Window{weekdays}},
+			now:  base.Add(8 * time.Hour),
+			want: false,
+		},
+		"inside a window": {
+			cfg:  &Configuration{windows: []Window{weekdays}},
+			now:  base.Add(10 * time.Hour),
+			want: true,
+		},
+		"inside a window on the wrong day": {
+			cfg:  &Configuration{windows: []Window{weekdays}},
+			now:  base.Add(-14 * time.Hour),
+			want: false,
+		},
+		"inside a zero rate window": {
+			cfg:  &Configuration{windows: []Window{weekdays, lunch}},
+			now:  base.Add(12*time.Hour + 30*time.Minute),
+			want: false,
+		},
+		"inside a window after a zero rate window": {
+			cfg:  &Configuration{windows: []Window{weekdays, lunch}},
+			now:  base.Add(14 * time.Hour),
+			want: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if have := tc.cfg.IsOpen(tc.now); have != tc.want {
+				t.Errorf("unexpected result: have=%v want=%v", have, tc.want)
+			}
+		})
+	}
+}
+
 func TestConfiguration_Schedule(t *testing.T) {
 	// We have other tests to test the actual implementation of scheduleAt();
 	// this is purely to ensure that we do the special case handling of not
@@ -12,11 +12,29 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/inference"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
+// ErrPackageRepositoryNotReady is returned by QueueIndexesForPackage when the repository providing
+// the package is known to exist (or is about to exist) but cannot yet be resolved, either because
+// it is still being cloned or because it is a package repository that has not yet been synced.
+// Callers may retry the package at a later time.
+var ErrPackageRepositoryNotReady = errors.New("package repository not ready")
+
+// ErrRateLimited is returned by QueueIndexesForPackage when index jobs would be enqueued for the
+// package but the given allow function denied it. Callers may retry the package at a later time.
+var ErrRateLimited = errors.New("rate limited")
+
+// packageRepositorySchemes is the set of package schemes that are resolved to synthetic package
+// repositories (e.g. JVM packages) rather than to repositories of a code host. These repositories
+// are created by an external service sync triggered by the dependency indexing scheduler.
+var packageRepositorySchemes = map[string]struct{}{
+	"semanticdb": {},
+}
+
 type IndexEnqueuer struct {
 	dbStore            DBStore
 	gitserverClient    GitserverClient
@@ -96,12 +114,16 @@ func (s *IndexEnqueuer) QueueIndexes(ctx context.Context, repositoryID int, rev,
 	commit := string(commitID)
 	traceLog(log.String("commit", commit))
 
-	return s.queueIndexForRepositoryAndCommit(ctx, repositoryID, commit, configuration, force, traceLog)
+	return s.queueIndexForRepositoryAndCommit(ctx, repositoryID, commit, configuration, force, nil, traceLog)
 }
 
 // QueueIndexesForPackage enqueues index jobs for a dependency of a recently-processed precise code
 // intelligence index.
-func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.Package) (err error) {
+//
+// If allow is non-nil, it is called right before index jobs are inserted for the repository and
+// commit providing the package, which happens only if they have no upload or index yet. No jobs are
+// inserted and ErrRateLimited is returned if it returns false.
+func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.Package, allow func() (bool, error)) (err error) {
 	ctx, traceLog, endObservation := s.operations.QueueIndexForPackage.WithAndLogger(ctx, &err, observation.Args{
 		LogFields: []log.Field{
 			log.String("scheme", pkg.Scheme),
@@ -125,6 +147,11 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.
 	resp, err := s.repoUpdater.EnqueueRepoUpdate(ctx, api.RepoName(repoName))
 	if err != nil {
 		if errcode.IsNotFound(err) {
+			if _, ok := packageRepositorySchemes[pkg.Scheme]; ok {
+				// The package repository is created by the next sync of its external service
+				return ErrPackageRepositoryNotReady
+			}
+
 			return nil
 		}
 
@@ -133,6 +160,9 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.
 
 	commit, err := s.gitserverClient.ResolveRevision(ctx, int(resp.ID), revision)
 	if err != nil {
+		if vcs.IsCloneInProgress(err) {
+			return ErrPackageRepositoryNotReady
+		}
 		if errcode.IsNotFound(err) {
 			return nil
 		}
@@ -140,7 +170,7 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.
 		return errors.Wrap(err, "gitserverClient.ResolveRevision")
 	}
 
-	_, err = s.queueIndexForRepositoryAndCommit(ctx, int(resp.ID), string(commit), "", false, traceLog)
+	_, err = s.queueIndexForRepositoryAndCommit(ctx, int(resp.ID), string(commit), "", false, allow, traceLog)
 	return err
 }
 
@@ -149,7 +179,10 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg precise.
 // If the force flag is false, then the presence of an upload or index record for this given repository and commit
 // will cause this method to no-op. Note that this is NOT a guarantee that there will never be any duplicate records
 // when the flag is false.
-func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, repositoryID int, commit, configuration string, force bool, traceLog observation.TraceLogger) ([]store.Index, error) {
+//
+// If allow is non-nil, it is called before the index jobs are inserted. If it returns false, no jobs are
+// inserted and ErrRateLimited is returned.
+func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, repositoryID int, commit, configuration string, force bool, allow func() (bool, error), traceLog observation.TraceLogger) ([]store.Index, error) {
 	if !force {
 		isQueued, err := s.dbStore.IsQueued(ctx, repositoryID, commit)
 		if err != nil {
@@ -169,6 +202,14 @@ func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, re
 	}
 	traceLog(log.Int("numIndexes", len(indexes)))
 
+	if allow != nil {
+		if ok, err := allow(); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrRateLimited
+		}
+	}
+
 	return s.dbStore.InsertIndexes(ctx, indexes)
 }
 
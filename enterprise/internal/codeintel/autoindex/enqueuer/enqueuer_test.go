@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -482,7 +483,7 @@ func TestQueueIndexesForPackage(t *testing.T) {
 		Scheme:  "gomod",
 		Name:    "https://github.com/sourcegraph/sourcegraph",
 		Version: "v3.26.0-4e7eeb0f8a96",
-	})
+	}, nil)
 
 	if len(mockDBStore.IsQueuedFunc.History()) != 1 {
 		t.Errorf("unexpected number of calls to IsQueued. want=%d have=%d", 1, len(mockDBStore.IsQueuedFunc.History()))
@@ -526,3 +527,101 @@ func TestQueueIndexesForPackage(t *testing.T) {
 		}
 	}
 }
+
+func TestQueueIndexesForPackageRateLimited(t *testing.T) {
+	testCases := []struct {
+		isQueued           bool
+		allowed            bool
+		expectedAllowCalls int
+		expectedErr        error
+		expectedInserts    int
+	}{
+		{isQueued: true, allowed: false, expectedAllowCalls: 0, expectedErr: nil, expectedInserts: 0},
+		{isQueued: false, allowed: false, expectedAllowCalls: 1, expectedErr: ErrRateLimited, expectedInserts: 0},
+		{isQueued: false, allowed: true, expectedAllowCalls: 1, expectedErr: nil, expectedInserts: 1},
+	}
+
+	for _, testCase := range testCases {
+		mockDBStore := NewMockDBStore()
+		mockDBStore.InsertIndexesFunc.SetDefaultHook(func(ctx context.Context, indexes []store.Index) ([]store.Index, error) { return indexes, nil })
+		mockDBStore.IsQueuedFunc.SetDefaultReturn(testCase.isQueued, nil)
+		mockGitserverClient := NewMockGitserverClient()
+		mockGitserverClient.ResolveRevisionFunc.SetDefaultReturn("c42", nil)
+		mockGitserverClient.ListFilesFunc.SetDefaultReturn([]string{"go.mod"}, nil)
+		mockRepoUpdater := NewMockRepoUpdaterClient()
+		mockRepoUpdater.EnqueueRepoUpdateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{ID: 42}, nil)
+
+		scheduler := NewIndexEnqueuer(mockDBStore, mockGitserverClient, mockRepoUpdater, &testConfig, &observation.TestContext)
+
+		allowCalls := 0
+		allow := func() (bool, error) {
+			allowCalls++
+			return testCase.allowed, nil
+		}
+
+		err := scheduler.QueueIndexesForPackage(context.Background(), precise.Package{
+			Scheme:  "gomod",
+			Name:    "https://github.com/sourcegraph/sourcegraph",
+			Version: "v3.26.0-4e7eeb0f8a96",
+		}, allow)
+		if err != testCase.expectedErr {
+			t.Errorf("unexpected error. want=%v have=%v", testCase.expectedErr, err)
+		}
+		if allowCalls != testCase.expectedAllowCalls {
+			t.Errorf("unexpected number of calls to allow. want=%d have=%d", testCase.expectedAllowCalls, allowCalls)
+		}
+		if len(mockDBStore.InsertIndexesFunc.History()) != testCase.expectedInserts {
+			t.Errorf("unexpected number of calls to InsertIndexes. want=%d have=%d", testCase.expectedInserts, len(mockDBStore.InsertIndexesFunc.History()))
+		}
+	}
+}
+
+func TestQueueIndexesForPackageCloneInProgress(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.ResolveRevisionFunc.SetDefaultReturn("", &vcs.RepoNotExistError{Repo: "github.com/sourcegraph/sourcegraph", CloneInProgress: true})
+	mockRepoUpdater := NewMockRepoUpdaterClient()
+	mockRepoUpdater.EnqueueRepoUpdateFunc.SetDefaultReturn(&protocol.RepoUpdateResponse{ID: 42}, nil)
+
+	scheduler := NewIndexEnqueuer(mockDBStore, mockGitserverClient, mockRepoUpdater, &testConfig, &observation.TestContext)
+
+	err := scheduler.QueueIndexesForPackage(context.Background(), precise.Package{
+		Scheme:  "gomod",
+		Name:    "https://github.com/sourcegraph/sourcegraph",
+		Version: "v3.26.0-4e7eeb0f8a96",
+	}, nil)
+	if err != ErrPackageRepositoryNotReady {
+		t.Fatalf("unexpected error. want=%q have=%v", ErrPackageRepositoryNotReady, err)
+	}
+
+	if len(mockDBStore.IsQueuedFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to IsQueued. want=%d have=%d", 0, len(mockDBStore.IsQueuedFunc.History()))
+	}
+}
+
+func TestQueueIndexesForPackageUnknownRepository(t *testing.T) {
+	testCases := []struct {
+		pkg         precise.Package
+		expectedErr error
+	}{
+		{
+			pkg:         precise.Package{Scheme: "gomod", Name: "https://github.com/sourcegraph/sourcegraph", Version: "v3.26.0"},
+			expectedErr: nil,
+		},
+		{
+			pkg:         precise.Package{Scheme: "semanticdb", Name: "maven/junit/junit", Version: "4.2"},
+			expectedErr: ErrPackageRepositoryNotReady,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockRepoUpdater := NewMockRepoUpdaterClient()
+		mockRepoUpdater.EnqueueRepoUpdateFunc.SetDefaultReturn(nil, &vcs.RepoNotExistError{Repo: "unknown"})
+
+		scheduler := NewIndexEnqueuer(NewMockDBStore(), NewMockGitserverClient(), mockRepoUpdater, &testConfig, &observation.TestContext)
+
+		if err := scheduler.QueueIndexesForPackage(context.Background(), testCase.pkg, nil); err != testCase.expectedErr {
+			t.Errorf("unexpected error for scheme %q. want=%v have=%v", testCase.pkg.Scheme, testCase.expectedErr, err)
+		}
+	}
+}
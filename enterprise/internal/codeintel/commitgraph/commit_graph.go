@@ -46,6 +46,32 @@ func NewGraph(commitGraph *gitserver.CommitGraph, commitGraphView *CommitGraphVi
 	}
 }
 
+// AttachMergeBase returns a copy of the given commit graph fragment in which the given merge base
+// commit is a parent of every commit along the boundary of the fragment (commits whose parents are
+// not known). This links a fragment of history that has not yet been merged into a tracked branch
+// (such as the head of a pull request from a fork) to the branch it is based on. The merge base's
+// visible uploads then become visible from the commits of the fragment that have no closer upload.
+//
+// If the merge base is already a member of the fragment, the fragment is returned unchanged.
+func AttachMergeBase(commitGraph *gitserver.CommitGraph, mergeBase string) *gitserver.CommitGraph {
+	graph := commitGraph.Graph()
+	if _, ok := graph[mergeBase]; ok {
+		return commitGraph
+	}
+
+	attached := make(map[string][]string, len(graph)+1)
+	for commit, parents := range graph {
+		if len(parents) == 0 {
+			parents = []string{mergeBase}
+		}
+
+		attached[commit] = parents
+	}
+	attached[mergeBase] = []string{}
+
+	return gitserver.NewCommitGraph(attached, append([]string{mergeBase}, commitGraph.Order()...))
+}
+
 // UploadsVisibleAtCommit returns the set of uploads that are visible from the given commit.
 func (g *Graph) UploadsVisibleAtCommit(commit string) []UploadMeta {
 	ancestorUploads, ancestorDistance := traverseForUploads(g.graph, g.ancestorUploads, commit)
@@ -160,6 +160,50 @@ func TestCalculateVisibleUploadsAlternateCommitGraph(t *testing.T) {
 // Benchmarks
 //
 
+func TestAttachMergeBase(t *testing.T) {
+	// testGraph is a fragment of a pull request branch with the following layout,
+	// where commit b's parent is outside of the fragment:
+	//
+	// b -- c --+-- e
+	//          |
+	//     d ---+
+	//
+	testGraph := gitserver.ParseCommitGraph([]string{
+		"e c d",
+		"d x",
+		"c b",
+		"b y",
+	})
+
+	// [m] is the merge base of e and the default branch
+	commitGraphView := NewCommitGraphView()
+	commitGraphView.Add(UploadMeta{UploadID: 50, Distance: 2}, "m", "sub1/:lsif-go")
+
+	attached := AttachMergeBase(testGraph, "m")
+
+	expectedGraph := map[string][]string{
+		"b": {"y"},
+		"c": {"b"},
+		"d": {"x"},
+		"e": {"c", "d"},
+		"m": {},
+		"x": {"m"},
+		"y": {"m"},
+	}
+	if diff := cmp.Diff(expectedGraph, attached.Graph()); diff != "" {
+		t.Errorf("unexpected graph (-want +got):\n%s", diff)
+	}
+
+	expectedVisibleUploads := []UploadMeta{{UploadID: 50, Distance: 5}}
+	if diff := cmp.Diff(expectedVisibleUploads, NewGraph(attached, commitGraphView).UploadsVisibleAtCommit("e")); diff != "" {
+		t.Errorf("unexpected visible uploads (-want +got):\n%s", diff)
+	}
+
+	if unchanged := AttachMergeBase(attached, "c"); unchanged != attached {
+		t.Errorf("expected graph containing merge base to be returned unchanged")
+	}
+}
+
 func BenchmarkCalculateVisibleUploads(b *testing.B) {
 	commitGraph, err := readBenchmarkCommitGraph()
 	if err != nil {
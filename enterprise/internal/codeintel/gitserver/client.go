@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -93,6 +94,12 @@ type CommitGraph struct {
 	order []string
 }
 
+// NewCommitGraph creates a commit graph from the given mapping from commits to their parents
+// and the given topological ordering of its commits (parents before children).
+func NewCommitGraph(graph map[string][]string, order []string) *CommitGraph {
+	return &CommitGraph{graph: graph, order: order}
+}
+
 func (c *CommitGraph) Graph() map[string][]string { return c.graph }
 func (c *CommitGraph) Order() []string            { return c.order }
 
@@ -136,6 +143,72 @@ func (c *Client) CommitGraph(ctx context.Context, repositoryID int, opts CommitG
 	return ParseCommitGraph(strings.Split(out, "\n")), nil
 }
 
+// MergeBase returns the best common ancestor of the given commit and revision. If the two do not
+// share any history (e.g. the commit belongs to an unrelated fork), a false-valued flag is returned
+// along with a nil error and empty commit.
+func (c *Client) MergeBase(ctx context.Context, repositoryID int, commit, revision string) (_ string, exists bool, err error) {
+	ctx, endObservation := c.operations.mergeBase.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.String("revision", revision),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repo, err := c.repositoryIDToRepo(ctx, repositoryID)
+	if err != nil {
+		return "", false, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "merge-base", commit, revision)
+	cmd.Repo = repo
+
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		// git merge-base exits with status 1 when there is no common ancestor
+		if cmd.ExitStatus == 1 {
+			return "", false, nil
+		}
+
+		return "", false, errors.Wrap(err, "gitserver.Command")
+	}
+
+	return string(bytes.TrimSpace(out)), true, nil
+}
+
+// CommitsOnBranch returns the commits reachable from the tip of the given branch in reverse chronological
+// order. If a since value is supplied, commits with a commit date before that time are not returned. If a
+// positive limit is supplied, only that many of the most recent commits are returned.
+func (c *Client) CommitsOnBranch(ctx context.Context, repositoryID int, branchName string, since *time.Time, limit int) (_ []string, err error) {
+	ctx, endObservation := c.operations.commitsOnBranch.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("branchName", branchName),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	args := []string{"log", "--pretty=%H", "refs/heads/" + branchName}
+	if since != nil {
+		args = append(args, fmt.Sprintf("--since=%s", since.Format(time.RFC3339)))
+	}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+
+	out, err := c.execGitCommand(ctx, repositoryID, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+
+	return commits, nil
+}
+
 // ParseCommitGraph converts the output of git log into a map from commits to parent commits,
 // and a topological ordering of commits such that parents come before children. If a commit
 // is listed but has no ancestors then its parent slice is empty, but is still present in
@@ -370,6 +443,82 @@ func (c *Client) ListFiles(ctx context.Context, repositoryID int, commit string,
 	return matching, nil
 }
 
+// WordMatch is the (zero-based) position of a whole-word occurrence of a search term in a file.
+type WordMatch struct {
+	Path      string
+	Line      int
+	Character int
+}
+
+// SearchWord returns the position of each whole-word occurrence of the given word in the files of a
+// particular commit of a repository. If pathspecs are supplied, only the files matching at least one
+// of them are searched. At most limit matches are returned.
+func (c *Client) SearchWord(ctx context.Context, repositoryID int, commit, word string, pathspecs []string, limit int) (_ []WordMatch, err error) {
+	ctx, endObservation := c.operations.searchWord.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.String("commit", commit),
+		log.String("word", word),
+		log.String("pathspecs", strings.Join(pathspecs, ", ")),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repo, err := c.repositoryIDToRepo(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"grep", "--no-color", "-z", "-n", "--column", "-I", "-w", "-F", "-e", word, commit, "--"}, pathspecs...)
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		// git grep exits with status 1 when there are no matches
+		if cmd.ExitStatus == 1 {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "gitserver.Command")
+	}
+
+	return parseWordMatches(string(out), commit, limit), nil
+}
+
+// parseWordMatches parses the output of git grep invoked with the -z, -n, and --column flags.
+// Each line of output has the form `{commit}:{path}\0{line}\0{column}\0{text}`, where the
+// line and column values are one-based.
+func parseWordMatches(out, commit string, limit int) []WordMatch {
+	var matches []WordMatch
+	for _, line := range strings.Split(out, "\n") {
+		if len(matches) >= limit {
+			break
+		}
+
+		parts := strings.SplitN(line, "\x00", 4)
+		if len(parts) != 4 {
+			continue
+		}
+
+		lineNumber, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		column, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+
+		matches = append(matches, WordMatch{
+			Path:      strings.TrimPrefix(parts[0], commit+":"),
+			Line:      lineNumber - 1,
+			Character: column - 1,
+		})
+	}
+
+	return matches
+}
+
 // ResolveRevision returns the absolute commit for a commit-ish spec.
 func (c *Client) ResolveRevision(ctx context.Context, repositoryID int, versionString string) (commitID api.CommitID, err error) {
 	ctx, endObservation := c.operations.resolveRevision.With(ctx, &err, observation.Args{LogFields: []log.Field{
@@ -1,6 +1,7 @@
 package gitserver
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -207,3 +208,20 @@ func TestParseRefDescriptions(t *testing.T) {
 		t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
 	}
 }
+
+func TestParseWordMatches(t *testing.T) {
+	out := strings.Join([]string{
+		"deadbeef:cmd/main.go\x0012\x005\x00\tfoo := bar()",
+		"deadbeef:internal/bar/bar.go\x003\x006\x00func bar() int {",
+		"deadbeef:internal/bar/bar.go\x0010\x0010\x00\treturn bar()",
+		"",
+	}, "\n")
+
+	expected := []WordMatch{
+		{Path: "cmd/main.go", Line: 11, Character: 4},
+		{Path: "internal/bar/bar.go", Line: 2, Character: 5},
+	}
+	if diff := cmp.Diff(expected, parseWordMatches(out, "deadbeef", 2)); diff != "" {
+		t.Errorf("unexpected word matches (-want +got):\n%s", diff)
+	}
+}
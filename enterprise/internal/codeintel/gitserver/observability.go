@@ -14,13 +14,16 @@ type operations struct {
 	commitDate        *observation.Operation
 	commitExists      *observation.Operation
 	commitGraph       *observation.Operation
+	commitsOnBranch   *observation.Operation
 	directoryChildren *observation.Operation
 	fileExists        *observation.Operation
 	head              *observation.Operation
 	listFiles         *observation.Operation
+	mergeBase         *observation.Operation
 	rawContents       *observation.Operation
 	refDescriptions   *observation.Operation
 	resolveRevision   *observation.Operation
+	searchWord        *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -49,12 +52,15 @@ func newOperations(observationContext *observation.Context) *operations {
 		commitDate:        op("CommitDate"),
 		commitExists:      op("CommitExists"),
 		commitGraph:       op("CommitGraph"),
+		commitsOnBranch:   op("CommitsOnBranch"),
 		directoryChildren: op("DirectoryChildren"),
 		fileExists:        op("FileExists"),
 		head:              op("Head"),
 		listFiles:         op("ListFiles"),
+		mergeBase:         op("MergeBase"),
 		rawContents:       op("RawContents"),
 		refDescriptions:   op("RefDescriptions"),
 		resolveRevision:   op("ResolveRevision"),
+		searchWord:        op("SearchWord"),
 	}
 }
@@ -0,0 +1,3 @@
+package policies
+
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/policies -i GitserverClient -o mock_iface_test.go
@@ -0,0 +1,15 @@
+package policies
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+)
+
+type GitserverClient interface {
+	CommitDate(ctx context.Context, repositoryID int, commit string) (time.Time, error)
+	CommitExists(ctx context.Context, repositoryID int, commit string) (bool, error)
+	CommitsOnBranch(ctx context.Context, repositoryID int, branchName string, since *time.Time, limit int) ([]string, error)
+	RefDescriptions(ctx context.Context, repositoryID int) (map[string]gitserver.RefDescription, error)
+}
@@ -0,0 +1,176 @@
+package policies
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+// PolicyMatch indicates the name of the matching branch, tag, or commit associated with
+// some commit. The policy identifier is nil when the match exists only because the commit
+// is the tip of the default branch, which is always retained.
+type PolicyMatch struct {
+	Name           string
+	PolicyID       *int
+	PolicyDuration *time.Duration
+}
+
+// Matcher determines which commits of a repository are retained by a set of
+// configuration policies.
+type Matcher struct {
+	gitserverClient GitserverClient
+}
+
+func NewMatcher(gitserverClient GitserverClient) *Matcher {
+	return &Matcher{
+		gitserverClient: gitserverClient,
+	}
+}
+
+// CommitsDescribedByPolicy returns a map from commits within the given repository to the set
+// of policy matches that retain that commit as of the given time. Policies that do not have
+// retention enabled are ignored.
+//
+// A GIT_TAG policy retains each tagged commit whose tag name matches the policy pattern and
+// whose tag was created within the retention duration. A GIT_TREE policy retains the tip of
+// each branch whose name matches the policy pattern and whose tip was committed within the
+// retention duration; if the policy retains intermediate commits then every commit on the
+// branch committed within the retention duration is retained as well, up to the retention
+// count of most recent commits on the branch. A GIT_COMMIT policy retains the commit named
+// by its pattern if it was committed within the retention duration. A nil retention duration
+// or retention count is unbounded.
+func (m *Matcher) CommitsDescribedByPolicy(ctx context.Context, repositoryID int, policies []dbstore.ConfigurationPolicy, now time.Time) (map[string][]PolicyMatch, error) {
+	patterns, err := compilePatterns(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	refDescriptions, err := m.gitserverClient.RefDescriptions(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "gitserver.RefDescriptions")
+	}
+
+	commitMap := map[string][]PolicyMatch{}
+	addMatch := func(commit, name string, policy *dbstore.ConfigurationPolicy) {
+		if policy == nil {
+			commitMap[commit] = append(commitMap[commit], PolicyMatch{Name: name})
+			return
+		}
+
+		id := policy.ID
+		commitMap[commit] = append(commitMap[commit], PolicyMatch{
+			Name:           name,
+			PolicyID:       &id,
+			PolicyDuration: policy.RetentionDuration,
+		})
+	}
+
+	for commit, refDescription := range refDescriptions {
+		if refDescription.IsDefaultBranch {
+			addMatch(commit, refDescription.Name, nil)
+		}
+
+		for i := range policies {
+			policy := &policies[i]
+			if !policy.RetentionEnabled || policy.Type == "GIT_COMMIT" || !patterns[policy.ID].Match(refDescription.Name) {
+				continue
+			}
+
+			switch policy.Type {
+			case "GIT_TAG":
+				if refDescription.Type == gitserver.RefTypeTag && withinDuration(refDescription.CreatedDate, policy.RetentionDuration, now) {
+					addMatch(commit, refDescription.Name, policy)
+				}
+
+			case "GIT_TREE":
+				if refDescription.Type != gitserver.RefTypeBranch {
+					continue
+				}
+
+				if !policy.RetainIntermediateCommits {
+					if withinDuration(refDescription.CreatedDate, policy.RetentionDuration, now) {
+						addMatch(commit, refDescription.Name, policy)
+					}
+
+					continue
+				}
+
+				var since *time.Time
+				if policy.RetentionDuration != nil {
+					t := now.Add(-*policy.RetentionDuration)
+					since = &t
+				}
+
+				var limit int
+				if policy.RetentionCount != nil {
+					limit = *policy.RetentionCount
+				}
+
+				commits, err := m.gitserverClient.CommitsOnBranch(ctx, repositoryID, refDescription.Name, since, limit)
+				if err != nil {
+					return nil, errors.Wrap(err, "gitserver.CommitsOnBranch")
+				}
+
+				for _, branchCommit := range commits {
+					addMatch(branchCommit, refDescription.Name, policy)
+				}
+			}
+		}
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.RetentionEnabled || policy.Type != "GIT_COMMIT" {
+			continue
+		}
+
+		exists, err := m.gitserverClient.CommitExists(ctx, repositoryID, policy.Pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "gitserver.CommitExists")
+		}
+		if !exists {
+			continue
+		}
+
+		commitDate, err := m.gitserverClient.CommitDate(ctx, repositoryID, policy.Pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "gitserver.CommitDate")
+		}
+
+		if withinDuration(commitDate, policy.RetentionDuration, now) {
+			addMatch(policy.Pattern, policy.Pattern, policy)
+		}
+	}
+
+	return commitMap, nil
+}
+
+// compilePatterns compiles the pattern of each branch and tag policy into a glob.
+func compilePatterns(policies []dbstore.ConfigurationPolicy) (map[int]glob.Glob, error) {
+	patterns := make(map[int]glob.Glob, len(policies))
+	for _, policy := range policies {
+		if policy.Type == "GIT_COMMIT" {
+			continue
+		}
+
+		pattern, err := glob.Compile(policy.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "illegal pattern %q in configuration policy %d", policy.Pattern, policy.ID)
+		}
+
+		patterns[policy.ID] = pattern
+	}
+
+	return patterns, nil
+}
+
+// withinDuration returns true if the given time is no older than the given duration. A nil
+// duration is unbounded.
+func withinDuration(t time.Time, duration *time.Duration, now time.Time) bool {
+	return duration == nil || now.Sub(t) <= *duration
+}
@@ -0,0 +1,136 @@
+package policies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+func TestCommitsDescribedByPolicy(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	day := 24 * time.Hour
+
+	gitserverClient := NewMockGitserverClient()
+	gitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string]gitserver.RefDescription{
+		"deadbeef01": {Name: "main", Type: gitserver.RefTypeBranch, IsDefaultBranch: true, CreatedDate: now.Add(-1 * day)},
+		"deadbeef02": {Name: "feat/blank", Type: gitserver.RefTypeBranch, CreatedDate: now.Add(-3 * day)},
+		"deadbeef03": {Name: "feat/stale", Type: gitserver.RefTypeBranch, CreatedDate: now.Add(-30 * day)},
+		"deadbeef04": {Name: "v1.2.3", Type: gitserver.RefTypeTag, CreatedDate: now.Add(-100 * day)},
+		"deadbeef05": {Name: "v0.1.0", Type: gitserver.RefTypeTag, CreatedDate: now.Add(-400 * day)},
+	}, nil)
+	gitserverClient.CommitsOnBranchFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, branchName string, since *time.Time, limit int) ([]string, error) {
+		if branchName == "main" {
+			return []string{"deadbeef01", "deadbeef06", "deadbeef07"}, nil
+		}
+		return nil, nil
+	})
+	gitserverClient.CommitExistsFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit string) (bool, error) {
+		return commit == "deadbeef08", nil
+	})
+	gitserverClient.CommitDateFunc.SetDefaultReturn(now.Add(-2*day), nil)
+
+	tenDays := 10 * day
+	oneYear := 365 * day
+
+	policies := []dbstore.ConfigurationPolicy{
+		{ID: 1, Type: "GIT_TREE", Pattern: "feat/*", RetentionEnabled: true, RetentionDuration: &tenDays},
+		{ID: 2, Type: "GIT_TAG", Pattern: "v*", RetentionEnabled: true, RetentionDuration: &oneYear},
+		{ID: 3, Type: "GIT_TREE", Pattern: "main", RetentionEnabled: true, RetentionDuration: &tenDays, RetainIntermediateCommits: true},
+		{ID: 4, Type: "GIT_COMMIT", Pattern: "deadbeef08", RetentionEnabled: true},
+		{ID: 5, Type: "GIT_COMMIT", Pattern: "deadbeef09", RetentionEnabled: true},
+		{ID: 6, Type: "GIT_TREE", Pattern: "*", RetentionEnabled: false},
+	}
+
+	commitMap, err := NewMatcher(gitserverClient).CommitsDescribedByPolicy(context.Background(), 50, policies, now)
+	if err != nil {
+		t.Fatalf("unexpected error finding matching commits: %s", err)
+	}
+
+	id := func(v int) *int { return &v }
+
+	expectedCommitMap := map[string][]PolicyMatch{
+		"deadbeef01": {
+			{Name: "main"},
+			{Name: "main", PolicyID: id(3), PolicyDuration: &tenDays},
+		},
+		"deadbeef02": {{Name: "feat/blank", PolicyID: id(1), PolicyDuration: &tenDays}},
+		"deadbeef04": {{Name: "v1.2.3", PolicyID: id(2), PolicyDuration: &oneYear}},
+		"deadbeef06": {{Name: "main", PolicyID: id(3), PolicyDuration: &tenDays}},
+		"deadbeef07": {{Name: "main", PolicyID: id(3), PolicyDuration: &tenDays}},
+		"deadbeef08": {{Name: "deadbeef08", PolicyID: id(4)}},
+	}
+	if diff := cmp.Diff(expectedCommitMap, commitMap); diff != "" {
+		t.Errorf("unexpected commit map (-want +got):\n%s", diff)
+	}
+
+	if history := gitserverClient.CommitsOnBranchFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to CommitsOnBranch. want=%d have=%d", 1, len(history))
+	} else if since := history[0].Arg3; since == nil || !since.Equal(now.Add(-tenDays)) {
+		t.Errorf("unexpected since argument. want=%s have=%v", now.Add(-tenDays), since)
+	} else if limit := history[0].Arg4; limit != 0 {
+		t.Errorf("unexpected limit argument. want=%d have=%d", 0, limit)
+	}
+}
+
+func TestCommitsDescribedByPolicyRetentionCount(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+
+	gitserverClient := NewMockGitserverClient()
+	gitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string]gitserver.RefDescription{
+		"deadbeef01": {Name: "main", Type: gitserver.RefTypeBranch, IsDefaultBranch: true, CreatedDate: now},
+	}, nil)
+	gitserverClient.CommitsOnBranchFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, branchName string, since *time.Time, limit int) ([]string, error) {
+		commits := []string{"deadbeef01", "deadbeef02", "deadbeef03", "deadbeef04"}
+		if limit > 0 && limit < len(commits) {
+			commits = commits[:limit]
+		}
+		return commits, nil
+	})
+
+	two := 2
+
+	policies := []dbstore.ConfigurationPolicy{
+		{ID: 1, Type: "GIT_TREE", Pattern: "main", RetentionEnabled: true, RetainIntermediateCommits: true, RetentionCount: &two},
+	}
+
+	commitMap, err := NewMatcher(gitserverClient).CommitsDescribedByPolicy(context.Background(), 50, policies, now)
+	if err != nil {
+		t.Fatalf("unexpected error finding matching commits: %s", err)
+	}
+
+	id := func(v int) *int { return &v }
+
+	expectedCommitMap := map[string][]PolicyMatch{
+		"deadbeef01": {
+			{Name: "main"},
+			{Name: "main", PolicyID: id(1)},
+		},
+		"deadbeef02": {{Name: "main", PolicyID: id(1)}},
+	}
+	if diff := cmp.Diff(expectedCommitMap, commitMap); diff != "" {
+		t.Errorf("unexpected commit map (-want +got):\n%s", diff)
+	}
+
+	if history := gitserverClient.CommitsOnBranchFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to CommitsOnBranch. want=%d have=%d", 1, len(history))
+	} else if since := history[0].Arg3; since != nil {
+		t.Errorf("unexpected since argument. want=nil have=%s", since)
+	} else if limit := history[0].Arg4; limit != 2 {
+		t.Errorf("unexpected limit argument. want=%d have=%d", 2, limit)
+	}
+}
+
+func TestCommitsDescribedByPolicyIllegalPattern(t *testing.T) {
+	policies := []dbstore.ConfigurationPolicy{
+		{ID: 1, Type: "GIT_TAG", Pattern: "v[", RetentionEnabled: true},
+	}
+
+	if _, err := NewMatcher(NewMockGitserverClient()).CommitsDescribedByPolicy(context.Background(), 50, policies, time.Now()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
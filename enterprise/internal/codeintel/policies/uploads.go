@@ -0,0 +1,19 @@
+package policies
+
+import "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+
+// PartitionUploads splits the identifiers of the given uploads into those that are retained
+// and those that are expired with respect to the given commit map (as returned by the method
+// CommitsDescribedByPolicy). An upload is retained if it is visible from the tip of a branch
+// or tag, or if its commit is described by at least one retention policy.
+func PartitionUploads(uploads []dbstore.Upload, commitMap map[string][]PolicyMatch) (protectedIDs, expiredIDs []int) {
+	for _, upload := range uploads {
+		if _, ok := commitMap[upload.Commit]; ok || upload.VisibleAtTip {
+			protectedIDs = append(protectedIDs, upload.ID)
+		} else {
+			expiredIDs = append(expiredIDs, upload.ID)
+		}
+	}
+
+	return protectedIDs, expiredIDs
+}
@@ -0,0 +1,30 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+func TestPartitionUploads(t *testing.T) {
+	uploads := []dbstore.Upload{
+		{ID: 1, Commit: "deadbeef01"},
+		{ID: 2, Commit: "deadbeef02"},
+		{ID: 3, Commit: "deadbeef03", VisibleAtTip: true},
+		{ID: 4, Commit: "deadbeef04"},
+	}
+	commitMap := map[string][]PolicyMatch{
+		"deadbeef01": {{Name: "main"}},
+		"deadbeef04": {{Name: "v1.2.3"}},
+	}
+
+	protectedIDs, expiredIDs := PartitionUploads(uploads, commitMap)
+	if diff := cmp.Diff([]int{1, 3, 4}, protectedIDs); diff != "" {
+		t.Errorf("unexpected protected ids (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{2}, expiredIDs); diff != "" {
+		t.Errorf("unexpected expired ids (-want +got):\n%s", diff)
+	}
+}
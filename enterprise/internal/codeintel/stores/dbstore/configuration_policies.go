@@ -24,6 +24,7 @@ type ConfigurationPolicy struct {
 	IndexingEnabled           bool
 	IndexCommitMaxAge         *time.Duration
 	IndexIntermediateCommits  bool
+	RetentionCount            *int
 }
 
 // scanConfigurationPolicies scans a slice of configuration policies from the return value of `*Store.query`.
@@ -50,6 +51,7 @@ func scanConfigurationPolicies(rows *sql.Rows, queryErr error) (_ []Configuratio
 			&configurationPolicy.IndexingEnabled,
 			&indexCommitMaxAgeHours,
 			&configurationPolicy.IndexIntermediateCommits,
+			&configurationPolicy.RetentionCount,
 		); err != nil {
 			return nil, err
 		}
@@ -119,7 +121,8 @@ SELECT
 	retain_intermediate_commits,
 	indexing_enabled,
 	index_commit_max_age_hours,
-	index_intermediate_commits
+	index_intermediate_commits,
+	retention_count
 FROM lsif_configuration_policies
 WHERE %s
 ORDER BY name
@@ -148,7 +151,8 @@ SELECT
 	retain_intermediate_commits,
 	indexing_enabled,
 	index_commit_max_age_hours,
-	index_intermediate_commits
+	index_intermediate_commits,
+	retention_count
 FROM lsif_configuration_policies
 WHERE id = %s
 `
@@ -183,6 +187,7 @@ func (s *Store) CreateConfigurationPolicy(ctx context.Context, configurationPoli
 		configurationPolicy.IndexingEnabled,
 		indexingCOmmitMaxAgeHours,
 		configurationPolicy.IndexIntermediateCommits,
+		configurationPolicy.RetentionCount,
 	)))
 	if err != nil {
 		return ConfigurationPolicy{}, err
@@ -203,8 +208,9 @@ INSERT INTO lsif_configuration_policies (
 	retain_intermediate_commits,
 	indexing_enabled,
 	index_commit_max_age_hours,
-	index_intermediate_commits
-) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+	index_intermediate_commits,
+	retention_count
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING
 	id,
 	repository_id,
@@ -216,7 +222,8 @@ RETURNING
 	retain_intermediate_commits,
 	indexing_enabled,
 	index_commit_max_age_hours,
-	index_intermediate_commits
+	index_intermediate_commits,
+	retention_count
 `
 
 // UpdateConfigurationPolicy updates the fields of the configuration policy record with the given identifier.
@@ -248,6 +255,7 @@ func (s *Store) UpdateConfigurationPolicy(ctx context.Context, policy Configurat
 		policy.IndexingEnabled,
 		indexCommitMaxAge,
 		policy.IndexIntermediateCommits,
+		policy.RetentionCount,
 		policy.ID,
 	))
 }
@@ -264,7 +272,8 @@ SET
 	retain_intermediate_commits = %s,
 	indexing_enabled = %s,
 	index_commit_max_age_hours = %s,
-	index_intermediate_commits = %s
+	index_intermediate_commits = %s,
+	retention_count = %s
 WHERE id = %s
 `
 
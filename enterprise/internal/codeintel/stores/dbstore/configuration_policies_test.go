@@ -160,6 +160,7 @@ func TestCreateConfigurationPolicy(t *testing.T) {
 	repositoryID := 42
 	d1 := time.Hour * 5
 	d2 := time.Hour * 6
+	c1 := 10
 
 	configurationPolicy := ConfigurationPolicy{
 		RepositoryID:              &repositoryID,
@@ -172,6 +173,7 @@ func TestCreateConfigurationPolicy(t *testing.T) {
 		IndexingEnabled:           false,
 		IndexCommitMaxAge:         &d2,
 		IndexIntermediateCommits:  true,
+		RetentionCount:            &c1,
 	}
 
 	hydratedConfigurationPolicy, err := store.CreateConfigurationPolicy(context.Background(), configurationPolicy)
@@ -209,6 +211,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 	repositoryID := 42
 	d1 := time.Hour * 5
 	d2 := time.Hour * 6
+	c1 := 10
 
 	configurationPolicy := ConfigurationPolicy{
 		RepositoryID:              &repositoryID,
@@ -221,6 +224,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 		IndexingEnabled:           false,
 		IndexCommitMaxAge:         &d2,
 		IndexIntermediateCommits:  true,
+		RetentionCount:            &c1,
 	}
 
 	hydratedConfigurationPolicy, err := store.CreateConfigurationPolicy(context.Background(), configurationPolicy)
@@ -235,6 +239,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 
 	d3 := time.Hour * 10
 	d4 := time.Hour * 15
+	c2 := 20
 
 	newConfigurationPolicy := ConfigurationPolicy{
 		ID:                        hydratedConfigurationPolicy.ID,
@@ -249,6 +254,7 @@ func TestUpdateConfigurationPolicy(t *testing.T) {
 		IndexCommitMaxAge:         &d4,
 
 		IndexIntermediateCommits: false,
+		RetentionCount:           &c2,
 	}
 
 	if err := store.UpdateConfigurationPolicy(context.Background(), newConfigurationPolicy); err != nil {
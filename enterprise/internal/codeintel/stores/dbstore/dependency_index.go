@@ -21,6 +21,7 @@ type DependencyIndexingJob struct {
 	ID             int        `json:"id"`
 	State          string     `json:"state"`
 	FailureMessage *string    `json:"failureMessage"`
+	QueuedAt       time.Time  `json:"queuedAt"`
 	StartedAt      *time.Time `json:"startedAt"`
 	FinishedAt     *time.Time `json:"finishedAt"`
 	ProcessAfter   *time.Time `json:"processAfter"`
@@ -48,6 +49,7 @@ func scanDependencyIndexingJobs(rows *sql.Rows, queryErr error) (_ []DependencyI
 			&job.ID,
 			&job.State,
 			&job.FailureMessage,
+			&job.QueuedAt,
 			&job.StartedAt,
 			&job.FinishedAt,
 			&job.ProcessAfter,
@@ -68,6 +70,7 @@ var dependencyIndexingJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("j.id"),
 	sqlf.Sprintf("j.state"),
 	sqlf.Sprintf("j.failure_message"),
+	sqlf.Sprintf("j.queued_at"),
 	sqlf.Sprintf("j.started_at"),
 	sqlf.Sprintf("j.finished_at"),
 	sqlf.Sprintf("j.process_after"),
@@ -12,6 +12,7 @@ type operations struct {
 	calculateVisibleUploads                *observation.Operation
 	commitGraphMetadata                    *observation.Operation
 	createConfigurationPolicy              *observation.Operation
+	defaultBranchDumps                     *observation.Operation
 	definitionDumps                        *observation.Operation
 	deleteConfigurationPolicyByID          *observation.Operation
 	deleteIndexByID                        *observation.Operation
@@ -39,10 +40,12 @@ type operations struct {
 	getUploadByID                          *observation.Operation
 	getUploads                             *observation.Operation
 	getUploadsByIDs                        *observation.Operation
+	getUploadValidationErrors              *observation.Operation
 	hardDeleteUploadByID                   *observation.Operation
 	hasCommit                              *observation.Operation
 	hasRepository                          *observation.Operation
 	indexQueueSize                         *observation.Operation
+	inboundPackageReferences               *observation.Operation
 	insertDependencyIndexingJob            *observation.Operation
 	insertCloneableDependencyRepo          *observation.Operation
 	insertIndex                            *observation.Operation
@@ -58,17 +61,24 @@ type operations struct {
 	queueSize                              *observation.Operation
 	referenceIDsAndFilters                 *observation.Operation
 	referencesForUpload                    *observation.Operation
+	unresolvedReferencesForUpload          *observation.Operation
 	refreshCommitResolvability             *observation.Operation
 	repoName                               *observation.Operation
 	requeue                                *observation.Operation
 	requeueIndex                           *observation.Operation
+	selectRepositoriesForRetentionScan     *observation.Operation
+	softDeleteExpiredUploads               *observation.Operation
 	softDeleteOldUploads                   *observation.Operation
+	staleReferenceCountRepositories        *observation.Operation
 	staleSourcedCommits                    *observation.Operation
 	updateCommitedAt                       *observation.Operation
 	updateConfigurationPolicy              *observation.Operation
 	updateIndexConfigurationByRepositoryID *observation.Operation
 	updatePackageReferences                *observation.Operation
 	updatePackages                         *observation.Operation
+	updateReferenceCounts                  *observation.Operation
+	updateUploadRetention                  *observation.Operation
+	updateUploadValidationErrors           *observation.Operation
 
 	writeVisibleUploads        *observation.Operation
 	persistNearestUploads      *observation.Operation
@@ -99,6 +109,7 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		calculateVisibleUploads:                op("CalculateVisibleUploads"),
 		commitGraphMetadata:                    op("CommitGraphMetadata"),
 		createConfigurationPolicy:              op("CreateConfigurationPolicy"),
+		defaultBranchDumps:                     op("DefaultBranchDumps"),
 		definitionDumps:                        op("DefinitionDumps"),
 		deleteConfigurationPolicyByID:          op("DeleteConfigurationPolicyByID"),
 		deleteIndexByID:                        op("DeleteIndexByID"),
@@ -126,10 +137,12 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		getUploadByID:                          op("GetUploadByID"),
 		getUploads:                             op("GetUploads"),
 		getUploadsByIDs:                        op("GetUploadsByIDs"),
+		getUploadValidationErrors:              op("GetUploadValidationErrors"),
 		hardDeleteUploadByID:                   op("HardDeleteUploadByID"),
 		hasCommit:                              op("HasCommit"),
 		hasRepository:                          op("HasRepository"),
 		indexQueueSize:                         op("IndexQueueSize"),
+		inboundPackageReferences:               op("InboundPackageReferences"),
 		insertDependencyIndexingJob:            op("InsertDependencyIndexingJob"),
 		insertCloneableDependencyRepo:          op("InsertCloneableDependencyRepo"),
 		insertIndex:                            op("InsertIndex"),
@@ -145,17 +158,24 @@ func newOperations(observationContext *observation.Context, metrics *metrics.Ope
 		queueSize:                              op("QueueSize"),
 		referenceIDsAndFilters:                 op("ReferenceIDsAndFilters"),
 		referencesForUpload:                    op("ReferencesForUpload"),
+		unresolvedReferencesForUpload:          op("UnresolvedReferencesForUpload"),
 		refreshCommitResolvability:             op("RefreshCommitResolvability"),
 		repoName:                               op("RepoName"),
 		requeue:                                op("Requeue"),
 		requeueIndex:                           op("RequeueIndex"),
+		selectRepositoriesForRetentionScan:     op("SelectRepositoriesForRetentionScan"),
+		softDeleteExpiredUploads:               op("SoftDeleteExpiredUploads"),
 		softDeleteOldUploads:                   op("SoftDeleteOldUploads"),
+		staleReferenceCountRepositories:        op("StaleReferenceCountRepositories"),
 		staleSourcedCommits:                    op("StaleSourcedCommits"),
 		updateCommitedAt:                       op("UpdateCommitedAt"),
 		updateConfigurationPolicy:              op("UpdateConfigurationPolicy"),
 		updateIndexConfigurationByRepositoryID: op("UpdateIndexConfigurationByRepositoryID"),
 		updatePackageReferences:                op("UpdatePackageReferences"),
 		updatePackages:                         op("UpdatePackages"),
+		updateReferenceCounts:                  op("UpdateReferenceCounts"),
+		updateUploadRetention:                  op("UpdateUploadRetention"),
+		updateUploadValidationErrors:           op("UpdateUploadValidationErrors"),
 
 		writeVisibleUploads:        subOp("writeVisibleUploads"),
 		persistNearestUploads:      subOp("persistNearestUploads"),
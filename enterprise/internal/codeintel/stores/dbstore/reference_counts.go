@@ -0,0 +1,145 @@
+package dbstore
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// StaleReferenceCountRepositories returns the identifiers of repositories with an upload visible from
+// the tip of their default branch whose reference counts have never been calculated or were calculated
+// before the given minimum time since last update. Repositories which have never been ranked are returned
+// first, followed by the repositories with the oldest reference counts.
+func (s *Store) StaleReferenceCountRepositories(ctx context.Context, minimumTimeSinceLastUpdate time.Duration, limit int, now time.Time) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.staleReferenceCountRepositories.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("minimumTimeSinceLastUpdate", minimumTimeSinceLastUpdate.String()),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repositoryIDs, err := basestore.ScanInts(s.Query(ctx, sqlf.Sprintf(
+		staleReferenceCountRepositoriesQuery,
+		now,
+		minimumTimeSinceLastUpdate/time.Second,
+		limit,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRepositories", len(repositoryIDs)))
+
+	return repositoryIDs, nil
+}
+
+const staleReferenceCountRepositoriesQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/reference_counts.go:StaleReferenceCountRepositories
+SELECT repository_id FROM (
+	SELECT DISTINCT uvt.repository_id, rc.updated_at
+	FROM lsif_uploads_visible_at_tip uvt
+	LEFT JOIN lsif_reference_counts rc ON rc.repository_id = uvt.repository_id
+	WHERE uvt.is_default_branch AND (rc.updated_at IS NULL OR %s - rc.updated_at > (%s * '1 second'::interval))
+) s
+ORDER BY updated_at NULLS FIRST, repository_id
+LIMIT %s
+`
+
+// DefaultBranchDumps returns the dumps visible from the tip of the default branch of the given repository.
+func (s *Store) DefaultBranchDumps(ctx context.Context, repositoryID int) (_ []Dump, err error) {
+	ctx, traceLog, endObservation := s.operations.defaultBranchDumps.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	dumps, err := scanDumps(s.Query(ctx, sqlf.Sprintf(defaultBranchDumpsQuery, repositoryID)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numDumps", len(dumps)))
+
+	return dumps, nil
+}
+
+const defaultBranchDumpsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/reference_counts.go:DefaultBranchDumps
+SELECT
+	u.id,
+	u.commit,
+	u.root,
+	TRUE AS visible_at_tip,
+	u.uploaded_at,
+	u.state,
+	u.failure_message,
+	u.started_at,
+	u.finished_at,
+	u.process_after,
+	u.num_resets,
+	u.num_failures,
+	u.repository_id,
+	u.repository_name,
+	u.indexer,
+	u.associated_index_id
+FROM lsif_dumps_with_repository_name u
+WHERE u.id IN (SELECT uvt.upload_id FROM lsif_uploads_visible_at_tip uvt WHERE uvt.repository_id = %s AND uvt.is_default_branch)
+ORDER BY u.id
+`
+
+// InboundPackageReferences returns a scanner over the package references of uploads which depend on
+// a package provided by the given upload. Only uploads belonging to another repository and visible
+// from the tip of the default branch of that repository are considered. Each reference is paired with
+// a bloom filter encoding the set of identifiers imported from the package.
+func (s *Store) InboundPackageReferences(ctx context.Context, repositoryID, uploadID int) (_ PackageReferenceScanner, err error) {
+	ctx, endObservation := s.operations.inboundPackageReferences.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.Int("uploadID", uploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(inboundPackageReferencesQuery, uploadID, repositoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	return packageReferenceScannerFromRows(rows), nil
+}
+
+const inboundPackageReferencesQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/reference_counts.go:InboundPackageReferences
+SELECT r.dump_id, r.scheme, r.name, r.version, r.filter
+FROM lsif_references r
+JOIN lsif_packages p ON p.scheme = r.scheme AND p.name = r.name AND p.version IS NOT DISTINCT FROM r.version
+WHERE
+	p.dump_id = %s AND
+	r.dump_id IN (SELECT uvt.upload_id FROM lsif_uploads_visible_at_tip uvt WHERE uvt.repository_id != %s AND uvt.is_default_branch)
+ORDER BY r.dump_id
+`
+
+// UpdateReferenceCounts replaces the per-path inbound reference counts of the given repository.
+func (s *Store) UpdateReferenceCounts(ctx context.Context, repositoryID int, pathCounts map[string]int, now time.Time) (err error) {
+	ctx, endObservation := s.operations.updateReferenceCounts.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+		log.Int("numPaths", len(pathCounts)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	serializedPathCounts, err := json.Marshal(pathCounts)
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(updateReferenceCountsQuery, repositoryID, serializedPathCounts, now))
+}
+
+const updateReferenceCountsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/reference_counts.go:UpdateReferenceCounts
+INSERT INTO lsif_reference_counts (repository_id, path_counts, updated_at)
+VALUES (%s, %s, %s)
+ON CONFLICT (repository_id) DO UPDATE SET
+	path_counts = EXCLUDED.path_counts,
+	updated_at = EXCLUDED.updated_at
+`
@@ -0,0 +1,130 @@
+package dbstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStaleReferenceCountRepositories(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+	now := time.Unix(1587396557, 0).UTC()
+
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50},
+		Upload{ID: 2, RepositoryID: 51},
+		Upload{ID: 3, RepositoryID: 52},
+		Upload{ID: 4, RepositoryID: 53},
+	)
+	insertVisibleAtTip(t, db, 50, 1)
+	insertVisibleAtTip(t, db, 51, 2)
+	insertVisibleAtTip(t, db, 52, 3)
+	insertVisibleAtTipNonDefaultBranch(t, db, 53, 4)
+
+	if err := store.UpdateReferenceCounts(context.Background(), 50, map[string]int{"a.go": 1}, now.Add(-time.Hour)); err != nil {
+		t.Fatalf("unexpected error updating reference counts: %s", err)
+	}
+	if err := store.UpdateReferenceCounts(context.Background(), 51, map[string]int{"b.go": 1}, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("unexpected error updating reference counts: %s", err)
+	}
+
+	repositoryIDs, err := store.StaleReferenceCountRepositories(context.Background(), time.Minute*30, 10, now)
+	if err != nil {
+		t.Fatalf("unexpected error getting stale repositories: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{52, 50}, repositoryIDs); diff != "" {
+		t.Errorf("unexpected repository ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestInboundPackageReferences(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50},
+		Upload{ID: 2, RepositoryID: 50}, // same repository
+		Upload{ID: 3, RepositoryID: 51},
+		Upload{ID: 4, RepositoryID: 52},
+		Upload{ID: 5, RepositoryID: 53}, // not visible from the default branch
+		Upload{ID: 6, RepositoryID: 54}, // references another package
+	)
+	insertVisibleAtTip(t, db, 50, 1, 2)
+	insertVisibleAtTip(t, db, 51, 3)
+	insertVisibleAtTip(t, db, 52, 4)
+	insertVisibleAtTipNonDefaultBranch(t, db, 53, 5)
+	insertVisibleAtTip(t, db, 54, 6)
+
+	insertPackages(t, store, []lsifstore.Package{
+		{DumpID: 1, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"},
+	})
+	insertPackageReferences(t, store, []lsifstore.PackageReference{
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f2")},
+		{Package: lsifstore.Package{DumpID: 3, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f3")},
+		{Package: lsifstore.Package{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f4")},
+		{Package: lsifstore.Package{DumpID: 5, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f5")},
+		{Package: lsifstore.Package{DumpID: 6, Scheme: "gomod", Name: "rightpad", Version: "0.1.0"}, Filter: []byte("f6")},
+	})
+
+	scanner, err := store.InboundPackageReferences(context.Background(), 50, 1)
+	if err != nil {
+		t.Fatalf("unexpected error getting inbound references: %s", err)
+	}
+
+	references, err := consumeScanner(scanner)
+	if err != nil {
+		t.Fatalf("unexpected error from scanner: %s", err)
+	}
+
+	expected := []lsifstore.PackageReference{
+		{Package: lsifstore.Package{DumpID: 3, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f3")},
+		{Package: lsifstore.Package{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"}, Filter: []byte("f4")},
+	}
+	if diff := cmp.Diff(expected, references); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+}
+
+func TestDefaultBranchDumps(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50, Root: "sub1/"},
+		Upload{ID: 2, RepositoryID: 50, Root: "sub2/"},
+		Upload{ID: 3, RepositoryID: 50, Root: "sub3/"}, // not visible from the default branch
+		Upload{ID: 4, RepositoryID: 51, Root: "sub1/"}, // another repository
+	)
+	insertVisibleAtTip(t, db, 50, 1, 2)
+	insertVisibleAtTipNonDefaultBranch(t, db, 50, 3)
+	insertVisibleAtTip(t, db, 51, 4)
+
+	dumps, err := store.DefaultBranchDumps(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting dumps: %s", err)
+	}
+
+	var ids []int
+	for _, dump := range dumps {
+		ids = append(ids, dump.ID)
+	}
+	if diff := cmp.Diff([]int{1, 2}, ids); diff != "" {
+		t.Errorf("unexpected dump ids (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,193 @@
+package dbstore
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// SelectRepositoriesForRetentionScan returns a set of repository identifiers with live code intelligence
+// data that should be checked against data retention policies. Repositories which have never been scanned
+// are returned first, followed by the repositories which were scanned least recently. Repositories which
+// were scanned more recently than the given process delay are not returned. The last scan time of each of
+// the returned repositories is updated to the given time.
+func (s *Store) SelectRepositoriesForRetentionScan(ctx context.Context, processDelay time.Duration, limit int, now time.Time) (_ []int, err error) {
+	ctx, traceLog, endObservation := s.operations.selectRepositoriesForRetentionScan.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("processDelay", processDelay.String()),
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repositoryIDs, err := basestore.ScanInts(s.Query(ctx, sqlf.Sprintf(
+		selectRepositoriesForRetentionScanQuery,
+		now,
+		processDelay/time.Second,
+		limit,
+		now,
+		now,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRepositories", len(repositoryIDs)))
+
+	return repositoryIDs, nil
+}
+
+const selectRepositoriesForRetentionScanQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/retention.go:SelectRepositoriesForRetentionScan
+WITH candidate_repositories AS (
+	SELECT DISTINCT u.repository_id AS id
+	FROM lsif_uploads u
+	WHERE u.state = 'completed'
+),
+repositories AS (
+	SELECT cr.id
+	FROM candidate_repositories cr
+	LEFT JOIN lsif_last_retention_scan lrs ON lrs.repository_id = cr.id
+	WHERE lrs.last_retention_scan_at IS NULL OR %s - lrs.last_retention_scan_at > (%s * '1 second'::interval)
+	ORDER BY lrs.last_retention_scan_at NULLS FIRST, cr.id
+	LIMIT %s
+)
+INSERT INTO lsif_last_retention_scan (repository_id, last_retention_scan_at)
+SELECT r.id, %s FROM repositories r
+ON CONFLICT (repository_id) DO UPDATE SET last_retention_scan_at = %s
+RETURNING repository_id
+`
+
+// UpdateUploadRetention marks the given uploads as protected or expired. Expired uploads are removed by
+// SoftDeleteExpiredUploads once no protected upload depends on them.
+func (s *Store) UpdateUploadRetention(ctx context.Context, protectedIDs, expiredIDs []int) (err error) {
+	ctx, endObservation := s.operations.updateUploadRetention.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("numProtectedIDs", len(protectedIDs)),
+		log.Int("numExpiredIDs", len(expiredIDs)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	for _, update := range []struct {
+		ids     []int
+		expired bool
+	}{
+		{protectedIDs, false},
+		{expiredIDs, true},
+	} {
+		if len(update.ids) == 0 {
+			continue
+		}
+
+		// Ensure ids are sorted so that we take row locks during the
+		// UPDATE query in a determinstic order. This should prevent
+		// deadlocks with other queries that mass update lsif_uploads.
+		sort.Ints(update.ids)
+
+		var idQueries []*sqlf.Query
+		for _, id := range update.ids {
+			idQueries = append(idQueries, sqlf.Sprintf("%s", id))
+		}
+
+		if err := tx.Store.Exec(ctx, sqlf.Sprintf(updateUploadRetentionQuery, update.expired, sqlf.Join(idQueries, ", "))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const updateUploadRetentionQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/retention.go:UpdateUploadRetention
+UPDATE lsif_uploads SET expired = %s WHERE id IN (%s)
+`
+
+// SoftDeleteExpiredUploads marks upload records that are both expired and have no references as deleted.
+// Uploads visible from the tip of a branch or tag and uploads that define a package referenced by another
+// unexpired upload are never deleted. The associated repositories will be marked as dirty so that their
+// commit graphs are updated in the background.
+func (s *Store) SoftDeleteExpiredUploads(ctx context.Context) (count int, err error) {
+	ctx, traceLog, endObservation := s.operations.softDeleteExpiredUploads.WithAndLogger(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	repositories, err := scanCounts(tx.Store.Query(ctx, sqlf.Sprintf(softDeleteExpiredUploadsQuery)))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, numUpdated := range repositories {
+		count += numUpdated
+	}
+	traceLog(
+		log.Int("count", count),
+		log.Int("numRepositories", len(repositories)),
+	)
+
+	for repositoryID := range repositories {
+		if err := tx.MarkRepositoryAsDirty(ctx, repositoryID); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+const softDeleteExpiredUploadsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/retention.go:SoftDeleteExpiredUploads
+WITH RECURSIVE
+protected_uploads AS (
+	(
+		-- Base case: select all completed upload records that are protected by
+		-- a data retention policy, as well as all upload records visible from
+		-- the tip of a branch or tag. These form the roots of our dependency
+		-- graph traversal.
+
+		SELECT u.id FROM lsif_uploads u
+		WHERE u.state = 'completed' AND NOT u.expired
+		UNION
+		SELECT upload_id as id FROM lsif_uploads_visible_at_tip
+	) UNION (
+		-- Iterative case: expand the working set of protected uploads by traversing
+		-- the dependency graph: select all upload records that define an LSIF package
+		-- that is referenced by an upload already in the working set. We skip any
+		-- self-imports here, which may occur on some older Sourcegraph instances.
+
+		SELECT p.dump_id as id
+		FROM protected_uploads pu
+		JOIN lsif_references r ON r.dump_id = pu.id
+		JOIN lsif_packages p ON p.scheme = r.scheme AND p.name = r.name AND p.version = r.version AND p.dump_id != r.dump_id
+	)
+),
+candidates AS (
+	-- Find the expired upload records that are not reachable via the
+	-- dependencies of any upload in protected_uploads.
+	SELECT u.id
+	FROM lsif_uploads u
+	WHERE u.state = 'completed' AND u.expired AND u.id NOT IN (SELECT id FROM protected_uploads)
+
+	-- Lock these rows in a deterministic order so that we don't
+	-- deadlock with other processes updating the lsif_uploads table.
+	ORDER BY u.id FOR UPDATE
+),
+updated AS (
+	UPDATE lsif_uploads u
+	SET state = 'deleting'
+	WHERE u.id IN (SELECT id FROM candidates)
+	RETURNING u.id, u.repository_id
+)
+SELECT u.repository_id, count(*) FROM updated u GROUP BY u.repository_id
+`
@@ -0,0 +1,199 @@
+package dbstore
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestSelectRepositoriesForRetentionScan(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+	now := time.Unix(1587396557, 0).UTC()
+
+	insertUploads(t, db,
+		Upload{ID: 1, RepositoryID: 50},
+		Upload{ID: 2, RepositoryID: 51},
+		Upload{ID: 3, RepositoryID: 52},
+		Upload{ID: 4, RepositoryID: 53},
+		Upload{ID: 5, RepositoryID: 54, State: "errored"},
+	)
+
+	for _, testCase := range []struct {
+		now         time.Time
+		expectedIDs []int
+		name        string
+	}{
+		{now, []int{50, 51}, "first batch"},
+		{now, []int{52, 53}, "second batch"},
+		{now, nil, "all repositories scanned recently"},
+		{now.Add(time.Hour * 2), []int{50, 51}, "process delay elapsed"},
+	} {
+		repositoryIDs, err := store.SelectRepositoriesForRetentionScan(context.Background(), time.Hour, 2, testCase.now)
+		if err != nil {
+			t.Fatalf("unexpected error selecting repositories for retention scan: %s", err)
+		}
+		sort.Ints(repositoryIDs)
+
+		if diff := cmp.Diff(testCase.expectedIDs, repositoryIDs); diff != "" {
+			t.Errorf("unexpected repository identifiers for %s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
+
+func TestUpdateUploadRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1},
+		Upload{ID: 2},
+		Upload{ID: 3},
+		Upload{ID: 4},
+		Upload{ID: 5},
+	)
+
+	if err := store.UpdateUploadRetention(context.Background(), []int{}, []int{2, 3, 4}); err != nil {
+		t.Fatalf("unexpected error marking uploads as expired: %s", err)
+	}
+	if err := store.UpdateUploadRetention(context.Background(), []int{3}, nil); err != nil {
+		t.Fatalf("unexpected error marking uploads as protected: %s", err)
+	}
+
+	expiredIDs, err := basestore.ScanInts(db.Query("SELECT id FROM lsif_uploads WHERE expired ORDER BY id"))
+	if err != nil {
+		t.Fatalf("unexpected error querying expired uploads: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{2, 4}, expiredIDs); diff != "" {
+		t.Errorf("unexpected expired uploads (-want +got):\n%s", diff)
+	}
+}
+
+func TestSoftDeleteExpiredUploads(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	tests := []struct {
+		upload        Upload
+		expired       bool
+		expectedState string
+	}{
+		// not completed or not expired
+		{upload: Upload{ID: 11, State: "uploaded"}, expired: true, expectedState: "uploaded"},
+		{upload: Upload{ID: 12, State: "errored"}, expired: true, expectedState: "errored"},
+		{upload: Upload{ID: 13}, expired: false, expectedState: "completed"},
+
+		// visible from the tip of a branch
+		{upload: Upload{ID: 14}, expired: true, expectedState: "completed"},
+		{upload: Upload{ID: 15}, expired: true, expectedState: "completed"},
+		{upload: Upload{ID: 16}, expired: true, expectedState: "completed"},
+
+		// expired and only reachable from other deletion candidates
+		{upload: Upload{ID: 19}, expired: true, expectedState: "deleting"},
+		{upload: Upload{ID: 20}, expired: true, expectedState: "deleting"}, // dependency of 19
+
+		// expired, but dependency of a non-deletion candidate
+		{upload: Upload{ID: 21}, expired: true, expectedState: "completed"}, // dependency of 13
+		{upload: Upload{ID: 22}, expired: true, expectedState: "completed"}, // dependency of 14
+		{upload: Upload{ID: 23}, expired: true, expectedState: "completed"}, // dependency of 16
+		{upload: Upload{ID: 24}, expired: true, expectedState: "completed"}, // dependency of 16 (via 23)
+	}
+
+	var uploads []Upload
+	var expiredIDs []int
+	for _, test := range tests {
+		uploads = append(uploads, test.upload)
+
+		if test.expired {
+			expiredIDs = append(expiredIDs, test.upload.ID)
+		}
+	}
+
+	insertUploads(t, db, uploads...)
+	insertVisibleAtTip(t, db, 50, 14, 15)
+	insertVisibleAtTipNonDefaultBranch(t, db, 50, 16)
+
+	if err := store.UpdateUploadRetention(context.Background(), nil, expiredIDs); err != nil {
+		t.Fatalf("unexpected error marking uploads as expired: %s", err)
+	}
+
+	packages := map[int][]precise.Package{
+		20: {{Scheme: "s0", Name: "n0", Version: "v0"}},
+		21: {{Scheme: "s1", Name: "n1", Version: "v1"}},
+		22: {{Scheme: "s2", Name: "n2", Version: "v2"}},
+		23: {{Scheme: "s3", Name: "n3", Version: "v3"}},
+		24: {{Scheme: "s4", Name: "n4", Version: "v4"}},
+	}
+	references := map[int][]precise.PackageReference{
+		13: {{Package: precise.Package{Scheme: "s1", Name: "n1", Version: "v1"}}},
+		14: {{Package: precise.Package{Scheme: "s2", Name: "n2", Version: "v2"}}},
+		16: {{Package: precise.Package{Scheme: "s3", Name: "n3", Version: "v3"}}},
+		19: {{Package: precise.Package{Scheme: "s0", Name: "n0", Version: "v0"}}},
+		23: {{Package: precise.Package{Scheme: "s4", Name: "n4", Version: "v4"}}},
+	}
+
+	for id, packages := range packages {
+		if err := store.UpdatePackages(context.Background(), id, packages); err != nil {
+			t.Fatalf("unexpected error updating packages: %s", err)
+		}
+	}
+	for id, references := range references {
+		if err := store.UpdatePackageReferences(context.Background(), id, references); err != nil {
+			t.Fatalf("unexpected error updating package references: %s", err)
+		}
+	}
+
+	if count, err := store.SoftDeleteExpiredUploads(context.Background()); err != nil {
+		t.Fatalf("unexpected error soft deleting uploads: %s", err)
+	} else if count != 2 {
+		t.Fatalf("unexpected number of uploads deleted: want=%d have=%d", 2, count)
+	}
+
+	var uploadIDs []int
+	expectedStates := map[int]string{}
+	for _, test := range tests {
+		id := test.upload.ID
+		uploadIDs = append(uploadIDs, id)
+		expectedStates[id] = test.expectedState
+	}
+
+	// Ensure records were deleted
+	if states, err := getUploadStates(db, uploadIDs...); err != nil {
+		t.Fatalf("unexpected error getting states: %s", err)
+	} else if diff := cmp.Diff(expectedStates, states); diff != "" {
+		t.Errorf("unexpected upload states (-want +got):\n%s", diff)
+	}
+
+	// Ensure repository was marked as dirty
+	repositoryIDs, err := store.DirtyRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing dirty repositories: %s", err)
+	}
+
+	var keys []int
+	for repositoryID := range repositoryIDs {
+		keys = append(keys, repositoryID)
+	}
+	sort.Ints(keys)
+
+	if len(keys) != 1 || keys[0] != 50 {
+		t.Errorf("expected repository to be marked dirty")
+	}
+}
@@ -689,9 +689,9 @@ const hardDeleteUploadByIDQuery = `
 DELETE FROM lsif_uploads WHERE id IN (%s)
 `
 
-// SoftDeleteOldUploads marks uploads older than the given age that are not visible at the tip of the default branch
-// as deleted. The associated repositories will be marked as dirty so that their commit graphs are updated in the
-// background.
+// SoftDeleteOldUploads marks upload records older than the given age that never completed processing as deleted.
+// Completed uploads are expired via data retention policies instead (see SoftDeleteExpiredUploads). The associated
+// repositories will be marked as dirty so that their commit graphs are updated in the background.
 func (s *Store) SoftDeleteOldUploads(ctx context.Context, maxAge time.Duration, now time.Time) (count int, err error) {
 	ctx, traceLog, endObservation := s.operations.softDeleteOldUploads.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.String("maxAge", maxAge.String()),
@@ -729,36 +729,12 @@ func (s *Store) SoftDeleteOldUploads(ctx context.Context, maxAge time.Duration,
 
 const softDeleteOldUploadsQuery = `
 -- source: enterprise/internal/codeintel/stores/dbstore/uploads.go:SoftDeleteOldUploads
-WITH RECURSIVE
-protected_uploads AS (
-	(
-		-- Base case: select all upload records that are yonger than the configured
-		-- retention age, as well as all upload records visible from a non-stale
-		-- branch or tag. These form the roots of our dependency graph traversal.
-
-		SELECT u.id FROM lsif_uploads u
-		WHERE %s - COALESCE(u.finished_at, u.uploaded_at) <= (%s || ' second')::interval
-		UNION
-		SELECT upload_id as id FROM lsif_uploads_visible_at_tip
-	) UNION (
-		-- Iterative case: expand the working set of protected uploads by traversing
-		-- the dependency graph: select all upload records that define an LSIF package
-		-- that is referenced by an upload already in the working set. We skip any
-		-- self-imports here, which may occur on some older Sourcegraph instances.
-
-		SELECT p.dump_id as id
-		FROM protected_uploads pu
-		JOIN lsif_references r ON r.dump_id = pu.id
-		JOIN lsif_packages p ON p.scheme = r.scheme AND p.name = r.name AND p.version = r.version AND p.dump_id != r.dump_id
-	)
-),
-candidates AS (
-	-- Find the inverse of protected_uploads, which contains each upload record
-	-- that is older than the configured retention age and is not reachable via
-	-- the dependencies of any upload in protected_uploads.
+WITH candidates AS (
 	SELECT u.id
 	FROM lsif_uploads u
-	WHERE u.id NOT IN (SELECT id FROM protected_uploads)
+	WHERE
+		u.state NOT IN ('completed', 'deleting', 'deleted') AND
+		%s - COALESCE(u.finished_at, u.uploaded_at) > (%s || ' second')::interval
 
 	-- Lock these rows in a deterministic order so that we don't
 	-- deadlock with other processes updating the lsif_uploads table.
@@ -766,7 +742,7 @@ candidates AS (
 ),
 updated AS (
 	UPDATE lsif_uploads u
-	SET state = CASE WHEN u.state = 'completed' THEN 'deleting' ELSE 'deleted' END
+	SET state = 'deleted'
 	WHERE u.id IN (SELECT id FROM candidates)
 	RETURNING u.id, u.repository_id
 )
@@ -14,7 +14,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
-	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -896,63 +895,25 @@ func TestSoftDeleteOldUploads(t *testing.T) {
 		{upload: Upload{ID: 12, State: "errored", FinishedAt: &t4}, expectedState: "errored"},
 		{upload: Upload{ID: 13, State: "completed", FinishedAt: &t4}, expectedState: "completed"},
 
-		// protected on a branch
-		{upload: Upload{ID: 14, State: "completed", FinishedAt: &t2}, expectedState: "completed"},
-		{upload: Upload{ID: 15, State: "completed", FinishedAt: &t3}, expectedState: "completed"},
-		{upload: Upload{ID: 16, State: "completed", FinishedAt: &t2}, expectedState: "completed"},
+		// old, but completed (subject to data retention policies instead)
+		{upload: Upload{ID: 14, State: "completed", FinishedAt: &t1}, expectedState: "completed"},
+		{upload: Upload{ID: 15, State: "completed", FinishedAt: &t2}, expectedState: "completed"},
 
-		// old and only reachable from other deletion candidates
-		{upload: Upload{ID: 17, State: "uploaded", UploadedAt: t3}, expectedState: "deleted"},
-		{upload: Upload{ID: 18, State: "errored", FinishedAt: &t2}, expectedState: "deleted"},
-		{upload: Upload{ID: 19, State: "completed", FinishedAt: &t1}, expectedState: "deleting"},
-		{upload: Upload{ID: 20, State: "completed", FinishedAt: &t3}, expectedState: "deleting"}, // dependency of 19
-
-		// old, but dependency of a non-deletion candidate
-		{upload: Upload{ID: 21, State: "completed", FinishedAt: &t1}, expectedState: "completed"}, // dependency of 13
-		{upload: Upload{ID: 22, State: "completed", FinishedAt: &t1}, expectedState: "completed"}, // dependency of 14
-		{upload: Upload{ID: 23, State: "completed", FinishedAt: &t1}, expectedState: "completed"}, // dependency of 16
-		{upload: Upload{ID: 24, State: "completed", FinishedAt: &t1}, expectedState: "completed"}, // dependency of 16 (via 23)
+		// old and never completed
+		{upload: Upload{ID: 16, State: "uploaded", UploadedAt: t3}, expectedState: "deleted"},
+		{upload: Upload{ID: 17, State: "errored", FinishedAt: &t2}, expectedState: "deleted"},
 	}
 
 	var uploads []Upload
 	for _, test := range tests {
 		uploads = append(uploads, test.upload)
 	}
-
 	insertUploads(t, db, uploads...)
-	insertVisibleAtTip(t, db, 50, 14, 15)
-	insertVisibleAtTipNonDefaultBranch(t, db, 50, 16)
-
-	packages := map[int][]precise.Package{
-		20: {{Scheme: "s0", Name: "n0", Version: "v0"}},
-		21: {{Scheme: "s1", Name: "n1", Version: "v1"}},
-		22: {{Scheme: "s2", Name: "n2", Version: "v2"}},
-		23: {{Scheme: "s3", Name: "n3", Version: "v3"}},
-		24: {{Scheme: "s4", Name: "n4", Version: "v4"}},
-	}
-	references := map[int][]precise.PackageReference{
-		13: {{Package: precise.Package{Scheme: "s1", Name: "n1", Version: "v1"}}},
-		14: {{Package: precise.Package{Scheme: "s2", Name: "n2", Version: "v2"}}},
-		16: {{Package: precise.Package{Scheme: "s3", Name: "n3", Version: "v3"}}},
-		19: {{Package: precise.Package{Scheme: "s0", Name: "n0", Version: "v0"}}},
-		23: {{Package: precise.Package{Scheme: "s4", Name: "n4", Version: "v4"}}},
-	}
-
-	for id, packages := range packages {
-		if err := store.UpdatePackages(context.Background(), id, packages); err != nil {
-			t.Fatalf("unexpected error updating packages: %s", err)
-		}
-	}
-	for id, references := range references {
-		if err := store.UpdatePackageReferences(context.Background(), id, references); err != nil {
-			t.Fatalf("unexpected error updating package references: %s", err)
-		}
-	}
 
 	if count, err := store.SoftDeleteOldUploads(context.Background(), time.Minute, t1.Add(time.Minute*6)); err != nil {
 		t.Fatalf("unexpected error soft deleting uploads: %s", err)
-	} else if count != 4 {
-		t.Fatalf("unexpected number of uploads deleted: want=%d have=%d", 4, count)
+	} else if count != 2 {
+		t.Fatalf("unexpected number of uploads deleted: want=%d have=%d", 2, count)
 	}
 
 	var uploadIDs []int
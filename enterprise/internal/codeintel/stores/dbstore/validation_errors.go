@@ -0,0 +1,122 @@
+package dbstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// UploadValidationError is a problem detected while validating the raw LSIF data of an upload.
+type UploadValidationError struct {
+	Severity      string `json:"severity"`
+	Message       string `json:"message"`
+	RelevantLines []int  `json:"relevantLines"`
+}
+
+// scanUploadValidationErrors scans a slice of upload validation errors from the return value of `*Store.query`.
+func scanUploadValidationErrors(rows *sql.Rows, queryErr error) (_ []UploadValidationError, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var validationErrors []UploadValidationError
+	for rows.Next() {
+		var validationError UploadValidationError
+		var rawRelevantLines []sql.NullInt32
+		if err := rows.Scan(
+			&validationError.Severity,
+			&validationError.Message,
+			pq.Array(&rawRelevantLines),
+		); err != nil {
+			return nil, err
+		}
+
+		relevantLines := make([]int, 0, len(rawRelevantLines))
+		for _, relevantLine := range rawRelevantLines {
+			relevantLines = append(relevantLines, int(relevantLine.Int32))
+		}
+		validationError.RelevantLines = relevantLines
+
+		validationErrors = append(validationErrors, validationError)
+	}
+
+	return validationErrors, nil
+}
+
+// GetUploadValidationErrors returns the validation errors recorded for the given upload in the order
+// in which they were detected.
+func (s *Store) GetUploadValidationErrors(ctx context.Context, uploadID int) (_ []UploadValidationError, err error) {
+	ctx, endObservation := s.operations.getUploadValidationErrors.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return scanUploadValidationErrors(s.Store.Query(ctx, sqlf.Sprintf(getUploadValidationErrorsQuery, uploadID)))
+}
+
+const getUploadValidationErrorsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/validation_errors.go:GetUploadValidationErrors
+SELECT e.severity, e.message, e.relevant_lines
+FROM lsif_upload_validation_errors e
+WHERE e.upload_id = %s
+ORDER BY e.id
+`
+
+// UpdateUploadValidationErrors replaces the validation errors recorded for the given upload.
+func (s *Store) UpdateUploadValidationErrors(ctx context.Context, uploadID int, validationErrors []UploadValidationError) (err error) {
+	ctx, endObservation := s.operations.updateUploadValidationErrors.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+		log.Int("numValidationErrors", len(validationErrors)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	tx, err := s.transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteUploadValidationErrorsQuery, uploadID)); err != nil {
+		return err
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		relevantLines := validationError.RelevantLines
+		if relevantLines == nil {
+			relevantLines = []int{}
+		}
+
+		values = append(values, sqlf.Sprintf(
+			"(%s, %s, %s, %s)",
+			uploadID,
+			validationError.Severity,
+			validationError.Message,
+			pq.Array(relevantLines),
+		))
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(insertUploadValidationErrorsQuery, sqlf.Join(values, ", ")))
+}
+
+const deleteUploadValidationErrorsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/validation_errors.go:UpdateUploadValidationErrors
+DELETE FROM lsif_upload_validation_errors WHERE upload_id = %s
+`
+
+const insertUploadValidationErrorsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/validation_errors.go:UpdateUploadValidationErrors
+INSERT INTO lsif_upload_validation_errors (upload_id, severity, message, relevant_lines)
+VALUES %s
+`
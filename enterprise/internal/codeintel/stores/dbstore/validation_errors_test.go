@@ -0,0 +1,81 @@
+package dbstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestUpdateUploadValidationErrors(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	// for foreign key relation
+	insertUploads(t, db, Upload{ID: 42}, Upload{ID: 43})
+
+	if err := store.UpdateUploadValidationErrors(context.Background(), 42, []UploadValidationError{
+		{Severity: "ERROR", Message: "stale", RelevantLines: []int{1}},
+	}); err != nil {
+		t.Fatalf("unexpected error updating validation errors: %s", err)
+	}
+
+	expected := []UploadValidationError{
+		{Severity: "ERROR", Message: "range 4 is not contained in any document", RelevantLines: []int{4}},
+		{Severity: "WARNING", Message: "ranges overlap", RelevantLines: []int{5, 6}},
+		{Severity: "WARNING", Message: "vertex 7 unreachable", RelevantLines: []int{}},
+	}
+	if err := store.UpdateUploadValidationErrors(context.Background(), 42, []UploadValidationError{
+		expected[0],
+		expected[1],
+		{Severity: "WARNING", Message: "vertex 7 unreachable"},
+	}); err != nil {
+		t.Fatalf("unexpected error updating validation errors: %s", err)
+	}
+	if err := store.UpdateUploadValidationErrors(context.Background(), 43, []UploadValidationError{
+		{Severity: "ERROR", Message: "other upload", RelevantLines: []int{1}},
+	}); err != nil {
+		t.Fatalf("unexpected error updating validation errors: %s", err)
+	}
+
+	validationErrors, err := store.GetUploadValidationErrors(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error getting validation errors: %s", err)
+	}
+	if diff := cmp.Diff(expected, validationErrors); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateUploadValidationErrorsEmpty(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	// for foreign key relation
+	insertUploads(t, db, Upload{ID: 42})
+
+	if err := store.UpdateUploadValidationErrors(context.Background(), 42, []UploadValidationError{
+		{Severity: "ERROR", Message: "stale", RelevantLines: []int{1}},
+	}); err != nil {
+		t.Fatalf("unexpected error updating validation errors: %s", err)
+	}
+	if err := store.UpdateUploadValidationErrors(context.Background(), 42, nil); err != nil {
+		t.Fatalf("unexpected error updating validation errors: %s", err)
+	}
+
+	validationErrors, err := store.GetUploadValidationErrors(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("unexpected error getting validation errors: %s", err)
+	}
+	if len(validationErrors) != 0 {
+		t.Errorf("unexpected validation errors. want=%d have=%d", 0, len(validationErrors))
+	}
+}
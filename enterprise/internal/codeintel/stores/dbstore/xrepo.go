@@ -181,3 +181,40 @@ FROM lsif_references r
 WHERE dump_id = %s
 ORDER BY r.scheme, r.name, r.version
 `
+
+// UnresolvedReferencesForUpload returns the subset of import monikers attached to the given upload
+// identifier that are not provided by any completed upload. These are the dependencies of the upload
+// that have not yet been indexed. As with ReferencesForUpload, the scanner will return nulls for the
+// Filter field.
+func (s *Store) UnresolvedReferencesForUpload(ctx context.Context, uploadID int) (_ PackageReferenceScanner, err error) {
+	ctx, endObservation := s.operations.unresolvedReferencesForUpload.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("uploadID", uploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(unresolvedReferencesForUploadQuery, uploadID))
+	if err != nil {
+		return nil, err
+	}
+
+	return packageReferenceScannerFromRows(rows), nil
+}
+
+const unresolvedReferencesForUploadQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/xrepo.go:UnresolvedReferencesForUpload
+SELECT r.dump_id, r.scheme, r.name, r.version, NULL as filter
+FROM lsif_references r
+WHERE
+	r.dump_id = %s AND
+	NOT EXISTS (
+		SELECT 1
+		FROM lsif_packages p
+		JOIN lsif_uploads u ON u.id = p.dump_id
+		WHERE
+			p.scheme = r.scheme AND
+			p.name = r.name AND
+			p.version = r.version AND
+			u.state = 'completed'
+	)
+ORDER BY r.scheme, r.name, r.version
+`
@@ -403,6 +403,54 @@ func TestReferencesForUpload(t *testing.T) {
 	}
 }
 
+func TestUnresolvedReferencesForUpload(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertUploads(t, db,
+		Upload{ID: 1, Commit: makeCommit(2), Root: "sub1/"},
+		Upload{ID: 2, Commit: makeCommit(3), Root: "sub2/"},
+		Upload{ID: 3, Commit: makeCommit(4), Root: "sub3/", State: "errored"},
+		Upload{ID: 4, Commit: makeCommit(5), Root: "sub4/"},
+	)
+
+	insertPackages(t, store, []lsifstore.Package{
+		{DumpID: 1, Scheme: "gomod", Name: "leftpad", Version: "1.1.0"},
+		{DumpID: 3, Scheme: "gomod", Name: "leftpad", Version: "2.1.0"},
+		{DumpID: 4, Scheme: "gomod", Name: "rightpad", Version: "1.1.0"},
+	})
+
+	insertPackageReferences(t, store, []lsifstore.PackageReference{
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "1.1.0"}, Filter: []byte("f1")},
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "2.1.0"}, Filter: []byte("f2")},
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "3.1.0"}, Filter: []byte("f3")},
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "npm", Name: "rightpad", Version: "1.1.0"}, Filter: []byte("f4")},
+		{Package: lsifstore.Package{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "4.1.0"}, Filter: []byte("f5")},
+	})
+
+	scanner, err := store.UnresolvedReferencesForUpload(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error getting references: %s", err)
+	}
+
+	references, err := consumeScanner(scanner)
+	if err != nil {
+		t.Fatalf("unexpected error from scanner: %s", err)
+	}
+
+	expected := []lsifstore.PackageReference{
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "2.1.0"}, Filter: nil},
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "3.1.0"}, Filter: nil},
+		{Package: lsifstore.Package{DumpID: 2, Scheme: "npm", Name: "rightpad", Version: "1.1.0"}, Filter: nil},
+	}
+	if diff := cmp.Diff(expected, references); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+}
+
 // consumeScanner reads all values from the scanner into memory.
 func consumeScanner(scanner PackageReferenceScanner) (references []lsifstore.PackageReference, _ error) {
 	for {
@@ -0,0 +1,132 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// EnclosingDefinitions returns the range of the innermost definition enclosing each of the given ranges
+// of a document. The resulting map is keyed by the index of the given range. Ranges that do not occur
+// within a definition, and ranges of indexes that did not emit the full ranges of their definitions, are
+// absent from the map.
+func (s *Store) EnclosingDefinitions(ctx context.Context, bundleID int, path string, ranges []Range) (_ map[int]Range, err error) {
+	ctx, traceLog, endObservation := s.operations.enclosingDefinitions.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+		log.Int("numRanges", len(ranges)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.Store.Query(ctx, sqlf.Sprintf(enclosingDefinitionsDocumentQuery, bundleID, path)))
+	if err != nil || !exists {
+		return nil, err
+	}
+	traceLog(log.Int("numRanges", len(documentData.Document.Ranges)))
+
+	enclosingRanges := make(map[int]Range, len(ranges))
+	for i, rn := range ranges {
+		for _, r := range precise.FindRanges(documentData.Document.Ranges, rn.Start.Line, rn.Start.Character) {
+			if enclosing, ok := documentData.Document.Ranges[r.EnclosingRangeID]; ok {
+				enclosingRanges[i] = newRange(enclosing.StartLine, enclosing.StartCharacter, enclosing.EndLine, enclosing.EndCharacter)
+				break
+			}
+		}
+	}
+	traceLog(log.Int("numEnclosingRanges", len(enclosingRanges)))
+
+	return enclosingRanges, nil
+}
+
+const enclosingDefinitionsDocumentQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/call_hierarchy.go:EnclosingDefinitions
+SELECT
+	dump_id,
+	path,
+	data,
+	ranges,
+	NULL AS hovers,
+	NULL AS monikers,
+	NULL AS packages,
+	NULL AS diagnostics
+FROM
+	lsif_data_documents
+WHERE
+	dump_id = %s AND
+	path = %s
+LIMIT 1
+`
+
+// EnclosedRanges returns the ranges occurring within the full range of the innermost definition at the
+// given position, including the ranges of nested definitions.
+func (s *Store) EnclosedRanges(ctx context.Context, bundleID int, path string, line, character int) (_ []Range, err error) {
+	ctx, traceLog, endObservation := s.operations.enclosedRanges.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+		log.String("path", path),
+		log.Int("line", line),
+		log.Int("character", character),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	documentData, exists, err := s.scanFirstDocumentData(s.Store.Query(ctx, sqlf.Sprintf(enclosedRangesDocumentQuery, bundleID, path)))
+	if err != nil || !exists {
+		return nil, err
+	}
+	traceLog(log.Int("numRanges", len(documentData.Document.Ranges)))
+
+	// Index the ranges by their enclosing definition
+	enclosedRangeIDs := map[precise.ID][]precise.ID{}
+	for id, r := range documentData.Document.Ranges {
+		if r.EnclosingRangeID != "" {
+			enclosedRangeIDs[r.EnclosingRangeID] = append(enclosedRangeIDs[r.EnclosingRangeID], id)
+		}
+	}
+
+	// Find the innermost definition at the given position. Ranges in the document are not
+	// ordered, so we pick the definition with the tightest bounds.
+	var definitionID precise.ID
+	for id, r := range documentData.Document.Ranges {
+		if _, ok := enclosedRangeIDs[id]; !ok || precise.ComparePosition(r, line, character) != 0 {
+			continue
+		}
+		if definitionID == "" || precise.CompareRanges(r, documentData.Document.Ranges[definitionID]) > 0 {
+			definitionID = id
+		}
+	}
+	if definitionID == "" {
+		return nil, nil
+	}
+
+	var ranges []Range
+	for queue := enclosedRangeIDs[definitionID]; len(queue) > 0; queue = queue[1:] {
+		r := documentData.Document.Ranges[queue[0]]
+		ranges = append(ranges, newRange(r.StartLine, r.StartCharacter, r.EndLine, r.EndCharacter))
+		queue = append(queue, enclosedRangeIDs[queue[0]]...)
+	}
+	traceLog(log.Int("numEnclosedRanges", len(ranges)))
+
+	return ranges, nil
+}
+
+const enclosedRangesDocumentQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/call_hierarchy.go:EnclosedRanges
+SELECT
+	dump_id,
+	path,
+	data,
+	ranges,
+	NULL AS hovers,
+	NULL AS monikers,
+	NULL AS packages,
+	NULL AS diagnostics
+FROM
+	lsif_data_documents
+WHERE
+	dump_id = %s AND
+	path = %s
+LIMIT 1
+`
@@ -23,6 +23,9 @@ const CurrentDefinitionsSchemaVersion = 2
 // CurrentReferencesSchemaVersion is the schema version used for new lsif_data_references rows.
 const CurrentReferencesSchemaVersion = 2
 
+// CurrentImplementationsSchemaVersion is the schema version used for new lsif_data_implementations rows.
+const CurrentImplementationsSchemaVersion = 1
+
 // WriteMeta is called (transactionally) from the precise-code-intel-worker.
 func (s *Store) WriteMeta(ctx context.Context, bundleID int, meta precise.MetaData) (err error) {
 	ctx, endObservation := s.operations.writeMeta.With(ctx, &err, observation.Args{LogFields: []log.Field{
@@ -54,6 +57,10 @@ func (s *Store) WriteDocuments(ctx context.Context, bundleID int, documents chan
 	var count uint32
 	inserter := func(inserter *batch.Inserter) error {
 		for v := range documents {
+			if v.Err != nil {
+				return v.Err
+			}
+
 			data, err := s.serializer.MarshalDocumentData(v.Document)
 			if err != nil {
 				return err
@@ -212,6 +219,16 @@ func (s *Store) WriteReferences(ctx context.Context, bundleID int, monikerLocati
 	return s.writeDefinitionReferences(ctx, bundleID, "lsif_data_references", CurrentReferencesSchemaVersion, monikerLocations, traceLog)
 }
 
+// WriteImplementations is called (transactionally) from the precise-code-intel-worker.
+func (s *Store) WriteImplementations(ctx context.Context, bundleID int, monikerLocations chan precise.MonikerLocations) (err error) {
+	ctx, traceLog, endObservation := s.operations.writeImplementations.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.writeDefinitionReferences(ctx, bundleID, "lsif_data_implementations", CurrentImplementationsSchemaVersion, monikerLocations, traceLog)
+}
+
 func (s *Store) writeDefinitionReferences(ctx context.Context, bundleID int, tableName string, version int, monikerLocations chan precise.MonikerLocations, traceLog observation.TraceLogger) (err error) {
 	tx, err := s.Transact(ctx)
 	if err != nil {
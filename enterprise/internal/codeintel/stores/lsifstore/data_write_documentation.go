@@ -12,10 +12,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
-// WriteDocumentationPages is called (transactionally) from the precise-code-intel-worker.
-func (s *Store) WriteDocumentationPages(ctx context.Context, bundleID int, documentationPages chan *precise.DocumentationPageData) (err error) {
+// WriteDocumentationPages is called (transactionally) from the precise-code-intel-worker. The nodes
+// of each page that define a search key are also indexed for documentation search.
+func (s *Store) WriteDocumentationPages(ctx context.Context, bundleID int, meta DocumentationSearchMeta, documentationPages chan *precise.DocumentationPageData) (err error) {
 	ctx, traceLog, endObservation := s.operations.writeDocumentationPages.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.Int("bundleID", bundleID),
+		log.Int("repositoryID", meta.RepositoryID),
+		log.String("language", meta.Language),
 	}})
 	defer endObservation(1, observation.Args{})
 
@@ -31,6 +34,7 @@ func (s *Store) WriteDocumentationPages(ctx context.Context, bundleID int, docum
 	}
 
 	var count uint32
+	var searchRecords []documentationSearchRecord
 	inserter := func(inserter *batch.Inserter) error {
 		for v := range documentationPages {
 			data, err := s.serializer.MarshalDocumentationPageData(v)
@@ -42,6 +46,7 @@ func (s *Store) WriteDocumentationPages(ctx context.Context, bundleID int, docum
 				return err
 			}
 
+			searchRecords = append(searchRecords, documentationSearchRecords(v)...)
 			atomic.AddUint32(&count, 1)
 		}
 		return nil
@@ -57,11 +62,18 @@ func (s *Store) WriteDocumentationPages(ctx context.Context, bundleID int, docum
 	); err != nil {
 		return err
 	}
-	traceLog(log.Int("numResultChunkRecords", int(count)))
+	traceLog(
+		log.Int("numResultChunkRecords", int(count)),
+		log.Int("numSearchRecords", len(searchRecords)),
+	)
 
 	// Insert the values from the temporary table into the target table. We select a
 	// parameterized dump id here since it is the same for all rows in this operation.
-	return tx.Exec(ctx, sqlf.Sprintf(writeDocumentationPagesInsertQuery, bundleID))
+	if err := tx.Exec(ctx, sqlf.Sprintf(writeDocumentationPagesInsertQuery, bundleID)); err != nil {
+		return err
+	}
+
+	return tx.writeDocumentationSearch(ctx, bundleID, meta, searchRecords)
 }
 
 const writeDocumentationPagesTemporaryTableQuery = `
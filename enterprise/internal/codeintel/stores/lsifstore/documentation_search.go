@@ -0,0 +1,333 @@
+package lsifstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+// DocumentationSearchMeta describes the upload whose documentation is indexed for search.
+type DocumentationSearchMeta struct {
+	RepositoryID int
+	Language     string
+}
+
+// DocumentationSearchResult is a documentation node matching a documentation search query.
+type DocumentationSearchResult struct {
+	DumpID       int
+	RepositoryID int
+	Language     string
+	PathID       string
+	SearchKey    string
+	Label        string
+	Detail       string
+	Tags         []string
+}
+
+// DocumentationSearchOptions are the parameters of a documentation search.
+type DocumentationSearchOptions struct {
+	// Query is the user-supplied search text.
+	Query string
+
+	// RepositoryIDs restricts results to the given repositories. An empty slice does not restrict results.
+	RepositoryIDs []int
+
+	// Languages restricts results to the given languages. An empty slice does not restrict results.
+	Languages []string
+
+	// Tags restricts results to those with at least one of the given tags (e.g. symbol kinds). An empty
+	// slice does not restrict results.
+	Tags []string
+
+	// Limit is the maximum number of results to return.
+	Limit int
+}
+
+// indexerLanguages maps the name of an LSIF indexer to the language it indexes. Indexers not in this
+// map are assumed to be named `lsif-{language}`.
+var indexerLanguages = map[string]string{
+	"lsif-clang": "cpp",
+	"lsif-cpp":   "cpp",
+	"lsif-node":  "typescript",
+	"lsif-py":    "python",
+	"lsif-tsc":   "typescript",
+}
+
+// IndexerLanguage returns the language indexed by the LSIF indexer with the given name.
+func IndexerLanguage(indexer string) string {
+	if language, ok := indexerLanguages[indexer]; ok {
+		return language
+	}
+
+	return strings.ToLower(strings.TrimPrefix(indexer, "lsif-"))
+}
+
+// documentationSearchRecord is a row of the lsif_data_documentation_search table without the upload
+// metadata, which is the same for all rows written by a single operation.
+type documentationSearchRecord struct {
+	pathID    string
+	searchKey string
+	label     string
+	detail    string
+	tags      []string
+}
+
+// documentationSearchRecords returns a record for every node of the given page (excluding the nodes of
+// nested pages, which are indexed with their own page) that defines a search key.
+func documentationSearchRecords(page *precise.DocumentationPageData) []documentationSearchRecord {
+	var records []documentationSearchRecord
+
+	var walk func(node *precise.DocumentationNode)
+	walk = func(node *precise.DocumentationNode) {
+		if node == nil {
+			return
+		}
+
+		if node.Documentation.SearchKey != "" {
+			tags := make([]string, 0, len(node.Documentation.Tags))
+			for _, tag := range node.Documentation.Tags {
+				tags = append(tags, string(tag))
+			}
+
+			records = append(records, documentationSearchRecord{
+				pathID:    node.PathID,
+				searchKey: node.Documentation.SearchKey,
+				label:     node.Label.Value,
+				detail:    node.Detail.Value,
+				tags:      tags,
+			})
+		}
+
+		for _, child := range node.Children {
+			walk(child.Node)
+		}
+	}
+	walk(page.Tree)
+
+	return records
+}
+
+// writeDocumentationSearch indexes the given records for search. This method should only be called from
+// within a transaction that has also written the documentation pages of the given bundle.
+func (s *Store) writeDocumentationSearch(ctx context.Context, bundleID int, meta DocumentationSearchMeta, records []documentationSearchRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// Create temporary table symmetric to lsif_data_documentation_search without the upload metadata
+	if err := s.Exec(ctx, sqlf.Sprintf(writeDocumentationSearchTemporaryTableQuery)); err != nil {
+		return err
+	}
+
+	inserter := func(inserter *batch.Inserter) error {
+		for _, record := range records {
+			if err := inserter.Insert(
+				ctx,
+				record.pathID,
+				record.searchKey,
+				record.label,
+				record.detail,
+				pq.Array(record.tags),
+				searchTerms(record.searchKey),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Bulk insert all the unique column values into the temporary table
+	if err := withBatchInserter(
+		ctx,
+		s.Handle().DB(),
+		"t_lsif_data_documentation_search",
+		[]string{"path_id", "search_key", "label", "detail", "tags", "search_terms"},
+		inserter,
+	); err != nil {
+		return err
+	}
+
+	// Insert the values from the temporary table into the target table. We select parameterized
+	// upload metadata here since it is the same for all rows in this operation.
+	return s.Exec(ctx, sqlf.Sprintf(writeDocumentationSearchInsertQuery, bundleID, meta.RepositoryID, meta.Language))
+}
+
+const writeDocumentationSearchTemporaryTableQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/documentation_search.go:writeDocumentationSearch
+CREATE TEMPORARY TABLE t_lsif_data_documentation_search (
+	path_id TEXT NOT NULL,
+	search_key TEXT NOT NULL,
+	label TEXT NOT NULL,
+	detail TEXT NOT NULL,
+	tags TEXT[] NOT NULL,
+	search_terms TEXT NOT NULL
+) ON COMMIT DROP
+`
+
+const writeDocumentationSearchInsertQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/documentation_search.go:writeDocumentationSearch
+INSERT INTO lsif_data_documentation_search (dump_id, repo_id, lang, path_id, search_key, label, detail, tags, tsv)
+SELECT
+	%s,
+	%s,
+	%s,
+	source.path_id,
+	source.search_key,
+	source.label,
+	source.detail,
+	source.tags,
+	setweight(to_tsvector('simple', source.search_terms), 'A') ||
+	setweight(to_tsvector('simple', source.label), 'B') ||
+	setweight(to_tsvector('simple', source.detail), 'C')
+FROM t_lsif_data_documentation_search source
+`
+
+// DocumentationSearch returns the documentation nodes matching the given query. A node matches if its
+// search key, label, or detail contain all terms of the query (full-text), or if the query is similar to
+// a word within its search key (fuzzy). Results are ordered by relevance, weighting matches in the search
+// key over matches in the label over matches in the detail.
+func (s *Store) DocumentationSearch(ctx context.Context, opts DocumentationSearchOptions) (_ []DocumentationSearchResult, err error) {
+	ctx, traceLog, endObservation := s.operations.documentationSearch.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("query", opts.Query),
+		log.Int("numRepositoryIDs", len(opts.RepositoryIDs)),
+		log.String("languages", strings.Join(opts.Languages, ", ")),
+		log.String("tags", strings.Join(opts.Tags, ", ")),
+		log.Int("limit", opts.Limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	terms := searchTerms(opts.Query)
+	if terms == "" {
+		return nil, nil
+	}
+
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("(tsv @@ plainto_tsquery('simple', %s) OR %s <%% search_key)", terms, opts.Query),
+	}
+	if len(opts.RepositoryIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("repo_id = ANY(%s)", pq.Array(opts.RepositoryIDs)))
+	}
+	if len(opts.Languages) > 0 {
+		languages := make([]string, 0, len(opts.Languages))
+		for _, language := range opts.Languages {
+			languages = append(languages, strings.ToLower(language))
+		}
+
+		conds = append(conds, sqlf.Sprintf("lang = ANY(%s)", pq.Array(languages)))
+	}
+	if len(opts.Tags) > 0 {
+		conds = append(conds, sqlf.Sprintf("tags && %s", pq.Array(opts.Tags)))
+	}
+
+	results, err := scanDocumentationSearchResults(s.Store.Query(ctx, sqlf.Sprintf(
+		documentationSearchQuery,
+		terms,
+		opts.Query,
+		sqlf.Join(conds, " AND "),
+		opts.Limit,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numResults", len(results)))
+
+	return results, nil
+}
+
+const documentationSearchQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/documentation_search.go:DocumentationSearch
+SELECT dump_id, repo_id, lang, path_id, search_key, label, detail, tags
+FROM lsif_data_documentation_search
+CROSS JOIN LATERAL (
+	SELECT ts_rank_cd(tsv, plainto_tsquery('simple', %s)) + word_similarity(%s, search_key) AS rank
+) r
+WHERE %s
+ORDER BY r.rank DESC, id
+LIMIT %s
+`
+
+// scanDocumentationSearchResults scans a slice of documentation search results from the return value of `*Store.query`.
+func scanDocumentationSearchResults(rows *sql.Rows, queryErr error) (_ []DocumentationSearchResult, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var results []DocumentationSearchResult
+	for rows.Next() {
+		var result DocumentationSearchResult
+		if err := rows.Scan(
+			&result.DumpID,
+			&result.RepositoryID,
+			&result.Language,
+			&result.PathID,
+			&result.SearchKey,
+			&result.Label,
+			&result.Detail,
+			pq.Array(&result.Tags),
+		); err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// searchTerms splits the given text into the words that are indexed for full-text search. Text is split
+// on punctuation and at lower-to-upper case transitions so that a search key such as `mux.Router.ServeHTTP`
+// is matched by each of the queries `router`, `serve http`, and `mux router`. The original identifiers
+// are retained alongside their constituent words so that exact identifier matches also rank highly.
+func searchTerms(text string) string {
+	var terms []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return !isIdentifierRune(r) }) {
+		terms = append(terms, field)
+
+		if words := splitCamelCase(field); len(words) > 1 {
+			terms = append(terms, words...)
+		}
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// splitCamelCase splits the given identifier into words at underscores and at lower-to-upper case
+// transitions (e.g. `ServeHTTP` becomes `Serve` and `HTTP`).
+func splitCamelCase(identifier string) []string {
+	var words []string
+	var current []rune
+	var prev rune
+
+	for _, r := range identifier {
+		if r == '_' || (unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+			}
+			current = current[:0]
+		}
+		if r != '_' {
+			current = append(current, r)
+		}
+		prev = r
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+
+	return words
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,146 @@
+package lsifstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestDocumentationSearch(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db, &observation.TestContext)
+
+	newNode := func(pathID, searchKey, label, detail string, tags []protocol.Tag, children ...precise.DocumentationNodeChild) *precise.DocumentationNode {
+		return &precise.DocumentationNode{
+			PathID: pathID,
+			Documentation: protocol.Documentation{
+				Identifier: pathID,
+				SearchKey:  searchKey,
+				Tags:       tags,
+			},
+			Label:    protocol.MarkupContent{Kind: protocol.PlainText, Value: label},
+			Detail:   protocol.MarkupContent{Kind: protocol.PlainText, Value: detail},
+			Children: children,
+		}
+	}
+
+	pages := []*precise.DocumentationPageData{
+		{Tree: newNode("/mux", "mux", "package mux", "Package mux implements a request router.", []protocol.Tag{protocol.TagPackage},
+			precise.DocumentationNodeChild{Node: newNode("/mux#Router", "mux.Router", "type Router struct", "Router registers routes to be matched and dispatches a handler.", []protocol.Tag{protocol.TagStruct})},
+			precise.DocumentationNodeChild{Node: newNode("/mux#Router.ServeHTTP", "mux.Router.ServeHTTP", "func (r *Router) ServeHTTP(w, req)", "ServeHTTP dispatches the handler registered in the matched route.", []protocol.Tag{protocol.TagMethod})},
+			precise.DocumentationNodeChild{PathID: "/mux/internal"},
+		)},
+		{Tree: newNode("/mux/internal", "", "package internal", "", nil,
+			precise.DocumentationNodeChild{Node: newNode("/mux/internal#parseTemplate", "internal.parseTemplate", "func parseTemplate(tpl string)", "parseTemplate compiles a route template.", []protocol.Tag{protocol.TagFunction, protocol.TagPrivate})},
+		)},
+	}
+
+	writePages := func(bundleID int, meta DocumentationSearchMeta) {
+		ch := make(chan *precise.DocumentationPageData, len(pages))
+		for _, page := range pages {
+			ch <- page
+		}
+		close(ch)
+
+		if err := store.WriteDocumentationPages(context.Background(), bundleID, meta, ch); err != nil {
+			t.Fatalf("unexpected error writing documentation pages: %s", err)
+		}
+	}
+	writePages(1, DocumentationSearchMeta{RepositoryID: 50, Language: "go"})
+	writePages(2, DocumentationSearchMeta{RepositoryID: 51, Language: "typescript"})
+
+	pathIDs := func(results []DocumentationSearchResult) []string {
+		var pathIDs []string
+		for _, result := range results {
+			pathIDs = append(pathIDs, result.PathID)
+		}
+		return pathIDs
+	}
+
+	testCases := []struct {
+		name     string
+		opts     DocumentationSearchOptions
+		expected []string
+	}{
+		{name: "search key", opts: DocumentationSearchOptions{Query: "ServeHTTP", RepositoryIDs: []int{50}, Limit: 10}, expected: []string{"/mux#Router.ServeHTTP"}},
+		{name: "split identifier", opts: DocumentationSearchOptions{Query: "serve http", RepositoryIDs: []int{50}, Limit: 10}, expected: []string{"/mux#Router.ServeHTTP"}},
+		{name: "detail", opts: DocumentationSearchOptions{Query: "compiles template", RepositoryIDs: []int{50}, Limit: 10}, expected: []string{"/mux/internal#parseTemplate"}},
+		{name: "fuzzy", opts: DocumentationSearchOptions{Query: "parseTemplat", RepositoryIDs: []int{50}, Limit: 10}, expected: []string{"/mux/internal#parseTemplate"}},
+		{name: "language", opts: DocumentationSearchOptions{Query: "ServeHTTP", Languages: []string{"TypeScript"}, Limit: 10}, expected: []string{"/mux#Router.ServeHTTP"}},
+		{name: "tags", opts: DocumentationSearchOptions{Query: "router", RepositoryIDs: []int{50}, Tags: []string{"struct"}, Limit: 10}, expected: []string{"/mux#Router"}},
+		{name: "limit", opts: DocumentationSearchOptions{Query: "ServeHTTP", Limit: 1}, expected: []string{"/mux#Router.ServeHTTP"}},
+		{name: "no terms", opts: DocumentationSearchOptions{Query: "...", Limit: 10}, expected: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			results, err := store.DocumentationSearch(context.Background(), testCase.opts)
+			if err != nil {
+				t.Fatalf("unexpected error searching documentation: %s", err)
+			}
+			if diff := cmp.Diff(testCase.expected, pathIDs(results)); diff != "" {
+				t.Errorf("unexpected results (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	results, err := store.DocumentationSearch(context.Background(), DocumentationSearchOptions{Query: "router", RepositoryIDs: []int{51}, Tags: []string{"struct"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error searching documentation: %s", err)
+	}
+	expected := []DocumentationSearchResult{
+		{
+			DumpID:       2,
+			RepositoryID: 51,
+			Language:     "typescript",
+			PathID:       "/mux#Router",
+			SearchKey:    "mux.Router",
+			Label:        "type Router struct",
+			Detail:       "Router registers routes to be matched and dispatches a handler.",
+			Tags:         []string{"struct"},
+		},
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	testCases := map[string]string{
+		"mux.Router.ServeHTTP":    "mux Router ServeHTTP Serve HTTP",
+		"parse_template":          "parse_template parse template",
+		"io.ReadAll(r io.Reader)": "io ReadAll Read All r io Reader",
+		"...":                     "",
+	}
+
+	for text, expected := range testCases {
+		if terms := searchTerms(text); terms != expected {
+			t.Errorf("unexpected search terms for %q. want=%q have=%q", text, expected, terms)
+		}
+	}
+}
+
+func TestIndexerLanguage(t *testing.T) {
+	testCases := map[string]string{
+		"lsif-go":       "go",
+		"lsif-tsc":      "typescript",
+		"lsif-clang":    "cpp",
+		"lsif-Java":     "java",
+		"rust-analyzer": "rust-analyzer",
+	}
+
+	for indexer, expected := range testCases {
+		if language := IndexerLanguage(indexer); language != expected {
+			t.Errorf("unexpected language for %q. want=%q have=%q", indexer, expected, language)
+		}
+	}
+}
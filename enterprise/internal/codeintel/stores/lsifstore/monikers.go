@@ -161,3 +161,38 @@ func monikersToString(vs []precise.MonikerData) string {
 
 	return strings.Join(strs, ", ")
 }
+
+// DefinitionPaths returns the set of paths containing a definition of each moniker defined by the
+// given bundle, keyed by moniker identifier.
+func (s *Store) DefinitionPaths(ctx context.Context, bundleID int) (_ map[string][]string, err error) {
+	ctx, traceLog, endObservation := s.operations.definitionPaths.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("bundleID", bundleID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	locationData, err := s.scanQualifiedMonikerLocations(s.Store.Query(ctx, sqlf.Sprintf(definitionPathsQuery, bundleID)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numMonikers", len(locationData)))
+
+	paths := make(map[string][]string, len(locationData))
+	for _, monikerLocations := range locationData {
+		seen := map[string]struct{}{}
+		for _, location := range monikerLocations.Locations {
+			if _, ok := seen[location.URI]; ok {
+				continue
+			}
+
+			seen[location.URI] = struct{}{}
+			paths[monikerLocations.Identifier] = append(paths[monikerLocations.Identifier], location.URI)
+		}
+	}
+
+	return paths, nil
+}
+
+const definitionPathsQuery = `
+-- source: enterprise/internal/codeintel/stores/lsifstore/monikers.go:DefinitionPaths
+SELECT dump_id, scheme, identifier, data FROM lsif_data_definitions WHERE dump_id = %s ORDER BY (scheme, identifier)
+`
@@ -124,3 +124,26 @@ func TestDatabaseBulkMonikerResults(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabaseDefinitionPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	populateTestStore(t)
+	store := NewStore(db, &observation.TestContext)
+
+	paths, err := store.DefinitionPaths(context.Background(), testBundleID)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	for identifier, expected := range map[string][]string{
+		"github.com/sourcegraph/lsif-go/protocol:Edge":        {"protocol/protocol.go"},
+		"github.com/sourcegraph/lsif-go/protocol:NewMetaData": {"protocol/protocol.go"},
+	} {
+		if diff := cmp.Diff(expected, paths[identifier]); diff != "" {
+			t.Errorf("unexpected paths for %s (-want +got):\n%s", identifier, diff)
+		}
+	}
+}
@@ -10,15 +10,20 @@ import (
 type operations struct {
 	bulkMonikerResults            *observation.Operation
 	clear                         *observation.Operation
+	definitionPaths               *observation.Operation
 	definitions                   *observation.Operation
 	diagnostics                   *observation.Operation
+	enclosedRanges                *observation.Operation
+	enclosingDefinitions          *observation.Operation
 	exists                        *observation.Operation
 	hover                         *observation.Operation
+	implementations               *observation.Operation
 	monikerResults                *observation.Operation
 	monikersByPosition            *observation.Operation
 	packageInformation            *observation.Operation
 	ranges                        *observation.Operation
 	references                    *observation.Operation
+	typeDefinitions               *observation.Operation
 	documentationPage             *observation.Operation
 	documentationPathInfo         *observation.Operation
 	documentationIDsToPathIDs     *observation.Operation
@@ -27,8 +32,10 @@ type operations struct {
 	documentationDefinitions      *observation.Operation
 	documentationReferences       *observation.Operation
 	documentationAtPosition       *observation.Operation
+	documentationSearch           *observation.Operation
 	writeDefinitions              *observation.Operation
 	writeDocuments                *observation.Operation
+	writeImplementations          *observation.Operation
 	writeMeta                     *observation.Operation
 	writeReferences               *observation.Operation
 	writeResultChunks             *observation.Operation
@@ -68,15 +75,20 @@ func newOperations(observationContext *observation.Context) *operations {
 	return &operations{
 		bulkMonikerResults:            op("BulkMonikerResults"),
 		clear:                         op("Clear"),
+		definitionPaths:               op("DefinitionPaths"),
 		definitions:                   op("Definitions"),
 		diagnostics:                   op("Diagnostics"),
+		enclosedRanges:                op("EnclosedRanges"),
+		enclosingDefinitions:          op("EnclosingDefinitions"),
 		exists:                        op("Exists"),
 		hover:                         op("Hover"),
+		implementations:               op("Implementations"),
 		monikerResults:                op("MonikerResults"),
 		monikersByPosition:            op("MonikersByPosition"),
 		packageInformation:            op("PackageInformation"),
 		ranges:                        op("Ranges"),
 		references:                    op("References"),
+		typeDefinitions:               op("TypeDefinitions"),
 		documentationPage:             op("DocumentationPage"),
 		documentationPathInfo:         op("DocumentationPathInfo"),
 		documentationIDsToPathIDs:     op("DocumentationIDsToPathIDs"),
@@ -85,8 +97,10 @@ func newOperations(observationContext *observation.Context) *operations {
 		documentationDefinitions:      op("DocumentationDefinitions"),
 		documentationReferences:       op("DocumentationReferences"),
 		documentationAtPosition:       op("DocumentationAtPosition"),
+		documentationSearch:           op("DocumentationSearch"),
 		writeDefinitions:              op("WriteDefinitions"),
 		writeDocuments:                op("WriteDocuments"),
+		writeImplementations:          op("WriteImplementations"),
 		writeMeta:                     op("WriteMeta"),
 		writeReferences:               op("WriteReferences"),
 		writeResultChunks:             op("WriteResultChunks"),
@@ -48,3 +48,23 @@ func TestIntegration_PermsStore(t *testing.T) {
 		t.Run(tc.name, tc.test)
 	}
 }
+
+func TestIntegration_SubRepoPermsStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+
+	db := dbtest.NewDB(t, *dsn)
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T)
+	}{
+		{"Upsert", testSubRepoPermsStore_Upsert(db)},
+		{"SetForUserAndService", testSubRepoPermsStore_SetForUserAndService(db)},
+	} {
+		t.Run(tc.name, tc.test)
+	}
+}
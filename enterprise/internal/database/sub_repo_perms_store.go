@@ -0,0 +1,173 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// SubRepoPermsStore is the unified interface for managing sub repository
+// permissions explicitly in the database. It is concurrency-safe and maintains
+// data consistency over the 'sub_repo_permissions' table.
+type SubRepoPermsStore struct {
+	*basestore.Store
+}
+
+// SubRepoPerms returns a new SubRepoPermsStore with the given parameters.
+func SubRepoPerms(db dbutil.DB) *SubRepoPermsStore {
+	return &SubRepoPermsStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// SubRepoPermsWith instantiates and returns a new SubRepoPermsStore using the
+// other store handle.
+func SubRepoPermsWith(other basestore.ShareableStore) *SubRepoPermsStore {
+	return &SubRepoPermsStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+// With is a helper method that returns a new SubRepoPermsStore over the
+// transaction of other.
+func (s *SubRepoPermsStore) With(other basestore.ShareableStore) *SubRepoPermsStore {
+	return &SubRepoPermsStore{Store: s.Store.With(other)}
+}
+
+// Transact begins a new transaction and make a new SubRepoPermsStore over it.
+func (s *SubRepoPermsStore) Transact(ctx context.Context) (*SubRepoPermsStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &SubRepoPermsStore{Store: txBase}, err
+}
+
+func (s *SubRepoPermsStore) Done(err error) error {
+	return s.Store.Done(err)
+}
+
+// Upsert will upsert sub repo permissions data.
+func (s *SubRepoPermsStore) Upsert(ctx context.Context, userID int32, repoID api.RepoID, perms authz.SubRepoPermissions) error {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.Upsert
+INSERT INTO sub_repo_permissions (user_id, repo_id, path_includes, path_excludes, updated_at)
+VALUES (%s, %s, %s, %s, NOW())
+ON CONFLICT (repo_id, user_id)
+DO UPDATE
+SET
+  path_includes = EXCLUDED.path_includes,
+  path_excludes = EXCLUDED.path_excludes,
+  updated_at = NOW()
+`, userID, repoID, pq.Array(perms.PathIncludes), pq.Array(perms.PathExcludes))
+	return errors.Wrap(s.Exec(ctx, q), "upserting sub repo permissions")
+}
+
+// UpsertWithSpec will upsert sub repo permissions data using the provided
+// external repo spec to map to our internal repo id. If there is no mapping,
+// nothing is written.
+func (s *SubRepoPermsStore) UpsertWithSpec(ctx context.Context, userID int32, spec api.ExternalRepoSpec, perms authz.SubRepoPermissions) error {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.UpsertWithSpec
+INSERT INTO sub_repo_permissions (user_id, repo_id, path_includes, path_excludes, updated_at)
+SELECT %s, id, %s, %s, NOW()
+FROM repo
+WHERE external_service_id = %s
+  AND external_service_type = %s
+  AND external_id = %s
+  AND deleted_at IS NULL
+ON CONFLICT (repo_id, user_id)
+DO UPDATE
+SET
+  path_includes = EXCLUDED.path_includes,
+  path_excludes = EXCLUDED.path_excludes,
+  updated_at = NOW()
+`, userID, pq.Array(perms.PathIncludes), pq.Array(perms.PathExcludes), spec.ServiceID, spec.ServiceType, spec.ID)
+	return errors.Wrap(s.Exec(ctx, q), "upserting sub repo permissions with spec")
+}
+
+// SetForUserAndService replaces the sub repo permissions of the user for the
+// repositories of the code host identified by serviceType and serviceID with
+// the given permissions, keyed by external repo ID.
+func (s *SubRepoPermsStore) SetForUserAndService(ctx context.Context, userID int32, serviceType, serviceID string, perms map[string]authz.SubRepoPermissions) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.SetForUserAndService
+DELETE FROM sub_repo_permissions
+WHERE user_id = %s
+  AND repo_id IN (
+    SELECT id FROM repo
+    WHERE external_service_type = %s
+      AND external_service_id = %s
+  )
+`, userID, serviceType, serviceID)
+	if err = tx.Exec(ctx, q); err != nil {
+		return errors.Wrap(err, "deleting sub repo permissions")
+	}
+
+	for id, p := range perms {
+		spec := api.ExternalRepoSpec{ID: id, ServiceType: serviceType, ServiceID: serviceID}
+		if err = tx.UpsertWithSpec(ctx, userID, spec, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get will fetch sub repo rules for the given repo and user combination. It
+// returns nil if there are no rules.
+func (s *SubRepoPermsStore) Get(ctx context.Context, userID int32, repoID api.RepoID) (*authz.SubRepoPermissions, error) {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.Get
+SELECT path_includes, path_excludes
+FROM sub_repo_permissions
+WHERE user_id = %s
+  AND repo_id = %s
+`, userID, repoID)
+
+	perms := new(authz.SubRepoPermissions)
+	err := s.QueryRow(ctx, q).Scan(pq.Array(&perms.PathIncludes), pq.Array(&perms.PathExcludes))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting sub repo permissions")
+	}
+	return perms, nil
+}
+
+// GetByUser fetches all sub repo perms for a user keyed by repo name. It
+// implements authz.SubRepoPermissionsGetter.
+func (s *SubRepoPermsStore) GetByUser(ctx context.Context, userID int32) (_ map[api.RepoName]authz.SubRepoPermissions, err error) {
+	q := sqlf.Sprintf(`
+-- source: enterprise/internal/database/sub_repo_perms_store.go:SubRepoPermsStore.GetByUser
+SELECT r.name, p.path_includes, p.path_excludes
+FROM sub_repo_permissions p
+JOIN repo r ON r.id = p.repo_id
+WHERE p.user_id = %s
+  AND r.deleted_at IS NULL
+`, userID)
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting sub repo permissions by user")
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	result := make(map[api.RepoName]authz.SubRepoPermissions)
+	for rows.Next() {
+		var repoName api.RepoName
+		var perms authz.SubRepoPermissions
+		if err := rows.Scan(&repoName, pq.Array(&perms.PathIncludes), pq.Array(&perms.PathExcludes)); err != nil {
+			return nil, errors.Wrap(err, "scanning row")
+		}
+		result[repoName] = perms
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func cleanupSubRepoPermsTables(t *testing.T, s *PermsStore) {
+	if t.Failed() {
+		return
+	}
+
+	q := `TRUNCATE TABLE sub_repo_permissions, repo, users RESTART IDENTITY CASCADE;`
+	if err := s.execute(context.Background(), sqlf.Sprintf(q)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func prepareSubRepoTestData(ctx context.Context, t *testing.T, s *PermsStore) {
+	t.Helper()
+
+	qs := []*sqlf.Query{
+		sqlf.Sprintf(`INSERT INTO users(username) VALUES('alice')`), // ID=1
+		sqlf.Sprintf(`INSERT INTO users(username) VALUES('bob')`),   // ID=2
+		sqlf.Sprintf(`INSERT INTO repo(name, private, external_id, external_service_type, external_service_id) VALUES('perforce/depot/main', TRUE, '//depot/main/', %s, 'ssl:111.222.333.444:1666')`, extsvc.TypePerforce),         // ID=1
+		sqlf.Sprintf(`INSERT INTO repo(name, private, external_id, external_service_type, external_service_id) VALUES('perforce/depot/training', TRUE, '//depot/training/', %s, 'ssl:111.222.333.444:1666')`, extsvc.TypePerforce), // ID=2
+		sqlf.Sprintf(`INSERT INTO repo(name) VALUES('github.com/foo/bar')`), // ID=3
+	}
+	for _, q := range qs {
+		if err := s.execute(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testSubRepoPermsStore_Upsert(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+		ps := Perms(db, time.Now)
+		t.Cleanup(func() { cleanupSubRepoPermsTables(t, ps) })
+		prepareSubRepoTestData(ctx, t, ps)
+
+		s := SubRepoPerms(db)
+		perms := authz.SubRepoPermissions{
+			PathIncludes: []string{"/src/foo/*"},
+			PathExcludes: []string{"/src/bar/*"},
+		}
+		if err := s.Upsert(ctx, 1, 3, perms); err != nil {
+			t.Fatal(err)
+		}
+		have, err := s.Get(ctx, 1, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&perms, have); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// Upserting again replaces the rules.
+		perms = authz.SubRepoPermissions{PathIncludes: []string{"/**"}}
+		if err := s.Upsert(ctx, 1, 3, perms); err != nil {
+			t.Fatal(err)
+		}
+		have, err = s.Get(ctx, 1, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&perms, have); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// No rules is nil.
+		have, err = s.Get(ctx, 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != nil {
+			t.Fatalf("want nil but got %v", have)
+		}
+	}
+}
+
+func testSubRepoPermsStore_SetForUserAndService(db *sql.DB) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+		ps := Perms(db, time.Now)
+		t.Cleanup(func() { cleanupSubRepoPermsTables(t, ps) })
+		prepareSubRepoTestData(ctx, t, ps)
+
+		s := SubRepoPerms(db)
+		if err := s.Upsert(ctx, 1, 3, authz.SubRepoPermissions{PathIncludes: []string{"/docs/**"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		set := func(perms map[string]authz.SubRepoPermissions) {
+			t.Helper()
+			if err := s.SetForUserAndService(ctx, 1, extsvc.TypePerforce, "ssl:111.222.333.444:1666", perms); err != nil {
+				t.Fatal(err)
+			}
+		}
+		set(map[string]authz.SubRepoPermissions{
+			"//depot/main/":     {PathIncludes: []string{"/**"}, PathExcludes: []string{"/secret/**"}},
+			"//depot/training/": {PathIncludes: []string{"/public/**"}},
+			"//depot/unknown/":  {PathIncludes: []string{"/**"}},
+		})
+		// Rules of repositories which are no longer returned are removed, and
+		// those of other code hosts are kept.
+		set(map[string]authz.SubRepoPermissions{
+			"//depot/main/": {PathIncludes: []string{"/**"}, PathExcludes: []string{"/secret/**"}},
+		})
+
+		have, err := s.GetByUser(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[api.RepoName]authz.SubRepoPermissions{
+			"perforce/depot/main": {PathIncludes: []string{"/**"}, PathExcludes: []string{"/secret/**"}},
+			"github.com/foo/bar":  {PathIncludes: []string{"/docs/**"}},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatal(diff)
+		}
+
+		have, err = s.GetByUser(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Fatalf("want no rules but got %v", have)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package ber implements the subset of the Basic Encoding Rules (BER) of ASN.1 which is used by
+// the LDAP protocol (https://datatracker.ietf.org/doc/html/rfc4511#section-5.1).
+package ber
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Class is the class of the tag of an element.
+type Class byte
+
+const (
+	ClassUniversal   Class = 0x00
+	ClassApplication Class = 0x40
+	ClassContext     Class = 0x80
+)
+
+// Tags of the universal class used by LDAP.
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+// maxLength is the maximum length of the contents of an element that is read, so that a
+// misbehaving peer can't make us allocate arbitrary amounts of memory.
+const maxLength = 64 << 20
+
+// Packet is a BER element. The contents of constructed elements are their Children, and the
+// contents of primitive elements are their Value.
+type Packet struct {
+	Class       Class
+	Constructed bool
+	Tag         int
+	Value       []byte
+	Children    []*Packet
+}
+
+// NewConstructed returns a constructed element.
+func NewConstructed(class Class, tag int, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// NewSequence returns a universal SEQUENCE element.
+func NewSequence(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSequence, children...)
+}
+
+// NewSet returns a universal SET element.
+func NewSet(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSet, children...)
+}
+
+// NewPrimitive returns a primitive element.
+func NewPrimitive(class Class, tag int, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+// NewString returns a universal OCTET STRING element.
+func NewString(s string) *Packet {
+	return NewPrimitive(ClassUniversal, TagOctetString, []byte(s))
+}
+
+// NewInteger returns a universal INTEGER element.
+func NewInteger(v int64) *Packet {
+	return NewPrimitive(ClassUniversal, TagInteger, encodeInteger(v))
+}
+
+// NewEnumerated returns a universal ENUMERATED element.
+func NewEnumerated(v int64) *Packet {
+	return NewPrimitive(ClassUniversal, TagEnumerated, encodeInteger(v))
+}
+
+// NewBoolean returns a universal BOOLEAN element.
+func NewBoolean(v bool) *Packet {
+	if v {
+		return NewPrimitive(ClassUniversal, TagBoolean, []byte{0xff})
+	}
+	return NewPrimitive(ClassUniversal, TagBoolean, []byte{0x00})
+}
+
+// Is returns whether the element has the given class and tag.
+func (p *Packet) Is(class Class, tag int) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// String returns the value of a primitive element as a string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Int returns the value of an INTEGER or ENUMERATED element.
+func (p *Packet) Int() (int64, error) {
+	if p.Constructed || len(p.Value) == 0 || len(p.Value) > 8 {
+		return 0, errors.Errorf("invalid integer of %d bytes", len(p.Value))
+	}
+	v := int64(int8(p.Value[0]))
+	for _, b := range p.Value[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, nil
+}
+
+// Bool returns the value of a BOOLEAN element.
+func (p *Packet) Bool() (bool, error) {
+	if p.Constructed || len(p.Value) != 1 {
+		return false, errors.Errorf("invalid boolean of %d bytes", len(p.Value))
+	}
+	return p.Value[0] != 0, nil
+}
+
+// Child returns the i-th child of a constructed element, or an error if it has fewer children.
+func (p *Packet) Child(i int) (*Packet, error) {
+	if i >= len(p.Children) {
+		return nil, errors.Errorf("element with tag %d has %d children, expected at least %d", p.Tag, len(p.Children), i+1)
+	}
+	return p.Children[i], nil
+}
+
+// Bytes returns the encoding of the element.
+func (p *Packet) Bytes() []byte {
+	var buf bytes.Buffer
+	p.encode(&buf)
+	return buf.Bytes()
+}
+
+func (p *Packet) encode(buf *bytes.Buffer) {
+	identifier := byte(p.Class) | byte(p.Tag)
+	if p.Constructed {
+		identifier |= 0x20
+	}
+	buf.WriteByte(identifier)
+
+	contents := p.Value
+	if p.Constructed {
+		var children bytes.Buffer
+		for _, c := range p.Children {
+			c.encode(&children)
+		}
+		contents = children.Bytes()
+	}
+	encodeLength(buf, len(contents))
+	buf.Write(contents)
+}
+
+func encodeLength(buf *bytes.Buffer, n int) {
+	if n < 0x80 {
+		buf.WriteByte(byte(n))
+		return
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	buf.WriteByte(0x80 | byte(len(b)))
+	buf.Write(b)
+}
+
+// encodeInteger returns the minimal two's complement encoding of v.
+func encodeInteger(v int64) []byte {
+	b := []byte{byte(v)}
+	for v > 0x7f || v < -0x80 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+	return b
+}
+
+// ReadPacket reads an element from r.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	p := &Packet{
+		Class:       Class(header[0] & 0xc0),
+		Constructed: header[0]&0x20 != 0,
+		Tag:         int(header[0] & 0x1f),
+	}
+	if p.Tag == 0x1f {
+		return nil, errors.New("high tag numbers are not supported")
+	}
+
+	length := int(header[1])
+	if length == 0x80 {
+		return nil, errors.New("indefinite lengths are not supported")
+	}
+	if length > 0x80 {
+		n := length & 0x7f
+		if n > 4 {
+			return nil, errors.Errorf("length of %d bytes is too long", n)
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		length = 0
+		for _, c := range b {
+			length = length<<8 | int(c)
+		}
+	}
+	if length > maxLength {
+		return nil, errors.Errorf("length of %d bytes exceeds the maximum of %d bytes", length, maxLength)
+	}
+
+	contents := make([]byte, length)
+	if _, err := io.ReadFull(r, contents); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if !p.Constructed {
+		p.Value = contents
+		return p, nil
+	}
+
+	cr := bytes.NewReader(contents)
+	for cr.Len() > 0 {
+		c, err := ReadPacket(cr)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		p.Children = append(p.Children, c)
+	}
+	return p, nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, as the end of input is only expected
+// before the first byte of an element.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
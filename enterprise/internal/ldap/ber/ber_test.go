@@ -0,0 +1,69 @@
+package ber
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPacket_RoundTrip(t *testing.T) {
+	p := NewSequence(
+		NewInteger(1),
+		NewConstructed(ClassApplication, 3,
+			NewString("dc=example,dc=com"),
+			NewEnumerated(2),
+			NewBoolean(true),
+			NewPrimitive(ClassContext, 7, []byte("objectClass")),
+			NewString(strings.Repeat("x", 300)),
+		),
+	)
+
+	got, err := ReadPacket(bytes.NewReader(p.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(p, got, cmp.Comparer(func(a, b []byte) bool { return bytes.Equal(a, b) })); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPacket_Int(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1 << 40, -(1 << 40)} {
+		got, err := NewInteger(v).Int()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v {
+			t.Errorf("want %d but got %d", v, got)
+		}
+	}
+
+	if got := NewInteger(128).Value; !bytes.Equal(got, []byte{0x00, 0x80}) {
+		t.Errorf("want minimal encoding of 128 but got %x", got)
+	}
+}
+
+func TestReadPacket_Errors(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":              {},
+		"truncated header":   {0x30},
+		"truncated contents": {0x04, 0x05, 'a'},
+		"truncated child":    {0x30, 0x03, 0x04, 0x05, 'a'},
+		"indefinite length":  {0x30, 0x80},
+		"high tag number":    {0x1f, 0x01},
+		"huge length":        {0x04, 0x84, 0x7f, 0xff, 0xff, 0xff},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadPacket(bytes.NewReader(data))
+			if err == nil {
+				t.Fatal("want error but got none")
+			}
+			if name == "empty" && err != io.EOF {
+				t.Fatalf("want io.EOF but got %v", err)
+			}
+		})
+	}
+}
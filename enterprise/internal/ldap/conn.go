@@ -0,0 +1,430 @@
+// Package ldap implements a client for the subset of the LDAP v3 protocol
+// (https://datatracker.ietf.org/doc/html/rfc4511) which is needed to authenticate users and look
+// up their groups: simple binds, searches and StartTLS.
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ber"
+)
+
+// Tags of the protocol operations of the application class.
+const (
+	TagBindRequest       = 0
+	TagBindResponse      = 1
+	TagUnbindRequest     = 2
+	TagSearchRequest     = 3
+	TagSearchResultEntry = 4
+	TagSearchResultDone  = 5
+	TagSearchResultRef   = 19
+	TagExtendedRequest   = 23
+	TagExtendedResponse  = 24
+)
+
+// StartTLSOID is the name of the StartTLS extended operation.
+const StartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Result codes of LDAP operations.
+const (
+	ResultSuccess                  = 0
+	ResultOperationsError          = 1
+	ResultProtocolError            = 2
+	ResultSizeLimitExceeded        = 4
+	ResultNoSuchObject             = 32
+	ResultInvalidCredentials       = 49
+	ResultInsufficientAccessRights = 50
+	ResultUnwillingToPerform       = 53
+)
+
+// Error is an unsuccessful result of an LDAP operation.
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("LDAP result code %d", e.ResultCode)
+	}
+	return fmt.Sprintf("LDAP result code %d: %s", e.ResultCode, e.Message)
+}
+
+// IsInvalidCredentials returns whether err is an LDAP error for invalid credentials.
+func IsInvalidCredentials(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.ResultCode == ResultInvalidCredentials
+}
+
+// Conn is a connection to an LDAP server. Operations on a connection are performed one at a
+// time.
+type Conn struct {
+	mu   sync.Mutex
+	conn net.Conn
+	br   *bufio.Reader
+	// host is the name of the host of the server, which is used to verify its certificate.
+	host   string
+	msgID  int64
+	isTLS  bool
+	closed bool
+}
+
+// Dial connects to the LDAP server with the given URL, whose scheme is either "ldap" or "ldaps".
+// The tlsConfig is used for "ldaps" URLs and may be nil.
+func Dial(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing URL")
+	}
+
+	var useTLS bool
+	port := "389"
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		useTLS = true
+		port = "636"
+	default:
+		return nil, errors.Errorf("unsupported URL scheme %q, expected ldap or ldaps", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(nc, u.Hostname())
+	if useTLS {
+		if err := c.handshake(ctx, tlsConfig); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func newConn(nc net.Conn, host string) *Conn {
+	return &Conn{conn: nc, br: bufio.NewReader(nc), host: host}
+}
+
+// handshake upgrades the connection to TLS.
+func (c *Conn) handshake(ctx context.Context, tlsConfig *tls.Config) error {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = c.host
+	}
+
+	tc := tls.Client(c.conn, tlsConfig)
+	c.setDeadline(ctx)
+	if err := tc.Handshake(); err != nil {
+		return errors.Wrap(err, "TLS handshake")
+	}
+	c.conn = tc
+	c.br = bufio.NewReader(tc)
+	c.isTLS = true
+	return nil
+}
+
+// StartTLS upgrades the connection to TLS with the StartTLS extended operation. The tlsConfig
+// may be nil. The certificate of the server is verified against the host of the URL it was dialed
+// with, unless tlsConfig specifies another server name.
+func (c *Conn) StartTLS(ctx context.Context, tlsConfig *tls.Config) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isTLS {
+		return errors.New("connection already uses TLS")
+	}
+
+	req := ber.NewConstructed(ber.ClassApplication, TagExtendedRequest,
+		ber.NewPrimitive(ber.ClassContext, 0, []byte(StartTLSOID)),
+	)
+	resp, err := c.roundTrip(ctx, req, TagExtendedResponse)
+	if err != nil {
+		return errors.Wrap(err, "StartTLS")
+	}
+	if err := resultError(resp); err != nil {
+		return errors.Wrap(err, "StartTLS")
+	}
+	return c.handshake(ctx, tlsConfig)
+}
+
+// Bind authenticates the connection with a simple bind of the given DN and password.
+//
+// 🚨 SECURITY: An empty password is rejected, because LDAP servers treat binds with an empty
+// password as unauthenticated binds, which succeed for any DN.
+func (c *Conn) Bind(ctx context.Context, dn, password string) error {
+	if password == "" {
+		return &Error{ResultCode: ResultInvalidCredentials, Message: "empty password"}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req := ber.NewConstructed(ber.ClassApplication, TagBindRequest,
+		ber.NewInteger(3),
+		ber.NewString(dn),
+		ber.NewPrimitive(ber.ClassContext, 0, []byte(password)),
+	)
+	resp, err := c.roundTrip(ctx, req, TagBindResponse)
+	if err != nil {
+		return errors.Wrap(err, "bind")
+	}
+	return resultError(resp)
+}
+
+// Scope is the scope of a search.
+type Scope int
+
+const (
+	ScopeBaseObject   Scope = 0
+	ScopeSingleLevel  Scope = 1
+	ScopeWholeSubtree Scope = 2
+)
+
+// SearchRequest is a search for entries.
+type SearchRequest struct {
+	BaseDN string
+	Scope  Scope
+	// Filter is the string representation of the filter of the search
+	// (https://datatracker.ietf.org/doc/html/rfc4515), such as "(&(objectClass=person)(uid=alice))".
+	// It defaults to "(objectClass=*)", which matches all entries.
+	Filter string
+	// Attributes are the attributes to return for each entry.
+	Attributes []string
+	// SizeLimit is the maximum number of entries to return, or 0 for no limit.
+	SizeLimit int
+}
+
+// Entry is an entry returned by a search.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Values returns the values of the attribute, whose name is case-insensitive.
+func (e *Entry) Values(attr string) []string {
+	for name, vs := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return vs
+		}
+	}
+	return nil
+}
+
+// Value returns the first value of the attribute, whose name is case-insensitive, or "" if it
+// has none.
+func (e *Entry) Value(attr string) string {
+	if vs := e.Values(attr); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// Search returns the entries matching the search request.
+func (c *Conn) Search(ctx context.Context, req *SearchRequest) ([]*Entry, error) {
+	rawFilter := req.Filter
+	if rawFilter == "" {
+		rawFilter = "(objectClass=*)"
+	}
+	filter, err := CompileFilter(rawFilter)
+	if err != nil {
+		return nil, err
+	}
+	attributes := ber.NewSequence()
+	for _, a := range req.Attributes {
+		attributes.Children = append(attributes.Children, ber.NewString(a))
+	}
+	op := ber.NewConstructed(ber.ClassApplication, TagSearchRequest,
+		ber.NewString(req.BaseDN),
+		ber.NewEnumerated(int64(req.Scope)),
+		ber.NewEnumerated(0), // neverDerefAliases
+		ber.NewInteger(int64(req.SizeLimit)),
+		ber.NewInteger(0), // no time limit
+		ber.NewBoolean(false),
+		filter,
+		attributes,
+	)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, err := c.send(ctx, op)
+	if err != nil {
+		return nil, errors.Wrap(err, "search")
+	}
+
+	var entries []*Entry
+	for {
+		resp, err := c.receive(id)
+		if err != nil {
+			return nil, errors.Wrap(err, "search")
+		}
+		switch {
+		case resp.Is(ber.ClassApplication, TagSearchResultEntry):
+			e, err := parseEntry(resp)
+			if err != nil {
+				return nil, errors.Wrap(err, "parsing search result entry")
+			}
+			entries = append(entries, e)
+
+		case resp.Is(ber.ClassApplication, TagSearchResultRef):
+			// Referrals to other servers are not followed.
+
+		case resp.Is(ber.ClassApplication, TagSearchResultDone):
+			if err := resultError(resp); err != nil {
+				return nil, errors.Wrap(err, "search")
+			}
+			return entries, nil
+
+		default:
+			return nil, errors.Errorf("unexpected search response with tag %d", resp.Tag)
+		}
+	}
+}
+
+func parseEntry(p *ber.Packet) (*Entry, error) {
+	dn, err := p.Child(0)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := p.Child(1)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entry{DN: dn.String(), Attributes: make(map[string][]string, len(attrs.Children))}
+	for _, attr := range attrs.Children {
+		name, err := attr.Child(0)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := attr.Child(1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(vals.Children))
+		for _, v := range vals.Children {
+			values = append(values, v.String())
+		}
+		e.Attributes[name.String()] = values
+	}
+	return e, nil
+}
+
+// Close sends an unbind request and closes the connection.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	// The server doesn't respond to unbind requests, so errors sending it are ignored.
+	_, _ = c.send(context.Background(), ber.NewPrimitive(ber.ClassApplication, TagUnbindRequest, nil))
+	return c.conn.Close()
+}
+
+// roundTrip sends an operation and receives its response, which must have the given tag.
+func (c *Conn) roundTrip(ctx context.Context, op *ber.Packet, respTag int) (*ber.Packet, error) {
+	id, err := c.send(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.receive(id)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Is(ber.ClassApplication, respTag) {
+		return nil, errors.Errorf("unexpected response with tag %d, expected %d", resp.Tag, respTag)
+	}
+	return resp, nil
+}
+
+// send sends a message with the operation and returns its ID. The deadline of the context
+// applies to the operation until the next call to send.
+func (c *Conn) send(ctx context.Context, op *ber.Packet) (int64, error) {
+	if c.closed && !op.Is(ber.ClassApplication, TagUnbindRequest) {
+		return 0, errors.New("connection is closed")
+	}
+
+	c.msgID++
+	msg := ber.NewSequence(ber.NewInteger(c.msgID), op)
+	c.setDeadline(ctx)
+	if _, err := c.conn.Write(msg.Bytes()); err != nil {
+		return 0, err
+	}
+	return c.msgID, nil
+}
+
+// receive reads the operation of the next message, which must have the given ID.
+func (c *Conn) receive(id int64) (*ber.Packet, error) {
+	msg, err := ber.ReadPacket(c.br)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Is(ber.ClassUniversal, ber.TagSequence) || len(msg.Children) < 2 {
+		return nil, errors.New("malformed message")
+	}
+	msgID, err := msg.Children[0].Int()
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed message ID")
+	}
+	op := msg.Children[1]
+	if msgID == 0 {
+		// Unsolicited notifications, such as the notice of disconnection, have a zero ID.
+		if err := resultError(op); err != nil {
+			return nil, errors.Wrap(err, "unsolicited notification")
+		}
+		return nil, errors.New("unsolicited notification")
+	}
+	if msgID != id {
+		return nil, errors.Errorf("unexpected message ID %d, expected %d", msgID, id)
+	}
+	return op, nil
+}
+
+func (c *Conn) setDeadline(ctx context.Context) {
+	deadline, _ := ctx.Deadline()
+	_ = c.conn.SetDeadline(deadline)
+}
+
+// resultError returns the error of an LDAPResult, or nil if it is successful.
+func resultError(p *ber.Packet) error {
+	code, err := p.Child(0)
+	if err != nil {
+		return err
+	}
+	rc, err := code.Int()
+	if err != nil {
+		return errors.Wrap(err, "malformed result code")
+	}
+	if rc == ResultSuccess {
+		return nil
+	}
+
+	var message string
+	if m, err := p.Child(2); err == nil {
+		message = m.String()
+	}
+	return &Error{ResultCode: int(rc), Message: message}
+}
@@ -0,0 +1,264 @@
+package ldap_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ldaptest"
+)
+
+var testEntries = []ldaptest.Entry{
+	{
+		DN: "cn=admin,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"person"},
+			"userPassword": {"admin-secret"},
+		},
+	},
+	{
+		DN: "uid=alice,ou=People,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":  {"inetOrgPerson"},
+			"uid":          {"alice"},
+			"mail":         {"alice@example.com"},
+			"cn":           {"Alice Liddell"},
+			"userPassword": {"alice-secret"},
+		},
+	},
+	{
+		DN: "uid=bob,ou=People,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass": {"inetOrgPerson"},
+			"uid":         {"bob"},
+			"mail":        {"bob@example.com"},
+		},
+	},
+	{
+		DN: "cn=engineering,ou=Groups,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {"engineering"},
+			"member":      {"uid=alice,ou=People,dc=example,dc=com", "uid=bob,ou=People,dc=example,dc=com"},
+		},
+	},
+}
+
+func TestConn_Bind(t *testing.T) {
+	s := ldaptest.NewServer(testEntries...)
+	defer s.Close()
+
+	tests := []struct {
+		name     string
+		dn       string
+		password string
+		wantErr  bool
+	}{
+		{name: "valid credentials", dn: "uid=alice,ou=People,dc=example,dc=com", password: "alice-secret"},
+		{name: "DN with different case", dn: "UID=Alice, OU=People, DC=example, DC=com", password: "alice-secret"},
+		{name: "wrong password", dn: "uid=alice,ou=People,dc=example,dc=com", password: "bob-secret", wantErr: true},
+		{name: "unknown DN", dn: "uid=carol,ou=People,dc=example,dc=com", password: "alice-secret", wantErr: true},
+		{name: "empty password", dn: "uid=alice,ou=People,dc=example,dc=com", password: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, err := ldap.Dial(ctx, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			err = c.Bind(ctx, test.dn, test.password)
+			if test.wantErr {
+				if !ldap.IsInvalidCredentials(err) {
+					t.Fatalf("want invalid credentials error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestConn_Search(t *testing.T) {
+	s := ldaptest.NewServer(testEntries...)
+	defer s.Close()
+
+	ctx := context.Background()
+	c, err := ldap.Dial(ctx, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Searches require a bind.
+	if _, err := c.Search(ctx, &ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree}); err == nil {
+		t.Fatal("want error searching before binding but got none")
+	}
+	if err := c.Bind(ctx, "cn=admin,dc=example,dc=com", "admin-secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		req     ldap.SearchRequest
+		wantDNs []string
+	}{
+		{
+			name:    "equality",
+			req:     ldap.SearchRequest{BaseDN: "ou=People,dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, Filter: "(uid=ALICE)"},
+			wantDNs: []string{"uid=alice,ou=People,dc=example,dc=com"},
+		},
+		{
+			name:    "and with presence",
+			req:     ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, Filter: "(&(objectClass=inetOrgPerson)(cn=*))"},
+			wantDNs: []string{"uid=alice,ou=People,dc=example,dc=com"},
+		},
+		{
+			name: "or with substrings",
+			req:  ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, Filter: "(|(mail=*@example.com)(cn=eng*))"},
+			wantDNs: []string{
+				"cn=engineering,ou=Groups,dc=example,dc=com",
+				"uid=alice,ou=People,dc=example,dc=com",
+				"uid=bob,ou=People,dc=example,dc=com",
+			},
+		},
+		{
+			name:    "not",
+			req:     ldap.SearchRequest{BaseDN: "ou=People,dc=example,dc=com", Scope: ldap.ScopeSingleLevel, Filter: "(!(uid=alice))"},
+			wantDNs: []string{"uid=bob,ou=People,dc=example,dc=com"},
+		},
+		{
+			name:    "base object",
+			req:     ldap.SearchRequest{BaseDN: "cn=engineering,ou=Groups,dc=example,dc=com", Scope: ldap.ScopeBaseObject},
+			wantDNs: []string{"cn=engineering,ou=Groups,dc=example,dc=com"},
+		},
+		{
+			name:    "escaped value",
+			req:     ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, Filter: "(uid=" + ldap.EscapeFilter("*") + ")"},
+			wantDNs: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := c.Search(ctx, &test.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dns []string
+			for _, e := range entries {
+				dns = append(dns, e.DN)
+			}
+			sort.Strings(dns)
+			if diff := cmp.Diff(test.wantDNs, dns); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("attributes", func(t *testing.T) {
+		entries, err := c.Search(ctx, &ldap.SearchRequest{
+			BaseDN:     "dc=example,dc=com",
+			Scope:      ldap.ScopeWholeSubtree,
+			Filter:     "(uid=alice)",
+			Attributes: []string{"mail", "CN"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("want 1 entry but got %d", len(entries))
+		}
+		want := map[string][]string{"mail": {"alice@example.com"}, "cn": {"Alice Liddell"}}
+		if diff := cmp.Diff(want, entries[0].Attributes); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+		if got := entries[0].Value("CN"); got != "Alice Liddell" {
+			t.Fatalf("want display name %q but got %q", "Alice Liddell", got)
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, err := c.Search(ctx, &ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, SizeLimit: 1})
+		var e *ldap.Error
+		if !errors.As(err, &e) || e.ResultCode != ldap.ResultSizeLimitExceeded {
+			t.Fatalf("want size limit exceeded error but got %v", err)
+		}
+	})
+}
+
+func TestConn_TLS(t *testing.T) {
+	for name, newServer := range map[string]func(...ldaptest.Entry) *ldaptest.Server{
+		"StartTLS": ldaptest.NewStartTLSServer,
+		"LDAPS":    ldaptest.NewTLSServer,
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newServer(testEntries...)
+			defer s.Close()
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(s.Certificate)) {
+				t.Fatal("failed to parse certificate")
+			}
+			tlsConfig := &tls.Config{RootCAs: pool}
+
+			ctx := context.Background()
+			c, err := ldap.Dial(ctx, s.URL, tlsConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			if name == "StartTLS" {
+				if err := c.StartTLS(ctx, tlsConfig); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := c.Bind(ctx, "uid=alice,ou=People,dc=example,dc=com", "alice-secret"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		s := ldaptest.NewStartTLSServer(testEntries...)
+		defer s.Close()
+
+		ctx := context.Background()
+		c, err := ldap.Dial(ctx, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		if err := c.StartTLS(ctx, nil); err == nil {
+			t.Fatal("want error for untrusted certificate but got none")
+		}
+	})
+
+	t.Run("StartTLS not supported", func(t *testing.T) {
+		s := ldaptest.NewServer(testEntries...)
+		defer s.Close()
+
+		ctx := context.Background()
+		c, err := ldap.Dial(ctx, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		var e *ldap.Error
+		if err := c.StartTLS(ctx, nil); !errors.As(err, &e) || e.ResultCode != ldap.ResultProtocolError {
+			t.Fatalf("want protocol error but got %v", err)
+		}
+	})
+}
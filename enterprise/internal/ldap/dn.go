@@ -0,0 +1,34 @@
+package ldap
+
+import "strings"
+
+// NormalizeDN returns a normalized form of a distinguished name, so that DNs which differ only
+// by case or by spaces around their separators are equal. For example, "CN=Alice, OU=People"
+// is normalized to "cn=alice,ou=people".
+func NormalizeDN(dn string) string {
+	var rdns []string
+	var b strings.Builder
+	escaped := false
+	for _, c := range dn {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == ',' || c == ';':
+			rdns = append(rdns, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(c)
+	}
+	rdns = append(rdns, b.String())
+
+	for i, rdn := range rdns {
+		if j := strings.IndexByte(rdn, '='); j >= 0 {
+			rdn = strings.TrimSpace(rdn[:j]) + "=" + strings.TrimSpace(rdn[j+1:])
+		}
+		rdns[i] = strings.ToLower(strings.TrimSpace(rdn))
+	}
+	return strings.Join(rdns, ",")
+}
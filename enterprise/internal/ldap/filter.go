@@ -0,0 +1,212 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ber"
+)
+
+// Tags of the choices of search filters, of the context-specific class.
+const (
+	FilterAnd             = 0
+	FilterOr              = 1
+	FilterNot             = 2
+	FilterEqualityMatch   = 3
+	FilterSubstrings      = 4
+	FilterGreaterOrEqual  = 5
+	FilterLessOrEqual     = 6
+	FilterPresent         = 7
+	FilterApproxMatch     = 8
+	FilterExtensibleMatch = 9
+)
+
+// Tags of the parts of substrings filters, of the context-specific class.
+const (
+	SubstringInitial = 0
+	SubstringAny     = 1
+	SubstringFinal   = 2
+)
+
+// EscapeFilter escapes a value so that it can be used literally in an assertion of a filter.
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			b.WriteByte('\\')
+			b.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// CompileFilter compiles the string representation of a search filter
+// (https://datatracker.ietf.org/doc/html/rfc4515) to its protocol encoding. Extensible match
+// filters are not supported.
+func CompileFilter(filter string) (*ber.Packet, error) {
+	p := &filterParser{s: filter}
+	f, err := p.filter()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter %q", filter)
+	}
+	if p.pos != len(p.s) {
+		return nil, errors.Errorf("invalid filter %q: unexpected %q at offset %d", filter, p.s[p.pos:], p.pos)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+func (p *filterParser) filter() (*ber.Packet, error) {
+	if !p.consume("(") {
+		return nil, errors.Errorf("expected '(' at offset %d", p.pos)
+	}
+
+	var f *ber.Packet
+	var err error
+	switch {
+	case p.consume("&"):
+		f, err = p.filterList(FilterAnd)
+	case p.consume("|"):
+		f, err = p.filterList(FilterOr)
+	case p.consume("!"):
+		var not *ber.Packet
+		not, err = p.filter()
+		f = ber.NewConstructed(ber.ClassContext, FilterNot, not)
+	default:
+		f, err = p.item()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume(")") {
+		return nil, errors.Errorf("expected ')' at offset %d", p.pos)
+	}
+	return f, nil
+}
+
+func (p *filterParser) filterList(tag int) (*ber.Packet, error) {
+	list := ber.NewConstructed(ber.ClassContext, tag)
+	for strings.HasPrefix(p.s[p.pos:], "(") {
+		f, err := p.filter()
+		if err != nil {
+			return nil, err
+		}
+		list.Children = append(list.Children, f)
+	}
+	if len(list.Children) == 0 {
+		return nil, errors.Errorf("expected a filter at offset %d", p.pos)
+	}
+	return list, nil
+}
+
+func (p *filterParser) item() (*ber.Packet, error) {
+	end := strings.IndexAny(p.s[p.pos:], "=()")
+	if end <= 0 || p.s[p.pos+end] != '=' {
+		return nil, errors.Errorf("expected an attribute and '=' at offset %d", p.pos)
+	}
+	attr := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	tag := FilterEqualityMatch
+	switch {
+	case strings.HasSuffix(attr, "~"):
+		tag = FilterApproxMatch
+	case strings.HasSuffix(attr, ">"):
+		tag = FilterGreaterOrEqual
+	case strings.HasSuffix(attr, "<"):
+		tag = FilterLessOrEqual
+	case strings.HasSuffix(attr, ":"):
+		return nil, errors.New("extensible match filters are not supported")
+	}
+	if tag != FilterEqualityMatch {
+		attr = attr[:len(attr)-1]
+	}
+	if attr == "" {
+		return nil, errors.Errorf("empty attribute at offset %d", p.pos)
+	}
+
+	end = strings.IndexAny(p.s[p.pos:], "()")
+	if end < 0 {
+		return nil, errors.New("unterminated filter")
+	}
+	rawValue := p.s[p.pos : p.pos+end]
+	p.pos += end
+
+	parts, err := unescapeFilterValue(rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 1 {
+		return ber.NewConstructed(ber.ClassContext, tag, ber.NewString(attr), ber.NewString(parts[0])), nil
+	}
+	if tag != FilterEqualityMatch {
+		return nil, errors.Errorf("unexpected '*' in value of attribute %q", attr)
+	}
+	if len(parts) == 2 && parts[0] == "" && parts[1] == "" {
+		return ber.NewPrimitive(ber.ClassContext, FilterPresent, []byte(attr)), nil
+	}
+
+	substrings := ber.NewSequence()
+	for i, part := range parts {
+		if part == "" {
+			if i == 0 || i == len(parts)-1 {
+				continue
+			}
+			return nil, errors.Errorf("unexpected '**' in value of attribute %q", attr)
+		}
+		partTag := SubstringAny
+		switch i {
+		case 0:
+			partTag = SubstringInitial
+		case len(parts) - 1:
+			partTag = SubstringFinal
+		}
+		substrings.Children = append(substrings.Children, ber.NewPrimitive(ber.ClassContext, partTag, []byte(part)))
+	}
+	return ber.NewConstructed(ber.ClassContext, FilterSubstrings, ber.NewString(attr), substrings), nil
+}
+
+func (p *filterParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+// unescapeFilterValue unescapes the value of an assertion and splits it at its unescaped '*'s.
+func unescapeFilterValue(raw string) ([]string, error) {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; c {
+		case '*':
+			parts = append(parts, b.String())
+			b.Reset()
+		case '\\':
+			if i+2 >= len(raw) {
+				return nil, errors.Errorf("invalid escape sequence in %q", raw)
+			}
+			decoded, err := hex.DecodeString(raw[i+1 : i+3])
+			if err != nil {
+				return nil, errors.Errorf("invalid escape sequence in %q", raw)
+			}
+			b.Write(decoded)
+			i += 2
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(parts, b.String()), nil
+}
@@ -0,0 +1,98 @@
+package ldap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ber"
+)
+
+func TestCompileFilter(t *testing.T) {
+	str := func(s string) *ber.Packet { return ber.NewString(s) }
+	ctx := func(tag int, s string) *ber.Packet { return ber.NewPrimitive(ber.ClassContext, tag, []byte(s)) }
+	cons := func(tag int, children ...*ber.Packet) *ber.Packet {
+		return ber.NewConstructed(ber.ClassContext, tag, children...)
+	}
+
+	tests := []struct {
+		filter string
+		want   *ber.Packet
+	}{
+		{filter: "(uid=alice)", want: cons(FilterEqualityMatch, str("uid"), str("alice"))},
+		{filter: "(cn=*)", want: ctx(FilterPresent, "cn")},
+		{filter: "(cn~=alice)", want: cons(FilterApproxMatch, str("cn"), str("alice"))},
+		{filter: "(uidNumber>=1000)", want: cons(FilterGreaterOrEqual, str("uidNumber"), str("1000"))},
+		{filter: "(uidNumber<=1000)", want: cons(FilterLessOrEqual, str("uidNumber"), str("1000"))},
+		{filter: `(cn=a\2a\28b\29)`, want: cons(FilterEqualityMatch, str("cn"), str("a*(b)"))},
+		{
+			filter: "(cn=a*b*c)",
+			want: cons(FilterSubstrings, str("cn"), ber.NewSequence(
+				ctx(SubstringInitial, "a"), ctx(SubstringAny, "b"), ctx(SubstringFinal, "c"),
+			)),
+		},
+		{
+			filter: "(mail=*@example.com)",
+			want:   cons(FilterSubstrings, str("mail"), ber.NewSequence(ctx(SubstringFinal, "@example.com"))),
+		},
+		{
+			filter: "(&(objectClass=person)(|(uid=alice)(!(uid=bob))))",
+			want: cons(FilterAnd,
+				cons(FilterEqualityMatch, str("objectClass"), str("person")),
+				cons(FilterOr,
+					cons(FilterEqualityMatch, str("uid"), str("alice")),
+					cons(FilterNot, cons(FilterEqualityMatch, str("uid"), str("bob"))),
+				),
+			),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.filter, func(t *testing.T) {
+			got, err := CompileFilter(test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Bytes(), test.want.Bytes()) {
+				t.Fatalf("want %x but got %x", test.want.Bytes(), got.Bytes())
+			}
+		})
+	}
+
+	for _, filter := range []string{
+		"",
+		"uid=alice",
+		"(uid=alice",
+		"(uid=alice))",
+		"(&)",
+		"(=alice)",
+		"(uid)",
+		"(uid>=a*)",
+		"(cn=a**b)",
+		`(cn=a\2)`,
+		`(cn=a\zz)`,
+		"(cn:caseExactMatch:=alice)",
+	} {
+		t.Run("invalid "+filter, func(t *testing.T) {
+			if _, err := CompileFilter(filter); err == nil {
+				t.Fatalf("want error for filter %q but got none", filter)
+			}
+		})
+	}
+}
+
+func TestEscapeFilter(t *testing.T) {
+	if got, want := EscapeFilter(`a*b(c)d\e`+"\x00"), `a\2ab\28c\29d\5ce\00`; got != want {
+		t.Fatalf("want %q but got %q", want, got)
+	}
+}
+
+func TestNormalizeDN(t *testing.T) {
+	for dn, want := range map[string]string{
+		"uid=alice,ou=people,dc=example,dc=com":      "uid=alice,ou=people,dc=example,dc=com",
+		"UID=Alice, OU=People , DC = example;DC=com": "uid=alice,ou=people,dc=example,dc=com",
+		`CN=Liddell\, Alice,OU=People`:               `cn=liddell\, alice,ou=people`,
+	} {
+		if got := NormalizeDN(dn); got != want {
+			t.Errorf("NormalizeDN(%q): want %q but got %q", dn, want, got)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+// Package ldaptest provides an in-process LDAP server for tests.
+//
+// The server supports simple binds, searches and StartTLS, which is the subset of the protocol
+// that the ldap package uses. Entries are kept in memory and their passwords are stored in
+// plain text in their "userPassword" attribute.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/ldap/ber"
+)
+
+// Entry is an entry of the directory of a Server.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Server is an LDAP server listening on a local address.
+type Server struct {
+	// URL is the URL of the server, such as "ldap://127.0.0.1:12345".
+	URL string
+	// Certificate is the PEM-encoded self-signed certificate of servers created with
+	// NewStartTLSServer or NewTLSServer.
+	Certificate string
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	entries []Entry
+	conns   map[net.Conn]struct{}
+	closed  bool
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server with the given entries, which doesn't support TLS. The caller should
+// call Close when finished, to shut it down.
+func NewServer(entries ...Entry) *Server {
+	return newServer("ldap", nil, "", entries)
+}
+
+// NewStartTLSServer starts a server with the given entries, which supports upgrading connections
+// to TLS with StartTLS. The caller should call Close when finished, to shut it down.
+func NewStartTLSServer(entries ...Entry) *Server {
+	cfg, cert := newTLSConfig()
+	return newServer("ldap", cfg, cert, entries)
+}
+
+// NewTLSServer starts a server with the given entries, which only accepts TLS connections. The
+// caller should call Close when finished, to shut it down.
+func NewTLSServer(entries ...Entry) *Server {
+	cfg, cert := newTLSConfig()
+	return newServer("ldaps", cfg, cert, entries)
+}
+
+func newServer(scheme string, tlsConfig *tls.Config, cert string, entries []Entry) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("ldaptest: failed to listen: " + err.Error())
+	}
+	if scheme == "ldaps" {
+		l = tls.NewListener(l, tlsConfig)
+	}
+
+	s := &Server{
+		URL:         scheme + "://" + l.Addr().String(),
+		Certificate: cert,
+		listener:    l,
+		tlsConfig:   tlsConfig,
+		entries:     entries,
+		conns:       make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// SetEntries replaces the entries of the directory.
+func (s *Server) SetEntries(entries ...Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = entries
+}
+
+// Close shuts down the server and closes its connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(c)
+		}()
+	}
+}
+
+// session is the state of a connection.
+type session struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	isTLS bool
+	// boundDN is the DN of the entry that the connection is authenticated as, or "" for
+	// anonymous connections.
+	boundDN string
+}
+
+func (s *Server) serveConn(c net.Conn) {
+	sess := &session{conn: c, r: bufio.NewReader(c), isTLS: strings.HasPrefix(s.URL, "ldaps:")}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		sess.conn.Close()
+	}()
+
+	for {
+		msg, err := ber.ReadPacket(sess.r)
+		if err != nil {
+			return
+		}
+		if len(msg.Children) < 2 {
+			return
+		}
+		id, err := msg.Children[0].Int()
+		if err != nil {
+			return
+		}
+
+		op := msg.Children[1]
+		if op.Class != ber.ClassApplication {
+			return
+		}
+		switch op.Tag {
+		case ldap.TagBindRequest:
+			err = s.bind(sess, id, op)
+		case ldap.TagSearchRequest:
+			err = s.search(sess, id, op)
+		case ldap.TagExtendedRequest:
+			err = s.extended(sess, id, op)
+		default:
+			// Unbind requests and unsupported operations end the connection.
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) bind(sess *session, id int64, op *ber.Packet) error {
+	if len(op.Children) < 3 {
+		return errors.New("malformed bind request")
+	}
+	dn, password := op.Children[1].String(), op.Children[2].String()
+
+	code := ldap.ResultInvalidCredentials
+	if dn == "" && password == "" {
+		sess.boundDN = ""
+		code = ldap.ResultSuccess
+	} else if e := s.entry(dn); e != nil && password != "" && containsString(e.Attributes["userPassword"], password) {
+		sess.boundDN = e.DN
+		code = ldap.ResultSuccess
+	}
+	return sess.send(id, result(ldap.TagBindResponse, code))
+}
+
+func (s *Server) extended(sess *session, id int64, op *ber.Packet) error {
+	if len(op.Children) < 1 || op.Children[0].String() != ldap.StartTLSOID || s.tlsConfig == nil || sess.isTLS {
+		return sess.send(id, result(ldap.TagExtendedResponse, ldap.ResultProtocolError))
+	}
+	if err := sess.send(id, result(ldap.TagExtendedResponse, ldap.ResultSuccess)); err != nil {
+		return err
+	}
+
+	tc := tls.Server(sess.conn, s.tlsConfig)
+	if err := tc.Handshake(); err != nil {
+		return err
+	}
+	sess.conn = tc
+	sess.r = bufio.NewReader(tc)
+	sess.isTLS = true
+	return nil
+}
+
+func (s *Server) search(sess *session, id int64, op *ber.Packet) error {
+	if len(op.Children) < 8 {
+		return errors.New("malformed search request")
+	}
+	if sess.boundDN == "" {
+		return sess.send(id, result(ldap.TagSearchResultDone, ldap.ResultInsufficientAccessRights))
+	}
+
+	baseDN := ldap.NormalizeDN(op.Children[0].String())
+	scope, err := op.Children[1].Int()
+	if err != nil {
+		return err
+	}
+	sizeLimit, err := op.Children[3].Int()
+	if err != nil {
+		return err
+	}
+	filter := op.Children[6]
+	var attributes []string
+	for _, a := range op.Children[7].Children {
+		attributes = append(attributes, a.String())
+	}
+
+	s.mu.Lock()
+	entries := s.entries
+	s.mu.Unlock()
+
+	var n int64
+	for _, e := range entries {
+		if !inScope(ldap.NormalizeDN(e.DN), baseDN, ldap.Scope(scope)) || !matches(e, filter) {
+			continue
+		}
+		if sizeLimit > 0 && n == sizeLimit {
+			return sess.send(id, result(ldap.TagSearchResultDone, ldap.ResultSizeLimitExceeded))
+		}
+		n++
+		if err := sess.send(id, searchResultEntry(e, attributes)); err != nil {
+			return err
+		}
+	}
+	return sess.send(id, result(ldap.TagSearchResultDone, ldap.ResultSuccess))
+}
+
+func (s *Server) entry(dn string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dn = ldap.NormalizeDN(dn)
+	for i := range s.entries {
+		if ldap.NormalizeDN(s.entries[i].DN) == dn {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func (sess *session) send(id int64, op *ber.Packet) error {
+	_, err := sess.conn.Write(ber.NewSequence(ber.NewInteger(id), op).Bytes())
+	return err
+}
+
+func result(tag, code int) *ber.Packet {
+	return ber.NewConstructed(ber.ClassApplication, tag,
+		ber.NewEnumerated(int64(code)),
+		ber.NewString(""),
+		ber.NewString(""),
+	)
+}
+
+func searchResultEntry(e Entry, attributes []string) *ber.Packet {
+	attrs := ber.NewSequence()
+	for name, values := range e.Attributes {
+		if name == "userPassword" || (len(attributes) > 0 && !containsFold(attributes, name)) {
+			continue
+		}
+		vals := ber.NewSet()
+		for _, v := range values {
+			vals.Children = append(vals.Children, ber.NewString(v))
+		}
+		attrs.Children = append(attrs.Children, ber.NewSequence(ber.NewString(name), vals))
+	}
+	return ber.NewConstructed(ber.ClassApplication, ldap.TagSearchResultEntry, ber.NewString(e.DN), attrs)
+}
+
+func inScope(dn, baseDN string, scope ldap.Scope) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == baseDN
+	case ldap.ScopeSingleLevel:
+		i := strings.IndexByte(dn, ',')
+		return i >= 0 && dn[i+1:] == baseDN
+	default:
+		return dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+// matches returns whether the entry matches the filter. Values are compared case-insensitively.
+func matches(e Entry, filter *ber.Packet) bool {
+	if filter.Class != ber.ClassContext {
+		return false
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, f := range filter.Children {
+			if !matches(e, f) {
+				return false
+			}
+		}
+		return true
+
+	case ldap.FilterOr:
+		for _, f := range filter.Children {
+			if matches(e, f) {
+				return true
+			}
+		}
+		return false
+
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !matches(e, filter.Children[0])
+
+	case ldap.FilterPresent:
+		return strings.EqualFold(filter.String(), "objectClass") || len(values(e, filter.String())) > 0
+
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		want := strings.ToLower(filter.Children[1].String())
+		for _, v := range values(e, filter.Children[0].String()) {
+			v = strings.ToLower(v)
+			switch {
+			case filter.Tag == ldap.FilterGreaterOrEqual && v >= want,
+				filter.Tag == ldap.FilterLessOrEqual && v <= want,
+				v == want:
+				return true
+			}
+		}
+		return false
+
+	case ldap.FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		for _, v := range values(e, filter.Children[0].String()) {
+			if matchesSubstrings(strings.ToLower(v), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func matchesSubstrings(v string, parts []*ber.Packet) bool {
+	for _, part := range parts {
+		s := strings.ToLower(part.String())
+		switch part.Tag {
+		case ldap.SubstringInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldap.SubstringAny:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		case ldap.SubstringFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func values(e Entry, attr string) []string {
+	for name, vs := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return vs
+		}
+	}
+	return nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(ss []string, s string) bool {
+	for _, v := range ss {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTLSConfig returns a TLS configuration with a self-signed certificate for 127.0.0.1 and
+// localhost, and the PEM encoding of the certificate.
+func newTLSConfig() (*tls.Config, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic("ldaptest: failed to generate key: " + err.Error())
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"ldaptest"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic("ldaptest: failed to create certificate: " + err.Error())
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	return cfg, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,74 @@
+package bitbucketcloud
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Bitbucket Cloud authz providers derived from the connections.
+// It also returns any validation problems with the config, separating these into "serious problems" and
+// "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+func NewAuthzProviders(conns []*types.BitbucketCloudConnection) (ps []authz.Provider, problems []string, warnings []string) {
+	for _, c := range conns {
+		p, err := newAuthzProvider(c.URN, c.BitbucketCloudConnection)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Bitbucket Cloud config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(urn string, c *schema.BitbucketCloudConnection) (authz.Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, errors.Errorf("Could not parse URL for Bitbucket Cloud %q: %s", c.Url, err)
+	}
+
+	rawAPIURL := c.ApiURL
+	if rawAPIURL == "" {
+		rawAPIURL = "https://api.bitbucket.org"
+	}
+	apiURL, err := url.Parse(rawAPIURL)
+	if err != nil {
+		return nil, errors.Errorf("Could not parse API URL for Bitbucket Cloud %q: %s", rawAPIURL, err)
+	}
+
+	client := bitbucketcloud.NewClient(extsvc.NormalizeBaseURL(apiURL), nil)
+	client.Username = c.Username
+	client.AppPassword = c.AppPassword
+
+	// The repositories of the connection are those of the workspace of the user
+	// and of the teams.
+	workspaces := append([]string{c.Username}, c.Teams...)
+
+	return NewProvider(urn, baseURL, client, workspaces), nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Bitbucket Cloud
+// external service config.
+func ValidateAuthz(cfg *schema.BitbucketCloudConnection) error {
+	_, err := newAuthzProvider("", cfg)
+	return err
+}
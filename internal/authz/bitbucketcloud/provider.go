@@ -0,0 +1,163 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider implements authz.Provider for Bitbucket Cloud repository permissions.
+//
+// The permissions are listed with the workspace permission APIs, which require the
+// client to be authenticated as an admin of the workspaces. Users are identified by
+// their Bitbucket Cloud UUIDs, which are the account IDs of the external accounts
+// created by the Bitbucket Cloud OAuth authentication provider.
+type Provider struct {
+	urn        string
+	codeHost   *extsvc.CodeHost
+	client     *bitbucketcloud.Client
+	workspaces []string
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Bitbucket Cloud authorization provider for the
+// repositories of the given workspaces on the Bitbucket Cloud at baseURL.
+func NewProvider(urn string, baseURL *url.URL, client *bitbucketcloud.Client, workspaces []string) *Provider {
+	return &Provider{
+		urn:        urn,
+		codeHost:   extsvc.NewCodeHost(baseURL, extsvc.TypeBitbucketCloud),
+		client:     client,
+		workspaces: workspaces,
+	}
+}
+
+// FetchAccount implements the authz.Provider interface. It always returns nil, because the Bitbucket
+// Cloud API doesn't provide a way to look up users by email or username. The accounts are created
+// when users sign in with Bitbucket Cloud instead.
+func (p *Provider) FetchAccount(context.Context, *types.User, []*extsvc.Account, []string) (mine *extsvc.Account, err error) {
+	return nil, nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType
+}
+
+func (p *Provider) Validate() (problems []string) {
+	return nil
+}
+
+// FetchUserPerms returns a list of repository UUIDs (on code host) that the given account
+// has read access on the code host, in the workspaces of the connection. The repository UUID
+// has the same value as it would be used as api.ExternalRepoSpec.ID.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-get
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	if account == nil {
+		return nil, errors.New("no account provided")
+	} else if !extsvc.IsHostOfAccount(p.codeHost, account) {
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			account.AccountSpec.ServiceID, p.codeHost.ServiceID)
+	}
+
+	perms := &authz.ExternalUserPermissions{}
+	seen := make(map[extsvc.RepoID]struct{})
+	for _, workspace := range p.workspaces {
+		for page := (&bitbucketcloud.PageToken{Pagelen: 100}); page != nil; {
+			var repoPerms []*bitbucketcloud.RepoPermission
+			var err error
+			if repoPerms, page, err = p.client.UserRepoPermissions(ctx, page, workspace, account.AccountID); err != nil {
+				return perms, errors.Wrapf(err, "list repository permissions of user in workspace %q", workspace)
+			}
+			if !page.HasMore() {
+				page = nil
+			}
+
+			for _, perm := range repoPerms {
+				if perm.Repository == nil {
+					continue
+				}
+				// All permissions ("read", "write" and "admin") grant read access.
+				id := extsvc.RepoID(perm.Repository.UUID)
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					perms.Exacts = append(perms.Exacts, id)
+				}
+			}
+		}
+	}
+	return perms, nil
+}
+
+// FetchRepoPerms returns a list of user UUIDs (on code host) who have read access to
+// the given repository on the code host. The user UUID has the same value as it would
+// be used as extsvc.Account.AccountID. The returned list includes both direct access
+// and inherited from the group membership.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-repo-slug-get
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	if repo == nil {
+		return nil, errors.New("no repository provided")
+	} else if !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec) {
+		return nil, errors.Errorf("not a code host of the repository: want %q but have %q",
+			repo.ServiceID, p.codeHost.ServiceID)
+	}
+
+	// NOTE: We do not store port or scheme in our URI, so stripping the hostname alone is enough.
+	fullName := strings.TrimPrefix(repo.URI, p.codeHost.BaseURL.Hostname())
+	fullName = strings.TrimPrefix(fullName, "/")
+	workspace, slug, ok := splitFullName(fullName)
+	if !ok {
+		return nil, errors.Errorf("invalid Bitbucket Cloud repository name %q", fullName)
+	}
+
+	var userIDs []extsvc.AccountID
+	for page := (&bitbucketcloud.PageToken{Pagelen: 100}); page != nil; {
+		var repoPerms []*bitbucketcloud.RepoPermission
+		var err error
+		if repoPerms, page, err = p.client.RepoPermissions(ctx, page, workspace, slug); err != nil {
+			return userIDs, errors.Wrap(err, "list users for repo")
+		}
+		if !page.HasMore() {
+			page = nil
+		}
+
+		for _, perm := range repoPerms {
+			if perm.User != nil {
+				userIDs = append(userIDs, extsvc.AccountID(perm.User.UUID))
+			}
+		}
+	}
+	return userIDs, nil
+}
+
+// splitFullName splits the full name of a repository ("workspace/repo_slug")
+// into the workspace and repository slugs.
+func splitFullName(fullName string) (workspace, slug string, ok bool) {
+	i := strings.Index(fullName, "/")
+	if i <= 0 || i == len(fullName)-1 || strings.Contains(fullName[i+1:], "/") {
+		return "", "", false
+	}
+	return fullName[:i], fullName[i+1:], true
+}
@@ -0,0 +1,199 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+)
+
+// newTestProvider returns a provider for the workspaces "alice" and "acme",
+// whose client talks to a fake Bitbucket Cloud API which serves the given
+// pages of permissions for each request path and query.
+func newTestProvider(t *testing.T, pages map[string][][]*bitbucketcloud.RepoPermission) *Provider {
+	t.Helper()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if q := r.URL.Query().Get("q"); q != "" {
+			key += "?q=" + q
+		}
+		perms, ok := pages[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		page := 0
+		if p := r.URL.Query().Get("page"); p == "2" {
+			page = 1
+		}
+		resp := map[string]interface{}{
+			"page":   page + 1,
+			"values": perms[page],
+		}
+		if page+1 < len(perms) {
+			next, _ := url.Parse(srv.URL + r.URL.String())
+			qry := next.Query()
+			qry.Set("page", "2")
+			next.RawQuery = qry.Encode()
+			resp["next"] = next.String()
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	apiURL, _ := url.Parse(srv.URL)
+	client := bitbucketcloud.NewClient(apiURL, srv.Client())
+	baseURL, _ := url.Parse("https://bitbucket.org")
+	return NewProvider("", baseURL, client, []string{"alice", "acme"})
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p := newTestProvider(t, map[string][][]*bitbucketcloud.RepoPermission{
+		`/2.0/workspaces/alice/permissions/repositories?q=user.uuid="{alice}"`: {
+			{{Permission: "admin", Repository: &bitbucketcloud.Repo{UUID: "{repo-1}"}}},
+		},
+		`/2.0/workspaces/acme/permissions/repositories?q=user.uuid="{alice}"`: {
+			{
+				{Permission: "read", Repository: &bitbucketcloud.Repo{UUID: "{repo-2}"}},
+				{Permission: "write", Repository: &bitbucketcloud.Repo{UUID: "{repo-3}"}},
+			},
+			{
+				{Permission: "read", Repository: &bitbucketcloud.Repo{UUID: "{repo-1}"}},
+				{Permission: "read", Repository: &bitbucketcloud.Repo{UUID: "{repo-4}"}},
+			},
+		},
+	})
+
+	t.Run("nil account", func(t *testing.T) {
+		_, err := p.FetchUserPerms(context.Background(), nil, authz.FetchPermsOptions{})
+		want := "no account provided"
+		if err == nil || err.Error() != want {
+			t.Fatalf("err: want %q but got %v", want, err)
+		}
+	})
+
+	t.Run("not the code host of the account", func(t *testing.T) {
+		_, err := p.FetchUserPerms(context.Background(),
+			&extsvc.Account{
+				AccountSpec: extsvc.AccountSpec{
+					ServiceType: extsvc.TypeGitLab,
+					ServiceID:   "https://gitlab.com/",
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		want := `not a code host of the account: want "https://gitlab.com/" but have "https://bitbucket.org/"`
+		if err == nil || err.Error() != want {
+			t.Fatalf("err: want %q but got %v", want, err)
+		}
+	})
+
+	t.Run("all pages of all workspaces", func(t *testing.T) {
+		perms, err := p.FetchUserPerms(context.Background(),
+			&extsvc.Account{
+				AccountSpec: extsvc.AccountSpec{
+					ServiceType: extsvc.TypeBitbucketCloud,
+					ServiceID:   "https://bitbucket.org/",
+					AccountID:   "{alice}",
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []extsvc.RepoID{"{repo-1}", "{repo-2}", "{repo-3}", "{repo-4}"}
+		if diff := cmp.Diff(want, perms.Exacts); diff != "" {
+			t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	p := newTestProvider(t, map[string][][]*bitbucketcloud.RepoPermission{
+		"/2.0/workspaces/acme/permissions/repositories/app": {
+			{
+				{Permission: "admin", User: &bitbucketcloud.User{UUID: "{alice}"}},
+				{Permission: "read", User: &bitbucketcloud.User{UUID: "{bob}"}},
+			},
+			{
+				{Permission: "write", User: &bitbucketcloud.User{UUID: "{carol}"}},
+			},
+		},
+	})
+
+	t.Run("nil repository", func(t *testing.T) {
+		_, err := p.FetchRepoPerms(context.Background(), nil, authz.FetchPermsOptions{})
+		want := "no repository provided"
+		if err == nil || err.Error() != want {
+			t.Fatalf("err: want %q but got %v", want, err)
+		}
+	})
+
+	t.Run("not the code host of the repository", func(t *testing.T) {
+		_, err := p.FetchRepoPerms(context.Background(),
+			&extsvc.Repository{
+				URI: "gitlab.com/acme/app",
+				ExternalRepoSpec: api.ExternalRepoSpec{
+					ServiceType: extsvc.TypeGitLab,
+					ServiceID:   "https://gitlab.com/",
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		want := `not a code host of the repository: want "https://gitlab.com/" but have "https://bitbucket.org/"`
+		if err == nil || err.Error() != want {
+			t.Fatalf("err: want %q but got %v", want, err)
+		}
+	})
+
+	t.Run("all pages", func(t *testing.T) {
+		accountIDs, err := p.FetchRepoPerms(context.Background(),
+			&extsvc.Repository{
+				URI: "bitbucket.org/acme/app",
+				ExternalRepoSpec: api.ExternalRepoSpec{
+					ID:          "{repo-1}",
+					ServiceType: extsvc.TypeBitbucketCloud,
+					ServiceID:   "https://bitbucket.org/",
+				},
+			},
+			authz.FetchPermsOptions{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []extsvc.AccountID{"{alice}", "{bob}", "{carol}"}
+		if diff := cmp.Diff(want, accountIDs); diff != "" {
+			t.Fatalf("AccountIDs mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestSplitFullName(t *testing.T) {
+	for fullName, want := range map[string][2]string{
+		"acme/app":   {"acme", "app"},
+		"acme":       {},
+		"/app":       {},
+		"acme/":      {},
+		"acme/app/x": {},
+	} {
+		workspace, slug, ok := splitFullName(fullName)
+		if ok != (want[0] != "") || workspace != want[0] || slug != want[1] {
+			t.Errorf("splitFullName(%q): want %q but got %q, %q, %v", fullName, want, workspace, slug, ok)
+		}
+	}
+}
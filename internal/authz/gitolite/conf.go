@@ -0,0 +1,315 @@
+package gitolite
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// allGroup is the group of all repositories in "repo" lines, and of all users
+// in rules.
+const allGroup = "@all"
+
+// wildRepoUsers are the users of rules which stand for users of wild repos.
+// They are stored in the repositories rather than in the gitolite-admin
+// repository, so rules for them never match.
+var wildRepoUsers = map[string]bool{
+	"CREATOR": true,
+	"READERS": true,
+	"WRITERS": true,
+}
+
+// acl is the access control list of a Gitolite instance, as defined by the
+// gitolite.conf file in the gitolite-admin repository.
+//
+// See https://gitolite.com/gitolite/conf.html for the syntax of the file.
+type acl struct {
+	// groups maps the names of groups (including the "@" prefix) defined in
+	// the main configuration to their members, which are repositories, users or
+	// other groups.
+	groups map[string][]string
+	// blocks are the "repo" blocks in the order they appear in the file.
+	blocks []*repoBlock
+}
+
+// repoBlock is a "repo" line, and the rules and options which follow it.
+type repoBlock struct {
+	// repos are the names, patterns and groups of the repositories on the
+	// "repo" line.
+	repos []string
+	rules []rule
+	// denyRules is whether "option deny-rules = 1" is set, which makes deny
+	// rules apply to read access.
+	denyRules bool
+	// sub is the subconf the block is in, or nil if it is in the main
+	// configuration.
+	sub *subconf
+}
+
+// subconf is a file included with a "subconf" line, for delegated
+// administration. Its rules only apply to the repositories in the group of the
+// main configuration with the name of the subconf, and the groups it defines
+// are local to it.
+//
+// See https://gitolite.com/gitolite/deleg.html.
+type subconf struct {
+	// name is the name of the subconf, which is also the name of the group of
+	// its repositories without the "@" prefix.
+	name string
+	// groups are the groups defined in the subconf.
+	groups map[string][]string
+}
+
+// rule is an access rule, such as "RW+ = alice @devs".
+type rule struct {
+	perm  string
+	users []string
+	// sub is the subconf the rule is in, whose groups the users are looked up
+	// in, or nil if it is in the main configuration.
+	sub *subconf
+}
+
+// readFileFunc returns the content of the file at the given path in the
+// gitolite-admin repository.
+type readFileFunc func(path string) ([]byte, error)
+
+// globFunc returns the paths of the files in the gitolite-admin repository
+// which match the given pattern.
+type globFunc func(pattern string) ([]string, error)
+
+var (
+	// includeLine matches include and subconf lines, such as `include "foo.conf"`
+	// or `subconf foo = "foo.conf"`.
+	includeLine = lazyregexp.New(`^(include|subconf)\s+(?:(\S+)\s*=\s*)?["']?([^"']+)["']?$`)
+	// permPattern matches the permissions of rules.
+	permPattern = lazyregexp.New(`^(-|C|R|RW\+?C?D?M?)$`)
+	// repoNamePattern matches names of repositories which are not patterns. This
+	// is REPONAME_PATT of Gitolite.
+	repoNamePattern = lazyregexp.New(`^@?[0-9a-zA-Z][-0-9a-zA-Z._@/+]*$`)
+)
+
+// parseACL parses the gitolite.conf file at confPath, including the files
+// included by it.
+func parseACL(confPath string, readFile readFileFunc, glob globFunc) (*acl, error) {
+	a := &acl{groups: map[string][]string{}}
+	if err := a.parseFile(confPath, nil, readFile, glob, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseFile parses the configuration file with the given name. sub is the
+// subconf the file is in, or nil if it is part of the main configuration.
+func (a *acl) parseFile(name string, sub *subconf, readFile readFileFunc, glob globFunc, seen map[string]bool) error {
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	b, err := readFile(name)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", name)
+	}
+
+	var block *repoBlock
+	for _, line := range strings.Split(string(b), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if m := includeLine.FindStringSubmatch(line); m != nil {
+			// Included files are relative to the conf directory.
+			names, err := glob(path.Join("conf", m[3]))
+			if err != nil {
+				return errors.Wrapf(err, "%s %s", m[1], m[3])
+			}
+			for _, name := range names {
+				fileSub := sub
+				if m[1] == "subconf" && sub == nil {
+					// Subconfs are named after their files unless a name is
+					// given. Files included by subconfs, including nested
+					// subconfs, stay restricted to the outermost subconf.
+					subName := m[2]
+					if subName == "" {
+						subName = strings.TrimSuffix(path.Base(name), ".conf")
+					}
+					fileSub = &subconf{name: subName, groups: map[string][]string{}}
+				}
+				if err := a.parseFile(name, fileSub, readFile, glob, seen); err != nil {
+					return err
+				}
+			}
+			block = nil
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case fields[0] == "repo":
+			block = &repoBlock{repos: fields[1:], sub: sub}
+			a.blocks = append(a.blocks, block)
+
+		case strings.HasPrefix(fields[0], "@") && len(fields) >= 2 && fields[1] == "=":
+			// Groups defined in subconfs are local to them, so that they
+			// can't add repositories or users to the groups of the main
+			// configuration outside of the subconf.
+			groups := a.groups
+			if sub != nil {
+				groups = sub.groups
+			}
+			groups[fields[0]] = append(groups[fields[0]], fields[2:]...)
+
+		case fields[0] == "option" || fields[0] == "config":
+			if block != nil && fields[0] == "option" && len(fields) == 4 &&
+				fields[1] == "deny-rules" && fields[2] == "=" && fields[3] == "1" {
+				block.denyRules = true
+			}
+
+		case block != nil && permPattern.MatchString(fields[0]):
+			i := strings.Index(line, "=")
+			if i < 0 {
+				continue
+			}
+			block.rules = append(block.rules, rule{
+				perm:  fields[0],
+				users: strings.Fields(line[i+1:]),
+				sub:   sub,
+			})
+		}
+	}
+	return nil
+}
+
+// expand returns the names in names, with the groups among them replaced by
+// their members, recursively. The members of groups are those defined in the
+// main configuration and, if sub is not nil, those defined in the subconf.
+func (a *acl) expand(sub *subconf, names []string) map[string]bool {
+	expanded := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if expanded[name] {
+			return
+		}
+		expanded[name] = true
+		for _, member := range a.groups[name] {
+			visit(member)
+		}
+		if sub != nil {
+			for _, member := range sub.groups[name] {
+				visit(member)
+			}
+		}
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return expanded
+}
+
+// matchesRepo returns whether the block applies to the repository.
+func (a *acl) matchesRepo(b *repoBlock, repo string) bool {
+	// 🚨 SECURITY: The blocks of a subconf only apply to the repositories in
+	// the group of the subconf defined in the main configuration.
+	if b.sub != nil && !a.expand(nil, []string{"@" + b.sub.name})[repo] {
+		return false
+	}
+
+	for name := range a.expand(b.sub, b.repos) {
+		if name == repo || name == allGroup {
+			return true
+		}
+		if repoNamePattern.MatchString(name) {
+			continue
+		}
+		// Names which aren't valid repository names are patterns of the
+		// repositories created by users ("wild repos"), in which CREATOR
+		// stands for the name of the user.
+		pattern := strings.ReplaceAll(name, "CREATOR", "[^/]+")
+		if re, err := regexp.Compile("^(?:" + pattern + ")$"); err == nil && re.MatchString(repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// canRead returns whether the user can read the repository.
+func (a *acl) canRead(user, repo string) bool {
+	if wildRepoUsers[user] {
+		return false
+	}
+
+	var rules []rule
+	denyRules := false
+	for _, b := range a.blocks {
+		if a.matchesRepo(b, repo) {
+			rules = append(rules, b.rules...)
+			denyRules = denyRules || b.denyRules
+		}
+	}
+
+	for _, r := range rules {
+		if r.perm == "C" {
+			// Create permissions apply to patterns, not repositories.
+			continue
+		}
+		if r.perm == "-" && !denyRules {
+			// Deny rules only apply to writes unless deny-rules is set.
+			continue
+		}
+		users := a.expand(r.sub, r.users)
+		if !users[user] && !users[allGroup] {
+			continue
+		}
+		// With deny-rules set, the first rule which matches the user decides.
+		return r.perm != "-"
+	}
+	return false
+}
+
+// users returns the names of the users which appear in the rules and groups.
+// Users which are only given access through @all don't appear.
+func (a *acl) users() []string {
+	seen := map[string]bool{}
+	var users []string
+	add := func(sub *subconf, names []string) {
+		for name := range a.expand(sub, names) {
+			if strings.HasPrefix(name, "@") || wildRepoUsers[name] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			users = append(users, name)
+		}
+	}
+	for _, b := range a.blocks {
+		for _, r := range b.rules {
+			add(r.sub, r.users)
+		}
+	}
+	return users
+}
+
+// keyUser returns the name of the user of the public key at the given path in
+// the keydir of the gitolite-admin repository, such as "alice" for
+// "keydir/alice.pub" and "keydir/laptops/alice@laptop.pub". It returns an empty
+// string if the path isn't a public key.
+func keyUser(keyPath string) string {
+	name := path.Base(keyPath)
+	if !strings.HasSuffix(name, ".pub") {
+		return ""
+	}
+	name = strings.TrimSuffix(name, ".pub")
+	// A suffix "@location" which isn't the domain of an email address
+	// distinguishes the keys of the same user.
+	if i := strings.LastIndex(name, "@"); i > 0 && !strings.Contains(name[i+1:], ".") {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,233 @@
+package gitolite
+
+import (
+	"os"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func parseTestACL(t *testing.T, files map[string]string) *acl {
+	t.Helper()
+
+	readFile := func(name string) ([]byte, error) {
+		s, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(s), nil
+	}
+	glob := func(pattern string) ([]string, error) {
+		var matches []string
+		for name := range files {
+			if ok, _ := path.Match(pattern, name); ok {
+				matches = append(matches, name)
+			}
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	a, err := parseACL("conf/gitolite.conf", readFile, glob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestACL_CanRead(t *testing.T) {
+	a := parseTestACL(t, map[string]string{
+		"conf/gitolite.conf": `
+# Groups are cumulative and can be nested.
+@admins = alice
+@devs   = bob @admins
+@devs   = carol
+@apps   = web api
+
+repo gitolite-admin
+    RW+ = @admins
+
+repo @apps
+    RW+ = @devs
+    R   = dave
+
+repo web
+    -   = carol       # denies only writes without deny-rules
+    R   = erin
+
+repo public
+    R   = @all
+
+repo secret
+    option deny-rules = 1
+    -   = bob
+    R   = @devs
+
+repo CREATOR/..*
+    C   = @devs
+    RW+ = CREATOR
+    R   = frank
+
+include "projects/*.conf"
+`,
+		"conf/projects/tools.conf": `
+repo tools
+    RW refs/heads/main = grace
+`,
+	})
+
+	tests := []struct {
+		user, repo string
+		want       bool
+	}{
+		{"alice", "gitolite-admin", true},
+		{"bob", "gitolite-admin", false},
+
+		{"alice", "web", true},
+		{"bob", "api", true},
+		{"carol", "web", true},
+		{"dave", "api", true},
+		{"erin", "web", true},
+		{"erin", "api", false},
+
+		{"anyone", "public", true},
+
+		{"alice", "secret", true},
+		{"bob", "secret", false},
+		{"carol", "secret", true},
+
+		{"frank", "bob/project", true},
+		{"grace", "bob/project", false},
+		{"frank", "other", false},
+		{"CREATOR", "bob/project", false},
+
+		{"grace", "tools", true},
+		{"alice", "tools", false},
+
+		{"alice", "unknown", false},
+	}
+	for _, test := range tests {
+		if got := a.canRead(test.user, test.repo); got != test.want {
+			t.Errorf("canRead(%q, %q): want %v but got %v", test.user, test.repo, test.want, got)
+		}
+	}
+}
+
+func TestACL_CanRead_Subconf(t *testing.T) {
+	a := parseTestACL(t, map[string]string{
+		"conf/gitolite.conf": `
+@frontend = web docs
+@backend  = api
+@secrets  = vault
+
+repo vault
+    RW+ = alice
+
+subconf "subs/*.conf"
+subconf ops = "delegated/operations.conf"
+`,
+		// The frontend admin may only grant access to @frontend.
+		"conf/subs/frontend.conf": `
+repo @frontend
+    R = bob
+
+# A repository outside of @frontend.
+repo api vault
+    R = bob
+
+# Local groups can't add repositories to the groups of the main configuration,
+# and extend them only within the subconf.
+@frontend = api
+@secrets  = carol
+repo @frontend
+    R = carol
+repo @secrets
+    R = carol
+
+repo @all
+    R = dave
+
+include "subs/frontend-extra.conf"
+`,
+		// Files included by a subconf are restricted to it too.
+		"conf/subs/frontend-extra.conf": `
+repo vault
+    R = erin
+`,
+		// Named subconfs are restricted to the group with their name, which
+		// doesn't exist.
+		"conf/delegated/operations.conf": `
+repo api
+    R = frank
+`,
+	})
+
+	tests := []struct {
+		user, repo string
+		want       bool
+	}{
+		{"alice", "vault", true},
+
+		{"bob", "web", true},
+		{"bob", "docs", true},
+		{"bob", "api", false},
+		{"bob", "vault", false},
+
+		{"carol", "web", true},
+		{"carol", "api", false},
+		{"carol", "vault", false},
+
+		{"dave", "docs", true},
+		{"dave", "api", false},
+		{"dave", "vault", false},
+
+		{"erin", "vault", false},
+
+		{"frank", "api", false},
+	}
+	for _, test := range tests {
+		if got := a.canRead(test.user, test.repo); got != test.want {
+			t.Errorf("canRead(%q, %q): want %v but got %v", test.user, test.repo, test.want, got)
+		}
+	}
+}
+
+func TestACL_Users(t *testing.T) {
+	a := parseTestACL(t, map[string]string{
+		"conf/gitolite.conf": `
+@devs = bob @admins
+@admins = alice
+@unused = zoe
+
+repo foo
+    RW+ = @devs
+    R   = carol @all
+
+repo CREATOR/..*
+    RW+ = CREATOR
+`,
+	})
+
+	got := a.users()
+	sort.Strings(got)
+	want := []string{"alice", "bob", "carol"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("users mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestKeyUser(t *testing.T) {
+	for keyPath, want := range map[string]string{
+		"keydir/alice.pub":                    "alice",
+		"keydir/laptops/alice@laptop.pub":     "alice",
+		"keydir/alice@example.com.pub":        "alice@example.com",
+		"keydir/alice@example.com@laptop.pub": "alice@example.com",
+		"keydir/README.md":                    "",
+	} {
+		if got := keyUser(keyPath); got != want {
+			t.Errorf("keyUser(%q): want %q but got %q", keyPath, want, got)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package gitolite
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+var _ authz.Provider = (*Provider)(nil)
+
+// adminRepo is the name of the Gitolite repository which contains the
+// configuration of the Gitolite instance.
+const adminRepo = "gitolite-admin"
+
+// maxConfFileSize is the maximum size of the configuration files read from
+// the gitolite-admin repository.
+const maxConfFileSize = 10 * 1024 * 1024
+
+// Provider implements authz.Provider for Gitolite repository permissions.
+//
+// The access rules are read from the gitolite-admin repository, which must be
+// mirrored from the Gitolite instance. Gitolite users are matched to
+// Sourcegraph users through an admin-configured mapping of Gitolite usernames
+// to verified email addresses, because Gitolite users have no other
+// attributes to match Sourcegraph users with.
+type Provider struct {
+	urn      string
+	codeHost *extsvc.CodeHost
+
+	// users maps Gitolite usernames to the verified email addresses of the
+	// Sourcegraph users they belong to.
+	users map[string]string
+
+	host string
+	// adminRepoName is the name of the gitolite-admin repository on
+	// Sourcegraph.
+	adminRepoName api.RepoName
+
+	lister gitoliteLister
+}
+
+type gitoliteLister interface {
+	ListGitolite(ctx context.Context, gitoliteHost string) ([]*gitolite.Repo, error)
+}
+
+// NewProvider returns a new Gitolite authorization provider for the Gitolite
+// instance at host, whose repositories are named with the given prefix on
+// Sourcegraph. users maps Gitolite usernames to the verified email addresses of
+// the Sourcegraph users they belong to. It uses our default gitserver client.
+func NewProvider(urn, host, prefix string, users map[string]string) *Provider {
+	return &Provider{
+		urn:   urn,
+		users: users,
+		codeHost: &extsvc.CodeHost{
+			ServiceID:   gitolite.ServiceID(host),
+			ServiceType: extsvc.TypeGitolite,
+		},
+		host:          host,
+		adminRepoName: reposource.GitoliteRepoName(prefix, adminRepo),
+		lister:        gitserver.DefaultClient,
+	}
+}
+
+// FetchAccount returns the account of the Gitolite user that is mapped to one
+// of the verified email addresses of the user. It returns nil if no Gitolite
+// user is mapped to them.
+//
+// 🚨 SECURITY: The Sourcegraph username is never used, because users may be
+// able to choose it.
+func (p *Provider) FetchAccount(_ context.Context, user *types.User, _ []*extsvc.Account, verifiedEmails []string) (*extsvc.Account, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	gitoliteUser := p.userByEmail(verifiedEmails)
+	if gitoliteUser == "" {
+		return nil, nil
+	}
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   gitoliteUser,
+		},
+	}, nil
+}
+
+// userByEmail returns the Gitolite username mapped to one of the given email
+// addresses, or an empty string if there is none. If several Gitolite users
+// are mapped to them, the first in alphabetical order is returned.
+func (p *Provider) userByEmail(emails []string) string {
+	isEmail := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		isEmail[strings.ToLower(email)] = true
+	}
+
+	var match string
+	for gitoliteUser, email := range p.users {
+		if isEmail[strings.ToLower(email)] && (match == "" || gitoliteUser < match) {
+			match = gitoliteUser
+		}
+	}
+	return match
+}
+
+// FetchUserPerms returns a list of repository names (on code host) that the
+// given account has read access on the code host. The repository name has the
+// same value as it would be used as api.ExternalRepoSpec.ID.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	if account == nil {
+		return nil, errors.New("no account provided")
+	} else if !extsvc.IsHostOfAccount(p.codeHost, account) {
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			account.AccountSpec.ServiceID, p.codeHost.ServiceID)
+	}
+
+	a, _, err := p.loadACL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := p.lister.ListGitolite(ctx, p.host)
+	if err != nil {
+		return nil, errors.Wrap(err, "list repositories")
+	}
+
+	perms := &authz.ExternalUserPermissions{}
+	for _, repo := range repos {
+		if a.canRead(account.AccountID, repo.Name) {
+			perms.Exacts = append(perms.Exacts, extsvc.RepoID(repo.Name))
+		}
+	}
+	return perms, nil
+}
+
+// FetchRepoPerms returns a list of usernames (on code host) who have read
+// access to the given repository on the code host. The username has the same
+// value as it would be used as extsvc.Account.AccountID. The users are those
+// named in the access rules and those with public keys in the gitolite-admin
+// repository.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	if repo == nil {
+		return nil, errors.New("no repository provided")
+	} else if !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec) {
+		return nil, errors.Errorf("not a code host of the repository: want %q but have %q",
+			repo.ServiceID, p.codeHost.ServiceID)
+	}
+
+	a, files, err := p.loadACL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := a.users()
+	seen := make(map[string]bool, len(users))
+	for _, user := range users {
+		seen[user] = true
+	}
+	for _, file := range files {
+		if !strings.HasPrefix(file, "keydir/") {
+			continue
+		}
+		if user := keyUser(file); user != "" && !seen[user] {
+			seen[user] = true
+			users = append(users, user)
+		}
+	}
+
+	var userIDs []extsvc.AccountID
+	for _, user := range users {
+		if a.canRead(user, repo.ID) {
+			userIDs = append(userIDs, extsvc.AccountID(user))
+		}
+	}
+	return userIDs, nil
+}
+
+// loadACL parses the gitolite.conf file at the HEAD of the gitolite-admin
+// repository. It also returns the paths of all files in the repository.
+func (p *Provider) loadACL(ctx context.Context) (*acl, []string, error) {
+	commit, err := git.ResolveRevision(ctx, p.adminRepoName, "HEAD", git.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "resolve HEAD of %s", p.adminRepoName)
+	}
+
+	infos, err := git.ReadDir(ctx, p.adminRepoName, commit, "", true)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "list files of %s", p.adminRepoName)
+	}
+	var files []string
+	for _, info := range infos {
+		if info.Mode()&fs.ModeType == 0 {
+			files = append(files, info.Name())
+		}
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return git.ReadFile(ctx, p.adminRepoName, commit, name, maxConfFileSize)
+	}
+	glob := func(pattern string) ([]string, error) {
+		var matches []string
+		for _, file := range files {
+			ok, err := path.Match(pattern, file)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matches = append(matches, file)
+			}
+		}
+		return matches, nil
+	}
+
+	a, err := parseACL("conf/gitolite.conf", readFile, glob)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "parse configuration of %s", p.adminRepoName)
+	}
+	return a, files, nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType
+}
+
+func (p *Provider) Validate() (problems []string) {
+	gitoliteUsers := make(map[string][]string, len(p.users))
+	for gitoliteUser, email := range p.users {
+		email = strings.ToLower(email)
+		gitoliteUsers[email] = append(gitoliteUsers[email], gitoliteUser)
+	}
+	for email, users := range gitoliteUsers {
+		if len(users) > 1 {
+			sort.Strings(users)
+			problems = append(problems, fmt.Sprintf("email %q is mapped to more than one Gitolite user: %s", email, strings.Join(users, ", ")))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
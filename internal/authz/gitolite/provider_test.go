@@ -0,0 +1,78 @@
+package gitolite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := NewProvider("", "git@gitolite.example.com", "gitolite.example.com/", map[string]string{
+		"alice": "alice@example.com",
+		"bob":   "Bob@example.com",
+	})
+
+	tests := []struct {
+		name           string
+		user           *types.User
+		verifiedEmails []string
+		wantAccountID  string
+	}{
+		{
+			name:           "mapped email",
+			user:           &types.User{ID: 1, Username: "alice"},
+			verifiedEmails: []string{"alice@example.com"},
+			wantAccountID:  "alice",
+		},
+		{
+			name:           "email with different case",
+			user:           &types.User{ID: 2, Username: "robert"},
+			verifiedEmails: []string{"other@example.com", "bob@example.com"},
+			wantAccountID:  "bob",
+		},
+		{
+			name:           "username of a Gitolite user without a mapped email",
+			user:           &types.User{ID: 3, Username: "alice"},
+			verifiedEmails: []string{"mallory@example.com"},
+		},
+		{
+			name: "no verified emails",
+			user: &types.User{ID: 4, Username: "bob"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account, err := p.FetchAccount(context.Background(), test.user, nil, test.verifiedEmails)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.wantAccountID == "" {
+				if account != nil {
+					t.Fatalf("want no account but got %+v", account)
+				}
+				return
+			}
+			if account == nil {
+				t.Fatal("want account but got none")
+			}
+			if account.UserID != test.user.ID || account.AccountID != test.wantAccountID {
+				t.Fatalf("want account %q of user %d but got %q of user %d", test.wantAccountID, test.user.ID, account.AccountID, account.UserID)
+			}
+		})
+	}
+}
+
+func TestProvider_Validate(t *testing.T) {
+	p := NewProvider("", "git@gitolite.example.com", "gitolite.example.com/", map[string]string{
+		"alice":  "alice@example.com",
+		"alice2": "Alice@example.com",
+		"bob":    "bob@example.com",
+	})
+	want := []string{`email "alice@example.com" is mapped to more than one Gitolite user: alice, alice2`}
+	if diff := cmp.Diff(want, p.Validate()); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
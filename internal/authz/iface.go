@@ -19,6 +19,11 @@ type ExternalUserPermissions struct {
 	Exacts          []extsvc.RepoID
 	IncludeContains []extsvc.RepoID
 	ExcludeContains []extsvc.RepoID
+
+	// SubRepoPermissions are the rules for paths within the repositories
+	// which the user can only partially access, keyed by repository ID (on
+	// code host). The repositories must also be in Exacts.
+	SubRepoPermissions map[extsvc.RepoID]*SubRepoPermissions
 }
 
 // FetchPermsOptions declares options when performing permissions sync.
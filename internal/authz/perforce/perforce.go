@@ -35,6 +35,10 @@ type Provider struct {
 
 	p4Execer p4Execer
 
+	// depots are the depots of the code host connection, which get sub-repo
+	// permissions when they are enabled.
+	depots []extsvc.RepoID
+
 	// NOTE: We do not need mutex because there is no concurrent access to these
 	// 	fields in the current implementation.
 	cachedAllUserEmails map[string]string   // username <-> email
@@ -49,7 +53,10 @@ type p4Execer interface {
 // host, user and password to talk to a Perforce Server that is the source of
 // truth for permissions. It assumes emails of Sourcegraph accounts match 1-1
 // with emails of Perforce Server users. It uses our default gitserver client.
-func NewProvider(urn, host, user, password string) *Provider {
+//
+// The depots are the depots synced as repositories, for which sub-repo
+// permissions are returned when they are enabled.
+func NewProvider(urn, host, user, password string, depots []extsvc.RepoID) *Provider {
 	baseURL, _ := url.Parse(host)
 	return &Provider{
 		urn:                urn,
@@ -58,6 +65,7 @@ func NewProvider(urn, host, user, password string) *Provider {
 		user:               user,
 		password:           password,
 		p4Execer:           gitserver.DefaultClient,
+		depots:             depots,
 		cachedGroupMembers: make(map[string][]string),
 	}
 }
@@ -174,7 +182,9 @@ func (p *Provider) canGrantReadAccess(level string) bool {
 }
 
 // FetchUserPerms returns a list of depot prefixes that the given user has
-// access to on the Perforce Server.
+// access to on the Perforce Server. When sub-repo permissions are enabled, it
+// also returns the depots which the user has access to parts of, and the rules
+// for the paths within them.
 func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
 	if account == nil {
 		return nil, errors.New("no account provided")
@@ -204,6 +214,7 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 	)
 
 	var includeContains, excludeContains []extsvc.RepoID
+	var rules []protectRule
 	scanner := bufio.NewScanner(rc)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -253,6 +264,7 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 			if !p.canRevokeReadAccess(level) {
 				continue
 			}
+			rules = append(rules, protectRule{exclude: true, match: depotMatch[1:]})
 
 			if strings.Contains(depotContains, wildcardMatchAll) ||
 				strings.Contains(depotContains, wildcardMatchDirectory) {
@@ -282,6 +294,7 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 			if !p.canGrantReadAccess(level) {
 				continue
 			}
+			rules = append(rules, protectRule{match: depotMatch})
 
 			includeContains = append(includeContains, extsvc.RepoID(depotContains))
 		}
@@ -295,12 +308,22 @@ func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account,
 		excludeContains[i] = extsvc.RepoID(string(exclude) + wildcardMatchAll)
 	}
 
-	// As per interface definition for this method, implementation should return
-	// partial but valid results even when something went wrong.
-	return &authz.ExternalUserPermissions{
+	perms := &authz.ExternalUserPermissions{
 		IncludeContains: includeContains,
 		ExcludeContains: excludeContains,
-	}, errors.Wrap(scanner.Err(), "scanner.Err")
+	}
+	if err = scanner.Err(); err != nil {
+		// As per interface definition for this method, implementation should return
+		// partial but valid results even when something went wrong.
+		return perms, errors.Wrap(err, "scanner.Err")
+	}
+
+	// Rules for paths within depots are only returned from complete results,
+	// because missing rules grant access to the whole depot.
+	if len(p.depots) > 0 && authz.SubRepoPermissionsEnabled() {
+		perms.Exacts, perms.SubRepoPermissions = subRepoPermissions(p.depots, rules)
+	}
+	return perms, nil
 }
 
 // getAllUserEmails returns a set of username <-> email pairs of all users in the Perforce server.
@@ -16,9 +16,11 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestProvider_FetchAccount(t *testing.T) {
@@ -86,7 +88,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("nil account", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(ctx, nil, authz.FetchPermsOptions{})
 		want := "no account provided"
 		got := fmt.Sprintf("%v", err)
@@ -96,7 +98,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	})
 
 	t.Run("not the code host of the account", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(context.Background(),
 			&extsvc.Account{
 				AccountSpec: extsvc.AccountSpec{
@@ -114,7 +116,7 @@ func TestProvider_FetchUserPerms(t *testing.T) {
 	})
 
 	t.Run("no user found in account data", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchUserPerms(ctx,
 			&extsvc.Account{
 				AccountSpec: extsvc.AccountSpec{
@@ -252,11 +254,101 @@ open user alice * -//Sourcegraph/*/Handbook/...                      ## sub-matc
 	}
 }
 
+func TestProvider_FetchUserPerms_SubRepoPermissions(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SubRepoPermissions: &schema.SubRepoPermissions{Enabled: true},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	accountData, err := jsoniter.Marshal(
+		perforce.AccountData{
+			Username: "alice",
+			Email:    "alice@example.com",
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	execer := p4ExecFunc(func(ctx context.Context, host, user, password string, args ...string) (io.ReadCloser, http.Header, error) {
+		data := `
+read user alice * //Sourcegraph/Engineering/...
+read user alice * -//Sourcegraph/Engineering/Backend/Credentials/...
+read user alice * //Sourcegraph/Handbook/Public/...
+read user alice * -//Sourcegraph/Handbook/Public/Drafts/...
+read user alice * //Sourcegraph/Handbook/Public/Drafts/...    ## exact match of a previous exclude
+read user alice * //Sourcegraph/*/Frontend/...
+read user alice * -//Sourcegraph/Engineering/.../secret.txt
+list user alice * //Sourcegraph/Security/...                  ## "list" can't grant read access
+read user alice * //Sourcegraph/Docs/...
+read user alice * //Other/...
+`
+		return io.NopCloser(strings.NewReader(data)), nil, nil
+	})
+
+	p := NewTestProvider("", "ssl:111.222.333.444:1666", "admin", "password", execer)
+	p.depots = []extsvc.RepoID{
+		"//Sourcegraph/Engineering/",
+		"//Sourcegraph/Handbook/",
+		"//Sourcegraph/Security/",
+		"//Sourcegraph/Docs/",
+		"//Sourcegraph/Marketing/Frontend/",
+		"//Archive/",
+	}
+	got, err := p.FetchUserPerms(context.Background(),
+		&extsvc.Account{
+			AccountSpec: extsvc.AccountSpec{
+				ServiceType: extsvc.TypePerforce,
+				ServiceID:   "ssl:111.222.333.444:1666",
+			},
+			AccountData: extsvc.AccountData{
+				Data: (*json.RawMessage)(&accountData),
+			},
+		},
+		authz.FetchPermsOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantExacts := []extsvc.RepoID{
+		"//Sourcegraph/Engineering/",
+		"//Sourcegraph/Handbook/",
+		"//Sourcegraph/Security/",
+		"//Sourcegraph/Docs/",
+		"//Sourcegraph/Marketing/Frontend/",
+	}
+	if diff := cmp.Diff(wantExacts, got.Exacts); diff != "" {
+		t.Fatalf("Exacts mismatch (-want +got):\n%s", diff)
+	}
+
+	wantSubRepoPerms := map[extsvc.RepoID]*authz.SubRepoPermissions{
+		"//Sourcegraph/Engineering/": {
+			PathIncludes: []string{"/**", "/Frontend/**"},
+			PathExcludes: []string{"/Backend/Credentials/**", "/**/secret.txt"},
+		},
+		"//Sourcegraph/Handbook/": {
+			PathIncludes: []string{"/Public/**", "/Public/Drafts/**", "/Frontend/**"},
+			PathExcludes: []string{},
+		},
+		"//Sourcegraph/Security/": {
+			PathIncludes: []string{"/Frontend/**"},
+		},
+	}
+	if diff := cmp.Diff(wantSubRepoPerms, got.SubRepoPermissions); diff != "" {
+		t.Fatalf("SubRepoPermissions mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestProvider_FetchRepoPerms(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("nil repository", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchRepoPerms(ctx, nil, authz.FetchPermsOptions{})
 		want := "no repository provided"
 		got := fmt.Sprintf("%v", err)
@@ -266,7 +358,7 @@ func TestProvider_FetchRepoPerms(t *testing.T) {
 	})
 
 	t.Run("not the code host of the repository", func(t *testing.T) {
-		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password")
+		p := NewProvider("", "ssl:111.222.333.444:1666", "admin", "password", nil)
 		_, err := p.FetchRepoPerms(ctx,
 			&extsvc.Repository{
 				URI: "gitlab.com/user/repo",
@@ -395,7 +487,7 @@ func TestScanAllUsers(t *testing.T) {
 }
 
 func NewTestProvider(urn, host, user, password string, execer p4Execer) *Provider {
-	p := NewProvider(urn, host, user, password)
+	p := NewProvider(urn, host, user, password, nil)
 	p.p4Execer = execer
 	return p
 }
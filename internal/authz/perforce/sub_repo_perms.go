@@ -0,0 +1,130 @@
+package perforce
+
+import (
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+// protectRule is a line of the output of "p4 protects" which grants or revokes
+// read access, such as "read user alice * //Sourcegraph/dir/...".
+type protectRule struct {
+	// exclude is whether the rule revokes access.
+	exclude bool
+	// match is the depot path pattern of the rule without the "-" of
+	// exclusions, e.g. //Sourcegraph/*/dir/...
+	match string
+}
+
+// subRepoPermissions returns the depots which the rules grant access to, at
+// least in part, and the rules for the paths within the depots which the user
+// can't access entirely.
+//
+// Perforce protections can have conflicting rules and the later one wins. The
+// returned rules only approximate this: an include of exactly an earlier
+// exclude takes it out, but otherwise excludes take precedence over includes.
+func subRepoPermissions(depots []extsvc.RepoID, rules []protectRule) ([]extsvc.RepoID, map[extsvc.RepoID]*authz.SubRepoPermissions) {
+	exacts := []extsvc.RepoID{}
+	perms := make(map[extsvc.RepoID]*authz.SubRepoPermissions)
+	for _, depot := range depots {
+		var includes, excludes []string
+		for _, r := range rules {
+			rel, ok := relativeDepotRule(string(depot), r.match)
+			if !ok {
+				continue
+			}
+
+			if r.exclude {
+				excludes = append(excludes, rel)
+				continue
+			}
+			for i := len(excludes) - 1; i >= 0; i-- {
+				if excludes[i] == rel {
+					excludes = append(excludes[:i], excludes[i+1:]...)
+				}
+			}
+			includes = append(includes, rel)
+		}
+
+		if len(includes) == 0 || containsString(excludes, "/**") {
+			continue
+		}
+		exacts = append(exacts, depot)
+
+		if len(excludes) == 0 && containsString(includes, "/**") {
+			// The user can access the whole depot.
+			continue
+		}
+		perms[depot] = &authz.SubRepoPermissions{
+			PathIncludes: includes,
+			PathExcludes: excludes,
+		}
+	}
+	return exacts, perms
+}
+
+// relativeDepotRule returns the glob pattern of the paths within the depot
+// which the depot path pattern matches, relative to the root of the depot. It
+// returns false if the pattern matches no path within the depot.
+//
+// For example, the pattern "//Sourcegraph/*/dir/..." matches "/dir/**" within
+// the depot "//Sourcegraph/Engineering/".
+func relativeDepotRule(depot, match string) (string, bool) {
+	if !strings.HasPrefix(match, "//") {
+		return "", false
+	}
+
+	depotSegments := strings.Split(strings.Trim(depot, "/"), "/")
+	matchSegments := strings.Split(strings.TrimPrefix(match, "//"), "/")
+	for i, depotSegment := range depotSegments {
+		if i >= len(matchSegments) {
+			return "", false
+		}
+		matchSegment := matchSegments[i]
+
+		// '...' matches across directories, so the rest of the pattern can
+		// match anywhere within the depot once its start matches.
+		if j := strings.Index(matchSegment, "..."); j >= 0 {
+			if ok, _ := path.Match(escapeMatch(matchSegment[:j])+"*", depotSegment); !ok {
+				return "", false
+			}
+			rest := append([]string{matchSegment[j:]}, matchSegments[i+1:]...)
+			return "/" + toGlob(strings.Join(rest, "/")), true
+		}
+
+		// '*' matches within one directory, like it does for path.Match.
+		if ok, _ := path.Match(escapeMatch(matchSegment), depotSegment); !ok {
+			return "", false
+		}
+	}
+
+	rest := strings.Join(matchSegments[len(depotSegments):], "/")
+	if rest == "" {
+		return "", false
+	}
+	return "/" + toGlob(rest), true
+}
+
+// escapeMatch escapes the characters of a Perforce path pattern which are
+// special to path.Match, except for '*'.
+func escapeMatch(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `[`, `\[`, `?`, `\?`).Replace(s)
+}
+
+// toGlob converts a Perforce path pattern to a glob pattern of
+// authz.SubRepoPermissions.
+func toGlob(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `{`, `\{`, `}`, `\}`, `?`, `\?`).Replace(s)
+	return strings.ReplaceAll(s, "...", "**")
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
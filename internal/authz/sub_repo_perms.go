@@ -0,0 +1,322 @@
+package authz
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+	"github.com/golang/groupcache/lru"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// SubRepoPermissions denotes access control rules within a repository's
+// contents.
+//
+// Rules are expressed as glob patterns of paths relative to the root of the
+// repository with a leading "/", such as "/dir/**". A path can be read if it is
+// matched by one of PathIncludes and by none of PathExcludes.
+type SubRepoPermissions struct {
+	PathIncludes []string
+	PathExcludes []string
+}
+
+// RepoContent specifies data existing in a repo. It currently only supports
+// paths but will be extended in future to support other pieces of metadata, for
+// example branch.
+type RepoContent struct {
+	Repo api.RepoName
+	// Path is the path of a file relative to the root of the repository. Paths
+	// of directories end with a "/".
+	Path string
+}
+
+// SubRepoPermissionChecker is the interface exposed by the SubRepoPermsClient
+// and is exposed to allow consumers to mock out the client.
+type SubRepoPermissionChecker interface {
+	// Permissions returns the level of access the provided user has for the
+	// requested content.
+	//
+	// If the userID represents an anonymous user, ErrUnauthenticated is returned.
+	Permissions(ctx context.Context, userID int32, content RepoContent) (Perms, error)
+
+	// Enabled indicates whether sub-repo permissions are enabled.
+	Enabled() bool
+
+	// EnabledForRepo indicates whether the user has sub-repo permissions rules
+	// for the repository, in which case some of its paths may not be readable.
+	EnabledForRepo(ctx context.Context, userID int32, repo api.RepoName) (bool, error)
+}
+
+// DefaultSubRepoPermsChecker is the default checker of sub-repo permissions. It
+// allows access to all paths until it is set to a SubRepoPermsClient by the
+// enterprise frontend.
+var DefaultSubRepoPermsChecker SubRepoPermissionChecker = &noopPermsChecker{}
+
+type noopPermsChecker struct{}
+
+func (*noopPermsChecker) Permissions(ctx context.Context, userID int32, content RepoContent) (Perms, error) {
+	return Read, nil
+}
+
+func (*noopPermsChecker) Enabled() bool {
+	return false
+}
+
+func (*noopPermsChecker) EnabledForRepo(ctx context.Context, userID int32, repo api.RepoName) (bool, error) {
+	return false, nil
+}
+
+// SubRepoPermissionsGetter allows getting sub-repo permissions of users.
+type SubRepoPermissionsGetter interface {
+	// GetByUser returns the sub-repo permissions rules of the user, keyed by
+	// the name of the repository they apply to.
+	GetByUser(ctx context.Context, userID int32) (map[api.RepoName]SubRepoPermissions, error)
+}
+
+// ErrUnauthenticated is returned when checking sub-repo permissions of an
+// anonymous user.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+const (
+	// subRepoPermsCacheSize is the number of users whose rules are cached.
+	subRepoPermsCacheSize = 1000
+	// subRepoPermsCacheTTL is how long the rules of a user are cached for, so
+	// that rules synced from code hosts are enforced soon after.
+	subRepoPermsCacheTTL = 10 * time.Second
+)
+
+// SubRepoPermsClient is responsible for checking whether a user has access to
+// data within a repo. Sub-repository permissions enforcement is on top of
+// existing repository permissions, which means the user must already have
+// access to the repository itself. The intention is for this client to be
+// created once at startup and passed in to all places that need to check sub
+// repo permissions.
+//
+// Note that sub-repo permissions are currently opt-in via the
+// experimentalFeatures.subRepoPermissions.enabled setting.
+type SubRepoPermsClient struct {
+	getter SubRepoPermissionsGetter
+	clock  func() time.Time
+
+	mu    sync.Mutex
+	cache *lru.Cache // userID -> *cachedRules
+}
+
+// NewSubRepoPermsClient instantiates a new client which gets the rules of
+// users from getter.
+func NewSubRepoPermsClient(getter SubRepoPermissionsGetter) *SubRepoPermsClient {
+	return &SubRepoPermsClient{
+		getter: getter,
+		clock:  time.Now,
+		cache:  lru.New(subRepoPermsCacheSize),
+	}
+}
+
+// cachedRules are the compiled rules of a user.
+type cachedRules struct {
+	rules     map[api.RepoName]*compiledRules
+	timestamp time.Time
+}
+
+// compiledRules are the compiled rules of a user for a repository.
+type compiledRules struct {
+	includes []glob.Glob
+	excludes []glob.Glob
+	// includePrefixes are the literal prefixes of the include patterns, which
+	// are used to allow access to the parent directories of included paths.
+	includePrefixes []string
+}
+
+// Permissions implements SubRepoPermissionChecker.
+func (s *SubRepoPermsClient) Permissions(ctx context.Context, userID int32, content RepoContent) (Perms, error) {
+	if !s.Enabled() {
+		return Read, nil
+	}
+	if userID == 0 {
+		return None, ErrUnauthenticated
+	}
+
+	rules, err := s.getCompiledRules(ctx, userID)
+	if err != nil {
+		return None, errors.Wrap(err, "get compiled rules")
+	}
+	r, ok := rules[content.Repo]
+	if !ok {
+		// No rules for the repository means that the user has access to all
+		// of it, given that they have access to the repository itself.
+		return Read, nil
+	}
+	return r.perms(content.Path), nil
+}
+
+// Enabled implements SubRepoPermissionChecker.
+func (s *SubRepoPermsClient) Enabled() bool {
+	return SubRepoPermissionsEnabled()
+}
+
+// SubRepoPermissionsEnabled returns whether sub-repo permissions are enabled
+// in the site configuration.
+func SubRepoPermissionsEnabled() bool {
+	if c := conf.Get().ExperimentalFeatures; c != nil && c.SubRepoPermissions != nil {
+		return c.SubRepoPermissions.Enabled
+	}
+	return false
+}
+
+// EnabledForRepo implements SubRepoPermissionChecker.
+func (s *SubRepoPermsClient) EnabledForRepo(ctx context.Context, userID int32, repo api.RepoName) (bool, error) {
+	if !s.Enabled() {
+		return false, nil
+	}
+	if userID == 0 {
+		return false, ErrUnauthenticated
+	}
+
+	rules, err := s.getCompiledRules(ctx, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "get compiled rules")
+	}
+	_, ok := rules[repo]
+	return ok, nil
+}
+
+func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32) (map[api.RepoName]*compiledRules, error) {
+	s.mu.Lock()
+	v, ok := s.cache.Get(userID)
+	s.mu.Unlock()
+	if ok {
+		if cached := v.(*cachedRules); s.clock().Sub(cached.timestamp) < subRepoPermsCacheTTL {
+			return cached.rules, nil
+		}
+	}
+
+	perms, err := s.getter.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching rules")
+	}
+	rules := make(map[api.RepoName]*compiledRules, len(perms))
+	for repo, p := range perms {
+		r, err := compileSubRepoPermissions(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling rules of %q", repo)
+		}
+		rules[repo] = r
+	}
+
+	s.mu.Lock()
+	s.cache.Add(userID, &cachedRules{rules: rules, timestamp: s.clock()})
+	s.mu.Unlock()
+	return rules, nil
+}
+
+func compileSubRepoPermissions(p SubRepoPermissions) (*compiledRules, error) {
+	r := &compiledRules{}
+	for _, pattern := range p.PathIncludes {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling include pattern %q", pattern)
+		}
+		r.includes = append(r.includes, g)
+		r.includePrefixes = append(r.includePrefixes, globLiteralPrefix(pattern))
+	}
+	for _, pattern := range p.PathExcludes {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "compiling exclude pattern %q", pattern)
+		}
+		r.excludes = append(r.excludes, g)
+	}
+	return r, nil
+}
+
+// globLiteralPrefix returns the part of the pattern before its first special
+// character.
+func globLiteralPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[{\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// perms returns the permissions for the path. Directories, whose paths end
+// with a "/", can be read if they contain paths which can be read, so that
+// these can be navigated to.
+func (r *compiledRules) perms(path string) Perms {
+	path = "/" + strings.TrimPrefix(path, "/")
+	if path == "/" {
+		return Read
+	}
+
+	for _, g := range r.excludes {
+		if g.Match(path) {
+			return None
+		}
+	}
+	for _, g := range r.includes {
+		if g.Match(path) {
+			return Read
+		}
+	}
+	if strings.HasSuffix(path, "/") {
+		for _, prefix := range r.includePrefixes {
+			if strings.HasPrefix(prefix, path) {
+				return Read
+			}
+		}
+	}
+	return None
+}
+
+// ActorPermissions returns the level of access the given actor has for the
+// requested content.
+//
+// Internal actors are allowed access to all content, and so are anonymous
+// actors, which can only access public repositories.
+func ActorPermissions(ctx context.Context, s SubRepoPermissionChecker, a *actor.Actor, content RepoContent) (Perms, error) {
+	if !s.Enabled() || a.IsInternal() || !a.IsAuthenticated() {
+		return Read, nil
+	}
+
+	perms, err := s.Permissions(ctx, a.UID, content)
+	if err != nil {
+		return None, errors.Wrapf(err, "getting actor permissions for actor: %d", a.UID)
+	}
+	return perms, nil
+}
+
+// ActorEnabledForRepo returns whether the actor has sub-repo permissions rules
+// for the repository.
+func ActorEnabledForRepo(ctx context.Context, s SubRepoPermissionChecker, a *actor.Actor, repo api.RepoName) (bool, error) {
+	if !s.Enabled() || a.IsInternal() || !a.IsAuthenticated() {
+		return false, nil
+	}
+	return s.EnabledForRepo(ctx, a.UID, repo)
+}
+
+// FilterActorPaths returns the paths of the repository which the actor can
+// read.
+func FilterActorPaths(ctx context.Context, s SubRepoPermissionChecker, a *actor.Actor, repo api.RepoName, paths []string) ([]string, error) {
+	if enabled, err := ActorEnabledForRepo(ctx, s, a, repo); err != nil {
+		return nil, err
+	} else if !enabled {
+		return paths, nil
+	}
+
+	filtered := make([]string, 0, len(paths))
+	for _, p := range paths {
+		perms, err := s.Permissions(ctx, a.UID, RepoContent{Repo: repo, Path: p})
+		if err != nil {
+			return nil, errors.Wrap(err, "checking sub-repo permissions")
+		}
+		if perms.Include(Read) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,118 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type mockSubRepoPermsGetter map[api.RepoName]SubRepoPermissions
+
+func (m mockSubRepoPermsGetter) GetByUser(ctx context.Context, userID int32) (map[api.RepoName]SubRepoPermissions, error) {
+	return m, nil
+}
+
+func mockSubRepoPermsEnabled(t *testing.T, enabled bool) {
+	t.Helper()
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SubRepoPermissions: &schema.SubRepoPermissions{Enabled: enabled},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
+
+func TestSubRepoPermsClient_Permissions(t *testing.T) {
+	client := NewSubRepoPermsClient(mockSubRepoPermsGetter{
+		"monorepo": {
+			PathIncludes: []string{"/docs/**", "/src/public/**", "/*.md"},
+			PathExcludes: []string{"/docs/internal/**"},
+		},
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockSubRepoPermsEnabled(t, false)
+
+		perms, err := client.Permissions(context.Background(), 1, RepoContent{Repo: "monorepo", Path: "src/secret/main.go"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms != Read {
+			t.Fatalf("want %v but got %v", Read, perms)
+		}
+	})
+
+	mockSubRepoPermsEnabled(t, true)
+
+	if _, err := client.Permissions(context.Background(), 0, RepoContent{Repo: "monorepo", Path: "README.md"}); err != ErrUnauthenticated {
+		t.Fatalf("err: want %v but got %v", ErrUnauthenticated, err)
+	}
+
+	for _, tc := range []struct {
+		repo api.RepoName
+		path string
+		want Perms
+	}{
+		{"monorepo", "", Read},
+		{"monorepo", "README.md", Read},
+		{"monorepo", "docs/index.md", Read},
+		{"monorepo", "docs/internal/plan.md", None},
+		{"monorepo", "docs/internal/", None},
+		{"monorepo", "src/", Read},
+		{"monorepo", "src/public/", Read},
+		{"monorepo", "src/public/main.go", Read},
+		{"monorepo", "src/secret/", None},
+		{"monorepo", "src/secret/main.go", None},
+		{"monorepo", "src/README.md", None},
+		{"other", "src/secret/main.go", Read},
+	} {
+		perms, err := client.Permissions(context.Background(), 1, RepoContent{Repo: tc.repo, Path: tc.path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms != tc.want {
+			t.Errorf("Permissions(%q, %q): want %v but got %v", tc.repo, tc.path, tc.want, perms)
+		}
+	}
+}
+
+func TestFilterActorPaths(t *testing.T) {
+	mockSubRepoPermsEnabled(t, true)
+
+	client := NewSubRepoPermsClient(mockSubRepoPermsGetter{
+		"monorepo": {PathIncludes: []string{"/public/**"}},
+	})
+	paths := []string{"public/a.go", "private/b.go"}
+
+	for _, tc := range []struct {
+		name  string
+		actor *actor.Actor
+		repo  api.RepoName
+		want  []string
+	}{
+		{"user", actor.FromUser(1), "monorepo", []string{"public/a.go"}},
+		{"repo without rules", actor.FromUser(1), "other", paths},
+		{"internal actor", &actor.Actor{Internal: true}, "monorepo", paths},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FilterActorPaths(context.Background(), client, tc.actor, tc.repo, paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("want %q but got %q", tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("want %q but got %q", tc.want, got)
+				}
+			}
+		})
+	}
+}
@@ -321,6 +321,21 @@ func AndOrQueryEnabled() bool {
 	return val == "enabled"
 }
 
+// GitServerReplicas returns the "experimentalFeatures.gitServerReplicas" site
+// config value. Replicas is 0 if hot repositories are not replicated, and
+// defaults to 2 otherwise.
+func GitServerReplicas() schema.GitServerReplicas {
+	val := ExperimentalFeatures().GitServerReplicas
+	if val == nil {
+		return schema.GitServerReplicas{}
+	}
+	replicas := *val
+	if replicas.Replicas == 0 {
+		replicas.Replicas = 2
+	}
+	return replicas
+}
+
 func ExperimentalFeatures() schema.ExperimentalFeatures {
 	val := Get().ExperimentalFeatures
 	if val == nil {
@@ -46,6 +46,7 @@ type ExternalServiceStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	BitbucketCloudValidators  []func(*schema.BitbucketCloudConnection) error
 	PerforceValidators        []func(*schema.PerforceConnection) error
 
 	key encryption.Key
@@ -60,6 +61,7 @@ func (e *ExternalServiceStore) copy() *ExternalServiceStore {
 		GitHubValidators:          e.GitHubValidators,
 		GitLabValidators:          e.GitLabValidators,
 		BitbucketServerValidators: e.BitbucketServerValidators,
+		BitbucketCloudValidators:  e.BitbucketCloudValidators,
 		PerforceValidators:        e.PerforceValidators,
 	}
 }
@@ -125,8 +127,10 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
 	extsvc.KindJVMPackages:     {CodeHost: true, JSONSchema: schema.JVMPackagesSchemaJSON},
+	extsvc.KindMercurial:       {CodeHost: true, JSONSchema: schema.MercurialSchemaJSON},
 	extsvc.KindPerforce:        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 	extsvc.KindPhabricator:     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
+	extsvc.KindSubversion:      {CodeHost: true, JSONSchema: schema.SubversionSchemaJSON},
 	extsvc.KindOther:           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
 
@@ -403,7 +407,14 @@ func (e *ExternalServiceStore) validateBitbucketServerConnection(ctx context.Con
 }
 
 func (e *ExternalServiceStore) validateBitbucketCloudConnection(ctx context.Context, id int64, c *schema.BitbucketCloudConnection) error {
-	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
+	err := new(multierror.Error)
+	for _, validate := range e.BitbucketCloudValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c))
+
+	return err.ErrorOrNil()
 }
 
 func (e *ExternalServiceStore) validatePerforceConnection(ctx context.Context, id int64, c *schema.PerforceConnection) error {
@@ -44,13 +44,14 @@ func (s *GitserverRepoStore) Transact(ctx context.Context) (*GitserverRepoStore,
 func (s *GitserverRepoStore) Upsert(ctx context.Context, repos ...*types.GitserverRepo) error {
 	values := make([]*sqlf.Query, 0, len(repos))
 	for _, gr := range repos {
-		q := sqlf.Sprintf("(%s, %s, %s, %s, %s, %s, now())",
+		q := sqlf.Sprintf("(%s, %s, %s, %s, %s, %s, %s, now())",
 			gr.RepoID,
 			gr.CloneStatus,
 			dbutil.NewNullString(gr.ShardID),
 			dbutil.NewNullInt64(gr.LastExternalService),
 			dbutil.NewNullString(sanitizeToUTF8(gr.LastError)),
 			gr.LastFetched,
+			dbutil.NewNullString(gr.RebalancingFrom),
 		)
 
 		values = append(values, q)
@@ -59,11 +60,11 @@ func (s *GitserverRepoStore) Upsert(ctx context.Context, repos ...*types.Gitserv
 	err := s.Exec(ctx, sqlf.Sprintf(`
 -- source: internal/database/gitserver_repos.go:GitserverRepoStore.Upsert
 INSERT INTO
-    gitserver_repos(repo_id, clone_status, shard_id, last_external_service, last_error, last_fetched, updated_at)
+    gitserver_repos(repo_id, clone_status, shard_id, last_external_service, last_error, last_fetched, rebalancing_from, updated_at)
     VALUES %s
     ON CONFLICT (repo_id) DO UPDATE
-    SET (clone_status, shard_id, last_external_service, last_error, last_fetched, updated_at) =
-        (EXCLUDED.clone_status, EXCLUDED.shard_id, EXCLUDED.last_external_service, EXCLUDED.last_error, EXCLUDED.last_fetched, now())
+    SET (clone_status, shard_id, last_external_service, last_error, last_fetched, rebalancing_from, updated_at) =
+        (EXCLUDED.clone_status, EXCLUDED.shard_id, EXCLUDED.last_external_service, EXCLUDED.last_error, EXCLUDED.last_fetched, EXCLUDED.rebalancing_from, now())
 `, sqlf.Join(values, ",")))
 
 	return errors.Wrap(err, "creating GitserverRepo")
@@ -92,7 +93,8 @@ SELECT repo.id,
        gr.last_external_service,
        gr.last_error,
        gr.last_fetched,
-       gr.updated_at
+       gr.updated_at,
+       gr.rebalancing_from
 FROM repo
     LEFT JOIN gitserver_repos gr ON gr.repo_id = repo.id
     WHERE repo.deleted_at IS NULL
@@ -121,6 +123,7 @@ FROM repo
 			&dbutil.NullString{S: &gr.LastError},
 			&dbutil.NullTime{Time: &gr.LastFetched},
 			&dbutil.NullTime{Time: &gr.UpdatedAt},
+			&dbutil.NullString{S: &gr.RebalancingFrom},
 		); err != nil {
 			return errors.Wrap(err, "scanning row")
 		}
@@ -157,12 +160,37 @@ SELECT
        last_external_service,
        last_error,
        last_fetched,
-       updated_at
+       updated_at,
+       rebalancing_from
 FROM gitserver_repos
 WHERE repo_id = %s
 `
 
-	row := s.QueryRow(ctx, sqlf.Sprintf(q, id))
+	return scanGitserverRepo(s.QueryRow(ctx, sqlf.Sprintf(q, id)))
+}
+
+// GetByName returns the GitserverRepo of the repo with the given name.
+func (s *GitserverRepoStore) GetByName(ctx context.Context, name api.RepoName) (*types.GitserverRepo, error) {
+	q := `
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.GetByName
+SELECT
+       gr.repo_id,
+       gr.clone_status,
+       gr.shard_id,
+       gr.last_external_service,
+       gr.last_error,
+       gr.last_fetched,
+       gr.updated_at,
+       gr.rebalancing_from
+FROM gitserver_repos gr
+JOIN repo ON repo.id = gr.repo_id
+WHERE repo.name = %s
+`
+
+	return scanGitserverRepo(s.QueryRow(ctx, sqlf.Sprintf(q, name)))
+}
+
+func scanGitserverRepo(row *sql.Row) (*types.GitserverRepo, error) {
 	if row.Err() != nil {
 		return nil, errors.Wrap(row.Err(), "getting GitserverRepo")
 	}
@@ -176,6 +204,7 @@ WHERE repo_id = %s
 		&dbutil.NullString{S: &gr.LastError},
 		&dbutil.NullTime{Time: &gr.LastFetched},
 		&gr.UpdatedAt,
+		&dbutil.NullString{S: &gr.RebalancingFrom},
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "scanning GitserverRepo")
@@ -241,6 +270,65 @@ SET (last_fetched, shard_id, updated_at) =
 	return errors.Wrap(err, "setting last fetched")
 }
 
+// StartRebalancing records that the repo with the given name is being moved from
+// the shard fromShardID to the shard toShardID. Until FinishRebalancing is called,
+// requests for the repo are routed to fromShardID.
+func (s *GitserverRepoStore) StartRebalancing(ctx context.Context, name api.RepoName, fromShardID, toShardID string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.StartRebalancing
+UPDATE gitserver_repos
+SET (shard_id, rebalancing_from, updated_at) = (%s, %s, now())
+FROM repo
+WHERE repo.id = gitserver_repos.repo_id AND repo.name = %s
+`, toShardID, fromShardID, name))
+
+	return errors.Wrap(err, "starting rebalancing")
+}
+
+// FinishRebalancing records that the repo with the given name has been moved to the
+// given shard and is served by it.
+func (s *GitserverRepoStore) FinishRebalancing(ctx context.Context, name api.RepoName, shardID string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.FinishRebalancing
+UPDATE gitserver_repos
+SET (clone_status, shard_id, rebalancing_from, updated_at) = (%s, %s, NULL, now())
+FROM repo
+WHERE repo.id = gitserver_repos.repo_id AND repo.name = %s
+`, types.CloneStatusCloned, shardID, name))
+
+	return errors.Wrap(err, "finishing rebalancing")
+}
+
+// ListRebalancing returns the repos which are being moved between shards, mapped
+// to the shard which serves them until the move completes.
+func (s *GitserverRepoStore) ListRebalancing(ctx context.Context) (map[api.RepoName]string, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(`
+-- source: internal/database/gitserver_repos.go:GitserverRepoStore.ListRebalancing
+SELECT repo.name, gr.rebalancing_from
+FROM gitserver_repos gr
+JOIN repo ON repo.id = gr.repo_id
+WHERE gr.rebalancing_from IS NOT NULL AND repo.deleted_at IS NULL
+`))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing rebalancing repos")
+	}
+	defer rows.Close()
+
+	shards := map[api.RepoName]string{}
+	for rows.Next() {
+		var (
+			name    api.RepoName
+			shardID string
+		)
+		if err := rows.Scan(&name, &shardID); err != nil {
+			return nil, errors.Wrap(err, "scanning row")
+		}
+		shards[name] = shardID
+	}
+
+	return shards, errors.Wrap(rows.Err(), "iterating rows")
+}
+
 // sanitizeToUTF8 will remove any null character terminated string. The null character can be
 // represented in one of the following ways in Go:
 //
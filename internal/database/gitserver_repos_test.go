@@ -468,6 +468,75 @@ func TestGitserverRepoUpsert(t *testing.T) {
 	}
 }
 
+func TestGitserverRepoRebalancing(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	repo1 := &types.Repo{
+		Name: "github.com/sourcegraph/repo1",
+		URI:  "github.com/sourcegraph/repo1",
+	}
+	repo2 := &types.Repo{
+		Name: "github.com/sourcegraph/repo2",
+		URI:  "github.com/sourcegraph/repo2",
+	}
+	if err := Repos(db).Create(ctx, repo1, repo2); err != nil {
+		t.Fatal(err)
+	}
+
+	store := GitserverRepos(db)
+	if err := store.Upsert(ctx,
+		&types.GitserverRepo{RepoID: repo1.ID, ShardID: "gitserver-1", CloneStatus: types.CloneStatusCloned},
+		&types.GitserverRepo{RepoID: repo2.ID, ShardID: "gitserver-1", CloneStatus: types.CloneStatusCloned},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.StartRebalancing(ctx, repo1.Name, "gitserver-1", "gitserver-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	fromDB, err := store.GetByName(ctx, repo1.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromDB.ShardID != "gitserver-2" || fromDB.RebalancingFrom != "gitserver-1" {
+		t.Fatalf("unexpected shards. want=(%q, %q) have=(%q, %q)", "gitserver-2", "gitserver-1", fromDB.ShardID, fromDB.RebalancingFrom)
+	}
+
+	rebalancing, err := store.ListRebalancing(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[api.RepoName]string{repo1.Name: "gitserver-1"}, rebalancing); diff != "" {
+		t.Fatalf("unexpected rebalancing repos (-want +got):\n%s", diff)
+	}
+
+	if err := store.FinishRebalancing(ctx, repo1.Name, "gitserver-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	fromDB, err = store.GetByID(ctx, repo1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromDB.ShardID != "gitserver-2" || fromDB.RebalancingFrom != "" || fromDB.CloneStatus != types.CloneStatusCloned {
+		t.Fatalf("unexpected repo after rebalancing: %+v", fromDB)
+	}
+
+	rebalancing, err = store.ListRebalancing(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rebalancing) != 0 {
+		t.Fatalf("unexpected rebalancing repos: %v", rebalancing)
+	}
+}
+
 func TestSanitizeToUTF8(t *testing.T) {
 	testSet := map[string]string{
 		"test\x00":     "test",
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// ReferenceCountStore provides access to the lsif_reference_counts table, which stores the
+// number of precise code intelligence uploads from other repositories that reference the
+// definitions of a repository. These counts are calculated by the codeintel-ranking worker job.
+type ReferenceCountStore struct {
+	*basestore.Store
+}
+
+// ReferenceCounts instantiates and returns a new ReferenceCountStore.
+func ReferenceCounts(db dbutil.DB) *ReferenceCountStore {
+	return &ReferenceCountStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// GetPathCounts returns a map from document paths to the number of inbound references to the
+// definitions within that document. An empty map is returned if the reference counts of the given
+// repository have not been calculated.
+func (s *ReferenceCountStore) GetPathCounts(ctx context.Context, repoID api.RepoID) (map[string]int, error) {
+	var payload []byte
+	if err := s.QueryRow(ctx, sqlf.Sprintf(getPathCountsQuery, repoID)).Scan(&payload); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return map[string]int{}, nil
+		}
+		return nil, err
+	}
+
+	var counts map[string]int
+	if err := json.Unmarshal(payload, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+const getPathCountsQuery = `
+-- source: internal/database/reference_counts.go:GetPathCounts
+SELECT path_counts FROM lsif_reference_counts WHERE repository_id = %s
+`
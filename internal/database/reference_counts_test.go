@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestReferenceCountsGetPathCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	db := dbtest.NewDB(t, "")
+	ctx := context.Background()
+
+	repo1 := &types.Repo{Name: "github.com/sourcegraph/repo1"}
+	repo2 := &types.Repo{Name: "github.com/sourcegraph/repo2"}
+	if err := Repos(db).Create(ctx, repo1, repo2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO lsif_reference_counts (repository_id, path_counts)
+		VALUES ($1, '{"main.go": 3, "util/util.go": 1}')
+	`, repo1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := ReferenceCounts(db).GetPathCounts(ctx, repo1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]int{"main.go": 3, "util/util.go": 1}, counts); diff != "" {
+		t.Errorf("unexpected path counts (-want +got):\n%s", diff)
+	}
+
+	counts, err = ReferenceCounts(db).GetPathCounts(ctx, repo2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]int{}, counts); diff != "" {
+		t.Errorf("unexpected path counts (-want +got):\n%s", diff)
+	}
+}
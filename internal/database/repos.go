@@ -473,7 +473,7 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(perforce.Depot)
 	case extsvc.TypePhabricator:
 		r.Metadata = new(phabricator.Repo)
-	case extsvc.TypeOther:
+	case extsvc.TypeOther, extsvc.TypeMercurial, extsvc.TypeSubversion:
 		r.Metadata = new(extsvc.OtherRepoMetadata)
 	case extsvc.TypeJVMPackages:
 		r.Metadata = new(jvmpackages.Metadata)
@@ -50,6 +50,10 @@ type Client struct {
 	// The username and app password credentials for accessing the server.
 	Username, AppPassword string
 
+	// Token is the OAuth access token used for accessing the server instead of
+	// the username and app password, if set.
+	Token string
+
 	// RateLimit is the self-imposed rate limiter (since Bitbucket does not have a concept
 	// of rate limiting in HTTP response headers).
 	RateLimit *rate.Limiter
@@ -104,6 +108,71 @@ func (c *Client) Repos(ctx context.Context, pageToken *PageToken, accountName st
 	return repos, next, err
 }
 
+// CurrentUser returns the user that the client is authenticated as.
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	req, err := http.NewRequest("GET", "/2.0/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := c.do(ctx, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CurrentUserEmails returns a list of the email addresses of the user that the
+// client is authenticated as, according to the given pagination criteria. It
+// requires the "email" scope.
+func (c *Client) CurrentUserEmails(ctx context.Context, pageToken *PageToken) ([]*UserEmail, *PageToken, error) {
+	var emails []*UserEmail
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &emails)
+	} else {
+		next, err = c.page(ctx, "/2.0/user/emails", nil, pageToken, &emails)
+	}
+	return emails, next, err
+}
+
+// RepoPermissions returns a list of the permissions of users on the repository
+// with the given slug in the workspace, according to the given pagination
+// criteria. The permissions are the highest level of permission of each user,
+// whether it is granted directly or through a group. It requires the admin
+// permission on the workspace.
+func (c *Client) RepoPermissions(ctx context.Context, pageToken *PageToken, workspace, repoSlug string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		path := fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories/%s", url.PathEscape(workspace), url.PathEscape(repoSlug))
+		next, err = c.page(ctx, path, nil, pageToken, &perms)
+	}
+	return perms, next, err
+}
+
+// UserRepoPermissions returns a list of the permissions of the user with the
+// given UUID on the repositories of the workspace, according to the given
+// pagination criteria. Like RepoPermissions, it requires the admin permission
+// on the workspace.
+func (c *Client) UserRepoPermissions(ctx context.Context, pageToken *PageToken, workspace, userUUID string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	var next *PageToken
+	var err error
+	if pageToken.HasMore() {
+		next, err = c.reqPage(ctx, pageToken.Next, &perms)
+	} else {
+		path := fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories", url.PathEscape(workspace))
+		qry := url.Values{"q": []string{fmt.Sprintf("user.uuid=%q", userUUID)}}
+		next, err = c.page(ctx, path, qry, pageToken, &perms)
+	}
+	return perms, next, err
+}
+
 func (c *Client) page(ctx context.Context, path string, qry url.Values, token *PageToken, results interface{}) (*PageToken, error) {
 	if qry == nil {
 		qry = make(url.Values)
@@ -195,6 +264,10 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 }
 
 func (c *Client) authenticate(req *http.Request) error {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		return nil
+	}
 	req.SetBasicAuth(c.Username, c.AppPassword)
 	return nil
 }
@@ -236,6 +309,36 @@ type Repo struct {
 	Links       Links  `json:"links"`
 }
 
+// User is a Bitbucket Cloud user.
+type User struct {
+	UUID        string    `json:"uuid"`
+	Username    string    `json:"username"`
+	Nickname    string    `json:"nickname"`
+	DisplayName string    `json:"display_name"`
+	AccountID   string    `json:"account_id"`
+	Links       UserLinks `json:"links"`
+}
+
+type UserLinks struct {
+	HTML   Link `json:"html"`
+	Avatar Link `json:"avatar"`
+}
+
+// UserEmail is an email address of a Bitbucket Cloud user.
+type UserEmail struct {
+	Email       string `json:"email"`
+	IsPrimary   bool   `json:"is_primary"`
+	IsConfirmed bool   `json:"is_confirmed"`
+}
+
+// RepoPermission is the permission of a user on a repository, which is one of
+// "read", "write" and "admin".
+type RepoPermission struct {
+	Permission string `json:"permission"`
+	User       *User  `json:"user"`
+	Repository *Repo  `json:"repository"`
+}
+
 type Links struct {
 	Clone CloneLinks `json:"clone"`
 	HTML  Link       `json:"html"`
@@ -0,0 +1,51 @@
+package bitbucketcloud
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	eventTypeHeader = "X-Event-Key"
+)
+
+func WebhookEventType(r *http.Request) string {
+	return r.Header.Get(eventTypeHeader)
+}
+
+func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err error) {
+	switch eventType {
+	case "repo:push":
+		e = &PushEvent{}
+		return e, json.Unmarshal(payload, e)
+	default:
+		return nil, errors.Errorf("unknown webhook event type: %q", eventType)
+	}
+}
+
+// PushEvent is sent when one or more refs of a repository are created, updated
+// or deleted by a push.
+type PushEvent struct {
+	Repository Repo `json:"repository"`
+	Push       struct {
+		Changes []PushChange `json:"changes"`
+	} `json:"push"`
+}
+
+// PushChange describes a change to a single branch or tag in a PushEvent. Old
+// is nil for created refs, and New is nil for deleted refs.
+type PushChange struct {
+	Old     *PushRef `json:"old"`
+	New     *PushRef `json:"new"`
+	Created bool     `json:"created"`
+	Closed  bool     `json:"closed"`
+	Forced  bool     `json:"forced"`
+}
+
+// PushRef is a branch or tag in a PushChange.
+type PushRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
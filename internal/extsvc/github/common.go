@@ -93,7 +93,9 @@ func (c *CheckSuite) Key() string {
 
 // CheckRun represents the status of a checkrun
 type CheckRun struct {
-	ID string
+	ID         string
+	Name       string
+	DetailsURL string
 	// One of COMPLETED, IN_PROGRESS, QUEUED, REQUESTED
 	Status string
 	// One of ACTION_REQUIRED, CANCELLED, FAILURE, NEUTRAL, SUCCESS, TIMED_OUT
@@ -130,6 +132,7 @@ type CommitStatus struct {
 	SHA        string
 	Context    string
 	State      string
+	TargetURL  string
 	ReceivedAt time.Time
 }
 
@@ -144,6 +147,7 @@ type Context struct {
 	Context     string
 	Description string
 	State       string
+	TargetURL   string
 }
 
 type Label struct {
@@ -165,6 +169,7 @@ type PullRequest struct {
 	BaseRefOid    string
 	HeadRefName   string
 	BaseRefName   string
+	Mergeable     string
 	Number        int64
 	Author        Actor
 	Participants  []Actor
@@ -964,6 +969,20 @@ mutation MergePullRequest($input: MergePullRequestInput!) {
 
 // MergePullRequest tries to merge the PullRequest on Github.
 func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash bool) error {
+	mergeMethod := "MERGE"
+	if squash {
+		mergeMethod = "SQUASH"
+	}
+	return c.mergePullRequest(ctx, pr, mergeMethod)
+}
+
+// RebaseMergePullRequest tries to merge the PullRequest on Github by rebasing
+// its commits onto the base branch.
+func (c *V4Client) RebaseMergePullRequest(ctx context.Context, pr *PullRequest) error {
+	return c.mergePullRequest(ctx, pr, "REBASE")
+}
+
+func (c *V4Client) mergePullRequest(ctx context.Context, pr *PullRequest, mergeMethod string) error {
 	version := c.determineGitHubVersion(ctx)
 	prFragment, err := pullRequestFragments(version)
 	if err != nil {
@@ -980,10 +999,6 @@ func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash
 		} `json:"mergePullRequest"`
 	}
 
-	mergeMethod := "MERGE"
-	if squash {
-		mergeMethod = "SQUASH"
-	}
 	input := map[string]interface{}{"input": struct {
 		PullRequestID string `json:"pullRequestId"`
 		MergeMethod   string `json:"mergeMethod,omitempty"`
@@ -1348,6 +1363,7 @@ fragment commitWithChecks on Commit {
       context
       state
       description
+      targetUrl
     }
   }
   checkSuites(last: 20) {
@@ -1358,8 +1374,10 @@ fragment commitWithChecks on Commit {
       checkRuns(last: 20) {
         nodes {
           id
+          name
           status
           conclusion
+          detailsUrl
         }
       }
     }
@@ -1386,6 +1404,7 @@ fragment pr on PullRequest {
   baseRefOid
   headRefName
   baseRefName
+  mergeable
   %s
   author {
     ...actor
@@ -37,6 +37,10 @@ var MockGetMergeRequestNotes func(c *Client, ctx context.Context, project *Proje
 // Client.GetMergeRequestPipelines
 var MockGetMergeRequestPipelines func(c *Client, ctx context.Context, project *Project, iid ID) func() ([]*Pipeline, error)
 
+// MockGetPipelineJobs, if non-nil, will be called instead of
+// Client.GetPipelineJobs
+var MockGetPipelineJobs func(c *Client, ctx context.Context, project *Project, pipeline ID) func() ([]*Job, error)
+
 // MockGetOpenMergeRequestByRefs, if non-nil, will be called instead of
 // Client.GetOpenMergeRequestByRefs
 var MockGetOpenMergeRequestByRefs func(c *Client, ctx context.Context, project *Project, source, target string) (*MergeRequest, error)
@@ -59,6 +59,55 @@ func (c *Client) GetMergeRequestPipelines(ctx context.Context, project *Project,
 	}
 }
 
+// GetPipelineJobs retrieves the jobs of the given pipeline. As the jobs are
+// paginated, a function is returned that may be invoked to return the next
+// page of results. An empty slice and a nil error indicates that all pages
+// have been returned.
+func (c *Client) GetPipelineJobs(ctx context.Context, project *Project, pipeline ID) func() ([]*Job, error) {
+	if MockGetPipelineJobs != nil {
+		return MockGetPipelineJobs(c, ctx, project, pipeline)
+	}
+
+	baseURL := fmt.Sprintf("projects/%d/pipelines/%d/jobs", project.ID, pipeline)
+	currentPage := "1"
+	return func() ([]*Job, error) {
+		page := []*Job{}
+
+		// If there aren't any further pages, we'll return the empty slice we
+		// just created.
+		if currentPage == "" {
+			return page, nil
+		}
+
+		time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+		url, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		q := url.Query()
+		q.Add("page", currentPage)
+		url.RawQuery = q.Encode()
+
+		req, err := http.NewRequest("GET", url.String(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating job request")
+		}
+
+		header, _, err := c.do(ctx, req, &page)
+		if err != nil {
+			return nil, errors.Wrap(err, "requesting job page")
+		}
+
+		// If there's another page, this will be a page number. If there's not, then
+		// this will be an empty string, and we can detect that next iteration
+		// to short circuit.
+		currentPage = header.Get("X-Next-Page")
+
+		return page, nil
+	}
+}
+
 type Pipeline struct {
 	ID        ID             `json:"id"`
 	SHA       string         `json:"sha"`
@@ -67,6 +116,19 @@ type Pipeline struct {
 	WebURL    string         `json:"web_url"`
 	CreatedAt Time           `json:"created_at"`
 	UpdatedAt Time           `json:"updated_at"`
+
+	// Jobs is not part of the pipeline API response: it is filled in from the
+	// jobs API or the builds in pipeline webhook payloads.
+	Jobs []*Job `json:"jobs,omitempty"`
+}
+
+// Job is a single job in a pipeline.
+type Job struct {
+	ID     ID             `json:"id"`
+	Name   string         `json:"name"`
+	Stage  string         `json:"stage"`
+	Status PipelineStatus `json:"status"`
+	WebURL string         `json:"web_url"`
 }
 
 type PipelineStatus string
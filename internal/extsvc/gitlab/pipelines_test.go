@@ -141,6 +141,51 @@ func TestGetMergeRequestPipelines(t *testing.T) {
 	})
 }
 
+func TestGetPipelineJobs(t *testing.T) {
+	ctx := context.Background()
+	project := &Project{}
+
+	t.Run("error status code", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPEmptyResponse{http.StatusNotFound}
+
+		jobs, err := client.GetPipelineJobs(ctx, project, 42)()
+		if jobs != nil {
+			t.Errorf("unexpected non-nil jobs: %+v", jobs)
+		}
+		if err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("one page", func(t *testing.T) {
+		client := newTestClient(t)
+		client.httpClient = &mockHTTPResponseBody{
+			responseBody: `[{"id":1,"name":"test","stage":"test","status":"success"}]`,
+		}
+
+		it := client.GetPipelineJobs(ctx, project, 42)
+
+		jobs, err := it()
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		want := []*Job{{ID: 1, Name: "test", Stage: "test", Status: PipelineStatusSuccess}}
+		if diff := cmp.Diff(jobs, want); diff != "" {
+			t.Errorf("unexpected jobs: %s", diff)
+		}
+
+		// Calls after iteration should continue to return empty pages.
+		jobs, err = it()
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if diff := cmp.Diff(jobs, []*Job{}); diff != "" {
+			t.Errorf("unexpected jobs: %s", diff)
+		}
+	})
+}
+
 func TestPipelineKey(t *testing.T) {
 	pipeline := &Pipeline{ID: 42}
 	if have, want := pipeline.Key(), "Pipeline:42"; have != want {
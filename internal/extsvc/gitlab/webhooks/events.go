@@ -23,6 +23,18 @@ type PipelineEvent struct {
 	User         gitlab.User          `json:"user"`
 	Pipeline     gitlab.Pipeline      `json:"object_attributes"`
 	MergeRequest *gitlab.MergeRequest `json:"merge_request"`
+	Builds       []*gitlab.Job        `json:"builds"`
+}
+
+// PushEvent is sent when commits or tags are pushed to a project. Tag pushes
+// have the object kind "tag_push", but are otherwise identical.
+type PushEvent struct {
+	EventCommon
+
+	Before       string `json:"before"`
+	After        string `json:"after"`
+	Ref          string `json:"ref"`
+	UserUsername string `json:"user_username"`
 }
 
 var ErrObjectKindUnknown = errors.New("unknown object kind")
@@ -32,7 +44,7 @@ type downcaster interface {
 }
 
 // UnmarshalEvent unmarshals the given JSON into an event type. Possible return
-// types are *MergeRequestEvent.
+// types are *MergeRequestEvent, *PipelineEvent and *PushEvent.
 //
 // Errors caused by a valid payload being of an unknown type may be
 // distinguished from other errors by checking for ErrObjectKindUnknown in the
@@ -60,6 +72,8 @@ func UnmarshalEvent(data []byte) (interface{}, error) {
 		typedEvent = &mergeRequestEvent{}
 	case "pipeline":
 		typedEvent = &PipelineEvent{}
+	case "push", "tag_push":
+		typedEvent = &PushEvent{}
 	default:
 		return nil, errors.Wrapf(ErrObjectKindUnknown, "kind: %s", event.ObjectKind)
 	}
@@ -83,6 +83,8 @@ const (
 	KindPerforce        = "PERFORCE"
 	KindPhabricator     = "PHABRICATOR"
 	KindJVMPackages     = "JVMPACKAGES"
+	KindMercurial       = "MERCURIAL"
+	KindSubversion      = "SUBVERSION"
 	KindOther           = "OTHER"
 )
 
@@ -122,6 +124,14 @@ const (
 	// TypeJVMPackages is the (api.ExternalRepoSpec).ServiceType value for Maven packages (Java/JVM ecosystem libraries).
 	TypeJVMPackages = "jvmPackages"
 
+	// TypeMercurial is the (api.ExternalRepoSpec).ServiceType value for Mercurial repositories. The
+	// ServiceID value is the base URL of the repositories.
+	TypeMercurial = "mercurial"
+
+	// TypeSubversion is the (api.ExternalRepoSpec).ServiceType value for Subversion repositories. The
+	// ServiceID value is the base URL of the repositories.
+	TypeSubversion = "subversion"
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = "other"
 
@@ -150,6 +160,10 @@ func KindToType(kind string) string {
 		return TypePerforce
 	case KindJVMPackages:
 		return TypeJVMPackages
+	case KindMercurial:
+		return TypeMercurial
+	case KindSubversion:
+		return TypeSubversion
 	case KindOther:
 		return TypeOther
 	default:
@@ -179,6 +193,10 @@ func TypeToKind(t string) string {
 		return KindPhabricator
 	case TypeJVMPackages:
 		return KindJVMPackages
+	case TypeMercurial:
+		return KindMercurial
+	case TypeSubversion:
+		return KindSubversion
 	case TypeOther:
 		return KindOther
 	default:
@@ -215,6 +233,10 @@ func ParseServiceType(s string) (string, bool) {
 		return TypePhabricator, true
 	case jvmLower:
 		return TypeJVMPackages, true
+	case TypeMercurial:
+		return TypeMercurial, true
+	case TypeSubversion:
+		return TypeSubversion, true
 	case TypeOther:
 		return TypeOther, true
 	default:
@@ -244,6 +266,10 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindPhabricator, true
 	case KindJVMPackages:
 		return KindJVMPackages, true
+	case KindMercurial:
+		return KindMercurial, true
+	case KindSubversion:
+		return KindSubversion, true
 	case KindOther:
 		return KindOther, true
 	default:
@@ -285,6 +311,10 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.PhabricatorConnection{}
 	case KindJVMPackages:
 		cfg = &schema.JVMPackagesConnection{}
+	case KindMercurial:
+		cfg = &schema.MercurialConnection{}
+	case KindSubversion:
+		cfg = &schema.SubversionConnection{}
 	case KindOther:
 		cfg = &schema.OtherExternalServiceConnection{}
 	default:
@@ -304,6 +334,8 @@ func WebhookURL(kind string, externalServiceID int64, externalURL string) string
 		path = "bitbucket-server-webhooks"
 	case KindGitLab:
 		path = "gitlab-webhooks"
+	case KindBitbucketCloud:
+		path = "bitbucket-cloud-webhooks"
 	default:
 		return ""
 	}
@@ -496,6 +528,10 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
 		rawURL = c.Url
+	case *schema.MercurialConnection:
+		rawURL = c.Url
+	case *schema.SubversionConnection:
+		rawURL = c.Url
 	case *schema.GitoliteConnection:
 		rawURL = c.Host
 	case *schema.AWSCodeCommitConnection:
@@ -75,6 +75,17 @@ type Client struct {
 	// UserAgent is a string identifying who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
+
+	// PinnedAddrs is an optional function which returns the gitserver address
+	// of repositories which must not be routed by the hash of their name, keyed
+	// by normalized repository name. This is used to keep routing requests to
+	// the previous owner of a repository while it is moved to its new owner.
+	// The function must be safe for concurrent use.
+	PinnedAddrs func() map[api.RepoName]string
+
+	// hotRepos maps the names of repositories which gitservers reported as hot
+	// to the time until which they are considered hot.
+	hotRepos sync.Map
 }
 
 // AddrForRepo returns the gitserver address to use for the given repo name.
@@ -83,6 +94,11 @@ func (c *Client) AddrForRepo(repo api.RepoName) string {
 	if len(addrs) == 0 {
 		panic("unexpected state: no gitserver addresses")
 	}
+	if c.PinnedAddrs != nil {
+		if addr, ok := c.PinnedAddrs()[protocol.NormalizeRepo(repo)]; ok {
+			return addr
+		}
+	}
 	return AddrForRepo(repo, addrs)
 }
 
@@ -105,10 +121,71 @@ func AddrForRepo(repo api.RepoName, addrs []string) string {
 
 // addrForKey returns the gitserver address to use for the given string key,
 // which is hashed for sharding purposes.
+//
+// We use rendezvous (highest random weight) hashing: every address is scored by
+// hashing it together with the key, and the address with the highest score wins.
+// Adding a gitserver therefore only moves the keys the new gitserver wins, and
+// removing one only moves the keys it owned, instead of remapping almost every
+// key as hashing modulo the number of addresses would.
 func addrForKey(key string, addrs []string) string {
-	sum := md5.Sum([]byte(key))
-	serverIndex := binary.BigEndian.Uint64(sum[:]) % uint64(len(addrs))
-	return addrs[serverIndex]
+	var (
+		addr  string
+		score uint64
+	)
+	for _, candidate := range addrs {
+		if candidateScore := rendezvousScore(candidate, key); addr == "" || candidateScore > score {
+			addr, score = candidate, candidateScore
+		}
+	}
+	return addr
+}
+
+// rendezvousScore returns the score of the given address for the given key.
+func rendezvousScore(addr, key string) uint64 {
+	sum := md5.Sum([]byte(addr + "\x00" + key))
+	return binary.BigEndian.Uint64(sum[:])
+}
+
+// AddrForShard returns the address of the gitserver identified by the given shard
+// ID (the hostname recorded in gitserver_repos.shard_id), if it is one of the given
+// addresses.
+func AddrForShard(shardID string, addrs []string) (string, bool) {
+	for _, addr := range addrs {
+		if HostnameMatch(shardID, addr) {
+			return addr, true
+		}
+	}
+	return "", false
+}
+
+// PinnedAddrsForShards returns the addresses of the gitservers serving the given
+// repositories, identified by their shard IDs, keyed by normalized repository
+// name as expected by Client.PinnedAddrs. Repositories served by a gitserver
+// which isn't one of the given addresses are omitted, so that they are routed
+// to their new owner.
+func PinnedAddrsForShards(shards map[api.RepoName]string, addrs []string) map[api.RepoName]string {
+	addrsByRepo := make(map[api.RepoName]string, len(shards))
+	for name, shardID := range shards {
+		if addr, ok := AddrForShard(shardID, addrs); ok {
+			addrsByRepo[protocol.NormalizeRepo(name)] = addr
+		}
+	}
+	return addrsByRepo
+}
+
+// HostnameMatch checks whether the hostname matches the given address. If we don't
+// find an exact match, we look at the initial prefix.
+func HostnameMatch(hostname, addr string) bool {
+	if hostname == "" || !strings.HasPrefix(addr, hostname) {
+		return false
+	}
+	if addr == hostname {
+		return true
+	}
+	// We know that hostname is shorter than addr so we can safely check the next
+	// char
+	next := addr[len(hostname)]
+	return next == '.' || next == ':'
 }
 
 // ArchiveOptions contains options for the Archive func.
@@ -184,7 +261,7 @@ func (c *Client) Archive(ctx context.Context, repo api.RepoName, opt ArchiveOpti
 	}
 
 	u := c.ArchiveURL(repo, opt)
-	resp, err := c.do(ctx, repo, "GET", u.String(), nil)
+	resp, err := c.doRead(ctx, repo, "GET", strings.TrimPrefix(u.RequestURI(), "/"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +326,13 @@ func (c *Cmd) sendExec(ctx context.Context) (_ io.ReadCloser, _ http.Header, err
 		EnsureRevision: c.EnsureRevision,
 		Args:           c.Args[1:],
 	}
-	resp, err := c.client.httpPost(ctx, repoName, "exec", req)
+	var resp *http.Response
+	var err error
+	if isReadOnlyCommand(req.Args) {
+		resp, err = c.client.doRead(ctx, repoName, "POST", "exec", req)
+	} else {
+		resp, err = c.client.httpPost(ctx, repoName, "exec", req)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -843,6 +926,12 @@ func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, pay
 // do performs a request to a gitserver, sharding based on the given
 // repo name (the repo name is otherwise not used).
 func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) (resp *http.Response, err error) {
+	return c.doWithHeader(ctx, repo, method, op, payload, nil)
+}
+
+// doWithHeader is like do, but additionally sets the given headers on the
+// request.
+func (c *Client) doWithHeader(ctx context.Context, repo api.RepoName, method, op string, payload interface{}, header http.Header) (resp *http.Response, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Client.do")
 	defer func() {
 		span.LogKV("repo", string(repo), "method", method, "op", op)
@@ -871,6 +960,9 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", c.UserAgent)
 	req.Header.Set("X-Sourcegraph-Actor", userFromContext(ctx))
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	req = req.WithContext(ctx)
 
 	if c.HTTPLimiter != nil {
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,8 +23,12 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestClient_ListCloned(t *testing.T) {
@@ -263,17 +268,17 @@ func TestAddrForRepo(t *testing.T) {
 		{
 			name: "repo1",
 			repo: api.RepoName("repo1"),
-			want: "gitserver-3",
+			want: "gitserver-1",
 		},
 		{
 			name: "check we normalise",
 			repo: api.RepoName("repo1.git"),
-			want: "gitserver-3",
+			want: "gitserver-1",
 		},
 		{
 			name: "another repo",
 			repo: api.RepoName("github.com/sourcegraph/sourcegraph.git"),
-			want: "gitserver-2",
+			want: "gitserver-3",
 		},
 	}
 
@@ -287,6 +292,244 @@ func TestAddrForRepo(t *testing.T) {
 	}
 }
 
+func TestAddrForRepoAddingGitserver(t *testing.T) {
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	newAddrs := append(addrs[:len(addrs):len(addrs)], "gitserver-4")
+
+	const numRepos = 1000
+	var moved int
+	for i := 0; i < numRepos; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", i))
+
+		before := gitserver.AddrForRepo(repo, addrs)
+		after := gitserver.AddrForRepo(repo, newAddrs)
+		if before == after {
+			continue
+		}
+		if after != "gitserver-4" {
+			t.Fatalf("repo %q moved from %q to %q, want it to stay or move to the new gitserver", repo, before, after)
+		}
+		moved++
+	}
+
+	// We expect roughly a quarter of the repositories to move to the new gitserver
+	if moved == 0 || moved > numRepos/2 {
+		t.Errorf("unexpected number of moved repositories: %d of %d", moved, numRepos)
+	}
+}
+
+func TestClient_AddrForRepoPinned(t *testing.T) {
+	cli := gitserver.NewClient(&http.Client{})
+	cli.Addrs = func() []string {
+		return []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	}
+	cli.PinnedAddrs = func() map[api.RepoName]string {
+		return map[api.RepoName]string{"github.com/sourcegraph/sourcegraph": "gitserver-2"}
+	}
+
+	if addr := cli.AddrForRepo("github.com/sourcegraph/sourcegraph.git"); addr != "gitserver-2" {
+		t.Errorf("unexpected address for pinned repo. want=%q have=%q", "gitserver-2", addr)
+	}
+	if addr := cli.AddrForRepo("repo1"); addr != "gitserver-1" {
+		t.Errorf("unexpected address for unpinned repo. want=%q have=%q", "gitserver-1", addr)
+	}
+}
+
+func TestAddrForShard(t *testing.T) {
+	addrs := []string{"gitserver-1.gitserver:3178", "gitserver-10.gitserver:3178"}
+
+	for shardID, want := range map[string]string{
+		"gitserver-1":  "gitserver-1.gitserver:3178",
+		"gitserver-10": "gitserver-10.gitserver:3178",
+		"gitserver-2":  "",
+		"":             "",
+	} {
+		if have, _ := gitserver.AddrForShard(shardID, addrs); have != want {
+			t.Errorf("unexpected address for shard %q. want=%q have=%q", shardID, want, have)
+		}
+	}
+}
+
+func TestPinnedAddrsForShards(t *testing.T) {
+	addrs := []string{"gitserver-1.gitserver:3178", "gitserver-2.gitserver:3178"}
+	shards := map[api.RepoName]string{
+		"github.com/sourcegraph/sourcegraph.git": "gitserver-1",
+		"github.com/sourcegraph/zoekt":           "gitserver-2",
+		"github.com/sourcegraph/removed":         "gitserver-3",
+	}
+
+	want := map[api.RepoName]string{
+		"github.com/sourcegraph/sourcegraph": "gitserver-1.gitserver:3178",
+		"github.com/sourcegraph/zoekt":       "gitserver-2.gitserver:3178",
+	}
+	if diff := cmp.Diff(want, gitserver.PinnedAddrsForShards(shards, addrs)); diff != "" {
+		t.Errorf("unexpected pinned addresses (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplicaAddrsForRepo(t *testing.T) {
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+
+	for i := 0; i < 100; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/sourcegraph/repo-%d", i))
+
+		replicas := gitserver.ReplicaAddrsForRepo(repo, addrs, 2)
+		if len(replicas) != 2 {
+			t.Fatalf("unexpected number of replicas for repo %q. want=%d have=%d", repo, 2, len(replicas))
+		}
+		if want := gitserver.AddrForRepo(repo, addrs); replicas[0] != want {
+			t.Fatalf("unexpected primary for repo %q. want=%q have=%q", repo, want, replicas[0])
+		}
+		if replicas[0] == replicas[1] {
+			t.Fatalf("duplicate replica for repo %q: %q", repo, replicas[1])
+		}
+
+		// The second replica is where the repository moves when its primary is removed
+		var remaining []string
+		for _, addr := range addrs {
+			if addr != replicas[0] {
+				remaining = append(remaining, addr)
+			}
+		}
+		if want := gitserver.AddrForRepo(repo, remaining); replicas[1] != want {
+			t.Fatalf("unexpected replica for repo %q. want=%q have=%q", repo, want, replicas[1])
+		}
+	}
+
+	if replicas := gitserver.ReplicaAddrsForRepo("repo1", addrs, 5); len(replicas) != len(addrs) {
+		t.Errorf("unexpected number of replicas. want=%d have=%d", len(addrs), len(replicas))
+	}
+}
+
+func TestClient_ReadHotRepoFromReplica(t *testing.T) {
+	const repo = api.RepoName("github.com/sourcegraph/monorepo")
+
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				GitServerReplicas: &schema.GitServerReplicas{
+					Repos: []string{string(repo)},
+				},
+			},
+		},
+	})
+	defer conf.Mock(nil)
+
+	addrs := []string{"gitserver-1", "gitserver-2", "gitserver-3"}
+	replicas := gitserver.ReplicaAddrsForRepo(repo, addrs, 2)
+
+	var replicaReads int
+	cli := &gitserver.Client{
+		Addrs: func() []string { return addrs },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Host {
+			case replicas[1]:
+				if r.Header.Get(protocol.ReplicaReadHeader) == "" {
+					return nil, errors.Errorf("missing replica read header")
+				}
+				replicaReads++
+
+				// The replica has not replicated the repository yet
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil
+			case replicas[0]:
+				if r.Header.Get(protocol.ReplicaReadHeader) != "" {
+					return nil, errors.Errorf("unexpected replica read header")
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString("deadbeef")),
+					Trailer:    http.Header{"X-Exec-Exit-Status": {"0"}},
+				}, nil
+			default:
+				return nil, errors.Errorf("unexpected host: %s", r.URL.Host)
+			}
+		}),
+	}
+
+	for i := 0; i < 50; i++ {
+		cmd := cli.Command("git", "rev-parse", "HEAD")
+		cmd.Repo = repo
+
+		out, err := cmd.Output(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "deadbeef" {
+			t.Fatalf("unexpected output. want=%q have=%q", "deadbeef", out)
+		}
+	}
+
+	// The replica is chosen at random, so it is practically impossible that it
+	// was never chosen.
+	if replicaReads == 0 {
+		t.Errorf("expected read requests to be sent to the replica")
+	}
+}
+
+func TestClient_RPCFallsBackToExec(t *testing.T) {
+	const repo = api.RepoName("github.com/sourcegraph/sourcegraph")
+
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+
+	var execArgs []string
+	cli := &gitserver.Client{
+		Addrs: func() []string { return []string{"gitserver-1"} },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Path {
+			case "/resolve-revision":
+				// Gitservers without the typed endpoint respond with the 404 of
+				// their router.
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewBufferString("404 page not found\n")),
+				}, nil
+			case "/list-refs":
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"cloneInProgress":true}`)),
+				}, nil
+			case "/exec":
+				var req protocol.ExecRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					return nil, err
+				}
+				execArgs = req.Args
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString("deadbeef\n")),
+					Trailer:    http.Header{"X-Exec-Exit-Status": {"0"}},
+				}, nil
+			default:
+				return nil, errors.Errorf("unexpected path: %s", r.URL.Path)
+			}
+		}),
+	}
+
+	resp, err := cli.ResolveRevision(context.Background(), &protocol.ResolveRevisionRequest{Repo: repo, Spec: "main^0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.CommitID != "deadbeef" {
+		t.Errorf("unexpected commit. want=%q have=%q", "deadbeef", resp.CommitID)
+	}
+	if diff := cmp.Diff([]string{"rev-parse", "main^0"}, execArgs); diff != "" {
+		t.Errorf("unexpected exec args (-want +got):\n%s", diff)
+	}
+
+	// Requests for repositories which are not cloned yet are not retried.
+	execArgs = nil
+	if _, err := cli.ListRefs(context.Background(), &protocol.ListRefsRequest{Repo: repo}); !vcs.IsCloneInProgress(err) {
+		t.Errorf("expected clone in progress error, got %v", err)
+	}
+	if execArgs != nil {
+		t.Errorf("unexpected exec request: %v", execArgs)
+	}
+}
+
 func TestClient_P4Exec(t *testing.T) {
 	root, err := os.MkdirTemp("", t.Name())
 	if err != nil {
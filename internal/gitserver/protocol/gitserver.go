@@ -105,6 +105,27 @@ type RepoDeleteRequest struct {
 	Repo api.RepoName
 }
 
+// ReplicateRequest is a request to a gitserver to fetch a copy of a hot
+// repository from its primary gitserver.
+type ReplicateRequest struct {
+	// Repo is the repository to replicate.
+	Repo api.RepoName
+	// Primary is the address of the gitserver which owns the repository.
+	Primary string
+}
+
+const (
+	// ReplicaReadHeader is set on read-only requests which are sent to a replica
+	// of a hot repository rather than to its primary gitserver. Replicas do not
+	// clone or fetch the repository to serve such requests, but respond with
+	// http.StatusNotFound so that the client falls back to the primary.
+	ReplicaReadHeader = "X-Sourcegraph-Gitserver-Replica-Read"
+
+	// HotRepoHeader is set by the primary gitserver of a repository on responses
+	// to read requests while it considers the repository hot.
+	HotRepoHeader = "X-Sourcegraph-Gitserver-Hot-Repo"
+)
+
 // RepoInfoRequest is a request for information about multiple repositories on gitserver.
 type RepoInfoRequest struct {
 	// Repos are the repositories to get information about.
@@ -0,0 +1,218 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// The requests in this file are served by typed gitserver endpoints, which run
+// the git command for an operation and parse its output on behalf of the
+// client. Unlike ExecRequest, they do not let the client choose the git
+// arguments, which allows gitserver to cache their results and to enforce
+// limits per operation.
+//
+// Responses to typed requests which failed have the status code
+// http.StatusUnprocessableEntity and an RPCError body, except for requests for
+// repositories which are not cloned, which are responded to like ExecRequests.
+
+// ResolveRevisionRequest is a request to resolve a revision spec to a commit.
+type ResolveRevisionRequest struct {
+	Repo api.RepoName
+	// Spec is the revision spec to resolve, such as "HEAD" or "mybranch^0".
+	Spec string
+	// EnsureRevision is whether Spec should be fetched from the remote if it
+	// doesn't exist locally.
+	EnsureRevision bool
+}
+
+// ResolveRevisionResponse is the response to a ResolveRevisionRequest.
+type ResolveRevisionResponse struct {
+	// CommitID is the output of git rev-parse. It is not verified to be an
+	// absolute commit ID, as git returns "HEAD" for empty repositories.
+	CommitID api.CommitID
+}
+
+// ListRefsRequest is a request to list the refs of a repository.
+type ListRefsRequest struct {
+	Repo api.RepoName
+	// HeadsOnly restricts the refs to branches.
+	HeadsOnly bool
+	// TagsOnly restricts the refs to tags.
+	TagsOnly bool
+}
+
+// Ref is a ref in a ListRefsResponse.
+type Ref struct {
+	Name     string // the full name of the ref (e.g., "refs/heads/mybranch")
+	CommitID api.CommitID
+}
+
+// ListRefsResponse is the response to a ListRefsRequest. The refs are sorted
+// by name.
+type ListRefsResponse struct {
+	Refs []Ref
+}
+
+// ReadBlobRequest is a request to read the contents of a file at a commit.
+type ReadBlobRequest struct {
+	Repo   api.RepoName
+	Commit api.CommitID
+	Path   string
+	// MaxBytes limits the returned contents to the first MaxBytes bytes of the
+	// file. If MaxBytes <= 0, the entire file is read.
+	MaxBytes int64
+}
+
+// ReadBlobResponse is the response to a ReadBlobRequest.
+type ReadBlobResponse struct {
+	Content []byte
+	// Truncated is whether the file is larger than the requested MaxBytes.
+	Truncated bool
+}
+
+// ListTreeRequest is a request to list the entries of a tree at a commit.
+type ListTreeRequest struct {
+	Repo   api.RepoName
+	Commit api.CommitID
+	// Path is the tree entry to list. With a trailing slash, the entries of
+	// the tree at Path are listed instead of the entry for Path itself.
+	Path string
+	// Recursive lists the entries of subtrees too.
+	Recursive bool
+}
+
+// TreeEntry is an entry in a ListTreeResponse.
+type TreeEntry struct {
+	Path string // full path relative to the root of the repository
+	Mode uint32 // git file mode, such as 0100644
+	Type string // "blob", "tree" or "commit" (for submodules)
+	OID  string
+	Size int64 // -1 for trees and submodules, 0 for blobs missing from partial clones
+}
+
+// ListTreeResponse is the response to a ListTreeRequest. Entries is empty if
+// the listed path doesn't exist, or if the listed tree is empty.
+type ListTreeResponse struct {
+	Entries []TreeEntry
+}
+
+// LogRequest is a request to list the commits matching a set of filters.
+type LogRequest struct {
+	Repo api.RepoName
+
+	Range string // commit range (revspec, "A..B", "A...B", etc.)
+
+	N    uint // limit the number of returned commits to this many (0 means no limit)
+	Skip uint // skip this many commits at the beginning
+
+	MessageQuery string // include only commits whose commit message contains this substring
+
+	Author string // include only commits whose author matches this
+	After  string // include only commits after this date
+	Before string // include only commits before this date
+
+	Reverse   bool // Whether or not commits should be given in reverse order
+	DateOrder bool // Whether or not commits should be sorted by date
+
+	Path string // only commits modifying the given path are selected
+
+	// EnsureRevision is whether Range should be fetched from the remote if it
+	// doesn't exist locally.
+	EnsureRevision bool
+}
+
+// Signature is the author or committer of a Commit.
+type Signature struct {
+	Name  string
+	Email string
+	Date  time.Time
+}
+
+// Commit is a commit in a LogResponse.
+type Commit struct {
+	ID        api.CommitID
+	Author    Signature
+	Committer Signature
+	Message   string
+	Parents   []api.CommitID
+}
+
+// LogResponse is the response to a LogRequest.
+type LogResponse struct {
+	Commits []Commit
+}
+
+// DiffRequest is a request for the diff between two commits.
+type DiffRequest struct {
+	Repo api.RepoName
+	// Range is the commit range to diff, such as "A...B".
+	Range string
+}
+
+// DiffResponse is the response to a DiffRequest.
+type DiffResponse struct {
+	// Patch is the diff in the unified format, without a/ and b/ prefixes.
+	Patch []byte
+}
+
+// BlameRequest is a request for the blame of a file at a commit.
+type BlameRequest struct {
+	Repo   api.RepoName
+	Commit api.CommitID
+	Path   string
+
+	StartLine int // 1-indexed start line (or 0 for beginning of file)
+	EndLine   int // 1-indexed end line (or 0 for end of file)
+}
+
+// BlameHunk is a contiguous portion of a file associated with a commit.
+type BlameHunk struct {
+	StartLine int // 1-indexed start line number
+	EndLine   int // 1-indexed end line number
+	StartByte int // 0-indexed start byte position (inclusive)
+	EndByte   int // 0-indexed end byte position (exclusive)
+	CommitID  api.CommitID
+	Author    Signature
+	Message   string
+}
+
+// BlameResponse is the response to a BlameRequest.
+type BlameResponse struct {
+	Hunks []BlameHunk
+}
+
+// The kinds of RPCErrors.
+const (
+	// RPCErrorRevisionNotFound is reported if a requested revision doesn't exist.
+	RPCErrorRevisionNotFound = "revision-not-found"
+	// RPCErrorPathNotFound is reported if a requested path doesn't exist at the
+	// requested commit.
+	RPCErrorPathNotFound = "path-not-found"
+	// RPCErrorLimitExceeded is reported if the output of an operation exceeds
+	// the limit gitserver enforces for it.
+	RPCErrorLimitExceeded = "limit-exceeded"
+	// RPCErrorInvalidRequest is reported for requests with invalid arguments.
+	RPCErrorInvalidRequest = "invalid-request"
+	// RPCErrorBlobUnavailable is reported if the content of a requested file is
+	// missing from the partial clone of its repository, because its path is
+	// excluded from the clone or fetching it failed.
+	RPCErrorBlobUnavailable = "blob-unavailable"
+)
+
+// RPCError is the body of responses to typed requests which failed.
+type RPCError struct {
+	// Kind is one of the RPCError* constants, or empty for other errors.
+	Kind string
+	// Message describes the error, and usually includes the stderr of the git
+	// command which failed.
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	if e.Kind == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
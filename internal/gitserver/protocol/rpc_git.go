@@ -0,0 +1,339 @@
+package protocol
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// The git commands run for typed requests, and the parsers of their output, are
+// shared by gitserver and by clients which fall back to running them through
+// ExecRequests when gitserver doesn't serve the typed endpoints yet.
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *ResolveRevisionRequest) GitArgs() []string {
+	return []string{"rev-parse", r.Spec}
+}
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *ListRefsRequest) GitArgs() []string {
+	args := []string{"show-ref"}
+	if r.HeadsOnly {
+		args = append(args, "--heads")
+	}
+	if r.TagsOnly {
+		args = append(args, "--tags")
+	}
+	return args
+}
+
+// ParseShowRef parses the output of git show-ref, and returns the refs sorted
+// by name.
+func ParseShowRef(out []byte) ([]Ref, error) {
+	out = bytes.TrimSuffix(out, []byte("\n"))
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	lines := bytes.Split(out, []byte("\n"))
+	refs := make([]Ref, len(lines))
+	for i, line := range lines {
+		if len(line) <= 41 {
+			return nil, errors.New("unexpectedly short (<=41 bytes) line in `git show-ref ...` output")
+		}
+		refs[i] = Ref{Name: string(line[41:]), CommitID: api.CommitID(line[:40])}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs, nil
+}
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *ReadBlobRequest) GitArgs() []string {
+	return []string{"show", string(r.Commit) + ":" + r.Path}
+}
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *ListTreeRequest) GitArgs() []string {
+	args := []string{
+		"ls-tree",
+		"--long", // show size
+		"--full-name",
+		"-z",
+		string(r.Commit),
+	}
+	if r.Recursive {
+		args = append(args, "-r", "-t")
+	}
+	if r.Path != "" {
+		args = append(args, "--", r.Path)
+	}
+	return args
+}
+
+// ParseLsTree parses the output of git ls-tree -z, with or without --long.
+// Without --long, the size of all entries is -1.
+func ParseLsTree(out []byte) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line == "" {
+			// last entry is empty
+			continue
+		}
+
+		tabPos := strings.IndexByte(line, '\t')
+		if tabPos == -1 {
+			return nil, errors.Errorf("invalid `git ls-tree` output: %q", line)
+		}
+		info := strings.SplitN(line[:tabPos], " ", 4)
+		if len(info) != 3 && len(info) != 4 {
+			return nil, errors.Errorf("invalid `git ls-tree` output: %q", line)
+		}
+
+		mode, err := strconv.ParseUint(info[0], 8, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid `git ls-tree` mode output: %q", info[0])
+		}
+
+		size := int64(-1)
+		if len(info) == 4 {
+			// Size of "-" indicates a dir or submodule.
+			if sizeStr := strings.TrimSpace(info[3]); sizeStr != "-" {
+				size, err = strconv.ParseInt(sizeStr, 10, 64)
+				if err != nil || size < 0 {
+					return nil, errors.Errorf("invalid `git ls-tree` size output: %q (error: %s)", sizeStr, err)
+				}
+			}
+		}
+
+		entries = append(entries, TreeEntry{
+			Path: line[tabPos+1:],
+			Mode: uint32(mode),
+			Type: info[1],
+			OID:  info[2],
+			Size: size,
+		})
+	}
+	return entries, nil
+}
+
+// LogFormat is the format of the commits listed by git log. The fields of each
+// commit are NUL-separated.
+const LogFormat = "--format=format:%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
+
+// logPartsPerCommit is the number of NUL-separated fields per commit in the
+// output of git log with LogFormat.
+const logPartsPerCommit = 9
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *LogRequest) GitArgs() []string {
+	return append([]string{"log", LogFormat}, r.FilterArgs()...)
+}
+
+// FilterArgs returns the arguments which select the commits matching the
+// filters of r, for git log and other commands walking the commit history, such
+// as git rev-list.
+func (r *LogRequest) FilterArgs() []string {
+	var args []string
+	if r.N != 0 {
+		args = append(args, "-n", strconv.FormatUint(uint64(r.N), 10))
+	}
+	if r.Skip != 0 {
+		args = append(args, "--skip="+strconv.FormatUint(uint64(r.Skip), 10))
+	}
+
+	if r.Author != "" {
+		args = append(args, "--fixed-strings", "--author="+r.Author)
+	}
+
+	if r.After != "" {
+		args = append(args, "--after="+r.After)
+	}
+	if r.Before != "" {
+		args = append(args, "--before="+r.Before)
+	}
+	if r.Reverse {
+		args = append(args, "--reverse")
+	}
+	if r.DateOrder {
+		args = append(args, "--date-order")
+	}
+
+	if r.MessageQuery != "" {
+		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+r.MessageQuery)
+	}
+
+	if r.Range != "" {
+		args = append(args, r.Range)
+	}
+
+	if r.Path != "" {
+		args = append(args, "--", r.Path)
+	}
+	return args
+}
+
+// ParseLog parses the output of git log with LogFormat.
+func ParseLog(data []byte) ([]Commit, error) {
+	var commits []Commit
+	for len(data) > 0 {
+		parts := bytes.SplitN(data, []byte{'\x00'}, logPartsPerCommit+1)
+		if len(parts) < logPartsPerCommit {
+			return nil, errors.Errorf("invalid commit log entry: %q", parts)
+		}
+
+		// log outputs are newline separated, so all but the 1st commit ID part
+		// has an erroneous leading newline.
+		commitID := api.CommitID(bytes.TrimPrefix(parts[0], []byte{'\n'}))
+
+		authorTime, err := strconv.ParseInt(string(parts[3]), 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("parsing git commit author time: %s", err)
+		}
+		committerTime, err := strconv.ParseInt(string(parts[6]), 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("parsing git commit committer time: %s", err)
+		}
+
+		var parents []api.CommitID
+		if parentPart := parts[8]; len(parentPart) > 0 {
+			for _, id := range bytes.Split(parentPart, []byte{' '}) {
+				parents = append(parents, api.CommitID(id))
+			}
+		}
+
+		commits = append(commits, Commit{
+			ID:        commitID,
+			Author:    Signature{Name: string(parts[1]), Email: string(parts[2]), Date: time.Unix(authorTime, 0).UTC()},
+			Committer: Signature{Name: string(parts[4]), Email: string(parts[5]), Date: time.Unix(committerTime, 0).UTC()},
+			Message:   strings.TrimSuffix(string(parts[7]), "\n"),
+			Parents:   parents,
+		})
+
+		data = nil
+		if len(parts) == logPartsPerCommit+1 {
+			data = parts[logPartsPerCommit]
+		}
+	}
+	return commits, nil
+}
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *DiffRequest) GitArgs() []string {
+	return []string{
+		"diff",
+		"--find-renames",
+		"--full-index",
+		"--inter-hunk-context=3",
+		"--no-prefix",
+		r.Range,
+		"--",
+	}
+}
+
+// GitArgs returns the arguments of the git command run for r.
+func (r *BlameRequest) GitArgs() []string {
+	args := []string{"blame", "-w", "--porcelain"}
+	if r.StartLine != 0 || r.EndLine != 0 {
+		args = append(args, fmt.Sprintf("-L%d,%d", r.StartLine, r.EndLine))
+	}
+	return append(args, string(r.Commit), "--", r.Path)
+}
+
+// ParseBlamePorcelain parses the output of git blame --porcelain.
+func ParseBlamePorcelain(out []byte) ([]BlameHunk, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	type blameCommit struct {
+		author  Signature
+		summary string
+	}
+
+	commits := make(map[string]blameCommit)
+	hunks := make([]BlameHunk, 0)
+	remainingLines := strings.Split(string(out[:len(out)-1]), "\n")
+	byteOffset := 0
+	for len(remainingLines) > 0 {
+		// Consume hunk
+		hunkHeader := strings.Split(remainingLines[0], " ")
+		if len(hunkHeader) != 4 {
+			return nil, errors.Errorf("Expected at least 4 parts to hunkHeader, but got: '%s'", hunkHeader)
+		}
+		commitID := hunkHeader[0]
+		lineNoCur, _ := strconv.Atoi(hunkHeader[2])
+		nLines, _ := strconv.Atoi(hunkHeader[3])
+		hunk := BlameHunk{
+			CommitID:  api.CommitID(commitID),
+			StartLine: lineNoCur,
+			EndLine:   lineNoCur + nLines,
+			StartByte: byteOffset,
+		}
+
+		if _, in := commits[commitID]; in {
+			// Already seen commit
+			byteOffset += len(remainingLines[1])
+			remainingLines = remainingLines[2:]
+		} else {
+			// New commit
+			author := strings.Join(strings.Split(remainingLines[1], " ")[1:], " ")
+			email := strings.Join(strings.Split(remainingLines[2], " ")[1:], " ")
+			if len(email) >= 2 && email[0] == '<' && email[len(email)-1] == '>' {
+				email = email[1 : len(email)-1]
+			}
+			authorTime, err := strconv.ParseInt(strings.Join(strings.Split(remainingLines[3], " ")[1:], " "), 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("Failed to parse author-time %q", remainingLines[3])
+			}
+			summary := strings.Join(strings.Split(remainingLines[9], " ")[1:], " ")
+			commit := blameCommit{
+				author: Signature{
+					Name:  author,
+					Email: email,
+					Date:  time.Unix(authorTime, 0).UTC(),
+				},
+				summary: summary,
+			}
+
+			if len(remainingLines) >= 13 && strings.HasPrefix(remainingLines[10], "previous ") {
+				byteOffset += len(remainingLines[12])
+				remainingLines = remainingLines[13:]
+			} else if len(remainingLines) >= 13 && remainingLines[10] == "boundary" {
+				byteOffset += len(remainingLines[12])
+				remainingLines = remainingLines[13:]
+			} else if len(remainingLines) >= 12 {
+				byteOffset += len(remainingLines[11])
+				remainingLines = remainingLines[12:]
+			} else if len(remainingLines) == 11 {
+				// Empty file
+				remainingLines = remainingLines[11:]
+			} else {
+				return nil, errors.Errorf("Unexpected number of remaining lines (%d):\n%s", len(remainingLines), "  "+strings.Join(remainingLines, "\n  "))
+			}
+
+			commits[commitID] = commit
+		}
+
+		commit := commits[commitID]
+		hunk.Author = commit.author
+		hunk.Message = commit.summary
+
+		// Consume remaining lines in hunk
+		for i := 1; i < nLines; i++ {
+			byteOffset += len(remainingLines[1])
+			remainingLines = remainingLines[2:]
+		}
+
+		hunk.EndByte = byteOffset
+		hunks = append(hunks, hunk)
+	}
+
+	return hunks, nil
+}
@@ -0,0 +1,182 @@
+package gitserver
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// HotRepoTTL is how long a repository stays hot after its primary gitserver
+// last reported it as hot.
+const HotRepoTTL = 10 * time.Minute
+
+var replicaReadCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_client_replica_reads_total",
+	Help: "Number of read requests for hot repositories sent to a replica, by whether the replica served them or the request fell back to the primary gitserver.",
+}, []string{"status"})
+
+// readOnlyCommands are the git commands which do not modify a repository, and
+// which may therefore be served by a replica of a hot repository.
+var readOnlyCommands = map[string]struct{}{
+	"archive":      {},
+	"blame":        {},
+	"cat-file":     {},
+	"diff":         {},
+	"for-each-ref": {},
+	"grep":         {},
+	"log":          {},
+	"ls-files":     {},
+	"ls-tree":      {},
+	"merge-base":   {},
+	"rev-list":     {},
+	"rev-parse":    {},
+	"shortlog":     {},
+	"show":         {},
+	"show-ref":     {},
+}
+
+// isReadOnlyCommand returns true if the git command with the given arguments
+// (excluding "git") does not modify the repository.
+func isReadOnlyCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, ok := readOnlyCommands[args[0]]
+	return ok
+}
+
+// ReplicaAddrsForRepo returns the addresses of the n gitservers which serve the
+// given repo when it is hot. The first address is the primary gitserver of the
+// repo, which is the one returned by AddrForRepo. The others are the gitservers
+// the repo would move to if the gitservers before them were removed, so adding
+// or removing a gitserver only changes the replicas of few repositories.
+func ReplicaAddrsForRepo(repo api.RepoName, addrs []string, n int) []string {
+	key := string(protocol.NormalizeRepo(repo))
+
+	ranked := make([]string, len(addrs))
+	copy(ranked, addrs)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rendezvousScore(ranked[i], key) > rendezvousScore(ranked[j], key)
+	})
+
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// IsHotRepo returns true if the given repo is listed as hot in the site
+// configuration.
+func IsHotRepo(repo api.RepoName) bool {
+	repo = protocol.NormalizeRepo(repo)
+	for _, name := range conf.GitServerReplicas().Repos {
+		if protocol.NormalizeRepo(api.RepoName(name)) == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// isHot returns true if the given repo is listed as hot in the site
+// configuration, or if its primary gitserver recently reported it as hot.
+func (c *Client) isHot(repo api.RepoName) bool {
+	if IsHotRepo(repo) {
+		return true
+	}
+
+	repo = protocol.NormalizeRepo(repo)
+	if until, ok := c.hotRepos.Load(repo); ok {
+		if time.Now().Before(until.(time.Time)) {
+			return true
+		}
+		c.hotRepos.Delete(repo)
+	}
+	return false
+}
+
+// readAddrsForRepo returns the gitserver addresses to send a read-only request
+// for the given repo to, in order. Requests for hot repositories go to a random
+// replica first, and then to the primary gitserver of the repository.
+func (c *Client) readAddrsForRepo(repo api.RepoName) []string {
+	primary := c.AddrForRepo(repo)
+
+	replicas := conf.GitServerReplicas().Replicas
+	if replicas < 2 || !c.isHot(repo) {
+		return []string{primary}
+	}
+
+	addrs := ReplicaAddrsForRepo(repo, c.Addrs(), replicas)
+	if addrs[0] != primary {
+		// The repository is pinned to another gitserver while it is being moved,
+		// so its replicas may not have been created yet.
+		return []string{primary}
+	}
+
+	if addr := addrs[rand.Intn(len(addrs))]; addr != primary {
+		return []string{addr, primary}
+	}
+	return []string{primary}
+}
+
+// doRead performs a read-only request to a gitserver. Requests for hot
+// repositories are load-balanced across the replicas of the repository, and
+// are sent to its primary gitserver if the replica could not serve them.
+func (c *Client) doRead(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) (*http.Response, error) {
+	addrs := c.readAddrsForRepo(repo)
+	for _, addr := range addrs[:len(addrs)-1] {
+		header := http.Header{}
+		header.Set(protocol.ReplicaReadHeader, "true")
+
+		resp, err := c.doWithHeader(ctx, repo, method, "http://"+addr+"/"+op, payload, header)
+		if err == nil && resp.StatusCode != http.StatusNotFound {
+			replicaReadCounter.WithLabelValues("served").Inc()
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		replicaReadCounter.WithLabelValues("fallback").Inc()
+	}
+
+	resp, err := c.doWithHeader(ctx, repo, method, "http://"+addrs[len(addrs)-1]+"/"+op, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header.Get(protocol.HotRepoHeader) != "" {
+		c.hotRepos.Store(protocol.NormalizeRepo(repo), time.Now().Add(HotRepoTTL))
+	}
+	return resp, nil
+}
+
+// ReplicateRepo asks the gitserver at the given address to fetch a copy of the
+// given hot repo from its primary gitserver.
+func (c *Client) ReplicateRepo(ctx context.Context, repo api.RepoName, addr, primary string) error {
+	req := &protocol.ReplicateRequest{
+		Repo:    repo,
+		Primary: primary,
+	}
+	resp, err := c.httpPost(ctx, repo, "http://"+addr+"/replicate", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &url.Error{URL: resp.Request.URL.String(), Op: "Replicate", Err: errors.Errorf("Replicate: http status %d", resp.StatusCode)}
+	}
+	return nil
+}
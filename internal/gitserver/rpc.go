@@ -0,0 +1,168 @@
+package gitserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// ResolveRevision resolves a revision spec using git rev-parse.
+func (c *Client) ResolveRevision(ctx context.Context, req *protocol.ResolveRevisionRequest) (*protocol.ResolveRevisionResponse, error) {
+	var resp protocol.ResolveRevisionResponse
+	if err := c.doRPC(ctx, req.Repo, "resolve-revision", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.resolveRevisionExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListRefs lists the refs of a repository using git show-ref.
+func (c *Client) ListRefs(ctx context.Context, req *protocol.ListRefsRequest) (*protocol.ListRefsResponse, error) {
+	var resp protocol.ListRefsResponse
+	if err := c.doRPC(ctx, req.Repo, "list-refs", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.listRefsExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReadBlob reads the contents of a file at a commit using git show.
+func (c *Client) ReadBlob(ctx context.Context, req *protocol.ReadBlobRequest) (*protocol.ReadBlobResponse, error) {
+	var resp protocol.ReadBlobResponse
+	if err := c.doRPC(ctx, req.Repo, "read-blob", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.readBlobExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListTree lists the entries of a tree at a commit using git ls-tree.
+func (c *Client) ListTree(ctx context.Context, req *protocol.ListTreeRequest) (*protocol.ListTreeResponse, error) {
+	var resp protocol.ListTreeResponse
+	if err := c.doRPC(ctx, req.Repo, "list-tree", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.listTreeExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Log lists the commits matching a set of filters using git log.
+func (c *Client) Log(ctx context.Context, req *protocol.LogRequest) (*protocol.LogResponse, error) {
+	var resp protocol.LogResponse
+	if err := c.doRPC(ctx, req.Repo, "log", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.logExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Diff returns the diff between two commits using git diff.
+func (c *Client) Diff(ctx context.Context, req *protocol.DiffRequest) (*protocol.DiffResponse, error) {
+	var resp protocol.DiffResponse
+	if err := c.doRPC(ctx, req.Repo, "diff", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.diffExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Blame returns the blame of a file at a commit using git blame.
+func (c *Client) Blame(ctx context.Context, req *protocol.BlameRequest) (*protocol.BlameResponse, error) {
+	var resp protocol.BlameResponse
+	if err := c.doRPC(ctx, req.Repo, "blame", req, &resp); err != nil {
+		if errors.Is(err, errRPCNotSupported) {
+			return c.blameExec(ctx, req)
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// errRPCNotSupported is returned by doRPC if gitserver doesn't serve the typed
+// endpoint of the request yet, such as while gitserver is being upgraded.
+var errRPCNotSupported = errors.New("gitserver does not serve typed requests")
+
+// doRPC sends a typed request to gitserver and decodes the response into resp.
+// Requests for repositories which are not cloned fail with a
+// vcs.RepoNotExistError, requests which gitserver could not serve fail with a
+// *protocol.RPCError, and requests to gitservers without the typed endpoint fail
+// with errRPCNotSupported.
+func (c *Client) doRPC(ctx context.Context, repo api.RepoName, op string, req, resp interface{}) (err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Client.doRPC")
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+	span.SetTag("request", op)
+	span.SetTag("repo", repo)
+
+	// Check that ctx is not expired.
+	if err := ctx.Err(); err != nil {
+		deadlineExceededCounter.Inc()
+		return err
+	}
+
+	r, err := c.doRead(ctx, repo, "POST", op, req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	switch r.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(r.Body).Decode(resp)
+
+	case http.StatusNotFound:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		var payload protocol.NotFoundPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			// Gitservers without the endpoint respond with the plain text 404
+			// of their router rather than a NotFoundPayload.
+			return errRPCNotSupported
+		}
+		return &vcs.RepoNotExistError{Repo: repo, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
+
+	case http.StatusUnprocessableEntity:
+		var rpcErr protocol.RPCError
+		if err := json.NewDecoder(r.Body).Decode(&rpcErr); err != nil {
+			return err
+		}
+		return &rpcErr
+
+	default:
+		return errors.Errorf("unexpected status code: %d", r.StatusCode)
+	}
+}
+
+// IsRPCError returns true if err is a *protocol.RPCError of the given kind.
+func IsRPCError(err error, kind string) bool {
+	var e *protocol.RPCError
+	return errors.As(err, &e) && e.Kind == kind
+}
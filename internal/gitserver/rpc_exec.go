@@ -0,0 +1,139 @@
+package gitserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// The functions in this file serve typed requests through exec requests, for
+// gitservers which don't serve the typed endpoints yet. They run the same git
+// commands as gitserver does, and report errors in the same way.
+
+// execRPC runs git with the given arguments through an exec request. If git
+// fails, the returned error includes its stderr.
+func (c *Client) execRPC(ctx context.Context, repo api.RepoName, ensureRevision string, args []string) (stdout []byte, stderr string, exitStatus int, err error) {
+	cmd := c.Command("git", args...)
+	cmd.Repo = repo
+	cmd.EnsureRevision = ensureRevision
+
+	stdout, stderrBytes, err := cmd.DividedOutput(ctx)
+	stderr = strings.TrimSpace(string(stderrBytes))
+	if err != nil {
+		return stdout, stderr, cmd.ExitStatus, errors.WithMessage(err, fmt.Sprintf("git command %v failed (stderr: %q)", args, stderr))
+	}
+	return stdout, stderr, cmd.ExitStatus, nil
+}
+
+func (c *Client) resolveRevisionExec(ctx context.Context, req *protocol.ResolveRevisionRequest) (*protocol.ResolveRevisionResponse, error) {
+	var ensureRevision string
+	if req.EnsureRevision {
+		ensureRevision = req.Spec
+	}
+
+	stdout, stderr, _, err := c.execRPC(ctx, req.Repo, ensureRevision, req.GitArgs())
+	if err != nil {
+		if strings.Contains(stderr, "unknown revision") {
+			return nil, &protocol.RPCError{Kind: protocol.RPCErrorRevisionNotFound, Message: stderr}
+		}
+		return nil, err
+	}
+	return &protocol.ResolveRevisionResponse{CommitID: api.CommitID(bytes.TrimSpace(stdout))}, nil
+}
+
+func (c *Client) listRefsExec(ctx context.Context, req *protocol.ListRefsRequest) (*protocol.ListRefsResponse, error) {
+	stdout, _, exitStatus, err := c.execRPC(ctx, req.Repo, "", req.GitArgs())
+	if err != nil {
+		// Exit status of 1 and no output means there were no
+		// results. This is not a fatal error.
+		if exitStatus == 1 && len(stdout) == 0 {
+			return &protocol.ListRefsResponse{}, nil
+		}
+		return nil, err
+	}
+
+	refs, err := protocol.ParseShowRef(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.ListRefsResponse{Refs: refs}, nil
+}
+
+func (c *Client) readBlobExec(ctx context.Context, req *protocol.ReadBlobRequest) (*protocol.ReadBlobResponse, error) {
+	stdout, stderr, _, err := c.execRPC(ctx, req.Repo, "", req.GitArgs())
+	if err != nil {
+		if strings.Contains(stderr, "exists on disk, but not in") || strings.Contains(stderr, "does not exist") {
+			return nil, &protocol.RPCError{Kind: protocol.RPCErrorPathNotFound, Message: stderr}
+		}
+		return nil, err
+	}
+
+	if req.MaxBytes > 0 && int64(len(stdout)) > req.MaxBytes {
+		return &protocol.ReadBlobResponse{Content: stdout[:req.MaxBytes], Truncated: true}, nil
+	}
+	return &protocol.ReadBlobResponse{Content: stdout}, nil
+}
+
+func (c *Client) listTreeExec(ctx context.Context, req *protocol.ListTreeRequest) (*protocol.ListTreeResponse, error) {
+	stdout, stderr, _, err := c.execRPC(ctx, req.Repo, "", req.GitArgs())
+	if err != nil {
+		if strings.Contains(stderr, "exists on disk, but not in") {
+			return nil, &protocol.RPCError{Kind: protocol.RPCErrorPathNotFound, Message: stderr}
+		}
+		return nil, err
+	}
+
+	entries, err := protocol.ParseLsTree(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.ListTreeResponse{Entries: entries}, nil
+}
+
+func (c *Client) logExec(ctx context.Context, req *protocol.LogRequest) (*protocol.LogResponse, error) {
+	var ensureRevision string
+	if req.EnsureRevision {
+		ensureRevision = req.Range
+	}
+
+	stdout, stderr, _, err := c.execRPC(ctx, req.Repo, ensureRevision, req.GitArgs())
+	if err != nil {
+		if stderr == "fatal: bad object "+req.Range {
+			return nil, &protocol.RPCError{Kind: protocol.RPCErrorRevisionNotFound, Message: stderr}
+		}
+		return nil, err
+	}
+
+	commits, err := protocol.ParseLog(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.LogResponse{Commits: commits}, nil
+}
+
+func (c *Client) diffExec(ctx context.Context, req *protocol.DiffRequest) (*protocol.DiffResponse, error) {
+	stdout, _, _, err := c.execRPC(ctx, req.Repo, "", req.GitArgs())
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.DiffResponse{Patch: stdout}, nil
+}
+
+func (c *Client) blameExec(ctx context.Context, req *protocol.BlameRequest) (*protocol.BlameResponse, error) {
+	stdout, _, _, err := c.execRPC(ctx, req.Repo, "", req.GitArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	hunks, err := protocol.ParseBlamePorcelain(stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.BlameResponse{Hunks: hunks}, nil
+}
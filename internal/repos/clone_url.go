@@ -71,6 +71,14 @@ func CloneURL(kind, config string, repo *types.Repo) (string, error) {
 		if r, ok := repo.Metadata.(*jvmpackages.Metadata); ok {
 			return r.Module.CloneURL(), nil
 		}
+	case *schema.MercurialConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return setUserinfoBestEffort(otherCloneURL(repo, r), t.Username, t.Password), nil
+		}
+	case *schema.SubversionConnection:
+		if r, ok := repo.Metadata.(*extsvc.OtherRepoMetadata); ok {
+			return setUserinfoBestEffort(otherCloneURL(repo, r), t.Username, t.Password), nil
+		}
 	default:
 		return "", errors.Errorf("unknown external service kind %q for repo %d", kind, repo.ID)
 	}
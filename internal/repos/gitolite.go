@@ -101,6 +101,7 @@ func (s GitoliteSource) makeRepo(repo *gitolite.Repo) *types.Repo {
 		Name:         api.RepoName(name),
 		URI:          name,
 		ExternalRepo: gitolite.ExternalRepoSpec(repo, gitolite.ServiceID(s.conn.Host)),
+		Private:      s.conn.Authorization != nil,
 		Sources: map[string]*types.SourceInfo{
 			urn: {
 				ID:       urn,
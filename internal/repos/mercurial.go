@@ -0,0 +1,123 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A MercurialSource yields repositories from a single Mercurial connection
+// configured in Sourcegraph via the external services configuration.
+type MercurialSource struct {
+	svc    *types.ExternalService
+	conn   *schema.MercurialConnection
+	client httpcli.Doer
+}
+
+// NewMercurialSource returns a new MercurialSource from the given external
+// service.
+func NewMercurialSource(svc *types.ExternalService, cf *httpcli.Factory) (*MercurialSource, error) {
+	var c schema.MercurialConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MercurialSource{svc: svc, conn: &c, client: cli}, nil
+}
+
+// ListRepos returns all Mercurial repositories of the connection. If the
+// connection doesn't list its repositories, they are listed from the hgweb
+// index at its URL.
+func (s MercurialSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := parseBaseURL(s.conn.Url)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	paths := s.conn.Repos
+	if len(paths) == 0 {
+		if paths, err = s.listHgwebRepos(ctx, base); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	for _, path := range paths {
+		r, err := baseURLRepo(s.svc.URN(), extsvc.TypeMercurial, base, s.conn.RepositoryPathPattern, path)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s MercurialSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+// listHgwebRepos returns the paths of the repositories in the JSON index of
+// the hgweb server at base.
+func (s MercurialSource) listHgwebRepos(ctx context.Context, base *url.URL) ([]string, error) {
+	u := *base
+	u.RawQuery = "style=json"
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.conn.Username != "" {
+		req.SetBasicAuth(s.conn.Username, s.conn.Password)
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read hgweb index")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d listing hgweb repositories: %s", resp.StatusCode, b)
+	}
+
+	var index struct {
+		Entries []struct {
+			Name string `json:"name"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode hgweb index: %s", b)
+	}
+
+	paths := make([]string, 0, len(index.Entries))
+	for _, e := range index.Entries {
+		if path := strings.Trim(e.Name, "/"); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
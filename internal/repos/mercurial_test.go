@@ -0,0 +1,117 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestMercurialSource_ListRepos(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "alice" || password != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/hg/" || r.URL.Query().Get("style") != "json" {
+			http.Error(w, r.URL.String()+" not found", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"entries": [{"name": "foo", "description": "unknown"}, {"name": "team/bar"}]}`))
+	}))
+	defer s.Close()
+
+	// The host of the base URL in repository names, e.g. 127.0.0.1-1234.
+	host := strings.ReplaceAll(strings.TrimPrefix(s.URL, "http://"), ":", "-")
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		want   []*types.Repo
+		err    string
+	}{{
+		name:   "hgweb index",
+		config: fmt.Sprintf(`{"url": %q, "username": "alice", "password": "secret"}`, s.URL+"/hg"),
+		want: []*types.Repo{{
+			Name: api.RepoName(host + "/hg/foo"),
+			URI:  host + "/hg/foo",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "foo",
+				ServiceType: extsvc.TypeMercurial,
+				ServiceID:   s.URL + "/hg/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:mercurial:1": {
+					ID:       "extsvc:mercurial:1",
+					CloneURL: s.URL + "/hg/foo",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "foo"},
+		}, {
+			Name: api.RepoName(host + "/hg/team/bar"),
+			URI:  host + "/hg/team/bar",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "team/bar",
+				ServiceType: extsvc.TypeMercurial,
+				ServiceID:   s.URL + "/hg/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:mercurial:1": {
+					ID:       "extsvc:mercurial:1",
+					CloneURL: s.URL + "/hg/team/bar",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "team/bar"},
+		}},
+	}, {
+		name:   "repos",
+		config: fmt.Sprintf(`{"url": %q, "repos": ["/baz/"], "repositoryPathPattern": "hg/{repo}"}`, s.URL+"/hg/"),
+		want: []*types.Repo{{
+			Name: "hg/baz",
+			URI:  host + "/hg/baz",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "baz",
+				ServiceType: extsvc.TypeMercurial,
+				ServiceID:   s.URL + "/hg/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:mercurial:1": {
+					ID:       "extsvc:mercurial:1",
+					CloneURL: s.URL + "/hg/baz",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "baz"},
+		}},
+	}, {
+		name:   "unauthorized",
+		config: fmt.Sprintf(`{"url": %q}`, s.URL+"/hg/"),
+		err:    "unexpected status 401",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewMercurialSource(&types.ExternalService{
+				ID:     1,
+				Kind:   extsvc.KindMercurial,
+				Config: tc.config,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+			if got := fmt.Sprint(err); tc.err != "" && !strings.Contains(got, tc.err) || tc.err == "" && err != nil {
+				t.Fatalf("got error %v, want %q", got, tc.err)
+			}
+			if diff := cmp.Diff(tc.want, repos); diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -60,6 +60,10 @@ func NewSource(svc *types.ExternalService, cf *httpcli.Factory) (Source, error)
 		return NewPerforceSource(svc)
 	case extsvc.KindJVMPackages:
 		return NewJVMPackagesSource(svc)
+	case extsvc.KindMercurial:
+		return NewMercurialSource(svc, cf)
+	case extsvc.KindSubversion:
+		return NewSubversionSource(svc, cf)
 	case extsvc.KindOther:
 		return NewOtherSource(svc, cf)
 	default:
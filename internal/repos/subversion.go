@@ -0,0 +1,120 @@
+package repos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A SubversionSource yields repositories from a single Subversion connection
+// configured in Sourcegraph via the external services configuration.
+type SubversionSource struct {
+	svc    *types.ExternalService
+	conn   *schema.SubversionConnection
+	client httpcli.Doer
+}
+
+// NewSubversionSource returns a new SubversionSource from the given external
+// service.
+func NewSubversionSource(svc *types.ExternalService, cf *httpcli.Factory) (*SubversionSource, error) {
+	var c schema.SubversionConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubversionSource{svc: svc, conn: &c, client: cli}, nil
+}
+
+// ListRepos returns all Subversion repositories of the connection. If the
+// connection doesn't list its repositories, they are listed from the parent
+// path listing at its URL.
+func (s SubversionSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	base, err := parseBaseURL(s.conn.Url)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	paths := s.conn.Repos
+	if len(paths) == 0 {
+		if paths, err = s.listParentPathRepos(ctx, base); err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+	}
+
+	for _, path := range paths {
+		r, err := baseURLRepo(s.svc.URN(), extsvc.TypeSubversion, base, s.conn.RepositoryPathPattern, path)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: err}
+			return
+		}
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s SubversionSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+// parentPathEntryRe matches the links to repositories in the HTML listing
+// served by mod_dav_svn for an SVNParentPath with SVNListParentPath on.
+var parentPathEntryRe = lazyregexp.New(`<li><a href="([^"/?#:][^"?#:]*)/">`)
+
+// listParentPathRepos returns the paths of the repositories in the parent
+// path listing at base.
+func (s SubversionSource) listParentPathRepos(ctx context.Context, base *url.URL) ([]string, error) {
+	req, err := http.NewRequest("GET", base.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.conn.Username != "" {
+		req.SetBasicAuth(s.conn.Username, s.conn.Password)
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Subversion parent path listing")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d listing Subversion repositories: %s", resp.StatusCode, b)
+	}
+
+	var paths []string
+	for _, m := range parentPathEntryRe.FindAllStringSubmatch(string(b), -1) {
+		path, err := url.PathUnescape(m[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid repository link %q", m[1])
+		}
+		if path = strings.Trim(path, "/"); path != "" && path != ".." {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
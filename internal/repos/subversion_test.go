@@ -0,0 +1,122 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSubversionSource_ListRepos(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/svn/" {
+			http.Error(w, r.URL.String()+" not found", http.StatusNotFound)
+			return
+		}
+		// The listing of an SVNParentPath by mod_dav_svn.
+		_, _ = w.Write([]byte(`<html><head><title>Collection of Repositories</title></head>
+<body>
+<h2>Collection of Repositories</h2>
+<ul>
+<li><a href="foo/">foo/</a></li>
+<li><a href="tools/">tools/</a></li>
+</ul>
+<hr noshade><em>Powered by <a href="http://subversion.apache.org/">Apache Subversion</a> version 1.14.1.</em>
+</body></html>`))
+	}))
+	defer s.Close()
+
+	// The host of the base URL in repository names, e.g. 127.0.0.1-1234.
+	host := strings.ReplaceAll(strings.TrimPrefix(s.URL, "http://"), ":", "-")
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		want   []*types.Repo
+		err    string
+	}{{
+		name:   "parent path listing",
+		config: fmt.Sprintf(`{"url": %q, "repositoryPathPattern": "svn/{repo}"}`, s.URL+"/svn"),
+		want: []*types.Repo{{
+			Name: "svn/foo",
+			URI:  host + "/svn/foo",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "foo",
+				ServiceType: extsvc.TypeSubversion,
+				ServiceID:   s.URL + "/svn/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:subversion:1": {
+					ID:       "extsvc:subversion:1",
+					CloneURL: s.URL + "/svn/foo",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "foo"},
+		}, {
+			Name: "svn/tools",
+			URI:  host + "/svn/tools",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "tools",
+				ServiceType: extsvc.TypeSubversion,
+				ServiceID:   s.URL + "/svn/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:subversion:1": {
+					ID:       "extsvc:subversion:1",
+					CloneURL: s.URL + "/svn/tools",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "tools"},
+		}},
+	}, {
+		name:   "repos",
+		config: fmt.Sprintf(`{"url": %q, "repos": ["bar"], "repositoryPathPattern": "svn/{repo}"}`, s.URL+"/svn/"),
+		want: []*types.Repo{{
+			Name: "svn/bar",
+			URI:  host + "/svn/bar",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "bar",
+				ServiceType: extsvc.TypeSubversion,
+				ServiceID:   s.URL + "/svn/",
+			},
+			Sources: map[string]*types.SourceInfo{
+				"extsvc:subversion:1": {
+					ID:       "extsvc:subversion:1",
+					CloneURL: s.URL + "/svn/bar",
+				},
+			},
+			Metadata: &extsvc.OtherRepoMetadata{RelativePath: "bar"},
+		}},
+	}, {
+		name:   "repository outside of url",
+		config: fmt.Sprintf(`{"url": %q, "repos": ["../bar"]}`, s.URL+"/svn/"),
+		err:    `repository "../bar" is not in`,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewSubversionSource(&types.ExternalService{
+				ID:     1,
+				Kind:   extsvc.KindSubversion,
+				Config: tc.config,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+			if got := fmt.Sprint(err); tc.err != "" && !strings.Contains(got, tc.err) || tc.err == "" && err != nil {
+				t.Fatalf("got error %v, want %q", got, tc.err)
+			}
+			if diff := cmp.Diff(tc.want, repos); diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
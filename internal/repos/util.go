@@ -2,6 +2,14 @@ package repos
 
 import (
 	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 // setUserinfoBestEffort adds the username and password to rawurl. If user is
@@ -38,3 +46,55 @@ func setUserinfoBestEffort(rawurl, username, password string) string {
 
 	return u.String()
 }
+
+// parseBaseURL parses the base URL of a connection whose repositories are at
+// paths relative to it.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	u.User = nil
+	u.RawQuery, u.Fragment = "", ""
+	return u, nil
+}
+
+// baseURLRepo returns the repository at path relative to base of a connection
+// which is converted to Git, such as a Mercurial or Subversion connection. The
+// credentials of the connection are added to the clone URL by CloneURL.
+func baseURLRepo(urn, serviceType string, base *url.URL, repositoryPathPattern, path string) (*types.Repo, error) {
+	path = strings.Trim(path, "/")
+	cloneURL, err := base.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	if cloneURL.Host != base.Host || !strings.HasPrefix(cloneURL.Path, base.Path) {
+		return nil, errors.Errorf("repository %q is not in %s", path, base)
+	}
+
+	serviceID := base.String()
+	relativePath := strings.TrimPrefix(cloneURL.String(), serviceID)
+	name := url.URL{Host: base.Host, Path: base.Path}
+
+	return &types.Repo{
+		Name: api.RepoName(reposource.OtherRepoName(repositoryPathPattern, name.String(), relativePath)),
+		URI:  reposource.OtherRepoName("", name.String(), relativePath),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          relativePath,
+			ServiceType: serviceType,
+			ServiceID:   serviceID,
+		},
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL.String(),
+			},
+		},
+		Metadata: &extsvc.OtherRepoMetadata{
+			RelativePath: relativePath,
+		},
+	}, nil
+}
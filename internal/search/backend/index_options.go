@@ -43,6 +43,10 @@ type zoektIndexOptions struct {
 	// Priority indicates ranking in results, higher first.
 	Priority float64 `json:",omitempty"`
 
+	// DocumentRanks maps file paths to a rank used to order file matches
+	// within the repository, higher first.
+	DocumentRanks map[string]float64 `json:",omitempty"`
+
 	// Error if non-empty indicates the request failed for the repo.
 	Error string `json:",omitempty"`
 }
@@ -66,12 +70,21 @@ type RepoIndexOptions struct {
 	// Archived is true if the repository is archived.
 	Archived bool
 
+	// DocumentRanks maps file paths to a rank used to order file matches
+	// within the repository, higher first. Only the maxDocumentRanks highest
+	// ranked paths are sent to zoekt.
+	DocumentRanks map[string]float64
+
 	// GetVersion is used to resolve revisions for a repo. If it fails, the
 	// error is encoded in the body. If the revision is missing, an empty
 	// string should be returned rather than an error.
 	GetVersion func(branch string) (string, error)
 }
 
+// maxDocumentRanks is the maximum number of document ranks sent to zoekt for
+// a single repository.
+const maxDocumentRanks = 10000
+
 // GetIndexOptions returns a json blob for consumption by
 // sourcegraph-zoekt-indexserver. It is for repos based on site settings c.
 func GetIndexOptions(
@@ -121,6 +134,8 @@ func getIndexOptions(
 		Archived:   opts.Archived,
 		LargeFiles: c.SearchLargeFiles,
 		Symbols:    getBoolPtr(c.SearchIndexSymbolsEnabled, true),
+
+		DocumentRanks: topDocumentRanks(opts.DocumentRanks, maxDocumentRanks),
 	}
 
 	// Set of branch names. Always index HEAD
@@ -190,6 +205,31 @@ func getIndexOptions(
 	return marshal(o)
 }
 
+// topDocumentRanks returns the n highest ranked paths of the given document
+// ranks. Ties are broken by path to keep the result stable.
+func topDocumentRanks(ranks map[string]float64, n int) map[string]float64 {
+	if len(ranks) <= n {
+		return ranks
+	}
+
+	paths := make([]string, 0, len(ranks))
+	for path := range ranks {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if ranks[paths[i]] != ranks[paths[j]] {
+			return ranks[paths[i]] > ranks[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	top := make(map[string]float64, n)
+	for _, path := range paths[:n] {
+		top[path] = ranks[path]
+	}
+	return top
+}
+
 func getBoolPtr(b *bool, default_ bool) bool {
 	if b == nil {
 		return default_
@@ -86,6 +86,18 @@ func TestGetIndexOptions(t *testing.T) {
 				{Name: "HEAD", Version: "!HEAD"},
 			},
 		},
+	}, {
+		name: "document ranks",
+		conf: schema.SiteConfiguration{},
+		repo: "ranked",
+		want: zoektIndexOptions{
+			RepoID:        8,
+			Symbols:       true,
+			DocumentRanks: map[string]float64{"main.go": 5, "util.go": 1},
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+		},
 	}, {
 		name: "nosymbols",
 		conf: schema.SiteConfiguration{
@@ -245,7 +257,7 @@ func TestGetIndexOptions(t *testing.T) {
 
 	getRepoIndexOptions := func(repo string) (*RepoIndexOptions, error) {
 		repoID := int32(1)
-		for _, r := range []string{"repo", "foo", "not_in_version_context", "priority", "public", "fork", "archived"} {
+		for _, r := range []string{"repo", "foo", "not_in_version_context", "priority", "public", "fork", "archived", "ranked"} {
 			if r == repo {
 				break
 			}
@@ -255,12 +267,17 @@ func TestGetIndexOptions(t *testing.T) {
 		if repo == "priority" {
 			priority = 10
 		}
+		var documentRanks map[string]float64
+		if repo == "ranked" {
+			documentRanks = map[string]float64{"main.go": 5, "util.go": 1}
+		}
 		return &RepoIndexOptions{
-			RepoID:   repoID,
-			Public:   repo == "public",
-			Fork:     repo == "fork",
-			Archived: repo == "archived",
-			Priority: priority,
+			RepoID:        repoID,
+			Public:        repo == "public",
+			Fork:          repo == "fork",
+			Archived:      repo == "archived",
+			Priority:      priority,
+			DocumentRanks: documentRanks,
 			GetVersion: func(branch string) (string, error) {
 				return "!" + branch, nil
 			},
@@ -439,3 +456,16 @@ func parseVersionContext(name string, repoRevStrs ...string) *schema.VersionCont
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestTopDocumentRanks(t *testing.T) {
+	ranks := map[string]float64{"a.go": 1, "b.go": 3, "c.go": 2, "d.go": 3}
+
+	if diff := cmp.Diff(ranks, topDocumentRanks(ranks, 4)); diff != "" {
+		t.Errorf("unexpected ranks (-want, +got):\n%s", diff)
+	}
+
+	want := map[string]float64{"b.go": 3, "c.go": 2, "d.go": 3}
+	if diff := cmp.Diff(want, topDocumentRanks(ranks, 3)); diff != "" {
+		t.Errorf("unexpected ranks (-want, +got):\n%s", diff)
+	}
+}
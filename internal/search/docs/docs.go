@@ -0,0 +1,79 @@
+// Package docs implements type:docs search over the API documentation of
+// precise code intelligence indexes.
+package docs
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// DefaultLimit is the maximum number of documentation results returned when
+// the query does not specify a count.
+const DefaultLimit = 50
+
+// Searcher searches the API documentation of precise code intelligence
+// indexes. It is implemented by the code intelligence resolver, which is only
+// available in enterprise builds.
+type Searcher interface {
+	SearchDocumentation(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error)
+}
+
+// DocumentationSearch is a search job that streams documentation matches.
+type DocumentationSearch struct {
+	Searcher Searcher
+
+	// Args are the generic search parameters of the query. The repositories
+	// to search are read from Args.Repos when the job is run, as they are
+	// resolved after the job is created.
+	Args *search.TextParameters
+
+	Limit int
+}
+
+func (s *DocumentationSearch) Run(ctx context.Context, stream streaming.Sender) error {
+	if len(s.Args.Repos) == 0 {
+		return nil
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(s.Args.Repos))
+	for _, repoRevs := range s.Args.Repos {
+		repoIDs = append(repoIDs, repoRevs.Repo.ID)
+	}
+
+	matches, err := s.Searcher.SearchDocumentation(ctx, search.DocumentationParameters{
+		Query:     s.Args.PatternInfo.Pattern,
+		RepoIDs:   repoIDs,
+		Languages: s.Args.PatternInfo.Languages,
+		Kinds:     symbolKinds(s.Args.PatternInfo.Select),
+		Limit:     s.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	results := make([]result.Match, 0, len(matches))
+	for _, match := range matches {
+		results = append(results, match)
+	}
+
+	stream.Send(streaming.SearchEvent{
+		Results: results,
+		Stats: streaming.Stats{
+			IsLimitHit: len(matches) >= s.Limit,
+		},
+	})
+	return nil
+}
+
+// symbolKinds returns the symbol kinds selected by select:symbol.<kind>, if any.
+func symbolKinds(path filter.SelectPath) []string {
+	if path.Root() == filter.Symbol && len(path) == 2 {
+		return []string{result.DocumentationTagForSymbolKind(path[1])}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type searcherFunc func(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error)
+
+func (f searcherFunc) SearchDocumentation(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+	return f(ctx, args)
+}
+
+func TestDocumentationSearch(t *testing.T) {
+	repo := types.RepoName{ID: 42, Name: "github.com/gorilla/mux"}
+	match := &result.DocumentationMatch{Repo: repo, Commit: "deadbeef", PathID: "/#Router", SearchKey: "mux.Router"}
+
+	var calls []search.DocumentationParameters
+	searcher := searcherFunc(func(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+		calls = append(calls, args)
+		return []*result.DocumentationMatch{match}, nil
+	})
+
+	job := &DocumentationSearch{
+		Searcher: searcher,
+		Args: &search.TextParameters{
+			PatternInfo: &search.TextPatternInfo{
+				Pattern:   "request router",
+				Languages: []string{"go"},
+				Select:    filter.SelectPath{filter.Symbol, "enum-member"},
+			},
+			Repos: []*search.RepositoryRevisions{{Repo: repo}, {Repo: types.RepoName{ID: 43, Name: "github.com/gorilla/websocket"}}},
+		},
+		Limit: 1,
+	}
+
+	matches, stats, err := streaming.CollectStream(func(stream streaming.Sender) error {
+		return job.Run(context.Background(), stream)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedArgs := []search.DocumentationParameters{{
+		Query:     "request router",
+		RepoIDs:   []api.RepoID{42, 43},
+		Languages: []string{"go"},
+		Kinds:     []string{"enumNumber"},
+		Limit:     1,
+	}}
+	if diff := cmp.Diff(expectedArgs, calls); diff != "" {
+		t.Errorf("unexpected search arguments (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]result.Match{match}, matches); diff != "" {
+		t.Errorf("unexpected matches (-want +got):\n%s", diff)
+	}
+	if !stats.IsLimitHit {
+		t.Errorf("expected limit to be hit")
+	}
+}
+
+func TestDocumentationSearchNoRepos(t *testing.T) {
+	searcher := searcherFunc(func(ctx context.Context, args search.DocumentationParameters) ([]*result.DocumentationMatch, error) {
+		t.Fatalf("unexpected documentation search")
+		return nil, nil
+	})
+
+	job := &DocumentationSearch{
+		Searcher: searcher,
+		Args:     &search.TextParameters{PatternInfo: &search.TextPatternInfo{Pattern: "router"}},
+		Limit:    DefaultLimit,
+	}
+	if err := job.Run(context.Background(), streaming.StreamFunc(func(streaming.SearchEvent) {})); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,110 @@
+package result
+
+import (
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// DocumentationMatch is a documentation node of a precise code intelligence index (e.g. the
+// documentation of a function or type) that matches a type:docs search.
+type DocumentationMatch struct {
+	Repo   types.RepoName
+	Commit api.CommitID
+
+	// PathID is the path ID of the documentation node, e.g. `/mux#Router.ServeHTTP`.
+	PathID string
+
+	// SearchKey is the fully qualified name of the documented symbol, e.g. `mux.Router.ServeHTTP`.
+	SearchKey string
+
+	// Label is a one-line summary of the documented symbol, typically its signature.
+	Label string
+
+	// Detail is the documentation of the symbol, typically its doc comment.
+	Detail string
+
+	// Tags describe the documented symbol, e.g. its kind and whether it is exported.
+	Tags []string
+
+	// Language is the language of the documented symbol.
+	Language string
+}
+
+func (d *DocumentationMatch) RepoName() types.RepoName {
+	return d.Repo
+}
+
+func (d *DocumentationMatch) ResultCount() int {
+	return 1
+}
+
+func (d *DocumentationMatch) Limit(limit int) int {
+	// Always represents one result and limit > 0 so we just return limit - 1.
+	return limit - 1
+}
+
+func (d *DocumentationMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: d.Repo.Name,
+			ID:   d.Repo.ID,
+		}
+	case filter.Symbol:
+		if len(path) == 1 {
+			return d
+		}
+		if len(path) == 2 && d.hasTag(DocumentationTagForSymbolKind(path[1])) {
+			return d
+		}
+	}
+	return nil
+}
+
+func (d *DocumentationMatch) hasTag(tag string) bool {
+	for _, t := range d.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the URL of the documentation page section describing this match.
+func (d *DocumentationMatch) URL() *url.URL {
+	path := "/" + string(d.Repo.Name)
+	if d.Commit != "" {
+		path += "@" + string(d.Commit)
+	}
+	return &url.URL{Path: path + "/-/docs" + d.PathID}
+}
+
+func (d *DocumentationMatch) Key() Key {
+	return Key{
+		TypeRank: rankDocumentationMatch,
+		Repo:     d.Repo.Name,
+		Commit:   d.Commit,
+		Path:     d.PathID,
+	}
+}
+
+func (d *DocumentationMatch) searchResultMarker() {}
+
+// documentationTags maps select:symbol.<kind> values whose spelling differs from the corresponding
+// LSIF documentation tag.
+var documentationTags = map[string]string{
+	"enum-member":    "enumNumber",
+	"type-parameter": "typeParameter",
+}
+
+// DocumentationTagForSymbolKind returns the LSIF documentation tag describing symbols of the given
+// select:symbol.<kind> kind.
+func DocumentationTagForSymbolKind(kind string) string {
+	if tag, ok := documentationTags[kind]; ok {
+		return tag
+	}
+	return kind
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
-// Match is *FileMatch | *RepoMatch | *CommitMatch. We have a private method
+// Match is *FileMatch | *RepoMatch | *CommitMatch | *DocumentationMatch. We have a private method
 // to ensure only those types implement Match.
 type Match interface {
 	ResultCount() int
@@ -26,6 +26,7 @@ var (
 	_ Match = (*FileMatch)(nil)
 	_ Match = (*RepoMatch)(nil)
 	_ Match = (*CommitMatch)(nil)
+	_ Match = (*DocumentationMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
@@ -36,6 +37,8 @@ const (
 	rankCommitMatch = 1
 	rankDiffMatch   = 2
 	rankRepoMatch   = 3
+
+	rankDocumentationMatch = 4
 )
 
 // Key is a sorting or deduplicating key for a Match.
@@ -7,10 +7,13 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/docs"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
@@ -18,8 +21,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 )
 
-func NewAggregator(db dbutil.DB, stream streaming.Sender) *Aggregator {
+func NewAggregator(ctx context.Context, db dbutil.DB, stream streaming.Sender) *Aggregator {
 	return &Aggregator{
+		ctx:          ctx,
 		db:           db,
 		parentStream: stream,
 		errors:       &multierror.Error{},
@@ -27,6 +31,9 @@ func NewAggregator(db dbutil.DB, stream streaming.Sender) *Aggregator {
 }
 
 type Aggregator struct {
+	// ctx is the context of the search, whose actor's sub-repo permissions are
+	// enforced on the results.
+	ctx          context.Context
 	parentStream streaming.Sender
 	db           dbutil.DB
 
@@ -46,6 +53,14 @@ func (a *Aggregator) Get() ([]result.Match, streaming.Stats, *multierror.Error)
 }
 
 func (a *Aggregator) Send(event streaming.SearchEvent) {
+	results, err := filterSubRepoPermissions(a.ctx, authz.DefaultSubRepoPermsChecker, actor.FromContext(a.ctx), event.Results)
+	if err != nil {
+		// Don't send any results we can't be sure the actor can read.
+		a.Error(errors.Wrap(err, "checking sub-repo permissions"))
+		results = nil
+	}
+	event.Results = results
+
 	if a.parentStream != nil {
 		a.parentStream.Send(event)
 	}
@@ -83,6 +98,8 @@ func jobName(job Job) string {
 	switch job.(type) {
 	case *unindexed.StructuralSearch:
 		return "Structural"
+	case *docs.DocumentationSearch:
+		return "Documentation"
 	default:
 		return "Unknown"
 	}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// filterSubRepoPermissions returns the matches which the actor can read
+// because of sub-repo permissions.
+//
+// File matches, which include symbol and path matches, are kept if the actor
+// can read the file. Commit matches in repositories with sub-repo permissions
+// are kept if the actor can read all the files touched by the commit, because
+// the message and the diff of a commit can refer to any of them. The diff
+// preview of a match isn't enough, since it only holds the matched files.
+//
+// Documentation matches only carry a documentation path ID, not the path of
+// the file defining the symbol, so they can't be checked and are dropped in
+// repositories with sub-repo permissions. The same goes for any other match
+// type which isn't handled below.
+func filterSubRepoPermissions(ctx context.Context, checker authz.SubRepoPermissionChecker, a *actor.Actor, matches []result.Match) ([]result.Match, error) {
+	if !checker.Enabled() || a.IsInternal() || !a.IsAuthenticated() {
+		return matches, nil
+	}
+
+	// enabledForRepo caches whether the actor has rules for a repository.
+	enabled := make(map[api.RepoName]bool)
+	enabledForRepo := func(repo api.RepoName) (bool, error) {
+		if e, ok := enabled[repo]; ok {
+			return e, nil
+		}
+		e, err := authz.ActorEnabledForRepo(ctx, checker, a, repo)
+		if err != nil {
+			return false, err
+		}
+		enabled[repo] = e
+		return e, nil
+	}
+
+	canRead := func(repo api.RepoName, path string) (bool, error) {
+		perms, err := authz.ActorPermissions(ctx, checker, a, authz.RepoContent{Repo: repo, Path: path})
+		if err != nil {
+			return false, err
+		}
+		return perms.Include(authz.Read), nil
+	}
+
+	filtered := matches[:0:0]
+	for _, m := range matches {
+		repo := m.RepoName().Name
+		if e, err := enabledForRepo(repo); err != nil {
+			return nil, err
+		} else if !e {
+			filtered = append(filtered, m)
+			continue
+		}
+
+		keep := true
+		switch v := m.(type) {
+		case *result.FileMatch:
+			ok, err := canRead(repo, v.Path)
+			if err != nil {
+				return nil, err
+			}
+			keep = ok
+
+		case *result.CommitMatch:
+			paths, err := git.CommitFiles(ctx, repo, v.Commit.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range paths {
+				ok, err := canRead(repo, path)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					keep = false
+					break
+				}
+			}
+
+		case *result.RepoMatch:
+			// A repository match reveals no file contents.
+
+		case *result.DocumentationMatch:
+			keep = false
+
+		default:
+			keep = false
+		}
+		if keep {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, nil
+}
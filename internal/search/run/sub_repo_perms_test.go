@@ -0,0 +1,105 @@
+package run
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// fakeSubRepoPermsChecker allows reading the paths of repo "restricted" which
+// don't start with "secret/", and all paths of other repositories.
+type fakeSubRepoPermsChecker struct{}
+
+func (fakeSubRepoPermsChecker) Permissions(ctx context.Context, userID int32, content authz.RepoContent) (authz.Perms, error) {
+	if content.Repo == "restricted" && strings.HasPrefix(content.Path, "secret/") {
+		return authz.None, nil
+	}
+	return authz.Read, nil
+}
+
+func (fakeSubRepoPermsChecker) Enabled() bool {
+	return true
+}
+
+func (fakeSubRepoPermsChecker) EnabledForRepo(ctx context.Context, userID int32, repo api.RepoName) (bool, error) {
+	return repo == "restricted", nil
+}
+
+func TestFilterSubRepoPermissions(t *testing.T) {
+	restricted := types.RepoName{ID: 1, Name: "restricted"}
+	other := types.RepoName{ID: 2, Name: "other"}
+
+	commitFiles := map[api.CommitID][]string{
+		"public":  {"public/a.go"},
+		"secret":  {"secret/b.go"},
+		"partial": {"public/a.go", "secret/b.go"},
+	}
+	git.Mocks.CommitFiles = func(repo api.RepoName, commit api.CommitID) ([]string, error) {
+		return commitFiles[commit], nil
+	}
+	defer git.ResetMocks()
+
+	diff := func(path string) *result.HighlightedString {
+		return &result.HighlightedString{Value: "diff --git " + path + " " + path + "\nindex 1..2 100644\n--- " + path + "\n+++ " + path + "\n@@ -1 +1 @@\n-a\n+b\n"}
+	}
+	message := &result.HighlightedString{Value: "message"}
+	matches := []result.Match{
+		&result.FileMatch{File: result.File{Repo: restricted, Path: "public/a.go"}},
+		&result.FileMatch{File: result.File{Repo: restricted, Path: "secret/b.go"}},
+		&result.FileMatch{File: result.File{Repo: other, Path: "secret/b.go"}},
+		&result.CommitMatch{Repo: restricted, Commit: git.Commit{ID: "public"}, DiffPreview: diff("public/a.go")},
+		&result.CommitMatch{Repo: restricted, Commit: git.Commit{ID: "secret"}, DiffPreview: diff("secret/b.go")},
+		// Only the readable file matched, but the commit also touches a restricted one.
+		&result.CommitMatch{Repo: restricted, Commit: git.Commit{ID: "partial"}, DiffPreview: diff("public/a.go")},
+		&result.CommitMatch{Repo: restricted, Commit: git.Commit{ID: "public"}, MessagePreview: message},
+		&result.CommitMatch{Repo: restricted, Commit: git.Commit{ID: "secret"}, MessagePreview: message},
+		&result.CommitMatch{Repo: other, Commit: git.Commit{ID: "secret"}, MessagePreview: message},
+		&result.RepoMatch{Name: restricted.Name, ID: restricted.ID},
+		// Documentation matches have no file path to check.
+		&result.DocumentationMatch{Repo: restricted, PathID: "/public#A"},
+		&result.DocumentationMatch{Repo: other, PathID: "/secret#B"},
+		// Match types which aren't known to the filter are dropped.
+		unknownMatch{Repo: restricted},
+		unknownMatch{Repo: other},
+	}
+
+	t.Run("user", func(t *testing.T) {
+		got, err := filterSubRepoPermissions(context.Background(), fakeSubRepoPermsChecker{}, actor.FromUser(1), matches)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []result.Match{matches[0], matches[2], matches[3], matches[6], matches[8], matches[9], matches[11], matches[13]}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("internal actor", func(t *testing.T) {
+		got, err := filterSubRepoPermissions(context.Background(), fakeSubRepoPermsChecker{}, &actor.Actor{Internal: true}, matches)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(matches) {
+			t.Fatalf("want all %d matches but got %d", len(matches), len(got))
+		}
+	})
+}
+
+// unknownMatch is a match type which filterSubRepoPermissions doesn't handle.
+type unknownMatch struct {
+	result.Match
+	Repo types.RepoName
+}
+
+func (m unknownMatch) RepoName() types.RepoName {
+	return m.Repo
+}
@@ -128,6 +128,8 @@ func (r *eventMatchUnmarshaller) UnmarshalJSON(b []byte) error {
 		r.EventMatch = &EventSymbolMatch{}
 	case CommitMatchType:
 		r.EventMatch = &EventCommitMatch{}
+	case DocumentationMatchType:
+		r.EventMatch = &EventDocumentationMatch{}
 	default:
 		return errors.Errorf("unknown MatchType %v", typeU.Type)
 	}
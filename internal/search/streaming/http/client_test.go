@@ -48,6 +48,11 @@ func TestFrontendClient(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventDocumentationMatch{
+				Type:      DocumentationMatchType,
+				SearchKey: "test",
+				Tags:      []string{"function"},
+			},
 		},
 	}, {
 		Name: "filters",
@@ -118,6 +118,26 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventDocumentationMatch is a documentation node of a precise code
+// intelligence index matching a type:docs search.
+type EventDocumentationMatch struct {
+	// Type is always DocumentationMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Label           string     `json:"label"`
+	URL             string     `json:"url"`
+	Detail          string     `json:"detail"`
+	SearchKey       string     `json:"searchKey"`
+	Tags            []string   `json:"tags"`
+	Language        string     `json:"language,omitempty"`
+	Repository      string     `json:"repository"`
+	RepoStars       int        `json:"repoStars,omitempty"`
+	RepoLastFetched *time.Time `json:"repoLastFetched,omitempty"`
+	Version         string     `json:"version,omitempty"`
+}
+
+func (e *EventDocumentationMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -156,6 +176,7 @@ const (
 	SymbolMatchType
 	CommitMatchType
 	PathMatchType
+	DocumentationMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -170,6 +191,8 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"commit"`), nil
 	case PathMatchType:
 		return []byte(`"path"`), nil
+	case DocumentationMatchType:
+		return []byte(`"docs"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -186,6 +209,8 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = CommitMatchType
 	} else if bytes.Equal(b, []byte(`"path"`)) {
 		*t = PathMatchType
+	} else if bytes.Equal(b, []byte(`"docs"`)) {
+		*t = DocumentationMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
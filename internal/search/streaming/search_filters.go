@@ -150,6 +150,12 @@ func (s *SearchFilters) Update(event SearchEvent) {
 			// can only be used with the 'repo:' scope. In that case,
 			// we shouldn't be getting any repositoy name matches back.
 			addRepoFilter(v.Name, v.ID, "", 1)
+		case *result.DocumentationMatch:
+			addRepoFilter(v.Repo.Name, v.Repo.ID, "", 1)
+			if v.Language != "" {
+				value := fmt.Sprintf(`lang:%s`, v.Language)
+				s.filters.Add(value, value, 1, false, "lang")
+			}
 		}
 	}
 }
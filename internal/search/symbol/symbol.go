@@ -16,7 +16,9 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -304,7 +306,34 @@ func searchZoekt(ctx context.Context, repoName types.RepoName, commitID api.Comm
 	return
 }
 
-func Compute(ctx context.Context, repoName types.RepoName, commitID api.CommitID, inputRev *string, query *string, first *int32, includePatterns *[]string) (res []*result.SymbolMatch, err error) {
+func Compute(ctx context.Context, repoName types.RepoName, commitID api.CommitID, inputRev *string, query *string, first *int32, includePatterns *[]string) ([]*result.SymbolMatch, error) {
+	matches, err := compute(ctx, repoName, commitID, inputRev, query, first, includePatterns)
+	if len(matches) == 0 {
+		return matches, err
+	}
+
+	// Only return the symbols of the files which the actor can read because of
+	// sub-repo permissions.
+	a := actor.FromContext(ctx)
+	if enabled, checkErr := authz.ActorEnabledForRepo(ctx, authz.DefaultSubRepoPermsChecker, a, repoName.Name); checkErr != nil {
+		return nil, checkErr
+	} else if !enabled {
+		return matches, err
+	}
+	filtered := matches[:0]
+	for _, m := range matches {
+		perms, checkErr := authz.ActorPermissions(ctx, authz.DefaultSubRepoPermsChecker, a, authz.RepoContent{Repo: repoName.Name, Path: m.File.Path})
+		if checkErr != nil {
+			return nil, checkErr
+		}
+		if perms.Include(authz.Read) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, err
+}
+
+func compute(ctx context.Context, repoName types.RepoName, commitID api.CommitID, inputRev *string, query *string, first *int32, includePatterns *[]string) (res []*result.SymbolMatch, err error) {
 	// TODO(keegancsmith) we should be able to use indexedSearchRequest here
 	// and remove indexedSymbolsBranch.
 	if branch := indexedSymbolsBranch(ctx, string(repoName.Name), string(commitID)); branch != "" {
@@ -22,10 +22,11 @@ type TypeParameters interface {
 	typeParametersValue()
 }
 
-func (CommitParameters) typeParametersValue()  {}
-func (DiffParameters) typeParametersValue()    {}
-func (SymbolsParameters) typeParametersValue() {}
-func (TextParameters) typeParametersValue()    {}
+func (CommitParameters) typeParametersValue()        {}
+func (DiffParameters) typeParametersValue()          {}
+func (SymbolsParameters) typeParametersValue()       {}
+func (DocumentationParameters) typeParametersValue() {}
+func (TextParameters) typeParametersValue()          {}
 
 type CommitParameters struct {
 	RepoRevs           *RepositoryRevisions
@@ -89,6 +90,28 @@ type SymbolsParameters struct {
 	First int
 }
 
+// DocumentationParameters are the parameters of a search over the API
+// documentation of precise code intelligence indexes (type:docs).
+type DocumentationParameters struct {
+	// Query is the search query. It is matched against the names, signatures,
+	// and doc comments of documented symbols.
+	Query string
+
+	// RepoIDs restricts results to documentation of the given repositories.
+	RepoIDs []api.RepoID
+
+	// Languages restricts results to documentation of the given languages
+	// (lang: values). An empty slice does not restrict results.
+	Languages []string
+
+	// Kinds restricts results to documentation of symbols of the given kinds
+	// (select:symbol.<kind> values). An empty slice does not restrict results.
+	Kinds []string
+
+	// Limit is the maximum number of results to return.
+	Limit int
+}
+
 // GlobalSearchMode designates code paths which optimize performance for global
 // searches, i.e., literal or regexp, indexed searches without repo: filter.
 type GlobalSearchMode int
@@ -4,6 +4,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
+type BitbucketCloudConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.BitbucketCloudConnection
+}
+
 type BitbucketServerConnection struct {
 	// The unique resource identifier of the external service.
 	URN string
@@ -22,6 +28,12 @@ type GitLabConnection struct {
 	*schema.GitLabConnection
 }
 
+type GitoliteConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GitoliteConnection
+}
+
 type PerforceConnection struct {
 	// The unique resource identifier of the external service.
 	URN string
@@ -9,6 +9,7 @@
 package types
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -66,6 +67,24 @@ func (e *ExternalService) RedactConfigSecrets() (string, error) {
 		newCfg, err = redactField(e.Config, []string{"url"})
 	case *schema.JVMPackagesConnection:
 		newCfg, err = e.Config, nil
+	case *schema.MercurialConnection:
+		var fields [][]string
+		if cfg.Password != "" {
+			fields = append(fields, []string{"password"})
+		}
+		if urlHasUserinfo(cfg.Url) {
+			fields = append(fields, []string{"url"})
+		}
+		newCfg, err = redactField(e.Config, fields...)
+	case *schema.SubversionConnection:
+		var fields [][]string
+		if cfg.Password != "" {
+			fields = append(fields, []string{"password"})
+		}
+		if urlHasUserinfo(cfg.Url) {
+			fields = append(fields, []string{"url"})
+		}
+		newCfg, err = redactField(e.Config, fields...)
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("RedactExternalServiceConfig: kind %q not implemented", e.Kind)
@@ -76,6 +95,13 @@ func (e *ExternalService) RedactConfigSecrets() (string, error) {
 	return newCfg, nil
 }
 
+// urlHasUserinfo returns true if the given URL contains a username or password, or if it
+// can't be parsed and might contain one.
+func urlHasUserinfo(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err != nil || u.User != nil
+}
+
 // redactField will unmarshal the passed JSON string into the passed value, and then replace the pointer fields you pass
 // with RedactedSecret, see RedactExternalServiceConfig for usage examples.
 // who needs generics anyway?
@@ -147,6 +173,20 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{[]string{"url"}, &cfg.Url})
 	case *schema.JVMPackagesConnection:
 		unredacted, err = e.Config, nil
+	case *schema.MercurialConnection:
+		// The url is redacted if it contains a username or password.
+		fields := []jsonStringField{{[]string{"url"}, &cfg.Url}}
+		if cfg.Password != "" {
+			fields = append(fields, jsonStringField{[]string{"password"}, &cfg.Password})
+		}
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
+	case *schema.SubversionConnection:
+		// The url is redacted if it contains a username or password.
+		fields := []jsonStringField{{[]string{"url"}, &cfg.Url}}
+		if cfg.Password != "" {
+			fields = append(fields, jsonStringField{[]string{"password"}, &cfg.Password})
+		}
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	default:
 		// return an error here, it's safer to fail than to incorrectly return unsafe data.
 		err = errors.Errorf("UnRedactExternalServiceConfig: kind %q not implemented", e.Kind)
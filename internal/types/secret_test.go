@@ -55,6 +55,24 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 		Url:                   someSecret,
 		RepositoryPathPattern: "foo",
 	}
+	mercurialConfig := schema.MercurialConnection{
+		Url:      "https://hg.example.com",
+		Username: "admin",
+		Password: someSecret,
+	}
+	mercurialConfigWithUserinfo := schema.MercurialConnection{
+		Url:                   "https://admin:" + someSecret + "@hg.example.com",
+		RepositoryPathPattern: "foo",
+	}
+	subversionConfig := schema.SubversionConnection{
+		Url:      "https://svn.example.com",
+		Username: "admin",
+		Password: someSecret,
+	}
+	subversionConfigWithUserinfo := schema.SubversionConnection{
+		Url:                   "svn://admin:" + someSecret + "@svn.example.com",
+		RepositoryPathPattern: "foo",
+	}
 	var tc = []struct {
 		kind        string
 		config      interface{} // the config for the service kind
@@ -127,6 +145,30 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 			editField:   &otherConfig.RepositoryPathPattern,
 			secretField: &otherConfig.Url,
 		},
+		{
+			kind:        extsvc.KindMercurial,
+			config:      &mercurialConfig,
+			editField:   &mercurialConfig.Url,
+			secretField: &mercurialConfig.Password,
+		},
+		{
+			kind:        extsvc.KindMercurial,
+			config:      &mercurialConfigWithUserinfo,
+			editField:   &mercurialConfigWithUserinfo.RepositoryPathPattern,
+			secretField: &mercurialConfigWithUserinfo.Url,
+		},
+		{
+			kind:        extsvc.KindSubversion,
+			config:      &subversionConfig,
+			editField:   &subversionConfig.Url,
+			secretField: &subversionConfig.Password,
+		},
+		{
+			kind:        extsvc.KindSubversion,
+			config:      &subversionConfigWithUserinfo,
+			editField:   &subversionConfigWithUserinfo.RepositoryPathPattern,
+			secretField: &subversionConfigWithUserinfo.Url,
+		},
 	}
 	for _, c := range tc {
 		t.Run(c.kind, func(t *testing.T) {
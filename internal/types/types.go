@@ -458,6 +458,9 @@ type GitserverRepo struct {
 	LastError   string
 	LastFetched time.Time
 	UpdatedAt   time.Time
+	// The shard that served the repo before it was moved to ShardID, or empty
+	// if the repo is not being moved between shards
+	RebalancingFrom string
 }
 
 // ExternalService is a connection to an external service.
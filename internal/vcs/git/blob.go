@@ -11,6 +11,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
 )
@@ -31,6 +32,9 @@ func ReadFile(ctx context.Context, repo api.RepoName, commit api.CommitID, name
 	}
 
 	name = util.Rel(name)
+	if err := checkSubRepoPermission(ctx, repo, name); err != nil {
+		return nil, err
+	}
 	b, err := readFileBytes(ctx, repo, commit, name, maxBytes)
 	if err != nil {
 		return nil, err
@@ -50,6 +54,9 @@ func NewFileReader(ctx context.Context, repo api.RepoName, commit api.CommitID,
 	defer span.Finish()
 
 	name = util.Rel(name)
+	if err := checkSubRepoPermission(ctx, repo, name); err != nil {
+		return nil, err
+	}
 	br, err := newBlobReader(ctx, repo, commit, name)
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting blobReader for %q", name)
@@ -58,21 +65,34 @@ func NewFileReader(ctx context.Context, repo api.RepoName, commit api.CommitID,
 }
 
 func readFileBytes(ctx context.Context, repo api.RepoName, commit api.CommitID, name string, maxBytes int64) ([]byte, error) {
-	br, err := newBlobReader(ctx, repo, commit, name)
-	if err != nil {
+	if err := ensureAbsoluteCommit(commit); err != nil {
 		return nil, err
 	}
-	defer br.Close()
 
-	r := io.Reader(br)
-	if maxBytes > 0 {
-		r = io.LimitReader(r, maxBytes)
-	}
-	data, err := io.ReadAll(r)
+	resp, err := gitserver.DefaultClient.ReadBlob(ctx, &protocol.ReadBlobRequest{
+		Repo:     repo,
+		Commit:   commit,
+		Path:     name,
+		MaxBytes: maxBytes,
+	})
 	if err != nil {
+		if gitserver.IsRPCError(err, protocol.RPCErrorPathNotFound) {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		if strings.Contains(err.Error(), "fatal: bad object ") {
+			// Could be a git submodule.
+			fi, statErr := Stat(ctx, repo, commit, name)
+			if statErr != nil {
+				return nil, statErr
+			}
+			// Return zero content for a submodule for now
+			if fi.Mode()&ModeSubmodule != 0 {
+				return nil, nil
+			}
+		}
 		return nil, err
 	}
-	return data, nil
+	return resp.Content, nil
 }
 
 // blobReader, which should be created using newBlobReader, is a struct that allows
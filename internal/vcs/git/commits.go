@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -166,6 +167,44 @@ func Commits(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*Com
 	return commitLog(ctx, repo, opt)
 }
 
+// CommitFiles returns the paths of the files which the commit adds, modifies or deletes
+// compared to any of its parents. Unlike LsFiles, the paths are not filtered by sub-repo
+// permissions, so that callers can check whether the actor can read all of them.
+func CommitFiles(ctx context.Context, repo api.RepoName, commit api.CommitID) ([]string, error) {
+	if Mocks.CommitFiles != nil {
+		return Mocks.CommitFiles(repo, commit)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: CommitFiles")
+	span.SetTag("Commit", commit)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(string(commit)); err != nil {
+		return nil, err
+	}
+
+	// -m compares merge commits with each of their parents, and --root compares root
+	// commits with the empty tree. Without rename detection, both paths of a renamed file
+	// are listed.
+	cmd := gitserver.DefaultClient.Command("git", "diff-tree", "-z", "-r", "-m", "--root", "--no-renames", "--no-commit-id", "--name-only", string(commit), "--")
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+
+	seen := map[string]bool{}
+	var paths []string
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // HasCommitAfter indicates the staleness of a repository. It returns a boolean indicating if a repository
 // contains a commit past a specified date.
 func HasCommitAfter(ctx context.Context, repo api.RepoName, date string, revspec string) (bool, error) {
@@ -191,97 +230,65 @@ func HasCommitAfter(ctx context.Context, repo api.RepoName, date string, revspec
 	return n > 0, err
 }
 
-func isBadObjectErr(output, obj string) bool {
-	return output == "fatal: bad object "+obj
-}
-
 // commitLog returns a list of commits.
 //
 // The caller is responsible for doing checkSpecArgSafety on opt.Head and opt.Base.
 func commitLog(ctx context.Context, repo api.RepoName, opt CommitsOptions) (commits []*Commit, err error) {
-	args, err := commitLogArgs([]string{"log", logFormatWithoutRefs}, opt)
-	if err != nil {
+	if err := checkSpecArgSafety(opt.Range); err != nil {
 		return nil, err
 	}
-
-	cmd := gitserver.DefaultClient.Command("git", args...)
-	cmd.Repo = repo
-	if !opt.NoEnsureRevision {
-		cmd.EnsureRevision = opt.Range
-	}
-	return runCommitLog(ctx, cmd, opt)
+	return runCommitLog(ctx, repo, opt)
 }
 
-// runCommitLog sends the git command to gitserver. It interprets missing
+// runCommitLog sends the log request to gitserver. It interprets missing
 // revision responses and converts them into RevisionNotFoundError.
 // It is declared as a variable so that we can swap it out in tests
-var runCommitLog = func(ctx context.Context, cmd *gitserver.Cmd, opt CommitsOptions) ([]*Commit, error) {
-	data, stderr, err := cmd.DividedOutput(ctx)
+var runCommitLog = func(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*Commit, error) {
+	resp, err := gitserver.DefaultClient.Log(ctx, logRequest(repo, opt))
 	if err != nil {
-		data = bytes.TrimSpace(data)
-		if isBadObjectErr(string(stderr), opt.Range) {
-			return nil, &gitserver.RevisionNotFoundError{Repo: cmd.Repo, Spec: opt.Range}
+		if gitserver.IsRPCError(err, protocol.RPCErrorRevisionNotFound) {
+			return nil, &gitserver.RevisionNotFoundError{Repo: repo, Spec: opt.Range}
 		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, data))
+		return nil, err
 	}
 
-	allParts := bytes.Split(data, []byte{'\x00'})
-	numCommits := len(allParts) / partsPerCommit
-	commits := make([]*Commit, 0, numCommits)
-	for len(data) > 0 {
-		var commit *Commit
-		var err error
-		commit, _, data, err = parseCommitFromLog(data)
-		if err != nil {
-			return nil, err
+	commits := make([]*Commit, len(resp.Commits))
+	for i, c := range resp.Commits {
+		committer := Signature(c.Committer)
+		commits[i] = &Commit{
+			ID:        c.ID,
+			Author:    Signature(c.Author),
+			Committer: &committer,
+			Message:   Message(c.Message),
+			Parents:   c.Parents,
 		}
-		commits = append(commits, commit)
 	}
 	return commits, nil
 }
 
+// logRequest returns the gitserver request listing the commits matching opt.
+func logRequest(repo api.RepoName, opt CommitsOptions) *protocol.LogRequest {
+	return &protocol.LogRequest{
+		Repo:           repo,
+		Range:          opt.Range,
+		N:              opt.N,
+		Skip:           opt.Skip,
+		MessageQuery:   opt.MessageQuery,
+		Author:         opt.Author,
+		After:          opt.After,
+		Before:         opt.Before,
+		Reverse:        opt.Reverse,
+		DateOrder:      opt.DateOrder,
+		Path:           opt.Path,
+		EnsureRevision: !opt.NoEnsureRevision,
+	}
+}
+
 func commitLogArgs(initialArgs []string, opt CommitsOptions) (args []string, err error) {
 	if err := checkSpecArgSafety(opt.Range); err != nil {
 		return nil, err
 	}
-
-	args = initialArgs
-	if opt.N != 0 {
-		args = append(args, "-n", strconv.FormatUint(uint64(opt.N), 10))
-	}
-	if opt.Skip != 0 {
-		args = append(args, "--skip="+strconv.FormatUint(uint64(opt.Skip), 10))
-	}
-
-	if opt.Author != "" {
-		args = append(args, "--fixed-strings", "--author="+opt.Author)
-	}
-
-	if opt.After != "" {
-		args = append(args, "--after="+opt.After)
-	}
-	if opt.Before != "" {
-		args = append(args, "--before="+opt.Before)
-	}
-	if opt.Reverse {
-		args = append(args, "--reverse")
-	}
-	if opt.DateOrder {
-		args = append(args, "--date-order")
-	}
-
-	if opt.MessageQuery != "" {
-		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+opt.MessageQuery)
-	}
-
-	if opt.Range != "" {
-		args = append(args, opt.Range)
-	}
-
-	if opt.Path != "" {
-		args = append(args, "--", opt.Path)
-	}
-	return args, nil
+	return append(initialArgs, logRequest("", opt).FilterArgs()...), nil
 }
 
 // CommitCount returns the number of commits that would be returned by Commits.
@@ -402,9 +409,6 @@ const (
 
 	// include refs (slow on repos with many refs)
 	logFormatWithRefs = "--format=format:%H%x00%D%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
-
-	// don't include refs (faster, should be used if refs are not needed)
-	logFormatWithoutRefs = "--format=format:%H%x00%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
 )
 
 // parseCommitFromLog parses the next commit from data and returns the commit and the remaining
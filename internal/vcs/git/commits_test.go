@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -54,10 +56,10 @@ func TestRepository_GetCommit(t *testing.T) {
 		t.Cleanup(func() {
 			runCommitLog = oldRunCommitLog
 		})
-		runCommitLog = func(ctx context.Context, cmd *gitserver.Cmd, opt CommitsOptions) ([]*Commit, error) {
+		runCommitLog = func(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*Commit, error) {
 			// Track the value of NoEnsureRevision we pass to gitserver
 			noEnsureRevision = opt.NoEnsureRevision
-			return oldRunCommitLog(ctx, cmd, opt)
+			return oldRunCommitLog(ctx, repo, opt)
 		}
 
 		resolveRevisionOptions := ResolveRevisionOptions{
@@ -529,6 +531,49 @@ func TestRepository_Commits_options_path(t *testing.T) {
 	}
 }
 
+func TestCommitFiles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	gitCommands := []string{
+		"echo a > a",
+		"echo b > b",
+		"git add a b",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m root --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git tag root",
+		"git mv a moved",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit -m move --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"git tag move",
+		"git checkout -b other root",
+		"echo c > c",
+		"git add c",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m other --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
+		"git checkout master",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git merge --no-edit other",
+	}
+	repo := MakeGitRepository(t, gitCommands...)
+
+	tests := map[string][]string{
+		"root":   {"a", "b"},
+		"move":   {"a", "moved"},
+		"master": {"a", "c", "moved"}, // the merge, compared to both parents
+	}
+	for rev, want := range tests {
+		commit, err := ResolveRevision(ctx, repo, rev, ResolveRevisionOptions{})
+		if err != nil {
+			t.Fatalf("%s: ResolveRevision: %s", rev, err)
+		}
+		paths, err := CommitFiles(ctx, repo, commit)
+		if err != nil {
+			t.Fatalf("%s: CommitFiles: %s", rev, err)
+		}
+		sort.Strings(paths)
+		if diff := cmp.Diff(want, paths); diff != "" {
+			t.Errorf("%s: unexpected paths (-want +got):\n%s", rev, diff)
+		}
+	}
+}
+
 // Test we return errLogOnelineBatchScannerClosed is returned. It is very
 // complicated to ensure we cover the code paths we care about.
 func TestLogOnelineBatchScanner_batchclosed(t *testing.T) {
@@ -1,14 +1,18 @@
 package git
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 )
 
 type DiffOptions struct {
@@ -36,30 +40,39 @@ func Diff(ctx context.Context, opts DiffOptions) (*DiffFileIterator, error) {
 		return nil, errors.Errorf("invalid diff range argument: %q", rangeSpec)
 	}
 
-	rdr, err := ExecReader(ctx, opts.Repo, []string{
-		"diff",
-		"--find-renames",
-		// TODO(eseliger): Enable once we have support for copy detection in go-diff
-		// and actually expose a `isCopy` field in the api, otherwise this
-		// information is thrown away anyways.
-		// "--find-copies",
-		"--full-index",
-		"--inter-hunk-context=3",
-		"--no-prefix",
-		rangeSpec,
-		"--",
-	})
+	patch, err := diffPatch(ctx, opts.Repo, rangeSpec)
 	if err != nil {
 		return nil, errors.Wrap(err, "executing git diff")
 	}
 
+	rdr := io.NopCloser(bytes.NewReader(patch))
 	return &DiffFileIterator{
+		ctx:  ctx,
+		repo: opts.Repo,
 		rdr:  rdr,
 		mfdr: diff.NewMultiFileDiffReader(rdr),
 	}, nil
 }
 
+func diffPatch(ctx context.Context, repo api.RepoName, rangeSpec string) ([]byte, error) {
+	if Mocks.Diff != nil {
+		return Mocks.Diff(repo, rangeSpec)
+	}
+
+	resp, err := gitserver.DefaultClient.Diff(ctx, &protocol.DiffRequest{
+		Repo:  repo,
+		Range: rangeSpec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Patch, nil
+}
+
 type DiffFileIterator struct {
+	// ctx and repo are used to check the sub-repo permissions of the files.
+	ctx  context.Context
+	repo api.RepoName
 	rdr  io.ReadCloser
 	mfdr *diff.MultiFileDiffReader
 }
@@ -69,7 +82,37 @@ func (i *DiffFileIterator) Close() error {
 }
 
 // Next returns the next file diff. If no more diffs are available, the diff
-// will be nil and the error will be io.EOF.
+// will be nil and the error will be io.EOF. Diffs of files which the actor
+// can't read because of sub-repo permissions are skipped.
 func (i *DiffFileIterator) Next() (*diff.FileDiff, error) {
-	return i.mfdr.ReadFile()
+	for {
+		fd, err := i.mfdr.ReadFile()
+		if err != nil {
+			return fd, err
+		}
+
+		canRead, err := i.canReadFileDiff(fd)
+		if err != nil {
+			return nil, err
+		}
+		if canRead {
+			return fd, nil
+		}
+	}
+}
+
+// canReadFileDiff returns whether the actor can read both the original and the
+// new file of the diff.
+func (i *DiffFileIterator) canReadFileDiff(fd *diff.FileDiff) (bool, error) {
+	for _, name := range []string{fd.OrigName, fd.NewName} {
+		if name == "" || name == "/dev/null" {
+			continue
+		}
+		if err := checkSubRepoPermission(i.ctx, i.repo, name); os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
 }
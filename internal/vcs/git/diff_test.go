@@ -3,10 +3,11 @@ package git
 import (
 	"context"
 	"io"
-	"strings"
 	"testing"
 
 	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 func TestDiff(t *testing.T) {
@@ -38,10 +39,9 @@ func TestDiff(t *testing.T) {
 			{opts: DiffOptions{Base: "foo", Head: "bar"}, want: "foo...bar"},
 		} {
 			t.Run("rangeSpec: "+tc.want, func(t *testing.T) {
-				Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
-					// The range spec is the sixth argument.
-					if args[5] != tc.want {
-						t.Errorf("unexpected rangeSpec: have: %s; want: %s", args[5], tc.want)
+				Mocks.Diff = func(repo api.RepoName, rangeSpec string) ([]byte, error) {
+					if rangeSpec != tc.want {
+						t.Errorf("unexpected rangeSpec: have: %s; want: %s", rangeSpec, tc.want)
 					}
 					return nil, nil
 				}
@@ -50,9 +50,9 @@ func TestDiff(t *testing.T) {
 		}
 	})
 
-	t.Run("ExecReader error", func(t *testing.T) {
-		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
-			return nil, errors.New("ExecReader error")
+	t.Run("gitserver error", func(t *testing.T) {
+		Mocks.Diff = func(repo api.RepoName, rangeSpec string) ([]byte, error) {
+			return nil, errors.New("gitserver error")
 		}
 		defer ResetMocks()
 
@@ -131,8 +131,8 @@ index 9bd8209..d2acfa9 100644
 			"README.md",
 		}
 
-		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
-			return io.NopCloser(strings.NewReader(testDiff)), nil
+		Mocks.Diff = func(repo api.RepoName, rangeSpec string) ([]byte, error) {
+			return []byte(testDiff), nil
 		}
 		defer ResetMocks()
 
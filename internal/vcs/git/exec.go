@@ -164,20 +164,3 @@ func isAllowedGitCmd(args []string) bool {
 	}
 	return true
 }
-
-func gitserverCmdFunc(repo api.RepoName) cmdFunc {
-	return func(args []string) cmd {
-		cmd := gitserver.DefaultClient.Command("git", args...)
-		cmd.Repo = repo
-		return cmd
-	}
-}
-
-// cmdFunc is a func that creates a new executable Git command.
-type cmdFunc func(args []string) cmd
-
-// cmd is an executable Git command.
-type cmd interface {
-	Output(context.Context) ([]byte, error)
-	String() string
-}
@@ -15,16 +15,19 @@ var Mocks, emptyMocks struct {
 	GetCommit        func(api.CommitID) (*Commit, error)
 	ExecSafe         func(params []string) (stdout, stderr []byte, exitCode int, err error)
 	ExecReader       func(args []string) (reader io.ReadCloser, err error)
+	Diff             func(repo api.RepoName, rangeSpec string) (patch []byte, err error)
 	RawLogDiffSearch func(opt RawLogDiffSearchOptions) ([]*LogCommitSearchResult, bool, error)
 	NewFileReader    func(commit api.CommitID, name string) (io.ReadCloser, error)
 	ReadFile         func(commit api.CommitID, name string) ([]byte, error)
 	ReadDir          func(commit api.CommitID, name string, recurse bool) ([]fs.FileInfo, error)
 	LsFiles          func(repo api.RepoName, commit api.CommitID) ([]string, error)
+	CommitFiles      func(repo api.RepoName, commit api.CommitID) ([]string, error)
 	ResolveRevision  func(spec string, opt ResolveRevisionOptions) (api.CommitID, error)
 	Stat             func(commit api.CommitID, name string) (fs.FileInfo, error)
 	GetObject        func(objectName string) (OID, ObjectType, error)
 	Commits          func(repo api.RepoName, opt CommitsOptions) ([]*Commit, error)
 	MergeBase        func(repo api.RepoName, a, b api.CommitID) (api.CommitID, error)
+	GetDefaultBranch func(repo api.RepoName) (refName string, commit api.CommitID, err error)
 }
 
 // ResetMocks clears the mock functions set on Mocks (so that subsequent tests don't inadvertently
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +14,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
@@ -166,7 +166,7 @@ func ListBranches(ctx context.Context, repo api.RepoName, opt BranchesOptions) (
 		f.add(b)
 	}
 
-	refs, err := showRef(ctx, repo, "--heads")
+	refs, err := showRef(ctx, repo, true)
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +214,47 @@ func branches(ctx context.Context, repo api.RepoName, args ...string) ([]string,
 	return branches, nil
 }
 
+// GetDefaultBranch returns the name of the default branch and the commit it's
+// currently at from the given repository.
+//
+// If the repository is empty or currently being cloned, empty values and no
+// error are returned.
+func GetDefaultBranch(ctx context.Context, repo api.RepoName) (refName string, commit api.CommitID, err error) {
+	if Mocks.GetDefaultBranch != nil {
+		return Mocks.GetDefaultBranch(repo)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: GetDefaultBranch")
+	span.SetTag("Repo", repo)
+	defer span.Finish()
+
+	cmd := gitserver.DefaultClient.Command("git", "symbolic-ref", "HEAD")
+	cmd.Repo = repo
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		if vcs.IsRepoNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", errors.Errorf("exec %v in %s failed: %v (output follows)\n\n%s", cmd.Args, cmd.Repo, err, out)
+	}
+
+	refName = string(bytes.TrimSpace(out))
+	if refName == "" {
+		return "", "", nil
+	}
+
+	commit, err = ResolveRevision(ctx, repo, refName, ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
+			// Empty repository.
+			return "", "", nil
+		}
+		return "", "", err
+	}
+
+	return refName, commit, nil
+}
+
 // GetBehindAhead returns the behind/ahead commit counts information for right vs. left (both Git
 // revspecs).
 func GetBehindAhead(ctx context.Context, repo api.RepoName, left, right string) (*BehindAhead, error) {
@@ -294,17 +335,11 @@ func parseTags(in []byte) ([]*Tag, error) {
 	return tags, nil
 }
 
-type byteSlices [][]byte
-
-func (p byteSlices) Len() int           { return len(p) }
-func (p byteSlices) Less(i, j int) bool { return bytes.Compare(p[i], p[j]) < 0 }
-func (p byteSlices) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-
 // ListRefs returns a list of all refs in the repository.
 func ListRefs(ctx context.Context, repo api.RepoName) ([]Ref, error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Git: ListRefs")
 	defer span.Finish()
-	return showRef(ctx, repo)
+	return showRef(ctx, repo, false)
 }
 
 // Ref describes a Git ref.
@@ -313,34 +348,18 @@ type Ref struct {
 	CommitID api.CommitID
 }
 
-func showRef(ctx context.Context, repo api.RepoName, args ...string) ([]Ref, error) {
-	cmd := gitserver.DefaultClient.Command("git", "show-ref")
-	cmd.Args = append(cmd.Args, args...)
-	cmd.Repo = repo
-	out, err := cmd.CombinedOutput(ctx)
+func showRef(ctx context.Context, repo api.RepoName, headsOnly bool) ([]Ref, error) {
+	resp, err := gitserver.DefaultClient.ListRefs(ctx, &protocol.ListRefsRequest{
+		Repo:      repo,
+		HeadsOnly: headsOnly,
+	})
 	if err != nil {
-		if vcs.IsRepoNotExist(err) {
-			return nil, err
-		}
-		// Exit status of 1 and no output means there were no
-		// results. This is not a fatal error.
-		if cmd.ExitStatus == 1 && len(out) == 0 {
-			return nil, nil
-		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+		return nil, err
 	}
 
-	out = bytes.TrimSuffix(out, []byte("\n")) // remove trailing newline
-	lines := bytes.Split(out, []byte("\n"))
-	sort.Sort(byteSlices(lines)) // sort for consistency
-	refs := make([]Ref, len(lines))
-	for i, line := range lines {
-		if len(line) <= 41 {
-			return nil, errors.New("unexpectedly short (<=41 bytes) line in `git show-ref ...` output")
-		}
-		id := line[:40]
-		name := line[41:]
-		refs[i] = Ref{Name: string(name), CommitID: api.CommitID(id)}
+	var refs []Ref
+	for _, ref := range resp.Refs {
+		refs = append(refs, Ref{Name: ref.Name, CommitID: ref.CommitID})
 	}
 	return refs, nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
@@ -79,18 +80,21 @@ func ResolveRevision(ctx context.Context, repo api.RepoName, spec string, opt Re
 		spec = spec + "^0"
 	}
 
-	cmd := gitserver.DefaultClient.Command("git", "rev-parse", spec)
-	cmd.Repo = repo
-	cmd.EnsureRevision = spec
-
 	// We don't ever need to ensure that HEAD is in git-server.
 	// HEAD is always there once a repo is cloned
 	// (except empty repos, but we don't need to ensure revision on those).
-	if opt.NoEnsureRevision || spec == "HEAD" {
-		cmd.EnsureRevision = ""
+	resp, err := gitserver.DefaultClient.ResolveRevision(ctx, &protocol.ResolveRevisionRequest{
+		Repo:           repo,
+		Spec:           spec,
+		EnsureRevision: !opt.NoEnsureRevision && spec != "HEAD",
+	})
+	if err != nil {
+		if gitserver.IsRPCError(err, protocol.RPCErrorRevisionNotFound) {
+			return "", &gitserver.RevisionNotFoundError{Repo: repo, Spec: spec}
+		}
+		return "", err
 	}
-
-	return runRevParse(ctx, cmd, spec)
+	return checkResolvedCommit(repo, spec, resp.CommitID)
 }
 
 type BadCommitError struct {
@@ -116,16 +120,21 @@ func runRevParse(ctx context.Context, cmd *gitserver.Cmd, spec string) (api.Comm
 		}
 		return "", errors.WithMessage(err, fmt.Sprintf("git command %v failed (stderr: %q)", cmd.Args, stderr))
 	}
-	commit := api.CommitID(bytes.TrimSpace(stdout))
+	return checkResolvedCommit(cmd.Repo, spec, api.CommitID(bytes.TrimSpace(stdout)))
+}
+
+// checkResolvedCommit returns the commit spec was resolved to by git rev-parse,
+// or an error if it isn't an absolute commit.
+func checkResolvedCommit(repo api.RepoName, spec string, commit api.CommitID) (api.CommitID, error) {
 	if !IsAbsoluteRevision(string(commit)) {
 		if commit == "HEAD" {
 			// We don't verify the existence of HEAD (see above comments), but
 			// if HEAD doesn't point to anything git just returns `HEAD` as the
 			// output of rev-parse. An example where this occurs is an empty
 			// repository.
-			return "", &gitserver.RevisionNotFoundError{Repo: cmd.Repo, Spec: spec}
+			return "", &gitserver.RevisionNotFoundError{Repo: repo, Spec: spec}
 		}
-		return "", BadCommitError{Spec: spec, Commit: commit, Repo: cmd.Repo}
+		return "", BadCommitError{Spec: spec, Commit: commit, Repo: repo}
 	}
 	return commit, nil
 }
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"context"
+	"io/fs"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+// checkSubRepoPermission returns the same error as for a missing file if the
+// actor of the context can't read the path of the repository because of
+// sub-repo permissions, so that restricted paths are indistinguishable from
+// missing ones.
+func checkSubRepoPermission(ctx context.Context, repo api.RepoName, path string) error {
+	perms, err := authz.ActorPermissions(ctx, authz.DefaultSubRepoPermsChecker, actor.FromContext(ctx), authz.RepoContent{
+		Repo: repo,
+		Path: path,
+	})
+	if err != nil {
+		return err
+	}
+	if !perms.Include(authz.Read) {
+		return &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+// filterFileInfos returns the files and directories which the actor of the
+// context can read because of sub-repo permissions. The names of fis must be
+// paths relative to the root of the repository.
+func filterFileInfos(ctx context.Context, repo api.RepoName, fis []fs.FileInfo) ([]fs.FileInfo, error) {
+	a := actor.FromContext(ctx)
+	if enabled, err := authz.ActorEnabledForRepo(ctx, authz.DefaultSubRepoPermsChecker, a, repo); err != nil {
+		return nil, err
+	} else if !enabled {
+		return fis, nil
+	}
+
+	filtered := make([]fs.FileInfo, 0, len(fis))
+	for _, fi := range fis {
+		path := fi.Name()
+		if fi.IsDir() {
+			path += "/"
+		}
+		perms, err := authz.ActorPermissions(ctx, authz.DefaultSubRepoPermsChecker, a, authz.RepoContent{Repo: repo, Path: path})
+		if err != nil {
+			return nil, err
+		}
+		if perms.Include(authz.Read) {
+			filtered = append(filtered, fi)
+		}
+	}
+	return filtered, nil
+}
@@ -8,7 +8,6 @@ import (
 	"os"
 	stdlibpath "path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,8 +17,11 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/golang/groupcache/lru"
 
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
 )
@@ -55,6 +57,12 @@ func Lstat(ctx context.Context, repo api.RepoName, commit api.CommitID, path str
 		return nil, &os.PathError{Op: "ls-tree", Path: path, Err: os.ErrNotExist}
 	}
 
+	if fis, err = filterFileInfos(ctx, repo, fis[:1]); err != nil {
+		return nil, err
+	} else if len(fis) == 0 {
+		return nil, &os.PathError{Op: "ls-tree", Path: path, Err: os.ErrNotExist}
+	}
+
 	return fis[0], nil
 }
 
@@ -120,7 +128,11 @@ func ReadDir(ctx context.Context, repo api.RepoName, commit api.CommitID, path s
 		// to list the dir's tree entry in its parent dir).
 		path = filepath.Clean(util.Rel(path)) + "/"
 	}
-	return lsTree(ctx, repo, commit, path, recurse)
+	fis, err := lsTree(ctx, repo, commit, path, recurse)
+	if err != nil {
+		return nil, err
+	}
+	return filterFileInfos(ctx, repo, fis)
 }
 
 // lsTreeRootCache caches the result of running `git ls-tree ...` on a repository's root path
@@ -150,7 +162,8 @@ func LsFiles(ctx context.Context, repo api.RepoName, commit api.CommitID) ([]str
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
 	}
-	return strings.Split(string(out), "\x00"), nil
+	files := strings.Split(string(out), "\x00")
+	return authz.FilterActorPaths(ctx, authz.DefaultSubRepoPermsChecker, actor.FromContext(ctx), repo, files)
 }
 
 // lsTree returns ls of tree at path.
@@ -200,30 +213,20 @@ func lsTreeUncached(ctx context.Context, repo api.RepoName, commit api.CommitID,
 		return nil, err
 	}
 
-	args := []string{
-		"ls-tree",
-		"--long", // show size
-		"--full-name",
-		"-z",
-		string(commit),
-	}
-	if recurse {
-		args = append(args, "-r", "-t")
-	}
-	if path != "" {
-		args = append(args, "--", filepath.ToSlash(path))
-	}
-	cmd := gitserver.DefaultClient.Command("git", args...)
-	cmd.Repo = repo
-	out, err := cmd.CombinedOutput(ctx)
+	resp, err := gitserver.DefaultClient.ListTree(ctx, &protocol.ListTreeRequest{
+		Repo:      repo,
+		Commit:    commit,
+		Path:      filepath.ToSlash(path),
+		Recursive: recurse,
+	})
 	if err != nil {
-		if bytes.Contains(out, []byte("exists on disk, but not in")) {
+		if gitserver.IsRPCError(err, protocol.RPCErrorPathNotFound) {
 			return nil, &os.PathError{Op: "ls-tree", Path: filepath.ToSlash(path), Err: os.ErrNotExist}
 		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+		return nil, err
 	}
 
-	if len(out) == 0 {
+	if len(resp.Entries) == 0 {
 		// If we are listing the empty root tree, we will have no output.
 		if stdlibpath.Clean(path) == "." {
 			return []fs.FileInfo{}, nil
@@ -232,56 +235,32 @@ func lsTreeUncached(ctx context.Context, repo api.RepoName, commit api.CommitID,
 	}
 
 	trimPath := strings.TrimPrefix(path, "./")
-	lines := strings.Split(string(out), "\x00")
-	fis := make([]fs.FileInfo, len(lines)-1)
-	for i, line := range lines {
-		if i == len(lines)-1 {
-			// last entry is empty
-			continue
-		}
-
-		tabPos := strings.IndexByte(line, '\t')
-		if tabPos == -1 {
-			return nil, errors.Errorf("invalid `git ls-tree` output: %q", out)
-		}
-		info := strings.SplitN(line[:tabPos], " ", 4)
-		name := line[tabPos+1:]
+	fis := make([]fs.FileInfo, len(resp.Entries))
+	for i, entry := range resp.Entries {
+		name := entry.Path
 		if len(name) < len(trimPath) {
 			// This is in a submodule; return the original path to avoid a slice out of bounds panic
 			// when setting the FileInfo._Name below.
 			name = trimPath
 		}
 
-		if len(info) != 4 {
-			return nil, errors.Errorf("invalid `git ls-tree` output: %q", out)
-		}
-		typ := info[1]
-		sha := info[2]
-		if !IsAbsoluteRevision(sha) {
-			return nil, errors.Errorf("invalid `git ls-tree` SHA output: %q", sha)
+		if !IsAbsoluteRevision(entry.OID) {
+			return nil, errors.Errorf("invalid `git ls-tree` SHA output: %q", entry.OID)
 		}
-		oid, err := decodeOID(sha)
+		oid, err := decodeOID(entry.OID)
 		if err != nil {
 			return nil, err
 		}
 
-		sizeStr := strings.TrimSpace(info[3])
 		var size int64
-		if sizeStr != "-" {
-			// Size of "-" indicates a dir or submodule.
-			size, err = strconv.ParseInt(sizeStr, 10, 64)
-			if err != nil || size < 0 {
-				return nil, errors.Errorf("invalid `git ls-tree` size output: %q (error: %s)", sizeStr, err)
-			}
+		if entry.Size > 0 {
+			// Size of -1 indicates a dir or submodule.
+			size = entry.Size
 		}
 
 		var sys interface{}
-		modeVal, err := strconv.ParseInt(info[0], 8, 32)
-		if err != nil {
-			return nil, err
-		}
-		mode := os.FileMode(modeVal)
-		switch typ {
+		mode := os.FileMode(entry.Mode)
+		switch entry.Type {
 		case "blob":
 			const gitModeSymlink = 020000
 			if mode&gitModeSymlink != 0 {
@@ -292,10 +271,8 @@ func lsTreeUncached(ctx context.Context, repo api.RepoName, commit api.CommitID,
 			}
 		case "commit":
 			mode = mode | ModeSubmodule
-			cmd := gitserver.DefaultClient.Command("git", "show", fmt.Sprintf("%s:.gitmodules", commit))
-			cmd.Repo = repo
 			var submodule Submodule
-			if out, err := cmd.Output(ctx); err == nil {
+			if out, err := readFileBytes(ctx, repo, commit, ".gitmodules", 0); err == nil {
 
 				var cfg config.Config
 				err := config.NewDecoder(bytes.NewBuffer(out)).Decode(&cfg)
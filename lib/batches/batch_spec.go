@@ -38,14 +38,17 @@ type BatchSpec struct {
 	TransformChanges  *TransformChanges        `json:"transformChanges,omitempty" yaml:"transformChanges,omitempty"`
 	ImportChangesets  []ImportChangeset        `json:"importChangesets,omitempty" yaml:"importChangesets"`
 	ChangesetTemplate *ChangesetTemplate       `json:"changesetTemplate,omitempty" yaml:"changesetTemplate"`
+	AutoMerge         *AutoMerge               `json:"autoMerge,omitempty" yaml:"autoMerge,omitempty"`
+	RerunOnBaseChange RerunOnBaseChange        `json:"rerunOnBaseChange,omitempty" yaml:"rerunOnBaseChange,omitempty"`
 }
 
 type ChangesetTemplate struct {
-	Title     string                       `json:"title,omitempty" yaml:"title"`
-	Body      string                       `json:"body,omitempty" yaml:"body"`
-	Branch    string                       `json:"branch,omitempty" yaml:"branch"`
-	Commit    ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
-	Published *overridable.BoolOrString    `json:"published" yaml:"published"`
+	Title          string                       `json:"title,omitempty" yaml:"title"`
+	Body           string                       `json:"body,omitempty" yaml:"body"`
+	Branch         string                       `json:"branch,omitempty" yaml:"branch"`
+	Commit         ExpandedGitCommitDescription `json:"commit,omitempty" yaml:"commit"`
+	Published      *overridable.BoolOrString    `json:"published" yaml:"published"`
+	RequiredChecks []string                     `json:"requiredChecks,omitempty" yaml:"requiredChecks"`
 }
 
 type GitCommitAuthor struct {
@@ -116,10 +119,47 @@ type Group struct {
 	Repository string `json:"repository,omitempty" yaml:"repository"`
 }
 
+type AutoMerge struct {
+	Strategy   AutoMergeStrategy `json:"strategy,omitempty" yaml:"strategy"`
+	MaxPerHour int               `json:"maxPerHour,omitempty" yaml:"maxPerHour"`
+}
+
+// AutoMergeStrategy configures how changesets are merged on each code host
+// type. An empty value means that a regular merge commit is created.
+type AutoMergeStrategy struct {
+	GitHub          MergeStrategy `json:"github,omitempty" yaml:"github"`
+	GitLab          MergeStrategy `json:"gitlab,omitempty" yaml:"gitlab"`
+	BitbucketServer MergeStrategy `json:"bitbucketServer,omitempty" yaml:"bitbucketServer"`
+}
+
+type MergeStrategy string
+
+const (
+	MergeStrategyMerge  MergeStrategy = "merge"
+	MergeStrategySquash MergeStrategy = "squash"
+	MergeStrategyRebase MergeStrategy = "rebase"
+)
+
+// RerunOnBaseChange configures when the changesets of a batch change are
+// rebased onto a newer commit of their base branch.
+type RerunOnBaseChange string
+
+const (
+	// RerunOnBaseChangeConflicts rebases changesets that conflict with their
+	// base branch.
+	RerunOnBaseChangeConflicts RerunOnBaseChange = "conflicts"
+	// RerunOnBaseChangeOutdated rebases changesets whenever their base branch
+	// moves.
+	RerunOnBaseChangeOutdated RerunOnBaseChange = "outdated"
+)
+
 type ParseBatchSpecOptions struct {
 	AllowArrayEnvironments bool
 	AllowTransformChanges  bool
 	AllowConditionalExec   bool
+	AllowAutoMerge         bool
+	AllowRerunOnBaseChange bool
+	AllowRequiredChecks    bool
 }
 
 func ParseBatchSpec(data []byte, opts ParseBatchSpecOptions) (*BatchSpec, error) {
@@ -180,6 +220,18 @@ func parseBatchSpec(schema string, data []byte, opts ParseBatchSpecOptions) (*Ba
 		}
 	}
 
+	if spec.AutoMerge != nil && !opts.AllowAutoMerge {
+		errs = multierror.Append(errs, NewValidationError(errors.New("batch spec includes autoMerge, which is not supported in this Sourcegraph version")))
+	}
+
+	if spec.RerunOnBaseChange != "" && !opts.AllowRerunOnBaseChange {
+		errs = multierror.Append(errs, NewValidationError(errors.New("batch spec includes rerunOnBaseChange, which is not supported in this Sourcegraph version")))
+	}
+
+	if spec.ChangesetTemplate != nil && len(spec.ChangesetTemplate.RequiredChecks) != 0 && !opts.AllowRequiredChecks {
+		errs = multierror.Append(errs, NewValidationError(errors.New("batch spec includes changesetTemplate.requiredChecks, which is not supported in this Sourcegraph version")))
+	}
+
 	return &spec, errs.ErrorOrNil()
 }
 
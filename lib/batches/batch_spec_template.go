@@ -0,0 +1,252 @@
+package batches
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateInputType is the type of the value of a TemplateInput.
+type TemplateInputType string
+
+const (
+	TemplateInputTypeString  TemplateInputType = "string"
+	TemplateInputTypeNumber  TemplateInputType = "number"
+	TemplateInputTypeBoolean TemplateInputType = "boolean"
+)
+
+// Valid returns true if the type is known.
+func (t TemplateInputType) Valid() bool {
+	switch t {
+	case TemplateInputTypeString, TemplateInputTypeNumber, TemplateInputTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// TemplateInput describes an input of a batch spec template. Inputs are
+// referenced in the template as `${{ inputs.<name> }}`.
+type TemplateInput struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Type        TemplateInputType `json:"type"`
+	// Default is used when no value is given for the input. Inputs without a
+	// default value are required.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// Required returns true if a value needs to be given for the input when
+// instantiating the template.
+func (i TemplateInput) Required() bool {
+	return i.Default == nil
+}
+
+var templateInputNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// templateInputReference matches references to inputs. Other `${{ }}`
+// expressions, such as the ones evaluated when executing steps, are left
+// untouched.
+var templateInputReference = regexp.MustCompile(`\$\{\{\s*inputs\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// ValidateBatchSpecTemplate checks that the given inputs are well-formed and
+// that the raw template only references declared inputs.
+func ValidateBatchSpecTemplate(rawTemplate string, inputs []TemplateInput) error {
+	var errs *multierror.Error
+
+	declared := make(map[string]struct{}, len(inputs))
+	for _, in := range inputs {
+		if !templateInputNamePattern.MatchString(in.Name) {
+			errs = multierror.Append(errs, NewValidationError(errors.Errorf("input name %q can only contain word characters and must not start with a digit", in.Name)))
+			continue
+		}
+		if _, ok := declared[in.Name]; ok {
+			errs = multierror.Append(errs, NewValidationError(errors.Errorf("input %q is declared more than once", in.Name)))
+			continue
+		}
+		declared[in.Name] = struct{}{}
+
+		if !in.Type.Valid() {
+			errs = multierror.Append(errs, NewValidationError(errors.Errorf("input %q has unknown type %q", in.Name, in.Type)))
+			continue
+		}
+		if in.Default != nil {
+			if _, err := convertTemplateInputValue(in, in.Default); err != nil {
+				errs = multierror.Append(errs, NewValidationError(errors.Wrap(err, "invalid default value")))
+			}
+		}
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(rawTemplate), &doc); err != nil {
+		errs = multierror.Append(errs, NewValidationError(errors.Wrap(err, "parsing template")))
+	}
+
+	for _, m := range templateInputReference.FindAllStringSubmatch(rawTemplate, -1) {
+		if _, ok := declared[m[1]]; !ok {
+			errs = multierror.Append(errs, NewValidationError(errors.Errorf("template references undeclared input %q", m[1])))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// RenderBatchSpecTemplate substitutes the given values for the inputs
+// referenced in the raw template and returns the resulting raw batch spec.
+//
+// A YAML value that consists of nothing but a reference to an input is
+// replaced with the typed value of the input, so that e.g. numbers and
+// booleans end up as such in the batch spec. References within longer strings
+// are interpolated.
+//
+// The result still needs to be validated with ParseBatchSpec.
+func RenderBatchSpecTemplate(rawTemplate string, inputs []TemplateInput, values map[string]interface{}) (string, error) {
+	resolved, err := resolveTemplateInputValues(inputs, values)
+	if err != nil {
+		return "", err
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(rawTemplate), &doc); err != nil {
+		return "", NewValidationError(errors.Wrap(err, "parsing template"))
+	}
+
+	r := &templateRenderer{values: resolved}
+	doc = r.render(doc)
+	if r.err != nil {
+		return "", r.err
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return "", errors.Wrap(err, "marshalling batch spec")
+	}
+	if err := enc.Close(); err != nil {
+		return "", errors.Wrap(err, "marshalling batch spec")
+	}
+	return out.String(), nil
+}
+
+// resolveTemplateInputValues validates the given values against the inputs
+// and fills in default values.
+func resolveTemplateInputValues(inputs []TemplateInput, values map[string]interface{}) (map[string]interface{}, error) {
+	var errs *multierror.Error
+
+	declared := make(map[string]struct{}, len(inputs))
+	resolved := make(map[string]interface{}, len(inputs))
+	for _, in := range inputs {
+		declared[in.Name] = struct{}{}
+
+		v, ok := values[in.Name]
+		if !ok || v == nil {
+			if in.Required() {
+				errs = multierror.Append(errs, NewValidationError(errors.Errorf("missing value for required input %q", in.Name)))
+				continue
+			}
+			v = in.Default
+		}
+
+		converted, err := convertTemplateInputValue(in, v)
+		if err != nil {
+			errs = multierror.Append(errs, NewValidationError(err))
+			continue
+		}
+		resolved[in.Name] = converted
+	}
+
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			errs = multierror.Append(errs, NewValidationError(errors.Errorf("unknown input %q", name)))
+		}
+	}
+
+	return resolved, errs.ErrorOrNil()
+}
+
+func convertTemplateInputValue(in TemplateInput, v interface{}) (interface{}, error) {
+	switch in.Type {
+	case TemplateInputTypeString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case TemplateInputTypeNumber:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case float32:
+			return float64(n), nil
+		case int:
+			return float64(n), nil
+		case int32:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		}
+	case TemplateInputTypeBoolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	}
+	return nil, errors.Errorf("input %q expects a value of type %s, got %v", in.Name, in.Type, v)
+}
+
+type templateRenderer struct {
+	values map[string]interface{}
+	err    error
+}
+
+func (r *templateRenderer) render(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			rendered[r.interpolate(k)] = r.render(v)
+		}
+		return rendered
+	case []interface{}:
+		rendered := make([]interface{}, len(n))
+		for i, v := range n {
+			rendered[i] = r.render(v)
+		}
+		return rendered
+	case string:
+		if m := templateInputReference.FindStringSubmatchIndex(n); m != nil && m[0] == 0 && m[1] == len(n) {
+			return r.value(n[m[2]:m[3]])
+		}
+		return r.interpolate(n)
+	default:
+		return node
+	}
+}
+
+func (r *templateRenderer) interpolate(s string) string {
+	return templateInputReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := templateInputReference.FindStringSubmatch(ref)[1]
+		switch v := r.value(name).(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case nil:
+			return ref
+		default:
+			return fmt.Sprint(v)
+		}
+	})
+}
+
+func (r *templateRenderer) value(name string) interface{} {
+	v, ok := r.values[name]
+	if !ok {
+		if r.err == nil {
+			r.err = NewValidationError(errors.Errorf("template references undeclared input %q", name))
+		}
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,159 @@
+package batches
+
+import (
+	"strings"
+	"testing"
+)
+
+const bumpDependencyTemplate = `
+name: bump-${{ inputs.dependency }}
+description: Bump ${{ inputs.dependency }} to ${{ inputs.version }}
+on:
+  - repositoriesMatchingQuery: file:package.json ${{ inputs.dependency }}
+steps:
+  - run: yarn upgrade ${{ inputs.dependency }}@${{ inputs.version }} && echo ${{ repository.name }}
+    container: node:14
+changesetTemplate:
+  title: Bump ${{ inputs.dependency }} to ${{ inputs.version }}
+  body: Automated upgrade
+  branch: bump-${{ inputs.dependency }}
+  commit:
+    message: Bump ${{ inputs.dependency }}
+  published: ${{ inputs.publish }}
+`
+
+var bumpDependencyInputs = []TemplateInput{
+	{Name: "dependency", Type: TemplateInputTypeString},
+	{Name: "version", Type: TemplateInputTypeString},
+	{Name: "publish", Type: TemplateInputTypeBoolean, Default: false},
+}
+
+func TestValidateBatchSpecTemplate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		if err := ValidateBatchSpecTemplate(bumpDependencyTemplate, bumpDependencyInputs); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	tests := map[string]struct {
+		inputs  []TemplateInput
+		wantErr string
+	}{
+		"undeclared input": {
+			inputs:  bumpDependencyInputs[:2],
+			wantErr: `template references undeclared input "publish"`,
+		},
+		"invalid name": {
+			inputs:  append([]TemplateInput{{Name: "1st", Type: TemplateInputTypeString}}, bumpDependencyInputs...),
+			wantErr: `input name "1st" can only contain word characters`,
+		},
+		"duplicate name": {
+			inputs:  append([]TemplateInput{{Name: "version", Type: TemplateInputTypeString}}, bumpDependencyInputs...),
+			wantErr: `input "version" is declared more than once`,
+		},
+		"unknown type": {
+			inputs:  append([]TemplateInput{{Name: "count", Type: "integer"}}, bumpDependencyInputs...),
+			wantErr: `input "count" has unknown type "integer"`,
+		},
+		"default of wrong type": {
+			inputs:  append([]TemplateInput{{Name: "count", Type: TemplateInputTypeNumber, Default: "ten"}}, bumpDependencyInputs...),
+			wantErr: `input "count" expects a value of type number, got ten`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBatchSpecTemplate(bumpDependencyTemplate, tc.inputs)
+			if err == nil {
+				t.Fatal("no error returned")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("wrong error. want=%q, have=%q", tc.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestRenderBatchSpecTemplate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		raw, err := RenderBatchSpecTemplate(bumpDependencyTemplate, bumpDependencyInputs, map[string]interface{}{
+			"dependency": "lodash",
+			"version":    "4.17.21",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		spec, err := ParseBatchSpec([]byte(raw), ParseBatchSpecOptions{})
+		if err != nil {
+			t.Fatalf("rendered template is not a valid batch spec: %s\n%s", err, raw)
+		}
+
+		if have, want := spec.Name, "bump-lodash"; have != want {
+			t.Fatalf("wrong name. want=%q, have=%q", want, have)
+		}
+		if have, want := spec.ChangesetTemplate.Title, "Bump lodash to 4.17.21"; have != want {
+			t.Fatalf("wrong title. want=%q, have=%q", want, have)
+		}
+		if have, want := spec.Steps[0].Run, "yarn upgrade lodash@4.17.21 && echo ${{ repository.name }}"; have != want {
+			t.Fatalf("wrong run. want=%q, have=%q", want, have)
+		}
+		// A reference to a boolean input is substituted with a boolean, not
+		// the string "false".
+		if have := spec.ChangesetTemplate.Published.ValueWithSuffix("github.com/sourcegraph/sourcegraph", "bump-lodash"); have != false {
+			t.Fatalf("wrong published value: %#v", have)
+		}
+	})
+
+	t.Run("number inputs", func(t *testing.T) {
+		raw, err := RenderBatchSpecTemplate(
+			"name: ${{ inputs.name }}\nautoMerge:\n  maxPerHour: ${{ inputs.max }}\n",
+			[]TemplateInput{
+				{Name: "name", Type: TemplateInputTypeString},
+				{Name: "max", Type: TemplateInputTypeNumber, Default: 5},
+			},
+			map[string]interface{}{"name": "hello", "max": float64(10)},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		spec, err := ParseBatchSpec([]byte(raw), ParseBatchSpecOptions{AllowAutoMerge: true})
+		if err != nil {
+			t.Fatalf("rendered template is not a valid batch spec: %s\n%s", err, raw)
+		}
+		if have, want := spec.AutoMerge.MaxPerHour, 10; have != want {
+			t.Fatalf("wrong maxPerHour. want=%d, have=%d", want, have)
+		}
+	})
+
+	tests := map[string]struct {
+		values  map[string]interface{}
+		wantErr string
+	}{
+		"missing required input": {
+			values:  map[string]interface{}{"dependency": "lodash"},
+			wantErr: `missing value for required input "version"`,
+		},
+		"wrong type": {
+			values:  map[string]interface{}{"dependency": "lodash", "version": "1.0.0", "publish": "yes"},
+			wantErr: `input "publish" expects a value of type boolean, got yes`,
+		},
+		"unknown input": {
+			values:  map[string]interface{}{"dependency": "lodash", "version": "1.0.0", "registry": "npm"},
+			wantErr: `unknown input "registry"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := RenderBatchSpecTemplate(bumpDependencyTemplate, bumpDependencyInputs, tc.values)
+			if err == nil {
+				t.Fatal("no error returned")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("wrong error. want=%q, have=%q", tc.wantErr, err.Error())
+			}
+		})
+	}
+}
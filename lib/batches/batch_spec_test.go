@@ -170,4 +170,123 @@ changesetTemplate:
 			}
 		}
 	})
+
+	t.Run("autoMerge", func(t *testing.T) {
+		const spec = `
+name: hello-world
+description: Add Hello World to READMEs
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a $(find -name README.md)
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+autoMerge:
+  strategy:
+    github: rebase
+    gitlab: squash
+  maxPerHour: 10
+`
+
+		if _, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{}); err == nil {
+			t.Fatal("no error returned")
+		}
+
+		batchSpec, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{AllowAutoMerge: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &AutoMerge{
+			Strategy:   AutoMergeStrategy{GitHub: MergeStrategyRebase, GitLab: MergeStrategySquash},
+			MaxPerHour: 10,
+		}
+		if *batchSpec.AutoMerge != *want {
+			t.Fatalf("wrong autoMerge. want=%+v, have=%+v", want, batchSpec.AutoMerge)
+		}
+	})
+
+	t.Run("autoMerge with unsupported strategy", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+autoMerge:
+  strategy:
+    bitbucketServer: squash
+`
+
+		if _, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{AllowAutoMerge: true}); err == nil {
+			t.Fatal("no error returned")
+		}
+	})
+	t.Run("requiredChecks", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+  requiredChecks:
+    - ci/build
+    - lint
+`
+
+		if _, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{}); err == nil {
+			t.Fatal("no error returned")
+		}
+
+		batchSpec, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{AllowRequiredChecks: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"ci/build", "lint"}
+		if have := batchSpec.ChangesetTemplate.RequiredChecks; fmt.Sprint(have) != fmt.Sprint(want) {
+			t.Fatalf("wrong requiredChecks. want=%v, have=%v", want, have)
+		}
+	})
+
+	t.Run("rerunOnBaseChange", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+rerunOnBaseChange: conflicts
+`
+
+		if _, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{}); err == nil {
+			t.Fatal("no error returned")
+		}
+
+		batchSpec, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{AllowRerunOnBaseChange: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, want := batchSpec.RerunOnBaseChange, RerunOnBaseChangeConflicts; have != want {
+			t.Fatalf("wrong rerunOnBaseChange. want=%q, have=%q", want, have)
+		}
+	})
+
+	t.Run("rerunOnBaseChange with invalid value", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+rerunOnBaseChange: always
+`
+
+		if _, err := ParseBatchSpec([]byte(spec), ParseBatchSpecOptions{AllowRerunOnBaseChange: true}); err == nil {
+			t.Fatal("no error returned")
+		}
+	})
 }
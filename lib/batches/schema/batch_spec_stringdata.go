@@ -328,8 +328,56 @@ const BatchSpecJSON = `{
               }
             }
           ]
+        },
+        "requiredChecks": {
+          "type": "array",
+          "description": "The names of the checks that must pass on a changeset before it is published as ready for review or merged. On GitHub these are the names of commit status contexts and check runs, on GitLab the names of the jobs in the latest pipeline, and on Bitbucket Server the names (or keys) of the build statuses.",
+          "items": {
+            "type": "string",
+            "minLength": 1
+          },
+          "uniqueItems": true
         }
       }
+    },
+    "autoMerge": {
+      "type": "object",
+      "description": "Automatically merge the changesets of the batch change once they have been approved and all their checks have passed. Merges only happen while a rollout window configured on the Sourcegraph instance is open.",
+      "additionalProperties": false,
+      "properties": {
+        "strategy": {
+          "type": "object",
+          "description": "The merge strategy to use on each type of code host. If omitted for a code host, a regular merge commit is created.",
+          "additionalProperties": false,
+          "properties": {
+            "github": {
+              "type": "string",
+              "description": "The merge strategy to use for GitHub pull requests.",
+              "enum": ["merge", "squash", "rebase"]
+            },
+            "gitlab": {
+              "type": "string",
+              "description": "The merge strategy to use for GitLab merge requests.",
+              "enum": ["merge", "squash"]
+            },
+            "bitbucketServer": {
+              "type": "string",
+              "description": "The merge strategy to use for Bitbucket Server pull requests. Bitbucket Server uses the merge strategy configured for the repository.",
+              "enum": ["merge"]
+            }
+          }
+        },
+        "maxPerHour": {
+          "type": "integer",
+          "description": "The maximum number of changesets of this batch change that are merged per hour. If omitted, the number of merges is only limited by the rollout windows.",
+          "minimum": 1
+        }
+      }
+    },
+    "rerunOnBaseChange": {
+      "type": "string",
+      "description": "Rebase the changesets of the batch change when their base branch moves. With \"conflicts\", changesets are only rebased once they conflict with their base branch; with \"outdated\", they are rebased whenever the base branch points to a newer commit. Changesets created by a server-side execution are re-executed against the new base commit, all others have their changes re-applied to it.",
+      "enum": ["conflicts", "outdated"]
     }
   }
 }
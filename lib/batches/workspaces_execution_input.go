@@ -0,0 +1,48 @@
+package batches
+
+// WorkspacesExecutionInput is the input that's handed to `src batch exec` when
+// a single workspace of a batch spec is executed server-side. It contains
+// everything src-cli needs to run the steps in the workspace without having
+// to resolve the batch spec's `on` and `workspaces` attributes again.
+type WorkspacesExecutionInput struct {
+	// BatchChangeAttributes are the attributes of the batch change that can be
+	// used in templated fields of the steps.
+	BatchChangeAttributes BatchChangeAttributes `json:"batchChange"`
+
+	Repository         WorkspaceRepo   `json:"repository"`
+	Branch             WorkspaceBranch `json:"branch"`
+	Path               string          `json:"path"`
+	OnlyFetchWorkspace bool            `json:"onlyFetchWorkspace"`
+	Steps              []Step          `json:"steps"`
+	// SearchResultPaths are the file matches of the `on` query in this
+	// workspace, which steps can access through `repository.search_result_paths`.
+	SearchResultPaths []string `json:"searchResultPaths"`
+
+	ChangesetTemplate *ChangesetTemplate `json:"changesetTemplate,omitempty"`
+	TransformChanges  *TransformChanges  `json:"transformChanges,omitempty"`
+}
+
+// BatchChangeAttributes are the name and description of a batch change.
+type BatchChangeAttributes struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// WorkspaceRepo identifies the repository a workspace lives in.
+type WorkspaceRepo struct {
+	// ID is the GraphQL ID of the repository.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WorkspaceBranch is the branch, and the commit on it, that a workspace is
+// checked out at.
+type WorkspaceBranch struct {
+	Name   string `json:"name"`
+	Target Commit `json:"target"`
+}
+
+// Commit is a single git commit.
+type Commit struct {
+	OID string `json:"oid"`
+}
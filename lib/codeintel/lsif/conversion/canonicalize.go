@@ -45,6 +45,8 @@ func canonicalizeDocuments(state *State) {
 
 			canonicalizeDocumentsInDefinitionReferences(state, state.DefinitionData, documentID, canonicalID)
 			canonicalizeDocumentsInDefinitionReferences(state, state.ReferenceData, documentID, canonicalID)
+			canonicalizeDocumentsInDefinitionReferences(state, state.TypeDefinitionData, documentID, canonicalID)
+			canonicalizeDocumentsInDefinitionReferences(state, state.ImplementationData, documentID, canonicalID)
 
 			// Remove non-canonical document
 			delete(state.DocumentData, documentID)
@@ -115,9 +117,9 @@ func canonicalizeResultSets(state *State) {
 	}
 }
 
-// canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
-// from the element's "next" result set if such an element exists and the identifier is not already.
-// defined. This also merges down the moniker ids by unioning the sets.
+// canonicalizeResultSets "merges down" the definition, reference, type definition, implementation,
+// and hover result identifiers from the element's "next" result set if such an element exists and
+// the identifier is not already defined. This also merges down the moniker ids by unioning the sets.
 //
 // This method is assumed to be invoked only after canonicalizeResultSets, otherwise the next element
 // of a range may not have all of the necessary data to perform this canonicalization step.
@@ -135,9 +137,9 @@ func canonicalizeRanges(state *State) {
 	}
 }
 
-// canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
-// from the element's "next" result set if such an element exists and the identifier is not
-// already defined. This also merges down the moniker ids by unioning the sets.
+// canonicalizeResultSets "merges down" the definition, reference, type definition, implementation,
+// and hover result identifiers from the element's "next" result set if such an element exists and
+// the identifier is not already defined. This also merges down the moniker ids by unioning the sets.
 func canonicalizeResultSetData(state *State, id int, item ResultSet) ResultSet {
 	if nextID, nextItem, ok := next(state, id); ok {
 		// Recursively canonicalize the next element
@@ -152,8 +154,8 @@ func canonicalizeResultSetData(state *State, id int, item ResultSet) ResultSet {
 	return item
 }
 
-// mergeNextResultSetData merges the definition, reference, and hover result identifiers from
-// nextItem into item when not already defined. The moniker identifiers of nextItem are unioned
+// mergeNextResultSetData merges the definition, reference, type definition, implementation, and
+// hover result identifiers from nextItem into item when not already defined. The moniker identifiers of nextItem are unioned
 // into the moniker identifiers of item.
 func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int, nextItem ResultSet) ResultSet {
 	if item.DefinitionResultID == 0 {
@@ -162,6 +164,12 @@ func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -173,8 +181,8 @@ func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int
 	return item
 }
 
-// mergeNextRangeData merges the definition, reference, and hover result identifiers from nextItem
-// into item when not already defined. The moniker identifiers of nextItem are unioned into the
+// mergeNextRangeData merges the definition, reference, type definition, implementation, and hover
+// result identifiers from nextItem into item when not already defined. The moniker identifiers of nextItem are unioned into the
 // moniker identifiers of item.
 func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextItem ResultSet) Range {
 	if item.DefinitionResultID == 0 {
@@ -183,6 +191,12 @@ func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextIt
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
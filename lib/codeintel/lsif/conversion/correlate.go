@@ -18,16 +18,43 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
+// HoverOptions limit the hover text held in memory during correlation. They don't bound
+// the total memory usage of correlation: ranges, result sets, definition and reference
+// results, monikers, and documentation are always held in memory.
+type HoverOptions struct {
+	// HoverMemoryLimit is the maximum number of bytes of hover text held in memory while
+	// correlating an upload. Hover text is the bulk of the data of most large uploads. Once
+	// this limit is reached, the remaining hover results are written to a temporary file and
+	// read back while documents are serialized. A value of zero disables the limit.
+	HoverMemoryLimit int64
+
+	// HoverSpillDir is the directory in which the temporary hover text file is created. The
+	// default directory for temporary files is used if empty.
+	HoverSpillDir string
+}
+
 // Correlate reads LSIF data from the given reader and returns a correlation state object with
 // the same data canonicalized and pruned for storage.
 //
 // If getChildren == nil, no pruning of irrelevant data is performed.
 func Correlate(ctx context.Context, r io.Reader, root string, getChildren pathexistence.GetChildrenFunc) (*precise.GroupedBundleDataChans, error) {
+	return CorrelateWithHoverOptions(ctx, r, root, getChildren, HoverOptions{})
+}
+
+// CorrelateWithHoverOptions behaves like Correlate but bounds the hover text held in memory as
+// configured by the given options. Any temporary file created during correlation is removed once
+// the documents channel of the returned bundle data has been drained (or the context is canceled).
+func CorrelateWithHoverOptions(ctx context.Context, r io.Reader, root string, getChildren pathexistence.GetChildrenFunc, options HoverOptions) (_ *precise.GroupedBundleDataChans, err error) {
 	// Read raw upload stream and return a correlation state
-	state, err := correlateFromReader(ctx, r, root)
+	state, err := correlateFromReaderWithHoverOptions(ctx, r, root, options)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			_ = state.HoverSpill.Close()
+		}
+	}()
 
 	// Remove duplicate elements, collapse linked elements
 	canonicalize(state)
@@ -115,6 +142,12 @@ func gitRoot(path string) (string, error) {
 // correlateFromReader reads the given upload stream and returns a correlation state object.
 // The data in the correlation state is neither canonicalized nor pruned.
 func correlateFromReader(ctx context.Context, r io.Reader, root string) (*State, error) {
+	return correlateFromReaderWithHoverOptions(ctx, r, root, HoverOptions{})
+}
+
+// correlateFromReaderWithHoverOptions behaves like correlateFromReader but evicts hover text from
+// memory as configured by the given options.
+func correlateFromReaderWithHoverOptions(ctx context.Context, r io.Reader, root string, options HoverOptions) (_ *State, err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	ch := Read(ctx, r)
 	defer func() {
@@ -126,7 +159,12 @@ func correlateFromReader(ctx context.Context, r io.Reader, root string) (*State,
 		}
 	}()
 
-	wrappedState := newWrappedState(root)
+	wrappedState := newWrappedState(root, options)
+	defer func() {
+		if err != nil {
+			_ = wrappedState.HoverSpill.Close()
+		}
+	}()
 
 	i := 0
 	for pair := range ch {
@@ -152,13 +190,16 @@ type wrappedState struct {
 	*State
 	dumpRoot            string
 	unsupportedVertices *datastructures.IDSet
+	options             HoverOptions
+	hoverBytes          int64 // the number of bytes of hover text held in HoverData
 }
 
-func newWrappedState(dumpRoot string) *wrappedState {
+func newWrappedState(dumpRoot string, options HoverOptions) *wrappedState {
 	return &wrappedState{
 		State:               newState(),
 		dumpRoot:            dumpRoot,
 		unsupportedVertices: datastructures.NewIDSet(),
+		options:             options,
 	}
 }
 
@@ -175,16 +216,18 @@ func correlateElement(state *wrappedState, element Element) error {
 }
 
 var vertexHandlers = map[string]func(state *wrappedState, element Element) error{
-	"metaData":           correlateMetaData,
-	"document":           correlateDocument,
-	"range":              correlateRange,
-	"resultSet":          correlateResultSet,
-	"definitionResult":   correlateDefinitionResult,
-	"referenceResult":    correlateReferenceResult,
-	"hoverResult":        correlateHoverResult,
-	"moniker":            correlateMoniker,
-	"packageInformation": correlatePackageInformation,
-	"diagnosticResult":   correlateDiagnosticResult,
+	"metaData":             correlateMetaData,
+	"document":             correlateDocument,
+	"range":                correlateRange,
+	"resultSet":            correlateResultSet,
+	"definitionResult":     correlateDefinitionResult,
+	"referenceResult":      correlateReferenceResult,
+	"typeDefinitionResult": correlateTypeDefinitionResult,
+	"implementationResult": correlateImplementationResult,
+	"hoverResult":          correlateHoverResult,
+	"moniker":              correlateMoniker,
+	"packageInformation":   correlatePackageInformation,
+	"diagnosticResult":     correlateDiagnosticResult,
 
 	// Sourcegraph extensions
 	string(protocol.VertexSourcegraphDocumentationResult): correlateDocumentationResult,
@@ -208,16 +251,18 @@ func correlateVertex(state *wrappedState, element Element) error {
 }
 
 var edgeHandlers = map[string]func(state *wrappedState, id int, edge Edge) error{
-	"contains":                correlateContainsEdge,
-	"next":                    correlateNextEdge,
-	"item":                    correlateItemEdge,
-	"textDocument/definition": correlateTextDocumentDefinitionEdge,
-	"textDocument/references": correlateTextDocumentReferencesEdge,
-	"textDocument/hover":      correlateTextDocumentHoverEdge,
-	"moniker":                 correlateMonikerEdge,
-	"nextMoniker":             correlateNextMonikerEdge,
-	"packageInformation":      correlatePackageInformationEdge,
-	"textDocument/diagnostic": correlateDiagnosticEdge,
+	"contains":                    correlateContainsEdge,
+	"next":                        correlateNextEdge,
+	"item":                        correlateItemEdge,
+	"textDocument/definition":     correlateTextDocumentDefinitionEdge,
+	"textDocument/references":     correlateTextDocumentReferencesEdge,
+	"textDocument/typeDefinition": correlateTextDocumentTypeDefinitionEdge,
+	"textDocument/implementation": correlateTextDocumentImplementationEdge,
+	"textDocument/hover":          correlateTextDocumentHoverEdge,
+	"moniker":                     correlateMonikerEdge,
+	"nextMoniker":                 correlateNextMonikerEdge,
+	"packageInformation":          correlatePackageInformationEdge,
+	"textDocument/diagnostic":     correlateDiagnosticEdge,
 
 	// Sourcegraph extensions
 	string(protocol.EdgeSourcegraphDocumentationResult):   correlateDocumentationResultEdge,
@@ -313,12 +358,35 @@ func correlateReferenceResult(state *wrappedState, element Element) error {
 	return nil
 }
 
+func correlateTypeDefinitionResult(state *wrappedState, element Element) error {
+	state.TypeDefinitionData[element.ID] = datastructures.NewDefaultIDSetMap()
+	return nil
+}
+
+func correlateImplementationResult(state *wrappedState, element Element) error {
+	state.ImplementationData[element.ID] = datastructures.NewDefaultIDSetMap()
+	return nil
+}
+
 func correlateHoverResult(state *wrappedState, element Element) error {
 	payload, ok := element.Payload.(string)
 	if !ok {
 		return ErrUnexpectedPayload
 	}
 
+	if limit := state.options.HoverMemoryLimit; limit > 0 && state.hoverBytes+int64(len(payload)) > limit {
+		if state.HoverSpill == nil {
+			spill, err := newSpill(state.options.HoverSpillDir)
+			if err != nil {
+				return err
+			}
+			state.HoverSpill = spill
+		}
+
+		return state.HoverSpill.Put(element.ID, payload)
+	}
+
+	state.hoverBytes += int64(len(payload))
 	state.HoverData[element.ID] = payload
 	return nil
 }
@@ -397,6 +465,32 @@ func correlateItemEdge(state *wrappedState, id int, edge Edge) error {
 		return nil
 	}
 
+	if documentMap, ok := state.TypeDefinitionData[edge.OutV]; ok {
+		for _, inV := range edge.InVs {
+			if _, ok := state.RangeData[inV]; !ok {
+				return malformedDump(id, inV, "range")
+			}
+
+			// Link type definition data to defining range
+			documentMap.SetAdd(edge.Document, inV)
+		}
+
+		return nil
+	}
+
+	if documentMap, ok := state.ImplementationData[edge.OutV]; ok {
+		for _, inV := range edge.InVs {
+			if _, ok := state.RangeData[inV]; !ok {
+				return malformedDump(id, inV, "range")
+			}
+
+			// Link implementation data to implementing range
+			documentMap.SetAdd(edge.Document, inV)
+		}
+
+		return nil
+	}
+
 	if documentMap, ok := state.ReferenceData[edge.OutV]; ok {
 		for _, inV := range edge.InVs {
 			if _, ok := state.ReferenceData[inV]; ok {
@@ -453,8 +547,38 @@ func correlateTextDocumentReferencesEdge(state *wrappedState, id int, edge Edge)
 	return nil
 }
 
+func correlateTextDocumentTypeDefinitionEdge(state *wrappedState, id int, edge Edge) error {
+	if _, ok := state.TypeDefinitionData[edge.InV]; !ok {
+		return malformedDump(id, edge.InV, "typeDefinitionResult")
+	}
+
+	if source, ok := state.RangeData[edge.OutV]; ok {
+		state.RangeData[edge.OutV] = source.SetTypeDefinitionResultID(edge.InV)
+	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
+		state.ResultSetData[edge.OutV] = source.SetTypeDefinitionResultID(edge.InV)
+	} else {
+		return malformedDump(id, edge.OutV, "range", "resultSet")
+	}
+	return nil
+}
+
+func correlateTextDocumentImplementationEdge(state *wrappedState, id int, edge Edge) error {
+	if _, ok := state.ImplementationData[edge.InV]; !ok {
+		return malformedDump(id, edge.InV, "implementationResult")
+	}
+
+	if source, ok := state.RangeData[edge.OutV]; ok {
+		state.RangeData[edge.OutV] = source.SetImplementationResultID(edge.InV)
+	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
+		state.ResultSetData[edge.OutV] = source.SetImplementationResultID(edge.InV)
+	} else {
+		return malformedDump(id, edge.OutV, "range", "resultSet")
+	}
+	return nil
+}
+
 func correlateTextDocumentHoverEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.HoverData[edge.InV]; !ok {
+	if _, ok := state.HoverData[edge.InV]; !ok && !state.HoverSpill.Has(edge.InV) {
 		return malformedDump(id, edge.InV, "hoverResult")
 	}
 
@@ -39,7 +39,8 @@ func TestCorrelate(t *testing.T) {
 						End:   protocol.Pos{Line: 3, Character: 4},
 					},
 				},
-				DefinitionResultID: 13,
+				DefinitionResultID:     13,
+				ImplementationResultID: 52,
 			},
 			5: {
 				Range: reader.Range{
@@ -89,8 +90,9 @@ func TestCorrelate(t *testing.T) {
 		},
 		ResultSetData: map[int]ResultSet{
 			10: {
-				DefinitionResultID: 12,
-				ReferenceResultID:  14,
+				DefinitionResultID:     12,
+				ReferenceResultID:      14,
+				TypeDefinitionResultID: 51,
 			},
 			11: {
 				HoverResultID: 16,
@@ -104,6 +106,12 @@ func TestCorrelate(t *testing.T) {
 			14: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{2: datastructures.IDSetWith(4, 5)}),
 			15: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{}),
 		},
+		TypeDefinitionData: map[int]*datastructures.DefaultIDSetMap{
+			51: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{3: datastructures.IDSetWith(8)}),
+		},
+		ImplementationData: map[int]*datastructures.DefaultIDSetMap{
+			52: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{2: datastructures.IDSetWith(6)}),
+		},
 		HoverData: map[int]string{
 			16: "```go\ntext A\n```",
 			17: "```go\ntext B\n```",
@@ -217,6 +225,8 @@ func TestCorrelateMetaDataRoot(t *testing.T) {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -265,6 +275,8 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/bloomfilter"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -24,7 +26,7 @@ const resultsPerResultChunk = 512
 
 // groupBundleData converts a raw (but canonicalized) correlation State into a GroupedBundleData.
 func groupBundleData(ctx context.Context, state *State) (*precise.GroupedBundleDataChans, error) {
-	numResults := len(state.DefinitionData) + len(state.ReferenceData)
+	numResults := len(state.DefinitionData) + len(state.ReferenceData) + len(state.TypeDefinitionData) + len(state.ImplementationData)
 	numResultChunks := int(math.Max(1, math.Floor(float64(numResults)/resultsPerResultChunk)))
 
 	meta := precise.MetaData{NumResultChunks: numResultChunks}
@@ -32,6 +34,7 @@ func groupBundleData(ctx context.Context, state *State) (*precise.GroupedBundleD
 	resultChunks := serializeResultChunks(ctx, state, numResultChunks)
 	definitionRows := gatherMonikersLocations(ctx, state, state.DefinitionData, func(r Range) int { return r.DefinitionResultID })
 	referenceRows := gatherMonikersLocations(ctx, state, state.ReferenceData, func(r Range) int { return r.ReferenceResultID })
+	implementationRows := gatherMonikersLocations(ctx, state, state.ImplementationData, func(r Range) int { return r.ImplementationResultID })
 	documentation := collectDocumentation(ctx, state)
 	packages := gatherPackages(state)
 	packageReferences, err := gatherPackageReferences(state, packages)
@@ -45,6 +48,7 @@ func groupBundleData(ctx context.Context, state *State) (*precise.GroupedBundleD
 		ResultChunks:          resultChunks,
 		Definitions:           definitionRows,
 		References:            referenceRows,
+		Implementations:       implementationRows,
 		DocumentationPages:    documentation.pages,
 		DocumentationPathInfo: documentation.pathInfo,
 		DocumentationMappings: documentation.mappings,
@@ -59,14 +63,24 @@ func serializeBundleDocuments(ctx context.Context, state *State) chan precise.Ke
 	go func() {
 		defer close(ch)
 
+		// Documents are the only consumer of hover text, so any evicted hover
+		// results can be discarded once every document has been serialized.
+		defer func() {
+			if err := state.HoverSpill.Close(); err != nil {
+				log15.Warn("Failed to remove spill file", "err", err)
+			}
+		}()
+
 		for documentID, uri := range state.DocumentData {
 			if strings.HasPrefix(uri, "..") {
 				continue
 			}
 
+			document, err := serializeDocument(state, documentID)
 			data := precise.KeyedDocumentData{
 				Path:     uri,
-				Document: serializeDocument(state, documentID),
+				Document: document,
+				Err:      err,
 			}
 
 			select {
@@ -74,13 +88,17 @@ func serializeBundleDocuments(ctx context.Context, state *State) chan precise.Ke
 			case <-ctx.Done():
 				return
 			}
+
+			if err != nil {
+				return
+			}
 		}
 	}()
 
 	return ch
 }
 
-func serializeDocument(state *State, documentID int) precise.DocumentData {
+func serializeDocument(state *State, documentID int) (_ precise.DocumentData, err error) {
 	document := precise.DocumentData{
 		Ranges:             make(map[precise.ID]precise.RangeData, state.Contains.SetLen(documentID)),
 		HoverResults:       map[precise.ID]string{},
@@ -89,6 +107,8 @@ func serializeDocument(state *State, documentID int) precise.DocumentData {
 		Diagnostics:        make([]precise.DiagnosticData, 0, state.Diagnostics.SetLen(documentID)),
 	}
 
+	enclosingRangeIDs := enclosingDefinitionRanges(state, documentID)
+
 	state.Contains.SetEach(documentID, func(rangeID int) {
 		rangeData := state.RangeData[rangeID]
 
@@ -114,19 +134,26 @@ func serializeDocument(state *State, documentID int) precise.DocumentData {
 		})
 
 		document.Ranges[toID(rangeID)] = precise.RangeData{
-			StartLine:             rangeData.Start.Line,
-			StartCharacter:        rangeData.Start.Character,
-			EndLine:               rangeData.End.Line,
-			EndCharacter:          rangeData.End.Character,
-			DefinitionResultID:    toID(rangeData.DefinitionResultID),
-			ReferenceResultID:     toID(rangeData.ReferenceResultID),
-			HoverResultID:         toID(rangeData.HoverResultID),
-			DocumentationResultID: toID(rangeData.DocumentationResultID),
-			MonikerIDs:            monikerIDs,
+			StartLine:              rangeData.Start.Line,
+			StartCharacter:         rangeData.Start.Character,
+			EndLine:                rangeData.End.Line,
+			EndCharacter:           rangeData.End.Character,
+			DefinitionResultID:     toID(rangeData.DefinitionResultID),
+			ReferenceResultID:      toID(rangeData.ReferenceResultID),
+			TypeDefinitionResultID: toID(rangeData.TypeDefinitionResultID),
+			ImplementationResultID: toID(rangeData.ImplementationResultID),
+			HoverResultID:          toID(rangeData.HoverResultID),
+			DocumentationResultID:  toID(rangeData.DocumentationResultID),
+			EnclosingRangeID:       toID(enclosingRangeIDs[rangeID]),
+			MonikerIDs:             monikerIDs,
 		}
 
-		if rangeData.HoverResultID != 0 {
-			hoverData := state.HoverData[rangeData.HoverResultID]
+		if rangeData.HoverResultID != 0 && err == nil {
+			hoverData, hoverErr := state.hoverText(rangeData.HoverResultID)
+			if hoverErr != nil {
+				err = errors.Wrapf(hoverErr, "reading hover result %d", rangeData.HoverResultID)
+				return
+			}
 			document.HoverResults[toID(rangeData.HoverResultID)] = hoverData
 		}
 	})
@@ -146,7 +173,83 @@ func serializeDocument(state *State, documentID int) precise.DocumentData {
 		}
 	})
 
-	return document
+	return document, err
+}
+
+// enclosingDefinitionRanges returns a map from the identifiers of the ranges in the given document
+// to the identifier of the innermost definition range whose full range encloses it. Full ranges are
+// only known for definition ranges with a range tag, so ranges of documents emitted by indexers that
+// do not tag their definitions have no enclosing range.
+func enclosingDefinitionRanges(state *State, documentID int) map[int]int {
+	var definitionIDs, rangeIDs []int
+	state.Contains.SetEach(documentID, func(rangeID int) {
+		if tag := state.RangeData[rangeID].Tag; tag != nil && tag.Type == "definition" && tag.FullRange != nil {
+			definitionIDs = append(definitionIDs, rangeID)
+		}
+		rangeIDs = append(rangeIDs, rangeID)
+	})
+	if len(definitionIDs) == 0 {
+		return nil
+	}
+
+	fullRange := func(definitionID int) protocol.RangeData {
+		return *state.RangeData[definitionID].Tag.FullRange
+	}
+
+	// Order definitions by the start of their full range, outermost first on ties, so that
+	// every definition is visited after the definitions enclosing it.
+	sort.Slice(definitionIDs, func(i, j int) bool {
+		a, b := fullRange(definitionIDs[i]), fullRange(definitionIDs[j])
+		if a.Start != b.Start {
+			return positionLess(a.Start, b.Start)
+		}
+		return positionLess(b.End, a.End)
+	})
+	sort.Slice(rangeIDs, func(i, j int) bool {
+		return positionLess(state.RangeData[rangeIDs[i]].Start, state.RangeData[rangeIDs[j]].Start)
+	})
+
+	// Sweep over the ranges in order of their start position. The stack holds the definitions
+	// whose full range started before the current range, with the innermost one on top. Since
+	// the ranges are visited in order, a definition ending before the current range starts can
+	// not enclose any later range either.
+	enclosingRangeIDs := map[int]int{}
+	var stack []int
+	popEnded := func(pos protocol.Pos) {
+		for len(stack) > 0 && positionLess(fullRange(stack[len(stack)-1]).End, pos) {
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	next := 0
+	for _, rangeID := range rangeIDs {
+		r := state.RangeData[rangeID].RangeData
+
+		for ; next < len(definitionIDs) && !positionLess(r.Start, fullRange(definitionIDs[next]).Start); next++ {
+			popEnded(fullRange(definitionIDs[next]).Start)
+			stack = append(stack, definitionIDs[next])
+		}
+		popEnded(r.Start)
+
+		for i := len(stack) - 1; i >= 0; i-- {
+			if definitionID := stack[i]; definitionID != rangeID && rangeDataContains(fullRange(definitionID), r) {
+				enclosingRangeIDs[rangeID] = definitionID
+				break
+			}
+		}
+	}
+
+	return enclosingRangeIDs
+}
+
+// rangeDataContains returns true if the outer range encloses the inner range.
+func rangeDataContains(outer, inner protocol.RangeData) bool {
+	return !positionLess(inner.Start, outer.Start) && !positionLess(outer.End, inner.End)
+}
+
+// positionLess returns true if the position a occurs before the position b.
+func positionLess(a, b protocol.Pos) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
 }
 
 func serializeResultChunks(ctx context.Context, state *State, numResultChunks int) chan precise.IndexedResultChunkData {
@@ -159,6 +262,14 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 		index := precise.HashKey(toID(id), numResultChunks)
 		chunkAssignments[index] = append(chunkAssignments[index], id)
 	}
+	for id := range state.TypeDefinitionData {
+		index := precise.HashKey(toID(id), numResultChunks)
+		chunkAssignments[index] = append(chunkAssignments[index], id)
+	}
+	for id := range state.ImplementationData {
+		index := precise.HashKey(toID(id), numResultChunks)
+		chunkAssignments[index] = append(chunkAssignments[index], id)
+	}
 
 	ch := make(chan precise.IndexedResultChunkData)
 
@@ -174,10 +285,7 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 			rangeIDsByResultID := make(map[precise.ID][]precise.DocumentIDRangeID, len(resultIDs))
 
 			for _, resultID := range resultIDs {
-				documentRanges, ok := state.DefinitionData[resultID]
-				if !ok {
-					documentRanges = state.ReferenceData[resultID]
-				}
+				documentRanges := resultData(state, resultID)
 
 				rangeIDMap := map[precise.ID]int{}
 				var documentIDRangeIDs []precise.DocumentIDRangeID
@@ -229,6 +337,21 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 	return ch
 }
 
+// resultData returns the ranges grouped by document of the definition, reference, type
+// definition, or implementation result with the given identifier.
+func resultData(state *State, resultID int) *datastructures.DefaultIDSetMap {
+	if documentRanges, ok := state.DefinitionData[resultID]; ok {
+		return documentRanges
+	}
+	if documentRanges, ok := state.ReferenceData[resultID]; ok {
+		return documentRanges
+	}
+	if documentRanges, ok := state.TypeDefinitionData[resultID]; ok {
+		return documentRanges
+	}
+	return state.ImplementationData[resultID]
+}
+
 // sortableDocumentIDRangeIDs implements sort.Interface for document/range id pairs.
 type sortableDocumentIDRangeIDs struct {
 	state         *State
@@ -656,6 +656,56 @@ func TestGroupBundleData(t *testing.T) {
 //
 //
 
+func TestEnclosingDefinitionRanges(t *testing.T) {
+	rng := func(startLine, endLine int, fullRange *protocol.RangeData) Range {
+		var tag *protocol.RangeTag
+		if fullRange != nil {
+			tag = &protocol.RangeTag{Type: "definition", FullRange: fullRange}
+		}
+
+		return Range{
+			Range: reader.Range{
+				RangeData: protocol.RangeData{
+					Start: protocol.Pos{Line: startLine, Character: 5},
+					End:   protocol.Pos{Line: endLine, Character: 10},
+				},
+				Tag: tag,
+			},
+		}
+	}
+	fullRange := func(startLine, endLine int) *protocol.RangeData {
+		return &protocol.RangeData{
+			Start: protocol.Pos{Line: startLine, Character: 0},
+			End:   protocol.Pos{Line: endLine, Character: 1},
+		}
+	}
+
+	state := &State{
+		RangeData: map[int]Range{
+			2001: rng(1, 1, fullRange(1, 20)),    // outer function
+			2002: rng(3, 3, nil),                 // call within outer function
+			2003: rng(5, 5, fullRange(5, 10)),    // nested function
+			2004: rng(7, 7, nil),                 // call within nested function
+			2005: rng(30, 30, nil),               // call outside of any function
+			2006: rng(25, 25, fullRange(25, 26)), // sibling function
+			2007: rng(12, 12, nil),               // call within outer function after nested function
+		},
+		Contains: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{
+			1001: datastructures.IDSetWith(2001, 2002, 2003, 2004, 2005, 2006, 2007),
+		}),
+	}
+
+	expected := map[int]int{
+		2002: 2001,
+		2003: 2001,
+		2004: 2003,
+		2007: 2001,
+	}
+	if diff := cmp.Diff(expected, enclosingDefinitionRanges(state, 1001)); diff != "" {
+		t.Errorf("unexpected enclosing ranges (-want +got):\n%s", diff)
+	}
+}
+
 func sortMonikerIDs(s []precise.ID) {
 	sort.Slice(s, func(i, j int) bool {
 		return strings.Compare(string(s[i]), string(s[j])) < 0
@@ -0,0 +1,105 @@
+package conversion
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Spill is an append-only temporary file holding the hover text evicted from memory during
+// correlation once the configured hover memory limit has been reached. Values are written once
+// while the upload is read and are read back (in any order) while the correlated data is
+// serialized. All methods are safe to call on a nil Spill, which holds no values.
+type Spill struct {
+	file      *os.File
+	offset    int64
+	refs      map[int]spillRef
+	closeOnce sync.Once
+	closeErr  error
+}
+
+type spillRef struct {
+	offset int64
+	length int
+}
+
+// newSpill creates a new spill file in the given directory. The default directory for
+// temporary files is used if dir is empty.
+func newSpill(dir string) (*Spill, error) {
+	file, err := os.CreateTemp(dir, "lsif-spill-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "os.CreateTemp")
+	}
+
+	return &Spill{file: file, refs: map[int]spillRef{}}, nil
+}
+
+// Put writes the given value to the spill file.
+func (s *Spill) Put(id int, value string) error {
+	n, err := io.WriteString(s.file, value)
+	if err != nil {
+		return errors.Wrap(err, "writing spill file")
+	}
+
+	s.refs[id] = spillRef{offset: s.offset, length: n}
+	s.offset += int64(n)
+	return nil
+}
+
+// Has returns true if a value with the given identifier was written to the spill file.
+func (s *Spill) Has(id int) bool {
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.refs[id]
+	return ok
+}
+
+// Get reads the value with the given identifier back from the spill file.
+func (s *Spill) Get(id int) (string, bool, error) {
+	if s == nil {
+		return "", false, nil
+	}
+
+	ref, ok := s.refs[id]
+	if !ok {
+		return "", false, nil
+	}
+
+	buf := make([]byte, ref.length)
+	if _, err := s.file.ReadAt(buf, ref.offset); err != nil {
+		return "", false, errors.Wrap(err, "reading spill file")
+	}
+
+	return string(buf), true, nil
+}
+
+// Len returns the number of values written to the spill file.
+func (s *Spill) Len() int {
+	if s == nil {
+		return 0
+	}
+
+	return len(s.refs)
+}
+
+// Close closes and removes the spill file. It is safe to call Close multiple times.
+func (s *Spill) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.closeOnce.Do(func() {
+		if err := s.file.Close(); err != nil {
+			s.closeErr = err
+		}
+		if err := os.Remove(s.file.Name()); err != nil && s.closeErr == nil {
+			s.closeErr = err
+		}
+	})
+
+	return s.closeErr
+}
@@ -0,0 +1,121 @@
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+func TestCorrelateHoverMemoryLimit(t *testing.T) {
+	input := makeSyntheticDump(200, 50, 1024)
+	spillDir := t.TempDir()
+	options := HoverOptions{HoverMemoryLimit: 64 * 1024, HoverSpillDir: spillDir}
+
+	state, err := correlateFromReaderWithHoverOptions(context.Background(), bytes.NewReader(input), "", options)
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	hoverBytes := 0
+	for _, text := range state.HoverData {
+		hoverBytes += len(text)
+	}
+	if hoverBytes > int(options.HoverMemoryLimit) {
+		t.Errorf("too much hover text held in memory. want<=%d have=%d", options.HoverMemoryLimit, hoverBytes)
+	}
+	if n := state.HoverSpill.Len(); n == 0 {
+		t.Errorf("expected hover results to be spilled to disk")
+	}
+	if total := len(state.HoverData) + state.HoverSpill.Len(); total != 200*50 {
+		t.Errorf("unexpected number of hover results. want=%d have=%d", 200*50, total)
+	}
+	_ = state.HoverSpill.Close()
+
+	expected := correlateDocuments(t, input, HoverOptions{})
+	actual := correlateDocuments(t, input, options)
+	if diff := cmp.Diff(expected, actual, datastructures.Comparers...); diff != "" {
+		t.Errorf("unexpected document data (-want +got):\n%s", diff)
+	}
+
+	entries, err := os.ReadDir(spillDir)
+	if err != nil {
+		t.Fatalf("unexpected error reading spill directory: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected spill files to be removed. have=%d", len(entries))
+	}
+}
+
+func TestSerializeDocumentSpillError(t *testing.T) {
+	input := makeSyntheticDump(1, 50, 1024)
+	options := HoverOptions{HoverMemoryLimit: 1024, HoverSpillDir: t.TempDir()}
+
+	state, err := correlateFromReaderWithHoverOptions(context.Background(), bytes.NewReader(input), "", options)
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	// Reading spilled hover results fails once the spill file is removed.
+	if err := state.HoverSpill.Close(); err != nil {
+		t.Fatalf("unexpected error closing spill: %s", err)
+	}
+
+	for documentID := range state.DocumentData {
+		if _, err := serializeDocument(state, documentID); err == nil {
+			t.Errorf("expected error serializing document")
+		}
+	}
+}
+
+func correlateDocuments(t *testing.T, input []byte, options HoverOptions) map[string]precise.DocumentData {
+	groupedBundleData, err := CorrelateWithHoverOptions(context.Background(), bytes.NewReader(input), "", nil, options)
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	documents := map[string]precise.DocumentData{}
+	for v := range groupedBundleData.Documents {
+		documents[v.Path] = v.Document
+	}
+
+	return documents
+}
+
+// makeSyntheticDump creates an LSIF dump with the given number of documents, each containing
+// the given number of ranges with a distinct hover result of (roughly) the given size.
+func makeSyntheticDump(numDocuments, numRangesPerDocument, hoverSize int) []byte {
+	var buf bytes.Buffer
+	id := 0
+	nextID := func() int { id++; return id }
+
+	fmt.Fprintf(&buf, `{"id": "%d", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test/"}`+"\n", nextID())
+
+	for i := 0; i < numDocuments; i++ {
+		documentID := nextID()
+		fmt.Fprintf(&buf, `{"id": "%d", "type": "vertex", "label": "document", "uri": "file:///test/file%d.go"}`+"\n", documentID, i)
+
+		rangeIDs := make([]string, 0, numRangesPerDocument)
+		for j := 0; j < numRangesPerDocument; j++ {
+			rangeID := nextID()
+			hoverID := nextID()
+			rangeIDs = append(rangeIDs, fmt.Sprintf(`"%d"`, rangeID))
+
+			text := fmt.Sprintf("doc %d range %d ", i, j) + strings.Repeat("x", hoverSize)
+			fmt.Fprintf(&buf, `{"id": "%d", "type": "vertex", "label": "range", "start": {"line": %d, "character": 0}, "end": {"line": %d, "character": 5}}`+"\n", rangeID, j, j)
+			fmt.Fprintf(&buf, `{"id": "%d", "type": "vertex", "label": "hoverResult", "result": {"contents": [{"language": "go", "value": "%s"}]}}`+"\n", hoverID, text)
+			fmt.Fprintf(&buf, `{"id": "%d", "type": "edge", "label": "textDocument/hover", "outV": "%d", "inV": "%d"}`+"\n", nextID(), rangeID, hoverID)
+		}
+
+		fmt.Fprintf(&buf, `{"id": "%d", "type": "edge", "label": "contains", "outV": "%d", "inVs": [%s]}`+"\n", nextID(), documentID, strings.Join(rangeIDs, ", "))
+	}
+
+	return buf.Bytes()
+}
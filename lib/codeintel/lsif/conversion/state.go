@@ -14,7 +14,10 @@ type State struct {
 	ResultSetData          map[int]ResultSet
 	DefinitionData         map[int]*datastructures.DefaultIDSetMap
 	ReferenceData          map[int]*datastructures.DefaultIDSetMap
+	TypeDefinitionData     map[int]*datastructures.DefaultIDSetMap
+	ImplementationData     map[int]*datastructures.DefaultIDSetMap
 	HoverData              map[int]string
+	HoverSpill             *Spill // hover results evicted from HoverData once the memory limit was reached
 	MonikerData            map[int]Moniker
 	PackageInformationData map[int]PackageInformation
 	DiagnosticResults      map[int][]Diagnostic
@@ -44,6 +47,8 @@ func newState() *State {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -66,3 +71,14 @@ func newState() *State {
 		DocumentationStringDetail: map[int]int{},
 	}
 }
+
+// hoverText returns the text of the given hover result, which may have been evicted from memory
+// into the state's spill file during correlation.
+func (s *State) hoverText(id int) (string, error) {
+	if text, ok := s.HoverData[id]; ok {
+		return text, nil
+	}
+
+	text, _, err := s.HoverSpill.Get(id)
+	return text, err
+}
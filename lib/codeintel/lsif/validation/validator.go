@@ -12,20 +12,35 @@ type Validator struct {
 	raisedMissingMetadataError bool
 }
 
+// Validate reads the given index file and validates each element as well as the relationships
+// between elements. Relationship validators are only run if each element is individually valid.
 func (v *Validator) Validate(indexFile io.Reader) error {
-	if err := reader.Read(indexFile, v.Context.Stasher, v.vertexMapper, v.edgeMapper); err != nil {
+	if err := v.ValidateElements(indexFile); err != nil {
 		return err
 	}
 
 	if len(v.Context.Errors) == 0 {
-		for _, rv := range relationshipValidators {
-			rv(v.Context)
-		}
+		v.ValidateRelationships()
 	}
 
 	return nil
 }
 
+// ValidateElements reads the given index file and validates the properties of each vertex and
+// edge element in isolation. Each element is stashed into the validation context so that the
+// relationships between elements can be validated afterwards.
+func (v *Validator) ValidateElements(indexFile io.Reader) error {
+	return reader.Read(indexFile, v.Context.Stasher, v.vertexMapper, v.edgeMapper)
+}
+
+// ValidateRelationships validates properties across all vertices and edges previously read
+// by ValidateElements.
+func (v *Validator) ValidateRelationships() {
+	for _, rv := range relationshipValidators {
+		rv(v.Context)
+	}
+}
+
 func (v *Validator) vertexMapper(lineContext reader.LineContext) {
 	atomic.AddUint64(&v.Context.NumVertices, 1)
 
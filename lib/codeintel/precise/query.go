@@ -3,10 +3,12 @@ package precise
 import "github.com/cockroachdb/errors"
 
 type QueryResult struct {
-	Definitions []LocationData
-	References  []LocationData
-	Hover       string
-	Monikers    []QualifiedMonikerData
+	Definitions     []LocationData
+	References      []LocationData
+	TypeDefinitions []LocationData
+	Implementations []LocationData
+	Hover           string
+	Monikers        []QualifiedMonikerData
 }
 
 func Query(bundle *GroupedBundleDataMaps, path string, line, character int) ([]QueryResult, error) {
@@ -35,10 +37,12 @@ func Resolve(bundle *GroupedBundleDataMaps, document DocumentData, rng RangeData
 	}
 
 	return QueryResult{
-		Definitions: resolveLocations(bundle, rng.DefinitionResultID),
-		References:  resolveLocations(bundle, rng.ReferenceResultID),
-		Hover:       hover,
-		Monikers:    monikers,
+		Definitions:     resolveLocations(bundle, rng.DefinitionResultID),
+		References:      resolveLocations(bundle, rng.ReferenceResultID),
+		TypeDefinitions: resolveLocations(bundle, rng.TypeDefinitionResultID),
+		Implementations: resolveLocations(bundle, rng.ImplementationResultID),
+		Hover:           hover,
+		Monikers:        monikers,
 	}
 }
 
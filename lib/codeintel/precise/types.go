@@ -25,15 +25,18 @@ type DocumentData struct {
 // that was reachable via a result set has been collapsed into this object during
 // conversion.
 type RangeData struct {
-	StartLine             int  // 0-indexed, inclusive
-	StartCharacter        int  // 0-indexed, inclusive
-	EndLine               int  // 0-indexed, inclusive
-	EndCharacter          int  // 0-indexed, inclusive
-	DefinitionResultID    ID   // possibly empty
-	ReferenceResultID     ID   // possibly empty
-	HoverResultID         ID   // possibly empty
-	DocumentationResultID ID   // possibly empty
-	MonikerIDs            []ID // possibly empty
+	StartLine              int  // 0-indexed, inclusive
+	StartCharacter         int  // 0-indexed, inclusive
+	EndLine                int  // 0-indexed, inclusive
+	EndCharacter           int  // 0-indexed, inclusive
+	DefinitionResultID     ID   // possibly empty
+	ReferenceResultID      ID   // possibly empty
+	TypeDefinitionResultID ID   // possibly empty
+	ImplementationResultID ID   // possibly empty
+	HoverResultID          ID   // possibly empty
+	DocumentationResultID  ID   // possibly empty
+	EnclosingRangeID       ID   // possibly empty
+	MonikerIDs             []ID // possibly empty
 }
 
 // MonikerData represent a unique name (eventually) attached to a range.
@@ -73,7 +76,8 @@ type DiagnosticData struct {
 }
 
 // ResultChunkData represents a row of the resultChunk table. Each row is a subset
-// of definition and reference result data in the index. Results are inserted into
+// of definition, reference, type definition, and implementation result data in the
+// index. Results are inserted into
 // chunks based on the hash of their identifier, thus every chunk has a roughly
 // proportional amount of data.
 type ResultChunkData struct {
@@ -122,10 +126,12 @@ type MonikerLocations struct {
 	Locations  []LocationData
 }
 
-// KeyedDocumentData pairs a document with its path.
+// KeyedDocumentData pairs a document with its path. If Err is non-nil, the document
+// could not be serialized and no further documents follow.
 type KeyedDocumentData struct {
 	Path     string
 	Document DocumentData
+	Err      error
 }
 
 // IndexedResultChunkData pairs a result chunk with its index.
@@ -210,6 +216,7 @@ type GroupedBundleDataChans struct {
 	ResultChunks          chan IndexedResultChunkData
 	Definitions           chan MonikerLocations
 	References            chan MonikerLocations
+	Implementations       chan MonikerLocations
 	Packages              []Package
 	PackageReferences     []PackageReference
 	DocumentationPages    chan *DocumentationPageData
@@ -223,6 +230,7 @@ type GroupedBundleDataMaps struct {
 	ResultChunks      map[int]ResultChunkData
 	Definitions       map[string]map[string][]LocationData
 	References        map[string]map[string][]LocationData
+	Implementations   map[string]map[string][]LocationData
 	Packages          []Package
 	PackageReferences []PackageReference
 }
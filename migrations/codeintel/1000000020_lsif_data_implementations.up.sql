@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_data_implementations (
+    dump_id integer NOT NULL,
+    scheme text NOT NULL,
+    identifier text NOT NULL,
+    data bytea,
+    schema_version integer NOT NULL,
+    num_locations integer NOT NULL
+);
+ALTER TABLE lsif_data_implementations ADD PRIMARY KEY (dump_id, scheme, identifier);
+CREATE INDEX lsif_data_implementations_dump_id_schema_version ON lsif_data_implementations (dump_id, schema_version);
+
+COMMENT ON TABLE lsif_data_implementations IS 'Associates (document, range) pairs with the monikers of the symbols implemented at the range.';
+COMMENT ON COLUMN lsif_data_implementations.dump_id IS 'The identifier of the associated dump in the lsif_uploads table (state=completed).';
+COMMENT ON COLUMN lsif_data_implementations.scheme IS 'The moniker scheme.';
+COMMENT ON COLUMN lsif_data_implementations.identifier IS 'The moniker identifier.';
+COMMENT ON COLUMN lsif_data_implementations.data IS 'A gob-encoded payload conforming to an array of [LocationData](https://sourcegraph.com/github.com/sourcegraph/sourcegraph@3.26/-/blob/enterprise/lib/codeintel/semantic/types.go#L106:6) types.';
+COMMENT ON COLUMN lsif_data_implementations.schema_version IS 'The schema version of this row - used to determine presence and encoding of data.';
+COMMENT ON COLUMN lsif_data_implementations.num_locations IS 'The number of locations stored in the data field.';
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS lsif_data_documentation_search (
+    id bigserial NOT NULL PRIMARY KEY,
+    dump_id integer NOT NULL,
+    repo_id integer NOT NULL,
+    lang text NOT NULL,
+    path_id text NOT NULL,
+    search_key text NOT NULL,
+    label text NOT NULL,
+    detail text NOT NULL,
+    tags text[] NOT NULL,
+    tsv tsvector NOT NULL
+);
+CREATE INDEX lsif_data_documentation_search_dump_id ON lsif_data_documentation_search (dump_id);
+CREATE INDEX lsif_data_documentation_search_repo_id ON lsif_data_documentation_search (repo_id);
+CREATE INDEX lsif_data_documentation_search_tsv ON lsif_data_documentation_search USING gin (tsv);
+CREATE INDEX lsif_data_documentation_search_search_key_trgm ON lsif_data_documentation_search USING gin (search_key gin_trgm_ops);
+
+COMMENT ON TABLE lsif_data_documentation_search IS 'A search index over the documentation nodes of each dump that define a search key.';
+COMMENT ON COLUMN lsif_data_documentation_search.dump_id IS 'The identifier of the associated dump in the lsif_uploads table (state=completed).';
+COMMENT ON COLUMN lsif_data_documentation_search.repo_id IS 'The identifier of the repository of the associated dump.';
+COMMENT ON COLUMN lsif_data_documentation_search.lang IS 'The language of the documented symbol, derived from the indexer of the associated dump.';
+COMMENT ON COLUMN lsif_data_documentation_search.path_id IS 'The documentation page path ID, see see GraphQL codeintel.schema:documentationPage for what this is.';
+COMMENT ON COLUMN lsif_data_documentation_search.search_key IS 'The search key of the documentation node, e.g. `mux.Router.ServeHTTP`.';
+COMMENT ON COLUMN lsif_data_documentation_search.label IS 'The plaintext or markdown label of the documentation node, e.g. a one-line signature.';
+COMMENT ON COLUMN lsif_data_documentation_search.detail IS 'The plaintext or markdown detail of the documentation node, e.g. its doc comment.';
+COMMENT ON COLUMN lsif_data_documentation_search.tags IS 'The tags of the documentation node, e.g. its symbol kind and whether it is exported.';
+COMMENT ON COLUMN lsif_data_documentation_search.tsv IS 'The weighted full-text search document built from the search key, label, and detail.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS batch_spec_workspace_execution_jobs;
+DROP TABLE IF EXISTS batch_spec_workspaces;
+DROP TABLE IF EXISTS batch_spec_resolution_jobs;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS batch_spec_templates (
+  id                BIGSERIAL PRIMARY KEY,
+
+  name              TEXT NOT NULL,
+  description       TEXT NOT NULL DEFAULT '',
+  raw_template      TEXT NOT NULL,
+  inputs            JSONB NOT NULL DEFAULT '[]'::jsonb CHECK (jsonb_typeof(inputs) = 'array'),
+
+  -- Templates without a namespace are available site-wide.
+  namespace_user_id INTEGER REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+  namespace_org_id  INTEGER REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+  creator_id        INTEGER REFERENCES users(id) ON DELETE SET NULL DEFERRABLE,
+
+  created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT batch_spec_templates_has_at_most_1_namespace CHECK (namespace_user_id IS NULL OR namespace_org_id IS NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS batch_spec_templates_unique_name ON batch_spec_templates (COALESCE(namespace_user_id, 0), COALESCE(namespace_org_id, 0), name);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_reference_counts (
+    repository_id integer PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    path_counts jsonb NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS lsif_reference_counts_updated_at ON lsif_reference_counts(updated_at);
+
+COMMENT ON TABLE lsif_reference_counts IS 'Stores the number of uploads from other repositories that reference the definitions of a repository, as visible from the tip of its default branch.';
+COMMENT ON COLUMN lsif_reference_counts.path_counts IS 'A map from document paths to the number of inbound references to definitions within that document.';
+COMMENT ON COLUMN lsif_reference_counts.updated_at IS 'The last time the reference counts of this repository were calculated.';
+
+COMMIT;
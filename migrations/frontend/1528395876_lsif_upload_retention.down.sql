@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM lsif_configuration_policies WHERE repository_id IS NULL AND name IN (
+    'Default tip-of-branch retention policy',
+    'Default tag retention policy',
+    'Default commit retention policy'
+);
+
+DROP TABLE IF EXISTS lsif_last_retention_scan;
+ALTER TABLE lsif_uploads DROP COLUMN IF EXISTS expired;
+
+COMMIT;
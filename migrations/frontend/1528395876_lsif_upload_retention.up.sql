@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE lsif_uploads ADD COLUMN IF NOT EXISTS expired boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN lsif_uploads.expired IS 'Whether or not this upload data is no longer protected by any data retention policy.';
+
+CREATE TABLE IF NOT EXISTS lsif_last_retention_scan (
+    repository_id integer PRIMARY KEY,
+    last_retention_scan_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE lsif_last_retention_scan IS 'Tracks the last time uploads of a repository were checked against data retention policies.';
+COMMENT ON COLUMN lsif_last_retention_scan.last_retention_scan_at IS 'The last time uploads of this repository were checked against data retention policies.';
+
+-- Seed the global policies that reproduce the previous fixed retention rules: keep the tips
+-- of recently active branches, recent tagged commits, and every commit on any branch
+-- from the last 30 days.
+INSERT INTO lsif_configuration_policies (repository_id, name, type, pattern, retention_enabled, retention_duration_hours, retain_intermediate_commits, indexing_enabled, index_commit_max_age_hours, index_intermediate_commits)
+VALUES
+    (NULL, 'Default tip-of-branch retention policy', 'GIT_TREE', '*', true, 2016, false, false, NULL, false),
+    (NULL, 'Default tag retention policy', 'GIT_TAG', '*', true, 8064, false, false, NULL, false),
+    (NULL, 'Default commit retention policy', 'GIT_TREE', '*', true, 720, true, false, NULL, false);
+
+COMMIT;
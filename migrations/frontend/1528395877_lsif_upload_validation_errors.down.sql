@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS lsif_upload_validation_errors;
+
+COMMIT;
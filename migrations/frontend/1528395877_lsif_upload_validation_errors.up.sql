@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_upload_validation_errors (
+    id serial PRIMARY KEY,
+    upload_id integer NOT NULL REFERENCES lsif_uploads(id) ON DELETE CASCADE,
+    severity text NOT NULL,
+    message text NOT NULL,
+    relevant_lines integer[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS lsif_upload_validation_errors_upload_id ON lsif_upload_validation_errors(upload_id);
+
+COMMENT ON TABLE lsif_upload_validation_errors IS 'Stores a capped list of problems detected while validating the raw LSIF data of an upload.';
+COMMENT ON COLUMN lsif_upload_validation_errors.upload_id IS 'The identifier of the upload in which the problem was detected.';
+COMMENT ON COLUMN lsif_upload_validation_errors.severity IS 'The severity of the problem (`ERROR` or `WARNING`).';
+COMMENT ON COLUMN lsif_upload_validation_errors.message IS 'A human-readable description of the problem.';
+COMMENT ON COLUMN lsif_upload_validation_errors.relevant_lines IS 'The (one-based) line numbers of the elements in the raw LSIF data related to the problem.';
+
+COMMIT;
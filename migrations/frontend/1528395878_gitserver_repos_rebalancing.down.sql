@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS gitserver_repos_rebalancing_from_idx;
+ALTER TABLE gitserver_repos DROP COLUMN IF EXISTS rebalancing_from;
+
+COMMIT;
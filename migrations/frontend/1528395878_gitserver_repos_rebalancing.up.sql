@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE gitserver_repos ADD COLUMN IF NOT EXISTS rebalancing_from text;
+
+CREATE INDEX IF NOT EXISTS gitserver_repos_rebalancing_from_idx ON gitserver_repos(repo_id) WHERE rebalancing_from IS NOT NULL;
+
+COMMENT ON COLUMN gitserver_repos.rebalancing_from IS 'The shard that served the repository before it was moved to shard_id. Requests are routed to this shard until the move completes.';
+
+COMMIT;
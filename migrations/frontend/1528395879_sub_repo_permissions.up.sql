@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS sub_repo_permissions (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    path_includes text[],
+    path_excludes text[],
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sub_repo_permissions_repo_id_user_id_uindex ON sub_repo_permissions(repo_id, user_id);
+CREATE INDEX IF NOT EXISTS sub_repo_perms_user_id ON sub_repo_permissions(user_id);
+
+COMMENT ON TABLE sub_repo_permissions IS 'Responsible for storing permissions at a finer granularity than repo';
+COMMENT ON COLUMN sub_repo_permissions.path_includes IS 'Glob patterns of the paths of the repository which the user can read, relative to its root with a leading slash.';
+COMMENT ON COLUMN sub_repo_permissions.path_excludes IS 'Glob patterns of the paths of the repository which the user cannot read, even if they are matched by path_includes.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE lsif_configuration_policies DROP COLUMN IF EXISTS retention_count;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE lsif_configuration_policies ADD COLUMN IF NOT EXISTS retention_count integer;
+
+COMMENT ON COLUMN lsif_configuration_policies.retention_count IS 'The max number of commits retained on each matching branch by this configuration policy. Only applies when intermediate commits are retained. If null, the number is unbounded.';
+
+COMMIT;
@@ -84,12 +84,14 @@ type AuthProviderCommon struct {
 	DisplayName string `json:"displayName,omitempty"`
 }
 type AuthProviders struct {
-	Builtin       *BuiltinAuthProvider
-	Saml          *SAMLAuthProvider
-	Openidconnect *OpenIDConnectAuthProvider
-	HttpHeader    *HTTPHeaderAuthProvider
-	Github        *GitHubAuthProvider
-	Gitlab        *GitLabAuthProvider
+	Builtin        *BuiltinAuthProvider
+	Saml           *SAMLAuthProvider
+	Openidconnect  *OpenIDConnectAuthProvider
+	HttpHeader     *HTTPHeaderAuthProvider
+	Github         *GitHubAuthProvider
+	Gitlab         *GitLabAuthProvider
+	Bitbucketcloud *BitbucketCloudAuthProvider
+	Ldap           *LDAPAuthProvider
 }
 
 func (v AuthProviders) MarshalJSON() ([]byte, error) {
@@ -111,6 +113,12 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.Gitlab != nil {
 		return json.Marshal(v.Gitlab)
 	}
+	if v.Bitbucketcloud != nil {
+		return json.Marshal(v.Bitbucketcloud)
+	}
+	if v.Ldap != nil {
+		return json.Marshal(v.Ldap)
+	}
 	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
 }
 func (v *AuthProviders) UnmarshalJSON(data []byte) error {
@@ -121,6 +129,8 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	switch d.DiscriminantProperty {
+	case "bitbucketcloud":
+		return json.Unmarshal(data, &v.Bitbucketcloud)
 	case "builtin":
 		return json.Unmarshal(data, &v.Builtin)
 	case "github":
@@ -129,12 +139,14 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Gitlab)
 	case "http-header":
 		return json.Unmarshal(data, &v.HttpHeader)
+	case "ldap":
+		return json.Unmarshal(data, &v.Ldap)
 	case "openidconnect":
 		return json.Unmarshal(data, &v.Openidconnect)
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "bitbucketcloud", "ldap"})
 }
 
 type BackendInsight struct {
@@ -185,22 +197,46 @@ type BatchSpec struct {
 	Workspaces []*WorkspaceConfiguration `json:"workspaces,omitempty"`
 }
 
+// BitbucketCloudAuthProvider description: Configures the Bitbucket Cloud OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create an OAuth consumer in a Bitbucket Cloud workspace: https://support.atlassian.com/bitbucket-cloud/docs/use-oauth-on-bitbucket-cloud/. The consumer should have the `account` and `email` permissions and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/bitbucketcloud/callback".
+type BitbucketCloudAuthProvider struct {
+	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org.
+	ApiURL string `json:"apiURL,omitempty"`
+	// ClientKey description: The Key of the Bitbucket Cloud OAuth consumer, accessible from the OAuth consumers in the settings of the workspace.
+	ClientKey string `json:"clientKey"`
+	// ClientSecret description: The Secret of the Bitbucket Cloud OAuth consumer, accessible from the OAuth consumers in the settings of the workspace.
+	ClientSecret string `json:"clientSecret"`
+	DisplayName  string `json:"displayName,omitempty"`
+	Type         string `json:"type"`
+	// Url description: URL of Bitbucket Cloud, such as https://bitbucket.org.
+	Url string `json:"url,omitempty"`
+}
+
+// BitbucketCloudAuthorization description: If non-null, enforces Bitbucket Cloud repository permissions. This requires that there is an item in the `auth.providers` field of type "bitbucketcloud" with the same `url` field as specified in this `BitbucketCloudConnection`, and that the user of the "appPassword" is an admin of the workspaces of the mirrored repositories.
+type BitbucketCloudAuthorization struct {
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
 	AppPassword string `json:"appPassword"`
+	// Authorization description: If non-null, enforces Bitbucket Cloud repository permissions. This requires that there is an item in the `auth.providers` field of type "bitbucketcloud" with the same `url` field as specified in this `BitbucketCloudConnection`, and that the user of the "appPassword" is an admin of the workspaces of the mirrored repositories.
+	Authorization *BitbucketCloudAuthorization `json:"authorization,omitempty"`
 	// Exclude description: A list of repositories to never mirror from Bitbucket Cloud. Takes precedence over "teams" configuration.
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
 	Exclude []*ExcludedBitbucketCloudRepo `json:"exclude,omitempty"`
+	// GitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+	GitLFS *BitbucketCloudGitLFS `json:"gitLFS,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Cloud.
 	//
 	// If "http", Sourcegraph will access Bitbucket Cloud repositories using Git URLs of the form https://bitbucket.org/myteam/myproject.git.
 	//
 	// If "ssh", Sourcegraph will access Bitbucket Cloud repositories using Git URLs of the form git@bitbucket.org:myteam/myproject.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
 	GitURLType string `json:"gitURLType,omitempty"`
+	// PartialClones description: Clone the matching repositories as partial clones, which only contain the content of files up to a size limit. The content of larger files is fetched from the code host when it is read. Use this for repositories which are too large to be cloned in full, such as repositories with vendored binaries. Ignored while indexed search is enabled, and not supported with custom git fetch commands.
+	PartialClones []*BitbucketCloudPartialClone `json:"partialClones,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
 	RateLimit *BitbucketCloudRateLimit `json:"rateLimit,omitempty"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Bitbucket Cloud repository.
@@ -217,6 +253,29 @@ type BitbucketCloudConnection struct {
 	Url string `json:"url"`
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
+	// WebhookSecret description: A secret used to authenticate incoming webhook payloads. Bitbucket Cloud does not sign webhook payloads, so the secret must be passed in the "secret" query parameter of the webhook URL configured in Bitbucket Cloud.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+}
+
+// BitbucketCloudGitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+type BitbucketCloudGitLFS struct {
+	// Enabled description: Whether to fetch Git LFS objects.
+	Enabled bool `json:"enabled"`
+	// Exclude description: Glob patterns of the paths whose Git LFS objects are not fetched, even if they match "include".
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Glob patterns of the paths whose Git LFS objects are fetched. If empty, the objects of all paths are fetched.
+	Include []string `json:"include,omitempty"`
+	// MaxFileSize description: The size in bytes of the largest Git LFS object to fetch. Files with larger objects are shown as pointer files.
+	MaxFileSize int `json:"maxFileSize,omitempty"`
+}
+
+type BitbucketCloudPartialClone struct {
+	// BlobSizeLimit description: The size in bytes of the largest files whose content is cloned with the repository. If 0, the content of all files is fetched when it is read.
+	BlobSizeLimit *int `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths of files and directories whose content is never fetched. They are left out of searches, and reading them fails.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// RepositoryPattern description: Regular expression which matches against the names of repositories on Sourcegraph. The first partial clone configuration matching a repository applies.
+	RepositoryPattern string `json:"repositoryPattern"`
 }
 
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
@@ -247,6 +306,8 @@ type BitbucketServerConnection struct {
 	Exclude []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	// ExcludePersonalRepositories description: Whether or not personal repositories should be excluded or not. When true, Sourcegraph will ignore personal repositories it may have access to. See https://docs.sourcegraph.com/integration/bitbucket_server#excluding-personal-repositories for more information.
 	ExcludePersonalRepositories bool `json:"excludePersonalRepositories,omitempty"`
+	// GitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+	GitLFS *BitbucketServerGitLFS `json:"gitLFS,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Server instance.
 	//
 	// If "http", Sourcegraph will access Bitbucket Server repositories using Git URLs of the form http(s)://bitbucket.example.com/scm/myproject/myrepo.git (using https: if the Bitbucket Server instance uses HTTPS).
@@ -255,6 +316,8 @@ type BitbucketServerConnection struct {
 	GitURLType string `json:"gitURLType,omitempty"`
 	// InitialRepositoryEnablement description: Deprecated and ignored field which will be removed entirely in the next release. BitBucket repositories can no longer be enabled or disabled explicitly.
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
+	// PartialClones description: Clone the matching repositories as partial clones, which only contain the content of files up to a size limit. The content of larger files is fetched from the code host when it is read. Use this for repositories which are too large to be cloned in full, such as repositories with vendored binaries. Ignored while indexed search is enabled, and not supported with custom git fetch commands.
+	PartialClones []*BitbucketServerPartialClone `json:"partialClones,omitempty"`
 	// Password description: The password to use when authenticating to the Bitbucket Server instance. Also set the corresponding "username" field.
 	//
 	// For Bitbucket Server instances that support personal access tokens (Bitbucket Server version 5.5 and newer), it is recommended to provide a token instead (in the "token" field).
@@ -291,6 +354,18 @@ type BitbucketServerConnection struct {
 	Webhooks *Webhooks `json:"webhooks,omitempty"`
 }
 
+// BitbucketServerGitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+type BitbucketServerGitLFS struct {
+	// Enabled description: Whether to fetch Git LFS objects.
+	Enabled bool `json:"enabled"`
+	// Exclude description: Glob patterns of the paths whose Git LFS objects are not fetched, even if they match "include".
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Glob patterns of the paths whose Git LFS objects are fetched. If empty, the objects of all paths are fetched.
+	Include []string `json:"include,omitempty"`
+	// MaxFileSize description: The size in bytes of the largest Git LFS object to fetch. Files with larger objects are shown as pointer files.
+	MaxFileSize int `json:"maxFileSize,omitempty"`
+}
+
 // BitbucketServerIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical in Sourcegraph and Bitbucket Server accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
 type BitbucketServerIdentityProvider struct {
 	Username *BitbucketServerUsernameIdentity
@@ -324,6 +399,15 @@ type BitbucketServerOAuth struct {
 	SigningKey string `json:"signingKey"`
 }
 
+type BitbucketServerPartialClone struct {
+	// BlobSizeLimit description: The size in bytes of the largest files whose content is cloned with the repository. If 0, the content of all files is fetched when it is read.
+	BlobSizeLimit *int `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths of files and directories whose content is never fetched. They are left out of searches, and reading them fails.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// RepositoryPattern description: Regular expression which matches against the names of repositories on Sourcegraph. The first partial clone configuration matching a repository applies.
+	RepositoryPattern string `json:"repositoryPattern"`
+}
+
 // BitbucketServerPlugin description: Configuration for Bitbucket Server Sourcegraph plugin
 type BitbucketServerPlugin struct {
 	// Permissions description: Enables fetching Bitbucket Server permissions through the roaring bitmap endpoint. Warning: there may be performance degradation under significant load.
@@ -550,6 +634,8 @@ type ExperimentalFeatures struct {
 	EnablePostSignupFlow bool `json:"enablePostSignupFlow,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GitServerReplicas description: Serves read requests for hot repositories from several gitservers. A repository is hot if it is listed in `repos`, or if its gitserver observes more than `requestsPerMinute` read requests per minute for it.
+	GitServerReplicas *GitServerReplicas `json:"gitServerReplicas,omitempty"`
 	// JvmPackages description: Allow adding JVM packages code host connections
 	JvmPackages string `json:"jvmPackages,omitempty"`
 	// Perforce description: Allow adding Perforce code host connections
@@ -564,6 +650,8 @@ type ExperimentalFeatures struct {
 	SearchMultipleRevisionsPerRepository *bool `json:"searchMultipleRevisionsPerRepository,omitempty"`
 	// StructuralSearch description: Enables structural search.
 	StructuralSearch string `json:"structuralSearch,omitempty"`
+	// SubRepoPermissions description: Enforces permissions on paths within repositories, as synced from code hosts which support them (Perforce) or set through the GraphQL API.
+	SubRepoPermissions *SubRepoPermissions `json:"subRepoPermissions,omitempty"`
 	// TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 	TlsExternal *TlsExternal `json:"tls.external,omitempty"`
 	// VersionContexts description: JSON array of version context configuration
@@ -651,6 +739,8 @@ type GitHubConnection struct {
 	//
 	// Note: ID is the GitHub GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Exclude []*ExcludedGitHubRepo `json:"exclude,omitempty"`
+	// GitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+	GitLFS *GitHubGitLFS `json:"gitLFS,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitHub instance.
 	//
 	// If "http", Sourcegraph will access GitHub repositories using Git URLs of the form http(s)://github.com/myteam/myproject.git (using https: if the GitHub instance uses HTTPS).
@@ -661,6 +751,8 @@ type GitHubConnection struct {
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// Orgs description: An array of organization names identifying GitHub organizations whose repositories should be mirrored on Sourcegraph.
 	Orgs []string `json:"orgs,omitempty"`
+	// PartialClones description: Clone the matching repositories as partial clones, which only contain the content of files up to a size limit. The content of larger files is fetched from the code host when it is read. Use this for repositories which are too large to be cloned in full, such as repositories with vendored binaries. Ignored while indexed search is enabled, and not supported with custom git fetch commands.
+	PartialClones []*GitHubPartialClone `json:"partialClones,omitempty"`
 	// RateLimit description: Rate limit applied when making background API requests to GitHub.
 	RateLimit *GitHubRateLimit `json:"rateLimit,omitempty"`
 	// Repos description: An array of repository "owner/name" strings specifying which GitHub or GitHub Enterprise repositories to mirror on Sourcegraph.
@@ -696,6 +788,27 @@ type GitHubConnection struct {
 	Webhooks []*GitHubWebhook `json:"webhooks,omitempty"`
 }
 
+// GitHubGitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+type GitHubGitLFS struct {
+	// Enabled description: Whether to fetch Git LFS objects.
+	Enabled bool `json:"enabled"`
+	// Exclude description: Glob patterns of the paths whose Git LFS objects are not fetched, even if they match "include".
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Glob patterns of the paths whose Git LFS objects are fetched. If empty, the objects of all paths are fetched.
+	Include []string `json:"include,omitempty"`
+	// MaxFileSize description: The size in bytes of the largest Git LFS object to fetch. Files with larger objects are shown as pointer files.
+	MaxFileSize int `json:"maxFileSize,omitempty"`
+}
+
+type GitHubPartialClone struct {
+	// BlobSizeLimit description: The size in bytes of the largest files whose content is cloned with the repository. If 0, the content of all files is fetched when it is read.
+	BlobSizeLimit *int `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths of files and directories whose content is never fetched. They are left out of searches, and reading them fails.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// RepositoryPattern description: Regular expression which matches against the names of repositories on Sourcegraph. The first partial clone configuration matching a repository applies.
+	RepositoryPattern string `json:"repositoryPattern"`
+}
+
 // GitHubRateLimit description: Rate limit applied when making background API requests to GitHub.
 type GitHubRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
@@ -740,6 +853,8 @@ type GitLabConnection struct {
 	CloudGlobal bool `json:"cloudGlobal,omitempty"`
 	// Exclude description: A list of projects to never mirror from this GitLab instance. Takes precedence over "projects" and "projectQuery" configuration. Supports excluding by name ({"name": "group/name"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedGitLabProject `json:"exclude,omitempty"`
+	// GitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+	GitLFS *GitLabGitLFS `json:"gitLFS,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitLab instance.
 	//
 	// If "http", Sourcegraph will access GitLab repositories using Git URLs of the form http(s)://gitlab.example.com/myteam/myproject.git (using https: if the GitLab instance uses HTTPS).
@@ -750,6 +865,8 @@ type GitLabConnection struct {
 	InitialRepositoryEnablement bool `json:"initialRepositoryEnablement,omitempty"`
 	// NameTransformations description: An array of transformations will apply to the repository name. Currently, only regex replacement is supported. All transformations happen after "repositoryPathPattern" is processed.
 	NameTransformations []*GitLabNameTransformation `json:"nameTransformations,omitempty"`
+	// PartialClones description: Clone the matching repositories as partial clones, which only contain the content of files up to a size limit. The content of larger files is fetched from the code host when it is read. Use this for repositories which are too large to be cloned in full, such as repositories with vendored binaries. Ignored while indexed search is enabled, and not supported with custom git fetch commands.
+	PartialClones []*GitLabPartialClone `json:"partialClones,omitempty"`
 	// ProjectQuery description: An array of strings specifying which GitLab projects to mirror on Sourcegraph. Each string is a URL path and query that targets a GitLab API endpoint returning a list of projects. If the string only contains a query, then "projects" is used as the path. Examples: "?membership=true&search=foo", "groups/mygroup/projects".
 	//
 	// The special string "none" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once. Here are a few endpoints that return a list of projects: https://docs.gitlab.com/ee/api/projects.html#list-all-projects, https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects, https://docs.gitlab.com/ee/api/search.html#scope-projects.
@@ -773,12 +890,33 @@ type GitLabConnection struct {
 	// Webhooks description: An array of webhook configurations
 	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
 }
+
+// GitLabGitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+type GitLabGitLFS struct {
+	// Enabled description: Whether to fetch Git LFS objects.
+	Enabled bool `json:"enabled"`
+	// Exclude description: Glob patterns of the paths whose Git LFS objects are not fetched, even if they match "include".
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Glob patterns of the paths whose Git LFS objects are fetched. If empty, the objects of all paths are fetched.
+	Include []string `json:"include,omitempty"`
+	// MaxFileSize description: The size in bytes of the largest Git LFS object to fetch. Files with larger objects are shown as pointer files.
+	MaxFileSize int `json:"maxFileSize,omitempty"`
+}
+
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
 	Regex string `json:"regex,omitempty"`
 	// Replacement description: The replacement used to replace all matched occurrences by the regex.
 	Replacement string `json:"replacement,omitempty"`
 }
+type GitLabPartialClone struct {
+	// BlobSizeLimit description: The size in bytes of the largest files whose content is cloned with the repository. If 0, the content of all files is fetched when it is read.
+	BlobSizeLimit *int `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths of files and directories whose content is never fetched. They are left out of searches, and reading them fails.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// RepositoryPattern description: Regular expression which matches against the names of repositories on Sourcegraph. The first partial clone configuration matching a repository applies.
+	RepositoryPattern string `json:"repositoryPattern"`
+}
 type GitLabProject struct {
 	// Id description: The ID of a GitLab project (as returned by the GitLab instance's API) to mirror.
 	Id int `json:"id,omitempty"`
@@ -798,8 +936,26 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitServerReplicas description: Serves read requests for hot repositories from several gitservers. A repository is hot if it is listed in `repos`, or if its gitserver observes more than `requestsPerMinute` read requests per minute for it.
+type GitServerReplicas struct {
+	// Replicas description: The number of gitservers serving each hot repository, including the gitserver which owns it.
+	Replicas int `json:"replicas,omitempty"`
+	// Repos description: Names of repositories which are always hot.
+	Repos []string `json:"repos,omitempty"`
+	// RequestsPerMinute description: The number of read requests per minute for a repository above which it is considered hot. Set to 0 to only replicate the repositories listed in `repos`.
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+}
+
+// GitoliteAuthorization description: If non-null, enforces Gitolite repository permissions. The access rules are read from the "gitolite-admin" repository, which must be mirrored from this Gitolite instance. Gitolite users are matched to Sourcegraph users with the mapping in "users".
+type GitoliteAuthorization struct {
+	// Users description: Maps Gitolite usernames to the verified email addresses of the Sourcegraph users they belong to. Gitolite users who are not listed are not given access to any repository on Sourcegraph.
+	Users map[string]string `json:"users"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
+	// Authorization description: If non-null, enforces Gitolite repository permissions. The access rules are read from the "gitolite-admin" repository, which must be mirrored from this Gitolite instance. Gitolite users are matched to Sourcegraph users by username.
+	Authorization *GitoliteAuthorization `json:"authorization,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
 	Exclude []*ExcludedGitoliteRepo `json:"exclude,omitempty"`
 	// Host description: Gitolite host that stores the repositories (e.g., git@gitolite.example.com, ssh://git@gitolite.example.com:2222/).
@@ -909,6 +1065,58 @@ type JVMPackagesConnection struct {
 	Maven *Maven `json:"maven,omitempty"`
 }
 
+// LDAPAttributes description: The attributes of user entries that Sourcegraph users are created from.
+type LDAPAttributes struct {
+	// DisplayName description: The attribute with the display name of the user.
+	DisplayName string `json:"displayName,omitempty"`
+	// Email description: The attribute with the email address of the user.
+	Email string `json:"email,omitempty"`
+	// Username description: The attribute with the username that users sign in with. It is normalized to become the username of the Sourcegraph user. Active Directory usernames are in the `sAMAccountName` attribute.
+	Username string `json:"username,omitempty"`
+}
+
+// LDAPAuthProvider description: Configures the LDAP authentication provider, which authenticates users with their username and password against an LDAP directory such as OpenLDAP or Active Directory. Users are looked up with the service account of `bindDN`, then authenticated by binding as the user's entry.
+type LDAPAuthProvider struct {
+	// AllowSignup description: Allows users of the directory who don't have a Sourcegraph account to sign up by signing in. If false, users must have an existing Sourcegraph account with a verified email that is the same as their email in the directory. Existing accounts are never linked by username.
+	AllowSignup *bool `json:"allowSignup,omitempty"`
+	// Attributes description: The attributes of user entries that Sourcegraph users are created from.
+	Attributes *LDAPAttributes `json:"attributes,omitempty"`
+	// BindDN description: The DN of the service account that searches for users and groups.
+	BindDN string `json:"bindDN"`
+	// BindPassword description: The password of the service account that searches for users and groups.
+	BindPassword string `json:"bindPassword"`
+	// Certificate description: TLS certificate of the LDAP server. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:636 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
+	Certificate string `json:"certificate,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	// GroupSync description: Syncs the members of LDAP groups to Sourcegraph organizations. Users who signed in with LDAP are added to the organizations of the groups they are members of, and removed from the organizations of the groups they aren't members of. Members of the organizations who didn't sign in with LDAP are left alone.
+	GroupSync *LDAPGroupSync `json:"groupSync,omitempty"`
+	// StartTLS description: Upgrade connections to TLS with the StartTLS operation before authenticating. Only applies to ldap URLs.
+	StartTLS bool   `json:"startTLS,omitempty"`
+	Type     string `json:"type"`
+	// Url description: URL of the LDAP server. Use the ldaps scheme to connect with TLS.
+	Url string `json:"url"`
+	// UserBaseDN description: The DN of the entry under which users are searched for.
+	UserBaseDN string `json:"userBaseDN"`
+	// UserFilter description: The filter that users must match to sign in, in addition to having the username they sign in with. Active Directory users are matched with `(objectClass=user)`.
+	UserFilter string `json:"userFilter,omitempty"`
+}
+type LDAPGroupOrg struct {
+	// GroupDN description: The DN of the group.
+	GroupDN string `json:"groupDN"`
+	// Org description: The name of the Sourcegraph organization.
+	Org string `json:"org"`
+}
+
+// LDAPGroupSync description: Syncs the members of LDAP groups to Sourcegraph organizations. Users who signed in with LDAP are added to the organizations of the groups they are members of, and removed from the organizations of the groups they aren't members of. Members of the organizations who didn't sign in with LDAP are left alone.
+type LDAPGroupSync struct {
+	// Interval description: The number of minutes between syncs. Members of groups are also synced when they sign in.
+	Interval int `json:"interval,omitempty"`
+	// MemberAttribute description: The attribute of group entries whose values are the DNs of their members.
+	MemberAttribute string `json:"memberAttribute,omitempty"`
+	// Orgs description: The groups whose members are synced, and the organizations they are synced to. The organizations must already exist.
+	Orgs []*LDAPGroupOrg `json:"orgs"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	// Sentry description: Configuration for Sentry
@@ -935,6 +1143,24 @@ type MavenRateLimit struct {
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
 
+// MercurialConnection description: Configuration for a connection to Mercurial repositories served over HTTP(S), such as by hgweb. The repositories are converted to Git repositories when they are cloned and updated.
+type MercurialConnection struct {
+	// Password description: The password to authenticate to the Mercurial server with.
+	Password string `json:"password,omitempty"`
+	// Repos description: The paths of the repositories relative to "url". If empty, all repositories listed by the hgweb index at "url" are added.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the host and path of "url", and "{repo}" is replaced with the path of the repository relative to "url".
+	//
+	// For example, if "url" is https://hg.example.com/repos and a repository is at https://hg.example.com/repos/my/repo, then a repositoryPathPattern of "{base}/{repo}" would mean that the repository is available on Sourcegraph at https://sourcegraph.example.com/hg.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: The base URL of the Mercurial repositories. If "repos" is empty, the repositories are listed from the hgweb index at this URL.
+	Url string `json:"url"`
+	// Username description: The username to authenticate to the Mercurial server with.
+	Username string `json:"username,omitempty"`
+}
+
 // MountedEncryptionKey description: This encryption key is mounted from a given file path or an environment variable.
 type MountedEncryptionKey struct {
 	EnvVarName string `json:"envVarName,omitempty"`
@@ -1116,7 +1342,11 @@ type OpenIDConnectAuthProvider struct {
 
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	// GitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+	GitLFS *OtherExternalServiceGitLFS `json:"gitLFS,omitempty"`
+	// PartialClones description: Clone the matching repositories as partial clones, which only contain the content of files up to a size limit. The content of larger files is fetched from the code host when it is read. Use this for repositories which are too large to be cloned in full, such as repositories with vendored binaries. Ignored while indexed search is enabled, and not supported with custom git fetch commands.
+	PartialClones []*OtherExternalServicePartialClone `json:"partialClones,omitempty"`
+	Repos         []string                            `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -1125,6 +1355,27 @@ type OtherExternalServiceConnection struct {
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	Url                   string `json:"url,omitempty"`
 }
+
+// OtherExternalServiceGitLFS description: Fetch the Git LFS objects of the default branch of repositories, so that files stored in Git LFS are shown and searched with their content rather than as pointer files. Indexed search still indexes the pointer files. Only supported for HTTP(S) Git URLs.
+type OtherExternalServiceGitLFS struct {
+	// Enabled description: Whether to fetch Git LFS objects.
+	Enabled bool `json:"enabled"`
+	// Exclude description: Glob patterns of the paths whose Git LFS objects are not fetched, even if they match "include".
+	Exclude []string `json:"exclude,omitempty"`
+	// Include description: Glob patterns of the paths whose Git LFS objects are fetched. If empty, the objects of all paths are fetched.
+	Include []string `json:"include,omitempty"`
+	// MaxFileSize description: The size in bytes of the largest Git LFS object to fetch. Files with larger objects are shown as pointer files.
+	MaxFileSize int `json:"maxFileSize,omitempty"`
+}
+
+type OtherExternalServicePartialClone struct {
+	// BlobSizeLimit description: The size in bytes of the largest files whose content is cloned with the repository. If 0, the content of all files is fetched when it is read.
+	BlobSizeLimit *int `json:"blobSizeLimit,omitempty"`
+	// ExcludePaths description: Paths of files and directories whose content is never fetched. They are left out of searches, and reading them fails.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// RepositoryPattern description: Regular expression which matches against the names of repositories on Sourcegraph. The first partial clone configuration matching a repository applies.
+	RepositoryPattern string `json:"repositoryPattern"`
+}
 type Overrides struct {
 	// Key description: The key that we want to override for example a username
 	Key string `json:"key,omitempty"`
@@ -1610,6 +1861,32 @@ type Step struct {
 	Run string `json:"run"`
 }
 
+// SubRepoPermissions description: Enforces permissions on paths within repositories, as synced from code hosts which support them (Perforce) or set through the GraphQL API.
+type SubRepoPermissions struct {
+	// Enabled description: Whether permissions on paths within repositories are synced and enforced.
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// SubversionConnection description: Configuration for a connection to Subversion repositories served over HTTP(S). The repositories are converted to Git repositories when they are cloned and updated.
+type SubversionConnection struct {
+	// Layout description: The layout of the repositories. With the "standard" layout, "trunk" is converted to the Git branch "master", the directories in "branches" to Git branches and the directories in "tags" to Git tags. With the "none" layout, the root of the repository is converted to the Git branch "master".
+	Layout string `json:"layout,omitempty"`
+	// Password description: The password to authenticate to the Subversion server with.
+	Password string `json:"password,omitempty"`
+	// Repos description: The paths of the repositories relative to "url". If empty, all repositories in the parent path listing at "url" are added.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the host and path of "url", and "{repo}" is replaced with the path of the repository relative to "url".
+	//
+	// For example, if "url" is https://svn.example.com/repos and a repository is at https://svn.example.com/repos/my/repo, then a repositoryPathPattern of "{base}/{repo}" would mean that the repository is available on Sourcegraph at https://sourcegraph.example.com/svn.example.com/repos/my/repo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: The base URL of the Subversion repositories. If "repos" is empty, the repositories are listed from the parent path listing at this URL (SVNListParentPath).
+	Url string `json:"url"`
+	// Username description: The username to authenticate to the Subversion server with.
+	Username string `json:"username,omitempty"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
@@ -38,6 +38,10 @@ var GitoliteSchemaJSON string
 //go:embed jvm-packages.schema.json
 var JVMPackagesSchemaJSON string
 
+// MercurialSchemaJSON is the content of the file "mercurial.schema.json".
+//go:embed mercurial.schema.json
+var MercurialSchemaJSON string
+
 // OtherExternalServiceSchemaJSON is the content of the file "other_external_service.schema.json".
 //go:embed other_external_service.schema.json
 var OtherExternalServiceSchemaJSON string
@@ -57,3 +61,7 @@ var SettingsSchemaJSON string
 // SiteSchemaJSON is the content of the file "site.schema.json".
 //go:embed site.schema.json
 var SiteSchemaJSON string
+
+// SubversionSchemaJSON is the content of the file "subversion.schema.json".
+//go:embed subversion.schema.json
+var SubversionSchemaJSON string